@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/bugreport"
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/crash"
+	"github.com/ll931217/claude-hud-enhanced/internal/render"
+	"github.com/ll931217/claude-hud-enhanced/internal/terminal"
+	"github.com/ll931217/claude-hud-enhanced/internal/version"
+)
+
+// debugLogTail is how much of the --debug log a bug report includes
+const debugLogTail = 64 * 1024
+
+// terminalEnv are the environment variables that decide how the HUD renders
+var terminalEnv = []string{"TERM", "COLORTERM", "TERM_PROGRAM", "TERM_PROGRAM_VERSION", "NO_COLOR", "LANG", "LC_ALL", "COLUMNS", "TMUX"}
+
+// runBugreportCommand bundles version, terminal, config, crash and log
+// details into a tarball to attach to an issue
+func runBugreportCommand(args []string) int {
+	fs := flag.NewFlagSet("bugreport", flag.ContinueOnError)
+	now := time.Now()
+	name := "claude-hud-bugreport-" + now.Format("20060102-150405")
+	output := fs.String("output", name+".tar.gz", "Write the bundle to this file")
+	sample := fs.Int("transcript-sample", 0, "Include the last N events of the transcript, anonymized (0 leaves it out)")
+	transcriptPath := fs.String("transcript", "", "Transcript to sample (default: the latest session in the current directory)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	files := []bugreport.File{
+		{Name: "version.txt", Data: versionReport()},
+		{Name: "terminal.txt", Data: terminalReport()},
+	}
+	add := func(name string, data []byte, err error) {
+		if err != nil {
+			if !os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "bugreport: skipping %s: %v\n", name, err)
+			}
+			return
+		}
+		files = append(files, bugreport.File{Name: name, Data: data})
+	}
+
+	if path, err := config.ConfigPath(); err == nil {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			data, err = bugreport.RedactConfig(data)
+		}
+		add("config.yaml", data, err)
+	}
+	if store, err := crash.DefaultStore(); err == nil {
+		data, err := os.ReadFile(store.Path())
+		add(crash.FileName, data, err)
+	}
+	data, err := bugreport.TailFile(debugLogPath, debugLogTail)
+	add("debug.log", data, err)
+
+	if *sample > 0 {
+		sess, err := resolveSession(sessionArgs{Transcript: *transcriptPath, Latest: true})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "bugreport: no transcript found for the current directory; pass --transcript")
+			return 1
+		}
+		file, err := os.Open(sess.Transcript)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "bugreport: %v\n", err)
+			return 1
+		}
+		data, err := bugreport.AnonymizeTranscript(file, *sample)
+		file.Close()
+		add("transcript-sample.jsonl", data, err)
+	}
+
+	out, err := os.Create(*output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bugreport: %v\n", err)
+		return 1
+	}
+	err = bugreport.WriteTarball(out, name, files, now)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(*output)
+		fmt.Fprintf(os.Stderr, "bugreport: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Wrote %s:\n", *output)
+	for _, f := range files {
+		fmt.Printf("  %s\n", f.Name)
+	}
+	fmt.Println("Secrets in the config are redacted; review the bundle before attaching it to an issue.")
+	return 0
+}
+
+// versionReport describes the build and platform
+func versionReport() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, version.FullVersionInfo())
+	info := version.BuildInfo()
+	for _, key := range render.Keys(info) {
+		fmt.Fprintf(&buf, "%s: %s\n", key, info[key])
+	}
+	fmt.Fprintf(&buf, "runtime: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	return buf.Bytes()
+}
+
+// terminalReport describes the terminal the command runs in
+func terminalReport() []byte {
+	var buf bytes.Buffer
+	for _, name := range terminalEnv {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			value = "(unset)"
+		}
+		fmt.Fprintf(&buf, "%s=%s\n", name, value)
+	}
+	size := terminal.GetSize()
+	fmt.Fprintf(&buf, "size: %dx%d\n", size.Columns, size.Rows)
+	stdout, _ := os.Stdout.Stat()
+	fmt.Fprintf(&buf, "stdout is a terminal: %t\n", stdout != nil && stdout.Mode()&os.ModeCharDevice != 0)
+	return buf.Bytes()
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/humanize"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+// runCleanCommand lists transcripts by size and age and, with --delete or
+// --archive, removes or gzips those older than --older-than days
+func runCleanCommand(args []string) int {
+	fs := flag.NewFlagSet("clean", flag.ContinueOnError)
+	olderThan := fs.Int("older-than", 30, "Only act on transcripts not modified for this many days")
+	deleteOld := fs.Bool("delete", false, "Delete old transcripts")
+	archiveOld := fs.Bool("archive", false, "Gzip old transcripts to .jsonl.gz and remove the originals")
+	dryRun := fs.Bool("dry-run", false, "Show what --delete or --archive would do without changing anything")
+	limit := fs.Int("limit", 20, "Number of files to list (0 lists all)")
+	dir := fs.String("dir", "", "Projects directory (default: ~/.claude/projects)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *deleteOld && *archiveOld {
+		fmt.Fprintln(os.Stderr, "clean: --delete and --archive are mutually exclusive")
+		return 2
+	}
+	if *olderThan < 1 && (*deleteOld || *archiveOld) {
+		// The active session's transcript is always recent; never touch it
+		fmt.Fprintln(os.Stderr, "clean: --older-than must be at least 1 day")
+		return 2
+	}
+
+	if *dir == "" {
+		projectsDir, err := transcript.ProjectsDir()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "clean: %v\n", err)
+			return 1
+		}
+		*dir = projectsDir
+	}
+	files, err := transcript.ListFiles(*dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clean: %v\n", err)
+		return 1
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -*olderThan)
+	var total, oldTotal int64
+	var old []transcript.File
+	for _, f := range files {
+		total += f.Size
+		if f.ModTime.Before(cutoff) {
+			oldTotal += f.Size
+			old = append(old, f)
+		}
+	}
+
+	printTranscriptFiles(*dir, files, *limit)
+	fmt.Printf("\n%d transcripts, %s total; %d older than %d days (%s)\n",
+		len(files), formatSize(total), len(old), *olderThan, formatSize(oldTotal))
+
+	if !*deleteOld && !*archiveOld {
+		return 0
+	}
+
+	failed := false
+	var freed int64
+	for _, f := range old {
+		switch {
+		case *archiveOld && f.Archived:
+			continue
+		case *dryRun && *deleteOld:
+			fmt.Printf("would delete  %s\n", f.Path)
+		case *dryRun:
+			fmt.Printf("would archive %s\n", f.Path)
+		case *deleteOld:
+			if err := os.Remove(f.Path); err != nil {
+				fmt.Fprintf(os.Stderr, "clean: %v\n", err)
+				failed = true
+				continue
+			}
+			freed += f.Size
+			fmt.Printf("deleted  %s\n", f.Path)
+		default:
+			archived, err := transcript.Archive(f.Path)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "clean: %v\n", err)
+				failed = true
+				continue
+			}
+			if info, err := os.Stat(archived); err == nil {
+				freed += f.Size - info.Size()
+			}
+			fmt.Printf("archived %s\n", archived)
+		}
+	}
+	if !*dryRun {
+		fmt.Printf("freed %s\n", formatSize(freed))
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// printTranscriptFiles prints the largest transcripts with their size and age
+func printTranscriptFiles(dir string, files []transcript.File, limit int) {
+	if limit > 0 && len(files) > limit {
+		files = files[:limit]
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SIZE\tAGE\tTRANSCRIPT")
+	for _, f := range files {
+		rel, err := filepath.Rel(dir, f.Path)
+		if err != nil {
+			rel = f.Path
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", formatSize(f.Size), humanize.Duration(time.Since(f.ModTime), humanize.Format{Precision: 1, Smallest: time.Hour}), rel)
+	}
+	w.Flush()
+}
+
+// formatSize formats a byte count as "12.3MB"
+func formatSize(bytes int64) string {
+	switch {
+	case bytes >= 1<<30:
+		return fmt.Sprintf("%.1fGB", float64(bytes)/(1<<30))
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%.1fMB", float64(bytes)/(1<<20))
+	case bytes >= 1<<10:
+		return fmt.Sprintf("%.0fKB", float64(bytes)/(1<<10))
+	default:
+		return fmt.Sprintf("%dB", bytes)
+	}
+}
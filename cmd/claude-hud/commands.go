@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/render"
+)
+
+// command is a claude-hud subcommand; it receives the arguments after its name
+// and returns the process exit code
+type command struct {
+	usage string
+	run   func(args []string) int
+}
+
+// commands maps subcommand names to their implementations
+var commands = map[string]command{
+	"bugreport": {usage: "Bundle logs, redacted config and crash counts into a tarball for an issue", run: runBugreportCommand},
+	"clean":     {usage: "List transcripts by size and age; delete or archive old ones", run: runCleanCommand},
+	"config":    {usage: "Manage the config file (config migrate)", run: runConfigCommand},
+	"daemon":    {usage: "Run the background daemon (or: daemon install|status|stop|uninstall)", run: runDaemonCommand},
+	"digest":    {usage: "Summarize a session: duration, cost, tokens, files and todos (--log: daily worklog)", run: runDigestCommand},
+	"doctor":    {usage: "Check config, daemon and transcripts (--sections: per-section health)", run: runDoctorCommand},
+	"export":    {usage: "Export session events, tool and token usage as CSV or JSON", run: runExportCommand},
+	"files":     {usage: "List the files changed in a session, with edit counts", run: runFilesCommand},
+	"fleet":     {usage: "Show the repository's worktrees side by side: branch, changes, session and context", run: runFleetCommand},
+	"grep":      {usage: "Search message text and tool inputs across the project's transcripts", run: runGrepCommand},
+	"hooks":     {usage: "Report how long user hooks add to tool calls (needs claude --debug)", run: runHooksCommand},
+	"mcp":       {usage: "Health-check MCP servers and list their tools (mcp probe|tools)", run: runMCPCommand},
+	"name":      {usage: "Name the current session, shown in the HUD and sessions list", run: runNameCommand},
+	"preview":   {usage: "Render the statusline with sample data at a given width and theme", run: runPreviewCommand},
+	"report":    {usage: "Write a Markdown or HTML session report with token and tool charts", run: runReportCommand},
+	"sections":  {usage: "List available sections and their data sources (sections list)", run: runSectionsCommand},
+	"sessions":  {usage: "List recent sessions with their names", run: runSessionsCommand},
+	"timeline":  {usage: "Print a session's prompts, tool calls, todos and compactions in order", run: runTimelineCommand},
+	"timer":     {usage: "Time-box work with a focus timer shown by the timer section", run: runTimerCommand},
+}
+
+// dispatchCommand runs a subcommand if os.Args names one
+// Returns false when the arguments should be handled as regular flags
+func dispatchCommand() (int, bool) {
+	if len(os.Args) < 2 {
+		return 0, false
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		return 0, false
+	}
+	return cmd.run(os.Args[2:]), true
+}
+
+// printCommands lists available subcommands
+func printCommands() {
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, name := range render.Keys(commands) {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", name, commands[name].usage)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+)
+
+// runConfigCommand handles `claude-hud config <action>`
+func runConfigCommand(args []string) int {
+	if len(args) == 0 || args[0] != "migrate" {
+		fmt.Fprintln(os.Stderr, "Usage: claude-hud config migrate [--dry-run] [--path FILE]")
+		return 2
+	}
+	return runConfigMigrate(args[1:])
+}
+
+// runConfigMigrate upgrades the config file to the current version and writes it back
+func runConfigMigrate(args []string) int {
+	fs := flag.NewFlagSet("config migrate", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "Show what would change without writing the file")
+	path := fs.String("path", "", "Config file to migrate (default: ~/.config/claude-hud/config.yaml)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if *path == "" {
+		p, err := config.ConfigPath()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "config migrate: %v\n", err)
+			return 1
+		}
+		*path = p
+	}
+
+	result, err := config.MigrateFile(*path, !*dryRun)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "config migrate: %v\n", err)
+		return 1
+	}
+
+	if !result.Migrated() {
+		if result.FromVersion > config.CurrentVersion {
+			fmt.Printf("%s is version %d, newer than this release (%d); not changed\n", *path, result.FromVersion, config.CurrentVersion)
+		} else {
+			fmt.Printf("%s is up to date (version %d)\n", *path, result.FromVersion)
+		}
+		return 0
+	}
+
+	for _, change := range result.Changes {
+		fmt.Printf("  %s\n", change)
+	}
+	if *dryRun {
+		fmt.Printf("Would migrate %s from version %d to %d\n", *path, result.FromVersion, result.ToVersion)
+	} else {
+		fmt.Printf("Migrated %s from version %d to %d (backup: %s.bak)\n", *path, result.FromVersion, result.ToVersion, *path)
+	}
+	return 0
+}
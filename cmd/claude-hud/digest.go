@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/digest"
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+	"github.com/ll931217/claude-hud-enhanced/internal/hook"
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+	"github.com/ll931217/claude-hud-enhanced/internal/webhook"
+)
+
+const (
+	// digestTimeout bounds reading the transcript for a digest from a hook
+	digestTimeout = 2 * time.Second
+
+	// digestPostTimeout bounds posting a digest to all its webhooks
+	digestPostTimeout = 10 * time.Second
+)
+
+// runDigestCommand prints a session's digest: duration, cost, tokens, files
+// changed and todos completed
+func runDigestCommand(args []string) int {
+	fs := flag.NewFlagSet("digest", flag.ContinueOnError)
+	transcriptPath := fs.String("transcript", "", "Transcript to read (default: the latest session in the current directory)")
+	sessionID := fs.String("session", "", "Read this session's transcript, by ID or name")
+	markdown := fs.Bool("markdown", false, "Print the digest as a Markdown worklog entry")
+	logDir := fs.String("log", "", "Also append the digest to the daily log in this directory")
+	post := fs.Bool("post", false, "Also post the digest to the webhooks configured under digest.webhooks")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	sess, err := resolveSession(sessionArgs{Transcript: *transcriptPath, Session: *sessionID, Latest: true})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "digest: %v\n", err)
+		return 1
+	}
+	d, err := digest.FromTranscript(context.Background(), sess.Transcript)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "digest: %v\n", err)
+		return 1
+	}
+	d.Name, d.Dir = sess.Name, sess.Dir
+
+	if *markdown {
+		fmt.Print(d.Markdown())
+	} else {
+		fmt.Print(d.Text())
+	}
+	if *logDir != "" {
+		if _, err := digest.AppendLog(expandHome(*logDir), d); err != nil {
+			fmt.Fprintf(os.Stderr, "digest: %v\n", err)
+			return 1
+		}
+	}
+	if *post {
+		errs := postDigest(config.Load(), d)
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "digest: %v\n", err)
+		}
+		if len(errs) > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// printSessionDigest prints the digest of the session a Stop or SessionEnd
+// hook reported, when digest is enabled for that event, appends it to the
+// daily log and posts it to the webhooks configured. name is the session's
+// name, if any
+func printSessionDigest(cfg *config.Config, ev *hook.Event, name string) {
+	if !cfg.Digest.Enabled || ev.TranscriptPath == "" {
+		return
+	}
+	want := hook.EventSessionEnd
+	if cfg.Digest.On == "stop" {
+		want = hook.EventStop
+	}
+	if ev.HookEventName != want {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), digestTimeout)
+	defer cancel()
+	d, err := digest.FromTranscript(ctx, ev.TranscriptPath)
+	if err != nil {
+		errors.Debug("hook", "digest failed: %v", err)
+		return
+	}
+	d.SessionID, d.Name, d.Dir = ev.SessionID, name, ev.Cwd
+
+	fmt.Print(d.Text())
+	if cfg.Digest.LogDir != "" {
+		if _, err := digest.AppendLog(expandHome(cfg.Digest.LogDir), d); err != nil {
+			errors.Debug("hook", "digest log failed: %v", err)
+		}
+	}
+	for _, err := range postDigest(cfg, d) {
+		errors.Debug("hook", "digest post failed: %v", err)
+	}
+}
+
+// postDigest posts d to each configured webhook that is not over its rate
+// limit, returning what went wrong
+func postDigest(cfg *config.Config, d digest.Digest) []error {
+	if len(cfg.Digest.Webhooks) == 0 {
+		return nil
+	}
+	store, err := session.DefaultStore()
+	if err != nil {
+		return []error{err}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), digestPostTimeout)
+	defer cancel()
+
+	var errs []error
+	for _, hookConfig := range cfg.Digest.Webhooks {
+		w, err := webhook.New(hookConfig)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		allowed := false
+		if err := store.Update(func(state *session.State) error {
+			allowed = state.AllowPost(w.Name(), w.PerHour(), time.Now())
+			return nil
+		}); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !allowed {
+			errs = append(errs, fmt.Errorf("webhook %q: skipped, already posted %d times in the last hour", w.Name(), w.PerHour()))
+			continue
+		}
+
+		if err := w.Post(ctx, d); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// sessionArgs select the session a command reads, from its flags
+type sessionArgs struct {
+	Transcript string // A transcript file
+	Session    string // A session ID or name
+	Latest     bool   // Else read the latest session in the current directory
+}
+
+// resolvedSession is the session a command reads
+type resolvedSession struct {
+	Transcript string // "" when args selected none
+	Cwd        string // The current directory; "" when it is unknown
+	Dir        string // The directory the session ran in, else Cwd
+	Name       string // Set with `claude-hud name`
+}
+
+// resolveSession returns the session args select: the given transcript, the
+// transcript of a session by ID or name or, with Latest, the latest session
+// in the current directory
+func resolveSession(args sessionArgs) (resolvedSession, error) {
+	var sess resolvedSession
+	sess.Cwd, _ = os.Getwd()
+	switch {
+	case args.Transcript != "":
+		sess.Transcript = args.Transcript
+	case args.Session != "":
+		if sess.Transcript = sessionTranscript(args.Session); sess.Transcript == "" {
+			return sess, fmt.Errorf("no transcript recorded for session %s", args.Session)
+		}
+	case args.Latest:
+		if sess.Cwd != "" {
+			sess.Transcript = latestTranscript(sess.Cwd)
+		}
+		if sess.Transcript == "" {
+			return sess, fmt.Errorf("no transcript found for the current directory; pass --transcript or --session")
+		}
+	}
+
+	sess.Dir = sess.Cwd
+	if sess.Transcript == "" {
+		return sess, nil
+	}
+	if recorded := recordedSession(sess.Transcript); recorded != nil {
+		sess.Name, sess.Dir = recorded.Name, recorded.Cwd
+	}
+	return sess, nil
+}
+
+// recordedSession returns the session the hooks recorded with transcript
+// path, or nil
+func recordedSession(path string) *session.SessionState {
+	store, err := session.DefaultStore()
+	if err != nil {
+		return nil
+	}
+	state, err := store.Load()
+	if err != nil {
+		return nil
+	}
+	return state.FindByTranscript(path)
+}
+
+// expandHome replaces a leading ~/ in a configured path with the home directory
+func expandHome(path string) string {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, rest)
+}
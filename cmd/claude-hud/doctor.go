@@ -0,0 +1,347 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/crash"
+	"github.com/ll931217/claude-hud-enhanced/internal/daemon"
+	"github.com/ll931217/claude-hud-enhanced/internal/quota"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/render"
+	"github.com/ll931217/claude-hud-enhanced/internal/statusline"
+	"github.com/ll931217/claude-hud-enhanced/internal/system"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+	"github.com/ll931217/claude-hud-enhanced/internal/watcher"
+)
+
+// runDoctorCommand checks the config, daemon, file watching and transcript
+// discovery and, with --sections, renders every enabled section once and reports its health
+// Exits non-zero when a check fails or a section is degraded
+func runDoctorCommand(args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	showSections := fs.Bool("sections", false, "Render each enabled section and report its health")
+	dir := fs.String("dir", ".", "Workspace directory to check")
+	transcriptPath := fs.String("transcript", "", "Transcript to render sections against (default: latest for --dir)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	workspace, err := filepath.Abs(*dir)
+	if err == nil {
+		err = checkDir(workspace)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "claude-hud doctor: %v\n", err)
+		return 1
+	}
+
+	ok := checkConfig()
+	checkDaemon()
+	checkWatcher(workspace)
+	checkBilling()
+	checkCrashes()
+
+	if *transcriptPath == "" {
+		*transcriptPath = latestTranscript(workspace)
+	}
+	if *transcriptPath == "" {
+		fmt.Printf("✗ transcript  none found for %s\n", workspace)
+	} else {
+		checkTranscript(*transcriptPath)
+	}
+
+	if *showSections {
+		statusline.SetContext(*transcriptPath, "")
+		fmt.Println()
+		ok = printSectionHealth(config.Load(), workspace) && ok
+	}
+
+	if !ok {
+		return 1
+	}
+	return 0
+}
+
+// checkDir reports an error unless dir is an existing directory
+func checkDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	return nil
+}
+
+// checkConfig reports whether the config file parses and is current
+func checkConfig() bool {
+	path, err := config.ConfigPath()
+	if err != nil {
+		fmt.Printf("✗ config      %v\n", err)
+		return false
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		fmt.Printf("✓ config      %s not found, using defaults\n", path)
+		return true
+	}
+	if err != nil {
+		fmt.Printf("✗ config      %v\n", err)
+		return false
+	}
+	_, result, err := config.Migrate(data)
+	switch {
+	case err != nil:
+		fmt.Printf("✗ config      %s: %v\n", path, err)
+		return false
+	case result.Migrated():
+		fmt.Printf("! config      %s is config_version %d; run `claude-hud config migrate`\n", path, result.FromVersion)
+	default:
+		fmt.Printf("✓ config      %s\n", path)
+	}
+	return true
+}
+
+// checkTranscript reports the transcript sections read and how well it
+// parses: lines that are not JSON, lines too long to read, and fields left
+// out for having a type this version doesn't expect, which suggest a newer
+// transcript format
+func checkTranscript(path string) {
+	configureTranscript(config.Load())
+	p := transcript.NewParser(path)
+	if err := p.Parse(context.Background()); err != nil {
+		fmt.Printf("✗ transcript  %s: %v\n", path, err)
+		return
+	}
+	state := p.GetState()
+	if state.ErrorsEncountered == 0 && len(state.FieldErrors) == 0 && state.OversizedLines == 0 {
+		fmt.Printf("✓ transcript  %s\n", path)
+		return
+	}
+	fmt.Printf("! transcript  %s: %d of %d lines failed to parse\n", path, state.ErrorsEncountered, state.LinesParsed)
+	if state.OversizedLines > 0 {
+		fmt.Printf("              %d lines over transcript.max_line_bytes were skipped\n", state.OversizedLines)
+	}
+	for _, field := range render.Keys(state.FieldErrors) {
+		fmt.Printf("              %s had an unexpected type (%d×)\n", field, state.FieldErrors[field])
+	}
+}
+
+// checkBilling reports how the cost section will treat the session
+// Billing that cannot be detected is priced in dollars, so it is not a failure
+func checkBilling() {
+	billing := quota.DetectBilling("")
+	switch billing.Mode {
+	case quota.BillingSubscription:
+		plan := billing.Plan
+		if plan == "" {
+			plan = "unknown plan"
+		}
+		fmt.Printf("✓ billing     subscription (%s, from %s); cost shows quota left\n", plan, billing.Source)
+	case quota.BillingAPI:
+		fmt.Printf("✓ billing     API (from %s); cost shows dollars\n", billing.Source)
+	default:
+		fmt.Printf("- billing     not detected; cost shows dollars (set sections.cost.mode to override)\n")
+	}
+}
+
+// checkCrashes reports the panics recorded across runs
+// Sections recover from panics, so they are not a failure, but worth reporting
+func checkCrashes() {
+	store, err := crash.DefaultStore()
+	if err != nil {
+		fmt.Printf("- panics      %v\n", err)
+		return
+	}
+	record, err := store.Load()
+	if err != nil {
+		fmt.Printf("! panics      %v\n", err)
+		return
+	}
+	printDisabled(record, time.Duration(config.Load().ErrorBudget.DisableMs)*time.Millisecond, time.Now())
+	total := record.PanicCount()
+	if total == 0 {
+		fmt.Printf("✓ panics      none recorded\n")
+		return
+	}
+	ops := record.Operations()
+	since := record.Panics[ops[0]].First
+	for _, p := range record.Panics {
+		if p.First.Before(since) {
+			since = p.First
+		}
+	}
+	fmt.Printf("! panics      %d recovered since %s; run `claude-hud bugreport`\n", total, since.Format("2006-01-02"))
+	for _, op := range ops {
+		p := record.Panics[op]
+		fmt.Printf("              %s ×%d, last %s: %s\n", op, p.Count, p.Last.Format("2006-01-02 15:04"), p.Message)
+	}
+}
+
+// printDisabled reports the sections a run turned off for panicking or
+// timing out too often, most recent first, and until when those turned off
+// within disableFor stay off
+func printDisabled(record *crash.Record, disableFor time.Duration, now time.Time) {
+	if len(record.Disabled) == 0 {
+		return
+	}
+	ops := render.Keys(record.Disabled)
+	slices.SortStableFunc(ops, func(a, b string) int {
+		return render.Newest(record.Disabled[a].At, record.Disabled[b].At)
+	})
+	fmt.Printf("! disabled    sections turned off for failing too often (see error_budget)\n")
+	for _, op := range ops {
+		d := record.Disabled[op]
+		still := ""
+		if until := d.At.Add(disableFor); until.After(now) {
+			still = fmt.Sprintf(", off until %s", until.Format("15:04"))
+		}
+		fmt.Printf("              %s at %s%s: %s\n", strings.TrimPrefix(op, "section."), d.At.Format("2006-01-02 15:04"), still, d.Reason)
+	}
+}
+
+// checkDaemon reports whether the daemon hooks report to answers, on its
+// socket or at the configured address
+// The daemon is optional, so a missing daemon is not a failure
+func checkDaemon() {
+	cfg := config.Load()
+	where := daemonAddress(cfg)
+	if where == "" {
+		socketPath, err := daemon.SocketPath()
+		if err != nil {
+			fmt.Printf("- daemon      %v\n", err)
+			return
+		}
+		where = socketPath
+	}
+	client, err := daemonClient(cfg, time.Second)
+	if err != nil {
+		fmt.Printf("✗ daemon      %v\n", err)
+		return
+	}
+	if err := client.Ping(); err != nil {
+		fmt.Printf("- daemon      not running (%s)\n", where)
+		return
+	}
+	fmt.Printf("✓ daemon      %s\n", where)
+}
+
+// checkWatcher reports whether transcripts can be watched with fsnotify
+// Polling still works, so falling back is not a failure
+func checkWatcher(workspace string) {
+	projectsDir, err := transcript.ProjectsDir()
+	if err != nil {
+		fmt.Printf("- watcher     %v\n", err)
+		return
+	}
+	dir := transcript.ProjectDirFor(projectsDir, workspace)
+	if _, err := os.Stat(dir); err != nil {
+		dir = projectsDir
+	}
+
+	cfg := config.Load()
+	configureWatching(cfg)
+	w := watcher.NewWatcher()
+	defer w.Stop()
+	if err := w.AddWatchGlob(filepath.Join(dir, "*.jsonl")); err != nil {
+		fmt.Printf("✗ watcher     %v\n", err)
+		return
+	}
+	if err := w.Start(context.Background()); err != nil {
+		fmt.Printf("✗ watcher     %v\n", err)
+		return
+	}
+
+	health := w.Health()
+	if health.PollOnly {
+		reason := "watch_mode"
+		if cfg.WatchMode != "polling" {
+			reason = "in " + string(system.DetectEnvironment())
+		}
+		fmt.Printf("✓ watcher     %s (%s), %d transcripts in %s\n", health.Mode, reason, health.Files, dir)
+		return
+	}
+	if health.Mode == watcher.ModePolling {
+		fmt.Printf("! watcher     %s (%v)\n", health.Mode, health.LastError)
+		return
+	}
+	fmt.Printf("✓ watcher     %s, %d transcripts in %s\n", health.Mode, health.Files, dir)
+}
+
+// latestTranscript returns the most recently modified transcript Claude Code
+// wrote for a workspace, or "" if there is none
+func latestTranscript(workspace string) string {
+	projectsDir, err := transcript.ProjectsDir()
+	if err != nil {
+		return ""
+	}
+	matches, _ := filepath.Glob(filepath.Join(transcript.ProjectDirFor(projectsDir, workspace), "*.jsonl"))
+
+	var latest string
+	var latestMod time.Time
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if info.ModTime().After(latestMod) {
+			latest, latestMod = path, info.ModTime()
+		}
+	}
+	return latest
+}
+
+// printSectionHealth renders each enabled section once against workspace and
+// prints its health
+// Returns false if any section is degraded
+func printSectionHealth(cfg *config.Config, workspace string) bool {
+	shared := newProviders()
+	defer shared.Close()
+	shared.SetWorkspace(workspace, "")
+	registry.DefaultRegistry().SetProviders(shared)
+
+	ok := true
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SECTION\tSTATE\tOUTPUT\tTIME\tREASON")
+	for _, name := range cfg.GetEnabledSections() {
+		section, err := registry.Create(name, cfg)
+		if err != nil {
+			fmt.Fprintf(w, "%s\t%s\t-\t-\t%v\n", name, "error", err)
+			ok = false
+			continue
+		}
+
+		start := time.Now()
+		content := section.Render()
+		elapsed := time.Since(start)
+
+		output := "yes"
+		if content == "" {
+			output = "empty"
+		}
+		health := registry.HealthOf(section)
+		if health.State == registry.HealthDegraded {
+			ok = false
+		}
+		reason := health.Reason
+		if reason == "" {
+			reason = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", name, health.State, output, elapsed.Round(time.Millisecond), reason)
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "claude-hud doctor: %v\n", err)
+		return false
+	}
+	return ok
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/export"
+	"github.com/ll931217/claude-hud-enhanced/internal/store"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+// runExportCommand writes normalized session data as CSV or JSON
+func runExportCommand(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", export.FormatCSV, "Output format: csv or json")
+	table := fs.String("table", export.TableEvents, "Table to export: events, tools, tokens (json also accepts all)")
+	sessionID := fs.String("session", "", "Only export this session, by ID or name")
+	transcriptPath := fs.String("transcript", "", "Export a single transcript file instead of discovering them")
+	since := fs.String("since", "", "Only export events at or after this date (YYYY-MM-DD or RFC 3339)")
+	until := fs.String("until", "", "Only export events before this date (YYYY-MM-DD or RFC 3339)")
+	output := fs.String("output", "", "Write to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	filter := store.Filter{SessionID: resolveSessionName(*sessionID)}
+	var err error
+	if filter.Since, err = parseExportTime(*since); err != nil {
+		fmt.Fprintf(os.Stderr, "export: invalid --since: %v\n", err)
+		return 2
+	}
+	if filter.Until, err = parseExportTime(*until); err != nil {
+		fmt.Fprintf(os.Stderr, "export: invalid --until: %v\n", err)
+		return 2
+	}
+
+	paths, err := exportPaths(*transcriptPath, filter.Since)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "export: %v\n", err)
+		return 1
+	}
+
+	ds, err := export.Collect(context.Background(), paths, filter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "export: %v\n", err)
+		return 1
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "export: %v\n", err)
+			return 1
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if err := export.Write(w, ds, *format, *table); err != nil {
+		fmt.Fprintf(os.Stderr, "export: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// exportPaths returns the transcripts to read: the explicit one, or every
+// transcript under the Claude projects directory modified since the start date
+func exportPaths(transcriptPath string, since time.Time) ([]string, error) {
+	if transcriptPath != "" {
+		return []string{transcriptPath}, nil
+	}
+	dir, err := transcript.ProjectsDir()
+	if err != nil {
+		return nil, err
+	}
+	return transcript.ListTranscripts(dir, since)
+}
+
+// parseExportTime accepts a date (local midnight) or an RFC 3339 timestamp
+func parseExportTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
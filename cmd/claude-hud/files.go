@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+// runFilesCommand lists the files changed in a session, to review what a
+// session touched before committing
+func runFilesCommand(args []string) int {
+	fs := flag.NewFlagSet("files", flag.ContinueOnError)
+	transcriptPath := fs.String("transcript", "", "Transcript to read (default: the latest session in the current directory)")
+	sessionID := fs.String("session", "", "Read this session's transcript, by ID or name")
+	absolute := fs.Bool("absolute", false, "Print absolute paths instead of paths relative to the current directory")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	sess, err := resolveSession(sessionArgs{Transcript: *transcriptPath, Session: *sessionID, Latest: true})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "files: %v\n", err)
+		return 1
+	}
+	file, err := transcript.Open(sess.Transcript)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "files: %v\n", err)
+		return 1
+	}
+	parser := transcript.NewParser(sess.Transcript)
+	err = parser.ParseFromReader(context.Background(), file)
+	file.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "files: %v\n", err)
+		return 1
+	}
+
+	files := parser.GetTouchedFiles()
+	if len(files) == 0 {
+		fmt.Println("No files changed in this session")
+		return 0
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "EDITS\tLAST CHANGED\tFILE")
+	for _, f := range files {
+		name := f.Path
+		if !*absolute && sess.Cwd != "" {
+			if rel, err := filepath.Rel(sess.Cwd, f.Path); err == nil && !strings.HasPrefix(rel, "..") {
+				name = rel
+			}
+		}
+		last := "-"
+		if !f.Last.IsZero() {
+			last = f.Last.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", f.Edits, last, name)
+	}
+	w.Flush()
+	return 0
+}
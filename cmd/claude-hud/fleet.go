@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/fleet"
+	"github.com/ll931217/claude-hud-enhanced/internal/humanize"
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+// runFleetCommand lists the worktrees of the repository side by side, with
+// each one's branch, uncommitted changes and latest session, for keeping an
+// eye on agents working in worktrees of their own
+func runFleetCommand(args []string) int {
+	fs := flag.NewFlagSet("fleet", flag.ContinueOnError)
+	dir := fs.String("dir", ".", "A directory in the repository")
+	active := fs.Duration("active", fleet.DefaultActive, "Sessions quiet for longer count as inactive")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	projectsDir, err := transcript.ProjectsDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fleet: %v\n", err)
+		return 1
+	}
+	opts := fleet.Options{ProjectsDir: projectsDir, Active: *active}
+	if store, err := session.DefaultStore(); err == nil {
+		// Without the hooks' records, states are told from transcript writes
+		opts.State, _ = store.Load()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	members, err := fleet.Read(ctx, *dir, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fleet: %s is not in a git repository: %v\n", *dir, err)
+		return 1
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  WORKTREE\tBRANCH\tCHANGES\tSESSION\tSTATE\tCONTEXT\tLAST ACTIVE")
+	for _, m := range members {
+		mark := " "
+		if m.Current {
+			mark = "*"
+		}
+		changes := "clean"
+		switch {
+		case m.GitError != "":
+			changes = "?"
+		case m.Conflicts > 0:
+			changes = fmt.Sprintf("±%d, %d conflicts", m.Changes, m.Conflicts)
+		case m.Dirty:
+			changes = fmt.Sprintf("±%d", m.Changes)
+		}
+		name, state, usage, active := "-", "-", "-", "-"
+		if sess := m.Session; sess != nil {
+			name = sess.Name
+			if name == "" {
+				name = sess.ID
+			}
+			state = sess.State
+			if sess.ContextKnown {
+				usage = fmt.Sprintf("%d%%", sess.ContextPercent)
+			}
+			active = humanize.Ago(time.Since(sess.LastActive))
+		}
+		fmt.Fprintf(w, "%s %s\t%s\t%s\t%s\t%s\t%s\t%s\n", mark, m.Path, m.Branch, changes, name, state, usage, active)
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "fleet: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/store"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+// runGrepCommand searches message text and tool inputs across transcripts
+func runGrepCommand(args []string) int {
+	fs := flag.NewFlagSet("grep", flag.ContinueOnError)
+	ignoreCase := fs.Bool("i", false, "Match case-insensitively")
+	all := fs.Bool("all", false, "Search the transcripts of every project, not just the current directory's")
+	transcriptPath := fs.String("transcript", "", "Search a single transcript file")
+	maxMatches := fs.Int("max", 100, "Stop after this many matches (0 for no limit)")
+	maxLine := fs.Int("max-line", transcript.MAX_SCAN_TOKEN_SIZE, "Skip transcript lines longer than this many bytes")
+	noColor := fs.Bool("no-color", false, "Print without colors (also when NO_COLOR is set or output is not a terminal)")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: claude-hud grep [flags] <pattern>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	pattern := fs.Arg(0)
+	if *ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "grep: invalid pattern: %v\n", err)
+		return 2
+	}
+
+	sess, err := resolveSession(sessionArgs{Transcript: *transcriptPath})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "grep: %v\n", err)
+		return 1
+	}
+	paths, err := grepPaths(sess, *all)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "grep: %v\n", err)
+		return 1
+	}
+	if len(paths) == 0 {
+		fmt.Fprintln(os.Stderr, "grep: no transcripts found for the current directory; pass --all or --transcript")
+		return 1
+	}
+
+	color := !*noColor && os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout)
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	found := 0
+	for _, path := range paths {
+		file, err := transcript.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "grep: %v\n", err)
+			continue
+		}
+		sessionID := store.SessionIDFromPath(path)
+		err = transcript.Search(file, re, *maxLine, func(m transcript.SearchMatch) bool {
+			printSearchMatch(w, sessionID, m, re, color)
+			found++
+			return *maxMatches <= 0 || found < *maxMatches
+		})
+		file.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "grep: %s: %v\n", path, err)
+		}
+		if *maxMatches > 0 && found >= *maxMatches {
+			w.Flush()
+			fmt.Fprintf(os.Stderr, "grep: stopped after %d matches (--max)\n", found)
+			break
+		}
+	}
+	if found == 0 {
+		return 1
+	}
+	return 0
+}
+
+// grepPaths returns the transcripts to search, most recent first
+func grepPaths(sess resolvedSession, all bool) ([]string, error) {
+	if sess.Transcript != "" {
+		return []string{sess.Transcript}, nil
+	}
+	projectsDir, err := transcript.ProjectsDir()
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	if all {
+		paths, err = transcript.ListTranscripts(projectsDir, time.Time{})
+	} else {
+		if sess.Cwd == "" {
+			return nil, fmt.Errorf("current directory unknown; pass --all or --transcript")
+		}
+		paths, err = transcript.ListProjectTranscripts(transcript.ProjectDirFor(projectsDir, sess.Cwd))
+	}
+	slices.Reverse(paths)
+	return paths, err
+}
+
+// printSearchMatch prints a match as session, time, where and snippet, with
+// the matched text highlighted
+func printSearchMatch(w *bufio.Writer, sessionID string, m transcript.SearchMatch, re *regexp.Regexp, color bool) {
+	if len(sessionID) > 8 {
+		sessionID = sessionID[:8]
+	}
+	when := "                   "
+	if !m.Time.IsZero() {
+		when = m.Time.Local().Format("2006-01-02 15:04:05")
+	}
+	where := "You"
+	if m.Role == "assistant" {
+		where = "Claude"
+	}
+	if m.Tool != "" {
+		where = m.Tool + " " + m.Field
+	}
+	if m.Sidechain {
+		where = "↳ " + where
+	}
+
+	snippet := m.Snippet
+	if color {
+		snippet = re.ReplaceAllStringFunc(snippet, func(s string) string {
+			return ansiBold + ansiRed + s + ansiReset
+		})
+		sessionID = ansiMagenta + sessionID + ansiReset
+		when = ansiDim + when + ansiReset
+		where = ansiCyan + where + ansiReset
+	}
+	fmt.Fprintf(w, "%s  %s  %s: %s\n", sessionID, when, where, snippet)
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/daemon"
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+	"github.com/ll931217/claude-hud-enhanced/internal/hook"
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+)
+
+// hookDaemonTimeout bounds how long a hook waits for the daemon
+// Hooks run synchronously inside Claude Code, so this must stay small
+const hookDaemonTimeout = 150 * time.Millisecond
+
+// runHookMode ingests a single Claude Code hook payload from stdin
+// Errors are reported to the caller but must never block Claude Code
+func runHookMode() error {
+	defer recordCrashes()()
+
+	// Keep the raw payload to forward it to the daemon unchanged
+	var payload bytes.Buffer
+	ev, err := hook.Parse(io.TeeReader(os.Stdin, &payload))
+	if err != nil {
+		return err
+	}
+	cfg := config.Load()
+	configureToolTargets(cfg)
+	configureDangerousCommands(cfg)
+	configureTranscript(cfg)
+
+	store, err := session.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	var name string
+	if err := store.Update(func(state *session.State) error {
+		ev.Apply(state, time.Now())
+		name = state.Session(ev.SessionID).Name
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to update session state: %w", err)
+	}
+
+	// Index the finished turn into the optional session store
+	if ev.HookEventName == hook.EventStop || ev.HookEventName == hook.EventSessionEnd {
+		if cfg.Store.Enabled && ev.TranscriptPath != "" {
+			if err := ingestTranscript(cfg, ev.TranscriptPath); err != nil {
+				errors.Debug("hook", "store ingest failed: %v", err)
+			}
+		}
+		printSessionDigest(cfg, ev, name)
+	}
+
+	// Forward to the daemon for real-time subscribers; it is fine if none is running
+	client, err := daemonClient(cfg, hookDaemonTimeout)
+	if err != nil {
+		errors.Debug("hook", "%v", err)
+		return nil
+	}
+	_ = client.SendEvent(payload.Bytes())
+	return nil
+}
+
+// daemonAddress returns the address of the daemon hooks and statuslines
+// report to: $CLAUDE_HUD_DAEMON, then daemon.address; empty for the local socket
+func daemonAddress(cfg *config.Config) string {
+	if addr := os.Getenv(daemon.AddressEnv); addr != "" {
+		return addr
+	}
+	if cfg == nil {
+		return ""
+	}
+	return cfg.Daemon.Address
+}
+
+// daemonToken returns the shared token requests to the daemon over TCP
+// carry: $CLAUDE_HUD_DAEMON_TOKEN, then daemon.token
+func daemonToken(cfg *config.Config) string {
+	if token := os.Getenv(daemon.TokenEnv); token != "" {
+		return token
+	}
+	if cfg == nil {
+		return ""
+	}
+	return cfg.Daemon.Token
+}
+
+// daemonClient returns a client for the daemon hooks report to
+func daemonClient(cfg *config.Config, timeout time.Duration) (*daemon.Client, error) {
+	if addr := daemonAddress(cfg); addr != "" {
+		return daemon.Dial(addr, daemonToken(cfg), timeout)
+	}
+	socketPath, err := daemon.SocketPath()
+	if err != nil {
+		return nil, err
+	}
+	return daemon.NewClient(socketPath, timeout), nil
+}
+
+// reportStatusline sends what Claude Code told the statusline to a daemon
+// on another machine, which cannot read this session's transcript. The
+// returned function waits for it to be sent
+func reportStatusline(cfg *config.Config, input *ClaudeCodeInput) (wait func()) {
+	addr := daemonAddress(cfg)
+	if network, _, _ := daemon.ParseAddress(addr); network != "tcp" || input == nil {
+		return func() {} // A daemon on this machine reads the transcript itself
+	}
+	client, err := daemon.Dial(addr, daemonToken(cfg), hookDaemonTimeout)
+	if err != nil {
+		return func() {}
+	}
+
+	report := daemon.StatuslineReport{
+		SessionID:      input.SessionID,
+		TranscriptPath: input.TranscriptPath,
+		Cwd:            input.Workspace.CurrentDir,
+		Model:          input.Model.DisplayName,
+	}
+	report.Host, _ = os.Hostname()
+	if cw := input.ContextWindow; cw != nil {
+		usage := cw.CurrentUsage
+		report.ContextTokens = usage.InputTokens + usage.CacheCreationInputTokens + usage.CacheReadInputTokens
+		report.ContextSize = cw.ContextWindowSize
+	}
+
+	done := make(chan struct{})
+	errors.SafeGo("statusline.report", func() {
+		defer close(done)
+		if err := client.SendStatusline(report); err != nil {
+			errors.Debug("statusline", "%v", err)
+		}
+	})
+	return func() { <-done }
+}
+
+// runDaemonCommand runs the daemon in the foreground until interrupted,
+// or manages it as a service when given install, status, stop or uninstall
+func runDaemonCommand(args []string) int {
+	if len(args) > 0 && serviceActions[args[0]] {
+		return runServiceCommand(args[0])
+	}
+
+	fs := flag.NewFlagSet("daemon", flag.ContinueOnError)
+	socket := fs.String("socket", "", "Unix socket path (default: state directory)")
+	httpAddr := fs.String("http", "", "Also serve the API and web dashboard over TCP on this address, e.g. 127.0.0.1:7878")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	defer errors.MainRecovery()
+	defer recordCrashes()()
+
+	cfg := config.Load()
+	if cfg != nil {
+		configureLogging(cfg)
+		checkProfile(cfg)
+		configureToolTargets(cfg)
+		configureDangerousCommands(cfg)
+		configureTranscript(cfg)
+		configureWatching(cfg)
+		configureLocale(cfg)
+	}
+
+	// Anyone who can reach the port could read the sessions otherwise
+	token := daemonToken(cfg)
+	if *httpAddr != "" && token == "" {
+		fmt.Fprintf(os.Stderr, "claude-hud daemon: --http needs a shared token; set daemon.token or $%s\n", daemon.TokenEnv)
+		return 1
+	}
+
+	socketPath := *socket
+	if socketPath == "" {
+		var err error
+		socketPath, err = daemon.SocketPath()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "claude-hud daemon: %v\n", err)
+			return 1
+		}
+	}
+
+	store, err := session.DefaultStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "claude-hud daemon: %v\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := daemon.NewServer(store, socketPath)
+	waitReporter := startReporter(ctx, cfg, server.Hub())
+	shared := newProviders()
+	defer shared.Close()
+	if cfg != nil {
+		shared.SetMaxParsers(cfg.Daemon.MaxSessions) // One parser per session served
+	}
+	server.SetTranscripts(shared.Transcript)
+	waitJobs := startJobs(ctx, cfg, shared)
+
+	if *httpAddr != "" {
+		listener, err := net.Listen("tcp", *httpAddr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "claude-hud daemon: %v\n", err)
+			return 1
+		}
+		errors.SafeGo("daemon.http", func() {
+			if err := server.ServeListener(ctx, listener, token); err != nil {
+				errors.Error("daemon", "dashboard: %v", err)
+			}
+		})
+	}
+
+	err = server.Serve(ctx)
+	stop()
+	waitReporter()
+	waitJobs()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "claude-hud daemon: %v\n", err)
+		return 1
+	}
+	return 0
+}
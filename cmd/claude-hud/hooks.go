@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/hook"
+)
+
+// runHooksCommand reports how long user hooks took in a session, from the
+// debug log Claude Code writes when started with --debug. Exits 1 when a
+// tool hook averages at or above --slow
+func runHooksCommand(args []string) int {
+	fs := flag.NewFlagSet("hooks", flag.ContinueOnError)
+	session := fs.String("session", "", "Session ID (default: the most recent debug log)")
+	logPath := fs.String("log", "", "Debug log to read instead of ~/.claude/debug/<session>.txt")
+	slow := fs.Duration("slow", time.Second, "Flag hooks averaging at or above this")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	path := *logPath
+	if path == "" {
+		dir, err := hook.DebugLogDir()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "hooks: %v\n", err)
+			return 1
+		}
+		if *session != "" {
+			path = hook.DebugLogPath(dir, *session)
+		} else if path, err = latestDebugLog(dir); err != nil {
+			fmt.Fprintf(os.Stderr, "hooks: %v\n", err)
+			return 1
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "hooks: %v (start Claude Code with --debug to record hook timings)\n", err)
+		return 1
+	}
+	defer file.Close()
+	runs, err := hook.ParseDebugLog(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "hooks: %v\n", err)
+		return 1
+	}
+	if len(runs) == 0 {
+		fmt.Printf("No hook runs in %s\n", path)
+		return 0
+	}
+
+	summary := hook.Summarize(runs, *slow)
+	fmt.Printf("%s\n%d tool calls waited %s on hooks (%s per call)\n\n",
+		path, summary.ToolCalls, summary.ToolLatency.Round(time.Millisecond), summary.PerToolCall().Round(time.Millisecond))
+
+	flagged := false
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "EVENT\tCOMMAND\tRUNS\tAVG\tMAX\tSLOW\tFAILED\t")
+	for _, stats := range summary.Hooks {
+		mark := ""
+		if stats.Average() >= *slow && (stats.Event == hook.EventPreToolUse || stats.Event == hook.EventPostToolUse) {
+			mark = "slow"
+			flagged = true
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%d\t%d\t%s\n", stats.Event, stats.Command, stats.Runs,
+			stats.Average().Round(time.Millisecond), stats.Max.Round(time.Millisecond), stats.Slow, stats.Failed, mark)
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "hooks: %v\n", err)
+		return 1
+	}
+	if flagged {
+		return 1
+	}
+	return 0
+}
+
+// latestDebugLog returns the most recently written debug log in dir
+func latestDebugLog(dir string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return "", err
+	}
+	var latest string
+	var latestMod time.Time
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if info.ModTime().After(latestMod) {
+			latest, latestMod = match, info.ModTime()
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("no debug logs in %s; start Claude Code with --debug", dir)
+	}
+	return latest, nil
+}
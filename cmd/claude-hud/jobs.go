@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/digest"
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+	"github.com/ll931217/claude-hud-enhanced/internal/providers"
+	"github.com/ll931217/claude-hud-enhanced/internal/report"
+	"github.com/ll931217/claude-hud-enhanced/internal/scheduler"
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+// startJobs schedules the daemon's enabled background jobs. The returned
+// function waits for them to stop once ctx is cancelled
+func startJobs(ctx context.Context, cfg *config.Config, shared *providers.Providers) (wait func()) {
+	jobs := cfg.Daemon.Jobs
+	s := scheduler.New()
+	add := func(name string, job config.JobConfig, run func(ctx context.Context) error) {
+		if !job.Enabled {
+			return
+		}
+		if err := s.Add(scheduler.Job{
+			Name:     name,
+			Interval: time.Duration(job.IntervalMs) * time.Millisecond,
+			Jitter:   time.Duration(job.JitterMs) * time.Millisecond,
+			Run:      run,
+		}); err != nil {
+			errors.Warn("scheduler", "%v", err)
+			return
+		}
+		errors.Info("scheduler", "running %s every %s", name, time.Duration(job.IntervalMs)*time.Millisecond)
+	}
+
+	add("cache_refresh", jobs.CacheRefresh, func(ctx context.Context) error {
+		return refreshCaches(ctx, cfg, shared)
+	})
+	add("mcp_probe", jobs.MCPProbe, func(ctx context.Context) error {
+		probeMCPServers(ctx, shared)
+		return nil
+	})
+	add("janitor", jobs.Janitor.JobConfig, func(ctx context.Context) error {
+		return archiveTranscripts(ctx, jobs.Janitor.OlderThanDays)
+	})
+	add("reports", jobs.Reports.JobConfig, func(ctx context.Context) error {
+		return writeReports(ctx, jobs.Reports, recentSince(jobs.Reports.JobConfig))
+	})
+	add("digest", jobs.Digest, func(ctx context.Context) error {
+		return postDigests(ctx, cfg, recentSince(jobs.Digest))
+	})
+
+	if s.Len() == 0 {
+		return func() {}
+	}
+	done := make(chan struct{})
+	errors.SafeGo("scheduler", func() {
+		defer close(done)
+		s.Run(ctx)
+	})
+	return func() { <-done }
+}
+
+// refreshCaches refreshes the file caches statuslines read, so they rarely
+// have to wait on the network or a transcript scan themselves
+func refreshCaches(ctx context.Context, cfg *config.Config, shared *providers.Providers) error {
+	if _, _, err := shared.SessionWindow().Window(time.Now()); err != nil {
+		return fmt.Errorf("session window: %w", err)
+	}
+	if cfg.UsageAPI.Enabled {
+		if _, _, err := shared.UsageAPI().Usage(ctx, time.Duration(cfg.UsageAPI.CacheTTLMs)*time.Millisecond); err != nil {
+			return fmt.Errorf("usage API: %w", err)
+		}
+	}
+	return nil
+}
+
+// archiveTranscripts gzips transcripts not modified for olderThanDays, like
+// claude-hud clean --archive
+func archiveTranscripts(ctx context.Context, olderThanDays int) error {
+	dir, err := transcript.ProjectsDir()
+	if err != nil {
+		return err
+	}
+	files, err := transcript.ListFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -olderThanDays)
+	archived := 0
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if f.Archived || !f.ModTime.Before(cutoff) {
+			continue
+		}
+		if _, err := transcript.Archive(f.Path); err != nil {
+			return err
+		}
+		archived++
+	}
+	if archived > 0 {
+		errors.Info("janitor", "archived %d transcripts older than %d days", archived, olderThanDays)
+	}
+	return nil
+}
+
+// writeReports writes a report of each session active since the given time,
+// replacing the session's earlier report
+func writeReports(ctx context.Context, cfg config.ReportsJobConfig, since time.Time) error {
+	dir := expandHome(cfg.Dir)
+	if dir == "" {
+		stateDir, err := session.StateDir()
+		if err != nil {
+			return err
+		}
+		dir = filepath.Join(stateDir, "reports")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	paths, err := recentTranscripts(since)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		r, err := report.Build(ctx, path)
+		if err != nil {
+			return err
+		}
+		if sess := recordedSession(path); sess != nil {
+			r.Name, r.Dir = sess.Name, sess.Cwd
+		}
+
+		file, err := os.Create(filepath.Join(dir, r.SessionID+"."+cfg.Format))
+		if err != nil {
+			return err
+		}
+		err = report.Write(file, r, cfg.Format)
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// postDigests posts the digest of each session active since the given time
+// to the configured webhooks
+func postDigests(ctx context.Context, cfg *config.Config, since time.Time) error {
+	if len(cfg.Digest.Webhooks) == 0 {
+		return nil
+	}
+	paths, err := recentTranscripts(since)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		d, err := digest.FromTranscript(ctx, path)
+		if err != nil {
+			return err
+		}
+		if sess := recordedSession(path); sess != nil {
+			d.Name, d.Dir = sess.Name, sess.Cwd
+		}
+		for _, err := range postDigest(cfg, d) {
+			errors.Warn("digest", "%v", err)
+		}
+	}
+	return nil
+}
+
+// recentSince returns when the period a run of job covers starts: one
+// interval before now
+func recentSince(job config.JobConfig) time.Time {
+	return time.Now().Add(-time.Duration(job.IntervalMs) * time.Millisecond)
+}
+
+// recentTranscripts returns the transcripts modified since the given time
+func recentTranscripts(since time.Time) ([]string, error) {
+	dir, err := transcript.ProjectsDir()
+	if err != nil {
+		return nil, err
+	}
+	return transcript.ListTranscripts(dir, since)
+}
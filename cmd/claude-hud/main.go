@@ -6,28 +6,53 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"regexp"
+	"strings"
 	"sync"
 	"syscall"
 
 	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/crash"
 	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+	"github.com/ll931217/claude-hud-enhanced/internal/i18n"
+	"github.com/ll931217/claude-hud-enhanced/internal/providers"
 	"github.com/ll931217/claude-hud-enhanced/internal/registry"
 	_ "github.com/ll931217/claude-hud-enhanced/internal/sections" // Register sections via init()
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
 	"github.com/ll931217/claude-hud-enhanced/internal/statusline"
+	"github.com/ll931217/claude-hud-enhanced/internal/styled"
+	"github.com/ll931217/claude-hud-enhanced/internal/system"
+	"github.com/ll931217/claude-hud-enhanced/internal/terminal"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
 	"github.com/ll931217/claude-hud-enhanced/internal/version"
+	"github.com/ll931217/claude-hud-enhanced/internal/watcher"
 )
 
 var (
 	showVersion    = flag.Bool("version", false, "Show version information")
 	showBuild      = flag.Bool("build-info", false, "Show detailed build information")
 	statuslineMode = flag.Bool("statusline", false, "Run in Claude Code statusline mode (single shot, multiline output)")
+	hookMode       = flag.Bool("hook", false, "Run as a Claude Code hook (reads hook JSON from stdin, updates session state)")
+	debugOverlay   = flag.Bool("debug-overlay", false, "Append the last logged warnings and errors to the statusline as a dimmed line")
+	plainOutput    = flag.Bool("plain", false, "Print the statusline as labeled plain text for screen readers, e.g. \"context: 72 percent\"")
+	profileName    = flag.String("profile", "", "Use this profile of the config file (default: $CLAUDE_HUD_PROFILE, then the file's profile)")
+	widthHint      = flag.Int("width", 0, "Lay the statusline out for this many columns (default: $COLUMNS, then layout.width)")
+	outputFormat   = flag.String("output", "", "Write the statusline as ansi, tmux, plain or json (default: the config's output, then ansi)")
 	debugLogMutex  sync.Mutex
 )
 
 func main() {
+	// Subcommands (e.g. "claude-hud daemon") take precedence over flags
+	if code, ok := dispatchCommand(); ok {
+		os.Exit(code)
+	}
+
 	// Auto-detect statusline mode: if stdin has data (not a TTY), assume statusline mode
 	// This allows the binary to work directly with Claude Code without the --statusline flag
 	if !isStdinTTY() && !hasExplicitFlags() {
+		flag.Parse() // Statusline options such as --debug-overlay, --plain, --profile, --width and --output
+		config.SelectProfile(*profileName)
 		// Parse JSON from stdin and run in statusline mode
 		if err := runStatuslineMode(); err != nil {
 			// Silent failure for statusline mode
@@ -37,7 +62,13 @@ func main() {
 	}
 
 	// Parse flags
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] | <command> [args]\n\nFlags:\n", os.Args[0])
+		flag.PrintDefaults()
+		printCommands()
+	}
 	flag.Parse()
+	config.SelectProfile(*profileName)
 
 	// Handle version flag
 	if *showVersion {
@@ -57,6 +88,15 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Handle hook mode - ingest a hook event and exit without output
+	if *hookMode {
+		if err := runHookMode(); err != nil {
+			// Hooks must never fail Claude Code, so only log at debug level
+			errors.Debug("hook", "%v", err)
+		}
+		os.Exit(0)
+	}
+
 	// Handle statusline mode - single shot output for Claude Code
 	if *statuslineMode {
 		if err := runStatuslineMode(); err != nil {
@@ -67,6 +107,7 @@ func main() {
 	}
 	// Set up panic recovery at the top level
 	defer errors.MainRecovery()
+	flushCrashes := recordCrashes()
 
 	// Load configuration with error handling
 	cfg := config.Load()
@@ -76,8 +117,16 @@ func main() {
 	}
 
 	// Configure logging based on config
+	configureLogging(cfg)
+	checkProfile(cfg)
+	configureToolTargets(cfg)
+	configureDangerousCommands(cfg)
+	configureTranscript(cfg)
+	configureWatching(cfg)
+	configureLocale(cfg)
+	configureAccessibility(cfg)
+	configureTheme(cfg)
 	if cfg.Debug {
-		errors.SetDebugMode(true)
 		errors.Info("main", "debug mode enabled")
 	}
 
@@ -116,10 +165,13 @@ func main() {
 	}
 
 	errors.Info("main", "Claude HUD Enhanced stopped")
+	flushCrashes()
 }
 
 // runStatuslineMode runs the statusline in single-shot mode for Claude Code
 func runStatuslineMode() error {
+	defer recordCrashes()()
+
 	// Read JSON from stdin (non-blocking if no input)
 	input, err := readStdinJSON()
 	if err != nil {
@@ -133,6 +185,21 @@ func runStatuslineMode() error {
 		cfg = config.DefaultConfig()
 	}
 
+	configureLogging(cfg)
+	checkProfile(cfg)
+	configureToolTargets(cfg)
+	configureDangerousCommands(cfg)
+	configureTranscript(cfg)
+	configureWatching(cfg)
+	configureLocale(cfg)
+	configureAccessibility(cfg)
+	configureTheme(cfg)
+	configureWidth(cfg)
+
+	if *debugOverlay && cfg.DebugOverlay <= 0 {
+		cfg.DebugOverlay = statusline.DefaultDebugOverlay
+	}
+
 	// Log stdin input for debugging if debug mode is enabled
 	if cfg.Debug && input != nil {
 		logStdinDebug(input)
@@ -140,11 +207,6 @@ func runStatuslineMode() error {
 
 	// Set global context from JSON input
 	if input != nil {
-		// Change to workspace directory if specified
-		if input.Workspace.CurrentDir != "" {
-			_ = os.Chdir(input.Workspace.CurrentDir) // Try to change dir, but continue regardless
-		}
-
 		// Extract context window data
 		var contextWindowSize, contextInputTokens, contextCacheTokens int
 		if input.ContextWindow != nil {
@@ -153,50 +215,116 @@ func runStatuslineMode() error {
 			contextCacheTokens = input.ContextWindow.CurrentUsage.CacheCreationInputTokens + input.ContextWindow.CurrentUsage.CacheReadInputTokens
 		}
 
-		// Always set context (even if directory change failed)
 		statusline.SetContextWithWindow(
 			input.TranscriptPath,
-			input.Workspace.CurrentDir,
 			input.Model.DisplayName,
 			contextWindowSize,
 			contextInputTokens,
 			contextCacheTokens,
 		)
+		statusline.SetLongContext(input.Model.ID, input.Exceeds200k)
 	}
 
-	// Create statusline with registry
-	sl, err := statusline.New(cfg, registry.DefaultRegistry())
-	if err != nil {
-		return fmt.Errorf("failed to create statusline: %w", err)
-	}
+	// A daemon on another machine learns of this session from the statusline
+	defer reportStatusline(cfg, input)()
 
-	// Create sections from config
-	enabledSections := cfg.GetEnabledSections()
+	// Share one set of data providers across all sections
+	dataProviders := newProviders()
+	defer dataProviders.Close()
+	registry.DefaultRegistry().SetProviders(dataProviders)
+	if input != nil {
+		// Sections read the session's directories from the providers; the
+		// process's working directory is left alone
+		dataProviders.SetWorkspace(input.Workspace.CurrentDir, input.Workspace.ProjectDir)
+	}
 
 	// Debug output
 	if cfg.Debug {
-		fmt.Fprintf(os.Stderr, "DEBUG: Enabled sections: %v\n", enabledSections)
+		fmt.Fprintf(os.Stderr, "DEBUG: Enabled sections: %v\n", cfg.GetEnabledSections())
 		fmt.Fprintf(os.Stderr, "DEBUG: Layout.Responsive.Enabled=%v\n", cfg.Layout.Responsive.Enabled)
 		fmt.Fprintf(os.Stderr, "DEBUG: Layout.Lines=%d\n", len(cfg.Layout.Lines))
 		fmt.Fprintf(os.Stderr, "DEBUG: Stdin logged to /tmp/claude-hud-debug.log\n")
 	}
 
-	for _, sectionName := range enabledSections {
+	sl, err := newStatusline(cfg)
+	if err != nil {
+		return err
+	}
+	restoreErrorBudget(sl)
+	configureOutput(cfg, sl)
+
+	// Render once and exit (no continuous refresh)
+	if *plainOutput || cfg.Accessibility.Plain {
+		return sl.RenderPlain()
+	}
+	return sl.RenderStatuslineMode()
+}
+
+// newStatusline creates a statusline with the sections cfg enables
+func newStatusline(cfg *config.Config) (*statusline.Statusline, error) {
+	sl, err := statusline.New(cfg, registry.DefaultRegistry())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create statusline: %w", err)
+	}
+	for _, sectionName := range cfg.GetEnabledSections() {
 		section, err := registry.Create(sectionName, cfg)
 		if err != nil {
 			continue
 		}
 		sl.AddSection(section)
 	}
+	return sl, nil
+}
+
+// newProviders creates the data providers shared by all sections, persisting
+// rate samples in the state directory so they survive between renders
+func newProviders() *providers.Providers {
+	p := providers.New()
+	if dir, err := session.StateDir(); err == nil {
+		p.SetStateDir(dir)
+	}
+	return p
+}
 
-	// Render once and exit (no continuous refresh)
-	return sl.RenderStatuslineMode()
+// recordCrashes starts counting recovered panics; the returned function
+// persists them, with the warnings and errors logged, for doctor and bugreport
+// Error budget failures are persisted at once, as a render that hangs may
+// get the process killed before it returns
+func recordCrashes() func() {
+	recorder := crash.Install()
+	flush := func(entries []errors.Entry) {
+		store, err := crash.DefaultStore()
+		if err != nil {
+			return
+		}
+		if err := recorder.Flush(store, entries); err != nil {
+			errors.Debug("crash", "%v", err)
+		}
+	}
+	recorder.OnFailure(func() { flush(nil) })
+	return func() { flush(errors.Recent(errors.RecentCapacity)) }
+}
+
+// restoreErrorBudget carries the error budget over from earlier runs, so
+// the sections they turned off stay off
+func restoreErrorBudget(sl *statusline.Statusline) {
+	store, err := crash.DefaultStore()
+	if err != nil {
+		return
+	}
+	record, err := store.Load()
+	if err != nil {
+		errors.Debug("crash", "%v", err)
+		return
+	}
+	sl.RestoreErrorBudget(record)
 }
 
 // Application represents the main application
 type Application struct {
 	config     *config.Config
 	statusline *statusline.Statusline
+	providers  *providers.Providers
 	ctx        context.Context
 	cancel     context.CancelFunc
 }
@@ -207,9 +335,14 @@ func NewApplication(cfg *config.Config) (*Application, error) {
 		cfg = config.DefaultConfig()
 	}
 
+	// Share one set of data providers across all sections
+	dataProviders := newProviders()
+	registry.DefaultRegistry().SetProviders(dataProviders)
+
 	// Create statusline with registry
 	sl, err := statusline.New(cfg, registry.DefaultRegistry())
 	if err != nil {
+		dataProviders.Close()
 		return nil, fmt.Errorf("failed to create statusline: %w", err)
 	}
 
@@ -223,12 +356,14 @@ func NewApplication(cfg *config.Config) (*Application, error) {
 		}
 		sl.AddSection(section)
 	}
+	restoreErrorBudget(sl)
 
 	ctx, cancel := context.WithCancel(context.Background())
 
 	app := &Application{
 		config:     cfg,
 		statusline: sl,
+		providers:  dataProviders,
 		ctx:        ctx,
 		cancel:     cancel,
 	}
@@ -263,18 +398,200 @@ func (a *Application) Stop() error {
 	// Stop the statusline
 	a.statusline.Stop()
 
+	// Release shared data sources such as file watchers
+	a.providers.Close()
+
 	errors.Info("app", "application stopped successfully")
 	return nil
 }
 
+// configureLogging applies the logging settings and debug mode to the global
+// logger. Invalid levels are reported and ignored
+func configureLogging(cfg *config.Config) {
+	logger := errors.GetGlobalLogger()
+	if cfg.Logging.File != "" {
+		file, err := os.OpenFile(cfg.Logging.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			errors.Warn("config", "cannot open logging.file: %v", err)
+		} else {
+			logger.SetOutput(file)
+		}
+	}
+	if cfg.Logging.Level != "" {
+		level, err := errors.ParseLevel(cfg.Logging.Level)
+		if err != nil {
+			errors.Warn("config", "logging.level: %v", err)
+		} else {
+			logger.SetLevel(level)
+		}
+	}
+	for prefix, name := range cfg.Logging.Modules {
+		level, err := errors.ParseLevel(name)
+		if err != nil {
+			errors.Warn("config", "logging.modules.%s: %v", prefix, err)
+			continue
+		}
+		logger.SetModuleLevel(prefix, level)
+	}
+	if cfg.Debug {
+		errors.SetDebugMode(true)
+	}
+}
+
+// configureToolTargets applies the tool_targets settings. Rules with an
+// invalid format are reported and ignored
+func configureToolTargets(cfg *config.Config) {
+	if len(cfg.ToolTargets) == 0 {
+		return
+	}
+	rules := make(map[string]transcript.TargetRule, len(cfg.ToolTargets))
+	for name, target := range cfg.ToolTargets {
+		format, err := transcript.ParseTargetFormat(target.Format)
+		if err != nil {
+			errors.Warn("config", "tool_targets.%s: %v", name, err)
+			continue
+		}
+		rules[name] = transcript.TargetRule{Fields: target.Fields, Format: format, MaxLen: target.MaxLen}
+	}
+	transcript.SetTargetRules(rules)
+}
+
+// configureDangerousCommands applies the dangerous_commands settings. Patterns
+// that fail to compile are reported and ignored
+func configureDangerousCommands(cfg *config.Config) {
+	if len(cfg.DangerousCommands) == 0 {
+		return
+	}
+	patterns := make(map[string]*regexp.Regexp, len(cfg.DangerousCommands))
+	for name, pattern := range cfg.DangerousCommands {
+		if pattern == "" {
+			patterns[name] = nil
+			continue
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			errors.Warn("config", "dangerous_commands.%s: %v", name, err)
+			continue
+		}
+		patterns[name] = re
+	}
+	transcript.SetDangerPatterns(patterns)
+}
+
+// configureTranscript sets how long transcript lines may be and whether
+// longer ones are skipped
+func configureTranscript(cfg *config.Config) {
+	transcript.SetLineLimit(transcript.LineLimit{
+		MaxBytes:  cfg.Transcript.MaxLineBytes,
+		Oversized: cfg.Transcript.OversizedLines,
+	})
+}
+
+// configureWatching picks how files are watched: watch_mode, or in auto mode
+// polling inside containers and WSL, where fsnotify misses changes made
+// through bind mounts
+func configureWatching(cfg *config.Config) {
+	switch cfg.WatchMode {
+	case "polling":
+		watcher.SetPreferPolling(true)
+	case "fsnotify":
+		watcher.SetPreferPolling(false)
+	default:
+		if cfg.WatchMode != "" && cfg.WatchMode != "auto" {
+			errors.Warn("config", "watch_mode: unknown mode %q, using auto", cfg.WatchMode)
+		}
+		watcher.SetPreferPolling(system.DetectEnvironment().Contained())
+	}
+}
+
+// checkProfile warns about a profile that was selected but not applied
+func checkProfile(cfg *config.Config) {
+	if err := cfg.ProfileError(); err != nil {
+		errors.Warn("config", "%v", err)
+	}
+}
+
+// configureLocale selects the language of labels and durations
+func configureLocale(cfg *config.Config) {
+	if _, ok := i18n.SetLocale(cfg.Locale); !ok && cfg.Locale != "" && cfg.Locale != "auto" {
+		errors.Warn("config", "locale: no translation for %q, using %s", cfg.Locale, i18n.DefaultLocale)
+	}
+}
+
+// configureAccessibility applies the accessibility settings to the theme
+// Plain output is read without colors, so it keeps the state symbols
+func configureAccessibility(cfg *config.Config) {
+	a := cfg.Accessibility
+	theme.SetSymbols(a.Enabled || a.Symbols || a.Plain || *plainOutput)
+	theme.SetHighContrast(a.Enabled || a.HighContrast)
+}
+
+// configureTheme colors the statusline with the configured theme; the bright
+// colors of high contrast mode win over it
+func configureTheme(cfg *config.Config) {
+	a := cfg.Accessibility
+	if cfg.Theme == "" || a.Enabled || a.HighContrast {
+		return
+	}
+	t, err := theme.Named(cfg.Theme)
+	if err != nil {
+		errors.Warn("config", "theme: %v", err)
+		return
+	}
+	theme.Use(t)
+}
+
+// configureWidth sets the width a single-shot render lays out for. Claude
+// Code pipes the statusline's output, so the terminal cannot be measured
+// and the responsive layout would assume a wide one; a wrapper can pass the
+// width with --width or $COLUMNS, and layout.width stands in when neither
+// does
+func configureWidth(cfg *config.Config) {
+	switch {
+	case *widthHint > 0:
+		terminal.SetWidth(*widthHint)
+	case terminal.EnvWidth() > 0:
+		terminal.SetWidth(terminal.EnvWidth())
+	case cfg.Layout.Width > 0 && terminal.AvailableWidth() == 0:
+		terminal.SetWidth(cfg.Layout.Width)
+	}
+}
+
+// configureOutput sets how the statusline is written, from --output or the
+// config's output setting
+func configureOutput(cfg *config.Config, sl *statusline.Statusline) {
+	name := cfg.Output
+	if *outputFormat != "" {
+		name = *outputFormat
+	}
+	backend, err := styled.Lookup(name)
+	if err != nil {
+		errors.Warn("config", "output: %v", err)
+		return
+	}
+	sl.SetBackend(backend)
+}
+
 // isStdinTTY checks if stdin is a terminal (has no piped input)
 func isStdinTTY() bool {
 	fileInfo, _ := os.Stdin.Stat()
 	return fileInfo.Mode()&os.ModeCharDevice != 0
 }
 
-// hasExplicitFlags checks if any command-line flags were provided
+// hasExplicitFlags checks if any command-line flags were provided, other
+// than options of statusline mode such as --debug-overlay
 func hasExplicitFlags() bool {
-	// Check if any arguments were passed (beyond program name)
-	return len(os.Args) > 1
+	for i := 1; i < len(os.Args); i++ {
+		name, _, hasValue := strings.Cut(strings.TrimLeft(os.Args[i], "-"), "=")
+		switch name {
+		case "debug-overlay", "plain":
+		case "profile", "width", "output":
+			if !hasValue {
+				i++ // The option's value
+			}
+		default:
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+	"github.com/ll931217/claude-hud-enhanced/internal/providers"
+	"github.com/ll931217/claude-hud-enhanced/internal/render"
+)
+
+// runMCPCommand handles `claude-hud mcp <action>`
+func runMCPCommand(args []string) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "probe":
+			return runMCPProbe(args[1:])
+		case "tools":
+			return runMCPTools(args[1:])
+		}
+	}
+	fmt.Fprintln(os.Stderr, "Usage: claude-hud mcp probe [--timeout DURATION]")
+	fmt.Fprintln(os.Stderr, "       claude-hud mcp tools [--refresh] [--timeout DURATION]")
+	return 2
+}
+
+// runMCPProbe health-checks every configured MCP server, prints the results
+// and saves them for the mcp section. Exits non-zero when a server fails
+func runMCPProbe(args []string) int {
+	fs := flag.NewFlagSet("mcp probe", flag.ContinueOnError)
+	timeout := fs.Duration("timeout", 10*time.Second, "Per-server handshake timeout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	shared := newProviders()
+	defer shared.Close()
+	prober := shared.MCPProber()
+	prober.Timeout = *timeout
+
+	ctx := context.Background()
+	client := shared.MCP()
+	if err := client.DetectServers(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "mcp probe: %v\n", err)
+		return 1
+	}
+	servers := client.GetServers()
+	if len(servers) == 0 {
+		fmt.Println("No MCP servers configured")
+		return 0
+	}
+
+	ok := true
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVER\tSTATUS\tLATENCY\tERROR")
+	for _, result := range prober.ProbeAll(ctx, servers) {
+		status, reason := "ok", "-"
+		if !result.OK {
+			status, reason = "failed", result.Error
+			ok = false
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", result.Server, status, result.Latency.Round(time.Millisecond), reason)
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "mcp probe: %v\n", err)
+		return 1
+	}
+	if !ok {
+		return 1
+	}
+	return 0
+}
+
+// runMCPTools prints how many tools, resources and prompts each MCP server
+// offers. Servers are only started when their cached lists are older than
+// an hour, or with --refresh
+func runMCPTools(args []string) int {
+	fs := flag.NewFlagSet("mcp tools", flag.ContinueOnError)
+	refresh := fs.Bool("refresh", false, "Refetch every server's lists, ignoring the cache")
+	timeout := fs.Duration("timeout", 10*time.Second, "Per-server timeout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	shared := newProviders()
+	defer shared.Close()
+	inventory := shared.MCPInventory()
+	inventory.Timeout = *timeout
+
+	ctx := context.Background()
+	client := shared.MCP()
+	if err := client.DetectServers(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "mcp tools: %v\n", err)
+		return 1
+	}
+	servers := client.GetServers()
+	if len(servers) == 0 {
+		fmt.Println("No MCP servers configured")
+		return 0
+	}
+
+	entries := inventory.Refresh(ctx, servers, *refresh)
+	names := render.Keys(entries)
+
+	ok := true
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVER\tTOOLS\tRESOURCES\tPROMPTS\tFETCHED\tERROR")
+	for _, name := range names {
+		inv := entries[name]
+		reason := "-"
+		if inv.Error != "" {
+			reason = inv.Error
+			ok = false
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t%s\n", name, len(inv.Tools), len(inv.Resources), len(inv.Prompts),
+			inv.FetchedAt.Format("2006-01-02 15:04"), reason)
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "mcp tools: %v\n", err)
+		return 1
+	}
+	if !ok {
+		return 1
+	}
+	return 0
+}
+
+// probeMCPServers re-detects servers, so config changes are picked up, probes
+// them and refreshes tool inventories older than their TTL
+func probeMCPServers(ctx context.Context, shared *providers.Providers) {
+	client := shared.MCP()
+	if err := client.DetectServers(ctx); err != nil {
+		errors.Debug("mcp", "failed to detect MCP servers: %v", err)
+		return
+	}
+	servers := client.GetServers()
+	for _, result := range shared.MCPProber().ProbeAll(ctx, servers) {
+		if !result.OK {
+			errors.Debug("mcp", "probe of %s failed: %s", result.Server, result.Error)
+		}
+	}
+	for name, inv := range shared.MCPInventory().Refresh(ctx, servers, false) {
+		if inv.Error != "" {
+			errors.Debug("mcp", "listing tools of %s failed: %s", name, inv.Error)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/paths"
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+)
+
+// maxSessionNameLength caps session names, which share a HUD line with others
+const maxSessionNameLength = 60
+
+// runNameCommand handles `claude-hud name [NAME]`, naming the current session
+// in the shared state file; without a name it prints the current one
+func runNameCommand(args []string) int {
+	// The name comes first, e.g. `name "auth refactor" --session abc123`
+	var name string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = strings.TrimSpace(args[0]), args[1:]
+	}
+
+	fs := flag.NewFlagSet("name", flag.ContinueOnError)
+	sessionID := fs.String("session", "", "Session ID to name (default: the latest session in this directory)")
+	clearName := fs.Bool("clear", false, "Remove the session's name")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "name: quote names with spaces, e.g. claude-hud name \"auth refactor\"")
+		return 2
+	}
+	if len([]rune(name)) > maxSessionNameLength {
+		fmt.Fprintf(os.Stderr, "name: names are limited to %d characters\n", maxSessionNameLength)
+		return 2
+	}
+
+	store, err := session.DefaultStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "name: %v\n", err)
+		return 1
+	}
+	here, _ := resolveSession(sessionArgs{})
+
+	if name == "" && !*clearName {
+		state, err := store.Load()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "name: %v\n", err)
+			return 1
+		}
+		sess, err := currentSession(state, *sessionID, here.Cwd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "name: %v\n", err)
+			return 1
+		}
+		if sess.Name == "" {
+			fmt.Printf("Session %s has no name\n", sess.SessionID)
+		} else {
+			fmt.Println(sess.Name)
+		}
+		return 0
+	}
+
+	var named *session.SessionState
+	err = store.Update(func(state *session.State) error {
+		sess, err := currentSession(state, *sessionID, here.Cwd)
+		if err != nil {
+			return err
+		}
+		sess.Name = name
+		named = sess
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "name: %v\n", err)
+		return 1
+	}
+	if name == "" {
+		fmt.Printf("Cleared the name of session %s\n", named.SessionID)
+	} else {
+		fmt.Printf("Named session %s %q\n", named.SessionID, name)
+	}
+	return 0
+}
+
+// currentSession returns the session with the given ID or, without one, the
+// most recently active session started in dir or a parent of it, else the
+// most recently active session of all
+func currentSession(state *session.State, id, dir string) (*session.SessionState, error) {
+	if id != "" {
+		// Hooks may not have recorded the session yet; name it anyway
+		return state.Session(id), nil
+	}
+
+	var found *session.SessionState
+	for _, sess := range state.Sessions {
+		if sess.Cwd == "" || !paths.Within(dir, sess.Cwd) {
+			continue
+		}
+		if found == nil || sess.LastEventAt.After(found.LastEventAt) {
+			found = sess
+		}
+	}
+	if found == nil {
+		found = state.Latest()
+	}
+	if found == nil {
+		return nil, errors.New("no sessions recorded yet (are the hooks installed?); pass --session ID")
+	}
+	return found, nil
+}
+
+// resolveSessionName returns the ID of the session named nameOrID, or
+// nameOrID itself when no session has that name
+func resolveSessionName(nameOrID string) string {
+	if nameOrID == "" {
+		return ""
+	}
+	store, err := session.DefaultStore()
+	if err != nil {
+		return nameOrID
+	}
+	state, err := store.Load()
+	if err != nil {
+		return nameOrID
+	}
+	if _, ok := state.Sessions[nameOrID]; ok {
+		return nameOrID
+	}
+	if sess := state.FindByName(nameOrID); sess != nil {
+		return sess.SessionID
+	}
+	return nameOrID
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/statusline"
+	"github.com/ll931217/claude-hud-enhanced/internal/terminal"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+)
+
+// Sample context window shown by preview without --live
+const (
+	sampleModelID     = "claude-sonnet-4-5"
+	sampleModelName   = "Sonnet 4.5"
+	sampleWindowSize  = 200_000
+	sampleInputTokens = 92_000
+)
+
+// runPreviewCommand renders the statusline once, with sample data or the
+// latest session's, at a chosen width and theme, so layouts can be tried
+// without resizing the terminal or waiting for Claude Code to refresh
+func runPreviewCommand(args []string) int {
+	fs := flag.NewFlagSet("preview", flag.ContinueOnError)
+	width := fs.Int("width", 0, "Render for this many columns (default: the terminal's width)")
+	themeName := fs.String("theme", "", "Theme to render with: "+strings.Join(theme.Names(), ", ")+" (default: the config's)")
+	configPath := fs.String("config", "", "Config file to preview (default: the usual one)")
+	profile := fs.String("profile", "", "Config profile to preview")
+	live := fs.Bool("live", false, "Render the latest session in the current directory instead of sample data")
+	transcriptPath := fs.String("transcript", "", "Render this transcript instead of sample data")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *width < 0 {
+		fmt.Fprintln(os.Stderr, "preview: --width must not be negative")
+		return 2
+	}
+	if *themeName != "" {
+		if _, err := theme.Named(*themeName); err != nil {
+			fmt.Fprintf(os.Stderr, "preview: %v\n", err)
+			return 2
+		}
+	}
+
+	config.SelectProfile(*profile)
+	cfg := config.Load()
+	if *configPath != "" {
+		cfg = config.LoadFromPath(expandHome(*configPath))
+	}
+	if err := cfg.ProfileError(); err != nil {
+		fmt.Fprintf(os.Stderr, "preview: %v\n", err)
+		return 1
+	}
+	if *themeName != "" {
+		cfg.Theme = *themeName
+	}
+	configureLogging(cfg)
+	configureToolTargets(cfg)
+	configureDangerousCommands(cfg)
+	configureTranscript(cfg)
+	configureLocale(cfg)
+	configureAccessibility(cfg)
+	configureTheme(cfg)
+	if *width > 0 {
+		terminal.SetWidth(*width)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "preview: %v\n", err)
+		return 1
+	}
+	if *live || *transcriptPath != "" {
+		sess, err := resolveSession(sessionArgs{Transcript: *transcriptPath, Latest: true})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "preview: %v\n", err)
+			return 1
+		}
+		statusline.SetContext(sess.Transcript, "")
+	} else {
+		dir, err := os.MkdirTemp("", "claude-hud-preview-")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "preview: %v\n", err)
+			return 1
+		}
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "sample.jsonl")
+		if err := os.WriteFile(path, sampleTranscript(cwd, time.Now()), 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "preview: %v\n", err)
+			return 1
+		}
+		statusline.SetContextWithWindow(path, sampleModelName, sampleWindowSize, sampleInputTokens, 0)
+		statusline.SetLongContext(sampleModelID, false)
+	}
+
+	dataProviders := newProviders()
+	defer dataProviders.Close()
+	dataProviders.SetWorkspace(cwd, "")
+	registry.DefaultRegistry().SetProviders(dataProviders)
+
+	sl, err := newStatusline(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "preview: %v\n", err)
+		return 1
+	}
+	if cfg.Accessibility.Plain {
+		err = sl.RenderPlain()
+	} else {
+		err = sl.RenderStatuslineMode()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "preview: %v\n", err)
+		return 1
+	}
+
+	// Mark the width, so lines running past it stand out
+	if columns := terminal.AvailableWidth(); columns > 0 {
+		label := fmt.Sprintf(" %d columns", columns)
+		fmt.Printf("\n%s%s%s%s\n", theme.Dim, strings.Repeat("─", max(columns-len(label), 0)), label, theme.Reset)
+	}
+	return 0
+}
+
+// sampleTranscript returns a short transcript of a session in dir that
+// started 42 minutes before now: a prompt, file reads and edits, a todo
+// list and a test run still going, so most sections have something to show
+func sampleTranscript(dir string, now time.Time) []byte {
+	at := func(ago time.Duration) string {
+		return now.Add(-ago).UTC().Format(time.RFC3339)
+	}
+	file := func(name string) string {
+		path, _ := json.Marshal(filepath.Join(dir, name))
+		return string(path)
+	}
+	lines := []string{
+		fmt.Sprintf(`{"type":"user","timestamp":%q,"message":{"role":"user","content":"Add refresh tokens to the auth service"}}`, at(42*time.Minute)),
+		fmt.Sprintf(`{"type":"assistant","timestamp":%q,"message":{"id":"m1","role":"assistant","model":"%s","usage":{"input_tokens":18000,"cache_read_input_tokens":40000,"output_tokens":900},"content":[{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":%s}}]}}`, at(41*time.Minute), sampleModelID, file("auth/token.go")),
+		fmt.Sprintf(`{"type":"user","timestamp":%q,"message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"package auth"}]}}`, at(41*time.Minute)),
+		fmt.Sprintf(`{"type":"assistant","timestamp":%q,"message":{"id":"m2","role":"assistant","model":"%s","usage":{"input_tokens":24000,"cache_read_input_tokens":52000,"output_tokens":1400},"content":[{"type":"tool_use","id":"t2","name":"TodoWrite","input":{"todos":[{"content":"Add refresh token type","status":"completed","activeForm":"Adding refresh token type"},{"content":"Rotate tokens on refresh","status":"in_progress","activeForm":"Rotating tokens on refresh"},{"content":"Update docs","status":"pending","activeForm":"Updating docs"}]}}]}}`, at(30*time.Minute), sampleModelID),
+		fmt.Sprintf(`{"type":"user","timestamp":%q,"message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t2","content":"ok"}]}}`, at(30*time.Minute)),
+		fmt.Sprintf(`{"type":"assistant","timestamp":%q,"message":{"id":"m3","role":"assistant","model":"%s","usage":{"input_tokens":31000,"cache_read_input_tokens":61000,"output_tokens":2600},"content":[{"type":"tool_use","id":"t3","name":"Edit","input":{"file_path":%s,"old_string":"a","new_string":"b"}}]}}`, at(12*time.Minute), sampleModelID, file("auth/token.go")),
+		fmt.Sprintf(`{"type":"user","timestamp":%q,"message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t3","content":"ok"}]}}`, at(12*time.Minute)),
+		fmt.Sprintf(`{"type":"assistant","timestamp":%q,"message":{"id":"m4","role":"assistant","model":"%s","usage":{"input_tokens":33000,"cache_read_input_tokens":59000,"output_tokens":700},"content":[{"type":"tool_use","id":"t4","name":"Bash","input":{"command":"go test ./auth/..."}}]}}`, at(10*time.Second), sampleModelID),
+	}
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/report"
+)
+
+// runReportCommand writes a shareable Markdown or HTML report of a session
+func runReportCommand(args []string) int {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	format := fs.String("format", report.FormatMarkdown, "Output format: md or html")
+	transcriptPath := fs.String("transcript", "", "Transcript to read (default: the latest session in the current directory)")
+	sessionID := fs.String("session", "", "Report on this session, by ID or name")
+	output := fs.String("output", "", "Write to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *format != report.FormatMarkdown && *format != report.FormatHTML {
+		fmt.Fprintf(os.Stderr, "report: unknown format %q (want md or html)\n", *format)
+		return 2
+	}
+
+	sess, err := resolveSession(sessionArgs{Transcript: *transcriptPath, Session: *sessionID, Latest: true})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "report: %v\n", err)
+		return 1
+	}
+	r, err := report.Build(context.Background(), sess.Transcript)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "report: %v\n", err)
+		return 1
+	}
+	r.Name, r.Dir = sess.Name, sess.Dir
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "report: %v\n", err)
+			return 1
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if err := report.Write(w, r, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "report: %v\n", err)
+		return 1
+	}
+	return 0
+}
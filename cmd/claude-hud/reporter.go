@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/daemon"
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+	"github.com/ll931217/claude-hud-enhanced/internal/hook"
+	"github.com/ll931217/claude-hud-enhanced/internal/reporter"
+)
+
+// startReporter summarizes sessions as they stop and delivers the summaries
+// to the configured team endpoint in the background
+// The returned function waits for the final flush after ctx is cancelled
+// Does nothing unless the reporter is enabled
+func startReporter(ctx context.Context, cfg *config.Config, hub *daemon.Hub) (wait func()) {
+	rep := reporter.New(cfg.Reporter)
+	if rep == nil {
+		return func() {}
+	}
+
+	errors.Info("reporter", "sending usage summaries to %s", cfg.Reporter.Endpoint)
+
+	done := make(chan struct{})
+	ch, cancel := hub.Subscribe()
+	errors.SafeGo("reporter", func() {
+		defer close(done)
+		rep.Run(ctx)
+	})
+	errors.SafeGo("reporter", func() {
+		defer cancel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				var ev hook.Event
+				if err := json.Unmarshal(msg, &ev); err != nil || ev.TranscriptPath == "" {
+					continue
+				}
+				if ev.HookEventName != hook.EventStop && ev.HookEventName != hook.EventSessionEnd {
+					continue
+				}
+				summary, err := reporter.SummarizeTranscript(ctx, ev.TranscriptPath)
+				if err != nil {
+					errors.Debug("reporter", "failed to summarize %s: %v", ev.SessionID, err)
+					continue
+				}
+				rep.Record(summary)
+			}
+		}
+	})
+
+	return func() { <-done }
+}
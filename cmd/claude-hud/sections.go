@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+)
+
+// runSectionsCommand handles `claude-hud sections <action>`
+func runSectionsCommand(args []string) int {
+	if len(args) == 0 || args[0] != "list" {
+		fmt.Fprintln(os.Stderr, "Usage: claude-hud sections list [--dir DIR]")
+		return 2
+	}
+	return runSectionsList(args[1:])
+}
+
+// runSectionsList prints every registered section, whether it is enabled and
+// whether its data sources are present for the workspace
+func runSectionsList(args []string) int {
+	fs := flag.NewFlagSet("sections list", flag.ContinueOnError)
+	dir := fs.String("dir", ".", "Workspace directory to check data sources against")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg := config.Load()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tENABLED\tPRIORITY\tDATA\tDESCRIPTION")
+	for _, meta := range registry.All() {
+		enabled := "no"
+		if cfg.IsSectionEnabled(meta.Name) {
+			enabled = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			meta.Name, enabled, meta.Priority, formatDependencies(meta.Dependencies, *dir), meta.Description)
+		for _, opt := range meta.Options {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t\t%s\n", opt.Name, opt.Type, "default "+opt.Default, opt.Description)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "sections list: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// formatDependencies lists data sources with ✓ when present and ✗ when missing
+func formatDependencies(deps []registry.Dependency, dir string) string {
+	if len(deps) == 0 {
+		return "-"
+	}
+	parts := make([]string, 0, len(deps))
+	for _, dep := range deps {
+		mark := "✗"
+		if dep.Available(dir) {
+			mark = "✓"
+		}
+		parts = append(parts, dep.Name+" "+mark)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/daemon"
+)
+
+// serviceActions are the `claude-hud daemon <action>` service management commands
+var serviceActions = map[string]bool{
+	"install":   true,
+	"status":    true,
+	"stop":      true,
+	"uninstall": true,
+}
+
+// runServiceCommand manages the daemon as a systemd user unit or launchd agent
+func runServiceCommand(action string) int {
+	svc, err := daemon.NewService()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "claude-hud daemon %s: %v\n", action, err)
+		return 1
+	}
+
+	switch action {
+	case "install":
+		err = svc.Install()
+		if err == nil {
+			fmt.Printf("Installed %s and started the daemon\n", svc.UnitPath())
+		}
+	case "stop":
+		err = svc.Stop()
+		if err == nil {
+			fmt.Println("Daemon stopped")
+		}
+	case "uninstall":
+		err = svc.Uninstall()
+		if err == nil {
+			fmt.Printf("Removed %s\n", svc.UnitPath())
+		}
+	case "status":
+		return printServiceStatus(svc)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "claude-hud daemon %s: %v\n", action, err)
+		return 1
+	}
+	return 0
+}
+
+// printServiceStatus reports the service manager state and whether the socket answers
+// Exits non-zero when the daemon is not responding
+func printServiceStatus(svc *daemon.Service) int {
+	status, err := svc.Status()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "claude-hud daemon status: %v\n", err)
+		return 1
+	}
+
+	installed := "not installed"
+	if status.Installed {
+		installed = svc.UnitPath()
+	}
+	fmt.Printf("Service:  %s\n", installed)
+	fmt.Printf("Manager:  %s\n", status.Manager)
+
+	socketPath, err := daemon.SocketPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "claude-hud daemon status: %v\n", err)
+		return 1
+	}
+	if err := daemon.NewClient(socketPath, time.Second).Ping(); err != nil {
+		fmt.Printf("Socket:   %s (not responding)\n", socketPath)
+		return 1
+	}
+	fmt.Printf("Socket:   %s (ok)\n", socketPath)
+	return 0
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/humanize"
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+)
+
+// runSessionsCommand lists the sessions in the shared state file, most
+// recently active first, with their names
+func runSessionsCommand(args []string) int {
+	fs := flag.NewFlagSet("sessions", flag.ContinueOnError)
+	named := fs.Bool("named", false, "Only list named sessions")
+	limit := fs.Int("limit", 20, "Number of sessions to list (0 lists all)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	store, err := session.DefaultStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sessions: %v\n", err)
+		return 1
+	}
+	state, err := store.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sessions: %v\n", err)
+		return 1
+	}
+
+	var list []*session.SessionState
+	for _, sess := range state.Sessions {
+		if *named && sess.Name == "" {
+			continue
+		}
+		list = append(list, sess)
+	}
+	switch {
+	case len(list) == 0 && *named:
+		fmt.Println("No named sessions (name one with claude-hud name)")
+		return 0
+	case len(list) == 0:
+		fmt.Println("No sessions recorded (sessions are recorded by the hooks)")
+		return 0
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].LastEventAt.Equal(list[j].LastEventAt) {
+			return list[i].LastEventAt.After(list[j].LastEventAt)
+		}
+		return list[i].SessionID < list[j].SessionID
+	})
+	if *limit > 0 && len(list) > *limit {
+		list = list[:*limit]
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SESSION\tNAME\tLAST ACTIVE\tDIRECTORY")
+	for _, sess := range list {
+		name, active := sess.Name, "-"
+		if name == "" {
+			name = "-"
+		}
+		if !sess.LastEventAt.IsZero() {
+			active = humanize.Ago(time.Since(sess.LastEventAt))
+		}
+		dir := sess.Cwd
+		if dir == "" {
+			dir = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", sess.SessionID, name, active, dir)
+	}
+	w.Flush()
+	return 0
+}
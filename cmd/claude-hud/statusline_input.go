@@ -9,17 +9,21 @@ import (
 
 // ClaudeCodeInput represents the JSON input from Claude Code
 type ClaudeCodeInput struct {
+	SessionID      string              `json:"session_id"`
 	Workspace      WorkspaceInfo       `json:"workspace"`
 	TranscriptPath string              `json:"transcript_path"`
 	Model          ModelInfo           `json:"model"`
 	ContextWindow  *ContextWindowInput `json:"context_window,omitempty"`
+	Exceeds200k    bool                `json:"exceeds_200k_tokens,omitempty"` // Set once the context is past 200k tokens
 }
 
 type WorkspaceInfo struct {
 	CurrentDir string `json:"current_dir"`
+	ProjectDir string `json:"project_dir"` // Where Claude Code was started; current_dir may be below it
 }
 
 type ModelInfo struct {
+	ID          string `json:"id"` // 1M-context beta models end in "[1m]"
 	DisplayName string `json:"display_name"`
 }
 
@@ -55,13 +59,14 @@ func readStdinJSON() (*ClaudeCodeInput, error) {
 	return &input, nil
 }
 
+// debugLogPath is where --debug mode logs the statusline input
+const debugLogPath = "/tmp/claude-hud-debug.log"
+
 // logStdinDebug logs the stdin JSON input to a file for debugging
 func logStdinDebug(input *ClaudeCodeInput) {
 	debugLogMutex.Lock()
 	defer debugLogMutex.Unlock()
 
-	const debugLogPath = "/tmp/claude-hud-debug.log"
-
 	// Marshal input to formatted JSON
 	data, err := json.MarshalIndent(input, "", "  ")
 	if err != nil {
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/store"
+)
+
+// storeIngestTimeout bounds transcript ingestion triggered from a hook
+const storeIngestTimeout = 2 * time.Second
+
+// openStore opens the SQLite session store at the configured path
+func openStore(cfg *config.Config) (*store.Store, error) {
+	path := cfg.Store.Path
+	if path == "" {
+		var err error
+		path, err = store.DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return store.Open(path)
+}
+
+// ingestTranscript adds new events from transcriptPath to the session store
+func ingestTranscript(cfg *config.Config, transcriptPath string) error {
+	st, err := openStore(cfg)
+	if err != nil {
+		return err
+	}
+	defer st.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), storeIngestTimeout)
+	defer cancel()
+
+	_, err = st.Ingest(ctx, transcriptPath)
+	return err
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+// ANSI styles of the timeline
+const (
+	ansiReset   = "\033[0m"
+	ansiBold    = "\033[1m"
+	ansiDim     = "\033[2m"
+	ansiRed     = "\033[31m"
+	ansiGreen   = "\033[32m"
+	ansiYellow  = "\033[33m"
+	ansiBlue    = "\033[34m"
+	ansiMagenta = "\033[35m"
+	ansiCyan    = "\033[36m"
+)
+
+// runTimelineCommand prints a session's events in order, for reading back
+// what happened in a session
+func runTimelineCommand(args []string) int {
+	fs := flag.NewFlagSet("timeline", flag.ContinueOnError)
+	transcriptPath := fs.String("transcript", "", "Transcript to read (default: the latest session in the current directory)")
+	sessionID := fs.String("session", "", "Read this session's transcript, by ID or name")
+	noColor := fs.Bool("no-color", false, "Print without colors (also when NO_COLOR is set or output is not a terminal)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	sess, err := resolveSession(sessionArgs{Transcript: *transcriptPath, Session: *sessionID, Latest: true})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "timeline: %v\n", err)
+		return 1
+	}
+
+	file, err := transcript.Open(sess.Transcript)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "timeline: %v\n", err)
+		return 1
+	}
+	entries, err := transcript.ReadTimeline(file)
+	file.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "timeline: %v\n", err)
+		return 1
+	}
+
+	color := !*noColor && os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout)
+	w := bufio.NewWriter(os.Stdout)
+	printTimeline(w, sess.Transcript, entries, color)
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "timeline: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// sessionTranscript returns the transcript path the hooks recorded for a
+// session, by ID or name
+func sessionTranscript(nameOrID string) string {
+	store, err := session.DefaultStore()
+	if err != nil {
+		return ""
+	}
+	state, err := store.Load()
+	if err != nil {
+		return ""
+	}
+	if sess, ok := state.Sessions[resolveSessionName(nameOrID)]; ok {
+		return sess.TranscriptPath
+	}
+	return ""
+}
+
+// isTerminal reports whether f is a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// printTimeline writes a header, one line per entry and a summary
+func printTimeline(w io.Writer, path string, entries []transcript.TimelineEntry, color bool) {
+	paint := func(style, text string) string {
+		if !color || style == "" {
+			return text
+		}
+		return style + text + ansiReset
+	}
+
+	fmt.Fprintln(w, paint(ansiBold, path))
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No events")
+		return
+	}
+	var start, end time.Time
+	for _, e := range entries {
+		if e.Time.IsZero() {
+			continue
+		}
+		if start.IsZero() {
+			start = e.Time
+		}
+		end = e.Time
+	}
+	if !start.IsZero() {
+		fmt.Fprintf(w, "%s → %s (%s)\n", start.Local().Format("2006-01-02 15:04"), end.Local().Format("15:04"), end.Sub(start).Round(time.Second))
+	}
+	fmt.Fprintln(w)
+
+	var prompts, calls, failed, compactions int
+	for _, e := range entries {
+		clock := "        "
+		if !e.Time.IsZero() {
+			clock = e.Time.Local().Format("15:04:05")
+		}
+		indent := ""
+		if e.Sidechain {
+			indent = "  ↳ "
+		}
+
+		var mark, label, text string
+		var style string
+		switch e.Kind {
+		case transcript.TimelinePrompt:
+			prompts++
+			mark, label, text, style = "▶", "You", e.Text, ansiBold+ansiCyan
+		case transcript.TimelineReply:
+			mark, label, text = "●", "Claude", e.Text
+		case transcript.TimelineTool:
+			calls++
+			label, text = e.Tool, e.Target
+			switch e.Status {
+			case "completed":
+				mark, style = "✓", ansiGreen
+			case "error":
+				failed++
+				mark, style = "✗", ansiRed
+			case "interrupted":
+				mark, style = "⊘", ansiYellow
+			default:
+				mark, style = "◐", ansiYellow
+			}
+			if e.Duration > 0 {
+				text += "  " + paint(ansiDim, formatTimelineDuration(e.Duration))
+			}
+		case transcript.TimelineTodos:
+			mark, label, text, style = "☐", "Todos", e.Text, ansiMagenta
+		case transcript.TimelineInterrupt:
+			mark, label, text, style = "⊘", "Interrupted", "", ansiYellow
+		case transcript.TimelineCompaction:
+			compactions++
+			mark, label, text, style = "⇣", "Compacted", e.Text, ansiBlue
+		}
+		line := fmt.Sprintf("%s  %s%s", paint(ansiDim, clock), indent, paint(style, fmt.Sprintf("%s %-11s", mark, label)))
+		if text != "" {
+			line += " " + text
+		}
+		fmt.Fprintln(w, strings.TrimRight(line, " "))
+	}
+
+	fmt.Fprintf(w, "\nPrompts: %d · Tool calls: %d (%d failed) · Compactions: %d\n", prompts, calls, failed, compactions)
+}
+
+// formatTimelineDuration formats a tool call's duration, e.g. 120ms or 4.2s
+func formatTimelineDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
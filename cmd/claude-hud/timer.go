@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+)
+
+// defaultTimerDuration is one pomodoro
+const defaultTimerDuration = 25 * time.Minute
+
+// runTimerCommand handles `claude-hud timer <action>`, managing the focus
+// timer in the shared state file that the timer section displays
+func runTimerCommand(args []string) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "start":
+			return runTimerStart(args[1:])
+		case "stop":
+			return runTimerStop()
+		case "status":
+			return runTimerStatus()
+		}
+	}
+	fmt.Fprintln(os.Stderr, "Usage: claude-hud timer start [DURATION] [--label LABEL]")
+	fmt.Fprintln(os.Stderr, "       claude-hud timer stop|status")
+	return 2
+}
+
+// runTimerStart starts a timer, replacing any running one
+func runTimerStart(args []string) int {
+	// The duration comes first, e.g. `timer start 25m --label review`
+	duration := defaultTimerDuration
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		d, err := time.ParseDuration(args[0])
+		if err != nil || d <= 0 {
+			fmt.Fprintf(os.Stderr, "timer start: invalid duration %q (e.g. 25m, 1h30m)\n", args[0])
+			return 2
+		}
+		duration, args = d, args[1:]
+	}
+
+	fs := flag.NewFlagSet("timer start", flag.ContinueOnError)
+	label := fs.String("label", "", "What the time box is for, shown in the HUD")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	timer := session.NewTimer(duration, *label, time.Now())
+	if err := updateTimer(timer); err != nil {
+		fmt.Fprintf(os.Stderr, "timer start: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Timer set for %s, until %s\n", duration, timer.EndsAt.Format("15:04"))
+	return 0
+}
+
+// runTimerStop clears the timer
+func runTimerStop() int {
+	if err := updateTimer(nil); err != nil {
+		fmt.Fprintf(os.Stderr, "timer stop: %v\n", err)
+		return 1
+	}
+	fmt.Println("Timer stopped")
+	return 0
+}
+
+// runTimerStatus prints the time left on the timer
+func runTimerStatus() int {
+	store, err := session.DefaultStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "timer status: %v\n", err)
+		return 1
+	}
+	state, err := store.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "timer status: %v\n", err)
+		return 1
+	}
+
+	timer := state.Timer
+	now := time.Now()
+	switch {
+	case timer == nil:
+		fmt.Println("No timer running")
+	case timer.Expired(now):
+		fmt.Printf("Time's up (%s ago)\n", now.Sub(timer.EndsAt).Round(time.Second))
+	default:
+		fmt.Printf("%s left of %s\n", timer.Remaining(now).Round(time.Second), timer.Duration())
+	}
+	return 0
+}
+
+// updateTimer replaces the timer in the shared state file
+func updateTimer(timer *session.Timer) error {
+	store, err := session.DefaultStore()
+	if err != nil {
+		return err
+	}
+	return store.Update(func(state *session.State) error {
+		state.Timer = timer
+		return nil
+	})
+}
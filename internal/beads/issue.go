@@ -1,6 +1,7 @@
 package beads
 
 import (
+	"strings"
 	"time"
 )
 
@@ -56,6 +57,10 @@ func (s IssueStatus) Icon() string {
 	}
 }
 
+// DepParentChild is the dependency type linking a child issue (IssueID) to
+// its parent (DependsOnID), e.g. a task to its epic
+const DepParentChild = "parent-child"
+
 // Dependency represents a dependency relationship
 type Dependency struct {
 	IssueID     string    `json:"issue_id"`
@@ -85,6 +90,21 @@ func (i *Issue) IsEpic() bool {
 	return i.IssueType == TypeEpic
 }
 
+// ParentID returns the ID of the issue's parent, or "" for a top-level issue
+// A parent-child dependency names it; otherwise hierarchical IDs do, as
+// bd-a3f8.1 is a child of bd-a3f8
+func (i *Issue) ParentID() string {
+	for _, dep := range i.Dependencies {
+		if dep.Type == DepParentChild && dep.DependsOnID != "" && dep.DependsOnID != i.ID {
+			return dep.DependsOnID
+		}
+	}
+	if dot := strings.LastIndex(i.ID, "."); dot > 0 {
+		return i.ID[:dot]
+	}
+	return ""
+}
+
 // IsInProgress returns true if the issue is in progress
 func (i *Issue) IsInProgress() bool {
 	return i.Status == StatusInProgress
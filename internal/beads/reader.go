@@ -14,12 +14,15 @@ import (
 	"github.com/ll931217/claude-hud-enhanced/internal/watcher"
 )
 
-// Reader reads and caches beads issues from .beads/issues.jsonl
+// Reader reads and caches beads issues from .beads/issues.jsonl or, when
+// there is none, from the SQLite database newer beads versions keep
 type Reader struct {
 	mu             sync.RWMutex
 	repoPath       string
 	issues         map[string]*Issue
 	byStatus       map[IssueStatus][]*Issue
+	children       map[string][]*Issue // Keyed by parent ID
+	source         string              // File the issues were last loaded from
 	lastModTime    time.Time
 	lastCheck      time.Time
 	cacheTTL       time.Duration
@@ -36,6 +39,7 @@ func NewReader(repoPath string) *Reader {
 		repoPath:    repoPath,
 		issues:      make(map[string]*Issue),
 		byStatus:    make(map[IssueStatus][]*Issue),
+		children:    make(map[string][]*Issue),
 		cacheTTL:    500 * time.Millisecond, // Faster initial load, will be improved with file watching
 		watcher:     watcher.NewWatcher(),
 		watcherDone: make(chan struct{}),
@@ -47,14 +51,39 @@ func (r *Reader) GetIssuesPath() string {
 	return filepath.Join(r.repoPath, ".beads", "issues.jsonl")
 }
 
-// Exists checks if the beads directory exists
+// GetDatabasePath returns the path to the beads SQLite database, or "" when
+// there is none
+func (r *Reader) GetDatabasePath() string {
+	return DatabasePath(r.repoPath)
+}
+
+// Exists checks if the repository has beads issues in either format
 func (r *Reader) Exists() bool {
-	issuesPath := r.GetIssuesPath()
-	_, err := os.Stat(issuesPath)
-	return err == nil
+	return r.sourcePath() != ""
+}
+
+// Backend returns the format issues are read from: "jsonl", "sqlite", or ""
+// when the repository has no beads issues
+func (r *Reader) Backend() string {
+	switch path := r.sourcePath(); {
+	case path == "":
+		return ""
+	case path == r.GetIssuesPath():
+		return "jsonl"
+	}
+	return "sqlite"
 }
 
-// Load loads (or reloads) the issues from the JSONL file
+// sourcePath picks issues.jsonl, which older beads versions and bd's git
+// sync write, over the database, so both backends give the same issues
+func (r *Reader) sourcePath() string {
+	if _, err := os.Stat(r.GetIssuesPath()); err == nil {
+		return r.GetIssuesPath()
+	}
+	return r.GetDatabasePath()
+}
+
+// Load loads (or reloads) the issues from the JSONL file or the database
 func (r *Reader) Load(ctx context.Context) error {
 	return errors.SafeCall(func() error {
 		// Start watcher on first load if not already started
@@ -74,82 +103,112 @@ func (r *Reader) Load(ctx context.Context) error {
 		r.forceReload = false
 		r.mu.Unlock()
 
-		// Check if file exists
-		issuesPath := r.GetIssuesPath()
-		if _, err := os.Stat(issuesPath); os.IsNotExist(err) {
-			return fmt.Errorf("beads issues file not found: %s", issuesPath)
+		// Check if either backend exists
+		path := r.sourcePath()
+		if path == "" {
+			return fmt.Errorf("beads issues file not found: %s", r.GetIssuesPath())
 		}
+		jsonl := path == r.GetIssuesPath()
 
 		// Get file modification time
-		info, err := os.Stat(issuesPath)
-		if err != nil {
-			return fmt.Errorf("failed to stat issues file: %w", err)
+		var modTime time.Time
+		if jsonl {
+			info, err := os.Stat(path)
+			if err != nil {
+				return fmt.Errorf("failed to stat issues file: %w", err)
+			}
+			modTime = info.ModTime()
+		} else {
+			var err error
+			if modTime, err = databaseModTime(path); err != nil {
+				return fmt.Errorf("failed to stat beads database: %w", err)
+			}
 		}
 
 		// Check if file has been modified since last read
 		r.mu.RLock()
-		modified := info.ModTime().After(r.lastModTime)
+		modified := path != r.source || modTime.After(r.lastModTime)
 		r.mu.RUnlock()
 
 		if !modified && len(r.issues) > 0 {
 			// File hasn't changed and we have cached data
+			r.mu.Lock()
+			r.lastCheck = time.Now()
+			r.mu.Unlock()
 			return nil
 		}
 
-		// Open the file
-		file, err := os.Open(issuesPath)
+		var issues []*Issue
+		var err error
+		if jsonl {
+			issues, err = readJSONL(ctx, path)
+		} else {
+			issues, err = loadSQLite(ctx, path)
+		}
 		if err != nil {
-			return fmt.Errorf("failed to open issues file: %w", err)
+			return err
 		}
-		defer file.Close()
 
-		// Clear cache
+		// Replace the cache in one step so readers never see a partial load
 		r.mu.Lock()
-		r.issues = make(map[string]*Issue)
+		defer r.mu.Unlock()
+		r.issues = make(map[string]*Issue, len(issues))
 		r.byStatus = make(map[IssueStatus][]*Issue)
-		r.lastModTime = info.ModTime()
+		r.children = make(map[string][]*Issue)
+		for _, issue := range issues {
+			r.issues[issue.ID] = issue
+			r.byStatus[issue.Status] = append(r.byStatus[issue.Status], issue)
+		}
+		r.indexChildren()
+		r.source = path
+		r.lastModTime = modTime
 		r.lastCheck = time.Now()
-		r.mu.Unlock()
 
-		// Parse line by line
-		scanner := bufio.NewScanner(file)
-		lineNum := 0
+		return nil
+	})
+}
 
-		for scanner.Scan() {
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			default:
-			}
+// readJSONL parses issues.jsonl line by line, skipping invalid lines
+func readJSONL(ctx context.Context, path string) ([]*Issue, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open issues file: %w", err)
+	}
+	defer file.Close()
 
-			lineNum++
-			line := scanner.Bytes()
+	var issues []*Issue
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
 
-			if len(line) == 0 {
-				continue
-			}
+	for scanner.Scan() {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
 
-			// Parse the issue
-			var issue Issue
-			if err := json.Unmarshal(line, &issue); err != nil {
-				// Log error but continue parsing
-				errors.Warn("beads.reader", "line %d: %v", lineNum, err)
-				continue
-			}
+		lineNum++
+		line := scanner.Bytes()
 
-			// Add to cache
-			r.mu.Lock()
-			r.issues[issue.ID] = &issue
-			r.byStatus[issue.Status] = append(r.byStatus[issue.Status], &issue)
-			r.mu.Unlock()
+		if len(line) == 0 {
+			continue
 		}
 
-		if err := scanner.Err(); err != nil {
-			return fmt.Errorf("scanner error: %w", err)
+		// Parse the issue
+		var issue Issue
+		if err := json.Unmarshal(line, &issue); err != nil {
+			// Log error but continue parsing
+			errors.Warn("beads.reader", "line %d: %v", lineNum, err)
+			continue
 		}
+		issues = append(issues, &issue)
+	}
 
-		return nil
-	})
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scanner error: %w", err)
+	}
+
+	return issues, nil
 }
 
 // GetAll returns all loaded issues
@@ -272,6 +331,97 @@ func (r *Reader) GetCurrentIssue() *Issue {
 	return nil
 }
 
+// Progress is how many of an epic's child issues are closed
+type Progress struct {
+	Closed int
+	Total  int
+}
+
+// Percent returns the closed share of the children (0-100)
+func (p Progress) Percent() int {
+	if p.Total == 0 {
+		return 0
+	}
+	return p.Closed * 100 / p.Total
+}
+
+// indexChildren groups the loaded issues under their parents
+// Must be called with r.mu held
+func (r *Reader) indexChildren() {
+	for _, issue := range r.issues {
+		if parentID := issue.ParentID(); parentID != "" {
+			if _, ok := r.issues[parentID]; ok {
+				r.children[parentID] = append(r.children[parentID], issue)
+			}
+		}
+	}
+}
+
+// GetParent returns the parent of an issue, or nil for a top-level issue
+func (r *Reader) GetParent(id string) *Issue {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.parentOf(id)
+}
+
+// parentOf must be called with r.mu held
+func (r *Reader) parentOf(id string) *Issue {
+	issue := r.issues[id]
+	if issue == nil {
+		return nil
+	}
+	return r.issues[issue.ParentID()]
+}
+
+// GetChildren returns the direct children of an issue
+func (r *Reader) GetChildren(id string) []*Issue {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	children := r.children[id]
+	if children == nil {
+		return nil
+	}
+
+	// Return a copy
+	result := make([]*Issue, len(children))
+	copy(result, children)
+	return result
+}
+
+// GetEpic resolves the epic an issue belongs to: the issue itself when it is
+// an epic, else its nearest epic ancestor. Returns nil outside any epic
+func (r *Reader) GetEpic(id string) *Issue {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Guard against parent cycles in hand-edited files
+	seen := make(map[string]bool)
+	for issue := r.issues[id]; issue != nil && !seen[issue.ID]; issue = r.parentOf(issue.ID) {
+		if issue.IsEpic() {
+			return issue
+		}
+		seen[issue.ID] = true
+	}
+	return nil
+}
+
+// GetEpicProgress returns how many of an epic's direct children are closed
+func (r *Reader) GetEpicProgress(epicID string) Progress {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var progress Progress
+	for _, child := range r.children[epicID] {
+		progress.Total++
+		if child.IsClosed() {
+			progress.Closed++
+		}
+	}
+	return progress
+}
+
 // Refresh triggers a reload of the issues
 func (r *Reader) Refresh(ctx context.Context) error {
 	r.mu.Lock()
@@ -300,12 +450,20 @@ func (r *Reader) startWatcherOnce() {
 
 	r.watcherStarted = true
 
-	// Watch the issues file
+	// Watch the issues file and the database with its write-ahead log; bd
+	// rewrites them in several steps, so one reload per burst is enough
 	issuesPath := r.GetIssuesPath()
+	beadsDir := filepath.Dir(issuesPath)
+	r.watcher.SetDebounce(100 * time.Millisecond)
 	if err := r.watcher.AddWatch(issuesPath); err != nil {
 		errors.Warn("beads.reader", "failed to watch issues file: %v", err)
 		return
 	}
+	for _, pattern := range []string{"*.db", "*.db-wal"} {
+		if err := r.watcher.AddWatchGlob(filepath.Join(beadsDir, pattern)); err != nil {
+			errors.Warn("beads.reader", "failed to watch beads database: %v", err)
+		}
+	}
 
 	// Start watcher in background
 	ctx, cancel := context.WithCancel(context.Background())
@@ -325,7 +483,7 @@ func (r *Reader) startWatcherOnce() {
 			case <-ctx.Done():
 				return
 			case event := <-r.watcher.Events():
-				if event.Path == issuesPath {
+				if filepath.Dir(event.Path) == beadsDir {
 					// File changed - invalidate cache
 					r.mu.Lock()
 					r.forceReload = true
@@ -338,25 +496,27 @@ func (r *Reader) startWatcherOnce() {
 		}
 	}()
 
-	errors.Debug("beads.reader", "started watching %s", issuesPath)
+	errors.Debug("beads.reader", "started watching %s", beadsDir)
 }
 
 // Stop stops the file watcher
+// Safe to call when the watcher was never started
 func (r *Reader) Stop() {
 	r.mu.Lock()
-	defer r.mu.Unlock()
+	cancel := r.watcherCancel
+	r.watcherCancel = nil
+	r.mu.Unlock()
 
-	if r.watcherCancel != nil {
-		r.watcherCancel()
-		r.watcherCancel = nil
+	// Cancel first: the watcher's goroutines exit on context cancellation
+	if cancel != nil {
+		cancel()
 	}
-
 	if r.watcher != nil {
 		r.watcher.Stop()
 	}
 
-	// Wait for watcher goroutine to finish
-	if r.watcherDone != nil {
+	// Wait for the watcher goroutine (if any) without holding the lock it needs
+	if cancel != nil {
 		<-r.watcherDone
 	}
 }
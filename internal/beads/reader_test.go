@@ -234,3 +234,49 @@ func TestReader_ParseFromString(t *testing.T) {
 		t.Errorf("Priority = %v, want P1", issue.Priority.String())
 	}
 }
+
+func TestReader_EpicResolution(t *testing.T) {
+	tmpDir := t.TempDir()
+	beadsDir := filepath.Join(tmpDir, ".beads")
+	os.MkdirAll(beadsDir, 0755)
+
+	// bd-2 is linked by a parent-child dependency, bd-1.1 and bd-1.2 by their
+	// hierarchical IDs, and bd-3 is a subtask of bd-2
+	content := `{"id":"bd-1","title":"Auth rework","status":"open","priority":1,"issue_type":"epic"}
+{"id":"bd-1.1","title":"Login form","status":"closed","priority":2,"issue_type":"task"}
+{"id":"bd-1.2","title":"Logout","status":"open","priority":2,"issue_type":"task"}
+{"id":"bd-2","title":"Sessions","status":"in_progress","priority":2,"issue_type":"feature","dependencies":[{"issue_id":"bd-2","depends_on_id":"bd-1","type":"parent-child"}]}
+{"id":"bd-3","title":"Token refresh","status":"open","priority":2,"issue_type":"task","dependencies":[{"issue_id":"bd-3","depends_on_id":"bd-2","type":"parent-child"},{"issue_id":"bd-3","depends_on_id":"bd-9","type":"blocks"}]}
+{"id":"bd-4","title":"Unrelated","status":"open","priority":2,"issue_type":"task"}
+`
+	if err := os.WriteFile(filepath.Join(beadsDir, "issues.jsonl"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := NewReader(tmpDir)
+	if err := reader.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if parent := reader.GetParent("bd-3"); parent == nil || parent.ID != "bd-2" {
+		t.Errorf("GetParent(bd-3) = %v, want bd-2", parent)
+	}
+	if children := reader.GetChildren("bd-1"); len(children) != 3 {
+		t.Errorf("GetChildren(bd-1) = %d issues, want 3", len(children))
+	}
+
+	for id, want := range map[string]string{"bd-3": "bd-1", "bd-1.1": "bd-1", "bd-1": "bd-1", "bd-4": ""} {
+		got := ""
+		if epic := reader.GetEpic(id); epic != nil {
+			got = epic.ID
+		}
+		if got != want {
+			t.Errorf("GetEpic(%s) = %q, want %q", id, got, want)
+		}
+	}
+
+	progress := reader.GetEpicProgress("bd-1")
+	if progress.Closed != 1 || progress.Total != 3 || progress.Percent() != 33 {
+		t.Errorf("GetEpicProgress(bd-1) = %+v, want 1 of 3", progress)
+	}
+}
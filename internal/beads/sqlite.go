@@ -0,0 +1,143 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	_ "modernc.org/sqlite" // The driver internal/store registers as well
+)
+
+// DefaultDatabaseName is the database newer beads versions create in .beads
+const DefaultDatabaseName = "beads.db"
+
+// sqliteDriver is the database/sql driver registered by modernc.org/sqlite
+const sqliteDriver = "sqlite"
+
+// sqliteIssuesQuery selects the issues with their dependencies and labels
+// folded into JSON columns, so one query reads everything
+const sqliteIssuesQuery = `SELECT i.id, i.title, i.description, i.status, i.priority, i.issue_type,
+	i.created_at, i.updated_at,
+	(SELECT json_group_array(json_object('issue_id', d.issue_id, 'depends_on_id', d.depends_on_id, 'type', d.type))
+		FROM dependencies d WHERE d.issue_id = i.id) AS dependencies,
+	(SELECT json_group_array(l.label) FROM labels l WHERE l.issue_id = i.id) AS labels
+FROM issues i`
+
+// sqliteTimeLayouts are the timestamp formats found in beads databases: Go's
+// SQLite drivers write time.Time as the first, older versions RFC 3339
+var sqliteTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+}
+
+// DatabasePath returns the beads database in repoPath's .beads directory,
+// preferring beads.db over databases named after an issue prefix
+// Returns "" when there is none
+func DatabasePath(repoPath string) string {
+	dir := filepath.Join(repoPath, ".beads")
+	if _, err := os.Stat(filepath.Join(dir, DefaultDatabaseName)); err == nil {
+		return filepath.Join(dir, DefaultDatabaseName)
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.db"))
+	if len(matches) == 0 {
+		return ""
+	}
+	sort.Strings(matches)
+	return matches[0]
+}
+
+// databaseModTime returns when the database last changed; with write-ahead
+// logging, commits land in the -wal file before the database itself
+func databaseModTime(dbPath string) (time.Time, error) {
+	info, err := os.Stat(dbPath)
+	if err != nil {
+		return time.Time{}, err
+	}
+	modTime := info.ModTime()
+	if wal, err := os.Stat(dbPath + "-wal"); err == nil && wal.ModTime().After(modTime) {
+		modTime = wal.ModTime()
+	}
+	return modTime, nil
+}
+
+// loadSQLite reads the issues of a beads database through the SQLite driver
+// the session store uses, opened read-only so a running bd is not disturbed
+func loadSQLite(ctx context.Context, dbPath string) ([]*Issue, error) {
+	abs, err := filepath.Abs(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	dsn := url.URL{Scheme: "file", Path: abs, RawQuery: "mode=ro&_pragma=busy_timeout(1000)"}
+	db, err := sql.Open(sqliteDriver, dsn.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", dbPath, err)
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, sqliteIssuesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dbPath, err)
+	}
+	defer rows.Close()
+
+	var issues []*Issue
+	for rows.Next() {
+		var (
+			issue                                                Issue
+			description, status, issueType, dependencies, labels sql.NullString
+			priority                                             sql.NullInt64
+			createdAt, updatedAt                                 any
+		)
+		if err := rows.Scan(&issue.ID, &issue.Title, &description, &status, &priority, &issueType,
+			&createdAt, &updatedAt, &dependencies, &labels); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", dbPath, err)
+		}
+		issue.Description = description.String
+		issue.Status = IssueStatus(status.String)
+		issue.Priority = Priority(priority.Int64)
+		issue.IssueType = IssueType(issueType.String)
+		issue.CreatedAt = parseSQLiteTime(createdAt)
+		issue.UpdatedAt = parseSQLiteTime(updatedAt)
+		if dependencies.Valid {
+			_ = json.Unmarshal([]byte(dependencies.String), &issue.Dependencies)
+		}
+		if labels.Valid {
+			_ = json.Unmarshal([]byte(labels.String), &issue.Labels)
+		}
+		issues = append(issues, &issue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dbPath, err)
+	}
+	return issues, nil
+}
+
+// parseSQLiteTime parses a timestamp column, which the driver returns as a
+// time for DATETIME columns it can parse and as text otherwise; NULL or
+// unknown formats give the zero time
+func parseSQLiteTime(value any) time.Time {
+	var text string
+	switch v := value.(type) {
+	case time.Time:
+		return v
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		return time.Time{}
+	}
+	for _, layout := range sqliteTimeLayouts {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,131 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// createDatabase writes a beads database with the statements in script
+func createDatabase(t *testing.T, path, script string) {
+	t.Helper()
+	db, err := sql.Open(sqliteDriver, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(script); err != nil {
+		t.Fatalf("creating %s: %v", path, err)
+	}
+}
+
+const testSchema = `
+CREATE TABLE issues (id TEXT PRIMARY KEY, title TEXT NOT NULL, description TEXT, status TEXT, priority INTEGER,
+	issue_type TEXT, created_at DATETIME, updated_at DATETIME, closed_at DATETIME);
+CREATE TABLE dependencies (issue_id TEXT, depends_on_id TEXT, type TEXT, created_at DATETIME, created_by TEXT);
+CREATE TABLE labels (issue_id TEXT, label TEXT);
+`
+
+func TestReader_LoadSQLite(t *testing.T) {
+	tmpDir := t.TempDir()
+	beadsDir := filepath.Join(tmpDir, ".beads")
+	os.MkdirAll(beadsDir, 0755)
+	dbPath := filepath.Join(beadsDir, DefaultDatabaseName)
+	createDatabase(t, dbPath, testSchema+`
+INSERT INTO issues VALUES ('bd-1', 'Auth rework', NULL, 'open', 1, 'epic', '2026-01-07 12:00:00+00:00', '2026-01-07 12:00:00+00:00', NULL);
+INSERT INTO issues VALUES ('bd-2', 'Login form', 'Steps:
+- [x] markup', 'in_progress', 2, 'task', '2026-01-07 12:00:00+00:00', '2026-01-07 12:05:00.5+00:00', NULL);
+INSERT INTO issues VALUES ('bd-3', 'Logout', '', 'closed', 2, 'task', '2026-01-07T12:00:00Z', '2026-01-07T12:01:00Z', NULL);
+INSERT INTO dependencies VALUES ('bd-2', 'bd-1', 'parent-child', NULL, NULL);
+INSERT INTO dependencies VALUES ('bd-3', 'bd-1', 'parent-child', NULL, NULL);
+INSERT INTO labels VALUES ('bd-2', 'frontend');
+`)
+
+	reader := NewReader(tmpDir)
+	if !reader.Exists() || reader.Backend() != "sqlite" {
+		t.Fatalf("Exists() = %v, Backend() = %q; want the sqlite backend", reader.Exists(), reader.Backend())
+	}
+	if err := reader.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if count := reader.Count(); count != 3 {
+		t.Errorf("Count() = %d, want 3", count)
+	}
+	current := reader.GetCurrentIssue()
+	if current == nil || current.ID != "bd-2" {
+		t.Fatalf("GetCurrentIssue() = %v, want bd-2", current)
+	}
+	if current.Description != "Steps:\n- [x] markup" || len(current.Labels) != 1 || current.Labels[0] != "frontend" {
+		t.Errorf("bd-2 = %+v", current)
+	}
+	if want := time.Date(2026, 1, 7, 12, 5, 0, 5e8, time.UTC); !current.UpdatedAt.Equal(want) {
+		t.Errorf("UpdatedAt = %v, want %v", current.UpdatedAt, want)
+	}
+	if epic := reader.GetEpic("bd-2"); epic == nil || epic.ID != "bd-1" {
+		t.Errorf("GetEpic(bd-2) = %v, want bd-1", epic)
+	}
+	if progress := reader.GetEpicProgress("bd-1"); progress.Closed != 1 || progress.Total != 2 {
+		t.Errorf("GetEpicProgress(bd-1) = %+v, want 1 of 2", progress)
+	}
+
+	// issues.jsonl takes precedence once it exists
+	content := `{"id":"test-1","title":"From JSONL","status":"open","priority":2,"issue_type":"task"}` + "\n"
+	if err := os.WriteFile(filepath.Join(beadsDir, "issues.jsonl"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := reader.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if reader.Backend() != "jsonl" || reader.Count() != 1 {
+		t.Errorf("after adding issues.jsonl: Backend() = %q, Count() = %d", reader.Backend(), reader.Count())
+	}
+}
+
+func TestDatabasePath(t *testing.T) {
+	tmpDir := t.TempDir()
+	beadsDir := filepath.Join(tmpDir, ".beads")
+	os.MkdirAll(beadsDir, 0755)
+
+	if path := DatabasePath(tmpDir); path != "" {
+		t.Errorf("DatabasePath() without a database = %q", path)
+	}
+	os.WriteFile(filepath.Join(beadsDir, "proj.db"), nil, 0644)
+	if path := DatabasePath(tmpDir); filepath.Base(path) != "proj.db" {
+		t.Errorf("DatabasePath() = %q, want proj.db", path)
+	}
+	os.WriteFile(filepath.Join(beadsDir, DefaultDatabaseName), nil, 0644)
+	if path := DatabasePath(tmpDir); filepath.Base(path) != DefaultDatabaseName {
+		t.Errorf("DatabasePath() = %q, want %s", path, DefaultDatabaseName)
+	}
+}
+
+func TestLoadSQLite_Empty(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), DefaultDatabaseName)
+	createDatabase(t, dbPath, testSchema)
+	issues, err := loadSQLite(context.Background(), dbPath)
+	if err != nil || len(issues) != 0 {
+		t.Errorf("loadSQLite(empty) = %v, %v", issues, err)
+	}
+}
+
+func TestLoadSQLite_NotBeads(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "other.db")
+	createDatabase(t, dbPath, "CREATE TABLE notes (body TEXT);")
+	if _, err := loadSQLite(context.Background(), dbPath); err == nil || !strings.Contains(err.Error(), "no such table") {
+		t.Errorf("loadSQLite() error = %v, want the missing table", err)
+	}
+
+	// Opened read-only, a database that is gone is not created again
+	missing := filepath.Join(t.TempDir(), "gone.db")
+	if _, err := loadSQLite(context.Background(), missing); err == nil {
+		t.Error("loadSQLite() of a missing database succeeded")
+	}
+	if _, err := os.Stat(missing); err == nil {
+		t.Error("loadSQLite() created the missing database")
+	}
+}
@@ -0,0 +1,230 @@
+// Package bugreport bundles what a bug report needs into a gzipped tarball:
+// version and terminal details, the config with its secrets redacted, the
+// recorded panics and logs and, optionally, an anonymized transcript sample
+package bugreport
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Redacted replaces secret values
+const Redacted = "REDACTED"
+
+// File is a file in the bundle
+type File struct {
+	Name string
+	Data []byte
+}
+
+// WriteTarball writes files to w as a gzipped tarball, inside dir
+func WriteTarball(w io.Writer, dir string, files []File, modTime time.Time) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, f := range files {
+		header := &tar.Header{
+			Name:    dir + "/" + f.Name,
+			Mode:    0644,
+			Size:    int64(len(f.Data)),
+			ModTime: modTime,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(f.Data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// secretWords mark config keys whose values are secrets, e.g. jira_token or
+// webhook_url
+var secretWords = []string{"token", "secret", "password", "passwd", "apikey", "api_key", "webhook", "auth", "credential"}
+
+// secretKey reports whether a config key names a secret
+func secretKey(key string) bool {
+	key = strings.ToLower(key)
+	if key == "key" || strings.HasSuffix(key, "_key") {
+		return true
+	}
+	for _, word := range secretWords {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactConfig returns a YAML config with the values of secret-looking keys
+// replaced, however deeply they are nested. Comments are kept
+func RedactConfig(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind == 0 {
+		return data, nil // Empty file
+	}
+	redactNode(&doc)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func redactNode(n *yaml.Node) {
+	if n.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			if value.Kind == yaml.ScalarNode && value.Value != "" && secretKey(key.Value) {
+				value.Value = Redacted
+				value.Tag = "!!str"
+				value.Style = 0
+				continue
+			}
+			redactNode(value)
+		}
+		return
+	}
+	for _, child := range n.Content {
+		redactNode(child)
+	}
+}
+
+// structuralFields are transcript fields kept verbatim when anonymizing:
+// they say what happened without saying what was worked on
+var structuralFields = map[string]bool{
+	"type":        true,
+	"subtype":     true,
+	"role":        true,
+	"model":       true,
+	"name":        true, // Tool names
+	"timestamp":   true,
+	"stop_reason": true,
+	"level":       true,
+	"version":     true,
+	"userType":    true,
+}
+
+// AnonymizeTranscript returns the last n lines of a JSONL transcript with
+// every string replaced by its length, except for structural fields such as
+// event types, model and tool names and timestamps. Numbers, booleans and the
+// shape of each event are kept, which is what most parsing bugs come down to
+// Lines that are not JSON are replaced entirely
+func AnonymizeTranscript(r io.Reader, n int) ([]byte, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	// Keep the last n lines, however long
+	tail := make([][]byte, 0, n)
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			if len(tail) == n {
+				tail = append(tail[:0], tail[1:]...)
+			}
+			tail = append(tail, line)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, line := range tail {
+		var v interface{}
+		if err := json.Unmarshal(line, &v); err != nil {
+			fmt.Fprintf(&buf, "%q\n", redactedString(string(line)))
+			continue
+		}
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(anonymize(v)); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func anonymize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if s, ok := value.(string); ok && structuralFields[key] {
+				v[key] = s
+				continue
+			}
+			v[key] = anonymize(value)
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = anonymize(v[i])
+		}
+		return v
+	case string:
+		return redactedString(v)
+	}
+	return v
+}
+
+func redactedString(s string) string {
+	if s == "" {
+		return ""
+	}
+	return fmt.Sprintf("<%d chars>", len(s))
+}
+
+// TailFile returns up to the last max bytes of a file, starting at a line
+func TailFile(path string, max int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := info.Size() - max
+	if offset <= 0 {
+		return io.ReadAll(f)
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		data = data[i+1:]
+	}
+	return data, nil
+}
@@ -0,0 +1,123 @@
+package bugreport
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRedactConfig(t *testing.T) {
+	input := `# Issue trackers
+issues:
+  provider: jira
+  jira_token: abc123
+  jira_url: https://acme.atlassian.net
+notifications:
+  webhook_url: https://hooks.example.com/secret
+  api_key: xyz
+layout:
+  separator: " | "
+`
+	out, err := RedactConfig([]byte(input))
+	if err != nil {
+		t.Fatalf("RedactConfig: %v", err)
+	}
+	got := string(out)
+	for _, secret := range []string{"abc123", "hooks.example.com", "xyz"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("secret %q not redacted:\n%s", secret, got)
+		}
+	}
+	for _, kept := range []string{"# Issue trackers", "provider: jira", "https://acme.atlassian.net", `separator: " | "`} {
+		if !strings.Contains(got, kept) {
+			t.Errorf("expected %q to be kept:\n%s", kept, got)
+		}
+	}
+
+	if _, err := RedactConfig([]byte("not: [valid")); err == nil {
+		t.Error("expected an error for invalid YAML")
+	}
+}
+
+func TestAnonymizeTranscript(t *testing.T) {
+	input := `{"type":"user","message":{"role":"user","content":"first"}}
+{"type":"user","message":{"role":"user","content":"fix the login page"}}
+not json at all
+{"type":"assistant","timestamp":"2026-01-02T03:04:05Z","message":{"model":"claude-sonnet-4","content":[{"type":"tool_use","name":"Read","input":{"file_path":"/home/me/secret.go"}}],"usage":{"input_tokens":12}}}
+`
+	out, err := AnonymizeTranscript(strings.NewReader(input), 3)
+	if err != nil {
+		t.Fatalf("AnonymizeTranscript: %v", err)
+	}
+	got := string(out)
+	lines := strings.Split(strings.TrimSpace(got), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected the last 3 lines, got %d:\n%s", len(lines), got)
+	}
+	for _, secret := range []string{"first", "login", "/home/me", "not json"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("content %q not anonymized:\n%s", secret, got)
+		}
+	}
+	for _, kept := range []string{`"type":"assistant"`, `"role":"user"`, `"name":"Read"`, `"model":"claude-sonnet-4"`, `"input_tokens":12`, `"content":"<18 chars>"`} {
+		if !strings.Contains(got, kept) {
+			t.Errorf("expected %s to be kept:\n%s", kept, got)
+		}
+	}
+
+	if out, _ := AnonymizeTranscript(strings.NewReader(input), 0); out != nil {
+		t.Errorf("expected no sample for 0 lines, got %q", out)
+	}
+}
+
+func TestWriteTarball(t *testing.T) {
+	files := []File{
+		{Name: "version.txt", Data: []byte("dev\n")},
+		{Name: "config.yaml", Data: []byte("debug: true\n")},
+	}
+	var buf bytes.Buffer
+	if err := WriteTarball(&buf, "report", files, time.Now()); err != nil {
+		t.Fatalf("WriteTarball: %v", err)
+	}
+
+	gz, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	tr := tar.NewReader(gz)
+	for _, want := range files {
+		header, err := tr.Next()
+		if err != nil {
+			t.Fatalf("tar: %v", err)
+		}
+		if header.Name != "report/"+want.Name {
+			t.Errorf("expected %s, got %s", "report/"+want.Name, header.Name)
+		}
+		data, _ := io.ReadAll(tr)
+		if !bytes.Equal(data, want.Data) {
+			t.Errorf("%s: expected %q, got %q", want.Name, want.Data, data)
+		}
+	}
+	if _, err := tr.Next(); err != io.EOF {
+		t.Errorf("expected end of archive, got %v", err)
+	}
+}
+
+func TestTailFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "debug.log")
+	if err := os.WriteFile(path, []byte("one\ntwo\nthree\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := TailFile(path, 100); string(data) != "one\ntwo\nthree\n" {
+		t.Errorf("expected the whole file, got %q", data)
+	}
+	if data, _ := TailFile(path, 8); string(data) != "three\n" {
+		t.Errorf("expected the last whole line, got %q", data)
+	}
+}
@@ -0,0 +1,158 @@
+package ci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/filecache"
+	"github.com/ll931217/claude-hud-enhanced/internal/refresh"
+)
+
+// DefaultInterval is how often a pending commit is asked about again
+const DefaultInterval = time.Minute
+
+// settledFactor stretches the interval once all runs finished; only re-runs
+// change a settled commit, and a new commit asks straight away
+const settledFactor = 10
+
+// cachedResult is the outcome of the last request for one commit
+type cachedResult struct {
+	Result    Result    `json:"result"`
+	Found     bool      `json:"found"`
+	Error     string    `json:"error,omitempty"`
+	ETag      string    `json:"etag,omitempty"`
+	CheckedAt time.Time `json:"checked_at"` // Last attempt, successful or not
+}
+
+// cacheFile is the persisted form of the client's state
+type cacheFile struct {
+	Results     map[string]cachedResult `json:"results"`
+	LimitedTill map[string]time.Time    `json:"rate_limited_until,omitempty"` // Keyed by provider
+}
+
+// Client fetches and caches commit statuses
+// Requests run in the background, so a slow API never holds up a render.
+// Failures are cached like results, conditional requests keep unchanged
+// results from counting against GitHub's rate limit, and no requests are
+// made while the limit is exhausted. With a cache path, all of this is
+// shared across processes
+type Client struct {
+	CachePath  string        // Optional file caching the last results
+	Timeout    time.Duration // Bounds each request
+	HTTPClient *http.Client
+
+	mu      sync.Mutex
+	cache   cacheFile
+	loaded  bool
+	fetches refresh.Group
+}
+
+// NewClient creates a CI client persisted at cachePath ("" keeps results in memory)
+func NewClient(cachePath string) *Client {
+	return &Client{
+		CachePath:  cachePath,
+		Timeout:    10 * time.Second,
+		HTTPClient: &http.Client{},
+		cache: cacheFile{
+			Results:     make(map[string]cachedResult),
+			LimitedTill: make(map[string]time.Time),
+		},
+	}
+}
+
+// Status returns the status of commit, asking provider in the background
+// once the cached result is older than interval (ten times that once all
+// runs finished). It waits for the request until ctx is done; until then,
+// and after a failure, the previous result, if any, is returned, after a
+// failure with the error
+func (c *Client) Status(ctx context.Context, provider Provider, commit Commit, interval time.Duration) (Result, bool, error) {
+	key := provider.Name() + "\x00" + commit.Owner + "/" + commit.Repo + "\x00" + commit.SHA
+	c.mu.Lock()
+	c.load()
+	last, ok := c.cache.Results[key]
+	until := c.cache.LimitedTill[provider.Name()]
+	c.mu.Unlock()
+	if last.Found && last.Result.State != StatePending && last.Result.State != StateUnknown {
+		interval *= settledFactor
+	}
+
+	now := time.Now()
+	due := !ok || now.Sub(last.CheckedAt) >= interval || now.Before(last.CheckedAt)
+	if due && now.Before(until) {
+		return last.Result, last.Found, fmt.Errorf("rate limited until %s", until.Local().Format("15:04"))
+	}
+	if due && c.fetches.Do(ctx, key, func() { c.fetch(provider, commit, key, last.ETag) }) {
+		c.mu.Lock()
+		last = c.cache.Results[key]
+		c.mu.Unlock()
+	}
+
+	if last.Error != "" {
+		return last.Result, last.Found, errors.New(last.Error)
+	}
+	return last.Result, last.Found, nil
+}
+
+// fetch asks provider for the status of commit and caches the outcome
+func (c *Client) fetch(provider Provider, commit Commit, key, etag string) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+	resp, err := provider.Fetch(ctx, c.HTTPClient, commit, etag)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	last := c.cache.Results[key]
+	last.CheckedAt = now
+	last.Error = ""
+	switch {
+	case err != nil:
+		last.Error = err.Error()
+	case resp.NotModified:
+	default:
+		last.Result, last.Found, last.ETag = resp.Result, true, resp.ETag
+	}
+	if resp.RateLimit.Exhausted() {
+		c.cache.LimitedTill[provider.Name()] = resp.RateLimit.Reset
+	}
+	c.cache.Results[key] = last
+	c.prune(now)
+	c.save()
+}
+
+// Wait blocks until requests still running finish
+func (c *Client) Wait() {
+	c.fetches.Wait()
+}
+
+// prune drops results nobody asked about for a day, e.g. for old commits
+func (c *Client) prune(now time.Time) {
+	filecache.Prune(c.cache.Results, now, 24*time.Hour, func(r cachedResult) time.Time { return r.CheckedAt })
+	for name, until := range c.cache.LimitedTill {
+		if now.After(until) {
+			delete(c.cache.LimitedTill, name)
+		}
+	}
+}
+
+// load reads the persisted state once; a missing or corrupt file starts empty
+func (c *Client) load() {
+	if c.loaded {
+		return
+	}
+	c.loaded = true
+	var cache cacheFile
+	if filecache.Load(c.CachePath, &cache) {
+		maps.Copy(c.cache.Results, cache.Results)
+		maps.Copy(c.cache.LimitedTill, cache.LimitedTill)
+	}
+}
+
+func (c *Client) save() {
+	_ = filecache.Save(c.CachePath, c.cache)
+}
@@ -0,0 +1,277 @@
+// Package ci reads the CI status of a commit from GitHub Actions or from a
+// commit status API (GitHub, Gitea and Forgejo share one)
+package ci
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Aggregate states of a commit's checks
+const (
+	StateUnknown = ""        // Nothing has run for the commit, e.g. it is not pushed
+	StatePending = "pending" // Something is queued or running
+	StateSuccess = "success"
+	StateFailure = "failure"
+)
+
+// Commit identifies the commit whose status is wanted
+type Commit struct {
+	Owner string
+	Repo  string
+	SHA   string
+}
+
+// Result is the aggregate status of a commit's runs or statuses
+type Result struct {
+	State   string `json:"state"`
+	Total   int    `json:"total"`
+	Failed  int    `json:"failed"`
+	Pending int    `json:"pending"`
+}
+
+// RateLimit is what the API reported about the caller's remaining requests
+type RateLimit struct {
+	Remaining int       // -1 when the response carried no limit
+	Reset     time.Time // When the limit resets
+}
+
+// Exhausted reports whether no requests are left until Reset
+func (r RateLimit) Exhausted() bool {
+	return r.Remaining == 0 && !r.Reset.IsZero()
+}
+
+// Response is the outcome of one request
+// NotModified is set when the ETag matched, in which case Result is empty
+type Response struct {
+	Result      Result
+	ETag        string
+	NotModified bool
+	RateLimit   RateLimit
+}
+
+// Provider fetches the status of a commit
+type Provider interface {
+	Name() string
+	// Fetch asks for commit's status, conditionally on etag when set
+	// The response's rate limit is filled in even when an error is returned
+	Fetch(ctx context.Context, client *http.Client, commit Commit, etag string) (Response, error)
+}
+
+// DefaultAPI is GitHub's REST API
+const DefaultAPI = "https://api.github.com"
+
+// GitHubActions aggregates the latest workflow run of each workflow triggered
+// for a commit
+type GitHubActions struct {
+	API   string // Defaults to DefaultAPI
+	Token string // Optional; raises the rate limit from 60 to 5000 requests an hour
+}
+
+// Name returns the provider name
+func (g GitHubActions) Name() string {
+	return "github"
+}
+
+// Fetch implements Provider
+func (g GitHubActions) Fetch(ctx context.Context, client *http.Client, commit Commit, etag string) (Response, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/actions/runs?head_sha=%s&per_page=100",
+		apiBase(g.API), url.PathEscape(commit.Owner), url.PathEscape(commit.Repo), url.QueryEscape(commit.SHA))
+	var payload struct {
+		WorkflowRuns []struct {
+			WorkflowID int64     `json:"workflow_id"`
+			Status     string    `json:"status"`
+			Conclusion string    `json:"conclusion"`
+			CreatedAt  time.Time `json:"created_at"`
+		} `json:"workflow_runs"`
+	}
+	resp, err := get(ctx, client, endpoint, g.Token, etag, &payload)
+	if err != nil || resp.NotModified {
+		return resp, err
+	}
+
+	// Re-runs create new runs of the same workflow; only the latest counts
+	type run struct {
+		status, conclusion string
+		createdAt          time.Time
+	}
+	latest := make(map[int64]run)
+	for _, r := range payload.WorkflowRuns {
+		if prev, ok := latest[r.WorkflowID]; !ok || r.CreatedAt.After(prev.createdAt) {
+			latest[r.WorkflowID] = run{r.Status, r.Conclusion, r.CreatedAt}
+		}
+	}
+	for _, r := range latest {
+		switch {
+		case r.status != "completed":
+			resp.Result.Pending++
+		case r.conclusion == "failure" || r.conclusion == "timed_out" || r.conclusion == "startup_failure" || r.conclusion == "action_required":
+			resp.Result.Failed++
+		}
+	}
+	resp.Result.Total = len(latest)
+	resp.Result.State = aggregate(resp.Result)
+	return resp, nil
+}
+
+// CommitStatus reads the combined commit status API, which GitHub, Gitea and
+// Forgejo serve at /repos/{owner}/{repo}/commits/{sha}/status
+type CommitStatus struct {
+	API   string // Defaults to DefaultAPI; e.g. https://gitea.example.com/api/v1
+	Token string
+}
+
+// Name returns the provider name
+func (c CommitStatus) Name() string {
+	return "status"
+}
+
+// Fetch implements Provider
+func (c CommitStatus) Fetch(ctx context.Context, client *http.Client, commit Commit, etag string) (Response, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/commits/%s/status",
+		apiBase(c.API), url.PathEscape(commit.Owner), url.PathEscape(commit.Repo), url.PathEscape(commit.SHA))
+	var payload struct {
+		Statuses []struct {
+			State string `json:"state"`
+		} `json:"statuses"`
+	}
+	resp, err := get(ctx, client, endpoint, c.Token, etag, &payload)
+	if err != nil || resp.NotModified {
+		return resp, err
+	}
+	for _, s := range payload.Statuses {
+		switch s.State {
+		case "pending":
+			resp.Result.Pending++
+		case "failure", "error":
+			resp.Result.Failed++
+		}
+	}
+	resp.Result.Total = len(payload.Statuses)
+	resp.Result.State = aggregate(resp.Result)
+	return resp, nil
+}
+
+// aggregate folds counts into one state: any failure fails the commit, then
+// anything still running keeps it pending
+func aggregate(r Result) string {
+	switch {
+	case r.Total == 0:
+		return StateUnknown
+	case r.Failed > 0:
+		return StateFailure
+	case r.Pending > 0:
+		return StatePending
+	}
+	return StateSuccess
+}
+
+func apiBase(api string) string {
+	if api == "" {
+		return DefaultAPI
+	}
+	return strings.TrimRight(api, "/")
+}
+
+// get decodes a JSON response into v, sending etag and token when set
+func get(ctx context.Context, client *http.Client, rawURL, token, etag string, v interface{}) (Response, error) {
+	resp := Response{RateLimit: RateLimit{Remaining: -1}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return resp, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	httpResp, err := client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return resp, fmt.Errorf("request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	resp.RateLimit = parseRateLimit(httpResp.Header)
+	resp.ETag = httpResp.Header.Get("ETag")
+	switch {
+	case httpResp.StatusCode == http.StatusNotModified:
+		resp.NotModified = true
+		return resp, nil
+	case httpResp.StatusCode == http.StatusNotFound:
+		return resp, errors.New("repository not found (private repositories need a token)")
+	case httpResp.StatusCode != http.StatusOK:
+		if resp.RateLimit.Exhausted() {
+			return resp, fmt.Errorf("rate limited until %s", resp.RateLimit.Reset.Local().Format("15:04"))
+		}
+		return resp, fmt.Errorf("CI API returned status %d", httpResp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(httpResp.Body, 4<<20)).Decode(v); err != nil {
+		return resp, fmt.Errorf("failed to parse CI response: %w", err)
+	}
+	return resp, nil
+}
+
+// parseRateLimit reads GitHub's X-RateLimit headers; Gitea sends none
+func parseRateLimit(header http.Header) RateLimit {
+	limit := RateLimit{Remaining: -1}
+	if remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining")); err == nil {
+		limit.Remaining = remaining
+	}
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		limit.Reset = time.Unix(reset, 0)
+	}
+	return limit
+}
+
+// ParseRemote splits a git remote URL into host, owner and repository, e.g.
+// git@github.com:owner/repo.git or https://github.com/owner/repo
+// Returns false for remotes that are not on a forge, such as local paths
+func ParseRemote(remote string) (host, owner, repo string, ok bool) {
+	remote = strings.TrimSpace(remote)
+	var path string
+	if u, err := url.Parse(remote); err == nil && u.Scheme != "" && u.Host != "" {
+		host, path = u.Hostname(), u.Path
+	} else if at := strings.Index(remote, "@"); at >= 0 && strings.Contains(remote[at:], ":") {
+		// scp-like syntax: user@host:owner/repo
+		hostPath := remote[at+1:]
+		colon := strings.Index(hostPath, ":")
+		host, path = hostPath[:colon], hostPath[colon+1:]
+	} else {
+		return "", "", "", false
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimSuffix(path, ".git"), "/"), "/")
+	if len(parts) < 2 || parts[len(parts)-2] == "" || parts[len(parts)-1] == "" {
+		return "", "", "", false
+	}
+	return host, parts[len(parts)-2], parts[len(parts)-1], true
+}
+
+// RepoURL returns the web page of a repository found by ParseRemote
+func RepoURL(host, owner, repo string) string {
+	return "https://" + host + "/" + owner + "/" + repo
+}
+
+// APIForHost guesses the REST API of a forge: GitHub's for github.com and
+// GitHub Enterprise's /api/v3 for other hosts
+func APIForHost(host string) string {
+	if host == "" || host == "github.com" {
+		return DefaultAPI
+	}
+	return "https://" + host + "/api/v3"
+}
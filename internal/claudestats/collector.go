@@ -47,13 +47,30 @@ var coreTools = map[string]bool{
 	"teamdelete":           true,
 }
 
+// SourceCounts splits a count of definitions by where they are defined
+type SourceCounts struct {
+	User    int // Under ~/.claude
+	Project int // Under .claude in the project directory
+	Plugin  int // Shipped by installed plugins
+}
+
+// Total returns the count across all sources
+func (s SourceCounts) Total() int {
+	return s.User + s.Project + s.Plugin
+}
+
 // StatsCache holds cached statistics
 type StatsCache struct {
-	CoreCount    int
-	MCPCount     int
-	PluginsCount int
-	HooksCount   int
-	Timestamp    time.Time
+	CoreCount     int
+	MCPCount      int // Configured MCP servers
+	MCPToolsCount int // Tools offered by those servers, from the inventory cache
+	Skills        SourceCounts
+	Commands      SourceCounts // Custom slash commands
+	Agents        SourceCounts // Subagent definitions
+	OutputStyles  SourceCounts
+	PluginsCount  int
+	HooksCount    int
+	Timestamp     time.Time
 }
 
 // Collector gathers Claude capability statistics
@@ -62,6 +79,8 @@ type Collector struct {
 	mcpClient    *mcp.Client
 	settingsPath string
 	pluginsDir   string
+	claudeDir    string // ~/.claude, holding user skills, commands, agents and output styles
+	projectDir   string // Project whose .claude directory is counted; "" uses the working directory
 	cache        *StatsCache
 	lastUpdate   time.Time
 	cacheTTL     time.Duration
@@ -69,23 +88,37 @@ type Collector struct {
 
 // NewCollector creates a new statistics collector
 func NewCollector() *Collector {
+	return NewCollectorWithClient(mcp.NewClient())
+}
+
+// NewCollectorWithClient creates a statistics collector that uses a shared MCP client
+func NewCollectorWithClient(client *mcp.Client) *Collector {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		errors.Warn("claudestats", "failed to get home directory: %v", err)
 		return &Collector{
-			mcpClient: mcp.NewClient(),
+			mcpClient: client,
 			cacheTTL:  5 * time.Second,
 		}
 	}
 
 	return &Collector{
-		mcpClient:    mcp.NewClient(),
+		mcpClient:    client,
 		settingsPath: filepath.Join(homeDir, ".claude", "settings.json"),
 		pluginsDir:   filepath.Join(homeDir, ".claude", "plugins"),
+		claudeDir:    filepath.Join(homeDir, ".claude"),
 		cacheTTL:     5 * time.Second,
 	}
 }
 
+// SetProjectDir sets the project whose skills, commands, agents and output
+// styles are counted alongside the user's
+func (c *Collector) SetProjectDir(dir string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.projectDir = dir
+}
+
 // Collect gathers all statistics with caching
 func (c *Collector) Collect(ctx context.Context) *StatsCache {
 	c.mu.Lock()
@@ -104,6 +137,13 @@ func (c *Collector) Collect(ctx context.Context) *StatsCache {
 		HooksCount:   c.collectHooksCount(ctx),
 		Timestamp:    time.Now(),
 	}
+	stats.MCPToolsCount = c.collectMCPToolsCount()
+
+	pluginPaths := c.pluginInstallPaths()
+	stats.Skills = c.collectDefinitions("skills", pluginPaths, countSkills)
+	stats.Commands = c.collectDefinitions("commands", pluginPaths, countCommands)
+	stats.Agents = c.collectDefinitions("agents", pluginPaths, countMarkdown)
+	stats.OutputStyles = c.collectDefinitions("output-styles", pluginPaths, countMarkdown)
 
 	c.cache = stats
 	c.lastUpdate = time.Now()
@@ -115,13 +155,96 @@ func (c *Collector) collectMCPCount(ctx context.Context) int {
 	if c.mcpClient == nil {
 		return 0
 	}
+	// Servers from valid config files are still counted when another fails to parse
 	if err := c.mcpClient.DetectServers(ctx); err != nil {
 		errors.Debug("claudestats", "failed to detect MCP servers: %v", err)
-		return 0
 	}
 	return c.mcpClient.ServerCount()
 }
 
+// collectMCPToolsCount returns the number of tools offered by the detected
+// MCP servers; it relies on collectMCPCount having detected them
+func (c *Collector) collectMCPToolsCount() int {
+	if c.mcpClient == nil {
+		return 0
+	}
+	return c.mcpClient.ToolCount()
+}
+
+// collectDefinitions counts the definitions in subdir of ~/.claude, of the
+// project's .claude directory and of each plugin install directory
+func (c *Collector) collectDefinitions(subdir string, pluginPaths []string, count func(dir string) int) SourceCounts {
+	var counts SourceCounts
+	if c.claudeDir != "" {
+		counts.User = count(filepath.Join(c.claudeDir, subdir))
+	}
+
+	projectDir := c.projectDir
+	if projectDir == "" {
+		projectDir, _ = os.Getwd()
+	}
+	// Running from the home directory would count the user's definitions twice
+	if projectClaudeDir := filepath.Join(projectDir, ".claude"); projectDir != "" && projectClaudeDir != c.claudeDir {
+		counts.Project = count(filepath.Join(projectClaudeDir, subdir))
+	}
+
+	for _, installPath := range pluginPaths {
+		counts.Plugin += count(filepath.Join(installPath, subdir))
+	}
+	return counts
+}
+
+// countSkills counts the skill directories directly under dir
+// A skill is a directory holding a SKILL.md
+func countSkills(dir string) int {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, entry.Name(), "SKILL.md")); err == nil {
+			count++
+		}
+	}
+	return count
+}
+
+// countMarkdown counts the .md files directly under dir, as used for agents
+// and output styles
+func countMarkdown(dir string) int {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".md") {
+			count++
+		}
+	}
+	return count
+}
+
+// countCommands counts the .md files under dir, including subdirectories,
+// which namespace commands (commands/frontend/lint.md is /frontend:lint)
+func countCommands(dir string) int {
+	count := 0
+	_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() && strings.HasSuffix(d.Name(), ".md") {
+			count++
+		}
+		return nil
+	})
+	return count
+}
+
 // collectPluginsCount returns enabled plugins count
 func (c *Collector) collectPluginsCount(ctx context.Context) int {
 	data, err := os.ReadFile(c.settingsPath)
@@ -180,15 +303,24 @@ func (c *Collector) collectSettingsHooksCount() int {
 
 // collectPluginHooksCount counts hooks from installed plugin.json files
 func (c *Collector) collectPluginHooksCount() int {
+	count := 0
+	for _, installPath := range c.pluginInstallPaths() {
+		count += c.countPluginHooks(installPath)
+	}
+	return count
+}
+
+// pluginInstallPaths returns the install directories of installed plugins
+func (c *Collector) pluginInstallPaths() []string {
 	if c.pluginsDir == "" {
-		return 0
+		return nil
 	}
 
 	installedPath := filepath.Join(c.pluginsDir, "installed_plugins.json")
 	data, err := os.ReadFile(installedPath)
 	if err != nil {
 		errors.Debug("claudestats", "failed to read installed_plugins.json: %v", err)
-		return 0
+		return nil
 	}
 
 	var installed struct {
@@ -199,10 +331,10 @@ func (c *Collector) collectPluginHooksCount() int {
 
 	if err := json.Unmarshal(data, &installed); err != nil {
 		errors.Debug("claudestats", "failed to parse installed_plugins.json: %v", err)
-		return 0
+		return nil
 	}
 
-	count := 0
+	var paths []string
 	seen := make(map[string]bool)
 	for _, installs := range installed.Plugins {
 		for _, inst := range installs {
@@ -210,10 +342,10 @@ func (c *Collector) collectPluginHooksCount() int {
 				continue
 			}
 			seen[inst.InstallPath] = true
-			count += c.countPluginHooks(inst.InstallPath)
+			paths = append(paths, inst.InstallPath)
 		}
 	}
-	return count
+	return paths
 }
 
 // countPluginHooks counts hooks in a single plugin directory
@@ -315,3 +315,105 @@ func TestCollector_CoreToolsSet(t *testing.T) {
 		}
 	}
 }
+
+func TestCollector_Skills(t *testing.T) {
+	tmpDir := t.TempDir()
+	skillsDir := filepath.Join(tmpDir, ".claude", "skills")
+	pluginsDir := filepath.Join(tmpDir, "plugins")
+	pluginInstallPath := filepath.Join(pluginsDir, "cache", "test-marketplace", "test-plugin", "1.0.0")
+
+	for _, dir := range []string{
+		filepath.Join(skillsDir, "review"),
+		filepath.Join(skillsDir, "deploy"),
+		filepath.Join(pluginInstallPath, "skills", "lint"),
+	} {
+		os.MkdirAll(dir, 0755)
+		os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("---\nname: skill\n---\n"), 0644)
+	}
+	// Directories without a SKILL.md are not skills
+	os.MkdirAll(filepath.Join(skillsDir, "notes"), 0755)
+
+	installed := map[string]interface{}{
+		"plugins": map[string]interface{}{
+			"test-plugin@test-marketplace": []map[string]interface{}{
+				{"installPath": pluginInstallPath},
+			},
+		},
+	}
+	installedData, _ := json.Marshal(installed)
+	os.WriteFile(filepath.Join(pluginsDir, "installed_plugins.json"), installedData, 0644)
+
+	collector := &Collector{
+		settingsPath: filepath.Join(tmpDir, "settings.json"),
+		pluginsDir:   pluginsDir,
+		claudeDir:    filepath.Join(tmpDir, ".claude"),
+		projectDir:   filepath.Join(tmpDir, "project"),
+		cacheTTL:     5 * time.Second,
+	}
+
+	stats := collector.Collect(context.Background())
+	if want := (SourceCounts{User: 2, Plugin: 1}); stats.Skills != want {
+		t.Errorf("Expected Skills %+v, got %+v", want, stats.Skills)
+	}
+}
+
+func TestCollector_Definitions(t *testing.T) {
+	tmpDir := t.TempDir()
+	claudeDir := filepath.Join(tmpDir, ".claude")
+	projectDir := filepath.Join(tmpDir, "project")
+	pluginsDir := filepath.Join(tmpDir, "plugins")
+	pluginInstallPath := filepath.Join(pluginsDir, "cache", "test-marketplace", "test-plugin", "1.0.0")
+
+	for _, path := range []string{
+		filepath.Join(claudeDir, "commands", "review.md"),
+		filepath.Join(claudeDir, "commands", "frontend", "lint.md"), // Namespaced as /frontend:lint
+		filepath.Join(claudeDir, "agents", "planner.md"),
+		filepath.Join(claudeDir, "output-styles", "terse.md"),
+		filepath.Join(projectDir, ".claude", "commands", "release.md"),
+		filepath.Join(projectDir, ".claude", "agents", "tester.md"),
+		filepath.Join(projectDir, ".claude", "agents", "debugger.md"),
+		filepath.Join(pluginInstallPath, "commands", "deploy.md"),
+		filepath.Join(pluginInstallPath, "agents", "reviewer.md"),
+	} {
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte("---\ndescription: test\n---\n"), 0644)
+	}
+	// Only markdown files are definitions
+	os.WriteFile(filepath.Join(claudeDir, "agents", "README.txt"), []byte("notes"), 0644)
+
+	installed := map[string]interface{}{
+		"plugins": map[string]interface{}{
+			"test-plugin@test-marketplace": []map[string]interface{}{
+				{"installPath": pluginInstallPath},
+			},
+		},
+	}
+	installedData, _ := json.Marshal(installed)
+	os.WriteFile(filepath.Join(pluginsDir, "installed_plugins.json"), installedData, 0644)
+
+	collector := &Collector{
+		settingsPath: filepath.Join(tmpDir, "settings.json"),
+		pluginsDir:   pluginsDir,
+		claudeDir:    claudeDir,
+		projectDir:   projectDir,
+		cacheTTL:     5 * time.Second,
+	}
+
+	stats := collector.Collect(context.Background())
+	if want := (SourceCounts{User: 2, Project: 1, Plugin: 1}); stats.Commands != want {
+		t.Errorf("Expected Commands %+v, got %+v", want, stats.Commands)
+	}
+	if want := (SourceCounts{User: 1, Project: 2, Plugin: 1}); stats.Agents != want {
+		t.Errorf("Expected Agents %+v, got %+v", want, stats.Agents)
+	}
+	if want := (SourceCounts{User: 1}); stats.OutputStyles != want {
+		t.Errorf("Expected OutputStyles %+v, got %+v", want, stats.OutputStyles)
+	}
+
+	// Running from the home directory does not count user definitions twice
+	collector.SetProjectDir(tmpDir)
+	collector.cache = nil
+	if stats := collector.Collect(context.Background()); stats.Commands.Total() != 3 {
+		t.Errorf("Expected 3 commands with the home directory as project, got %+v", stats.Commands)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"time"
 
 	"github.com/ll931217/claude-hud-enhanced/internal/theme"
@@ -12,23 +13,162 @@ import (
 
 // Config represents the application configuration
 type Config struct {
-	Colors            ColorsConfig            `yaml:"colors"`
-	Layout            LayoutConfig            `yaml:"layout"`
-	Sections          SectionsConfig          `yaml:"sections"`
-	RefreshIntervalMs int                     `yaml:"refresh_interval_ms"`
-	Debug             bool                    `yaml:"debug"`
-	CompactMode       bool                    `yaml:"compact_mode"`
-	MaxLines          int                     `yaml:"max_lines"`
+	Version           int                 `yaml:"config_version"`
+	Colors            ColorsConfig        `yaml:"colors"`
+	Layout            LayoutConfig        `yaml:"layout"`
+	Sections          SectionsConfig      `yaml:"sections"`
+	RefreshIntervalMs int                 `yaml:"refresh_interval_ms"`
+	Debug             bool                `yaml:"debug"`
+	Logging           LoggingConfig       `yaml:"logging"`
+	CompactMode       bool                `yaml:"compact_mode"`
+	MaxLines          int                 `yaml:"max_lines"`
+	Animate           bool                `yaml:"animate"`       // Let sections animate, e.g. spin for running tools
+	Hyperlinks        bool                `yaml:"hyperlinks"`    // Link sections to web pages with OSC 8 escapes
+	DebugOverlay      int                 `yaml:"debug_overlay"` // Append the last N logged warnings and errors as a dimmed line
+	WatchMode         string              `yaml:"watch_mode"`    // auto (poll in containers), fsnotify or polling
+	Locale            string              `yaml:"locale"`        // Language of labels and durations, or auto from LANG
+	Theme             string              `yaml:"theme"`         // Built-in theme for signal colors, e.g. nord; empty keeps the 256-color defaults
+	Output            string              `yaml:"output"`        // How the statusline is written: ansi (default), tmux, plain or json
+	CacheTTLMs        map[string]int      `yaml:"cache_ttl_ms"`
+	Store             StoreConfig         `yaml:"store"`
+	Digest            DigestConfig        `yaml:"digest"`
+	Reporter          ReporterConfig      `yaml:"reporter"`
+	MCP               MCPConfig           `yaml:"mcp"`
+	Daemon            DaemonConfig        `yaml:"daemon"`
+	Accessibility     AccessibilityConfig `yaml:"accessibility"`
+	UsageAPI          UsageAPIConfig      `yaml:"usage_api"`
+	Transcript        TranscriptConfig    `yaml:"transcript"`
+	ErrorBudget       ErrorBudgetConfig   `yaml:"error_budget"`
+
+	// Where tool targets are taken from, by tool name or name prefix ending in *
+	ToolTargets map[string]ToolTargetConfig `yaml:"tool_targets"`
+
+	// Regular expressions of Bash commands to warn about, by name; an empty
+	// pattern turns a built-in one off
+	DangerousCommands map[string]string `yaml:"dangerous_commands"`
+
+	// Named overlays of any of the settings above, e.g. a minimal layout
+	// for tmux; Profile picks one unless --profile or $CLAUDE_HUD_PROFILE does.
+	// Profiles stay YAML so they can be laid over the rest of the file
+	Profile  string               `yaml:"profile"`
+	Profiles map[string]yaml.Node `yaml:"profiles"`
+
+	ActiveProfile string `yaml:"-"` // The profile applied, if any
+	profileErr    error
 }
 
-// SectionsConfig holds section-specific configuration options
-type SectionsConfig struct {
-	ZaiUsage ZaiUsageConfig `yaml:"zaiusage"`
+// LoggingConfig holds log levels and where logs go
+type LoggingConfig struct {
+	Level   string            `yaml:"level"`   // Minimum level: debug, info, warn or error (default: info)
+	File    string            `yaml:"file"`    // Append logs to this file instead of stderr
+	Modules map[string]string `yaml:"modules"` // Levels by operation prefix, e.g. transcript: debug
 }
 
-// ZaiUsageConfig holds configuration for the zaiusage section
-type ZaiUsageConfig struct {
-	ShowResetTimes bool `yaml:"show_reset_times"` // Show when quotas reset
+// ToolTargetConfig says which input field holds a tool's target and how it is shown
+type ToolTargetConfig struct {
+	Fields []string `yaml:"fields"`  // Input fields to look in; the first non-empty string wins
+	Format string   `yaml:"format"`  // text, path, domain or auto (default)
+	MaxLen int      `yaml:"max_len"` // Longest target shown, in characters (default: 20)
+}
+
+// StoreConfig holds settings for the optional SQLite session store
+type StoreConfig struct {
+	Enabled bool   `yaml:"enabled"` // Ingest transcripts into the store when sessions stop
+	Path    string `yaml:"path"`    // Database path (default: state directory)
+}
+
+// DigestConfig holds settings for the session digest hooks print
+type DigestConfig struct {
+	Enabled bool   `yaml:"enabled"` // Print a digest from the Stop or SessionEnd hook
+	On      string `yaml:"on"`      // "session_end" (default) or "stop", after every turn
+	LogDir  string `yaml:"log_dir"` // Also append it to a daily log here, e.g. ~/worklog
+
+	// Slack or Discord incoming webhooks to post it to
+	Webhooks []WebhookConfig `yaml:"webhooks"`
+}
+
+// WebhookConfig is a Slack or Discord incoming webhook the digest is posted to
+type WebhookConfig struct {
+	Name       string `yaml:"name"`         // Names it in logs and rate limits (default: its kind)
+	Kind       string `yaml:"kind"`         // "slack" or "discord" (default: guessed from the URL)
+	URL        string `yaml:"url"`          // Incoming webhook URL
+	URLEnv     string `yaml:"url_env"`      // Environment variable holding the URL, to keep it out of the file
+	Template   string `yaml:"template"`     // Go template of the message over the digest (default: a summary)
+	MaxPerHour int    `yaml:"max_per_hour"` // Posts allowed per hour, across sessions (default: 10)
+}
+
+// ReporterConfig holds settings for the opt-in team usage reporter
+type ReporterConfig struct {
+	Enabled    bool   `yaml:"enabled"`     // Send usage summaries from the daemon
+	Endpoint   string `yaml:"endpoint"`    // URL that receives batched summaries
+	APIKey     string `yaml:"api_key"`     // Bearer token (default: $CLAUDE_HUD_REPORTER_API_KEY)
+	IntervalMs int    `yaml:"interval_ms"` // How often batches are sent (default: 5 minutes)
+	Developer  string `yaml:"developer"`   // Name to report under (default: anonymized user@host)
+}
+
+// MCPConfig holds settings for MCP server health checks
+type MCPConfig struct {
+	ProbeIntervalMs int `yaml:"probe_interval_ms"` // How often the daemon probes servers (0 disables)
+}
+
+// DaemonConfig holds settings for the daemon serving many sessions at once
+type DaemonConfig struct {
+	MaxSessions int    `yaml:"max_sessions"` // Transcripts kept parsed at once; the least recently used is dropped (default: 32)
+	Address     string `yaml:"address"`      // Daemon hooks and statuslines report to, e.g. tcp://127.0.0.1:7878 (default: the local socket)
+	Token       string `yaml:"token"`        // Shared secret every request over TCP carries; required by --http
+
+	// Recurring background work the daemon schedules
+	Jobs JobsConfig `yaml:"jobs"`
+}
+
+// JobsConfig holds the daemon's recurring jobs
+type JobsConfig struct {
+	CacheRefresh JobConfig        `yaml:"cache_refresh"` // Refresh the usage API and session window caches statuslines read
+	MCPProbe     JobConfig        `yaml:"mcp_probe"`     // Probe MCP servers; also on when mcp.probe_interval_ms is set
+	Janitor      JanitorJobConfig `yaml:"janitor"`       // Archive old transcripts
+	Reports      ReportsJobConfig `yaml:"reports"`       // Write reports of recent sessions
+	Digest       JobConfig        `yaml:"digest"`        // Post digests of recent sessions to digest.webhooks
+}
+
+// JobConfig is when a daemon job runs
+type JobConfig struct {
+	Enabled    bool `yaml:"enabled"`
+	IntervalMs int  `yaml:"interval_ms"` // Time between runs (minimum 60000)
+	JitterMs   int  `yaml:"jitter_ms"`   // Each run starts up to this much late, at random
+}
+
+// JanitorJobConfig is the transcript janitor job
+type JanitorJobConfig struct {
+	JobConfig     `yaml:",inline"`
+	OlderThanDays int `yaml:"older_than_days"` // Archive transcripts not modified for this long (minimum 1)
+}
+
+// ReportsJobConfig is the session report job
+type ReportsJobConfig struct {
+	JobConfig `yaml:",inline"`
+	Dir       string `yaml:"dir"`    // Where reports are written (default: reports in the state directory)
+	Format    string `yaml:"format"` // "md" (default) or "html"
+}
+
+// UsageAPIConfig holds settings for the opt-in subscription quota lookup
+type UsageAPIConfig struct {
+	Enabled    bool `yaml:"enabled"`      // Read Claude Code's OAuth credentials and query the usage endpoint
+	CacheTTLMs int  `yaml:"cache_ttl_ms"` // How long results are reused (default: 5 minutes)
+}
+
+// TranscriptConfig holds settings for reading transcripts
+type TranscriptConfig struct {
+	MaxLineBytes   int    `yaml:"max_line_bytes"`  // Longest line read (default: 1 MB, minimum 64 KB)
+	OversizedLines string `yaml:"oversized_lines"` // skip (default) or abort reading at a longer line
+}
+
+// ErrorBudgetConfig holds how often a section may fail before it is turned
+// off, and for how long
+type ErrorBudgetConfig struct {
+	Failures  int `yaml:"failures"`   // Panics and timeouts allowed within the window; 0 never turns sections off (default: 5)
+	WindowMs  int `yaml:"window_ms"`  // How far back failures count (default: 1 minute)
+	TimeoutMs int `yaml:"timeout_ms"` // A render taking longer counts as timed out (default: 2 seconds)
+	DisableMs int `yaml:"disable_ms"` // How long later runs keep a section turned off (default: 1 hour)
 }
 
 // ColorsConfig holds color customization options
@@ -42,17 +182,44 @@ type ColorsConfig struct {
 	Muted     string `yaml:"muted"`
 }
 
+// AccessibilityConfig holds settings for color blindness, low vision and
+// screen readers
+type AccessibilityConfig struct {
+	Enabled      bool `yaml:"enabled"`       // Turns on symbols and high_contrast together
+	Symbols      bool `yaml:"symbols"`       // Pair warning and critical colors with ⚠ and ‼
+	HighContrast bool `yaml:"high_contrast"` // Bright basic colors and no dimmed text
+	Plain        bool `yaml:"plain"`         // Labeled plain text for screen readers, like --plain
+}
+
 // LayoutConfig holds configuration for custom layouts
 type LayoutConfig struct {
-	Lines      []LineConfig     `yaml:"lines"`
-	Responsive ResponsiveConfig `yaml:"responsive"`
+	Lines            []LineConfig     `yaml:"lines"`
+	Responsive       ResponsiveConfig `yaml:"responsive"`
+	ShowPlaceholders bool             `yaml:"show_placeholders"` // Show dimmed markers for sections whose data source failed
+	Separator        string           `yaml:"separator"`         // Separator for lines without their own; " | " when empty
+	Decoration       string           `yaml:"decoration"`        // Decoration around every section, see Decorations
+	Padding          int              `yaml:"padding"`           // Spaces between each section and its decoration
+	Width            int              `yaml:"width"`             // Columns to lay out for when the terminal's width is unknown; 0 leaves it unknown
 }
 
 // LineConfig defines sections on a single line with custom separator
 type LineConfig struct {
-	Sections  []string `yaml:"sections"`  // Section names in order
-	Separator string   `yaml:"separator"` // Custom separator for this line
-	Wrap      bool     `yaml:"wrap"`      // Allow wrapping to next line if too long
+	Sections   []string `yaml:"sections"`   // Section names in order
+	Separator  string   `yaml:"separator"`  // Custom separator for this line
+	Wrap       bool     `yaml:"wrap"`       // Allow wrapping to next line if too long
+	Decoration string   `yaml:"decoration"` // Overrides layout.decoration for this line
+	Padding    *int     `yaml:"padding"`    // Overrides layout.padding for this line
+}
+
+// Decorations are the named decorations sections can be wrapped in; any
+// other decoration is an opening and a closing string separated by a space,
+// e.g. "⟨ ⟩". "pill" shows the section in reverse video between rounded caps
+var Decorations = map[string][2]string{
+	"none":     {"", ""},
+	"brackets": {"[", "]"},
+	"parens":   {"(", ")"},
+	"braces":   {"{", "}"},
+	"pill":     {"\ue0b6", "\ue0b4"},
 }
 
 // ResponsiveConfig holds settings for responsive behavior
@@ -70,7 +237,8 @@ func defaultConfig() *Config {
 	ct := theme.CatppuccinMocha()
 
 	return &Config{
-		Layout: DefaultLayout(),
+		Version: CurrentVersion,
+		Layout:  DefaultLayout(),
 		Colors: ColorsConfig{
 			Primary:   ct.Primary,
 			Secondary: ct.Secondary,
@@ -84,6 +252,42 @@ func defaultConfig() *Config {
 		Debug:             false,
 		CompactMode:       false,
 		MaxLines:          4,
+		Animate:           true,
+		Reporter: ReporterConfig{
+			IntervalMs: 5 * 60 * 1000,
+		},
+		UsageAPI: UsageAPIConfig{
+			CacheTTLMs: 5 * 60 * 1000,
+		},
+		Transcript: TranscriptConfig{
+			MaxLineBytes:   1024 * 1024,
+			OversizedLines: "skip",
+		},
+		ErrorBudget: ErrorBudgetConfig{
+			Failures:  5,
+			WindowMs:  60 * 1000,
+			TimeoutMs: 2000,
+			DisableMs: 60 * 60 * 1000,
+		},
+		Daemon: DaemonConfig{
+			MaxSessions: 32,
+			Jobs: JobsConfig{
+				CacheRefresh: JobConfig{Enabled: true, IntervalMs: 5 * 60 * 1000, JitterMs: 30 * 1000},
+				MCPProbe:     JobConfig{IntervalMs: 10 * 60 * 1000, JitterMs: 30 * 1000},
+				Janitor: JanitorJobConfig{
+					JobConfig:     JobConfig{IntervalMs: 24 * 60 * 60 * 1000, JitterMs: 60 * 60 * 1000},
+					OlderThanDays: 30,
+				},
+				Reports: ReportsJobConfig{
+					JobConfig: JobConfig{IntervalMs: 24 * 60 * 60 * 1000, JitterMs: 5 * 60 * 1000},
+					Format:    "md",
+				},
+				Digest: JobConfig{IntervalMs: 24 * 60 * 60 * 1000, JitterMs: 5 * 60 * 1000},
+			},
+		},
+		Digest: DigestConfig{
+			On: "session_end",
+		},
 	}
 }
 
@@ -112,11 +316,15 @@ func Load() *Config {
 		return defaultConfig()
 	}
 
+	// Upgrade files written for older releases
+	data = migrateData(configPath, data)
+
 	// Parse YAML with graceful degradation
 	config := defaultConfig()
 	if err := yaml.Unmarshal(data, config); err != nil {
 		return defaultConfig()
 	}
+	config.applyProfile()
 
 	// Validate and sanitize the config
 	config.validate()
@@ -141,10 +349,14 @@ func LoadFromPath(path string) *Config {
 		return config
 	}
 
+	// Upgrade files written for older releases
+	data = migrateData(path, data)
+
 	// Parse YAML
 	if err := yaml.Unmarshal(data, config); err != nil {
 		return config
 	}
+	config.applyProfile()
 
 	// Validate and sanitize
 	config.validate()
@@ -198,6 +410,76 @@ func (c *Config) validate() {
 	if c.Colors.Muted == "" {
 		c.Colors.Muted = ct.Muted
 	}
+
+	// Never send usage more often than once a minute
+	if c.Reporter.IntervalMs < 60*1000 {
+		c.Reporter.IntervalMs = 60 * 1000
+	}
+
+	// Probes spawn every server; never run them more often than once a minute
+	if c.MCP.ProbeIntervalMs < 0 {
+		c.MCP.ProbeIntervalMs = 0
+	}
+	if c.MCP.ProbeIntervalMs > 0 && c.MCP.ProbeIntervalMs < 60*1000 {
+		c.MCP.ProbeIntervalMs = 60 * 1000
+	}
+
+	c.Layout.Width = max(c.Layout.Width, 0)
+
+	if c.Daemon.MaxSessions < 1 {
+		c.Daemon.MaxSessions = 32
+	}
+
+	// Lines shorter than 64 KB are common, so a lower limit would lose them
+	if c.Transcript.MaxLineBytes < 64*1024 {
+		c.Transcript.MaxLineBytes = 64 * 1024
+	}
+	if c.Transcript.OversizedLines != "abort" {
+		c.Transcript.OversizedLines = "skip"
+	}
+
+	// Windows and timeouts too short to mean anything fall back to the
+	// defaults; 0 failures never turns a section off, and a section stays
+	// off at least until its failures leave the window
+	c.ErrorBudget.Failures = max(c.ErrorBudget.Failures, 0)
+	if c.ErrorBudget.WindowMs < 1000 {
+		c.ErrorBudget.WindowMs = 60 * 1000
+	}
+	if c.ErrorBudget.TimeoutMs < 100 {
+		c.ErrorBudget.TimeoutMs = 2000
+	}
+	if c.ErrorBudget.DisableMs < c.ErrorBudget.WindowMs {
+		c.ErrorBudget.DisableMs = max(60*60*1000, c.ErrorBudget.WindowMs)
+	}
+
+	// Background jobs never run more often than once a minute
+	jobs := &c.Daemon.Jobs
+	for _, job := range []*JobConfig{&jobs.CacheRefresh, &jobs.MCPProbe, &jobs.Janitor.JobConfig, &jobs.Reports.JobConfig, &jobs.Digest} {
+		job.IntervalMs = max(job.IntervalMs, 60*1000)
+		job.JitterMs = max(job.JitterMs, 0)
+	}
+	if c.MCP.ProbeIntervalMs > 0 && !jobs.MCPProbe.Enabled {
+		jobs.MCPProbe.Enabled = true
+		jobs.MCPProbe.IntervalMs = c.MCP.ProbeIntervalMs
+	}
+	jobs.Janitor.OlderThanDays = max(jobs.Janitor.OlderThanDays, 1)
+	if jobs.Reports.Format != "html" {
+		jobs.Reports.Format = "md"
+	}
+
+	if c.Digest.On != "stop" {
+		c.Digest.On = "session_end"
+	}
+	for i := range c.Digest.Webhooks {
+		if c.Digest.Webhooks[i].MaxPerHour <= 0 {
+			c.Digest.Webhooks[i].MaxPerHour = 10
+		}
+	}
+
+	// The usage endpoint is rate limited; never query it more than once a minute
+	if c.UsageAPI.CacheTTLMs < 60*1000 {
+		c.UsageAPI.CacheTTLMs = 60 * 1000
+	}
 }
 
 // GetEnabledSections returns a list of enabled section names in order from layout
@@ -209,7 +491,7 @@ func (c *Config) GetEnabledSections() []string {
 		var result []string
 		for _, line := range c.Layout.Lines {
 			for _, sectionName := range line.Sections {
-				if !seen[sectionName] {
+				if !seen[sectionName] && c.IsSectionEnabled(sectionName) {
 					seen[sectionName] = true
 					result = append(result, sectionName)
 				}
@@ -218,7 +500,8 @@ func (c *Config) GetEnabledSections() []string {
 		return result
 	}
 
-	// Fallback: return all enabled sections in default order
+	// Fallback: return all enabled sections in default order,
+	// rearranged by any sections.<name>.order options
 	defaultOrder := []string{"model", "contextbar", "duration", "zaiusage", "beads", "status", "workspace", "claudestats", "tools", "sysinfo"}
 	var result []string
 	for _, name := range defaultOrder {
@@ -226,13 +509,25 @@ func (c *Config) GetEnabledSections() []string {
 			result = append(result, name)
 		}
 	}
+	position := make(map[string]int, len(result))
+	for i, name := range result {
+		position[name] = c.Sections.Get(name).Int("order", i+1)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return position[result[i]] < position[result[j]]
+	})
 	return result
 }
 
 // IsSectionEnabled checks if a specific section is enabled
 // A section is enabled if it appears in any layout.lines configuration
+// and is not switched off with sections.<name>.enabled: false
 // If layout is empty, all sections are considered enabled (fallback behavior)
 func (c *Config) IsSectionEnabled(sectionName string) bool {
+	if !c.Sections.Get(sectionName).Bool("enabled", true) {
+		return false
+	}
+
 	if len(c.Layout.Lines) == 0 {
 		// No layout configured, all sections enabled
 		return true
@@ -253,9 +548,17 @@ func (c *Config) GetRefreshInterval() time.Duration {
 	return time.Duration(c.RefreshIntervalMs) * time.Millisecond
 }
 
-// ShowZaiResetTimes returns whether to show reset times in the zaiusage section
-func (c *Config) ShowZaiResetTimes() bool {
-	return c.Sections.ZaiUsage.ShowResetTimes
+// GetCacheTTL returns the render cache duration for a section
+// Falls back to the section's own default when no override is configured;
+// an override of 0 disables caching for that section
+func (c *Config) GetCacheTTL(sectionName string, fallback time.Duration) time.Duration {
+	if ms, ok := c.CacheTTLMs[sectionName]; ok {
+		if ms < 0 {
+			return 0
+		}
+		return time.Duration(ms) * time.Millisecond
+	}
+	return fallback
 }
 
 // Save writes the current configuration to the default config path
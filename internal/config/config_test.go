@@ -234,6 +234,95 @@ func TestValidate_RefreshIntervalClamping(t *testing.T) {
 	}
 }
 
+func TestValidate_MCPProbeIntervalClamping(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    int
+		expected int
+	}{
+		{"Disabled", 0, 0},
+		{"Negative", -1, 0},
+		{"Too low", 1000, 60000},
+		{"Valid", 300000, 300000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.MCP.ProbeIntervalMs = tt.input
+			config.validate()
+
+			if config.MCP.ProbeIntervalMs != tt.expected {
+				t.Errorf("Expected probe interval %d after validation, got %d",
+					tt.expected, config.MCP.ProbeIntervalMs)
+			}
+		})
+	}
+}
+
+func TestValidate_Jobs(t *testing.T) {
+	config := DefaultConfig()
+	config.MCP.ProbeIntervalMs = 300000
+	config.Daemon.Jobs.Janitor.IntervalMs = 1000
+	config.Daemon.Jobs.Janitor.OlderThanDays = 0
+	config.Daemon.Jobs.Reports.Format = "pdf"
+	config.validate()
+
+	jobs := config.Daemon.Jobs
+	if !jobs.MCPProbe.Enabled || jobs.MCPProbe.IntervalMs != 300000 {
+		t.Errorf("mcp_probe = %+v, want it on every 300000ms for mcp.probe_interval_ms", jobs.MCPProbe)
+	}
+	if jobs.Janitor.IntervalMs != 60000 || jobs.Janitor.OlderThanDays != 1 {
+		t.Errorf("janitor = %+v, want a 60000ms interval and 1 day", jobs.Janitor)
+	}
+	if jobs.Reports.Format != "md" {
+		t.Errorf("reports format = %q, want md", jobs.Reports.Format)
+	}
+	if !jobs.CacheRefresh.Enabled || jobs.Digest.Enabled {
+		t.Errorf("only cache_refresh should be on by default: %+v", jobs)
+	}
+}
+
+func TestValidate_Transcript(t *testing.T) {
+	config := DefaultConfig()
+	if config.Transcript.MaxLineBytes != 1024*1024 || config.Transcript.OversizedLines != "skip" {
+		t.Errorf("default transcript = %+v, want 1 MB lines, skipping longer ones", config.Transcript)
+	}
+
+	config.Transcript.MaxLineBytes = 100
+	config.Transcript.OversizedLines = "crash"
+	config.validate()
+	if config.Transcript.MaxLineBytes != 64*1024 || config.Transcript.OversizedLines != "skip" {
+		t.Errorf("transcript = %+v, want 64 KB lines, skipping longer ones", config.Transcript)
+	}
+}
+
+func TestValidate_ErrorBudget(t *testing.T) {
+	config := DefaultConfig()
+	config.ErrorBudget = ErrorBudgetConfig{Failures: -1, WindowMs: 10, TimeoutMs: 5}
+	config.validate()
+	want := ErrorBudgetConfig{Failures: 0, WindowMs: 60 * 1000, TimeoutMs: 2000, DisableMs: 60 * 60 * 1000}
+	if config.ErrorBudget != want {
+		t.Errorf("error budget = %+v, want %+v", config.ErrorBudget, want)
+	}
+
+	// A section may not come back on before its failures leave the window
+	config.ErrorBudget = ErrorBudgetConfig{Failures: 5, WindowMs: 2 * 60 * 60 * 1000, TimeoutMs: 2000, DisableMs: 60 * 1000}
+	config.validate()
+	if got := config.ErrorBudget.DisableMs; got != 2*60*60*1000 {
+		t.Errorf("disable_ms = %d, want the window", got)
+	}
+}
+
+func TestValidate_LayoutWidth(t *testing.T) {
+	config := DefaultConfig()
+	config.Layout.Width = -80
+	config.validate()
+	if config.Layout.Width != 0 {
+		t.Errorf("Layout.Width = %d, want a negative width to leave it unknown", config.Layout.Width)
+	}
+}
+
 func TestValidate_ColorDefaults(t *testing.T) {
 	config := DefaultConfig()
 
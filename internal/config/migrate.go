@@ -0,0 +1,238 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the config_version written by this release
+// Bump it and append a Migration whenever a key is renamed or restructured
+const CurrentVersion = 1
+
+// versionKey is the top-level YAML key holding the config version
+const versionKey = "config_version"
+
+// Migration upgrades a config document from version From to From+1
+// Apply edits the document mapping in place and returns a description of each change
+type Migration struct {
+	From        int
+	Description string
+	Apply       func(doc *yaml.Node) []string
+}
+
+// migrations are applied in order to files older than CurrentVersion
+var migrations = []Migration{
+	{
+		From:        0,
+		Description: "split the legacy session section into model, contextbar and duration",
+		Apply:       migrateSessionSection,
+	},
+}
+
+// MigrationResult describes what Migrate did to a document
+type MigrationResult struct {
+	FromVersion int
+	ToVersion   int
+	Changes     []string
+}
+
+// Migrated reports whether the document was changed
+func (r MigrationResult) Migrated() bool {
+	return r.FromVersion != r.ToVersion
+}
+
+// Migrate upgrades a YAML config document to CurrentVersion
+// Comments and key order are preserved. Documents that are already current,
+// empty, or written by a newer release are returned unchanged
+func Migrate(data []byte) ([]byte, MigrationResult, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return data, MigrationResult{}, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return data, MigrationResult{FromVersion: CurrentVersion, ToVersion: CurrentVersion}, nil
+	}
+	doc := root.Content[0]
+
+	version := 0
+	if node := mappingValue(doc, versionKey); node != nil {
+		v, err := strconv.Atoi(node.Value)
+		if err != nil {
+			return data, MigrationResult{}, fmt.Errorf("invalid %s %q", versionKey, node.Value)
+		}
+		version = v
+	}
+
+	result := MigrationResult{FromVersion: version, ToVersion: version}
+	if version >= CurrentVersion {
+		return data, result, nil
+	}
+
+	for _, m := range migrations {
+		if m.From < version {
+			continue
+		}
+		for _, change := range m.Apply(doc) {
+			result.Changes = append(result.Changes, fmt.Sprintf("v%d→v%d: %s", m.From, m.From+1, change))
+		}
+	}
+	result.ToVersion = CurrentVersion
+	setMappingValue(doc, versionKey, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(CurrentVersion)})
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&root); err != nil {
+		return data, MigrationResult{}, fmt.Errorf("failed to encode migrated config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return data, MigrationResult{}, fmt.Errorf("failed to encode migrated config: %w", err)
+	}
+	return buf.Bytes(), result, nil
+}
+
+// MigrateFile upgrades the config file at path
+// When write is true the migrated file replaces the original, which is kept as path+".bak"
+func MigrateFile(path string, write bool) (MigrationResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return MigrationResult{}, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	migrated, result, err := Migrate(data)
+	if err != nil || !result.Migrated() || !write {
+		return result, err
+	}
+
+	if err := os.WriteFile(path+".bak", data, 0644); err != nil {
+		return result, fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := os.WriteFile(path, migrated, 0644); err != nil {
+		return result, fmt.Errorf("failed to write migrated config: %w", err)
+	}
+	return result, nil
+}
+
+// ConfigPath returns the default configuration file path
+func ConfigPath() (string, error) {
+	return getConfigPath()
+}
+
+// migrateData upgrades config data while loading and logs what changed
+// The file on disk is left untouched; `claude-hud config migrate` writes it back
+func migrateData(path string, data []byte) []byte {
+	migrated, result, err := Migrate(data)
+	if err != nil {
+		return data
+	}
+	if result.FromVersion > CurrentVersion {
+		errors.Warn("config", "%s has config_version %d, newer than this release supports (%d)", path, result.FromVersion, CurrentVersion)
+		return data
+	}
+	if result.Migrated() {
+		for _, change := range result.Changes {
+			errors.Info("config", "migrated %s: %s", path, change)
+		}
+	}
+	return migrated
+}
+
+// migrateSessionSection replaces the legacy "session" section, which was split
+// into model, contextbar and duration, in layout lines and the sections map
+func migrateSessionSection(doc *yaml.Node) []string {
+	var changes []string
+	replacement := []string{"model", "contextbar", "duration"}
+
+	if layout := mappingValue(doc, "layout"); layout != nil {
+		if lines := mappingValue(layout, "lines"); lines != nil && lines.Kind == yaml.SequenceNode {
+			for i, line := range lines.Content {
+				sections := mappingValue(line, "sections")
+				if sections == nil || sections.Kind != yaml.SequenceNode {
+					continue
+				}
+				var content []*yaml.Node
+				replaced := false
+				for _, item := range sections.Content {
+					if item.Value != "session" {
+						content = append(content, item)
+						continue
+					}
+					replaced = true
+					for _, name := range replacement {
+						content = append(content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name})
+					}
+				}
+				if replaced {
+					sections.Content = content
+					changes = append(changes, fmt.Sprintf("layout.lines[%d]: session → model, contextbar, duration", i))
+				}
+			}
+		}
+	}
+
+	if sections := mappingValue(doc, "sections"); sections != nil && sections.Kind == yaml.MappingNode {
+		if legacy := mappingValue(sections, "session"); legacy != nil {
+			deleteMappingKey(sections, "session")
+			for _, name := range replacement {
+				if mappingValue(sections, name) == nil {
+					setMappingValue(sections, name, cloneNode(legacy))
+				}
+			}
+			changes = append(changes, "sections.session → sections.model, sections.contextbar, sections.duration")
+		}
+	}
+
+	return changes
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue sets key to value in a mapping node, appending the key if missing
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		value,
+	)
+}
+
+// deleteMappingKey removes key from a mapping node
+func deleteMappingKey(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// cloneNode deep-copies a YAML node
+func cloneNode(node *yaml.Node) *yaml.Node {
+	clone := *node
+	clone.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		clone.Content[i] = cloneNode(child)
+	}
+	return &clone
+}
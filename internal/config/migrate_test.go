@@ -0,0 +1,124 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const legacyConfig = `# My HUD
+refresh_interval_ms: 500
+layout:
+  lines:
+    - sections: [session, beads]
+      separator: " | "
+    - sections: [status]
+      separator: " | "
+sections:
+  session:
+    enabled: true
+    order: 1
+`
+
+func TestMigrate_SessionSection(t *testing.T) {
+	migrated, result, err := Migrate([]byte(legacyConfig))
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if result.FromVersion != 0 || result.ToVersion != CurrentVersion {
+		t.Errorf("versions = %d→%d, want 0→%d", result.FromVersion, result.ToVersion, CurrentVersion)
+	}
+	if len(result.Changes) != 2 {
+		t.Errorf("changes = %v, want 2 entries", result.Changes)
+	}
+
+	out := string(migrated)
+	if !strings.Contains(out, "# My HUD") {
+		t.Error("comments should be preserved")
+	}
+	if !strings.Contains(out, "config_version: 1") {
+		t.Errorf("migrated config should record its version:\n%s", out)
+	}
+
+	cfg := DefaultConfig()
+	if err := yaml.Unmarshal(migrated, cfg); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"model", "contextbar", "duration", "beads"}
+	if got := cfg.Layout.Lines[0].Sections; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("first line = %v, want %v", got, want)
+	}
+	if strings.Contains(out, "session:") {
+		t.Errorf("legacy sections.session should be removed:\n%s", out)
+	}
+}
+
+func TestMigrate_CurrentIsUnchanged(t *testing.T) {
+	input := []byte("config_version: 1\nlayout:\n  lines:\n    - sections: [session]\n")
+	out, result, err := Migrate(input)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if result.Migrated() || string(out) != string(input) {
+		t.Errorf("current config should not change, got %q", out)
+	}
+
+	// Newer configs are left alone rather than downgraded
+	if _, result, _ := Migrate([]byte("config_version: 99\n")); result.Migrated() || result.FromVersion != 99 {
+		t.Errorf("newer config result = %+v", result)
+	}
+}
+
+func TestMigrateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(legacyConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Dry run leaves the file alone
+	if _, err := MigrateFile(path, false); err != nil {
+		t.Fatalf("MigrateFile(dry run) error = %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != legacyConfig {
+		t.Error("dry run should not modify the file")
+	}
+
+	result, err := MigrateFile(path, true)
+	if err != nil {
+		t.Fatalf("MigrateFile() error = %v", err)
+	}
+	if !result.Migrated() {
+		t.Fatal("expected migration")
+	}
+	if data, _ := os.ReadFile(path + ".bak"); string(data) != legacyConfig {
+		t.Error("backup should hold the original file")
+	}
+
+	// Second run is a no-op
+	if result, _ := MigrateFile(path, true); result.Migrated() {
+		t.Error("already migrated file should not migrate again")
+	}
+}
+
+func TestLoadFromPath_MigratesLegacyConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(legacyConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := LoadFromPath(path)
+	if !cfg.IsSectionEnabled("contextbar") || cfg.IsSectionEnabled("session") {
+		t.Errorf("legacy session section not migrated: %v", cfg.Layout.Lines)
+	}
+	if cfg.Version != CurrentVersion {
+		t.Errorf("Version = %d, want %d", cfg.Version, CurrentVersion)
+	}
+
+	// Loading never rewrites the file
+	if data, _ := os.ReadFile(path); string(data) != legacyConfig {
+		t.Error("LoadFromPath should not modify the file")
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/render"
+)
+
+// ProfileEnv is the environment variable that selects a profile when no
+// --profile flag is given
+const ProfileEnv = "CLAUDE_HUD_PROFILE"
+
+// selectedProfile is the profile chosen with --profile; it wins over
+// $CLAUDE_HUD_PROFILE and the file's own profile setting
+var selectedProfile atomic.Pointer[string]
+
+// SelectProfile chooses the profile configs are loaded with from now on;
+// empty leaves the choice to $CLAUDE_HUD_PROFILE and the config file
+func SelectProfile(name string) {
+	selectedProfile.Store(&name)
+}
+
+// profileName returns the profile to apply to c
+func (c *Config) profileName() string {
+	if name := selectedProfile.Load(); name != nil && *name != "" {
+		return *name
+	}
+	if name := os.Getenv(ProfileEnv); name != "" {
+		return name
+	}
+	return c.Profile
+}
+
+// applyProfile lays the selected profile over the rest of the file. A
+// profile sets only the keys it lists: a nested object such as layout is
+// merged key by key, while a list such as layout.lines, or the options of a
+// section, replace the file's
+func (c *Config) applyProfile() {
+	name := c.profileName()
+	if name == "" {
+		return
+	}
+	node, ok := c.Profiles[name]
+	if !ok {
+		c.profileErr = fmt.Errorf("no profile %q in the config file (have: %v)", name, render.Keys(c.Profiles))
+		return
+	}
+	// Check the whole profile first, so a bad one is not half applied
+	if err := node.Decode(&Config{}); err != nil {
+		c.profileErr = fmt.Errorf("profile %q: %w", name, err)
+		return
+	}
+	profiles := c.Profiles
+	_ = node.Decode(c)
+	c.Profiles = profiles // A profile cannot redefine profiles
+	c.ActiveProfile = name
+}
+
+// ProfileError reports a profile that was selected but could not be
+// applied, leaving the config without it
+func (c *Config) ProfileError() error {
+	return c.profileErr
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const profilesYAML = `
+profile: full
+refresh_interval_ms: 1000
+colors:
+  primary: "#89b4fa"
+layout:
+  lines:
+    - sections: [model, context, cost]
+sections:
+  cost:
+    show_rate: true
+profiles:
+  minimal:
+    layout:
+      lines:
+        - sections: [model]
+    colors:
+      muted: "#6c7086"
+    sections:
+      clock:
+        seconds: true
+  full:
+    max_lines: 6
+  broken:
+    max_lines: many
+`
+
+// loadProfile loads profilesYAML with the given --profile selection
+func loadProfile(t *testing.T, selected string) *Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(profilesYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	SelectProfile(selected)
+	t.Cleanup(func() { SelectProfile("") })
+	return LoadFromPath(path)
+}
+
+func TestProfiles(t *testing.T) {
+	t.Setenv(ProfileEnv, "")
+
+	cfg := loadProfile(t, "minimal")
+	if cfg.ActiveProfile != "minimal" || cfg.ProfileError() != nil {
+		t.Fatalf("ActiveProfile = %q, error %v", cfg.ActiveProfile, cfg.ProfileError())
+	}
+	if got := cfg.GetEnabledSections(); len(got) != 1 || got[0] != "model" {
+		t.Errorf("sections = %v, want the profile's [model]", got)
+	}
+	// Keys the profile leaves out keep the file's values
+	if cfg.Colors.Muted != "#6c7086" || cfg.Colors.Primary != "#89b4fa" || cfg.RefreshIntervalMs != 1000 {
+		t.Errorf("colors = %+v, refresh %d; want the profile's muted over the file's primary", cfg.Colors, cfg.RefreshIntervalMs)
+	}
+	if !cfg.Sections.Get("cost").Bool("show_rate", false) || !cfg.Sections.Get("clock").Bool("seconds", false) {
+		t.Errorf("sections = %v, want the profile's clock alongside the file's cost", cfg.Sections)
+	}
+	if len(cfg.Profiles) != 3 {
+		t.Errorf("%d profiles kept, want 3", len(cfg.Profiles))
+	}
+}
+
+func TestProfiles_Selection(t *testing.T) {
+	t.Setenv(ProfileEnv, "")
+	if cfg := loadProfile(t, ""); cfg.ActiveProfile != "full" || cfg.MaxLines != 6 {
+		t.Errorf("without a selection: profile %q, max_lines %d; want the file's full", cfg.ActiveProfile, cfg.MaxLines)
+	}
+
+	t.Setenv(ProfileEnv, "minimal")
+	if cfg := loadProfile(t, ""); cfg.ActiveProfile != "minimal" {
+		t.Errorf("with %s: profile %q, want minimal", ProfileEnv, cfg.ActiveProfile)
+	}
+	if cfg := loadProfile(t, "full"); cfg.ActiveProfile != "full" {
+		t.Errorf("--profile over %s: profile %q, want full", ProfileEnv, cfg.ActiveProfile)
+	}
+}
+
+func TestProfiles_Errors(t *testing.T) {
+	t.Setenv(ProfileEnv, "")
+
+	cfg := loadProfile(t, "demo")
+	if cfg.ProfileError() == nil || cfg.ActiveProfile != "" {
+		t.Errorf("unknown profile: error %v, active %q", cfg.ProfileError(), cfg.ActiveProfile)
+	}
+
+	cfg = loadProfile(t, "broken")
+	if cfg.ProfileError() == nil || cfg.ActiveProfile != "" || cfg.MaxLines != 4 {
+		t.Errorf("broken profile: error %v, active %q, max_lines %d; want it left out", cfg.ProfileError(), cfg.ActiveProfile, cfg.MaxLines)
+	}
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// SectionsConfig holds per-section options keyed by section name
+//
+//	sections:
+//	  zaiusage:
+//	    enabled: true
+//	    show_reset_times: true
+//
+// Any section may set enabled (default true), order, and the separator,
+// decoration and padding it is shown with; all other keys are
+// section-specific and read through the typed getters on SectionOptions
+type SectionsConfig map[string]SectionOptions
+
+// SectionOptions holds the options of a single section
+type SectionOptions map[string]interface{}
+
+// Get returns the options for a section
+// Getters on a section with no options return their defaults
+func (s SectionsConfig) Get(name string) SectionOptions {
+	return s[name]
+}
+
+// Has reports whether key is set
+func (o SectionOptions) Has(key string) bool {
+	_, ok := o[key]
+	return ok
+}
+
+// Bool returns a boolean option, or def when unset or not a boolean
+func (o SectionOptions) Bool(key string, def bool) bool {
+	switch v := o[key].(type) {
+	case bool:
+		return v
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return def
+}
+
+// Int returns an integer option, or def when unset or not a number
+func (o SectionOptions) Int(key string, def int) int {
+	switch v := o[key].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	case string:
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+// Float returns a floating point option, or def when unset or not a number
+func (o SectionOptions) Float(key string, def float64) float64 {
+	switch v := o[key].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return def
+}
+
+// String returns a string option, or def when unset
+// Scalars of other types are formatted as strings
+func (o SectionOptions) String(key string, def string) string {
+	switch v := o[key].(type) {
+	case nil:
+		return def
+	case string:
+		return v
+	case bool, int, int64, float64:
+		return fmt.Sprint(v)
+	}
+	return def
+}
+
+// Duration returns an option given in milliseconds, or def when unset
+func (o SectionOptions) Duration(key string, def time.Duration) time.Duration {
+	if !o.Has(key) {
+		return def
+	}
+	ms := o.Int(key, -1)
+	if ms < 0 {
+		return def
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// Strings returns a list option, or def when unset
+// A single string is treated as a one-element list
+func (o SectionOptions) Strings(key string, def []string) []string {
+	switch v := o[key].(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			} else if item != nil {
+				result = append(result, fmt.Sprint(item))
+			}
+		}
+		return result
+	}
+	return def
+}
+
+// Map returns a nested options map, or nil when unset
+func (o SectionOptions) Map(key string) SectionOptions {
+	switch v := o[key].(type) {
+	case map[string]interface{}:
+		return SectionOptions(v)
+	case SectionOptions:
+		return v
+	}
+	return nil
+}
+
+// SectionOptions returns the options configured for a section
+func (c *Config) SectionOptions(name string) SectionOptions {
+	return c.Sections.Get(name)
+}
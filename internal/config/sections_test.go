@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSectionsConfig_FromYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	yamlContent := `
+layout:
+  lines:
+    - sections: [model, contextbar, duration, zaiusage]
+sections:
+  duration:
+    enabled: false
+  zaiusage:
+    show_reset_times: true
+    warn_percent: 80
+    poll_ms: 1500
+    labels: [session, weekly]
+`
+	if err := os.WriteFile(path, []byte(yamlContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := LoadFromPath(path)
+
+	if cfg.IsSectionEnabled("duration") {
+		t.Error("duration should be disabled by sections.duration.enabled")
+	}
+	if !cfg.IsSectionEnabled("model") {
+		t.Error("model should stay enabled")
+	}
+	if got := cfg.GetEnabledSections(); len(got) != 3 {
+		t.Errorf("GetEnabledSections() = %v, want 3 sections", got)
+	}
+
+	opts := cfg.SectionOptions("zaiusage")
+	if !opts.Bool("show_reset_times", false) {
+		t.Error("show_reset_times should be true")
+	}
+	if got := opts.Int("warn_percent", 0); got != 80 {
+		t.Errorf("warn_percent = %d, want 80", got)
+	}
+	if got := opts.Duration("poll_ms", time.Second); got != 1500*time.Millisecond {
+		t.Errorf("poll_ms = %v, want 1.5s", got)
+	}
+	if got := opts.Strings("labels", nil); len(got) != 2 || got[1] != "weekly" {
+		t.Errorf("labels = %v", got)
+	}
+}
+
+func TestSectionOptions_Defaults(t *testing.T) {
+	var opts SectionOptions // unconfigured section
+
+	if !opts.Bool("enabled", true) {
+		t.Error("Bool default not returned")
+	}
+	if opts.Int("order", 7) != 7 || opts.Float("ratio", 0.5) != 0.5 {
+		t.Error("numeric defaults not returned")
+	}
+	if opts.String("format", "x") != "x" || opts.Duration("ttl", time.Minute) != time.Minute {
+		t.Error("string/duration defaults not returned")
+	}
+
+	// Wrong types fall back to the default rather than panicking
+	opts = SectionOptions{"enabled": "yes please", "order": []interface{}{1}}
+	if !opts.Bool("enabled", true) || opts.Int("order", 3) != 3 {
+		t.Error("mistyped options should use defaults")
+	}
+}
+
+func TestGetEnabledSections_Order(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Layout.Lines = nil
+	cfg.Sections = SectionsConfig{
+		"sysinfo": {"order": 0},
+		"beads":   {"enabled": false},
+	}
+
+	got := cfg.GetEnabledSections()
+	if len(got) == 0 || got[0] != "sysinfo" {
+		t.Errorf("sysinfo should come first, got %v", got)
+	}
+	for _, name := range got {
+		if name == "beads" {
+			t.Error("beads should be disabled")
+		}
+	}
+}
@@ -0,0 +1,274 @@
+// Package crash keeps count of the panics claude-hud recovered from and the
+// warnings and errors it logged, across runs. Claude Code starts a new
+// process for every statusline render and discards its stderr, so without a
+// record these go unnoticed; doctor reports them and bugreport bundles them
+package crash
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+	"github.com/ll931217/claude-hud-enhanced/internal/filecache"
+	"github.com/ll931217/claude-hud-enhanced/internal/render"
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+)
+
+// FileName is the record's file name in the state directory
+const FileName = "crashes.json"
+
+// maxLogEntries is how many warnings and errors the record keeps
+const maxLogEntries = 50
+
+// maxFailures is how many failures the record keeps of each operation, more
+// than any error budget allows
+const maxFailures = 100
+
+// Panic counts the panics recovered in one operation, e.g. "section.cost"
+type Panic struct {
+	Count   int       `json:"count"`
+	First   time.Time `json:"first"`
+	Last    time.Time `json:"last"`
+	Message string    `json:"message"` // The latest panic value
+}
+
+// LogEntry is a logged warning or error
+type LogEntry struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Op      string    `json:"op,omitempty"`
+	Message string    `json:"message"`
+}
+
+// Disabled is an operation turned off after failing too often, e.g. a
+// section over its error budget. Later runs keep it off for a while
+type Disabled struct {
+	At     time.Time `json:"at"`
+	Reason string    `json:"reason"`
+}
+
+// Record is what the crash file holds
+type Record struct {
+	Panics   map[string]*Panic      `json:"panics"`             // By operation
+	Disabled map[string]*Disabled   `json:"disabled,omitempty"` // The latest time each operation was disabled
+	Failures map[string][]time.Time `json:"failures,omitempty"` // Error budget failures since the last disable, oldest first
+	Log      []LogEntry             `json:"log"`                // The latest warnings and errors, oldest first
+}
+
+// PanicCount returns the number of panics recorded across operations
+func (r *Record) PanicCount() int {
+	total := 0
+	for _, p := range r.Panics {
+		total += p.Count
+	}
+	return total
+}
+
+// Operations returns the operations that panicked, most recent first
+func (r *Record) Operations() []string {
+	ops := render.Keys(r.Panics)
+	slices.SortStableFunc(ops, func(a, b string) int {
+		return render.Newest(r.Panics[a].Last, r.Panics[b].Last)
+	})
+	return ops
+}
+
+// Recorder collects this process's panics, failures and disabled
+// operations until they are flushed to a store
+type Recorder struct {
+	mu        sync.Mutex
+	panics    []panicEvent
+	failures  []failureEvent
+	disabled  map[string]Disabled
+	onFailure func()
+}
+
+type panicEvent struct {
+	op      string
+	message string
+	at      time.Time
+}
+
+type failureEvent struct {
+	op string
+	at time.Time
+}
+
+// Install creates a recorder observing every panic recovered, every failure
+// counted against an error budget and every operation disabled in the process
+func Install() *Recorder {
+	r := &Recorder{}
+	errors.SetPanicObserver(r.observe)
+	errors.SetFailureObserver(r.observeFailure)
+	errors.SetDisableObserver(r.observeDisabled)
+	return r
+}
+
+// OnFailure sets fn to be called after each failure or disabled operation
+// observed, e.g. to flush the record at once. A render that hangs may get
+// the process killed before it exits and flushes
+func (r *Recorder) OnFailure(fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onFailure = fn
+}
+
+func (r *Recorder) observe(op string, panicValue interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.panics = append(r.panics, panicEvent{op: op, message: fmt.Sprint(panicValue), at: time.Now()})
+}
+
+func (r *Recorder) observeFailure(op string, at time.Time) {
+	r.mu.Lock()
+	r.failures = append(r.failures, failureEvent{op: op, at: at})
+	onFailure := r.onFailure
+	r.mu.Unlock()
+	if onFailure != nil {
+		onFailure()
+	}
+}
+
+func (r *Recorder) observeDisabled(op, reason string) {
+	r.mu.Lock()
+	if r.disabled == nil {
+		r.disabled = make(map[string]Disabled)
+	}
+	r.disabled[op] = Disabled{At: time.Now(), Reason: reason}
+	onFailure := r.onFailure
+	r.mu.Unlock()
+	if onFailure != nil {
+		onFailure()
+	}
+}
+
+// Flush adds the panics, failures and disabled operations observed since
+// the last flush and the given log entries to the store. Nothing is written
+// when there is nothing to add, so renders that went well cost no disk access
+func (r *Recorder) Flush(store *Store, entries []errors.Entry) error {
+	r.mu.Lock()
+	panics, failures, disabled := r.panics, r.failures, r.disabled
+	r.panics, r.failures, r.disabled = nil, nil, nil
+	r.mu.Unlock()
+	if len(panics) == 0 && len(failures) == 0 && len(disabled) == 0 && len(entries) == 0 {
+		return nil
+	}
+
+	return store.Update(func(record *Record) {
+		for _, event := range panics {
+			p, ok := record.Panics[event.op]
+			if !ok {
+				p = &Panic{First: event.at}
+				record.Panics[event.op] = p
+			}
+			p.Count++
+			p.Last = event.at
+			p.Message = event.message
+		}
+		for _, event := range failures {
+			if record.Failures == nil {
+				record.Failures = make(map[string][]time.Time)
+			}
+			times := append(record.Failures[event.op], event.at)
+			if len(times) > maxFailures {
+				times = times[len(times)-maxFailures:]
+			}
+			record.Failures[event.op] = times
+		}
+		for op, d := range disabled {
+			if record.Disabled == nil {
+				record.Disabled = make(map[string]*Disabled)
+			}
+			record.Disabled[op] = &d
+			delete(record.Failures, op) // Counted afresh once the operation is back on
+		}
+		for _, e := range entries {
+			record.Log = append(record.Log, LogEntry{Time: e.Time, Level: e.Level.String(), Op: e.Op, Message: e.Message})
+		}
+		if len(record.Log) > maxLogEntries {
+			record.Log = record.Log[len(record.Log)-maxLogEntries:]
+		}
+	})
+}
+
+// Store reads and writes the crash record with cross-process locking
+type Store struct {
+	path string
+}
+
+// NewStore creates a store backed by the file at path
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// DefaultStore returns a store in the state directory
+func DefaultStore() (*Store, error) {
+	dir, err := session.StateDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(filepath.Join(dir, FileName)), nil
+}
+
+// Path returns the record's file path
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load reads the record; a missing file yields an empty one
+func (s *Store) Load() (*Record, error) {
+	record := &Record{Panics: make(map[string]*Panic)}
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return record, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, record); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", s.path, err)
+	}
+	if record.Panics == nil {
+		record.Panics = make(map[string]*Panic)
+	}
+	return record, nil
+}
+
+// Update applies fn to the record under an exclusive lock and writes the result
+func (s *Store) Update(fn func(*Record)) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+
+	// Statusline renders run concurrently in several sessions
+	lock, err := os.OpenFile(s.path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+	if err := syscall.Flock(int(lock.Fd()), syscall.LOCK_EX); err != nil {
+		return err
+	}
+	defer syscall.Flock(int(lock.Fd()), syscall.LOCK_UN)
+
+	record, err := s.Load()
+	if err != nil {
+		// A corrupt record is not worth failing over - start over
+		record = &Record{Panics: make(map[string]*Panic)}
+	}
+	fn(record)
+	return filecache.Save(s.path, record)
+}
+
+// Reset clears the record
+func (s *Store) Reset() error {
+	return s.Update(func(record *Record) {
+		*record = Record{Panics: make(map[string]*Panic)}
+	})
+}
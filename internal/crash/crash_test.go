@@ -0,0 +1,98 @@
+package crash
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+)
+
+func TestRecorderFlush(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), FileName))
+	r := &Recorder{}
+
+	// Nothing to add: the file is not created
+	if err := r.Flush(store, nil); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if _, err := os.Stat(store.Path()); !os.IsNotExist(err) {
+		t.Errorf("expected no record file, got %v", err)
+	}
+
+	r.observe("section.cost", "index out of range")
+	r.observe("section.cost", "nil map")
+	r.observe("section.git", "boom")
+	failedAt := time.Now()
+	r.observeFailure("section.cost", failedAt)
+	r.observeFailure("section.git", failedAt)
+	r.observeDisabled("section.cost", "5 failures in 1m0s")
+	entry := errors.Entry{Time: time.Now(), Level: errors.LevelWarn, Op: "config", Message: "unknown key"}
+	if err := r.Flush(store, []errors.Entry{entry}); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	// A later run adds to the counts
+	r.observe("section.cost", "again")
+	if err := r.Flush(store, nil); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	record, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := record.PanicCount(); got != 4 {
+		t.Errorf("expected 4 panics, got %d", got)
+	}
+	cost := record.Panics["section.cost"]
+	if cost == nil || cost.Count != 3 || cost.Message != "again" {
+		t.Errorf("unexpected section.cost record: %+v", cost)
+	}
+	if d := record.Disabled["section.cost"]; d == nil || d.Reason != "5 failures in 1m0s" {
+		t.Errorf("unexpected section.cost disable: %+v", d)
+	}
+	if got := record.Failures["section.git"]; len(got) != 1 || !got[0].Equal(failedAt) {
+		t.Errorf("unexpected section.git failures: %v", got)
+	}
+	if got, ok := record.Failures["section.cost"]; ok {
+		t.Errorf("section.cost failures kept after it was disabled: %v", got)
+	}
+	if ops := record.Operations(); len(ops) != 2 || ops[0] != "section.cost" {
+		t.Errorf("expected section.cost first, got %v", ops)
+	}
+	if len(record.Log) != 1 || record.Log[0].Message != "unknown key" {
+		t.Errorf("unexpected log: %+v", record.Log)
+	}
+
+	if err := store.Reset(); err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+	if record, _ := store.Load(); record.PanicCount() != 0 || len(record.Disabled) != 0 || len(record.Failures) != 0 || len(record.Log) != 0 {
+		t.Errorf("expected an empty record after Reset, got %+v", record)
+	}
+}
+
+func TestRecorderOnFailure(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), FileName))
+	r := &Recorder{}
+	r.OnFailure(func() {
+		if err := r.Flush(store, nil); err != nil {
+			t.Errorf("Flush: %v", err)
+		}
+	})
+
+	// Written at once, without waiting for the process to flush on exit
+	r.observeFailure("section.git", time.Now())
+	record, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := len(record.Failures["section.git"]); got != 1 {
+		t.Errorf("expected the failure written at once, got %d", got)
+	}
+	r.observeDisabled("section.git", "2 failures in 1m0s")
+	if record, _ := store.Load(); record.Disabled["section.git"] == nil {
+		t.Error("expected the disable written at once")
+	}
+}
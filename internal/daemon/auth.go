@@ -0,0 +1,49 @@
+package daemon
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// TokenEnv overrides the shared token requests to the daemon over TCP carry
+const TokenEnv = "CLAUDE_HUD_DAEMON_TOKEN"
+
+// tokenCookie keeps the token in a browser that opened the dashboard with
+// ?token=, so the dashboard's own requests to the API carry it too
+const tokenCookie = "claude_hud_token"
+
+// requireToken rejects requests that do not carry token: as a bearer token,
+// in the dashboard's cookie or in the token query parameter, which sets the
+// cookie. Anyone who can reach a TCP port could otherwise read the sessions'
+// prompts, paths and commands, and record events of their own
+func requireToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && validToken(bearer, token) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if cookie, err := r.Cookie(tokenCookie); err == nil && validToken(cookie.Value, token) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if query := r.URL.Query().Get("token"); query != "" && validToken(query, token) {
+			http.SetCookie(w, &http.Cookie{
+				Name:     tokenCookie,
+				Value:    token,
+				Path:     "/",
+				HttpOnly: true,
+				SameSite: http.SameSiteStrictMode,
+			})
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("WWW-Authenticate", `Bearer realm="claude-hud"`)
+		http.Error(w, "missing or invalid token", http.StatusUnauthorized)
+	})
+}
+
+// validToken compares tokens in constant time
+func validToken(got, want string) bool {
+	return want != "" && subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
@@ -0,0 +1,140 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AddressEnv overrides the daemon address hooks and statuslines send to
+const AddressEnv = "CLAUDE_HUD_DAEMON"
+
+// Client talks to a running daemon over its Unix socket, or over TCP when
+// it runs on another machine; requests over TCP carry the daemon's token
+type Client struct {
+	http *http.Client
+}
+
+// NewClient creates a client for the daemon at socketPath
+// Requests fail after timeout so hooks never stall Claude Code
+func NewClient(socketPath string, timeout time.Duration) *Client {
+	return newClient("unix", socketPath, "", timeout)
+}
+
+// Dial creates a client for the daemon at addr: "tcp://host:port" or
+// "host:port" for one listening with --http, e.g. through an SSH forward,
+// or "unix:///path" or a path for a socket. Requests over TCP carry token
+func Dial(addr, token string, timeout time.Duration) (*Client, error) {
+	network, address, err := ParseAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	return newClient(network, address, token, timeout), nil
+}
+
+// ParseAddress splits a daemon address into a network and an address for net.Dial
+func ParseAddress(addr string) (network, address string, err error) {
+	switch {
+	case strings.HasPrefix(addr, "unix://"):
+		network, address = "unix", strings.TrimPrefix(addr, "unix://")
+	case strings.HasPrefix(addr, "tcp://"):
+		network, address = "tcp", strings.TrimPrefix(addr, "tcp://")
+	case strings.HasPrefix(addr, "/"):
+		network, address = "unix", addr
+	default:
+		network, address = "tcp", addr
+	}
+	if network == "tcp" {
+		if _, port, err := net.SplitHostPort(address); err != nil || port == "" {
+			return "", "", fmt.Errorf("invalid daemon address %q: want host:port, tcp://host:port or a socket path", addr)
+		}
+	}
+	if address == "" {
+		return "", "", fmt.Errorf("invalid daemon address %q", addr)
+	}
+	return network, address, nil
+}
+
+func newClient(network, address, token string, timeout time.Duration) *Client {
+	var transport http.RoundTripper = &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, address)
+		},
+	}
+	if network == "tcp" && token != "" {
+		transport = bearerTransport{token: token, next: transport}
+	}
+	return &Client{
+		http: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+		},
+	}
+}
+
+// bearerTransport adds the daemon's token to every request
+type bearerTransport struct {
+	token string
+	next  http.RoundTripper
+}
+
+func (t bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return t.next.RoundTrip(req)
+}
+
+// SendEvent forwards a raw hook payload to the daemon for broadcasting
+// A daemon reached over TCP also records it, as it cannot see the state file
+// the hook updated
+func (c *Client) SendEvent(payload []byte) error {
+	resp, err := c.http.Post("http://daemon/v1/events", "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to reach daemon: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("daemon rejected event: %s", resp.Status)
+	}
+	return nil
+}
+
+// SendStatusline reports what a statusline was told about its session, for
+// a daemon that cannot read the session's transcript
+func (c *Client) SendStatusline(report StatuslineReport) error {
+	payload, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Post("http://daemon/v1/statusline", "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to reach daemon: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("daemon rejected statusline: %s", resp.Status)
+	}
+	return nil
+}
+
+// Ping checks whether the daemon is responding
+func (c *Client) Ping() error {
+	resp, err := c.http.Get("http://daemon/v1/health")
+	if err != nil {
+		return fmt.Errorf("failed to reach daemon: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("daemon unhealthy: %s", resp.Status)
+	}
+	return nil
+}
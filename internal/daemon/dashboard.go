@@ -0,0 +1,26 @@
+package daemon
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+)
+
+// dashboardPage is the web dashboard, a single page fed by the event stream
+//
+//go:embed dashboard.html
+var dashboardPage []byte
+
+// handleDashboard serves the web dashboard
+func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	if _, err := w.Write(dashboardPage); err != nil {
+		errors.Warn("daemon", "failed to write response: %v", err)
+	}
+}
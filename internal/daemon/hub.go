@@ -0,0 +1,64 @@
+package daemon
+
+import (
+	"sync"
+)
+
+// subscriberBuffer is how many messages a slow subscriber may lag behind
+// before messages start being dropped for it
+const subscriberBuffer = 32
+
+// Hub fans out messages to all current subscribers
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[chan []byte]struct{}
+}
+
+// NewHub creates an empty hub
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[chan []byte]struct{}),
+	}
+}
+
+// Subscribe registers a new subscriber
+// The returned cancel function must be called to release it
+func (h *Hub) Subscribe() (<-chan []byte, func()) {
+	ch := make(chan []byte, subscriberBuffer)
+
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers, ch)
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+	return ch, cancel
+}
+
+// Publish sends msg to every subscriber without blocking
+// Subscribers whose buffer is full miss the message
+func (h *Hub) Publish(msg []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers {
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
+
+// Count returns the number of active subscribers
+func (h *Hub) Count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscribers)
+}
@@ -0,0 +1,70 @@
+package daemon
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+)
+
+// StatuslineReport is what a statusline on another machine was told about
+// its session by Claude Code, sent so the daemon can show a session whose
+// transcript it cannot read
+type StatuslineReport struct {
+	SessionID      string `json:"session_id,omitempty"`      // Taken from the transcript name when empty
+	TranscriptPath string `json:"transcript_path,omitempty"` // Only names the session; never read
+	Cwd            string `json:"cwd,omitempty"`
+	Host           string `json:"host,omitempty"`
+	Model          string `json:"model,omitempty"`
+	ContextTokens  int    `json:"context_tokens,omitempty"`
+	ContextSize    int    `json:"context_size,omitempty"`
+}
+
+// handleStatusline records a statusline report from another machine
+func (s *Server) handleStatusline(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var report StatuslineReport
+	if err := json.NewDecoder(io.LimitReader(r.Body, 64*1024)).Decode(&report); err != nil {
+		http.Error(w, "invalid statusline report: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	id := report.SessionID
+	if id == "" && report.TranscriptPath != "" {
+		// Transcripts are named after their session
+		id = strings.TrimSuffix(filepath.Base(report.TranscriptPath), ".jsonl")
+	}
+	if id == "" {
+		http.Error(w, "statusline report names no session", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	if err := s.store.Update(func(state *session.State) error {
+		sess := state.Session(id)
+		if sess.Cwd == "" {
+			sess.Cwd = report.Cwd
+		}
+		if sess.LastEventAt.IsZero() {
+			sess.LastEventAt = now // Without hooks, the report is all there is
+		}
+		sess.Remote = &session.RemoteStatus{
+			Host:          report.Host,
+			Model:         report.Model,
+			ContextTokens: report.ContextTokens,
+			ContextSize:   report.ContextSize,
+			ReportedAt:    now,
+		}
+		return nil
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,318 @@
+// Package daemon implements the long-running claude-hud daemon that
+// receives hook events and broadcasts session state to subscribers
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+	"github.com/ll931217/claude-hud-enhanced/internal/git"
+	"github.com/ll931217/claude-hud-enhanced/internal/hook"
+	"github.com/ll931217/claude-hud-enhanced/internal/render"
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+// SocketFileName is the daemon's Unix socket name inside the state directory
+const SocketFileName = "daemon.sock"
+
+// SocketPath returns the default daemon socket path
+func SocketPath() (string, error) {
+	dir, err := session.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, SocketFileName), nil
+}
+
+// Server serves session state and hook events over a Unix socket
+type Server struct {
+	store      *session.Store
+	socketPath string
+	hub        *Hub
+	httpServer *http.Server
+
+	transcripts    func(path string) *transcript.Parser // Parsers for the status API
+	streamInterval time.Duration                        // Overrides streamInterval when set
+}
+
+// NewServer creates a daemon server backed by store, listening on socketPath
+func NewServer(store *session.Store, socketPath string) *Server {
+	s := &Server{
+		store:      store,
+		socketPath: socketPath,
+		hub:        NewHub(),
+
+		transcripts: transcript.NewParser,
+	}
+	s.httpServer = &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return s
+}
+
+// Hub returns the server's broadcast hub
+func (s *Server) Hub() *Hub {
+	return s.hub
+}
+
+// Handler returns the HTTP handler exposing the daemon API on the socket,
+// to clients on this machine
+func (s *Server) Handler() http.Handler {
+	return s.handler(false)
+}
+
+// remoteHandler returns the HTTP handler exposing the daemon API over TCP,
+// to clients that carry token. Whatever such a client sends is from another
+// machine, so its events are recorded and their transcript paths dropped
+// whatever the request asks for
+func (s *Server) remoteHandler(token string) http.Handler {
+	return requireToken(token, s.handler(true))
+}
+
+// handler returns the HTTP handler; remote serves clients on other machines
+func (s *Server) handler(remote bool) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/health", s.handleHealth)
+	mux.HandleFunc("/v1/state", s.handleState)
+	mux.HandleFunc("/v1/events", func(w http.ResponseWriter, r *http.Request) {
+		s.handleEvents(w, r, remote)
+	})
+	mux.HandleFunc("/v1/metrics", s.handleMetrics)
+	mux.HandleFunc("/v1/status", s.handleStatus)
+	mux.HandleFunc("/v1/stream", s.handleStream)
+	mux.HandleFunc("/v1/sessions", s.handleSessions)
+	mux.HandleFunc("/v1/statusline", s.handleStatusline)
+	mux.HandleFunc("/{$}", s.handleDashboard)
+	return mux
+}
+
+// Serve listens on the socket and serves until ctx is cancelled
+func (s *Server) Serve(ctx context.Context) error {
+	if err := os.MkdirAll(filepath.Dir(s.socketPath), 0755); err != nil {
+		return fmt.Errorf("failed to create socket directory: %w", err)
+	}
+
+	// Remove a stale socket left behind by a crashed daemon
+	if err := removeStaleSocket(s.socketPath); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("unix", s.socketPath)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.socketPath, err)
+	}
+	defer os.Remove(s.socketPath)
+
+	errors.Info("daemon", "listening on %s", s.socketPath)
+	return serve(ctx, s.httpServer, listener)
+}
+
+// ServeListener serves the API and the dashboard on listener, such as a TCP
+// listener for browsers on other devices, until ctx is cancelled. Every
+// request must carry token, which may not be empty
+func (s *Server) ServeListener(ctx context.Context, listener net.Listener, token string) error {
+	if token == "" {
+		return fmt.Errorf("serving on %s needs a shared token; set daemon.token or $%s", listener.Addr(), TokenEnv)
+	}
+	errors.Info("daemon", "serving the dashboard on http://%s/", listener.Addr())
+	return serve(ctx, &http.Server{
+		Handler:           s.remoteHandler(token),
+		ReadHeaderTimeout: 5 * time.Second,
+	}, listener)
+}
+
+// serve runs server on listener until ctx is cancelled
+func serve(ctx context.Context, server *http.Server, listener net.Listener) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Serve(listener)
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("failed to shut down daemon: %w", err)
+		}
+		return nil
+	case err := <-errCh:
+		if err == http.ErrServerClosed {
+			return nil
+		}
+		return err
+	}
+}
+
+// removeStaleSocket deletes socketPath unless another daemon is accepting on it
+func removeStaleSocket(socketPath string) error {
+	if _, err := os.Stat(socketPath); os.IsNotExist(err) {
+		return nil
+	}
+	conn, err := net.DialTimeout("unix", socketPath, 200*time.Millisecond)
+	if err == nil {
+		conn.Close()
+		return fmt.Errorf("daemon already running on %s", socketPath)
+	}
+	if err := os.Remove(socketPath); err != nil {
+		return fmt.Errorf("failed to remove stale socket: %w", err)
+	}
+	return nil
+}
+
+// handleHealth reports that the daemon is alive
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{
+		"status":      "ok",
+		"subscribers": s.hub.Count(),
+	})
+}
+
+// handleState returns the full shared session state
+func (s *Server) handleState(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	state, err := s.store.Load()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, state)
+}
+
+// handleMetrics exports the repository stats HUD processes counted, in the
+// Prometheus text format
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	stats, err := git.LoadRepoStats(filepath.Join(filepath.Dir(s.store.Path()), git.RepoStatsFileName))
+	if err != nil && !os.IsNotExist(err) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	repos := render.Keys(stats)
+
+	var b strings.Builder
+	b.WriteString("# HELP claude_hud_repo_files Files tracked at HEAD.\n")
+	b.WriteString("# TYPE claude_hud_repo_files gauge\n")
+	for _, repo := range repos {
+		fmt.Fprintf(&b, "claude_hud_repo_files{repo=%s} %d\n", metricLabel(repo), stats[repo].Files)
+	}
+	b.WriteString("# HELP claude_hud_repo_bytes Total size of the files tracked at HEAD.\n")
+	b.WriteString("# TYPE claude_hud_repo_bytes gauge\n")
+	for _, repo := range repos {
+		fmt.Fprintf(&b, "claude_hud_repo_bytes{repo=%s} %d\n", metricLabel(repo), stats[repo].Bytes)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if _, err := w.Write([]byte(b.String())); err != nil {
+		errors.Warn("daemon", "failed to write response: %v", err)
+	}
+}
+
+// metricLabel quotes a label value, escaping what the text format requires
+func metricLabel(value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+	return `"` + value + `"`
+}
+
+// handleEvents accepts hook events (POST) or streams them to a subscriber (GET)
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request, remote bool) {
+	switch r.Method {
+	case http.MethodPost:
+		s.receiveEvent(w, r, remote)
+	case http.MethodGet:
+		s.streamEvents(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// receiveEvent validates a hook payload and broadcasts it to subscribers
+// A local hook process has already persisted it to the state file; the
+// daemon records those from another machine itself
+func (s *Server) receiveEvent(w http.ResponseWriter, r *http.Request, remote bool) {
+	ev, err := hook.Parse(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if remote {
+		// The transcript is on the hook's machine; the same path here names
+		// some other file, which the daemon must not read for it
+		ev.TranscriptPath = ""
+		if err := s.store.Update(func(state *session.State) error {
+			ev.Apply(state, time.Now())
+			if sess := state.Session(ev.SessionID); sess.Remote == nil {
+				sess.Remote = &session.RemoteStatus{}
+			}
+			return nil
+		}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	msg, err := json.Marshal(ev)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.hub.Publish(msg)
+
+	errors.Debug("daemon", "broadcast %s for session %s", ev.HookEventName, ev.SessionID)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// streamEvents writes each broadcast event as a line of JSON until the client disconnects
+func (s *Server) streamEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	ch, cancel := s.hub.Subscribe()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			if _, err := w.Write(append(msg, '\n')); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		errors.Warn("daemon", "failed to write response: %v", err)
+	}
+}
@@ -0,0 +1,615 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/git"
+	"github.com/ll931217/claude-hud-enhanced/internal/hook"
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+)
+
+func TestHub_PublishSubscribe(t *testing.T) {
+	hub := NewHub()
+	ch, cancel := hub.Subscribe()
+
+	if hub.Count() != 1 {
+		t.Fatalf("Count() = %d, want 1", hub.Count())
+	}
+
+	hub.Publish([]byte("hello"))
+	select {
+	case msg := <-ch:
+		if string(msg) != "hello" {
+			t.Errorf("got %q, want hello", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for message")
+	}
+
+	cancel()
+	cancel() // idempotent
+	if hub.Count() != 0 {
+		t.Errorf("Count() after cancel = %d, want 0", hub.Count())
+	}
+}
+
+func TestHub_SlowSubscriberDoesNotBlock(t *testing.T) {
+	hub := NewHub()
+	_, cancel := hub.Subscribe()
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < subscriberBuffer*2; i++ {
+			hub.Publish([]byte("x"))
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked on a full subscriber")
+	}
+}
+
+func TestServer_StateAndEvents(t *testing.T) {
+	store := session.NewStore(filepath.Join(t.TempDir(), "state.json"))
+	if err := store.Update(func(s *session.State) error {
+		s.Session("s1").Cwd = "/project"
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := NewServer(store, "")
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/v1/state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := new(strings.Builder)
+	_, _ = bufio.NewReader(resp.Body).WriteTo(body)
+	resp.Body.Close()
+	if !strings.Contains(body.String(), `"/project"`) {
+		t.Errorf("state response missing session: %s", body)
+	}
+
+	// Subscribe, then post an event and expect it on the stream
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/v1/events", nil)
+	stream, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Body.Close()
+
+	post, err := http.Post(ts.URL+"/v1/events", "application/json",
+		strings.NewReader(`{"session_id":"s1","hook_event_name":"Stop"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	post.Body.Close()
+	if post.StatusCode != http.StatusAccepted {
+		t.Fatalf("POST status = %d, want 202", post.StatusCode)
+	}
+
+	line, err := bufio.NewReader(stream.Body).ReadString('\n')
+	if err != nil {
+		t.Fatalf("reading stream: %v", err)
+	}
+	if !strings.Contains(line, `"hook_event_name":"Stop"`) {
+		t.Errorf("unexpected streamed event: %s", line)
+	}
+
+	bad, err := http.Post(ts.URL+"/v1/events", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bad.Body.Close()
+	if bad.StatusCode != http.StatusBadRequest {
+		t.Errorf("invalid event status = %d, want 400", bad.StatusCode)
+	}
+}
+
+func TestServer_ServeOverUnixSocket(t *testing.T) {
+	// Unix socket paths are length-limited, so keep the directory short
+	dir, err := os.MkdirTemp("", "hud")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	socketPath := filepath.Join(dir, SocketFileName)
+
+	store := session.NewStore(filepath.Join(dir, "state.json"))
+	srv := NewServer(store, socketPath)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.Serve(ctx) }()
+
+	client := NewClient(socketPath, time.Second)
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if err := client.Ping(); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("daemon did not become healthy")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if err := client.SendEvent([]byte(`{"session_id":"s1","hook_event_name":"Stop"}`)); err != nil {
+		t.Errorf("SendEvent() error = %v", err)
+	}
+
+	cancel()
+	if err := <-errCh; err != nil {
+		t.Errorf("Serve() error = %v", err)
+	}
+	if _, err := os.Stat(socketPath); !os.IsNotExist(err) {
+		t.Error("socket should be removed on shutdown")
+	}
+}
+
+func TestServer_Metrics(t *testing.T) {
+	dir := t.TempDir()
+	store := session.NewStore(filepath.Join(dir, "state.json"))
+	srv := NewServer(store, "")
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	get := func() string {
+		t.Helper()
+		resp, err := http.Get(ts.URL + "/v1/metrics")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("metrics status = %d, want 200", resp.StatusCode)
+		}
+		body := new(strings.Builder)
+		_, _ = bufio.NewReader(resp.Body).WriteTo(body)
+		return body.String()
+	}
+
+	// Nothing counted yet
+	if body := get(); strings.Contains(body, "{repo=") {
+		t.Errorf("metrics before any count = %s", body)
+	}
+
+	stats := `{"/src/hud":{"head":"abc","files":1234,"bytes":58720256,"computed_at":"2026-03-01T12:00:00Z"}}`
+	if err := os.WriteFile(filepath.Join(dir, git.RepoStatsFileName), []byte(stats), 0644); err != nil {
+		t.Fatal(err)
+	}
+	body := get()
+	for _, want := range []string{
+		`claude_hud_repo_files{repo="/src/hud"} 1234`,
+		`claude_hud_repo_bytes{repo="/src/hud"} 58720256`,
+		"# TYPE claude_hud_repo_bytes gauge",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestServer_StatusAndDashboard(t *testing.T) {
+	dir := t.TempDir()
+	transcriptPath := filepath.Join(dir, "s1.jsonl")
+	content := `{"type":"assistant","timestamp":"2026-01-11T03:00:00Z","message":{"role":"assistant","usage":{"input_tokens":50000,"output_tokens":100},"content":[{"type":"tool_use","id":"t1","name":"TodoWrite","input":{"todos":[{"content":"Fix login","status":"completed"},{"content":"Add tests","status":"in_progress"}]}}]}}
+{"type":"assistant","timestamp":"2026-01-11T03:00:05Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"go test ./..."}}]}}
+`
+	if err := os.WriteFile(transcriptPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	store := session.NewStore(filepath.Join(dir, "state.json"))
+	if err := store.Update(func(s *session.State) error {
+		sess := s.Session("s1")
+		sess.TranscriptPath = transcriptPath
+		sess.Name = "auth"
+		sess.LastEventAt = time.Now()
+		sess.Approval = &session.ApprovalRequest{Message: "Claude needs your permission to use Bash"}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := NewServer(store, "")
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	for _, query := range []string{"", "?session=s1", "?session=auth"} {
+		resp, err := http.Get(ts.URL + "/v1/status" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var status SessionStatus
+		err = json.NewDecoder(resp.Body).Decode(&status)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.SessionID != "s1" || !status.Approval || status.ContextTokens != 50000 || status.Cost == 0 {
+			t.Errorf("status%s = %+v", query, status)
+		}
+		if len(status.Tools) != 2 || status.Tools[0].Name != "Bash" || status.Tools[0].Status != "running" || status.Tools[0].Target != "go test ./..." {
+			t.Errorf("status%s tools = %+v, want the running Bash call first", query, status.Tools)
+		}
+		if len(status.Todos) != 2 || status.Todos[0].Content != "Fix login" || status.Todos[1].Status != "in_progress" {
+			t.Errorf("status%s todos = %+v", query, status.Todos)
+		}
+	}
+
+	resp, err := http.Get(ts.URL + "/v1/status?session=missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown session status = %d, want 404", resp.StatusCode)
+	}
+
+	resp, err = http.Get(ts.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := new(strings.Builder)
+	_, _ = bufio.NewReader(resp.Body).WriteTo(body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body.String(), "/v1/status") {
+		t.Errorf("dashboard = %d %.80q, want the page", resp.StatusCode, body)
+	}
+	resp, err = http.Get(ts.URL + "/nothing-here")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown path status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestServer_Stream(t *testing.T) {
+	dir := t.TempDir()
+	transcriptPath := filepath.Join(dir, "s1.jsonl")
+	if err := os.WriteFile(transcriptPath, []byte(`{"type":"user","timestamp":"2026-01-11T03:00:00Z","message":{"role":"user","content":"old prompt"}}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	store := session.NewStore(filepath.Join(dir, "state.json"))
+	if err := store.Update(func(s *session.State) error {
+		sess := s.Session("s1")
+		sess.TranscriptPath = transcriptPath
+		sess.LastEventAt = time.Now()
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := NewServer(store, "")
+	srv.streamInterval = 10 * time.Millisecond
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/v1/stream?session=missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown session stream = %d, want 404", resp.StatusCode)
+	}
+
+	resp, err = http.Get(ts.URL + "/v1/stream?session=s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	events := make(chan [2]string, 16)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		var name string
+		for scanner.Scan() {
+			line := scanner.Text()
+			if after, ok := strings.CutPrefix(line, "event: "); ok {
+				name = after
+			} else if after, ok := strings.CutPrefix(line, "data: "); ok {
+				events <- [2]string{name, after}
+			}
+		}
+		close(events)
+	}()
+	next := func(want string) string {
+		t.Helper()
+		select {
+		case ev, ok := <-events:
+			if !ok || ev[0] != want {
+				t.Fatalf("event = %v, want %s", ev, want)
+			}
+			return ev[1]
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no %s event", want)
+			return ""
+		}
+	}
+
+	// The status comes first; what the transcript held before is not replayed
+	var status SessionStatus
+	if err := json.Unmarshal([]byte(next("status")), &status); err != nil || status.SessionID != "s1" {
+		t.Fatalf("status = %+v, %v", status, err)
+	}
+
+	file, err := os.OpenFile(transcriptPath, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(`{"type":"assistant","timestamp":"2026-01-11T03:00:05Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"go test ./..."}}]}}` + "\n" + `{"type":"user","timestamp":"2026-01-11`); err != nil {
+		t.Fatal(err)
+	}
+	var entry TranscriptEvent
+	if err := json.Unmarshal([]byte(next("transcript")), &entry); err != nil || entry.Tool != "Bash" || entry.Status != "running" {
+		t.Fatalf("transcript event = %+v, %v", entry, err)
+	}
+	next("status") // The running call changes the status
+
+	// The partial line is streamed once it is complete
+	if _, err := file.WriteString(`T03:00:07Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t2","content":"ok"}]}}` + "\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(next("transcript")), &entry); err != nil || entry.Status != "completed" || entry.DurationMs != 2000 {
+		t.Fatalf("transcript event = %+v, %v", entry, err)
+	}
+	next("status")
+
+	srv.Hub().Publish([]byte(`{"hook_event_name":"Stop","session_id":"s1"}`))
+	if data := next("hook"); !strings.Contains(data, "Stop") {
+		t.Errorf("hook event = %s", data)
+	}
+}
+
+func TestServer_Sessions(t *testing.T) {
+	dir := t.TempDir()
+	store := session.NewStore(filepath.Join(dir, "state.json"))
+	if err := store.Update(func(s *session.State) error {
+		for i, id := range []string{"s1", "s2", "s3"} {
+			path := filepath.Join(dir, id+".jsonl")
+			content := `{"type":"assistant","timestamp":"2026-01-11T03:00:00Z","message":{"role":"assistant","usage":{"input_tokens":1000,"output_tokens":100},"content":[{"type":"tool_use","id":"a","name":"Read","input":{"file_path":"/a.go"}},{"type":"tool_use","id":"b","name":"Bash","input":{"command":"ls"}}]}}` + "\n"
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				return err
+			}
+			sess := s.Session(id)
+			sess.TranscriptPath = path
+			sess.LastEventAt = time.Now().Add(-time.Duration(i) * time.Hour)
+		}
+		s.Sessions["s2"].Stopped = true
+		s.Sessions["s1"].Approval = &session.ApprovalRequest{}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := NewServer(store, "")
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	get := func(query string) SessionsSummary {
+		t.Helper()
+		resp, err := http.Get(ts.URL + "/v1/sessions" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var summary SessionsSummary
+		if err := json.NewDecoder(resp.Body).Decode(&summary); err != nil {
+			t.Fatal(err)
+		}
+		return summary
+	}
+
+	summary := get("")
+	if summary.Total != 3 || summary.Working != 2 || summary.Approval != 1 || summary.Cost == 0 {
+		t.Errorf("summary = %+v", summary)
+	}
+	if len(summary.Sessions) != 3 || summary.Sessions[0].SessionID != "s1" || summary.Sessions[2].SessionID != "s3" {
+		t.Fatalf("sessions = %+v, want the most recently active first", summary.Sessions)
+	}
+	if tools := summary.Sessions[0].Tools; len(tools) != 1 {
+		t.Errorf("tools = %+v, want only the latest call", tools)
+	}
+
+	if summary := get("?active=90m"); summary.Total != 2 || summary.Sessions[1].SessionID != "s2" {
+		t.Errorf("active summary = %+v, want s1 and s2", summary)
+	}
+
+	resp, err := http.Get(ts.URL + "/v1/sessions?active=soon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bad active = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		addr, network, address string
+	}{
+		{"tcp://127.0.0.1:7878", "tcp", "127.0.0.1:7878"},
+		{"localhost:7878", "tcp", "localhost:7878"},
+		{"[::1]:7878", "tcp", "[::1]:7878"},
+		{"unix:///run/claude-hud.sock", "unix", "/run/claude-hud.sock"},
+		{"/tmp/daemon.sock", "unix", "/tmp/daemon.sock"},
+		{"localhost", "", ""},
+		{"tcp://", "", ""},
+		{"unix://", "", ""},
+	}
+	for _, tt := range tests {
+		network, address, err := ParseAddress(tt.addr)
+		if network != tt.network || address != tt.address || (err != nil) != (tt.network == "") {
+			t.Errorf("ParseAddress(%q) = %q, %q, %v, want %q, %q", tt.addr, network, address, err, tt.network, tt.address)
+		}
+	}
+}
+
+func TestServer_RemoteSession(t *testing.T) {
+	store := session.NewStore(filepath.Join(t.TempDir(), "state.json"))
+	srv := NewServer(store, "")
+	ts := httptest.NewServer(srv.remoteHandler("secret"))
+	defer ts.Close()
+
+	// Nothing is served or recorded without the token
+	stranger, err := Dial(strings.TrimPrefix(ts.URL, "http://"), "guess", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stranger.SendEvent([]byte(`{"hook_event_name":"UserPromptSubmit","session_id":"x1"}`)); err == nil {
+		t.Error("SendEvent() with the wrong token succeeded")
+	}
+	if resp, err := http.Get(ts.URL + "/v1/state"); err != nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("GET /v1/state without a token = %v, %v; want 401", resp.Status, err)
+	} else {
+		resp.Body.Close()
+	}
+
+	client, err := Dial(strings.TrimPrefix(ts.URL, "http://"), "secret", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The hook in the container recorded the event in its own state file
+	if err := client.SendEvent([]byte(`{"hook_event_name":"UserPromptSubmit","session_id":"r1","transcript_path":"/workspace/.claude/r1.jsonl","cwd":"/workspace"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SendStatusline(StatuslineReport{
+		TranscriptPath: "/workspace/.claude/r1.jsonl",
+		Host:           "devbox",
+		Model:          "Opus",
+		ContextTokens:  40000,
+		ContextSize:    200000,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := state.Sessions["x1"]; ok {
+		t.Error("an event with the wrong token was recorded")
+	}
+	sess := state.Sessions["r1"]
+	if sess == nil || sess.LastEvent != "UserPromptSubmit" || sess.Remote == nil || sess.Remote.Host != "devbox" {
+		t.Fatalf("session = %+v, want the remote event and report recorded", sess)
+	}
+	if sess.TranscriptPath != "" {
+		t.Errorf("TranscriptPath = %q, want a path from another machine never kept", sess.TranscriptPath)
+	}
+
+	// A browser opening the dashboard with the token keeps it in a cookie
+	resp, err := http.Get(ts.URL + "/?token=secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	cookies := resp.Cookies()
+	if resp.StatusCode != http.StatusOK || len(cookies) != 1 || !cookies[0].HttpOnly {
+		t.Fatalf("GET /?token= = %s with cookies %v, want 200 and the token cookie", resp.Status, cookies)
+	}
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/v1/status?session=r1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.AddCookie(cookies[0])
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var status SessionStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Host != "devbox" || status.ContextTokens != 40000 || status.ContextSize != 200000 || status.ContextPercent == 0 || status.Error != "" {
+		t.Errorf("status = %+v, want the reported context and no transcript error", status)
+	}
+
+	// A hook on this machine has already recorded its event
+	local := httptest.NewServer(srv.Handler())
+	defer local.Close()
+	resp, err = http.Post(local.URL+"/v1/events", "application/json", strings.NewReader(`{"hook_event_name":"Stop","session_id":"l1"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if state, err := store.Load(); err != nil || state.Sessions["l1"] != nil {
+		t.Errorf("socket event recorded by the daemon (err %v)", err)
+	}
+}
+
+func TestServer_RemoteEventsNeverCarryTranscriptPaths(t *testing.T) {
+	store := session.NewStore(filepath.Join(t.TempDir(), "state.json"))
+	srv := NewServer(store, "")
+	ts := httptest.NewServer(srv.remoteHandler("secret"))
+	defer ts.Close()
+	events, cancel := srv.Hub().Subscribe()
+	defer cancel()
+
+	// Any client with the token, not only ours, and whatever it asks for
+	for _, query := range []string{"", "?record=0"} {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/v1/events"+query,
+			strings.NewReader(`{"hook_event_name":"Stop","session_id":"r1","transcript_path":"/etc/passwd"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusAccepted {
+			t.Fatalf("POST /v1/events%s = %s, want 202", query, resp.Status)
+		}
+
+		select {
+		case msg := <-events:
+			var ev hook.Event
+			if err := json.Unmarshal(msg, &ev); err != nil {
+				t.Fatal(err)
+			}
+			if ev.TranscriptPath != "" {
+				t.Errorf("POST /v1/events%s published transcript path %q", query, ev.TranscriptPath)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("event never published")
+		}
+	}
+
+	state, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sess := state.Sessions["r1"]; sess == nil || sess.Remote == nil || sess.TranscriptPath != "" {
+		t.Errorf("session = %+v, want it recorded as remote without its path", sess)
+	}
+}
@@ -0,0 +1,252 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/template"
+)
+
+// ServiceName identifies the daemon to the service manager
+const ServiceName = "claude-hud"
+
+// launchdLabel is the launchd job label
+const launchdLabel = "com.github.ll931217.claude-hud"
+
+// Service manages the daemon as a systemd user unit (Linux) or launchd agent (macOS)
+type Service struct {
+	// GOOS selects the service manager
+	GOOS string
+	// Executable is the claude-hud binary the service runs
+	Executable string
+	// HomeDir is where user-level unit files are installed
+	HomeDir string
+	// Run executes a service manager command and returns its combined output
+	Run func(name string, args ...string) (string, error)
+}
+
+// NewService creates a service for the current platform and binary
+func NewService() (*Service, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return &Service{
+		GOOS:       runtime.GOOS,
+		Executable: exe,
+		HomeDir:    homeDir,
+		Run:        runCommand,
+	}, nil
+}
+
+// runCommand runs a command and returns its trimmed combined output
+func runCommand(name string, args ...string) (string, error) {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	return strings.TrimSpace(string(out)), err
+}
+
+// supported reports an error for platforms without a known service manager
+func (s *Service) supported() error {
+	switch s.GOOS {
+	case "linux", "darwin":
+		return nil
+	default:
+		return fmt.Errorf("service management is not supported on %s", s.GOOS)
+	}
+}
+
+// UnitPath returns where the unit file or plist is installed
+func (s *Service) UnitPath() string {
+	if s.GOOS == "darwin" {
+		return filepath.Join(s.HomeDir, "Library", "LaunchAgents", launchdLabel+".plist")
+	}
+	return filepath.Join(s.HomeDir, ".config", "systemd", "user", ServiceName+".service")
+}
+
+// systemdQuote quotes a path for an ExecStart line, so paths with spaces
+// stay one argument. Backslashes and quotes are escaped, and % and $ are
+// doubled so systemd does not expand them as specifiers or variables
+func systemdQuote(s string) string {
+	return `"` + systemdEscaper.Replace(s) + `"`
+}
+
+var systemdEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `%`, `%%`, `$`, `$$`)
+
+// xmlEscape escapes text for a plist string element
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s)) // Writes to a buffer never fail
+	return buf.String()
+}
+
+var systemdUnit = template.Must(template.New("unit").Funcs(template.FuncMap{"quote": systemdQuote}).Parse(`[Unit]
+Description=claude-hud daemon
+After=default.target
+
+[Service]
+Type=simple
+ExecStart={{quote .Executable}} daemon
+Restart=on-failure
+RestartSec=2
+
+[Install]
+WantedBy=default.target
+`))
+
+var launchdPlist = template.Must(template.New("plist").Funcs(template.FuncMap{"xml": xmlEscape}).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>{{xml .Label}}</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>{{xml .Executable}}</string>
+		<string>daemon</string>
+	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<true/>
+</dict>
+</plist>
+`))
+
+// UnitFile renders the systemd unit or launchd plist for this service
+func (s *Service) UnitFile() (string, error) {
+	tmpl := systemdUnit
+	if s.GOOS == "darwin" {
+		tmpl = launchdPlist
+	}
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, struct {
+		Executable string
+		Label      string
+	}{s.Executable, launchdLabel})
+	if err != nil {
+		return "", fmt.Errorf("failed to render unit file: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// Install writes the unit file and enables the service so it starts now and at login
+func (s *Service) Install() error {
+	if err := s.supported(); err != nil {
+		return err
+	}
+	content, err := s.UnitFile()
+	if err != nil {
+		return err
+	}
+
+	path := s.UnitPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	if s.GOOS == "darwin" {
+		// Reloading an already loaded job fails; unload first and ignore the result
+		_, _ = s.Run("launchctl", "unload", path)
+		return s.run("launchctl", "load", "-w", path)
+	}
+	if err := s.run("systemctl", "--user", "daemon-reload"); err != nil {
+		return err
+	}
+	return s.run("systemctl", "--user", "enable", "--now", ServiceName+".service")
+}
+
+// Stop stops the running service; it still starts again at next login
+func (s *Service) Stop() error {
+	if err := s.supported(); err != nil {
+		return err
+	}
+	if s.GOOS == "darwin" {
+		return s.run("launchctl", "unload", s.UnitPath())
+	}
+	return s.run("systemctl", "--user", "stop", ServiceName+".service")
+}
+
+// Uninstall stops and disables the service and removes its unit file
+func (s *Service) Uninstall() error {
+	if err := s.supported(); err != nil {
+		return err
+	}
+	path := s.UnitPath()
+	if s.GOOS == "darwin" {
+		_, _ = s.Run("launchctl", "unload", "-w", path)
+	} else {
+		_, _ = s.Run("systemctl", "--user", "disable", "--now", ServiceName+".service")
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	if s.GOOS == "linux" {
+		_, _ = s.Run("systemctl", "--user", "daemon-reload")
+	}
+	return nil
+}
+
+// ServiceStatus describes the installed and running state of the service
+type ServiceStatus struct {
+	Installed bool   // Unit file exists
+	Manager   string // Service manager state as reported by systemctl/launchctl
+}
+
+// Status reports whether the service is installed and what the manager says about it
+func (s *Service) Status() (ServiceStatus, error) {
+	if err := s.supported(); err != nil {
+		return ServiceStatus{}, err
+	}
+
+	var status ServiceStatus
+	if _, err := os.Stat(s.UnitPath()); err == nil {
+		status.Installed = true
+	}
+
+	if s.GOOS == "darwin" {
+		out, err := s.Run("launchctl", "list", launchdLabel)
+		if err != nil {
+			status.Manager = "not loaded"
+		} else if strings.Contains(out, `"PID"`) {
+			status.Manager = "running"
+		} else {
+			status.Manager = "loaded"
+		}
+		return status, nil
+	}
+
+	// is-active exits non-zero for inactive units but still prints the state
+	out, _ := s.Run("systemctl", "--user", "is-active", ServiceName+".service")
+	if out == "" {
+		out = "unknown"
+	}
+	status.Manager = out
+	return status, nil
+}
+
+// run executes a command and folds its output into the error
+func (s *Service) run(name string, args ...string) error {
+	out, err := s.Run(name, args...)
+	if err != nil {
+		if out != "" {
+			return fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, out)
+		}
+		return fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+	}
+	return nil
+}
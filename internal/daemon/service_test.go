@@ -0,0 +1,143 @@
+package daemon
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeRunner records service manager invocations
+type fakeRunner struct {
+	calls  []string
+	output string
+}
+
+func (f *fakeRunner) run(name string, args ...string) (string, error) {
+	f.calls = append(f.calls, name+" "+strings.Join(args, " "))
+	return f.output, nil
+}
+
+func newTestService(t *testing.T, goos string) (*Service, *fakeRunner) {
+	t.Helper()
+	runner := &fakeRunner{}
+	return &Service{
+		GOOS:       goos,
+		Executable: "/usr/local/bin/claude-hud",
+		HomeDir:    t.TempDir(),
+		Run:        runner.run,
+	}, runner
+}
+
+func TestService_InstallSystemd(t *testing.T) {
+	svc, runner := newTestService(t, "linux")
+
+	if err := svc.Install(); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	wantPath := filepath.Join(svc.HomeDir, ".config", "systemd", "user", "claude-hud.service")
+	if svc.UnitPath() != wantPath {
+		t.Errorf("UnitPath() = %q, want %q", svc.UnitPath(), wantPath)
+	}
+	data, err := os.ReadFile(wantPath)
+	if err != nil {
+		t.Fatalf("unit file not written: %v", err)
+	}
+	if !strings.Contains(string(data), `ExecStart="/usr/local/bin/claude-hud" daemon`) {
+		t.Errorf("unit file missing ExecStart:\n%s", data)
+	}
+
+	want := []string{
+		"systemctl --user daemon-reload",
+		"systemctl --user enable --now claude-hud.service",
+	}
+	if strings.Join(runner.calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("calls = %v, want %v", runner.calls, want)
+	}
+}
+
+func TestService_InstallLaunchd(t *testing.T) {
+	svc, runner := newTestService(t, "darwin")
+
+	if err := svc.Install(); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	data, err := os.ReadFile(svc.UnitPath())
+	if err != nil {
+		t.Fatalf("plist not written: %v", err)
+	}
+	if !strings.Contains(string(data), "<string>/usr/local/bin/claude-hud</string>") {
+		t.Errorf("plist missing program:\n%s", data)
+	}
+	if last := runner.calls[len(runner.calls)-1]; last != "launchctl load -w "+svc.UnitPath() {
+		t.Errorf("last call = %q", last)
+	}
+}
+
+func TestService_UnitFileEscapes(t *testing.T) {
+	const exe = `/Users/me/R&D <tools>/100% "hud"/claude-hud`
+
+	svc, _ := newTestService(t, "linux")
+	svc.Executable = exe
+	unit, err := svc.UnitFile()
+	if err != nil {
+		t.Fatalf("UnitFile() error = %v", err)
+	}
+	if want := `ExecStart="/Users/me/R&D <tools>/100%% \"hud\"/claude-hud" daemon`; !strings.Contains(unit, want) {
+		t.Errorf("unit file missing %s:\n%s", want, unit)
+	}
+
+	svc, _ = newTestService(t, "darwin")
+	svc.Executable = exe
+	plist, err := svc.UnitFile()
+	if err != nil {
+		t.Fatalf("UnitFile() error = %v", err)
+	}
+	var parsed struct {
+		Strings []string `xml:"dict>array>string"`
+	}
+	if err := xml.Unmarshal([]byte(plist), &parsed); err != nil {
+		t.Fatalf("plist is not valid XML: %v\n%s", err, plist)
+	}
+	if len(parsed.Strings) == 0 || parsed.Strings[0] != exe {
+		t.Errorf("plist program = %q, want %q", parsed.Strings, exe)
+	}
+}
+
+func TestService_StatusAndUninstall(t *testing.T) {
+	svc, runner := newTestService(t, "linux")
+
+	runner.output = "inactive"
+	status, err := svc.Status()
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if status.Installed || status.Manager != "inactive" {
+		t.Errorf("status before install = %+v", status)
+	}
+
+	if err := svc.Install(); err != nil {
+		t.Fatal(err)
+	}
+	runner.output = "active"
+	if status, _ := svc.Status(); !status.Installed || status.Manager != "active" {
+		t.Errorf("status after install = %+v", status)
+	}
+
+	if err := svc.Uninstall(); err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	if _, err := os.Stat(svc.UnitPath()); !os.IsNotExist(err) {
+		t.Error("unit file should be removed")
+	}
+}
+
+func TestService_Unsupported(t *testing.T) {
+	svc, _ := newTestService(t, "windows")
+	if err := svc.Install(); err == nil {
+		t.Error("Install() should fail on unsupported platforms")
+	}
+}
@@ -0,0 +1,66 @@
+package daemon
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/render"
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+)
+
+// SessionsSummary is the status of every session at once, for watching
+// many concurrent sessions such as an agent farm
+type SessionsSummary struct {
+	Sessions []SessionStatus `json:"sessions"` // Most recently active first
+	Total    int             `json:"total"`
+	Working  int             `json:"working"`  // Sessions in the middle of a turn
+	Approval int             `json:"approval"` // Sessions waiting on a permission prompt
+	Cost     float64         `json:"cost"`     // Estimated cost of all sessions
+}
+
+// handleSessions returns the status of every session, or of those with hook
+// events within the active query parameter, e.g. ?active=1h. Only the latest
+// tool call of each is listed
+func (s *Server) handleSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var active time.Duration
+	if value := r.URL.Query().Get("active"); value != "" {
+		var err error
+		if active, err = time.ParseDuration(value); err != nil || active <= 0 {
+			http.Error(w, "active must be a positive duration, e.g. 1h", http.StatusBadRequest)
+			return
+		}
+	}
+
+	state, err := s.store.Load()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	summary := SessionsSummary{Sessions: []SessionStatus{}}
+	sessions := render.Values(state.Sessions, func(a, b *session.SessionState) int {
+		return render.Newest(a.LastEventAt, b.LastEventAt)
+	})
+	for _, sess := range sessions {
+		if active > 0 && time.Since(sess.LastEventAt) > active {
+			continue
+		}
+		status := s.sessionStatus(r.Context(), sess)
+		status.Tools = status.Tools[:min(len(status.Tools), 1)]
+
+		summary.Sessions = append(summary.Sessions, status)
+		summary.Total++
+		if !status.Stopped {
+			summary.Working++
+		}
+		if status.Approval {
+			summary.Approval++
+		}
+		summary.Cost += status.Cost
+	}
+	writeJSON(w, summary)
+}
@@ -0,0 +1,139 @@
+package daemon
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+// statusToolCalls is how many recent tool calls a status lists
+const statusToolCalls = 20
+
+// SessionStatus is a session's live state for dashboards: its hook record
+// joined with what its transcript says
+type SessionStatus struct {
+	SessionID      string       `json:"session_id"`
+	Name           string       `json:"name,omitempty"`
+	Cwd            string       `json:"cwd,omitempty"`
+	Host           string       `json:"host,omitempty"` // Machine of a remote session
+	TranscriptPath string       `json:"transcript_path,omitempty"`
+	LastEventAt    time.Time    `json:"last_event_at"`
+	Stopped        bool         `json:"stopped"`
+	Approval       bool         `json:"approval"` // Waiting on a permission prompt
+	StartedAt      time.Time    `json:"started_at,omitzero"`
+	ContextPercent int          `json:"context_percent"`
+	ContextTokens  int          `json:"context_tokens"`
+	ContextSize    int          `json:"context_size"`
+	Cost           float64      `json:"cost"`
+	Tools          []StatusTool `json:"tools"`
+	Todos          []StatusTodo `json:"todos"`
+	Error          string       `json:"error,omitempty"` // Why the transcript could not be read
+}
+
+// StatusTool is a recent tool call
+type StatusTool struct {
+	Name   string    `json:"name"`
+	Target string    `json:"target,omitempty"`
+	Status string    `json:"status"` // running, completed, error, interrupted
+	At     time.Time `json:"at,omitzero"`
+}
+
+// StatusTodo is an item of the session's todo list
+type StatusTodo struct {
+	Content string `json:"content"`
+	Status  string `json:"status"` // pending, in_progress, completed
+}
+
+// SetTranscripts makes the status API parse transcripts with parsers from
+// fn, so they are shared and parsed incrementally
+func (s *Server) SetTranscripts(fn func(path string) *transcript.Parser) {
+	s.transcripts = fn
+}
+
+// handleStatus returns the status of the session named by the session query
+// parameter, or of the most recently active one
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	state, err := s.store.Load()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	sess := findSession(state, r.URL.Query().Get("session"))
+	if sess == nil {
+		http.Error(w, "no such session", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, s.sessionStatus(r.Context(), sess))
+}
+
+// findSession returns the session with the given ID or name, or the most
+// recently active one when id is empty
+func findSession(state *session.State, id string) *session.SessionState {
+	if id == "" {
+		return state.Latest()
+	}
+	if sess := state.Sessions[id]; sess != nil {
+		return sess
+	}
+	return state.FindByName(id)
+}
+
+// sessionStatus joins a session's hook record with its transcript
+func (s *Server) sessionStatus(ctx context.Context, sess *session.SessionState) SessionStatus {
+	status := SessionStatus{
+		SessionID:      sess.SessionID,
+		Name:           sess.Name,
+		Cwd:            sess.Cwd,
+		TranscriptPath: sess.TranscriptPath,
+		LastEventAt:    sess.LastEventAt,
+		Stopped:        sess.Stopped,
+		Approval:       sess.Approval != nil,
+		Tools:          []StatusTool{},
+		Todos:          []StatusTodo{},
+	}
+	if remote := sess.Remote; remote != nil {
+		// What its statusline reported; the transcript is on its own
+		// machine, and a path from there is never read here
+		status.Host = remote.Host
+		cw := transcript.ContextWindow{ContextWindowSize: remote.ContextSize}
+		cw.CurrentUsage.InputTokens = remote.ContextTokens
+		status.ContextPercent = cw.Percentage()
+		status.ContextTokens = remote.ContextTokens
+		status.ContextSize = remote.ContextSize
+		return status
+	}
+	if sess.TranscriptPath == "" {
+		return status
+	}
+
+	parser := s.transcripts(sess.TranscriptPath)
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	if err := parser.Parse(ctx); err != nil {
+		status.Error = err.Error()
+		return status
+	}
+
+	status.StartedAt = parser.GetSessionStart()
+	if cw := parser.GetContextWindow(); cw != nil {
+		status.ContextPercent = cw.Percentage()
+		status.ContextTokens = cw.CurrentUsage.TotalInput()
+		status.ContextSize = cw.ContextWindowSize
+	}
+	status.Cost = parser.CalculateCost()
+	for _, call := range parser.GetRecentToolCalls(statusToolCalls) {
+		status.Tools = append(status.Tools, StatusTool{Name: call.Name, Target: call.Target, Status: call.Status, At: call.LastUsed})
+	}
+	for _, todo := range parser.GetTodoList() {
+		status.Todos = append(status.Todos, StatusTodo{Content: todo.Content, Status: todo.Status})
+	}
+	return status
+}
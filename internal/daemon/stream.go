@@ -0,0 +1,267 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+const (
+	// streamInterval is how often a stream checks for a changed status and
+	// new transcript lines between hook events
+	streamInterval = time.Second
+
+	// streamKeepalive is how often an idle stream sends a comment, so proxies
+	// and browsers keep the connection open
+	streamKeepalive = 15 * time.Second
+
+	// maxTailRead is the most of a transcript a stream reads at once; a
+	// session that writes faster is caught up on the next check
+	maxTailRead = 4 * 1024 * 1024
+)
+
+// TranscriptEvent is a timeline entry of a session's transcript, streamed as
+// it is written
+type TranscriptEvent struct {
+	Time       time.Time `json:"time,omitzero"`
+	Kind       string    `json:"kind"` // prompt, reply, tool, todos, interrupt, compaction
+	Text       string    `json:"text,omitempty"`
+	Tool       string    `json:"tool,omitempty"`
+	ToolUseID  string    `json:"tool_use_id,omitempty"`
+	Target     string    `json:"target,omitempty"`
+	Status     string    `json:"status,omitempty"` // running, completed, error, interrupted
+	DurationMs int64     `json:"duration_ms,omitempty"`
+	Sidechain  bool      `json:"sidechain,omitempty"`
+}
+
+// handleStream streams a session's live state as Server-Sent Events: its
+// status whenever it changes, each new transcript entry and each hook event.
+// The session query parameter picks the session; without it the stream
+// follows whichever session was most recently active
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	id := r.URL.Query().Get("session")
+	state, err := s.store.Load()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if id != "" && findSession(state, id) == nil {
+		http.Error(w, "no such session", http.StatusNotFound)
+		return
+	}
+
+	ch, cancel := s.hub.Subscribe()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	stream := &sessionStream{server: s, w: w, flusher: flusher, id: id}
+	if err := stream.refresh(r); err != nil {
+		return
+	}
+
+	interval := s.streamInterval
+	if interval <= 0 {
+		interval = streamInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	keepalive := time.NewTicker(streamKeepalive)
+	defer keepalive.Stop()
+
+	for {
+		var err error
+		select {
+		case <-r.Context().Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err = stream.hookEvent(msg); err == nil {
+				err = stream.refresh(r)
+			}
+		case <-ticker.C:
+			err = stream.refresh(r)
+		case <-keepalive.C:
+			err = stream.write(": keepalive\n\n")
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// sessionStream is the state of one client's event stream
+type sessionStream struct {
+	server  *Server
+	w       io.Writer
+	flusher http.Flusher
+	id      string // The session asked for, or empty to follow the latest
+
+	sessionID  string          // The session being streamed
+	lastStatus []byte          // The status last sent, to send only changes
+	tail       *transcriptTail // New lines of the session's transcript
+}
+
+// refresh sends the session's new transcript entries and its status when it
+// changed, switching to another session when the latest one changes
+func (st *sessionStream) refresh(r *http.Request) error {
+	state, err := st.server.store.Load()
+	if err != nil {
+		return nil // A state file being rewritten is read on the next check
+	}
+	sess := findSession(state, st.id)
+	if sess == nil {
+		return nil
+	}
+	if sess.SessionID != st.sessionID || st.tail == nil || st.tail.path != sess.TranscriptPath {
+		st.sessionID = sess.SessionID
+		st.tail = newTranscriptTail(sess.TranscriptPath)
+	}
+
+	for _, entry := range st.tail.read() {
+		if err := st.event("transcript", transcriptEvent(entry)); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(st.server.sessionStatus(r.Context(), sess))
+	if err != nil || bytes.Equal(data, st.lastStatus) {
+		return nil
+	}
+	st.lastStatus = data
+	return st.write(fmt.Sprintf("event: status\ndata: %s\n\n", data))
+}
+
+// hookEvent forwards a broadcast hook event of the streamed session
+func (st *sessionStream) hookEvent(msg []byte) error {
+	var ev struct {
+		SessionID string `json:"session_id"`
+	}
+	if err := json.Unmarshal(msg, &ev); err != nil {
+		return nil
+	}
+	// Following the latest session, an event may be what makes it the latest
+	if st.id != "" && ev.SessionID != st.sessionID {
+		return nil
+	}
+	return st.write(fmt.Sprintf("event: hook\ndata: %s\n\n", msg))
+}
+
+// event sends v as JSON under the given event name
+func (st *sessionStream) event(name string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return st.write(fmt.Sprintf("event: %s\ndata: %s\n\n", name, data))
+}
+
+// write sends raw event stream text to the client
+func (st *sessionStream) write(text string) error {
+	if _, err := io.WriteString(st.w, text); err != nil {
+		return err
+	}
+	st.flusher.Flush()
+	return nil
+}
+
+// transcriptEvent converts a timeline entry for streaming
+func transcriptEvent(entry transcript.TimelineEntry) TranscriptEvent {
+	return TranscriptEvent{
+		Time:       entry.Time,
+		Kind:       string(entry.Kind),
+		Text:       entry.Text,
+		Tool:       entry.Tool,
+		ToolUseID:  entry.ToolUseID,
+		Target:     entry.Target,
+		Status:     entry.Status,
+		DurationMs: entry.Duration.Milliseconds(),
+		Sidechain:  entry.Sidechain,
+	}
+}
+
+// transcriptTail follows the lines appended to a transcript
+type transcriptTail struct {
+	path     string
+	offset   int64 // Where the first line not read yet starts
+	timeline *transcript.TimelineStream
+}
+
+// newTranscriptTail follows path from its current end; what was written
+// before is already summed up by the session's status
+func newTranscriptTail(path string) *transcriptTail {
+	t := &transcriptTail{path: path, timeline: transcript.NewTimelineStream()}
+	if info, err := os.Stat(path); err == nil {
+		t.offset = info.Size()
+	}
+	return t
+}
+
+// read returns the timeline entries of the complete lines appended since the
+// last read. A line still being written is left for the next read
+func (t *transcriptTail) read() []transcript.TimelineEntry {
+	if t.path == "" {
+		return nil
+	}
+	file, err := os.Open(t.path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil
+	}
+	if info.Size() < t.offset {
+		// The transcript was rewritten; follow it from its new end
+		t.offset = info.Size()
+		return nil
+	}
+	if info.Size() == t.offset {
+		return nil
+	}
+
+	buf := make([]byte, min(info.Size()-t.offset, maxTailRead))
+	n, err := file.ReadAt(buf, t.offset)
+	if err != nil && err != io.EOF {
+		return nil
+	}
+	buf = buf[:n]
+
+	end := bytes.LastIndexByte(buf, '\n')
+	if end < 0 {
+		if len(buf) == maxTailRead {
+			t.offset += int64(len(buf)) // A line too long to stream is skipped
+		}
+		return nil
+	}
+	t.offset += int64(end + 1)
+
+	var entries []transcript.TimelineEntry
+	for _, line := range bytes.Split(buf[:end], []byte{'\n'}) {
+		entries = append(entries, t.timeline.Line(line)...)
+	}
+	return entries
+}
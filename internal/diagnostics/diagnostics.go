@@ -0,0 +1,184 @@
+// Package diagnostics reads the diagnostics an editor exports to a JSON file,
+// e.g. from a neovim autocommand or a VS Code extension, and counts them
+package diagnostics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/paths"
+)
+
+// Severity is an LSP diagnostic severity
+type Severity int
+
+// LSP severities; VS Code's DiagnosticSeverity enum starts at 0 instead, so
+// helpers should export names or add one
+const (
+	SeverityError       Severity = 1
+	SeverityWarning     Severity = 2
+	SeverityInformation Severity = 3
+	SeverityHint        Severity = 4
+)
+
+// UnmarshalJSON accepts LSP numbers and names such as "error" or "Warning"
+func (s *Severity) UnmarshalJSON(data []byte) error {
+	var number int
+	if err := json.Unmarshal(data, &number); err == nil {
+		*s = Severity(number)
+		return nil
+	}
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("invalid severity %s", data)
+	}
+	switch strings.ToLower(name) {
+	case "error":
+		*s = SeverityError
+	case "warning", "warn":
+		*s = SeverityWarning
+	case "information", "info":
+		*s = SeverityInformation
+	case "hint":
+		*s = SeverityHint
+	default:
+		return fmt.Errorf("unknown severity %q", name)
+	}
+	return nil
+}
+
+// Diagnostic is one exported diagnostic; only the fields counted are kept
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	File     string   `json:"file"`
+	Filename string   `json:"filename"` // neovim helpers often add the buffer name
+	URI      string   `json:"uri"`
+}
+
+// path returns the file the diagnostic belongs to, or "" when not exported
+func (d Diagnostic) path() string {
+	for _, path := range []string{d.File, d.Filename, d.URI} {
+		if path != "" {
+			return uriPath(path)
+		}
+	}
+	return ""
+}
+
+// Counts are the diagnostics per severity
+type Counts struct {
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+	Infos    int `json:"infos"`
+	Hints    int `json:"hints"`
+}
+
+// add counts one diagnostic
+func (c *Counts) add(severity Severity) {
+	switch severity {
+	case SeverityError:
+		c.Errors++
+	case SeverityWarning:
+		c.Warnings++
+	case SeverityInformation:
+		c.Infos++
+	case SeverityHint:
+		c.Hints++
+	}
+}
+
+// Read parses the diagnostics file at path, keeping only diagnostics for
+// files inside workspace when it is set. Returns when the file was written
+func Read(path, workspace string) (Counts, time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Counts{}, time.Time{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Counts{}, time.Time{}, err
+	}
+	counts, err := Parse(data, workspace)
+	return counts, info.ModTime(), err
+}
+
+// Parse counts diagnostics in any of the shapes helpers export:
+//   - a list of diagnostics: [{"severity": 1, "file": "main.go"}, ...]
+//   - an object with such a list: {"diagnostics": [...]}
+//   - diagnostics per file or URI: {"file:///src/main.go": [...], ...}
+//   - counts the editor already made: {"errors": 2, "warnings": 5}
+//
+// Diagnostics without a file are always counted, as are precomputed counts
+func Parse(data []byte, workspace string) (Counts, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return Counts{}, nil // Editors truncate the file before writing it
+	}
+
+	var counts Counts
+	if data[0] == '[' {
+		var list []Diagnostic
+		if err := json.Unmarshal(data, &list); err != nil {
+			return Counts{}, fmt.Errorf("invalid diagnostics: %w", err)
+		}
+		for _, d := range list {
+			if inWorkspace(d.path(), workspace) {
+				counts.add(d.Severity)
+			}
+		}
+		return counts, nil
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return Counts{}, fmt.Errorf("invalid diagnostics: %w", err)
+	}
+	if list, ok := object["diagnostics"]; ok {
+		return Parse(list, workspace)
+	}
+	if _, ok := object["errors"]; ok {
+		if err := json.Unmarshal(data, &counts); err != nil {
+			return Counts{}, fmt.Errorf("invalid diagnostic counts: %w", err)
+		}
+		return counts, nil
+	}
+	for file, raw := range object {
+		if !inWorkspace(uriPath(file), workspace) {
+			continue
+		}
+		var list []Diagnostic
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return Counts{}, fmt.Errorf("invalid diagnostics for %s: %w", file, err)
+		}
+		for _, d := range list {
+			counts.add(d.Severity)
+		}
+	}
+	return counts, nil
+}
+
+// uriPath turns file:// URIs into paths and leaves paths alone
+func uriPath(file string) string {
+	if !strings.HasPrefix(file, "file://") {
+		return file
+	}
+	if u, err := url.Parse(file); err == nil {
+		return filepath.FromSlash(u.Path)
+	}
+	return strings.TrimPrefix(file, "file://")
+}
+
+// inWorkspace reports whether path lies inside workspace
+// Relative paths are taken to be relative to the workspace already
+func inWorkspace(path, workspace string) bool {
+	if path == "" || workspace == "" || !filepath.IsAbs(path) {
+		return true
+	}
+	return paths.Within(path, workspace)
+}
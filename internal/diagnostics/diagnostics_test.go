@@ -0,0 +1,79 @@
+package diagnostics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	const workspace = "/home/me/project"
+
+	tests := []struct {
+		name    string
+		data    string
+		want    Counts
+		wantErr bool
+	}{
+		{
+			name: "neovim list",
+			data: `[{"severity":1,"lnum":3,"bufnr":4},{"severity":2},{"severity":2},{"severity":4}]`,
+			want: Counts{Errors: 1, Warnings: 2, Hints: 1},
+		},
+		{
+			name: "named severities outside the workspace",
+			data: `{"diagnostics":[
+				{"severity":"Error","file":"/home/me/project/main.go"},
+				{"severity":"error","file":"internal/x.go"},
+				{"severity":"warning","file":"/home/me/other/main.go"},
+				{"severity":"info","filename":"/home/me/project-old/a.go"}
+			]}`,
+			want: Counts{Errors: 2},
+		},
+		{
+			name: "per file URI",
+			data: `{
+				"file:///home/me/project/src/app.ts":[{"severity":1},{"severity":3}],
+				"file:///usr/lib/node_modules/x.d.ts":[{"severity":1}]
+			}`,
+			want: Counts{Errors: 1, Infos: 1},
+		},
+		{
+			name: "precomputed counts",
+			data: `{"errors":2,"warnings":5}`,
+			want: Counts{Errors: 2, Warnings: 5},
+		},
+		{
+			name: "truncated while writing",
+			data: "",
+		},
+		{
+			name:    "invalid",
+			data:    `[{"severity":"fatal"}]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.data), workspace)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("Parse() = %+v, %v; want %+v, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "diagnostics.json")
+	if _, _, err := Read(path, ""); !os.IsNotExist(err) {
+		t.Errorf("Read() of a missing file error = %v, want not exist", err)
+	}
+	if err := os.WriteFile(path, []byte(`[{"severity":1}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	counts, modTime, err := Read(path, "")
+	if err != nil || counts.Errors != 1 || modTime.IsZero() {
+		t.Errorf("Read() = %+v, %v, %v", counts, modTime, err)
+	}
+}
@@ -0,0 +1,198 @@
+// Package digest sums up a finished Claude Code session for a worklog: how
+// long it ran, what it cost, the tokens it used, the files it changed and the
+// todos it completed
+package digest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/humanize"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+// maxListed is how many files or todos a digest lists before "and N more"
+const maxListed = 10
+
+// Funcs are helpers for templates that format a digest, e.g.
+// {{duration .Duration}} or {{cost .Cost}}
+var Funcs = map[string]any{
+	"count":    humanize.Count,
+	"duration": func(d time.Duration) string { return humanize.Duration(d, humanize.Elapsed) },
+	"cost":     func(cost float64) string { return fmt.Sprintf("$%.2f", cost) },
+	"time":     func(t time.Time) string { return t.Local().Format("2006-01-02 15:04") },
+	"list":     func(items []string) string { return list(items, "") },
+}
+
+// Digest is the summary of one session
+type Digest struct {
+	SessionID    string
+	Name         string // Set with claude-hud name, if any
+	Dir          string // Working directory
+	Start        time.Time
+	End          time.Time
+	Cost         float64
+	InputTokens  int
+	OutputTokens int
+	Files        []string // Changed files, most recently changed first
+	TodosDone    []string // Completed todos, in list order
+	TodosTotal   int
+}
+
+// Duration is how long the session ran
+func (d Digest) Duration() time.Duration {
+	if d.Start.IsZero() || d.End.Before(d.Start) {
+		return 0
+	}
+	return d.End.Sub(d.Start)
+}
+
+// FromTranscript reads a session's digest from its transcript. The session
+// ends at the transcript's last write
+func FromTranscript(ctx context.Context, path string) (Digest, error) {
+	file, err := transcript.Open(path)
+	if err != nil {
+		return Digest{}, err
+	}
+	defer file.Close()
+
+	parser := transcript.NewParser(path)
+	if err := parser.ParseFromReader(ctx, file); err != nil {
+		return Digest{}, err
+	}
+
+	d := Digest{
+		SessionID: strings.TrimSuffix(strings.TrimSuffix(filepath.Base(path), ".gz"), ".jsonl"),
+		Start:     parser.GetSessionStart(),
+		Cost:      parser.CalculateCost(),
+	}
+	if info, err := os.Stat(path); err == nil {
+		d.End = info.ModTime()
+	}
+	d.InputTokens, d.OutputTokens = parser.GetTotalTokens()
+	for _, f := range parser.GetTouchedFiles() {
+		d.Files = append(d.Files, f.Path)
+	}
+	todos := parser.GetTodoList()
+	d.TodosTotal = len(todos)
+	for _, todo := range todos {
+		if todo.Status == "completed" {
+			d.TodosDone = append(d.TodosDone, todo.Content)
+		}
+	}
+	return d, nil
+}
+
+// Text formats the digest for a terminal, e.g.
+//
+//	Session auth-refactor · 1h12m · $2.41 · 1.2M in / 48k out
+//	Files (2): internal/auth/token.go, internal/auth/token_test.go
+//	Todos 2/3: ✓ Add refresh tokens, ✓ Cover expiry
+func (d Digest) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Session %s · %s · $%.2f · %s in / %s out\n", d.Title(), humanize.Duration(d.Duration(), humanize.Elapsed),
+		d.Cost, humanize.Count(d.InputTokens), humanize.Count(d.OutputTokens))
+	if len(d.Files) > 0 {
+		fmt.Fprintf(&b, "Files (%d): %s\n", len(d.Files), list(d.RelativeFiles(), ""))
+	}
+	if d.TodosTotal > 0 {
+		fmt.Fprintf(&b, "Todos %d/%d", len(d.TodosDone), d.TodosTotal)
+		if len(d.TodosDone) > 0 {
+			b.WriteString(": " + list(d.TodosDone, "✓ "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Markdown formats the digest as an entry of a daily log
+func (d Digest) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s %s", d.End.Local().Format("15:04"), d.Title())
+	if d.Dir != "" {
+		fmt.Fprintf(&b, " — %s", d.Dir)
+	}
+	b.WriteString("\n\n")
+	fmt.Fprintf(&b, "- Duration: %s\n", humanize.Duration(d.Duration(), humanize.Elapsed))
+	fmt.Fprintf(&b, "- Cost: $%.2f\n", d.Cost)
+	fmt.Fprintf(&b, "- Tokens: %s in, %s out\n", humanize.Count(d.InputTokens), humanize.Count(d.OutputTokens))
+	if len(d.Files) > 0 {
+		fmt.Fprintf(&b, "- Files changed: %s\n", list(d.RelativeFiles(), ""))
+	}
+	if d.TodosTotal > 0 {
+		fmt.Fprintf(&b, "- Todos completed: %d/%d\n", len(d.TodosDone), d.TodosTotal)
+		for _, todo := range d.TodosDone {
+			fmt.Fprintf(&b, "  - %s\n", todo)
+		}
+	}
+	return b.String()
+}
+
+// AppendLog appends the digest to the daily log in dir, e.g.
+// dir/2026-03-01.md for a session ending on March 1st, creating both as
+// needed. It returns the log's path
+func AppendLog(dir string, d Digest) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, d.End.Local().Format("2006-01-02")+".md")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	entry := d.Markdown()
+	if info, err := file.Stat(); err == nil && info.Size() > 0 {
+		entry = "\n" + entry
+	}
+	if _, err := file.WriteString(entry); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Title names the session by its name, or its short ID without one
+func (d Digest) Title() string {
+	if d.Name != "" {
+		return d.Name
+	}
+	if len(d.SessionID) > 8 {
+		return d.SessionID[:8]
+	}
+	return d.SessionID
+}
+
+// RelativeFiles returns the changed files relative to the working directory
+// where they are inside it
+func (d Digest) RelativeFiles() []string {
+	files := make([]string, len(d.Files))
+	for i, path := range d.Files {
+		files[i] = path
+		if d.Dir == "" {
+			continue
+		}
+		if rel, err := filepath.Rel(d.Dir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			files[i] = rel
+		}
+	}
+	return files
+}
+
+// list joins items with commas, each after prefix, up to maxListed of them
+func list(items []string, prefix string) string {
+	shown := items[:min(len(items), maxListed)]
+	parts := make([]string, len(shown))
+	for i, item := range shown {
+		parts[i] = prefix + item
+	}
+	text := strings.Join(parts, ", ")
+	if more := len(items) - len(shown); more > 0 {
+		text += fmt.Sprintf(" and %d more", more)
+	}
+	return text
+}
@@ -0,0 +1,118 @@
+package digest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testTranscript = `{"type":"assistant","timestamp":"2026-03-01T10:00:00.000Z","message":{"role":"assistant","model":"claude-sonnet-4","usage":{"input_tokens":1000,"output_tokens":200},"content":[{"type":"tool_use","id":"t1","name":"Edit","input":{"file_path":"/p/auth/token.go"}}]}}
+{"type":"user","timestamp":"2026-03-01T10:00:01.000Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1"}]}}
+{"type":"assistant","timestamp":"2026-03-01T10:30:00.000Z","message":{"role":"assistant","model":"claude-sonnet-4","usage":{"input_tokens":2000,"output_tokens":400},"content":[{"type":"tool_use","id":"t2","name":"TodoWrite","input":{"todos":[{"content":"Add refresh tokens","status":"completed","activeForm":"Adding refresh tokens"},{"content":"Cover expiry","status":"in_progress","activeForm":"Covering expiry"}]}}]}}
+{"type":"user","timestamp":"2026-03-01T10:30:01.000Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t2"}]}}
+`
+
+func writeTranscript(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "abc-12345678.jsonl")
+	if err := os.WriteFile(path, []byte(testTranscript), 0644); err != nil {
+		t.Fatal(err)
+	}
+	end := time.Date(2026, 3, 1, 10, 45, 0, 0, time.UTC)
+	if err := os.Chtimes(path, end, end); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFromTranscript(t *testing.T) {
+	d, err := FromTranscript(context.Background(), writeTranscript(t))
+	if err != nil {
+		t.Fatalf("FromTranscript() error = %v", err)
+	}
+
+	if d.SessionID != "abc-12345678" {
+		t.Errorf("SessionID = %q", d.SessionID)
+	}
+	if d.Duration() != 45*time.Minute {
+		t.Errorf("Duration() = %v, want 45m", d.Duration())
+	}
+	if d.InputTokens != 3000 || d.OutputTokens != 600 {
+		t.Errorf("tokens = %d in, %d out; want 3000, 600", d.InputTokens, d.OutputTokens)
+	}
+	if len(d.Files) != 1 || d.Files[0] != "/p/auth/token.go" {
+		t.Errorf("Files = %v", d.Files)
+	}
+	if d.TodosTotal != 2 || len(d.TodosDone) != 1 || d.TodosDone[0] != "Add refresh tokens" {
+		t.Errorf("todos = %v of %d", d.TodosDone, d.TodosTotal)
+	}
+}
+
+func TestText(t *testing.T) {
+	d := Digest{
+		SessionID:    "abc-12345678",
+		Dir:          "/p",
+		Start:        time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+		End:          time.Date(2026, 3, 1, 11, 12, 0, 0, time.UTC),
+		Cost:         2.414,
+		InputTokens:  1_200_000,
+		OutputTokens: 48_000,
+		Files:        []string{"/p/auth/token.go", "/elsewhere/notes.md"},
+		TodosDone:    []string{"Add refresh tokens"},
+		TodosTotal:   3,
+	}
+
+	want := "Session abc-1234 · 1h12m · $2.41 · 1.2M in / 48k out\n" +
+		"Files (2): auth/token.go, /elsewhere/notes.md\n" +
+		"Todos 1/3: ✓ Add refresh tokens\n"
+	if got := d.Text(); got != want {
+		t.Errorf("Text() =\n%s\nwant\n%s", got, want)
+	}
+
+	d.Name = "auth-refactor"
+	d.Files, d.TodosTotal = nil, 0
+	if got := d.Text(); got != "Session auth-refactor · 1h12m · $2.41 · 1.2M in / 48k out\n" {
+		t.Errorf("Text() without files or todos = %q", got)
+	}
+}
+
+func TestList(t *testing.T) {
+	items := make([]string, maxListed+3)
+	for i := range items {
+		items[i] = "x"
+	}
+	if got := list(items, ""); !strings.HasSuffix(got, "x and 3 more") {
+		t.Errorf("list() = %q, want the rest counted", got)
+	}
+}
+
+func TestAppendLog(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "worklog")
+	end := time.Date(2026, 3, 1, 15, 0, 0, 0, time.Local)
+	first := Digest{Name: "first", Start: end.Add(-time.Hour), End: end}
+	second := Digest{Name: "second", Start: end, End: end.Add(time.Hour)}
+
+	path, err := AppendLog(dir, first)
+	if err != nil {
+		t.Fatalf("AppendLog() error = %v", err)
+	}
+	if filepath.Base(path) != "2026-03-01.md" {
+		t.Errorf("log = %s, want 2026-03-01.md", path)
+	}
+	if _, err := AppendLog(dir, second); err != nil {
+		t.Fatalf("AppendLog() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "## 15:00 first\n\n- Duration: 1h\n- Cost: $0.00\n- Tokens: 0 in, 0 out\n\n" +
+		"## 16:00 second\n\n- Duration: 1h\n- Cost: $0.00\n- Tokens: 0 in, 0 out\n"
+	if string(data) != want {
+		t.Errorf("log =\n%s\nwant\n%s", data, want)
+	}
+}
@@ -216,8 +216,10 @@ func WrapData(err error, op, msg string) error {
 	return WrapTyped(err, op, msg, TypeData)
 }
 
-// PanicError creates an error from a recovered panic.
+// PanicError creates an error from a recovered panic, recording the panic
+// with the panic observer.
 func PanicError(op string, panicValue interface{}) error {
+	RecordPanic(op, panicValue)
 	msg := fmt.Sprintf("panic: %v", panicValue)
 	return &TypedError{
 		HUDError: &HUDError{
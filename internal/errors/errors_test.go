@@ -3,7 +3,9 @@ package errors
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 )
@@ -608,6 +610,39 @@ func TestLoggerLevelFiltering(t *testing.T) {
 	}
 }
 
+// TestLoggerRecent tests the warnings and errors a logger remembers
+func TestLoggerRecent(t *testing.T) {
+	logger := NewLogger(LevelError, false) // Warnings are remembered even when not written
+	logger.SetOutput(io.Discard)
+
+	logger.Info("test", "info message")
+	logger.Warn("git", "slow %s", "status")
+	logger.LogError(New("beads", "database locked"))
+	recent := logger.Recent(5)
+	if len(recent) != 2 {
+		t.Fatalf("Recent(5) = %d entries, want 2", len(recent))
+	}
+	if e := recent[0]; e.Level != LevelWarn || e.Op != "git" || e.Message != "slow status" {
+		t.Errorf("first entry = %+v", e)
+	}
+	if e := recent[1]; e.Level != LevelError || e.Op != "beads" {
+		t.Errorf("second entry = %+v", e)
+	}
+
+	for i := 0; i < RecentCapacity+5; i++ {
+		logger.Warn("test", "warning %d", i)
+	}
+	if got := len(logger.Recent(100)); got != RecentCapacity {
+		t.Errorf("remembered %d entries, want %d", got, RecentCapacity)
+	}
+	if got := logger.Recent(1)[0].Message; got != fmt.Sprintf("warning %d", RecentCapacity+4) {
+		t.Errorf("latest entry = %q", got)
+	}
+	if got := logger.Recent(0); got != nil {
+		t.Errorf("Recent(0) = %v, want nil", got)
+	}
+}
+
 // TestSetDebugMode tests the global debug mode setting
 func TestSetDebugMode(t *testing.T) {
 	// Save original state
@@ -1288,3 +1323,43 @@ func TestLoggerConcurrent(t *testing.T) {
 		<-done
 	}
 }
+
+func TestLoggerModuleLevels(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(LevelInfo, false)
+	logger.SetOutput(&buf)
+	logger.SetModuleLevel("transcript", LevelDebug)
+	logger.SetModuleLevel("watcher", LevelWarn)
+	logger.SetModuleLevel("watcher.poll", LevelError)
+
+	logger.Debug("transcript.parser", "parsed line")
+	logger.Debug("transcripts", "not a submodule")
+	logger.Debug("git", "debug hidden")
+	logger.Info("watcher", "info hidden")
+	logger.Warn("watcher.fsnotify", "fell back")
+	logger.Warn("watcher.poll", "warning hidden")
+	logger.Info("main", "started")
+
+	out := buf.String()
+	for _, want := range []string{"parsed line", "fell back", "started"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q to be logged:\n%s", want, out)
+		}
+	}
+	for _, hidden := range []string{"not a submodule", "debug hidden", "info hidden", "warning hidden"} {
+		if strings.Contains(out, hidden) {
+			t.Errorf("expected %q to be filtered:\n%s", hidden, out)
+		}
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for name, want := range map[string]LogLevel{"debug": LevelDebug, "INFO": LevelInfo, "warning": LevelWarn, " error ": LevelError} {
+		if got, err := ParseLevel(name); err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"sync"
 	"time"
 )
@@ -45,6 +46,21 @@ func (l LogLevel) String() string {
 	}
 }
 
+// ParseLevel parses a level name: debug, info, warn (or warning) or error.
+func ParseLevel(name string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", name)
+}
+
 // ANSI color codes for terminal output.
 const (
 	colorReset  = "\033[0m"
@@ -71,13 +87,26 @@ func colorForLevel(level LogLevel) string {
 	}
 }
 
+// RecentCapacity is how many warnings and errors a logger remembers.
+const RecentCapacity = 20
+
+// Entry is a warning or error a logger remembers.
+type Entry struct {
+	Time    time.Time
+	Level   LogLevel
+	Op      string
+	Message string
+}
+
 // Logger is a thread-safe logger with configurable levels and output.
 type Logger struct {
 	mu       sync.Mutex
 	level    LogLevel
+	modules  map[string]LogLevel // Level overrides by operation prefix
 	output   io.Writer
 	debug    bool
 	useColor bool
+	recent   []Entry // Latest warnings and errors, oldest first
 }
 
 // NewLogger creates a new logger with the specified configuration.
@@ -106,6 +135,32 @@ func (l *Logger) SetLevel(level LogLevel) {
 	l.level = level
 }
 
+// SetModuleLevel overrides the minimum level for operations under prefix,
+// e.g. "transcript" covers "transcript" and "transcript.parser". The longest
+// matching prefix wins over the logger's level, in debug mode too.
+func (l *Logger) SetModuleLevel(prefix string, level LogLevel) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.modules == nil {
+		l.modules = make(map[string]LogLevel)
+	}
+	l.modules[prefix] = level
+}
+
+// levelFor returns the minimum level for an operation; the caller holds l.mu.
+func (l *Logger) levelFor(op string) LogLevel {
+	level, matched := l.level, -1
+	for prefix, moduleLevel := range l.modules {
+		if len(prefix) <= matched {
+			continue
+		}
+		if op == prefix || strings.HasPrefix(op, prefix+".") {
+			level, matched = moduleLevel, len(prefix)
+		}
+	}
+	return level
+}
+
 // SetOutput changes the output writer.
 func (l *Logger) SetOutput(w io.Writer) {
 	l.mu.Lock()
@@ -124,11 +179,12 @@ func (l *Logger) SetDebug(debug bool) {
 	}
 }
 
-// shouldLog returns true if a message at the given level should be logged.
-func (l *Logger) shouldLog(level LogLevel) bool {
+// shouldLog returns true if a message at the given level should be logged
+// for the operation.
+func (l *Logger) shouldLog(level LogLevel, op string) bool {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	return level >= l.level
+	return level >= l.levelFor(op)
 }
 
 // formatMessage formats a log message with timestamp and context.
@@ -161,9 +217,42 @@ func (l *Logger) formatMessage(level LogLevel, op string, msg string, args ...in
 	return formatted
 }
 
+// remember keeps warnings and errors, whatever the level logged, so they can
+// be shown where stderr is not, as in statusline mode.
+func (l *Logger) remember(level LogLevel, op string, msg string, args ...interface{}) {
+	if level < LevelWarn {
+		return
+	}
+	message := msg
+	if len(args) > 0 {
+		message = fmt.Sprintf(msg, args...)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.recent) == RecentCapacity {
+		l.recent = append(l.recent[:0], l.recent[1:]...)
+	}
+	l.recent = append(l.recent, Entry{Time: time.Now(), Level: level, Op: op, Message: message})
+}
+
+// Recent returns up to n of the latest warnings and errors, oldest first.
+func (l *Logger) Recent(n int) []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if n > len(l.recent) {
+		n = len(l.recent)
+	}
+	if n <= 0 {
+		return nil
+	}
+	return append([]Entry(nil), l.recent[len(l.recent)-n:]...)
+}
+
 // log writes a log message at the specified level.
 func (l *Logger) log(level LogLevel, op string, msg string, args ...interface{}) {
-	if !l.shouldLog(level) {
+	l.remember(level, op, msg, args...)
+	if !l.shouldLog(level, op) {
 		return
 	}
 
@@ -177,7 +266,8 @@ func (l *Logger) log(level LogLevel, op string, msg string, args ...interface{})
 // logDirect writes a pre-formatted log message at the specified level.
 // Use this when the message is already formatted or comes from user input.
 func (l *Logger) logDirect(level LogLevel, op string, message string) {
-	if !l.shouldLog(level) {
+	l.remember(level, op, "%s", message)
+	if !l.shouldLog(level, op) {
 		return
 	}
 
@@ -275,7 +365,7 @@ func (l *Logger) LogErrorWithLevel(err error) {
 
 // LogJSON logs a message as JSON for structured logging.
 func (l *Logger) LogJSON(level LogLevel, op string, data map[string]interface{}) {
-	if !l.shouldLog(level) {
+	if !l.shouldLog(level, op) {
 		return
 	}
 
@@ -336,6 +426,12 @@ func Error(op, msg string, args ...interface{}) {
 	globalLogger.Error(op, msg, args...)
 }
 
+// Recent returns up to n of the latest warnings and errors logged to the
+// global logger, oldest first.
+func Recent(n int) []Entry {
+	return globalLogger.Recent(n)
+}
+
 // LogError logs an error to the global logger.
 func LogError(err error) {
 	globalLogger.LogError(err)
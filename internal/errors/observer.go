@@ -0,0 +1,79 @@
+package errors
+
+import (
+	"sync"
+	"time"
+)
+
+// PanicObserver is told about each recovered panic, e.g. to count them.
+type PanicObserver func(op string, panicValue interface{})
+
+// DisableObserver is told about each operation turned off for the rest of
+// the process after failing too often, e.g. a section that keeps panicking.
+type DisableObserver func(op, reason string)
+
+// FailureObserver is told about each failure counted against an operation's
+// error budget, e.g. a section's render that panicked or timed out.
+type FailureObserver func(op string, at time.Time)
+
+var (
+	observerMu      sync.RWMutex
+	panicObserver   PanicObserver
+	disableObserver DisableObserver
+	failureObserver FailureObserver
+)
+
+// SetPanicObserver sets the function told about recovered panics; nil stops
+// observing.
+func SetPanicObserver(observer PanicObserver) {
+	observerMu.Lock()
+	defer observerMu.Unlock()
+	panicObserver = observer
+}
+
+// RecordPanic tells the panic observer about a recovered panic. PanicError
+// records the panics it describes; code recovering without it calls this.
+func RecordPanic(op string, panicValue interface{}) {
+	observerMu.RLock()
+	observer := panicObserver
+	observerMu.RUnlock()
+	if observer != nil {
+		observer(op, panicValue)
+	}
+}
+
+// SetDisableObserver sets the function told about disabled operations; nil
+// stops observing.
+func SetDisableObserver(observer DisableObserver) {
+	observerMu.Lock()
+	defer observerMu.Unlock()
+	disableObserver = observer
+}
+
+// RecordDisabled tells the disable observer that op was turned off, and why.
+func RecordDisabled(op, reason string) {
+	observerMu.RLock()
+	observer := disableObserver
+	observerMu.RUnlock()
+	if observer != nil {
+		observer(op, reason)
+	}
+}
+
+// SetFailureObserver sets the function told about failures counted against
+// error budgets; nil stops observing.
+func SetFailureObserver(observer FailureObserver) {
+	observerMu.Lock()
+	defer observerMu.Unlock()
+	failureObserver = observer
+}
+
+// RecordFailure tells the failure observer that op failed at at.
+func RecordFailure(op string, at time.Time) {
+	observerMu.RLock()
+	observer := failureObserver
+	observerMu.RUnlock()
+	if observer != nil {
+		observer(op, at)
+	}
+}
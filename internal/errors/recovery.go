@@ -107,8 +107,8 @@ func (pr *PanicRecovery) HandleRecovery(op string, r interface{}) {
 	stack := debug.Stack()
 
 	// Log by default if enabled
+	err := PanicError(op, r)
 	if pr.logByDefault {
-		err := PanicError(op, r)
 		LogErrorWithLevel(err)
 	}
 
@@ -152,8 +152,8 @@ func (pr *PanicRecovery) RecoverWithOperation(op string) bool {
 	stack := debug.Stack()
 
 	// Log by default if enabled
+	err := PanicError(op, r)
 	if pr.logByDefault {
-		err := PanicError(op, r)
 		LogErrorWithLevel(err)
 	}
 
@@ -0,0 +1,280 @@
+// Package export converts transcript data into normalized tables and writes
+// them as CSV or JSON for analysis in spreadsheets or BI tools
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/store"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+// Supported output formats
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// Supported tables
+const (
+	TableEvents = "events"
+	TableTools  = "tools"
+	TableTokens = "tokens"
+	TableAll    = "all" // JSON only: every table in one document
+)
+
+// ToolUsage is one row of the tools table
+type ToolUsage struct {
+	SessionID string `json:"session_id"`
+	ToolName  string `json:"tool_name"`
+	Calls     int    `json:"calls"`
+	Errors    int    `json:"errors"`
+}
+
+// TokenUsage is one row of the tokens table (per session, per hour)
+type TokenUsage struct {
+	SessionID           string    `json:"session_id"`
+	Hour                time.Time `json:"hour"`
+	Model               string    `json:"model"`
+	InputTokens         int       `json:"input_tokens"`
+	OutputTokens        int       `json:"output_tokens"`
+	CacheCreationTokens int       `json:"cache_creation_tokens"`
+	CacheReadTokens     int       `json:"cache_read_tokens"`
+	Cost                float64   `json:"cost"`
+}
+
+// Dataset holds the normalized tables
+type Dataset struct {
+	Events []store.Event `json:"events"`
+	Tools  []ToolUsage   `json:"tools"`
+	Tokens []TokenUsage  `json:"tokens"`
+}
+
+// Collect reads the given transcripts and builds the dataset for events matching filter
+func Collect(ctx context.Context, paths []string, filter store.Filter) (*Dataset, error) {
+	var events []store.Event
+	for _, path := range paths {
+		sessionID := store.SessionIDFromPath(path)
+		if filter.SessionID != "" && sessionID != filter.SessionID {
+			continue
+		}
+		fileEvents, err := readTranscript(ctx, path, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		for _, ev := range fileEvents {
+			if filter.Match(ev) {
+				events = append(events, ev)
+			}
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+
+	return &Dataset{
+		Events: events,
+		Tools:  toolUsage(events),
+		Tokens: tokenUsage(events),
+	}, nil
+}
+
+// readTranscript decodes every event in a transcript file
+func readTranscript(ctx context.Context, path, sessionID string) ([]store.Event, error) {
+	file, err := transcript.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transcript: %w", err)
+	}
+	defer file.Close()
+
+	lines := transcript.NewLineReader(file)
+	var events []store.Event
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		line, _, err := lines.Next()
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		events = append(events, store.DecodeLine(sessionID, line)...)
+	}
+}
+
+// toolUsage aggregates tool calls and errors per session and tool
+func toolUsage(events []store.Event) []ToolUsage {
+	type key struct{ session, tool string }
+	rows := make(map[key]*ToolUsage)
+	toolByID := make(map[key]string)
+
+	for _, ev := range events {
+		switch ev.Kind {
+		case store.KindToolUse:
+			k := key{ev.SessionID, ev.ToolName}
+			row, ok := rows[k]
+			if !ok {
+				row = &ToolUsage{SessionID: ev.SessionID, ToolName: ev.ToolName}
+				rows[k] = row
+			}
+			row.Calls++
+			if ev.ToolUseID != "" {
+				toolByID[key{ev.SessionID, ev.ToolUseID}] = ev.ToolName
+			}
+		case store.KindToolResult:
+			if !ev.IsError {
+				continue
+			}
+			if name, ok := toolByID[key{ev.SessionID, ev.ToolUseID}]; ok {
+				rows[key{ev.SessionID, name}].Errors++
+			}
+		}
+	}
+
+	result := make([]ToolUsage, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, *row)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].SessionID != result[j].SessionID {
+			return result[i].SessionID < result[j].SessionID
+		}
+		if result[i].Calls != result[j].Calls {
+			return result[i].Calls > result[j].Calls
+		}
+		return result[i].ToolName < result[j].ToolName
+	})
+	return result
+}
+
+// tokenUsage aggregates token usage per session, hour and model
+func tokenUsage(events []store.Event) []TokenUsage {
+	type key struct {
+		session string
+		hour    time.Time
+		model   string
+	}
+	rows := make(map[key]*TokenUsage)
+	var order []key
+
+	for _, ev := range events {
+		if ev.Kind != store.KindMessage {
+			continue
+		}
+		k := key{ev.SessionID, ev.Timestamp.UTC().Truncate(time.Hour), ev.Model}
+		row, ok := rows[k]
+		if !ok {
+			row = &TokenUsage{SessionID: k.session, Hour: k.hour, Model: k.model}
+			rows[k] = row
+			order = append(order, k)
+		}
+		row.InputTokens += ev.InputTokens
+		row.OutputTokens += ev.OutputTokens
+		row.CacheCreationTokens += ev.CacheCreationTokens
+		row.CacheReadTokens += ev.CacheReadTokens
+		row.Cost += ev.Cost
+	}
+
+	result := make([]TokenUsage, 0, len(order))
+	for _, k := range order {
+		result = append(result, *rows[k])
+	}
+	return result
+}
+
+// Write writes the requested table of the dataset to w in the given format
+func Write(w io.Writer, ds *Dataset, format, table string) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, ds, table)
+	case FormatCSV:
+		return writeCSV(w, ds, table)
+	default:
+		return fmt.Errorf("unsupported format %q (use csv or json)", format)
+	}
+}
+
+// writeJSON writes one table, or all of them, as indented JSON
+func writeJSON(w io.Writer, ds *Dataset, table string) error {
+	var v interface{}
+	switch table {
+	case TableEvents:
+		v = ds.Events
+	case TableTools:
+		v = ds.Tools
+	case TableTokens:
+		v = ds.Tokens
+	case TableAll:
+		v = ds
+	default:
+		return fmt.Errorf("unknown table %q", table)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeCSV writes a single table as CSV with a header row
+func writeCSV(w io.Writer, ds *Dataset, table string) error {
+	cw := csv.NewWriter(w)
+
+	switch table {
+	case TableEvents:
+		_ = cw.Write([]string{"session_id", "timestamp", "kind", "tool_name", "tool_use_id", "target", "model",
+			"input_tokens", "output_tokens", "cache_creation_tokens", "cache_read_tokens", "cost", "is_error"})
+		for _, ev := range ds.Events {
+			_ = cw.Write([]string{
+				ev.SessionID, formatTime(ev.Timestamp), ev.Kind, ev.ToolName, ev.ToolUseID, ev.Target, ev.Model,
+				strconv.Itoa(ev.InputTokens), strconv.Itoa(ev.OutputTokens),
+				strconv.Itoa(ev.CacheCreationTokens), strconv.Itoa(ev.CacheReadTokens),
+				formatCost(ev.Cost), strconv.FormatBool(ev.IsError),
+			})
+		}
+	case TableTools:
+		_ = cw.Write([]string{"session_id", "tool_name", "calls", "errors"})
+		for _, row := range ds.Tools {
+			_ = cw.Write([]string{row.SessionID, row.ToolName, strconv.Itoa(row.Calls), strconv.Itoa(row.Errors)})
+		}
+	case TableTokens:
+		_ = cw.Write([]string{"session_id", "hour", "model", "input_tokens", "output_tokens",
+			"cache_creation_tokens", "cache_read_tokens", "cost"})
+		for _, row := range ds.Tokens {
+			_ = cw.Write([]string{
+				row.SessionID, formatTime(row.Hour), row.Model,
+				strconv.Itoa(row.InputTokens), strconv.Itoa(row.OutputTokens),
+				strconv.Itoa(row.CacheCreationTokens), strconv.Itoa(row.CacheReadTokens),
+				formatCost(row.Cost),
+			})
+		}
+	case TableAll:
+		return fmt.Errorf("csv output needs a single table (events, tools or tokens)")
+	default:
+		return fmt.Errorf("unknown table %q", table)
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatTime renders a timestamp as RFC 3339, or empty when unknown
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// formatCost renders a USD cost with enough precision for small sessions
+func formatCost(cost float64) string {
+	return strconv.FormatFloat(cost, 'f', 6, 64)
+}
@@ -0,0 +1,165 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/store"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+const testTranscript = `{"type":"assistant","timestamp":"2026-01-11T10:05:00.000Z","message":{"role":"assistant","model":"claude-sonnet-4","usage":{"input_tokens":1000,"output_tokens":200},"content":[{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"/p/main.go"}}]}}
+{"type":"user","timestamp":"2026-01-11T10:05:01.000Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1"}]}}
+{"type":"assistant","timestamp":"2026-01-11T11:10:00.000Z","message":{"role":"assistant","model":"claude-sonnet-4","usage":{"input_tokens":2000,"output_tokens":400},"content":[{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"go test ./..."}}]}}
+{"type":"user","timestamp":"2026-01-11T11:10:05.000Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t2","is_error":true}]}}
+`
+
+func writeTranscript(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "project", "abc-123.jsonl")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(testTranscript), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCollect(t *testing.T) {
+	path := writeTranscript(t)
+
+	ds, err := Collect(context.Background(), []string{path}, store.Filter{})
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if len(ds.Events) != 6 {
+		t.Errorf("events = %d, want 6", len(ds.Events))
+	}
+
+	byName := make(map[string]ToolUsage)
+	for _, row := range ds.Tools {
+		byName[row.ToolName] = row
+	}
+	if got := byName["Bash"]; got.Calls != 1 || got.Errors != 1 || got.SessionID != "abc-123" {
+		t.Errorf("Bash usage = %+v", got)
+	}
+	if got := byName["Read"]; got.Calls != 1 || got.Errors != 0 {
+		t.Errorf("Read usage = %+v", got)
+	}
+
+	if len(ds.Tokens) != 2 {
+		t.Fatalf("token rows = %d, want 2", len(ds.Tokens))
+	}
+	if want := time.Date(2026, 1, 11, 10, 0, 0, 0, time.UTC); !ds.Tokens[0].Hour.Equal(want) {
+		t.Errorf("first hour = %v, want %v", ds.Tokens[0].Hour, want)
+	}
+	if ds.Tokens[1].InputTokens != 2000 || ds.Tokens[1].Model != "claude-sonnet-4" {
+		t.Errorf("second token row = %+v", ds.Tokens[1])
+	}
+}
+
+func TestCollect_Archived(t *testing.T) {
+	path, err := transcript.Archive(writeTranscript(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds, err := Collect(context.Background(), []string{path}, store.Filter{})
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if len(ds.Events) != 6 || ds.Events[0].SessionID != "abc-123" {
+		t.Errorf("Collect() from archive = %d events, session %q; want 6, abc-123", len(ds.Events), ds.Events[0].SessionID)
+	}
+}
+
+func TestCollect_Filter(t *testing.T) {
+	path := writeTranscript(t)
+
+	tests := []struct {
+		name   string
+		filter store.Filter
+		want   int
+	}{
+		{"other session", store.Filter{SessionID: "nope"}, 0},
+		{"matching session", store.Filter{SessionID: "abc-123"}, 6},
+		{"since", store.Filter{Since: time.Date(2026, 1, 11, 11, 0, 0, 0, time.UTC)}, 3},
+		{"until", store.Filter{Until: time.Date(2026, 1, 11, 11, 0, 0, 0, time.UTC)}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, err := Collect(context.Background(), []string{path}, tt.filter)
+			if err != nil {
+				t.Fatalf("Collect() error = %v", err)
+			}
+			if len(ds.Events) != tt.want {
+				t.Errorf("events = %d, want %d", len(ds.Events), tt.want)
+			}
+		})
+	}
+}
+
+func TestWrite_CSV(t *testing.T) {
+	ds, err := Collect(context.Background(), []string{writeTranscript(t)}, store.Filter{})
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+
+	for _, table := range []string{TableEvents, TableTools, TableTokens} {
+		var buf bytes.Buffer
+		if err := Write(&buf, ds, FormatCSV, table); err != nil {
+			t.Fatalf("Write(%s) error = %v", table, err)
+		}
+		records, err := csv.NewReader(&buf).ReadAll()
+		if err != nil {
+			t.Fatalf("invalid CSV for %s: %v", table, err)
+		}
+		if records[0][0] != "session_id" {
+			t.Errorf("%s header = %v", table, records[0])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, ds, FormatCSV, TableTools); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "abc-123,Bash,1,1") {
+		t.Errorf("tools CSV missing Bash row:\n%s", buf.String())
+	}
+
+	if err := Write(&buf, ds, FormatCSV, TableAll); err == nil {
+		t.Error("CSV with all tables should fail")
+	}
+}
+
+func TestWrite_JSON(t *testing.T) {
+	ds, err := Collect(context.Background(), []string{writeTranscript(t)}, store.Filter{})
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, ds, FormatJSON, TableAll); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var decoded Dataset
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(decoded.Events) != 6 || len(decoded.Tools) != 2 || len(decoded.Tokens) != 2 {
+		t.Errorf("decoded = %d events, %d tools, %d tokens", len(decoded.Events), len(decoded.Tools), len(decoded.Tokens))
+	}
+
+	if err := Write(&buf, ds, "parquet", TableEvents); err == nil {
+		t.Error("unsupported format should fail")
+	}
+}
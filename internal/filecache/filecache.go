@@ -0,0 +1,74 @@
+// Package filecache persists small JSON caches shared by claude-hud
+// processes, such as the last connectivity check or CI result. Claude Code
+// starts a process per statusline render and several sessions render at
+// once, so a cache file is replaced atomically: readers see the old or the
+// new content, never a partial write
+package filecache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Load reads the cache file at path into v. An empty path, a missing file or
+// content that does not parse leave v as it was and return false, so a
+// cache that cannot be read starts empty
+func Load(path string, v any) bool {
+	if path == "" {
+		return false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+// Save writes v to the cache file at path, creating its directory. The data
+// goes to a temporary file in the same directory first and is renamed over
+// path, so concurrent readers and writers never see a partial file. An
+// empty path saves nothing
+func Save(path string, v any) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		// CreateTemp makes the file private; caches stay as readable as before
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// Prune deletes the entries of m whose time, as told by at, is more than ttl
+// before now, e.g. results for commits nobody asks about any more
+func Prune[K comparable, V any](m map[K]V, now time.Time, ttl time.Duration, at func(V) time.Time) {
+	for key, value := range m {
+		if now.Sub(at(value)) > ttl {
+			delete(m, key)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package filecache
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "cache.json")
+
+	var missing map[string]int
+	if Load(path, &missing) || missing != nil {
+		t.Errorf("Load() of a missing file = %v, want false and v untouched", missing)
+	}
+
+	if err := Save(path, map[string]int{"a": 1}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	var got map[string]int
+	if !Load(path, &got) || got["a"] != 1 {
+		t.Errorf("Load() = %v, want the saved map", got)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0644 {
+		t.Errorf("cache file mode = %v (%v), want 0644", info.Mode().Perm(), err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("cache directory holds %d files, want no temporary files left", len(entries))
+	}
+
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	kept := map[string]int{"b": 2}
+	if Load(path, &kept) {
+		t.Error("Load() of a corrupt file succeeded")
+	}
+
+	if Load("", &got) || Save("", got) != nil {
+		t.Error("an empty path should load nothing and save nothing")
+	}
+}
+
+func TestSaveConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = Save(path, map[string]int{"writer": i})
+		}()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var got map[string]int
+			if _, err := os.Stat(path); err == nil && !Load(path, &got) {
+				t.Error("Load() read a partial file")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestPrune(t *testing.T) {
+	now := time.Now()
+	m := map[string]time.Time{"fresh": now.Add(-time.Minute), "stale": now.Add(-2 * time.Hour)}
+	Prune(m, now, time.Hour, func(at time.Time) time.Time { return at })
+	if _, ok := m["stale"]; ok || len(m) != 1 {
+		t.Errorf("Prune() left %v, want only the fresh entry", m)
+	}
+}
@@ -0,0 +1,198 @@
+// Package fleet lays the worktrees of one repository side by side, for
+// running several agents at once, each in a worktree of its own: each
+// worktree's branch and uncommitted changes, and the latest Claude Code
+// session started in it, with what it is doing and how full its context is
+package fleet
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/git"
+	"github.com/ll931217/claude-hud-enhanced/internal/paths"
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+// What a worktree's session is doing
+const (
+	Working  = "working"  // In the middle of a turn
+	Approval = "approval" // Waiting on a permission prompt
+	Idle     = "idle"     // Done with its turn, waiting for a prompt
+	Inactive = "inactive" // Nothing happened within the active window
+)
+
+// DefaultActive is how long a session may go without activity before it
+// counts as inactive, unless Options say otherwise
+const DefaultActive = 30 * time.Minute
+
+// writingWindow is how recently a transcript must have been written for a
+// session the hooks have not recorded to count as working
+const writingWindow = 2 * time.Minute
+
+// Member is a worktree of the repository and its latest session
+type Member struct {
+	git.Worktree
+	Current   bool   // The worktree Read was given a path in
+	Dirty     bool   // Has uncommitted changes
+	Changes   int    // Changed and untracked files
+	Conflicts int    // Unmerged paths
+	GitError  string // Why its changes could not be read, e.g. git timed out
+	Session   *Session
+}
+
+// Session is the latest session started in a worktree
+type Session struct {
+	ID             string
+	Name           string // Set with claude-hud name, if any
+	TranscriptPath string
+	LastActive     time.Time // Last hook event or, without the hooks, last transcript write
+	State          string    // Working, Approval, Idle or Inactive
+	ContextPercent int       // Share of the context window in use; valid when ContextKnown
+	ContextKnown   bool
+}
+
+// Options tell Read where to find sessions and how to read them
+type Options struct {
+	ProjectsDir string                               // Where Claude Code keeps transcripts
+	State       *session.State                       // Hook records; nil works from transcripts alone
+	Transcripts func(path string) *transcript.Parser // Parsers to reuse; nil parses each transcript afresh
+	Git         func(repoPath string) *git.Detector  // Detectors to reuse; nil creates them
+	Active      time.Duration                        // Sessions idle for longer are Inactive; zero means DefaultActive
+	Now         time.Time                            // Zero means time.Now()
+}
+
+// Read returns the worktrees of the repository containing repoPath, main
+// worktree first, with the latest session started at each one's root.
+// Worktrees whose directory is gone, and a bare main repository, are left
+// out. Git and transcripts are read for all worktrees at once; ctx bounds
+// the whole read
+func Read(ctx context.Context, repoPath string, opts Options) ([]Member, error) {
+	if abs, err := filepath.Abs(repoPath); err == nil {
+		repoPath = abs
+	}
+	worktrees, err := git.ListWorktrees(ctx, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	if opts.Active <= 0 {
+		opts.Active = DefaultActive
+	}
+
+	var members []Member
+	current, currentLen := -1, -1
+	for _, wt := range worktrees {
+		if wt.Bare || wt.Prunable {
+			continue
+		}
+		// Worktrees can nest, so the current one is the deepest holding repoPath
+		if paths.Within(repoPath, wt.Path) && len(wt.Path) > currentLen {
+			current, currentLen = len(members), len(wt.Path)
+		}
+		members = append(members, Member{Worktree: wt})
+	}
+	if current >= 0 {
+		members[current].Current = true
+	}
+
+	var wg sync.WaitGroup
+	for i := range members {
+		wg.Add(1)
+		go func(m *Member) {
+			defer wg.Done()
+			m.readChanges(ctx, opts)
+			m.Session = readSession(ctx, m.Path, opts)
+		}(&members[i])
+	}
+	wg.Wait()
+	return members, nil
+}
+
+// readChanges counts the worktree's uncommitted changes
+func (m *Member) readChanges(ctx context.Context, opts Options) {
+	detector := git.NewDetector
+	if opts.Git != nil {
+		detector = opts.Git
+	}
+	status, err := detector(m.Path).Changes(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			m.GitError = "git timed out"
+		} else {
+			m.GitError = err.Error()
+		}
+		return
+	}
+	m.Dirty, m.Changes, m.Conflicts = status.Dirty, status.ChangedFiles(), status.Conflicts
+}
+
+// readSession returns the latest session started in dir, or nil
+func readSession(ctx context.Context, dir string, opts Options) *Session {
+	path := transcript.LatestAt(opts.ProjectsDir, dir)
+	if path == "" {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+
+	sess := &Session{
+		ID:             strings.TrimSuffix(filepath.Base(path), ".jsonl"),
+		TranscriptPath: path,
+		LastActive:     info.ModTime(),
+	}
+	var record *session.SessionState
+	if opts.State != nil {
+		record = opts.State.FindByTranscript(path)
+	}
+	sess.State = state(record, info.ModTime(), opts)
+	if record != nil {
+		sess.Name = record.Name
+		if record.LastEventAt.After(sess.LastActive) {
+			sess.LastActive = record.LastEventAt
+		}
+	}
+
+	parser := transcript.NewParser
+	if opts.Transcripts != nil {
+		parser = opts.Transcripts
+	}
+	p := parser(path)
+	// Keep going on parse errors: data from earlier parses is still usable
+	_ = p.Parse(ctx)
+	if cw := p.GetContextWindow(); cw != nil && cw.ContextWindowSize > 0 {
+		sess.ContextPercent, sess.ContextKnown = cw.Percentage(), true
+	}
+	return sess
+}
+
+// state tells what a session is doing from its hook record or, for a
+// session the hooks have not seen, from when its transcript was written
+func state(record *session.SessionState, written time.Time, opts Options) string {
+	if record == nil {
+		switch age := opts.Now.Sub(written); {
+		case age <= writingWindow:
+			return Working
+		case age <= opts.Active:
+			return Idle
+		}
+		return Inactive
+	}
+	switch {
+	case record.Approval != nil:
+		return Approval
+	case opts.Now.Sub(record.LastEventAt) > opts.Active:
+		return Inactive
+	case !record.Stopped:
+		return Working
+	}
+	return Idle
+}
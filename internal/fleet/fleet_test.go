@@ -0,0 +1,109 @@
+package fleet
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+func TestState(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	opts := Options{Active: 30 * time.Minute, Now: now}
+	tests := []struct {
+		name    string
+		record  *session.SessionState
+		written time.Time
+		want    string
+	}{
+		{"mid-turn", &session.SessionState{LastEventAt: now.Add(-time.Minute)}, now, Working},
+		{"turn done", &session.SessionState{LastEventAt: now.Add(-time.Minute), Stopped: true}, now, Idle},
+		{"prompt pending", &session.SessionState{LastEventAt: now.Add(-time.Hour), Approval: &session.ApprovalRequest{}}, now, Approval},
+		{"quiet for long", &session.SessionState{LastEventAt: now.Add(-time.Hour)}, now, Inactive},
+		{"no hooks, writing", nil, now.Add(-30 * time.Second), Working},
+		{"no hooks, quiet", nil, now.Add(-10 * time.Minute), Idle},
+		{"no hooks, old", nil, now.Add(-2 * time.Hour), Inactive},
+	}
+	for _, tt := range tests {
+		if got := state(tt.record, tt.written, opts); got != tt.want {
+			t.Errorf("%s: state() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	dir := t.TempDir()
+	repo := filepath.Join(dir, "app")
+	auth := filepath.Join(dir, "app-auth")
+	spike := filepath.Join(dir, "app-spike")
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@test.com",
+			"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@test.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Skipf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	if err := os.Mkdir(repo, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	run("init", "-q", "-b", "main")
+	run("commit", "-q", "--allow-empty", "-m", "init")
+	run("worktree", "add", "-q", "-b", "auth", auth)
+	run("worktree", "add", "-q", "--detach", spike)
+	if err := os.WriteFile(filepath.Join(auth, "token.go"), []byte("package auth"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	projects := t.TempDir()
+	authTranscript := filepath.Join(transcript.ProjectDirFor(projects, auth), "s-auth.jsonl")
+	if err := os.MkdirAll(filepath.Dir(authTranscript), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	line := `{"type":"assistant","timestamp":"` + now.UTC().Format(time.RFC3339) + `","message":{"id":"m1","role":"assistant","model":"claude-sonnet-4-5","usage":{"input_tokens":60000,"cache_read_input_tokens":40000,"output_tokens":100},"content":[{"type":"text","text":"ok"}]}}` + "\n"
+	if err := os.WriteFile(authTranscript, []byte(line), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	state := session.NewState()
+	record := state.Session("s-auth")
+	record.TranscriptPath, record.Name = authTranscript, "auth refactor"
+	record.LastEventAt = now
+	record.Approval = &session.ApprovalRequest{}
+
+	members, err := Read(context.Background(), auth, Options{ProjectsDir: projects, State: state})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(members) != 3 {
+		t.Fatalf("Read() returned %d worktrees, want 3: %+v", len(members), members)
+	}
+
+	main, authMember, spikeMember := members[0], members[1], members[2]
+	if main.Branch != "main" || main.Current || main.Dirty || main.Session != nil {
+		t.Errorf("main worktree = %+v, want clean, not current, without a session", main)
+	}
+	if !authMember.Current || !authMember.Dirty || authMember.Changes != 1 {
+		t.Errorf("auth worktree = %+v, want current with one change", authMember)
+	}
+	sess := authMember.Session
+	if sess == nil {
+		t.Fatal("auth worktree has no session")
+	}
+	if sess.ID != "s-auth" || sess.Name != "auth refactor" || sess.State != Approval {
+		t.Errorf("auth session = %+v, want s-auth, named, waiting for approval", sess)
+	}
+	if !sess.ContextKnown || sess.ContextPercent == 0 {
+		t.Errorf("auth session context = %d%% (known %v), want it read from the transcript", sess.ContextPercent, sess.ContextKnown)
+	}
+	if spikeMember.Branch != "(detached)" || spikeMember.Name() != "app-spike" {
+		t.Errorf("spike worktree = %+v, want detached and named after its directory", spikeMember)
+	}
+}
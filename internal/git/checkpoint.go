@@ -0,0 +1,92 @@
+package git
+
+import (
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/filecache"
+)
+
+// checkpointEntry records since when a repository's uncommitted changes have
+// been over the checkpoint thresholds
+type checkpointEntry struct {
+	Since    time.Time `json:"since"`
+	Notified bool      `json:"notified,omitempty"`
+}
+
+// CheckpointTracker tracks how long repositories have had more uncommitted
+// changes than is comfortable to lose, so a commit can be suggested
+// With a cache path, the tracking carries over between statusline processes
+type CheckpointTracker struct {
+	CachePath string
+
+	mu      sync.Mutex
+	entries map[string]checkpointEntry // Keyed by repository path
+	loaded  bool
+}
+
+// NewCheckpointTracker creates a tracker persisted at cachePath ("" keeps it in memory)
+func NewCheckpointTracker(cachePath string) *CheckpointTracker {
+	return &CheckpointTracker{
+		CachePath: cachePath,
+		entries:   make(map[string]checkpointEntry),
+	}
+}
+
+// Observe records whether repo's changes are over the thresholds now and
+// returns for how long they have been; a commit that brings them under
+// resets the clock
+func (c *CheckpointTracker) Observe(repo string, over bool, now time.Time) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.load()
+	entry, ok := c.entries[repo]
+	switch {
+	case !over && ok:
+		delete(c.entries, repo)
+		c.save()
+		return 0
+	case !over:
+		return 0
+	case !ok || now.Before(entry.Since):
+		c.entries[repo] = checkpointEntry{Since: now}
+		c.save()
+		return 0
+	}
+	return now.Sub(entry.Since)
+}
+
+// Notify reports whether a reminder for repo's current stretch of changes
+// is still due, and records it as sent
+func (c *CheckpointTracker) Notify(repo string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.load()
+	entry, ok := c.entries[repo]
+	if !ok || entry.Notified {
+		return false
+	}
+	entry.Notified = true
+	c.entries[repo] = entry
+	c.save()
+	return true
+}
+
+// load reads the persisted entries once; a missing or corrupt file starts empty
+func (c *CheckpointTracker) load() {
+	if c.loaded {
+		return
+	}
+	c.loaded = true
+	var entries map[string]checkpointEntry
+	if filecache.Load(c.CachePath, &entries) {
+		maps.Copy(c.entries, entries)
+	}
+}
+
+func (c *CheckpointTracker) save() {
+	_ = filecache.Save(c.CachePath, c.entries)
+}
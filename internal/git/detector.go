@@ -7,10 +7,14 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+	"github.com/ll931217/claude-hud-enhanced/internal/i18n"
+	"github.com/ll931217/claude-hud-enhanced/internal/styled"
 )
 
 // Status represents the git status of a repository
@@ -23,17 +27,41 @@ type Status struct {
 	Added        int
 	Deleted      int
 	Untracked    int
+	Conflicts    int // Unmerged paths left by a merge, rebase or cherry-pick
+	Insertions   int // Uncommitted lines added to tracked files
+	Deletions    int // Uncommitted lines removed from tracked files
 	Ahead        int
 	Behind       int
 	Stashed      int
+	LastCommit   Commit // Zero in a repository without commits
+	Base         string // Branch Ahead/Behind of the base are counted against, e.g. "main"
+	BaseAhead    int
+	BaseBehind   int
 }
 
+// Commit describes a commit
+type Commit struct {
+	Subject string
+	Author  string
+	Time    time.Time // Committer date
+}
+
+// AutoBaseBranch picks the base branch from origin/HEAD, then main or master
+const AutoBaseBranch = "auto"
+
+// diffStatTTL is how long a diff stat is reused; diffing a large tree costs
+// more than the rest of the status together
+const diffStatTTL = 5 * time.Second
+
 // Detector handles git status and worktree detection
 type Detector struct {
-	mu        sync.RWMutex
-	repoPath  string
-	lastCheck int64
-	status    *Status
+	mu         sync.RWMutex
+	repoPath   string
+	lastCheck  int64
+	status     *Status
+	baseBranch string // "" skips the base comparison
+	diffStat   DiffStat
+	diffStatAt time.Time
 }
 
 // NewDetector creates a new git detector for the given path
@@ -76,8 +104,12 @@ func (d *Detector) Detect(ctx context.Context) (*Status, error) {
 
 		// Get status counts
 		if err := d.getStatusCounts(ctx, status); err == nil {
-			status.Dirty = status.Modified > 0 || status.Added > 0 ||
-				status.Deleted > 0 || status.Untracked > 0
+			status.Dirty = status.ChangedFiles() > 0 || status.Conflicts > 0
+		}
+
+		// Get the size of the uncommitted changes
+		if stat, err := d.DiffStat(ctx); err == nil {
+			status.Insertions, status.Deletions = stat.Insertions, stat.Deletions
 		}
 
 		// Get ahead/behind
@@ -86,11 +118,28 @@ func (d *Detector) Detect(ctx context.Context) (*Status, error) {
 			status.Behind = behind
 		}
 
+		// Get the last commit
+		if commit, err := d.getLastCommit(ctx); err == nil {
+			status.LastCommit = commit
+		}
+
 		// Get stash count
 		if stashed, err := d.getStashCount(ctx); err == nil {
 			status.Stashed = stashed
 		}
 
+		// Compare with the base branch, which works without an upstream
+		d.mu.RLock()
+		baseBranch := d.baseBranch
+		d.mu.RUnlock()
+		if baseBranch != "" && status.Branch != "(detached)" {
+			if ref, name, err := d.resolveBaseBranch(ctx, baseBranch); err == nil && name != status.Branch {
+				if ahead, behind, err := d.countAheadBehind(ctx, ref); err == nil {
+					status.Base, status.BaseAhead, status.BaseBehind = name, ahead, behind
+				}
+			}
+		}
+
 		d.mu.Lock()
 		d.status = status
 		d.mu.Unlock()
@@ -177,7 +226,9 @@ func (d *Detector) getWorktreeName(ctx context.Context) (string, error) {
 
 // getStatusCounts gets the count of changed files
 func (d *Detector) getStatusCounts(ctx context.Context, status *Status) error {
-	cmd := exec.CommandContext(ctx, "git", "status", "--porcelain")
+	// Without optional locks, a status killed by the timeout cannot leave
+	// index.lock behind to break the user's next git command
+	cmd := exec.CommandContext(ctx, "git", "--no-optional-locks", "status", "--porcelain")
 	cmd.Dir = d.repoPath
 	output, err := cmd.Output()
 	if err != nil {
@@ -196,6 +247,11 @@ func (d *Detector) getStatusCounts(ctx context.Context, status *Status) error {
 		index := line[0]
 		worktree := line[1]
 
+		if isUnmerged(index, worktree) {
+			status.Conflicts++
+			continue
+		}
+
 		switch index {
 		case 'M':
 			status.Modified++
@@ -220,9 +276,77 @@ func (d *Detector) getStatusCounts(ctx context.Context, status *Status) error {
 	return nil
 }
 
+// SetBaseBranch sets the branch Detect compares HEAD with, e.g. "main";
+// AutoBaseBranch detects it and "" turns the comparison off
+func (d *Detector) SetBaseBranch(branch string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.baseBranch = branch
+}
+
+// resolveBaseBranch returns the ref to compare with and the name to show
+func (d *Detector) resolveBaseBranch(ctx context.Context, branch string) (ref, name string, err error) {
+	if branch != AutoBaseBranch {
+		cmd := exec.CommandContext(ctx, "git", "rev-parse", "--verify", "--quiet", branch+"^{commit}")
+		cmd.Dir = d.repoPath
+		if err := cmd.Run(); err != nil {
+			return "", "", fmt.Errorf("base branch %s not found", branch)
+		}
+		return branch, strings.TrimPrefix(branch, "origin/"), nil
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "for-each-ref", "--format=%(refname) %(symref)",
+		"refs/remotes/origin/HEAD", "refs/heads/main", "refs/heads/master",
+		"refs/remotes/origin/main", "refs/remotes/origin/master")
+	cmd.Dir = d.repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", "", err
+	}
+	ref, name = pickBaseRef(string(output))
+	if ref == "" {
+		return "", "", fmt.Errorf("no main or master branch")
+	}
+	return ref, name, nil
+}
+
+// pickBaseRef chooses the base from for-each-ref "refname symref" lines:
+// the branch origin/HEAD points at, then a local main or master, then
+// origin's main or master
+func pickBaseRef(output string) (ref, name string) {
+	refs := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			refs[fields[0]] = strings.Join(fields[1:], "")
+		}
+	}
+	if target := refs["refs/remotes/origin/HEAD"]; target != "" {
+		return target, strings.TrimPrefix(target, "refs/remotes/origin/")
+	}
+	for _, candidate := range []string{"refs/heads/main", "refs/heads/master", "refs/remotes/origin/main", "refs/remotes/origin/master"} {
+		if _, ok := refs[candidate]; ok {
+			return candidate, candidate[strings.LastIndex(candidate, "/")+1:]
+		}
+	}
+	return "", ""
+}
+
+// isUnmerged reports whether a porcelain XY pair marks an unmerged path:
+// DD, AU, UD, UA, DU, AA or UU
+func isUnmerged(index, worktree byte) bool {
+	return index == 'U' || worktree == 'U' ||
+		(index == 'A' && worktree == 'A') || (index == 'D' && worktree == 'D')
+}
+
 // getAheadBehind gets the ahead/behind count for the current branch
 func (d *Detector) getAheadBehind(ctx context.Context) (ahead, behind int, err error) {
-	cmd := exec.CommandContext(ctx, "git", "rev-list", "--left-right", "--count", "HEAD...@{u}")
+	return d.countAheadBehind(ctx, "@{u}")
+}
+
+// countAheadBehind counts the commits HEAD has that ref lacks, and the reverse
+func (d *Detector) countAheadBehind(ctx context.Context, ref string) (ahead, behind int, err error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-list", "--left-right", "--count", "HEAD..."+ref)
 	cmd.Dir = d.repoPath
 	output, err := cmd.Output()
 	if err != nil {
@@ -261,6 +385,117 @@ func (d *Detector) getStashCount(ctx context.Context) (int, error) {
 	return count, nil
 }
 
+// getLastCommit returns the subject, author and date of HEAD
+func (d *Detector) getLastCommit(ctx context.Context) (Commit, error) {
+	cmd := exec.CommandContext(ctx, "git", "log", "-1", "--format=%s%x00%an%x00%ct")
+	cmd.Dir = d.repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return Commit{}, err
+	}
+	return parseLastCommit(string(output))
+}
+
+// parseLastCommit parses "subject\x00author\x00unix time" from git log
+func parseLastCommit(output string) (Commit, error) {
+	fields := strings.Split(strings.TrimRight(output, "\n"), "\x00")
+	if len(fields) != 3 {
+		return Commit{}, fmt.Errorf("unexpected git log output %q", output)
+	}
+	seconds, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return Commit{}, fmt.Errorf("invalid commit date %q", fields[2])
+	}
+	return Commit{Subject: fields[0], Author: fields[1], Time: time.Unix(seconds, 0)}, nil
+}
+
+// DiffStat is the size of uncommitted changes to tracked files
+type DiffStat struct {
+	Files      int
+	Insertions int
+	Deletions  int
+}
+
+// Lines returns the number of changed lines
+func (d DiffStat) Lines() int {
+	return d.Insertions + d.Deletions
+}
+
+// DiffStat measures staged and unstaged changes against HEAD
+// The result is reused for a few seconds
+func (d *Detector) DiffStat(ctx context.Context) (DiffStat, error) {
+	d.mu.RLock()
+	stat, at := d.diffStat, d.diffStatAt
+	d.mu.RUnlock()
+	if !at.IsZero() && time.Since(at) < diffStatTTL {
+		return stat, nil
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "--no-optional-locks", "diff", "HEAD", "--shortstat")
+	cmd.Dir = d.repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return DiffStat{}, err
+	}
+	stat = parseShortstat(string(output))
+
+	d.mu.Lock()
+	d.diffStat, d.diffStatAt = stat, time.Now()
+	d.mu.Unlock()
+	return stat, nil
+}
+
+// parseShortstat parses " 3 files changed, 312 insertions(+), 88 deletions(-)";
+// either count is left out when zero, and clean trees print nothing
+func parseShortstat(output string) DiffStat {
+	var stat DiffStat
+	for _, part := range strings.Split(strings.TrimSpace(output), ",") {
+		var n int
+		var what string
+		if _, err := fmt.Sscanf(strings.TrimSpace(part), "%d %s", &n, &what); err != nil {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(what, "file"):
+			stat.Files = n
+		case strings.HasPrefix(what, "insertion"):
+			stat.Insertions = n
+		case strings.HasPrefix(what, "deletion"):
+			stat.Deletions = n
+		}
+	}
+	return stat
+}
+
+// Branch returns the checked out branch, or "(detached)" without one
+func (d *Detector) Branch(ctx context.Context) (string, error) {
+	return d.getCurrentBranch(ctx)
+}
+
+// Head returns the full hash of the checked out commit
+func (d *Detector) Head(ctx context.Context) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
+	cmd.Dir = d.repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// RemoteURL returns the fetch URL of the named remote, e.g. "origin"
+func (d *Detector) RemoteURL(ctx context.Context, name string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "remote", "get-url", name)
+	cmd.Dir = d.repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
 // GetStatus returns the cached status or detects fresh status
 func (d *Detector) GetStatus(ctx context.Context) (*Status, error) {
 	d.mu.RLock()
@@ -294,42 +529,72 @@ func (s *Status) GetBranchShort() string {
 
 // FormatStatus returns a formatted status string
 func (s *Status) FormatStatus() string {
+	return styled.Render(styled.ANSI{}, s.FormatStatusStyled())
+}
+
+// FormatStatusStyled returns the status as styled text, e.g.
+// "🌿 main ± 3 +312 −88" with the changed lines in green and red
+func (s *Status) FormatStatusStyled() styled.Text {
 	if s.Branch == "" {
-		return ""
+		return nil
 	}
 
-	var parts []string
-
 	// Branch name
-	parts = append(parts, "🌿", s.GetBranchShort())
+	text := styled.Plain("🌿 " + s.GetBranchShort())
+	add := func(part string, style styled.Style) {
+		text = text.Add(" ", styled.None).Add(part, style)
+	}
 
 	// Worktree indicator
 	if s.IsWorktree && s.WorktreeName != "" {
-		parts = append(parts, fmt.Sprintf("[%s]", s.WorktreeName))
+		add(fmt.Sprintf("[%s]", s.WorktreeName), styled.None)
 	}
 
 	// Dirty indicator (plus-minus symbol, universally understood as "changed")
 	if s.Dirty {
-		parts = append(parts, "±")
+		add("±", styled.None)
+	}
+
+	// Conflicts need resolving before anything else, so they stand apart
+	if s.Conflicts > 0 {
+		add("‼ "+i18n.N("git.conflicts", s.Conflicts), styled.Critical)
 	}
 
 	// Changes count (compact format)
-	totalChanges := s.Modified + s.Added + s.Deleted + s.Untracked
+	totalChanges := s.ChangedFiles()
 	if totalChanges > 0 {
-		parts = append(parts, fmt.Sprintf("%d", totalChanges))
+		add(fmt.Sprintf("%d", totalChanges), styled.None)
+	}
+
+	// Changed lines, which tell a typo fix from a rewrite
+	if s.Insertions > 0 || s.Deletions > 0 {
+		add(fmt.Sprintf("+%d", s.Insertions), styled.Good)
+		add(fmt.Sprintf("−%d", s.Deletions), styled.Critical)
 	}
 
 	// Ahead/Behind (using more visible directional arrows)
 	if s.Ahead > 0 || s.Behind > 0 {
 		if s.Ahead > 0 && s.Behind > 0 {
 			// Diverged branches: use up-down arrow to clearly indicate divergence
-			parts = append(parts, fmt.Sprintf("⇅ %d|%d", s.Ahead, s.Behind))
+			add(fmt.Sprintf("⇅ %d|%d", s.Ahead, s.Behind), styled.None)
 		} else if s.Ahead > 0 {
-			parts = append(parts, fmt.Sprintf("⬆ %d", s.Ahead))
+			add(fmt.Sprintf("⬆ %d", s.Ahead), styled.None)
 		} else if s.Behind > 0 {
-			parts = append(parts, fmt.Sprintf("⬇ %d", s.Behind))
+			add(fmt.Sprintf("⬇ %d", s.Behind), styled.None)
+		}
+	}
+
+	// Distance from the base branch, e.g. how big a pull request would be
+	if s.Base != "" && (s.BaseAhead > 0 || s.BaseBehind > 0) {
+		var base string
+		if s.BaseAhead > 0 {
+			base = fmt.Sprintf("↑%d", s.BaseAhead)
+		}
+		if s.BaseBehind > 0 {
+			base += fmt.Sprintf("↓%d", s.BaseBehind)
 		}
+		add(base+" vs "+s.Base, styled.None)
 	}
 
-	return strings.Join(parts, " ")
+	return text
 }
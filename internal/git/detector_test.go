@@ -6,6 +6,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
 )
 
 func TestDetector_NewDetector(t *testing.T) {
@@ -89,6 +92,33 @@ func TestDetector_Detect_TestRepo(t *testing.T) {
 	if status.Dirty {
 		t.Error("Expected Dirty=false for clean repo")
 	}
+
+	if status.LastCommit.Subject != "test" || status.LastCommit.Author != "Test User" || status.LastCommit.Time.IsZero() {
+		t.Errorf("LastCommit = %+v, want the test commit", status.LastCommit)
+	}
+
+	// A fresh detector, since the diff stat is reused for a few seconds
+	if err := os.WriteFile(testFile, []byte("changed\nand more\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if status, err := NewDetector(tmpDir).Detect(ctx); err != nil || status.Insertions != 2 || status.Deletions != 1 {
+		t.Errorf("Detect() after an edit = %+v, %v; want +2 −1", status, err)
+	}
+
+	if head, err := d.Head(ctx); err != nil || len(head) != 40 {
+		t.Errorf("Head() = %q, %v; want a full commit hash", head, err)
+	}
+	if _, err := d.RemoteURL(ctx, "origin"); err == nil {
+		t.Error("RemoteURL() should fail without the remote")
+	}
+	cmd = exec.Command("git", "remote", "add", "origin", "git@github.com:o/r.git")
+	cmd.Dir = tmpDir
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if remote, err := d.RemoteURL(ctx, "origin"); err != nil || remote != "git@github.com:o/r.git" {
+		t.Errorf("RemoteURL() = %q, %v", remote, err)
+	}
 }
 
 func TestStatus_FormatStatus(t *testing.T) {
@@ -127,6 +157,47 @@ func TestStatus_FormatStatus(t *testing.T) {
 			},
 			want: "🌿 main ⇅ 2|1", // Already has space
 		},
+		{
+			name: "ahead of base without upstream",
+			status: &Status{
+				Branch:    "feature/login",
+				Base:      "main",
+				BaseAhead: 12,
+			},
+			want: "🌿 login ↑12 vs main",
+		},
+		{
+			name: "diverged from base",
+			status: &Status{
+				Branch:     "fix-typo",
+				Ahead:      1,
+				Base:       "main",
+				BaseAhead:  1,
+				BaseBehind: 3,
+			},
+			want: "🌿 fix-typo ⬆ 1 ↑1↓3 vs main",
+		},
+		{
+			name: "conflicts",
+			status: &Status{
+				Branch:    "main",
+				Dirty:     true,
+				Conflicts: 3,
+				Modified:  1,
+			},
+			want: "🌿 main ± " + theme.Red + "‼ 3 conflicts" + theme.Reset + " 1",
+		},
+		{
+			name: "changed lines",
+			status: &Status{
+				Branch:     "main",
+				Dirty:      true,
+				Modified:   3,
+				Insertions: 312,
+				Deletions:  88,
+			},
+			want: "🌿 main ± 3 " + theme.Green + "+312" + theme.Reset + " " + theme.Red + "−88" + theme.Reset,
+		},
 		{
 			name: "worktree",
 			status: &Status{
@@ -157,3 +228,188 @@ func TestStatus_FormatStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestPickBaseRef(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		wantRef  string
+		wantName string
+	}{
+		{
+			name:     "origin HEAD",
+			output:   "refs/heads/master \nrefs/remotes/origin/HEAD refs/remotes/origin/trunk\nrefs/remotes/origin/main \n",
+			wantRef:  "refs/remotes/origin/trunk",
+			wantName: "trunk",
+		},
+		{
+			name:     "local main before master",
+			output:   "refs/heads/main \nrefs/heads/master \n",
+			wantRef:  "refs/heads/main",
+			wantName: "main",
+		},
+		{
+			name:     "remote master only",
+			output:   "refs/remotes/origin/master \n",
+			wantRef:  "refs/remotes/origin/master",
+			wantName: "master",
+		},
+		{
+			name: "none",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, name := pickBaseRef(tt.output)
+			if ref != tt.wantRef || name != tt.wantName {
+				t.Errorf("pickBaseRef() = %q, %q; want %q, %q", ref, name, tt.wantRef, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestDetector_Detect_BaseBranch(t *testing.T) {
+	tmpDir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.email=test@test.com", "-c", "user.name=Test User"}, args...)...)
+		cmd.Dir = tmpDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Skipf("Cannot run git %v: %v (%s)", args, err, output)
+		}
+	}
+	run("init", "--initial-branch=main")
+	run("commit", "--allow-empty", "-m", "base")
+	run("checkout", "-b", "feature")
+	run("commit", "--allow-empty", "-m", "one")
+	run("commit", "--allow-empty", "-m", "two")
+
+	d := NewDetector(tmpDir)
+	d.SetBaseBranch(AutoBaseBranch)
+	status, err := d.Detect(context.Background())
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if status.Base != "main" || status.BaseAhead != 2 || status.BaseBehind != 0 {
+		t.Errorf("Detect() base = %q ↑%d ↓%d, want main ↑2", status.Base, status.BaseAhead, status.BaseBehind)
+	}
+
+	// The base itself is not compared with itself
+	run("checkout", "main")
+	if status, err := d.Detect(context.Background()); err != nil || status.Base != "" {
+		t.Errorf("Detect() on the base = %+v, %v", status, err)
+	}
+
+	d.SetBaseBranch("")
+	run("checkout", "feature")
+	if status, err := d.Detect(context.Background()); err != nil || status.Base != "" {
+		t.Errorf("Detect() with the comparison off = %+v, %v", status, err)
+	}
+}
+
+func TestDetector_Detect_Conflicts(t *testing.T) {
+	tmpDir := t.TempDir()
+	run := func(args ...string) error {
+		cmd := exec.Command("git", append([]string{"-c", "user.email=test@test.com", "-c", "user.name=Test User"}, args...)...)
+		cmd.Dir = tmpDir
+		return cmd.Run()
+	}
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := run("init", "--initial-branch=main"); err != nil {
+		t.Skipf("Cannot run git: %v", err)
+	}
+	write("a.txt", "base\n")
+	write("b.txt", "base\n")
+	write("c.txt", "base\n")
+	for _, args := range [][]string{{"add", "."}, {"commit", "-m", "base"}, {"checkout", "-b", "other"}} {
+		if err := run(args...); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+	write("a.txt", "other\n")
+	write("b.txt", "other\n")
+	for _, args := range [][]string{{"commit", "-am", "other"}, {"checkout", "main"}} {
+		if err := run(args...); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+	write("a.txt", "main\n")
+	write("b.txt", "main\n")
+	if err := run("commit", "-am", "main"); err != nil {
+		t.Fatal(err)
+	}
+	if err := run("merge", "other"); err == nil {
+		t.Fatal("expected the merge to conflict")
+	}
+	write("c.txt", "edited\n")
+
+	status, err := NewDetector(tmpDir).Detect(context.Background())
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if status.Conflicts != 2 || status.Modified != 1 || !status.Dirty {
+		t.Errorf("Detect() = %d conflicts, %d modified; want 2 and 1", status.Conflicts, status.Modified)
+	}
+}
+
+func TestParseLastCommit(t *testing.T) {
+	commit, err := parseLastCommit("fix parser\x00Ada Lovelace\x001700000000\n")
+	want := Commit{Subject: "fix parser", Author: "Ada Lovelace", Time: time.Unix(1700000000, 0)}
+	if err != nil || commit != want {
+		t.Errorf("parseLastCommit() = %+v, %v; want %+v", commit, err, want)
+	}
+	if _, err := parseLastCommit(""); err == nil {
+		t.Error("expected an error for a repository without commits")
+	}
+}
+
+func TestParseShortstat(t *testing.T) {
+	tests := []struct {
+		output string
+		want   DiffStat
+	}{
+		{" 3 files changed, 312 insertions(+), 88 deletions(-)\n", DiffStat{Files: 3, Insertions: 312, Deletions: 88}},
+		{" 1 file changed, 1 insertion(+)\n", DiffStat{Files: 1, Insertions: 1}},
+		{" 2 files changed, 5 deletions(-)\n", DiffStat{Files: 2, Deletions: 5}},
+		{"", DiffStat{}},
+	}
+	for _, tt := range tests {
+		if got := parseShortstat(tt.output); got != tt.want {
+			t.Errorf("parseShortstat(%q) = %+v, want %+v", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestCheckpointTracker(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "checkpoints.json")
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tracker := NewCheckpointTracker(cachePath)
+	if overFor := tracker.Observe("/repo", true, start); overFor != 0 {
+		t.Errorf("Observe() on first crossing = %v, want 0", overFor)
+	}
+
+	// Another process continues the clock
+	tracker = NewCheckpointTracker(cachePath)
+	if overFor := tracker.Observe("/repo", true, start.Add(20*time.Minute)); overFor != 20*time.Minute {
+		t.Errorf("Observe() = %v, want 20m", overFor)
+	}
+	if !tracker.Notify("/repo") || tracker.Notify("/repo") {
+		t.Error("Notify() should be due exactly once per stretch")
+	}
+	if tracker.Notify("/other") {
+		t.Error("Notify() should not be due for a repository under the thresholds")
+	}
+
+	// Committing resets the clock and the reminder
+	tracker.Observe("/repo", false, start.Add(25*time.Minute))
+	tracker.Observe("/repo", true, start.Add(30*time.Minute))
+	if overFor := tracker.Observe("/repo", true, start.Add(31*time.Minute)); overFor != time.Minute || !tracker.Notify("/repo") {
+		t.Errorf("Observe() after a commit = %v, want 1m and a new reminder", overFor)
+	}
+}
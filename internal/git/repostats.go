@@ -0,0 +1,188 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/filecache"
+)
+
+// RepoStatsFileName is the repository statistics cache inside the state directory
+const RepoStatsFileName = "repostats.json"
+
+// repoStatsTimeout bounds counting a very large tree in the background
+const repoStatsTimeout = 30 * time.Second
+
+// RepoStats is the size of the files tracked at a commit; ignored and
+// untracked files are left out by construction
+type RepoStats struct {
+	Head       string    `json:"head"` // Commit the stats were counted at
+	Files      int       `json:"files"`
+	Bytes      int64     `json:"bytes"`
+	ComputedAt time.Time `json:"computed_at"`
+}
+
+// RepoStatsCache counts tracked files and their size per repository
+// Counting runs in the background and its result is kept until HEAD moves;
+// with a cache path it is shared with other processes, e.g. the daemon's
+// metrics endpoint
+type RepoStatsCache struct {
+	CachePath string
+
+	mu      sync.Mutex
+	stats   map[string]RepoStats     // Keyed by repository path
+	running map[string]chan struct{} // Closed when the count for a repository finishes
+}
+
+// NewRepoStatsCache creates a cache persisted at cachePath ("" keeps it in memory)
+func NewRepoStatsCache(cachePath string) *RepoStatsCache {
+	return &RepoStatsCache{
+		CachePath: cachePath,
+		stats:     make(map[string]RepoStats),
+		running:   make(map[string]chan struct{}),
+	}
+}
+
+// Stats returns repoPath's stats at HEAD, counting them in the background
+// when HEAD moved since the last count. It waits for the count until ctx is
+// done, then returns the stats of the previous HEAD, if any, so a large
+// tree never holds up rendering
+func (c *RepoStatsCache) Stats(ctx context.Context, repoPath string) (RepoStats, bool, error) {
+	head, err := revParseHead(ctx, repoPath)
+	if err != nil {
+		return RepoStats{}, false, err
+	}
+
+	c.mu.Lock()
+	last, ok := c.stats[repoPath]
+	if !ok || last.Head != head {
+		// Another process may have counted it already
+		c.load()
+		last, ok = c.stats[repoPath]
+	}
+	if ok && last.Head == head {
+		c.mu.Unlock()
+		return last, true, nil
+	}
+	done, running := c.running[repoPath]
+	if !running {
+		done = make(chan struct{})
+		c.running[repoPath] = done
+		go c.count(repoPath, head, done)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-done:
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		stats, ok := c.stats[repoPath]
+		if !ok || stats.Head != head {
+			return last, ok, fmt.Errorf("counting tracked files failed")
+		}
+		return stats, true, nil
+	case <-ctx.Done():
+		return last, ok, nil
+	}
+}
+
+// count lists the tree at head and stores the totals
+func (c *RepoStatsCache) count(repoPath, head string, done chan struct{}) {
+	defer close(done)
+
+	ctx, cancel := context.WithTimeout(context.Background(), repoStatsTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", "ls-tree", "-r", "-l", "-z", head)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.running, repoPath)
+	if err != nil {
+		return
+	}
+	stats := parseLsTree(output)
+	stats.Head, stats.ComputedAt = head, time.Now()
+	c.stats[repoPath] = stats
+	c.save()
+}
+
+// parseLsTree sums the blobs of `git ls-tree -r -l -z` output, whose entries
+// read "<mode> blob <object> <size>\t<path>"; submodules have no size
+func parseLsTree(output []byte) RepoStats {
+	var stats RepoStats
+	for _, entry := range bytes.Split(output, []byte{0}) {
+		meta, _, ok := bytes.Cut(entry, []byte{'\t'})
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(string(meta))
+		if len(fields) != 4 || fields[1] != "blob" {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			continue
+		}
+		stats.Files++
+		stats.Bytes += size
+	}
+	return stats
+}
+
+// revParseHead returns the commit checked out in repoPath
+func revParseHead(ctx context.Context, repoPath string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("no commit checked out: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// LoadRepoStats reads the stats every process has counted so far, keyed by repository
+func LoadRepoStats(cachePath string) (map[string]RepoStats, error) {
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		return nil, err
+	}
+	var stats map[string]RepoStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, fmt.Errorf("invalid repository stats: %w", err)
+	}
+	return stats, nil
+}
+
+// load merges persisted stats in, keeping the newer count of each
+// repository. Must be called with c.mu held
+func (c *RepoStatsCache) load() {
+	var stats map[string]RepoStats
+	if !filecache.Load(c.CachePath, &stats) {
+		return
+	}
+	for repo, s := range stats {
+		if mine, ok := c.stats[repo]; !ok || s.ComputedAt.After(mine.ComputedAt) {
+			c.stats[repo] = s
+		}
+	}
+}
+
+// save writes the stats, merging what other processes counted meanwhile
+// Must be called with c.mu held
+func (c *RepoStatsCache) save() {
+	if c.CachePath == "" {
+		return
+	}
+	c.load()
+	_ = filecache.Save(c.CachePath, c.stats)
+}
@@ -0,0 +1,77 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseLsTree(t *testing.T) {
+	output := "100644 blob 8ab686eafeb1f44702738c8b0f24f2567c36da6d      12\tREADME.md\x00" +
+		"100755 blob 3b18e512dba79e4c8300dd08aeb37f8e728b8dad    4096\tscripts/build.sh\x00" +
+		"160000 commit 9c2b1e3f4a5d6e7f8091a2b3c4d5e6f708192a3b       -\tvendor/lib\x00"
+
+	stats := parseLsTree([]byte(output))
+	if stats.Files != 2 || stats.Bytes != 4108 {
+		t.Errorf("parseLsTree() = %d files, %d bytes; want 2 and 4108", stats.Files, stats.Bytes)
+	}
+}
+
+func TestRepoStatsCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.email=test@test.com", "-c", "user.name=Test User"}, args...)...)
+		cmd.Dir = tmpDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Skipf("Cannot run git %v: %v (%s)", args, err, output)
+		}
+	}
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("init", "--initial-branch=main")
+	write(".gitignore", "build/\n")
+	write("main.go", "package main\n")
+	if err := os.Mkdir(filepath.Join(tmpDir, "build"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	write("build/out.bin", "ignored output")
+	run("add", ".")
+	run("commit", "-m", "initial")
+
+	cachePath := filepath.Join(t.TempDir(), RepoStatsFileName)
+	cache := NewRepoStatsCache(cachePath)
+	stats, ok, err := cache.Stats(context.Background(), tmpDir)
+	if err != nil || !ok {
+		t.Fatalf("Stats() = %+v, %v, %v", stats, ok, err)
+	}
+	if stats.Files != 2 || stats.Bytes != int64(len("build/\n")+len("package main\n")) {
+		t.Errorf("Stats() = %d files, %d bytes; want the two tracked files", stats.Files, stats.Bytes)
+	}
+
+	// Another process reads the count instead of repeating it
+	other := NewRepoStatsCache(cachePath)
+	if shared, ok, _ := other.Stats(context.Background(), tmpDir); !ok || !shared.ComputedAt.Equal(stats.ComputedAt) {
+		t.Errorf("Stats() from the shared file = %+v, %v", shared, ok)
+	}
+
+	// A new commit is counted again
+	write("util.go", "package main\n\nfunc util() {}\n")
+	run("add", ".")
+	run("commit", "-m", "add util")
+	updated, ok, err := cache.Stats(context.Background(), tmpDir)
+	if err != nil || !ok || updated.Files != 3 || updated.Head == stats.Head {
+		t.Errorf("Stats() after a commit = %+v, %v, %v", updated, ok, err)
+	}
+
+	all, err := LoadRepoStats(cachePath)
+	if err != nil || all[tmpDir].Files != 3 || time.Since(all[tmpDir].ComputedAt) > time.Minute {
+		t.Errorf("LoadRepoStats() = %+v, %v", all, err)
+	}
+}
@@ -0,0 +1,92 @@
+package git
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Worktree is one working tree of a repository, as listed by git worktree
+// list
+type Worktree struct {
+	Path     string
+	Branch   string // "(detached)" for a detached HEAD, "" for a bare repository
+	Head     string // Full hash of the checked out commit
+	Bare     bool
+	Locked   bool
+	Prunable bool // Its directory is gone; git worktree prune would remove it
+}
+
+// Name returns the worktree's branch, or its directory name when HEAD is
+// detached
+func (w Worktree) Name() string {
+	if w.Branch == "" || w.Branch == "(detached)" {
+		return filepath.Base(w.Path)
+	}
+	return w.Branch
+}
+
+// ListWorktrees returns the worktrees of the repository at repoPath, main
+// worktree first
+func ListWorktrees(ctx context.Context, repoPath string) ([]Worktree, error) {
+	cmd := exec.CommandContext(ctx, "git", "worktree", "list", "--porcelain")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseWorktreeList(string(output)), nil
+}
+
+// parseWorktreeList parses the output of git worktree list --porcelain:
+// blank-line separated records of "key value" lines
+func parseWorktreeList(output string) []Worktree {
+	var worktrees []Worktree
+	var current *Worktree
+	for _, line := range strings.Split(output, "\n") {
+		key, value, _ := strings.Cut(strings.TrimRight(line, "\r"), " ")
+		if key == "worktree" {
+			worktrees = append(worktrees, Worktree{Path: value})
+			current = &worktrees[len(worktrees)-1]
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		switch key {
+		case "":
+			current = nil
+		case "HEAD":
+			current.Head = value
+		case "branch":
+			current.Branch = strings.TrimPrefix(value, "refs/heads/")
+		case "detached":
+			current.Branch = "(detached)"
+		case "bare":
+			current.Bare = true
+		case "locked":
+			current.Locked = true
+		case "prunable":
+			current.Prunable = true
+		}
+	}
+	return worktrees
+}
+
+// Changes reads only the counts of changed files, which is all an overview
+// of many worktrees needs and far cheaper than Detect
+func (d *Detector) Changes(ctx context.Context) (*Status, error) {
+	status := &Status{}
+	if err := d.getStatusCounts(ctx, status); err != nil {
+		return nil, err
+	}
+	status.Dirty = status.ChangedFiles() > 0 || status.Conflicts > 0
+	return status, nil
+}
+
+// ChangedFiles returns the number of modified, added, deleted and untracked
+// files
+func (s *Status) ChangedFiles() int {
+	return s.Modified + s.Added + s.Deleted + s.Untracked
+}
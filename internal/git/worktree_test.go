@@ -0,0 +1,80 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseWorktreeList(t *testing.T) {
+	output := "worktree /src/app\nHEAD aaa\nbranch refs/heads/main\n\n" +
+		"worktree /src/app-auth\nHEAD bbb\nbranch refs/heads/feature/auth\nlocked agent running\n\n" +
+		"worktree /src/app-spike\nHEAD ccc\ndetached\nprunable gitdir file points to non-existent location\n\n"
+	want := []Worktree{
+		{Path: "/src/app", Branch: "main", Head: "aaa"},
+		{Path: "/src/app-auth", Branch: "feature/auth", Head: "bbb", Locked: true},
+		{Path: "/src/app-spike", Branch: "(detached)", Head: "ccc", Prunable: true},
+	}
+	got := parseWorktreeList(output)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseWorktreeList() = %+v, want %+v", got, want)
+	}
+	if name := got[2].Name(); name != "app-spike" {
+		t.Errorf("Name() of a detached worktree = %q, want the directory name", name)
+	}
+
+	bare := parseWorktreeList("worktree /src/app.git\nbare\n")
+	if len(bare) != 1 || !bare[0].Bare || bare[0].Branch != "" {
+		t.Errorf("parseWorktreeList(bare) = %+v", bare)
+	}
+	if got := parseWorktreeList(""); len(got) != 0 {
+		t.Errorf("parseWorktreeList(\"\") = %+v, want none", got)
+	}
+}
+
+func TestListWorktrees(t *testing.T) {
+	dir := t.TempDir()
+	repo := filepath.Join(dir, "app")
+	run := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@test.com",
+			"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@test.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Skipf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	if err := os.Mkdir(repo, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	run(repo, "init", "-q", "-b", "main")
+	run(repo, "commit", "-q", "--allow-empty", "-m", "init")
+	run(repo, "worktree", "add", "-q", "-b", "auth", filepath.Join(dir, "app-auth"))
+	if err := os.WriteFile(filepath.Join(dir, "app-auth", "new.go"), []byte("package app"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	worktrees, err := ListWorktrees(ctx, repo)
+	if err != nil {
+		t.Fatalf("ListWorktrees() error = %v", err)
+	}
+	if len(worktrees) != 2 || worktrees[0].Branch != "main" || worktrees[1].Branch != "auth" {
+		t.Fatalf("ListWorktrees() = %+v, want main and auth", worktrees)
+	}
+
+	status, err := NewDetector(worktrees[1].Path).Changes(ctx)
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	if !status.Dirty || status.Untracked != 1 {
+		t.Errorf("Changes() = %+v, want one untracked file", status)
+	}
+	if status, _ := NewDetector(repo).Changes(ctx); status == nil || status.Dirty {
+		t.Errorf("Changes() of the clean main worktree = %+v", status)
+	}
+}
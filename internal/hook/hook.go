@@ -0,0 +1,161 @@
+// Package hook parses Claude Code hook payloads and folds them into the
+// shared session state
+package hook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+// Hook event names sent by Claude Code in hook_event_name
+const (
+	EventPreToolUse       = "PreToolUse"
+	EventPostToolUse      = "PostToolUse"
+	EventStop             = "Stop"
+	EventSubagentStop     = "SubagentStop"
+	EventUserPromptSubmit = "UserPromptSubmit"
+	EventNotification     = "Notification"
+	EventSessionStart     = "SessionStart"
+	EventSessionEnd       = "SessionEnd"
+)
+
+// maxPayloadSize bounds how much of stdin is read for a single hook payload
+const maxPayloadSize = 4 * 1024 * 1024
+
+// Event is the JSON payload Claude Code writes to a hook's stdin
+type Event struct {
+	SessionID      string                 `json:"session_id"`
+	TranscriptPath string                 `json:"transcript_path"`
+	Cwd            string                 `json:"cwd"`
+	HookEventName  string                 `json:"hook_event_name"`
+	ToolName       string                 `json:"tool_name,omitempty"`
+	ToolInput      map[string]interface{} `json:"tool_input,omitempty"`
+	ToolUseID      string                 `json:"tool_use_id,omitempty"`
+	ToolResponse   json.RawMessage        `json:"tool_response,omitempty"`
+	Message        string                 `json:"message,omitempty"`
+	NotifyType     string                 `json:"notification_type,omitempty"`
+	Prompt         string                 `json:"prompt,omitempty"`
+	StopHookActive bool                   `json:"stop_hook_active,omitempty"`
+}
+
+// Parse decodes a hook payload from r
+func Parse(r io.Reader) (*Event, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxPayloadSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hook payload: %w", err)
+	}
+	return ParseBytes(data)
+}
+
+// ParseBytes decodes a hook payload from raw JSON
+func ParseBytes(data []byte) (*Event, error) {
+	var ev Event
+	if err := json.Unmarshal(data, &ev); err != nil {
+		return nil, fmt.Errorf("failed to parse hook payload: %w", err)
+	}
+	if ev.HookEventName == "" {
+		return nil, fmt.Errorf("hook payload missing hook_event_name")
+	}
+	if ev.SessionID == "" {
+		return nil, fmt.Errorf("hook payload missing session_id")
+	}
+	return &ev, nil
+}
+
+// Target returns the display target of the tool call, if any
+func (e *Event) Target() string {
+	if e.ToolInput == nil {
+		return ""
+	}
+	return transcript.ToolTarget(e.ToolName, e.ToolInput)
+}
+
+// IsError reports whether a PostToolUse response indicates a failed tool call
+func (e *Event) IsError() bool {
+	if len(e.ToolResponse) == 0 {
+		return false
+	}
+	var resp struct {
+		IsError bool   `json:"is_error"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal(e.ToolResponse, &resp); err != nil {
+		return false
+	}
+	return resp.IsError || resp.Error != ""
+}
+
+// IsPermissionRequest reports whether a Notification announces a permission
+// prompt rather than, say, Claude waiting idle for input. Older Claude Code
+// versions only say so in the message
+func (e *Event) IsPermissionRequest() bool {
+	if e.NotifyType != "" {
+		return e.NotifyType == "permission_prompt"
+	}
+	return strings.Contains(strings.ToLower(e.Message), "permission")
+}
+
+// Apply folds the event into the shared state at time now
+func (e *Event) Apply(state *session.State, now time.Time) {
+	sess := state.Session(e.SessionID)
+	if e.TranscriptPath != "" {
+		sess.TranscriptPath = e.TranscriptPath
+	}
+	if e.Cwd != "" {
+		sess.Cwd = e.Cwd
+	}
+
+	record := session.HookEvent{
+		Name:      e.HookEventName,
+		ToolName:  e.ToolName,
+		ToolUseID: e.ToolUseID,
+		Target:    e.Target(),
+		Timestamp: now,
+	}
+
+	// Any other event means the prompt was answered, or the turn moved on
+	if e.HookEventName != EventNotification {
+		sess.Approval = nil
+	}
+
+	switch e.HookEventName {
+	case EventNotification:
+		if e.IsPermissionRequest() {
+			sess.Approval = &session.ApprovalRequest{Message: e.Message, At: now}
+		}
+	case EventPreToolUse:
+		sess.Stopped = false
+		sess.ActiveTools[e.toolKey()] = session.ActiveTool{
+			Name:      e.ToolName,
+			Target:    record.Target,
+			StartedAt: now,
+		}
+	case EventPostToolUse:
+		record.IsError = e.IsError()
+		delete(sess.ActiveTools, e.toolKey())
+		sess.ToolCalls++
+	case EventUserPromptSubmit, EventSessionStart:
+		sess.Stopped = false
+	case EventStop, EventSessionEnd:
+		sess.Stopped = true
+		// Nothing can still be running once the turn has ended
+		sess.ActiveTools = make(map[string]session.ActiveTool)
+	}
+
+	sess.AddEvent(record)
+}
+
+// toolKey identifies a tool call so PostToolUse can match its PreToolUse
+// Older Claude Code versions omit tool_use_id, so fall back to the tool name
+func (e *Event) toolKey() string {
+	if e.ToolUseID != "" {
+		return e.ToolUseID
+	}
+	return e.ToolName
+}
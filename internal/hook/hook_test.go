@@ -0,0 +1,160 @@
+package hook
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{
+			name:  "pre tool use",
+			input: `{"session_id":"s1","transcript_path":"/t.jsonl","cwd":"/p","hook_event_name":"PreToolUse","tool_name":"Read","tool_input":{"file_path":"/p/main.go"}}`,
+		},
+		{
+			name:  "stop",
+			input: `{"session_id":"s1","hook_event_name":"Stop","stop_hook_active":false}`,
+		},
+		{
+			name:    "invalid json",
+			input:   `{not json`,
+			wantErr: true,
+		},
+		{
+			name:    "missing event name",
+			input:   `{"session_id":"s1"}`,
+			wantErr: true,
+		},
+		{
+			name:    "missing session id",
+			input:   `{"hook_event_name":"Stop"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvent_ApplyToolLifecycle(t *testing.T) {
+	state := session.NewState()
+	now := time.Now()
+
+	pre, err := ParseBytes([]byte(`{"session_id":"s1","transcript_path":"/t.jsonl","hook_event_name":"PreToolUse","tool_name":"Bash","tool_use_id":"tu1","tool_input":{"command":"go test ./..."}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pre.Apply(state, now)
+
+	sess := state.Sessions["s1"]
+	if sess == nil {
+		t.Fatal("session not created")
+	}
+	active, ok := sess.ActiveTools["tu1"]
+	if !ok {
+		t.Fatal("PreToolUse should register an active tool")
+	}
+	if active.Target != "go test ./..." {
+		t.Errorf("active target = %q", active.Target)
+	}
+	if sess.TranscriptPath != "/t.jsonl" {
+		t.Errorf("TranscriptPath = %q", sess.TranscriptPath)
+	}
+
+	post, err := ParseBytes([]byte(`{"session_id":"s1","hook_event_name":"PostToolUse","tool_name":"Bash","tool_use_id":"tu1","tool_response":{"error":"exit status 1"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	post.Apply(state, now.Add(time.Second))
+
+	if len(sess.ActiveTools) != 0 {
+		t.Errorf("PostToolUse should clear the active tool, got %v", sess.ActiveTools)
+	}
+	if sess.ToolCalls != 1 {
+		t.Errorf("ToolCalls = %d, want 1", sess.ToolCalls)
+	}
+	last := sess.RecentEvents[len(sess.RecentEvents)-1]
+	if !last.IsError {
+		t.Error("PostToolUse with error response should be recorded as error")
+	}
+}
+
+func TestEvent_ApplyStop(t *testing.T) {
+	state := session.NewState()
+	now := time.Now()
+
+	pre := &Event{SessionID: "s1", HookEventName: EventPreToolUse, ToolName: "Read"}
+	pre.Apply(state, now)
+
+	stop := &Event{SessionID: "s1", HookEventName: EventStop}
+	stop.Apply(state, now.Add(time.Second))
+
+	sess := state.Sessions["s1"]
+	if !sess.Stopped {
+		t.Error("Stop should mark session stopped")
+	}
+	if len(sess.ActiveTools) != 0 {
+		t.Error("Stop should clear active tools")
+	}
+	if sess.LastEvent != EventStop {
+		t.Errorf("LastEvent = %q, want Stop", sess.LastEvent)
+	}
+
+	prompt := &Event{SessionID: "s1", HookEventName: EventUserPromptSubmit}
+	prompt.Apply(state, now.Add(2*time.Second))
+	if sess.Stopped {
+		t.Error("UserPromptSubmit should resume session")
+	}
+}
+
+func TestEvent_ApplyPermissionNotification(t *testing.T) {
+	state := session.NewState()
+	now := time.Now()
+
+	idle := &Event{SessionID: "s1", HookEventName: EventNotification, Message: "Claude is waiting for your input"}
+	idle.Apply(state, now)
+	if state.Sessions["s1"].Approval != nil {
+		t.Error("an idle notification should not count as a permission prompt")
+	}
+
+	ask := &Event{SessionID: "s1", HookEventName: EventNotification, Message: "Claude needs your permission to use Bash"}
+	ask.Apply(state, now.Add(time.Second))
+	sess := state.Sessions["s1"]
+	if sess.Approval == nil || !sess.Approval.At.Equal(now.Add(time.Second)) {
+		t.Fatalf("Approval = %+v, want the permission prompt", sess.Approval)
+	}
+
+	// A later notification of another kind leaves the prompt in place
+	idle.Apply(state, now.Add(2*time.Second))
+	if sess.Approval == nil {
+		t.Error("an idle notification should not clear the permission prompt")
+	}
+
+	post := &Event{SessionID: "s1", HookEventName: EventPostToolUse, ToolName: "Bash"}
+	post.Apply(state, now.Add(3*time.Second))
+	if sess.Approval != nil {
+		t.Error("PostToolUse should clear the permission prompt")
+	}
+
+	typed := &Event{SessionID: "s1", HookEventName: EventNotification, NotifyType: "permission_prompt", Message: "Allow Bash?"}
+	if !typed.IsPermissionRequest() {
+		t.Error("IsPermissionRequest() = false for notification_type permission_prompt")
+	}
+	typed.NotifyType = "idle_prompt"
+	if typed.IsPermissionRequest() {
+		t.Error("IsPermissionRequest() = true for notification_type idle_prompt")
+	}
+}
@@ -0,0 +1,321 @@
+package hook
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Claude Code debug log lines describing hook execution, written when it is
+// started with --debug. Each line is "<RFC 3339 timestamp> [LEVEL] message"
+var (
+	debugLinePattern     = regexp.MustCompile(`^(\S+) \[\w+\] (.*)$`)
+	hooksStartPattern    = regexp.MustCompile(`^Executing hooks for (\w+)(?::(.*))?$`)
+	commandStartPattern  = regexp.MustCompile(`^Executing hook command: (.*?)(?: with timeout \d+ms)?$`)
+	commandFinishPattern = regexp.MustCompile(`^Hook command completed with status (-?\d+): (.*)$`)
+)
+
+// maxDebugLineSize bounds a single debug log line; longer lines are skipped
+const maxDebugLineSize = 1024 * 1024
+
+// DebugLogDir returns the directory Claude Code writes debug logs to (~/.claude/debug)
+func DebugLogDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".claude", "debug"), nil
+}
+
+// DebugLogPath returns the debug log of a session
+func DebugLogPath(dir, sessionID string) string {
+	return filepath.Join(dir, sessionID+".txt")
+}
+
+// SessionIDFromTranscript returns the session ID a transcript is named after
+func SessionIDFromTranscript(transcriptPath string) string {
+	name := filepath.Base(transcriptPath)
+	name = strings.TrimSuffix(name, ".gz")
+	return strings.TrimSuffix(name, ".jsonl")
+}
+
+// HookRun is one execution of a user hook command
+type HookRun struct {
+	Event    string // Hook event, e.g. PreToolUse
+	Tool     string // Tool the hook ran for; empty for non-tool events
+	Command  string
+	Start    time.Time
+	Duration time.Duration
+	ExitCode int
+	batch    int // Runs with the same batch were started together and ran in parallel
+}
+
+// debugLogParser turns debug log lines into hook runs
+type debugLogParser struct {
+	event   string
+	tool    string
+	batch   int
+	started map[string][]HookRun // Runs awaiting completion by command, oldest first
+	runs    []HookRun
+}
+
+func newDebugLogParser() *debugLogParser {
+	return &debugLogParser{started: make(map[string][]HookRun)}
+}
+
+// parseLine handles one debug log line; unrelated lines are ignored
+func (p *debugLogParser) parseLine(line string) {
+	m := debugLinePattern.FindStringSubmatch(line)
+	if m == nil {
+		return
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, m[1])
+	if err != nil {
+		return
+	}
+	message := m[2]
+
+	if m := hooksStartPattern.FindStringSubmatch(message); m != nil {
+		p.event, p.tool = m[1], m[2]
+		p.batch++
+		return
+	}
+	if m := commandStartPattern.FindStringSubmatch(message); m != nil {
+		p.started[m[1]] = append(p.started[m[1]], HookRun{
+			Event:   p.event,
+			Tool:    p.tool,
+			Command: m[1],
+			Start:   timestamp,
+			batch:   p.batch,
+		})
+		return
+	}
+	if m := commandFinishPattern.FindStringSubmatch(message); m != nil {
+		pending := p.started[m[2]]
+		if len(pending) == 0 {
+			return
+		}
+		run := pending[0]
+		p.started[m[2]] = pending[1:]
+		run.ExitCode, _ = strconv.Atoi(m[1])
+		run.Duration = timestamp.Sub(run.Start)
+		p.runs = append(p.runs, run)
+	}
+}
+
+// ParseDebugLog returns the hook runs recorded in a Claude Code debug log
+// Hooks that were still running when the log ends are not included
+func ParseDebugLog(r io.Reader) ([]HookRun, error) {
+	p := newDebugLogParser()
+	reader := bufio.NewReader(r)
+	for {
+		line, _, err := readDebugLine(reader)
+		if line != "" {
+			p.parseLine(line)
+		}
+		if err == io.EOF {
+			return p.runs, nil
+		}
+		if err != nil {
+			return p.runs, fmt.Errorf("failed to read debug log: %w", err)
+		}
+	}
+}
+
+// readDebugLine reads one line without its newline, truncating it to
+// maxDebugLineSize; hook lines are short, so truncated lines never match
+// n is the number of bytes consumed, including the newline
+func readDebugLine(r *bufio.Reader) (line string, n int, err error) {
+	var buf bytes.Buffer
+	for {
+		chunk, err := r.ReadSlice('\n')
+		n += len(chunk)
+		if buf.Len() < maxDebugLineSize {
+			buf.Write(chunk)
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		return strings.TrimRight(buf.String(), "\r\n"), n, err
+	}
+}
+
+// HookStats aggregates the runs of one hook command
+type HookStats struct {
+	Event   string
+	Command string
+	Runs    int
+	Total   time.Duration
+	Max     time.Duration
+	Slow    int // Runs that took at least the slow threshold
+	Failed  int // Runs with a non-zero exit status
+}
+
+// Average returns the mean duration of the hook's runs
+func (s HookStats) Average() time.Duration {
+	if s.Runs == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Runs)
+}
+
+// LatencySummary reports how much time hooks add to tool calls
+type LatencySummary struct {
+	Hooks       []HookStats   // Slowest total first
+	ToolCalls   int           // Tool calls that ran hooks, counted from PreToolUse or PostToolUse batches
+	ToolLatency time.Duration // Time tool calls waited on PreToolUse and PostToolUse hooks
+}
+
+// PerToolCall returns the average time hooks added to each tool call
+func (s LatencySummary) PerToolCall() time.Duration {
+	if s.ToolCalls == 0 {
+		return 0
+	}
+	return s.ToolLatency / time.Duration(s.ToolCalls)
+}
+
+// Slowest returns the PreToolUse or PostToolUse hook with the highest
+// average duration, if any; hooks of other events don't delay tool calls
+func (s LatencySummary) Slowest() (HookStats, bool) {
+	var slowest HookStats
+	for _, stats := range s.Hooks {
+		if stats.Event != EventPreToolUse && stats.Event != EventPostToolUse {
+			continue
+		}
+		if stats.Average() > slowest.Average() {
+			slowest = stats
+		}
+	}
+	return slowest, slowest.Runs > 0
+}
+
+// Summarize aggregates hook runs; runs taking at least slow count as slow
+//
+// Claude Code runs the hooks matching an event in parallel, so a tool call
+// waits for the slowest hook of each batch, not for their sum
+func Summarize(runs []HookRun, slow time.Duration) LatencySummary {
+	var summary LatencySummary
+	byCommand := make(map[string]*HookStats)
+	batchLatency := make(map[int]time.Duration)
+	preBatches := make(map[int]bool)
+	postBatches := make(map[int]bool)
+
+	for _, run := range runs {
+		key := run.Event + "\x00" + run.Command
+		stats, ok := byCommand[key]
+		if !ok {
+			stats = &HookStats{Event: run.Event, Command: run.Command}
+			byCommand[key] = stats
+		}
+		stats.Runs++
+		stats.Total += run.Duration
+		if run.Duration > stats.Max {
+			stats.Max = run.Duration
+		}
+		if run.Duration >= slow {
+			stats.Slow++
+		}
+		if run.ExitCode != 0 {
+			stats.Failed++
+		}
+
+		switch run.Event {
+		case EventPreToolUse:
+			preBatches[run.batch] = true
+		case EventPostToolUse:
+			postBatches[run.batch] = true
+		default:
+			continue
+		}
+		if run.Duration > batchLatency[run.batch] {
+			batchLatency[run.batch] = run.Duration
+		}
+	}
+
+	for _, latency := range batchLatency {
+		summary.ToolLatency += latency
+	}
+	// Every tool call runs both events, so either count works when only one has hooks
+	summary.ToolCalls = max(len(preBatches), len(postBatches))
+	for _, stats := range byCommand {
+		summary.Hooks = append(summary.Hooks, *stats)
+	}
+	sort.Slice(summary.Hooks, func(i, j int) bool {
+		if summary.Hooks[i].Total != summary.Hooks[j].Total {
+			return summary.Hooks[i].Total > summary.Hooks[j].Total
+		}
+		return summary.Hooks[i].Event+summary.Hooks[i].Command < summary.Hooks[j].Event+summary.Hooks[j].Command
+	})
+	return summary
+}
+
+// LatencyTracker follows a growing debug log, reading only what was
+// appended since the last call
+type LatencyTracker struct {
+	path string
+
+	mu     sync.Mutex
+	offset int64
+	parser *debugLogParser
+}
+
+// NewLatencyTracker creates a tracker for the debug log at path
+func NewLatencyTracker(path string) *LatencyTracker {
+	return &LatencyTracker{path: path, parser: newDebugLogParser()}
+}
+
+// Path returns the debug log being tracked
+func (t *LatencyTracker) Path() string {
+	return t.path
+}
+
+// Runs reads new lines from the debug log and returns every hook run so far
+// A log that shrank, such as after being replaced, is read again from the start
+func (t *LatencyTracker) Runs() ([]HookRun, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	file, err := os.Open(t.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() < t.offset {
+		t.offset = 0
+		t.parser = newDebugLogParser()
+	}
+	if _, err := file.Seek(t.offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(file)
+	for {
+		line, n, err := readDebugLine(reader)
+		if err == io.EOF {
+			break // A partial last line is read again once it is complete
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read debug log: %w", err)
+		}
+		t.offset += int64(n)
+		t.parser.parseLine(line)
+	}
+
+	runs := make([]HookRun, len(t.parser.runs))
+	copy(runs, t.parser.runs)
+	return runs, nil
+}
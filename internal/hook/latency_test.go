@@ -0,0 +1,131 @@
+package hook
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// debugLog covers two Bash calls with two parallel PreToolUse hooks, one
+// PostToolUse hook and a SessionStart hook, among unrelated lines
+const debugLog = `2026-01-01T10:00:00.000Z [DEBUG] Executing hooks for SessionStart:startup
+2026-01-01T10:00:00.010Z [DEBUG] Executing hook command: ~/bin/setup.sh with timeout 60000ms
+2026-01-01T10:00:00.510Z [DEBUG] Hook command completed with status 0: ~/bin/setup.sh
+2026-01-01T10:00:01.000Z [DEBUG] Stream started - received first chunk
+2026-01-01T10:00:02.000Z [DEBUG] Executing hooks for PreToolUse:Bash
+2026-01-01T10:00:02.001Z [DEBUG] Executing hook command: lint.sh with timeout 60000ms
+2026-01-01T10:00:02.002Z [DEBUG] Executing hook command: claude-hud --hook with timeout 60000ms
+2026-01-01T10:00:02.052Z [DEBUG] Hook command completed with status 0: claude-hud --hook
+2026-01-01T10:00:04.001Z [DEBUG] Hook command completed with status 0: lint.sh
+2026-01-01T10:00:05.000Z [DEBUG] Executing hooks for PostToolUse:Bash
+2026-01-01T10:00:05.000Z [DEBUG] Executing hook command: claude-hud --hook with timeout 60000ms
+2026-01-01T10:00:05.100Z [DEBUG] Hook command completed with status 0: claude-hud --hook
+2026-01-01T10:00:06.000Z [DEBUG] Executing hooks for PreToolUse:Bash
+2026-01-01T10:00:06.000Z [DEBUG] Executing hook command: lint.sh with timeout 60000ms
+2026-01-01T10:00:06.000Z [DEBUG] Executing hook command: claude-hud --hook with timeout 60000ms
+2026-01-01T10:00:06.050Z [DEBUG] Hook command completed with status 0: claude-hud --hook
+2026-01-01T10:00:07.000Z [DEBUG] Hook command completed with status 2: lint.sh
+2026-01-01T10:00:08.000Z [DEBUG] Executing hooks for PostToolUse:Bash
+2026-01-01T10:00:08.000Z [DEBUG] Executing hook command: claude-hud --hook with timeout 60000ms
+2026-01-01T10:00:08.100Z [DEBUG] Hook command completed with status 0: claude-hud --hook
+`
+
+func TestParseDebugLog(t *testing.T) {
+	runs, err := ParseDebugLog(strings.NewReader(debugLog))
+	if err != nil {
+		t.Fatalf("ParseDebugLog() error = %v", err)
+	}
+	if len(runs) != 7 {
+		t.Fatalf("expected 7 runs, got %d", len(runs))
+	}
+
+	first := runs[0]
+	if first.Event != EventSessionStart || first.Tool != "startup" || first.Command != "~/bin/setup.sh" || first.Duration != 500*time.Millisecond {
+		t.Errorf("unexpected first run: %+v", first)
+	}
+	lint := runs[2]
+	if lint.Event != EventPreToolUse || lint.Tool != "Bash" || lint.Command != "lint.sh" || lint.Duration != 2*time.Second {
+		t.Errorf("unexpected lint run: %+v", lint)
+	}
+	if runs[5].ExitCode != 2 {
+		t.Errorf("expected exit status 2 for the failed lint run, got %+v", runs[5])
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	runs, err := ParseDebugLog(strings.NewReader(debugLog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary := Summarize(runs, time.Second)
+
+	if summary.ToolCalls != 2 {
+		t.Errorf("ToolCalls = %d, want 2", summary.ToolCalls)
+	}
+	// Parallel hooks cost their slowest: 2s + 100ms + 1s + 100ms
+	if want := 3200 * time.Millisecond; summary.ToolLatency != want {
+		t.Errorf("ToolLatency = %v, want %v", summary.ToolLatency, want)
+	}
+	if want := 1600 * time.Millisecond; summary.PerToolCall() != want {
+		t.Errorf("PerToolCall() = %v, want %v", summary.PerToolCall(), want)
+	}
+
+	if len(summary.Hooks) != 4 {
+		t.Fatalf("expected 4 hook stats, got %+v", summary.Hooks)
+	}
+	top := summary.Hooks[0]
+	if top.Command != "lint.sh" || top.Runs != 2 || top.Slow != 2 || top.Failed != 1 || top.Max != 2*time.Second {
+		t.Errorf("unexpected top hook: %+v", top)
+	}
+	slowest, ok := summary.Slowest()
+	if !ok || slowest.Command != "lint.sh" || slowest.Average() != 1500*time.Millisecond {
+		t.Errorf("Slowest() = %+v, %v", slowest, ok)
+	}
+}
+
+func TestLatencyTracker_Incremental(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.txt")
+	lines := strings.SplitAfter(debugLog, "\n")
+	// Stop halfway through a line; it is read once complete
+	head := strings.Join(lines[:6], "") + lines[6][:20]
+	if err := os.WriteFile(path, []byte(head), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tracker := NewLatencyTracker(path)
+	runs, err := tracker.Runs()
+	if err != nil {
+		t.Fatalf("Runs() error = %v", err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("expected 1 completed run, got %d", len(runs))
+	}
+
+	if err := os.WriteFile(path, []byte(debugLog), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if runs, err = tracker.Runs(); err != nil || len(runs) != 7 {
+		t.Fatalf("expected 7 runs after the log grew, got %d (%v)", len(runs), err)
+	}
+
+	// A replaced, shorter log is read from the start
+	if err := os.WriteFile(path, []byte(strings.Join(lines[:3], "")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if runs, err = tracker.Runs(); err != nil || len(runs) != 1 {
+		t.Errorf("expected 1 run after the log was replaced, got %d (%v)", len(runs), err)
+	}
+}
+
+func TestSessionIDFromTranscript(t *testing.T) {
+	for path, want := range map[string]string{
+		"/home/u/.claude/projects/-p/abc-123.jsonl":    "abc-123",
+		"/home/u/.claude/projects/-p/abc-123.jsonl.gz": "abc-123",
+	} {
+		if got := SessionIDFromTranscript(path); got != want {
+			t.Errorf("SessionIDFromTranscript(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+// Package humanize writes durations and timestamps the way people read them:
+// "1h05m", "3d4h", "5m ago" or "in 35m", with unit symbols and phrases in
+// the configured locale. Sections, git and the CLI share it so the same span
+// of time reads the same everywhere
+package humanize
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/i18n"
+)
+
+// Format controls how a duration is written
+type Format struct {
+	// Precision is the most units shown, counting from the largest: 2 gives
+	// "1h5m" and 1 gives "1h". Zero means 2
+	Precision int
+
+	// Compact writes the units without spaces, "1h5m" rather than "1h 5m"
+	Compact bool
+
+	// Pad keeps units after the first that are zero and writes minutes and
+	// seconds after the first unit with two digits, like a clock: "1h05m" and
+	// "1h00m" rather than "1h5m" and "1h"
+	Pad bool
+
+	// Smallest is the smallest unit shown: time.Second, time.Minute,
+	// time.Hour or Day. Zero means time.Second. A shorter duration reads as
+	// less than one of it, e.g. "<1m", except that under a second is "0s"
+	Smallest time.Duration
+}
+
+// Day is the unit above an hour
+const Day = 24 * time.Hour
+
+var (
+	// Short is the format of countdowns and remaining times, e.g. "1h05m",
+	// "25m", "3d4h" or "<1m"
+	Short = Format{Compact: true, Pad: true, Smallest: time.Minute}
+
+	// Elapsed is the format of elapsed times such as a session's length,
+	// e.g. "45s", "12m", "1h5m" or "2d"
+	Elapsed = Format{Compact: true}
+)
+
+// units are the units a duration is written in, largest first
+var units = []struct {
+	size   time.Duration
+	symbol string // Key of the unit's symbol under unit. in the catalogs
+}{
+	{Day, "d"},
+	{time.Hour, "h"},
+	{time.Minute, "m"},
+	{time.Second, "s"},
+}
+
+// Duration writes d in the given format; a negative d is written as zero
+func Duration(d time.Duration, f Format) string {
+	precision := f.Precision
+	if precision <= 0 {
+		precision = 2
+	}
+	smallest := time.Second
+	if f.Smallest > 0 {
+		smallest = units[unitIndex(f.Smallest)].size
+	}
+	d = max(d, 0)
+
+	// Rounding can carry into a larger unit, 59m50s becoming 1h, which
+	// shifts the units shown; rounding again settles it
+	first := 0
+	for range 2 {
+		first = largestUnit(d, smallest)
+		last := min(first+precision-1, unitIndex(smallest))
+		d = d.Round(units[last].size)
+	}
+	if d < smallest && smallest > time.Second {
+		return "<" + unitText(1, unitIndex(smallest), false)
+	}
+
+	last := min(first+precision-1, unitIndex(smallest))
+	counts := make([]int, 0, last-first+1)
+	for i := first; i <= last; i++ {
+		counts = append(counts, int(d/units[i].size))
+		d %= units[i].size
+	}
+	if !f.Pad {
+		// Drop trailing zero units: "1h" reads better than "1h0m"
+		for len(counts) > 1 && counts[len(counts)-1] == 0 {
+			counts = counts[:len(counts)-1]
+		}
+	}
+
+	parts := make([]string, len(counts))
+	for i, n := range counts {
+		parts[i] = unitText(n, first+i, f.Pad && i > 0 && units[first+i].size < time.Hour)
+	}
+
+	separator := " "
+	if f.Compact {
+		separator = ""
+	}
+	return strings.Join(parts, separator)
+}
+
+// Count writes a count such as a number of tokens compactly, e.g. "950",
+// "48k" or "1.2M"
+func Count(n int) string {
+	if n >= 1_000_000 {
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	}
+	if n >= 1_000 {
+		return fmt.Sprintf("%dk", n/1_000)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// Ago writes how long ago something happened in its largest unit, e.g.
+// "just now", "5m ago" or "3d ago". Units are truncated, so 5m59s is "5m ago"
+func Ago(d time.Duration) string {
+	if d < time.Minute {
+		return i18n.T("time.just_now")
+	}
+	i := largestUnit(d, time.Minute)
+	return i18n.T("time.ago", unitText(int(d/units[i].size), i, false))
+}
+
+// In writes how long until something happens, e.g. "in 35m" or "in 1h05m",
+// or "soon" when it is due
+func In(d time.Duration) string {
+	if d <= 0 {
+		return i18n.T("time.soon")
+	}
+	return i18n.T("time.in", Duration(d, Short))
+}
+
+// Since writes how long ago t was at now, e.g. "2h ago"
+func Since(t, now time.Time) string {
+	return Ago(now.Sub(t))
+}
+
+// Until writes how long until t at now, e.g. "in 35m"
+func Until(t, now time.Time) string {
+	return In(t.Sub(now))
+}
+
+// largestUnit returns the index of the largest unit d has at least one of,
+// or of smallest when it has none
+func largestUnit(d, smallest time.Duration) int {
+	for i, u := range units {
+		if d >= u.size || u.size == smallest {
+			return i
+		}
+	}
+	return len(units) - 1
+}
+
+// unitIndex returns the index of the largest unit no longer than size
+func unitIndex(size time.Duration) int {
+	for i, u := range units {
+		if u.size <= size {
+			return i
+		}
+	}
+	return len(units) - 1
+}
+
+// unitText writes n of a unit with its symbol, e.g. "5m", or "05m" padded
+func unitText(n, unit int, pad bool) string {
+	format := "%d%s"
+	if pad {
+		format = "%02d%s"
+	}
+	return fmt.Sprintf(format, n, i18n.T("unit."+units[unit].symbol))
+}
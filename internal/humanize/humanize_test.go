@@ -0,0 +1,102 @@
+package humanize
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/i18n"
+)
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		name   string
+		d      time.Duration
+		format Format
+		want   string
+	}{
+		{name: "seconds", d: 45 * time.Second, format: Elapsed, want: "45s"},
+		{name: "minutes and seconds", d: 4*time.Minute + 20*time.Second, format: Elapsed, want: "4m20s"},
+		{name: "whole hours", d: 2 * time.Hour, format: Elapsed, want: "2h"},
+		{name: "hours and minutes", d: 65 * time.Minute, format: Elapsed, want: "1h5m"},
+		{name: "days", d: 50 * time.Hour, format: Elapsed, want: "2d2h"},
+		{name: "negative", d: -time.Minute, format: Elapsed, want: "0s"},
+		{name: "short padded", d: 65 * time.Minute, format: Short, want: "1h05m"},
+		{name: "short zero minutes", d: 2 * time.Hour, format: Short, want: "2h00m"},
+		{name: "short rounds to minutes", d: 4*time.Minute + 40*time.Second, format: Short, want: "5m"},
+		{name: "short carries into hours", d: 59*time.Minute + 50*time.Second, format: Short, want: "1h00m"},
+		{name: "short days", d: 76 * time.Hour, format: Short, want: "3d4h"},
+		{name: "short under a minute", d: 20 * time.Second, format: Short, want: "<1m"},
+		{name: "spaced", d: 26*time.Hour + 10*time.Minute, format: Format{}, want: "1d 2h"},
+		{name: "precision 1", d: 26 * time.Hour, format: Format{Precision: 1}, want: "1d"},
+		{name: "precision 3", d: 26*time.Hour + 10*time.Minute, format: Format{Precision: 3, Compact: true}, want: "1d2h10m"},
+		{name: "hours at least", d: 20 * time.Minute, format: Format{Precision: 1, Smallest: time.Hour}, want: "<1h"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Duration(tt.d, tt.format); got != tt.want {
+				t.Errorf("Duration(%v) = %q, want %q", tt.d, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAgo(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		30 * time.Second:               "just now",
+		5*time.Minute + 59*time.Second: "5m ago",
+		2*time.Hour + 10*time.Minute:   "2h ago",
+		26 * time.Hour:                 "1d ago",
+		80 * time.Hour:                 "3d ago",
+	} {
+		if got := Ago(d); got != want {
+			t.Errorf("Ago(%v) = %q, want %q", d, got, want)
+		}
+	}
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if got := Since(now.Add(-3*time.Hour), now); got != "3h ago" {
+		t.Errorf("Since() = %q, want %q", got, "3h ago")
+	}
+}
+
+func TestIn(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if got := Until(now.Add(35*time.Minute), now); got != "in 35m" {
+		t.Errorf("Until() = %q, want %q", got, "in 35m")
+	}
+	if got := In(-time.Minute); got != "soon" {
+		t.Errorf("In() of a past time = %q, want %q", got, "soon")
+	}
+}
+
+func TestLocalized(t *testing.T) {
+	t.Cleanup(func() { i18n.SetLocale(i18n.DefaultLocale) })
+
+	tests := []struct {
+		locale string
+		got    func() string
+		want   string
+	}{
+		{locale: "de", got: func() string { return Duration(25*time.Minute, Short) }, want: "25min"},
+		{locale: "de", got: func() string { return Ago(3 * time.Hour) }, want: "vor 3h"},
+		{locale: "fr", got: func() string { return Duration(76*time.Hour, Short) }, want: "3j4h"},
+		{locale: "fr", got: func() string { return In(35 * time.Minute) }, want: "dans 35min"},
+		{locale: "es", got: func() string { return Ago(5 * time.Minute) }, want: "hace 5min"},
+		{locale: "ja", got: func() string { return Duration(72*time.Minute, Short) }, want: "1時間12分"},
+		{locale: "ja", got: func() string { return Ago(72 * time.Hour) }, want: "3日前"},
+	}
+	for _, tt := range tests {
+		i18n.SetLocale(tt.locale)
+		if got := tt.got(); got != tt.want {
+			t.Errorf("in %s got %q, want %q", tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestCount(t *testing.T) {
+	for n, want := range map[int]string{950: "950", 48_500: "48k", 1_234_567: "1.2M"} {
+		if got := Count(n); got != want {
+			t.Errorf("Count(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
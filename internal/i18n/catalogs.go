@@ -0,0 +1,194 @@
+package i18n
+
+// English has every message; the other catalogs may leave some out
+var en = map[string]string{
+	"time.just_now": "just now",
+	"time.ago":      "%s ago",
+	"time.in":       "in %s",
+	"time.soon":     "soon",
+	"unit.s":        "s",
+	"unit.m":        "m",
+	"unit.h":        "h",
+	"unit.d":        "d",
+
+	"health.degraded":    "degraded",
+	"health.unavailable": "unavailable",
+
+	"workspace.unavailable": "[Workspace: unavailable]",
+	"workspace.waiting":     "[Workspace: waiting for data]",
+
+	"git.conflicts.one":   "%d conflict",
+	"git.conflicts.other": "%d conflicts",
+
+	"commit.last": "last: %s",
+	"commit.by":   "by %s",
+
+	"window.resets":   "resets %s",
+	"approval.needed": "approval needed",
+	"turns.longest":   "longest %s",
+
+	"sessions.active.one":   "%d session",
+	"sessions.active.other": "%d sessions",
+	"sessions.working":      "%d working",
+	"sessions.approval":     "%d approval",
+
+	"plain.percent":      "%s percent",
+	"plain.context":      "context: %s",
+	"plain.warning":      "warning",
+	"plain.critical":     "critical",
+	"plain.long_context": "over 200k tokens",
+}
+
+var de = map[string]string{
+	"time.just_now": "gerade eben",
+	"time.ago":      "vor %s",
+	"time.in":       "in %s",
+	"time.soon":     "gleich",
+	"unit.m":        "min",
+	"unit.d":        "T",
+
+	"health.degraded":    "gestört",
+	"health.unavailable": "nicht verfügbar",
+
+	"workspace.unavailable": "[Workspace: nicht verfügbar]",
+	"workspace.waiting":     "[Workspace: warte auf Daten]",
+
+	"git.conflicts.one":   "%d Konflikt",
+	"git.conflicts.other": "%d Konflikte",
+
+	"commit.last": "zuletzt: %s",
+	"commit.by":   "von %s",
+
+	"window.resets":   "zurückgesetzt in %s",
+	"approval.needed": "Freigabe nötig",
+	"turns.longest":   "längste %s",
+
+	"sessions.active.one":   "%d Sitzung",
+	"sessions.active.other": "%d Sitzungen",
+	"sessions.working":      "%d aktiv",
+	"sessions.approval":     "%d Freigabe",
+
+	"plain.percent":      "%s Prozent",
+	"plain.context":      "Kontext: %s",
+	"plain.warning":      "Warnung",
+	"plain.critical":     "kritisch",
+	"plain.long_context": "über 200k Token",
+}
+
+var es = map[string]string{
+	"time.just_now": "ahora mismo",
+	"time.ago":      "hace %s",
+	"time.in":       "en %s",
+	"time.soon":     "pronto",
+	"unit.m":        "min",
+
+	"health.degraded":    "degradado",
+	"health.unavailable": "no disponible",
+
+	"workspace.unavailable": "[Workspace: no disponible]",
+	"workspace.waiting":     "[Workspace: esperando datos]",
+
+	"git.conflicts.one":   "%d conflicto",
+	"git.conflicts.other": "%d conflictos",
+
+	"commit.last": "último: %s",
+	"commit.by":   "de %s",
+
+	"window.resets":   "se reinicia en %s",
+	"approval.needed": "aprobación pendiente",
+	"turns.longest":   "más largo %s",
+
+	"sessions.active.one":   "%d sesión",
+	"sessions.active.other": "%d sesiones",
+	"sessions.working":      "%d trabajando",
+	"sessions.approval":     "%d aprobación",
+
+	"plain.percent":      "%s por ciento",
+	"plain.context":      "contexto: %s",
+	"plain.warning":      "advertencia",
+	"plain.critical":     "crítico",
+	"plain.long_context": "más de 200k tokens",
+}
+
+var fr = map[string]string{
+	"time.just_now": "à l'instant",
+	"time.ago":      "il y a %s",
+	"time.in":       "dans %s",
+	"time.soon":     "bientôt",
+	"unit.m":        "min",
+	"unit.d":        "j",
+
+	"health.degraded":    "dégradé",
+	"health.unavailable": "indisponible",
+
+	"workspace.unavailable": "[Workspace : indisponible]",
+	"workspace.waiting":     "[Workspace : en attente de données]",
+
+	"git.conflicts.one":   "%d conflit",
+	"git.conflicts.other": "%d conflits",
+
+	"commit.last": "dernier : %s",
+	"commit.by":   "par %s",
+
+	"window.resets":   "réinitialisé dans %s",
+	"approval.needed": "approbation requise",
+	"turns.longest":   "plus long %s",
+
+	"sessions.active.one":   "%d session",
+	"sessions.active.other": "%d sessions",
+	"sessions.working":      "%d en cours",
+	"sessions.approval":     "%d approbation",
+
+	"plain.percent":      "%s pour cent",
+	"plain.context":      "contexte : %s",
+	"plain.warning":      "avertissement",
+	"plain.critical":     "critique",
+	"plain.long_context": "plus de 200k jetons",
+}
+
+var ja = map[string]string{
+	"time.just_now": "たった今",
+	"time.ago":      "%s前",
+	"time.in":       "%s後",
+	"time.soon":     "まもなく",
+	"unit.s":        "秒",
+	"unit.m":        "分",
+	"unit.h":        "時間",
+	"unit.d":        "日",
+
+	"health.degraded":    "低下",
+	"health.unavailable": "利用不可",
+
+	"workspace.unavailable": "[ワークスペース: 利用不可]",
+	"workspace.waiting":     "[ワークスペース: データ待ち]",
+
+	"git.conflicts.one":   "競合 %d件",
+	"git.conflicts.other": "競合 %d件",
+
+	"commit.last": "最新: %s",
+	"commit.by":   "作成者 %s",
+
+	"window.resets":   "リセットまで %s",
+	"approval.needed": "承認待ち",
+	"turns.longest":   "最長 %s",
+
+	"sessions.active.one":   "%d セッション",
+	"sessions.active.other": "%d セッション",
+	"sessions.working":      "%d 作業中",
+	"sessions.approval":     "%d 承認待ち",
+
+	"plain.percent":      "%sパーセント",
+	"plain.context":      "コンテキスト: %s",
+	"plain.warning":      "警告",
+	"plain.critical":     "重大",
+	"plain.long_context": "20万トークン超",
+}
+
+// catalogs are the translations by locale
+var catalogs = map[string]*catalog{
+	"en": {locale: "en", messages: en, one: func(n int) bool { return n == 1 }},
+	"de": {locale: "de", messages: de, one: func(n int) bool { return n == 1 }},
+	"es": {locale: "es", messages: es, one: func(n int) bool { return n == 1 }},
+	"fr": {locale: "fr", messages: fr, one: func(n int) bool { return n == 0 || n == 1 }},
+	"ja": {locale: "ja", messages: ja, one: func(n int) bool { return false }},
+}
@@ -0,0 +1,125 @@
+// Package i18n translates the labels sections show, and the unit symbols and
+// phrases durations are written with, for the configured locale. Messages missing from a locale's catalog fall
+// back to English, so a partial translation never leaves a hole
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/render"
+)
+
+// DefaultLocale is the locale used when none is configured or detected
+const DefaultLocale = "en"
+
+// catalog is a locale's messages by key, with its plural rule
+type catalog struct {
+	locale   string
+	messages map[string]string
+	one      func(n int) bool // Whether n takes the singular form
+}
+
+// current is the catalog in use, swapped atomically since the daemon renders
+// from several goroutines
+var current atomic.Pointer[catalog]
+
+func init() {
+	current.Store(catalogs[DefaultLocale])
+}
+
+// SetLocale selects the catalog for a locale tag such as "de", "pt-BR" or
+// "fr_FR.UTF-8". "auto" or "" detects it from LC_ALL, LC_MESSAGES and LANG.
+// It returns the locale in use and whether a catalog matched; an unknown
+// locale selects English
+func SetLocale(tag string) (string, bool) {
+	if tag == "" || tag == "auto" {
+		tag = detectLocale(os.Getenv)
+	}
+	c, ok := lookup(tag)
+	if !ok {
+		c = catalogs[DefaultLocale]
+	}
+	current.Store(c)
+	return c.locale, ok
+}
+
+// Locale returns the locale in use, e.g. "en"
+func Locale() string {
+	return current.Load().locale
+}
+
+// Locales returns the locales with a catalog, in order
+func Locales() []string {
+	return render.Keys(catalogs)
+}
+
+// T returns the message for key in the current locale, formatted with args
+// when given. A key missing from every catalog is returned as is
+func T(key string, args ...interface{}) string {
+	message := current.Load().message(key)
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// N returns the singular or plural message for a count, e.g. "1 conflict"
+// or "3 conflicts", formatted with n. The forms are key.one and key.other
+func N(key string, n int) string {
+	c := current.Load()
+	if c.one(n) {
+		return fmt.Sprintf(c.message(key+".one"), n)
+	}
+	return fmt.Sprintf(c.message(key+".other"), n)
+}
+
+// message returns the message for key, falling back to English
+func (c *catalog) message(key string) string {
+	if message, ok := c.messages[key]; ok {
+		return message
+	}
+	if message, ok := catalogs[DefaultLocale].messages[key]; ok {
+		return message
+	}
+	return key
+}
+
+// lookup finds the catalog for a locale tag, trying the region-specific
+// locale before the language alone
+func lookup(tag string) (*catalog, bool) {
+	tag = normalize(tag)
+	if c, ok := catalogs[tag]; ok {
+		return c, true
+	}
+	language, _, _ := strings.Cut(tag, "_")
+	c, ok := catalogs[language]
+	return c, ok
+}
+
+// normalize turns "fr_FR.UTF-8", "pt-BR" or "de_DE@euro" into "fr_fr",
+// "pt_br" and "de_de"
+func normalize(tag string) string {
+	tag, _, _ = strings.Cut(tag, ".")
+	tag, _, _ = strings.Cut(tag, "@")
+	return strings.ToLower(strings.ReplaceAll(tag, "-", "_"))
+}
+
+// detectLocale reads the locale from the environment the way gettext does:
+// LC_ALL overrides LC_MESSAGES, which overrides LANG. The C and POSIX
+// locales mean English
+func detectLocale(getenv func(key string) string) string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := getenv(key)
+		if value == "" {
+			continue
+		}
+		if value == "C" || value == "POSIX" || strings.HasPrefix(value, "C.") {
+			return DefaultLocale
+		}
+		return value
+	}
+	return DefaultLocale
+}
@@ -0,0 +1,100 @@
+package i18n
+
+import "testing"
+
+// useLocale selects a locale for one test, restoring English afterwards
+func useLocale(t *testing.T, tag string) {
+	t.Helper()
+	if _, ok := SetLocale(tag); !ok {
+		t.Fatalf("SetLocale(%q) found no catalog", tag)
+	}
+	t.Cleanup(func() { SetLocale(DefaultLocale) })
+}
+
+func TestSetLocale(t *testing.T) {
+	t.Cleanup(func() { SetLocale(DefaultLocale) })
+
+	tests := []struct {
+		tag    string
+		want   string
+		wantOK bool
+	}{
+		{tag: "de", want: "de", wantOK: true},
+		{tag: "fr_FR.UTF-8", want: "fr", wantOK: true},
+		{tag: "es-MX", want: "es", wantOK: true},
+		{tag: "de_DE@euro", want: "de", wantOK: true},
+		{tag: "JA", want: "ja", wantOK: true},
+		{tag: "tlh", want: "en", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, ok := SetLocale(tt.tag)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("SetLocale(%q) = %q, %v, want %q, %v", tt.tag, got, ok, tt.want, tt.wantOK)
+			}
+			if Locale() != tt.want {
+				t.Errorf("Locale() = %q, want %q", Locale(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectLocale(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{name: "unset", want: "en"},
+		{name: "lang", env: map[string]string{"LANG": "de_DE.UTF-8"}, want: "de_DE.UTF-8"},
+		{name: "lc_messages over lang", env: map[string]string{"LANG": "de_DE.UTF-8", "LC_MESSAGES": "fr_FR.UTF-8"}, want: "fr_FR.UTF-8"},
+		{name: "lc_all over everything", env: map[string]string{"LANG": "de_DE.UTF-8", "LC_ALL": "ja_JP.UTF-8"}, want: "ja_JP.UTF-8"},
+		{name: "c locale", env: map[string]string{"LANG": "C.UTF-8"}, want: "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectLocale(func(key string) string { return tt.env[key] }); got != tt.want {
+				t.Errorf("detectLocale() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := T("window.resets", "1h12m"); got != "resets 1h12m" {
+		t.Errorf("T() = %q, want %q", got, "resets 1h12m")
+	}
+	if got := T("no.such.key"); got != "no.such.key" {
+		t.Errorf("T() of an unknown key = %q, want the key", got)
+	}
+
+	useLocale(t, "de")
+	if got := T("window.resets", "1h12m"); got != "zurückgesetzt in 1h12m" {
+		t.Errorf("T() in German = %q", got)
+	}
+	// A message the catalog leaves out falls back to English
+	if got := T("unit.h"); got != "h" {
+		t.Errorf("T() fallback = %q, want %q", got, "h")
+	}
+}
+
+func TestN(t *testing.T) {
+	tests := []struct {
+		locale string
+		n      int
+		want   string
+	}{
+		{locale: "en", n: 1, want: "1 conflict"},
+		{locale: "en", n: 0, want: "0 conflicts"},
+		{locale: "en", n: 3, want: "3 conflicts"},
+		{locale: "fr", n: 0, want: "0 conflit"},
+		{locale: "fr", n: 2, want: "2 conflits"},
+		{locale: "ja", n: 1, want: "競合 1件"},
+	}
+	for _, tt := range tests {
+		useLocale(t, tt.locale)
+		if got := N("git.conflicts", tt.n); got != tt.want {
+			t.Errorf("N(%d) in %s = %q, want %q", tt.n, tt.locale, got, tt.want)
+		}
+	}
+}
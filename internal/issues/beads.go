@@ -0,0 +1,55 @@
+package issues
+
+import (
+	"context"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/beads"
+)
+
+// Beads reads issues from the repository's beads tracker
+type Beads struct {
+	Reader *beads.Reader
+}
+
+// Name returns the provider name
+func (b Beads) Name() string {
+	return "bd"
+}
+
+// Key implements Provider; beads is local, so its snapshots are not cached
+func (b Beads) Key() string {
+	return "bd"
+}
+
+// Fetch implements Provider
+func (b Beads) Fetch(ctx context.Context) (Snapshot, error) {
+	if err := b.Reader.Load(ctx); err != nil {
+		return Snapshot{}, err
+	}
+
+	snapshot := Snapshot{
+		Open:  b.Reader.CountByStatus(beads.StatusOpen),
+		Total: b.Reader.Count(),
+	}
+	current := b.Reader.GetCurrentIssue()
+	if current == nil {
+		return snapshot, nil
+	}
+
+	issue := &Issue{
+		ID:          current.ID,
+		Title:       current.Title,
+		Description: current.Description,
+		Status:      current.Status,
+		Priority:    current.GetPriorityLabel(),
+	}
+	if epic := b.Reader.GetEpic(current.ID); epic != nil {
+		progress := b.Reader.GetEpicProgress(epic.ID)
+		issue.Epic = &Epic{Title: epic.Title, Closed: progress.Closed, Total: progress.Total}
+		if epic.ID == current.ID {
+			issue.Epic.Title = "" // Already shown as the issue's title
+		}
+	}
+	snapshot.Current = issue
+	return snapshot, nil
+}
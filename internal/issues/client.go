@@ -0,0 +1,120 @@
+package issues
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/filecache"
+	"github.com/ll931217/claude-hud-enhanced/internal/refresh"
+)
+
+// DefaultInterval is how often a remote tracker is asked again
+const DefaultInterval = 2 * time.Minute
+
+// cachedSnapshot is the outcome of the last request to a provider
+type cachedSnapshot struct {
+	Snapshot  Snapshot  `json:"snapshot"`
+	Found     bool      `json:"found"` // A request has succeeded
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"` // Last attempt, successful or not
+}
+
+// Client fetches and caches snapshots of remote trackers
+// Requests run in the background, so a slow tracker never holds up a render,
+// and failures are cached like snapshots, so a tracker that is down is not
+// asked on every render; with a cache path, snapshots are shared across processes
+type Client struct {
+	CachePath string        // Optional file caching the last snapshots
+	Timeout   time.Duration // Bounds each request
+
+	mu      sync.Mutex
+	cache   map[string]cachedSnapshot // Keyed by Provider.Key
+	loaded  bool
+	fetches refresh.Group
+}
+
+// NewClient creates a client persisted at cachePath ("" keeps snapshots in memory)
+func NewClient(cachePath string) *Client {
+	return &Client{
+		CachePath: cachePath,
+		Timeout:   10 * time.Second,
+		cache:     make(map[string]cachedSnapshot),
+	}
+}
+
+// Snapshot returns provider's snapshot, asking it in the background once the
+// cached one is older than interval. It waits for the request until ctx is
+// done; until then, and after a failure, the previous snapshot, if any, is
+// returned, after a failure with the error
+func (c *Client) Snapshot(ctx context.Context, provider Provider, interval time.Duration) (Snapshot, bool, error) {
+	key := provider.Key()
+	c.mu.Lock()
+	c.load()
+	last, ok := c.cache[key]
+	c.mu.Unlock()
+
+	now := time.Now()
+	if !ok || now.Sub(last.CheckedAt) >= interval || now.Before(last.CheckedAt) {
+		if c.fetches.Do(ctx, key, func() { c.fetch(provider, key) }) {
+			c.mu.Lock()
+			last = c.cache[key]
+			c.mu.Unlock()
+		}
+	}
+
+	if last.Error != "" {
+		return last.Snapshot, last.Found, errors.New(last.Error)
+	}
+	return last.Snapshot, last.Found, nil
+}
+
+// fetch asks provider for a snapshot and caches the outcome
+func (c *Client) fetch(provider Provider, key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+	snapshot, err := provider.Fetch(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	last := c.cache[key]
+	last.CheckedAt = now
+	last.Error = ""
+	if err != nil {
+		last.Error = err.Error()
+	} else {
+		last.Snapshot, last.Found = snapshot, true
+	}
+	c.cache[key] = last
+	c.prune(now)
+	c.save()
+}
+
+// Wait blocks until requests still running finish
+func (c *Client) Wait() {
+	c.fetches.Wait()
+}
+
+// prune drops snapshots nobody asked about for a day, e.g. of deleted branches
+func (c *Client) prune(now time.Time) {
+	filecache.Prune(c.cache, now, 24*time.Hour, func(s cachedSnapshot) time.Time { return s.CheckedAt })
+}
+
+// load reads the persisted snapshots once; a missing or corrupt file starts empty
+func (c *Client) load() {
+	if c.loaded {
+		return
+	}
+	c.loaded = true
+	var cache map[string]cachedSnapshot
+	if filecache.Load(c.CachePath, &cache) {
+		maps.Copy(c.cache, cache)
+	}
+}
+
+func (c *Client) save() {
+	_ = filecache.Save(c.CachePath, c.cache)
+}
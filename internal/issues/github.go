@@ -0,0 +1,171 @@
+package issues
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/beads"
+)
+
+// DefaultGitHubAPI is GitHub's REST API
+const DefaultGitHubAPI = "https://api.github.com"
+
+// priorityLabelPattern matches priority labels such as "P1" or "priority: high"
+var priorityLabelPattern = regexp.MustCompile(`(?i)^(?:p[0-4]|priority[:/ ]\s*\S+)$`)
+
+// GitHub reads GitHub Issues of a repository. The current issue is the one
+// whose number the branch names, e.g. 123-fix-login
+type GitHub struct {
+	API        string // Defaults to DefaultGitHubAPI
+	Token      string // Without one, the gh command's login is used when installed
+	Owner      string
+	Repo       string
+	Branch     string
+	HTTPClient *http.Client
+
+	// runGH overrides the gh command in tests
+	runGH func(ctx context.Context, args ...string) ([]byte, error)
+}
+
+// Name returns the provider name
+func (g GitHub) Name() string {
+	return "gh"
+}
+
+// Key implements Provider
+func (g GitHub) Key() string {
+	return "gh\x00" + g.Owner + "/" + g.Repo + "\x00" + g.Branch
+}
+
+// Fetch implements Provider
+func (g GitHub) Fetch(ctx context.Context) (Snapshot, error) {
+	var snapshot Snapshot
+	repo := url.PathEscape(g.Owner) + "/" + url.PathEscape(g.Repo)
+
+	if number := BranchNumber(g.Branch); number != "" {
+		var payload struct {
+			Number      int             `json:"number"`
+			Title       string          `json:"title"`
+			Body        string          `json:"body"`
+			State       string          `json:"state"`
+			HTMLURL     string          `json:"html_url"`
+			PullRequest json.RawMessage `json:"pull_request"`
+			Labels      []struct {
+				Name string `json:"name"`
+			} `json:"labels"`
+			Milestone *struct {
+				Title        string `json:"title"`
+				OpenIssues   int    `json:"open_issues"`
+				ClosedIssues int    `json:"closed_issues"`
+			} `json:"milestone"`
+		}
+		err := g.get(ctx, "repos/"+repo+"/issues/"+number, &payload)
+		switch {
+		case errors.Is(err, errNotFound):
+		case err != nil:
+			return snapshot, err
+		case len(payload.PullRequest) == 0 || string(payload.PullRequest) == "null":
+			issue := &Issue{
+				ID:          fmt.Sprintf("#%d", payload.Number),
+				Title:       payload.Title,
+				Description: payload.Body,
+				Status:      beads.StatusInProgress, // Its branch is checked out
+				URL:         payload.HTMLURL,
+			}
+			if payload.State == "closed" {
+				issue.Status = beads.StatusClosed
+			}
+			for _, label := range payload.Labels {
+				if priorityLabelPattern.MatchString(label.Name) {
+					issue.Priority = label.Name
+					break
+				}
+			}
+			if m := payload.Milestone; m != nil {
+				issue.Epic = &Epic{Title: m.Title, Closed: m.ClosedIssues, Total: m.OpenIssues + m.ClosedIssues}
+			}
+			snapshot.Current = issue
+		}
+	}
+
+	var err error
+	query := "repo:" + g.Owner + "/" + g.Repo + " is:issue"
+	if snapshot.Open, err = g.count(ctx, query+" is:open"); err != nil {
+		return snapshot, err
+	}
+	if snapshot.Total, err = g.count(ctx, query); err != nil {
+		return snapshot, err
+	}
+	return snapshot, nil
+}
+
+// count returns how many issues the search query finds
+func (g GitHub) count(ctx context.Context, query string) (int, error) {
+	var payload struct {
+		TotalCount int `json:"total_count"`
+	}
+	if err := g.get(ctx, "search/issues?per_page=1&q="+url.QueryEscape(query), &payload); err != nil {
+		return 0, err
+	}
+	return payload.TotalCount, nil
+}
+
+// get fetches an API path with the token, or through gh when there is none
+func (g GitHub) get(ctx context.Context, path string, v interface{}) error {
+	api := strings.TrimRight(g.API, "/")
+	if api == "" {
+		api = DefaultGitHubAPI
+	}
+	if g.Token == "" && api == DefaultGitHubAPI {
+		output, err := g.gh(ctx, "api", path)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(output, v); err != nil {
+				return fmt.Errorf("failed to parse gh output: %w", err)
+			}
+			return nil
+		case errors.Is(err, errNotFound):
+			return err
+		}
+		// Without gh, or logged out of it, fall back to unauthenticated requests
+	}
+
+	header := http.Header{}
+	if g.Token != "" {
+		header.Set("Authorization", "Bearer "+g.Token)
+	}
+	return doJSON(ctx, g.HTTPClient, http.MethodGet, api+"/"+path, header, nil, v)
+}
+
+// gh runs the gh command, mapping its HTTP 404 errors to errNotFound
+func (g GitHub) gh(ctx context.Context, args ...string) ([]byte, error) {
+	if g.runGH != nil {
+		return g.runGH(ctx, args...)
+	}
+	cmd := exec.CommandContext(ctx, "gh", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err == nil {
+		return output, nil
+	}
+	if errors.Is(err, exec.ErrNotFound) {
+		return nil, err
+	}
+	msg := strings.TrimSpace(stderr.String())
+	if strings.Contains(msg, "HTTP 404") {
+		return nil, errNotFound
+	}
+	if msg == "" {
+		return nil, fmt.Errorf("gh: %w", err)
+	}
+	return nil, fmt.Errorf("gh: %s", msg)
+}
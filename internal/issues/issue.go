@@ -0,0 +1,78 @@
+// Package issues reads the issue being worked on from an issue tracker:
+// beads, GitHub Issues, Linear or Jira
+package issues
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/beads"
+)
+
+// Issue is a tracker's issue reduced to what the HUD shows
+// Status uses beads' statuses, which the other trackers' states map onto
+type Issue struct {
+	ID          string            `json:"id"` // e.g. bd-12, #123, ENG-42
+	Title       string            `json:"title"`
+	Description string            `json:"description,omitempty"`
+	Status      beads.IssueStatus `json:"status"`
+	Priority    string            `json:"priority,omitempty"` // Tracker's label, e.g. P1 or High
+	URL         string            `json:"url,omitempty"`
+	Epic        *Epic             `json:"epic,omitempty"`
+}
+
+// Epic is the larger piece of work an issue belongs to, e.g. a beads epic,
+// a GitHub milestone, a Linear project or a Jira epic
+type Epic struct {
+	Title  string `json:"title"`
+	Closed int    `json:"closed"` // Closed child issues
+	Total  int    `json:"total"`  // 0 when the tracker does not report them
+}
+
+// Snapshot is what a provider knows about the issues of the workspace
+type Snapshot struct {
+	Current *Issue `json:"current,omitempty"` // Issue being worked on, nil when none is
+	Open    int    `json:"open"`
+	Total   int    `json:"total"`
+}
+
+// Provider reads issues from a tracker
+type Provider interface {
+	// Name returns the provider name, also shown in summaries, e.g. "gh"
+	Name() string
+	// Key identifies what the provider asks about, e.g. a repository and
+	// branch; snapshots are cached by it
+	Key() string
+	// Fetch returns the current issue and the open and total issue counts
+	Fetch(ctx context.Context) (Snapshot, error)
+}
+
+var (
+	// branchKeyPattern finds Linear and Jira keys such as ENG-123 in a
+	// branch name, e.g. alice/eng-123-fix-login
+	branchKeyPattern = regexp.MustCompile(`(?i)(?:^|[^a-z0-9])([a-z][a-z0-9]{1,9}-[0-9]+)(?:$|[^0-9])`)
+	// branchNumberPattern finds a GitHub issue number in a branch name, e.g.
+	// 123-fix-login, fix/123 or issue-123
+	branchNumberPattern = regexp.MustCompile(`(?:^|[/_-])#?([0-9]+)(?:$|[/_-])`)
+)
+
+// BranchKey returns the issue key named in a branch, upper-cased, e.g.
+// "ENG-123" for alice/eng-123-fix-login, or "" when there is none
+func BranchKey(branch string) string {
+	m := branchKeyPattern.FindStringSubmatch(branch)
+	if m == nil {
+		return ""
+	}
+	return strings.ToUpper(m[1])
+}
+
+// BranchNumber returns the issue number named in a branch, e.g. "123" for
+// 123-fix-login or fix/123, or "" when there is none
+func BranchNumber(branch string) string {
+	m := branchNumberPattern.FindStringSubmatch(branch)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
@@ -0,0 +1,244 @@
+package issues
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/beads"
+)
+
+func TestBranchKeyAndNumber(t *testing.T) {
+	tests := []struct {
+		branch, key, number string
+	}{
+		{"alice/eng-123-fix-login", "ENG-123", "123"},
+		{"ABC-42", "ABC-42", "42"},
+		{"123-fix-login", "", "123"},
+		{"fix/456", "", "456"},
+		{"issue-789", "ISSUE-789", "789"},
+		{"main", "", ""},
+		{"v2-migration", "", ""},
+	}
+	for _, tt := range tests {
+		if got := BranchKey(tt.branch); got != tt.key {
+			t.Errorf("BranchKey(%q) = %q, want %q", tt.branch, got, tt.key)
+		}
+		if got := BranchNumber(tt.branch); got != tt.number {
+			t.Errorf("BranchNumber(%q) = %q, want %q", tt.branch, got, tt.number)
+		}
+	}
+}
+
+func TestGitHubFetch(t *testing.T) {
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		switch {
+		case r.URL.Path == "/repos/acme/hud/issues/123":
+			w.Write([]byte(`{"number":123,"title":"Fix login","body":"- [x] form\n- [ ] tests","state":"open",
+				"html_url":"https://github.com/acme/hud/issues/123","labels":[{"name":"bug"},{"name":"P1"}],
+				"milestone":{"title":"v2.0","open_issues":3,"closed_issues":7}}`))
+		case r.URL.Path == "/search/issues" && strings.Contains(r.URL.Query().Get("q"), "is:open"):
+			w.Write([]byte(`{"total_count":12}`))
+		case r.URL.Path == "/search/issues":
+			w.Write([]byte(`{"total_count":40}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	g := GitHub{API: srv.URL, Token: "secret", Owner: "acme", Repo: "hud", Branch: "123-fix-login"}
+	snapshot, err := g.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("Authorization = %q", auth)
+	}
+	issue := snapshot.Current
+	if issue == nil || issue.ID != "#123" || issue.Status != beads.StatusInProgress || issue.Priority != "P1" {
+		t.Fatalf("Current = %+v", issue)
+	}
+	if issue.Epic == nil || issue.Epic.Title != "v2.0" || issue.Epic.Closed != 7 || issue.Epic.Total != 10 {
+		t.Errorf("Epic = %+v, want v2.0 7/10", issue.Epic)
+	}
+	if snapshot.Open != 12 || snapshot.Total != 40 {
+		t.Errorf("counts = %d/%d, want 12/40", snapshot.Open, snapshot.Total)
+	}
+
+	// A branch naming no issue only shows the counts
+	g.Branch = "999-gone"
+	if snapshot, err := g.Fetch(context.Background()); err != nil || snapshot.Current != nil {
+		t.Errorf("Fetch() for a missing issue = %+v, %v", snapshot, err)
+	}
+}
+
+func TestGitHubFetchThroughGH(t *testing.T) {
+	var paths []string
+	g := GitHub{Owner: "acme", Repo: "hud", Branch: "main", runGH: func(ctx context.Context, args ...string) ([]byte, error) {
+		paths = append(paths, args[1])
+		return []byte(`{"total_count":5}`), nil
+	}}
+	snapshot, err := g.Fetch(context.Background())
+	if err != nil || snapshot.Open != 5 || len(paths) != 2 || !strings.HasPrefix(paths[0], "search/issues?") {
+		t.Errorf("Fetch() through gh = %+v, %v (paths %q)", snapshot, err, paths)
+	}
+}
+
+func TestLinearFetch(t *testing.T) {
+	var request struct {
+		Variables struct {
+			ID    string `json:"id"`
+			ByKey bool   `json:"byKey"`
+		} `json:"variables"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "lin_api_key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		w.Write([]byte(`{"data":{"issue":{"identifier":"ENG-123","title":"Fix login","url":"https://linear.app/acme/issue/ENG-123",
+			"priorityLabel":"High","state":{"type":"started"},
+			"parent":{"title":"Auth rework","children":{"nodes":[{"state":{"type":"completed"}},{"state":{"type":"started"}}]}}},
+			"viewer":{"started":{"nodes":[]},"open":{"nodes":[{},{},{}]},"all":{"nodes":[{},{},{},{},{}]}}}}}`))
+	}))
+	defer srv.Close()
+
+	l := Linear{API: srv.URL, Token: "lin_api_key", Branch: "alice/eng-123-fix-login"}
+	snapshot, err := l.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if request.Variables.ID != "ENG-123" || !request.Variables.ByKey {
+		t.Errorf("variables = %+v, want the branch's key", request.Variables)
+	}
+	issue := snapshot.Current
+	if issue == nil || issue.ID != "ENG-123" || issue.Status != beads.StatusInProgress || issue.Priority != "High" {
+		t.Fatalf("Current = %+v", issue)
+	}
+	if issue.Epic == nil || issue.Epic.Closed != 1 || issue.Epic.Total != 2 {
+		t.Errorf("Epic = %+v, want 1/2", issue.Epic)
+	}
+	if snapshot.Open != 3 || snapshot.Total != 5 {
+		t.Errorf("counts = %d/%d, want 3/5", snapshot.Open, snapshot.Total)
+	}
+
+	if _, err := (Linear{API: srv.URL}).Fetch(context.Background()); err == nil {
+		t.Error("Fetch() without an API key succeeded")
+	}
+}
+
+func TestJiraFetch(t *testing.T) {
+	var cloudCounts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jql := r.URL.Query().Get("jql")
+		switch r.URL.Path {
+		case "/rest/api/2/issue/ABC-7":
+			w.Write([]byte(`{"key":"ABC-7","fields":{"summary":"Fix login","description":"- [ ] tests",
+				"status":{"statusCategory":{"key":"indeterminate"}},"priority":{"name":"Major"},
+				"parent":{"key":"ABC-1","fields":{"summary":"Auth rework"}}}}`))
+		case "/rest/api/2/search":
+			total := map[string]int{
+				"assignee = currentUser() AND statusCategory != Done": 4,
+				"assignee = currentUser()":                            9,
+				"parent = ABC-1":                                      5,
+				"parent = ABC-1 AND statusCategory = Done":            2,
+			}[jql]
+			fmt.Fprintf(w, `{"issues":[],"total":%d}`, total)
+		case "/rest/api/3/search/jql":
+			w.Write([]byte(`{"issues":[{"key":"ABC-9","fields":{"summary":"Cloud issue","description":{"type":"doc"},
+				"status":{"statusCategory":{"key":"indeterminate"}}}}]}`))
+		case "/rest/api/3/search/approximate-count":
+			user, _, _ := r.BasicAuth()
+			if user != "dev@acme.com" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			cloudCounts++
+			w.Write([]byte(`{"count":3}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	// Data Center with a personal access token
+	j := Jira{URL: srv.URL, Token: "pat", Branch: "ABC-7-fix-login"}
+	snapshot, err := j.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	issue := snapshot.Current
+	if issue == nil || issue.ID != "ABC-7" || issue.Priority != "Major" || issue.URL != srv.URL+"/browse/ABC-7" {
+		t.Fatalf("Current = %+v", issue)
+	}
+	if issue.Epic == nil || issue.Epic.Title != "Auth rework" || issue.Epic.Closed != 2 || issue.Epic.Total != 5 {
+		t.Errorf("Epic = %+v, want Auth rework 2/5", issue.Epic)
+	}
+	if snapshot.Open != 4 || snapshot.Total != 9 {
+		t.Errorf("counts = %d/%d, want 4/9", snapshot.Open, snapshot.Total)
+	}
+
+	// Jira Cloud without a key in the branch searches for the issue in progress
+	j = Jira{URL: srv.URL, Email: "dev@acme.com", Token: "token", Branch: "main"}
+	snapshot, err = j.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() on Cloud error = %v", err)
+	}
+	if snapshot.Current == nil || snapshot.Current.ID != "ABC-9" || snapshot.Current.Description != "" || cloudCounts != 2 {
+		t.Errorf("Fetch() on Cloud = %+v (%d counts)", snapshot.Current, cloudCounts)
+	}
+}
+
+// countingProvider counts its fetches
+type countingProvider struct {
+	fetches int
+	err     error
+}
+
+func (p *countingProvider) Name() string { return "test" }
+func (p *countingProvider) Key() string  { return "test" }
+func (p *countingProvider) Fetch(ctx context.Context) (Snapshot, error) {
+	p.fetches++
+	if p.err != nil {
+		return Snapshot{}, p.err
+	}
+	return Snapshot{Open: p.fetches}, nil
+}
+
+func TestClientSnapshot(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "issues.json")
+	provider := &countingProvider{}
+	client := NewClient(cachePath)
+
+	snapshot, found, err := client.Snapshot(context.Background(), provider, time.Hour)
+	if err != nil || !found || snapshot.Open != 1 {
+		t.Fatalf("Snapshot() = %+v, %v, %v", snapshot, found, err)
+	}
+	client.Snapshot(context.Background(), provider, time.Hour)
+	if provider.fetches != 1 {
+		t.Errorf("fetches = %d, want the cached snapshot reused", provider.fetches)
+	}
+
+	// Another process shares the cache file
+	if snapshot, found, _ := NewClient(cachePath).Snapshot(context.Background(), provider, time.Hour); !found || snapshot.Open != 1 || provider.fetches != 1 {
+		t.Errorf("Snapshot() from the file = %+v, %v (%d fetches)", snapshot, found, provider.fetches)
+	}
+
+	// A failure keeps the last snapshot
+	provider.err = errors.New("tracker down")
+	snapshot, found, err = client.Snapshot(context.Background(), provider, 0)
+	if err == nil || !found || snapshot.Open != 1 {
+		t.Errorf("Snapshot() after a failure = %+v, %v, %v", snapshot, found, err)
+	}
+}
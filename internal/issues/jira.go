@@ -0,0 +1,199 @@
+package issues
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/beads"
+)
+
+// jiraFields are the issue fields read from Jira
+const jiraFields = "summary,description,status,priority,parent"
+
+// Jira reads the user's issues from Jira. The current issue is the one whose
+// key the branch names, e.g. ABC-123-fix-login, else the user's most recently
+// updated issue in progress
+//
+// Jira Cloud authenticates with an account email and API token and has
+// replaced the search API; Jira Data Center takes a personal access token
+type Jira struct {
+	URL        string // Site, e.g. https://acme.atlassian.net
+	Email      string // Account email; set for Jira Cloud
+	Token      string
+	Branch     string
+	HTTPClient *http.Client
+}
+
+// jiraIssue is an issue in Jira's REST API
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string      `json:"summary"`
+		Description interface{} `json:"description"` // Wiki markup, or a document in API v3
+		Status      struct {
+			StatusCategory struct {
+				Key string `json:"key"`
+			} `json:"statusCategory"`
+		} `json:"status"`
+		Priority *struct {
+			Name string `json:"name"`
+		} `json:"priority"`
+		Parent *struct {
+			Key    string `json:"key"`
+			Fields struct {
+				Summary string `json:"summary"`
+			} `json:"fields"`
+		} `json:"parent"`
+	} `json:"fields"`
+}
+
+// Name returns the provider name
+func (j Jira) Name() string {
+	return "jira"
+}
+
+// Key implements Provider
+func (j Jira) Key() string {
+	return "jira\x00" + j.URL + "\x00" + j.Branch
+}
+
+// Fetch implements Provider
+func (j Jira) Fetch(ctx context.Context) (Snapshot, error) {
+	if j.URL == "" || j.Token == "" {
+		return Snapshot{}, errors.New("Jira needs a site URL and an API token")
+	}
+
+	var current *jiraIssue
+	if key := BranchKey(j.Branch); key != "" {
+		var issue jiraIssue
+		err := j.get(ctx, "/rest/api/2/issue/"+url.PathEscape(key)+"?fields="+jiraFields, &issue)
+		switch {
+		case errors.Is(err, errNotFound):
+		case err != nil:
+			return Snapshot{}, err
+		default:
+			current = &issue
+		}
+	}
+	if current == nil {
+		found, err := j.search(ctx, `assignee = currentUser() AND statusCategory = "In Progress" ORDER BY updated DESC`)
+		if err != nil {
+			return Snapshot{}, err
+		}
+		current = found
+	}
+
+	var snapshot Snapshot
+	var err error
+	if snapshot.Open, err = j.count(ctx, "assignee = currentUser() AND statusCategory != Done"); err != nil {
+		return snapshot, err
+	}
+	if snapshot.Total, err = j.count(ctx, "assignee = currentUser()"); err != nil {
+		return snapshot, err
+	}
+	if current == nil {
+		return snapshot, nil
+	}
+
+	issue := &Issue{
+		ID:     current.Key,
+		Title:  current.Fields.Summary,
+		Status: jiraStatus(current.Fields.Status.StatusCategory.Key),
+		URL:    strings.TrimRight(j.URL, "/") + "/browse/" + current.Key,
+	}
+	if description, ok := current.Fields.Description.(string); ok {
+		issue.Description = description
+	}
+	if p := current.Fields.Priority; p != nil {
+		issue.Priority = p.Name
+	}
+	if p := current.Fields.Parent; p != nil {
+		parentJQL := fmt.Sprintf("parent = %s", p.Key)
+		epic := &Epic{Title: p.Fields.Summary}
+		if epic.Total, err = j.count(ctx, parentJQL); err != nil {
+			return snapshot, err
+		}
+		if epic.Closed, err = j.count(ctx, parentJQL+" AND statusCategory = Done"); err != nil {
+			return snapshot, err
+		}
+		issue.Epic = epic
+	}
+	snapshot.Current = issue
+	return snapshot, nil
+}
+
+// search returns the first issue the JQL query finds, or nil
+func (j Jira) search(ctx context.Context, jql string) (*jiraIssue, error) {
+	path := "/rest/api/2/search"
+	if j.cloud() {
+		path = "/rest/api/3/search/jql"
+	}
+	var payload struct {
+		Issues []jiraIssue `json:"issues"`
+	}
+	query := url.Values{"jql": {jql}, "maxResults": {"1"}, "fields": {jiraFields}}
+	if err := j.get(ctx, path+"?"+query.Encode(), &payload); err != nil {
+		return nil, err
+	}
+	if len(payload.Issues) == 0 {
+		return nil, nil
+	}
+	return &payload.Issues[0], nil
+}
+
+// count returns how many issues the JQL query finds
+func (j Jira) count(ctx context.Context, jql string) (int, error) {
+	if j.cloud() {
+		var payload struct {
+			Count int `json:"count"`
+		}
+		err := doJSON(ctx, j.HTTPClient, http.MethodPost, j.endpoint("/rest/api/3/search/approximate-count"),
+			j.header(), map[string]string{"jql": jql}, &payload)
+		return payload.Count, err
+	}
+
+	var payload struct {
+		Total int `json:"total"`
+	}
+	query := url.Values{"jql": {jql}, "maxResults": {"0"}}
+	err := j.get(ctx, "/rest/api/2/search?"+query.Encode(), &payload)
+	return payload.Total, err
+}
+
+func (j Jira) get(ctx context.Context, path string, v interface{}) error {
+	return doJSON(ctx, j.HTTPClient, http.MethodGet, j.endpoint(path), j.header(), nil, v)
+}
+
+func (j Jira) endpoint(path string) string {
+	return strings.TrimRight(j.URL, "/") + path
+}
+
+// cloud reports whether the site is Jira Cloud, which takes an email with the token
+func (j Jira) cloud() bool {
+	return j.Email != ""
+}
+
+func (j Jira) header() http.Header {
+	req := &http.Request{Header: http.Header{}}
+	if j.cloud() {
+		req.SetBasicAuth(j.Email, j.Token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+j.Token)
+	}
+	return req.Header
+}
+
+// jiraStatus maps a Jira status category
+func jiraStatus(category string) beads.IssueStatus {
+	switch category {
+	case "indeterminate":
+		return beads.StatusInProgress
+	case "done":
+		return beads.StatusClosed
+	}
+	return beads.StatusOpen // new
+}
@@ -0,0 +1,165 @@
+package issues
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/beads"
+)
+
+// DefaultLinearAPI is Linear's GraphQL endpoint
+const DefaultLinearAPI = "https://api.linear.app/graphql"
+
+// linearQuery reads the issue the branch names, the viewer's most recently
+// updated started issue as a fallback, and the viewer's assigned issues
+const linearQuery = `query($id: String!, $byKey: Boolean!) {
+  issue(id: $id) @include(if: $byKey) { ...fields }
+  viewer {
+    started: assignedIssues(first: 1, orderBy: updatedAt, filter: {state: {type: {eq: "started"}}}) { nodes { ...fields } }
+    open: assignedIssues(first: 250, filter: {state: {type: {nin: ["completed", "canceled"]}}}) { nodes { id } }
+    all: assignedIssues(first: 250) { nodes { id } }
+  }
+}
+fragment fields on Issue {
+  identifier title description url priorityLabel
+  state { type }
+  parent { title children(first: 250) { nodes { state { type } } } }
+}`
+
+// Linear reads the viewer's issues from Linear. The current issue is the one
+// whose identifier the branch names, as Linear's branch names do, e.g.
+// alice/eng-123-fix-login, else the viewer's most recently updated started one
+type Linear struct {
+	API        string // Defaults to DefaultLinearAPI
+	Token      string // Personal API key
+	Branch     string
+	HTTPClient *http.Client
+}
+
+// linearIssue is an issue in Linear's GraphQL schema
+type linearIssue struct {
+	Identifier    string `json:"identifier"`
+	Title         string `json:"title"`
+	Description   string `json:"description"`
+	URL           string `json:"url"`
+	PriorityLabel string `json:"priorityLabel"`
+	State         struct {
+		Type string `json:"type"`
+	} `json:"state"`
+	Parent *struct {
+		Title    string `json:"title"`
+		Children struct {
+			Nodes []struct {
+				State struct {
+					Type string `json:"type"`
+				} `json:"state"`
+			} `json:"nodes"`
+		} `json:"children"`
+	} `json:"parent"`
+}
+
+// Name returns the provider name
+func (l Linear) Name() string {
+	return "linear"
+}
+
+// Key implements Provider
+func (l Linear) Key() string {
+	return "linear\x00" + l.Branch
+}
+
+// Fetch implements Provider
+func (l Linear) Fetch(ctx context.Context) (Snapshot, error) {
+	if l.Token == "" {
+		return Snapshot{}, errors.New("Linear needs an API key")
+	}
+	api := l.API
+	if api == "" {
+		api = DefaultLinearAPI
+	}
+
+	key := BranchKey(l.Branch)
+	request := map[string]interface{}{
+		"query":     linearQuery,
+		"variables": map[string]interface{}{"id": key, "byKey": key != ""},
+	}
+	var response struct {
+		Data *struct {
+			Issue  *linearIssue `json:"issue"`
+			Viewer *struct {
+				Started struct {
+					Nodes []linearIssue `json:"nodes"`
+				} `json:"started"`
+				Open struct {
+					Nodes []struct{} `json:"nodes"`
+				} `json:"open"`
+				All struct {
+					Nodes []struct{} `json:"nodes"`
+				} `json:"all"`
+			} `json:"viewer"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	// Personal API keys are sent as is, without a Bearer prefix
+	header := http.Header{"Authorization": {l.Token}}
+	if err := doJSON(ctx, l.HTTPClient, http.MethodPost, api, header, request, &response); err != nil {
+		return Snapshot{}, err
+	}
+	// A branch naming no issue fails the issue field alone; the viewer's
+	// fields still answer
+	if response.Data == nil || response.Data.Viewer == nil {
+		if len(response.Errors) > 0 {
+			return Snapshot{}, fmt.Errorf("Linear: %s", response.Errors[0].Message)
+		}
+		return Snapshot{}, errors.New("Linear returned no data")
+	}
+
+	viewer := response.Data.Viewer
+	snapshot := Snapshot{Open: len(viewer.Open.Nodes), Total: len(viewer.All.Nodes)}
+	current := response.Data.Issue
+	if current == nil && len(viewer.Started.Nodes) > 0 {
+		current = &viewer.Started.Nodes[0]
+	}
+	if current != nil {
+		snapshot.Current = current.toIssue()
+	}
+	return snapshot, nil
+}
+
+// toIssue maps a Linear issue, with its parent as the epic
+func (i *linearIssue) toIssue() *Issue {
+	issue := &Issue{
+		ID:          i.Identifier,
+		Title:       i.Title,
+		Description: i.Description,
+		Status:      linearStatus(i.State.Type),
+		URL:         i.URL,
+	}
+	if i.PriorityLabel != "No priority" {
+		issue.Priority = i.PriorityLabel
+	}
+	if p := i.Parent; p != nil {
+		issue.Epic = &Epic{Title: p.Title, Total: len(p.Children.Nodes)}
+		for _, child := range p.Children.Nodes {
+			if linearStatus(child.State.Type) == beads.StatusClosed {
+				issue.Epic.Closed++
+			}
+		}
+	}
+	return issue
+}
+
+// linearStatus maps a Linear workflow state type
+func linearStatus(stateType string) beads.IssueStatus {
+	switch stateType {
+	case "started":
+		return beads.StatusInProgress
+	case "completed", "canceled":
+		return beads.StatusClosed
+	}
+	return beads.StatusOpen // backlog, unstarted, triage
+}
@@ -2,7 +2,6 @@ package mcp
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -26,9 +25,14 @@ const (
 // MCPServer represents an MCP server configuration
 type MCPServer struct {
 	Name     string                 `json:"name"`
+	Scope    string                 `json:"scope,omitempty"`  // Config scope the server was read from, e.g. ScopeProject
+	Source   string                 `json:"source,omitempty"` // File the server was read from
+	Type     string                 `json:"type,omitempty"`   // "stdio" (default), "http" or "sse"
 	Command  string                 `json:"command"`
 	Args     []string               `json:"args"`
 	Env      map[string]string      `json:"env,omitempty"`
+	URL      string                 `json:"url,omitempty"`     // Remote servers
+	Headers  map[string]string      `json:"headers,omitempty"` // Sent with every request to URL
 	Disabled bool                   `json:"disabled,omitempty"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
@@ -46,7 +50,10 @@ type Client struct {
 	mu            sync.RWMutex
 	configPath    string
 	pluginsDir    string
+	managedPath   string // Enterprise managed-mcp.json
+	projectDir    string // Project whose .mcp.json and local servers apply; "" uses the working directory
 	servers       map[string]*MCPServer
+	inventory     *InventoryCache // Optional source of the servers' tool lists
 	enabled       bool
 	timeout       time.Duration
 	lastQueryTime time.Time
@@ -66,17 +73,20 @@ func NewClient() *Client {
 	}
 
 	return &Client{
-		configPath: filepath.Join(homeDir, ClaudeConfigFile),
-		pluginsDir: filepath.Join(homeDir, ClaudePluginsDir),
-		servers:    make(map[string]*MCPServer),
-		enabled:    true,
-		timeout:    DefaultTimeout,
-		queryCache: make(map[string]*MCPData),
-		cacheTTL:   5 * time.Second,
+		configPath:  filepath.Join(homeDir, ClaudeConfigFile),
+		pluginsDir:  filepath.Join(homeDir, ClaudePluginsDir),
+		managedPath: managedConfigPath(),
+		servers:     make(map[string]*MCPServer),
+		enabled:     true,
+		timeout:     DefaultTimeout,
+		queryCache:  make(map[string]*MCPData),
+		cacheTTL:    5 * time.Second,
 	}
 }
 
-// DetectServers detects MCP servers from Claude config and plugins
+// DetectServers detects MCP servers from every Claude Code config scope
+// Servers from valid files are detected even when another file fails to
+// parse; the parse errors are returned together
 func (c *Client) DetectServers(ctx context.Context) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -86,117 +96,10 @@ func (c *Client) DetectServers(ctx context.Context) error {
 	}
 
 	c.servers = make(map[string]*MCPServer)
-
-	// Load global MCP servers from ~/.claude.json
-	c.loadGlobalServers()
-
-	// Load plugin MCP servers from installed plugin .mcp.json files
-	c.loadPluginServers()
+	err := c.loadScopes()
 
 	errors.Info("mcp", "detected %d MCP servers", len(c.servers))
-	return nil
-}
-
-// loadGlobalServers loads MCP servers from ~/.claude.json
-func (c *Client) loadGlobalServers() {
-	if _, err := os.Stat(c.configPath); os.IsNotExist(err) {
-		errors.Debug("mcp", "Claude config not found at %s", c.configPath)
-		return
-	}
-
-	data, err := os.ReadFile(c.configPath)
-	if err != nil {
-		errors.Warn("mcp", "failed to read config file: %v", err)
-		return
-	}
-
-	var config struct {
-		MCPServers map[string]json.RawMessage `json:"mcpServers"`
-	}
-
-	if err := json.Unmarshal(data, &config); err != nil {
-		errors.Warn("mcp", "failed to parse config file: %v", err)
-		return
-	}
-
-	for name, serverData := range config.MCPServers {
-		var server MCPServer
-		if err := json.Unmarshal(serverData, &server); err != nil {
-			errors.Warn("mcp", "failed to parse server %s: %v", name, err)
-			continue
-		}
-		server.Name = name
-		if !server.Disabled {
-			c.servers[name] = &server
-		}
-	}
-}
-
-// loadPluginServers loads MCP servers from installed plugin .mcp.json files
-func (c *Client) loadPluginServers() {
-	if c.pluginsDir == "" {
-		return
-	}
-
-	installedPath := filepath.Join(c.pluginsDir, "installed_plugins.json")
-	data, err := os.ReadFile(installedPath)
-	if err != nil {
-		errors.Debug("mcp", "failed to read installed_plugins.json: %v", err)
-		return
-	}
-
-	var installed struct {
-		Plugins map[string][]struct {
-			InstallPath string `json:"installPath"`
-		} `json:"plugins"`
-	}
-
-	if err := json.Unmarshal(data, &installed); err != nil {
-		errors.Debug("mcp", "failed to parse installed_plugins.json: %v", err)
-		return
-	}
-
-	seen := make(map[string]bool)
-	for _, installs := range installed.Plugins {
-		for _, inst := range installs {
-			if inst.InstallPath == "" || seen[inst.InstallPath] {
-				continue
-			}
-			seen[inst.InstallPath] = true
-			c.loadMCPFromPlugin(inst.InstallPath)
-		}
-	}
-}
-
-// loadMCPFromPlugin loads MCP servers from a plugin's .mcp.json file
-func (c *Client) loadMCPFromPlugin(installPath string) {
-	mcpPath := filepath.Join(installPath, ".mcp.json")
-	data, err := os.ReadFile(mcpPath)
-	if err != nil {
-		return
-	}
-
-	var mcpConfig struct {
-		MCPServers map[string]json.RawMessage `json:"mcpServers"`
-	}
-
-	if err := json.Unmarshal(data, &mcpConfig); err != nil {
-		return
-	}
-
-	for name, serverData := range mcpConfig.MCPServers {
-		if _, exists := c.servers[name]; exists {
-			continue
-		}
-		var server MCPServer
-		if err := json.Unmarshal(serverData, &server); err != nil {
-			continue
-		}
-		server.Name = name
-		if !server.Disabled {
-			c.servers[name] = &server
-		}
-	}
+	return err
 }
 
 // GetServers returns the list of detected MCP servers
@@ -238,14 +141,10 @@ func (c *Client) QueryAll(ctx context.Context) []*MCPData {
 	return results
 }
 
-// queryServer queries a single MCP server
+// queryServer returns what is known about a single MCP server
+// Servers are not started here; their tools, resources and prompts come from
+// the inventory cache, which `claude-hud mcp tools` and the daemon refresh
 func (c *Client) queryServer(ctx context.Context, server *MCPServer) *MCPData {
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
-	// For now, return a placeholder since we can't actually run MCP commands
-	// In a real implementation, this would execute the server command and parse output
 	data := &MCPData{
 		ServerName: server.Name,
 		Data: map[string]interface{}{
@@ -256,6 +155,17 @@ func (c *Client) queryServer(ctx context.Context, server *MCPServer) *MCPData {
 		Timestamp: time.Now(),
 	}
 
+	if c.inventory == nil {
+		return data
+	}
+	if inv, ok := c.inventory.Inventories()[server.Name]; ok {
+		data.Data["status"] = "listed"
+		data.Data["tools"] = inv.Tools
+		data.Data["resources"] = inv.Resources
+		data.Data["prompts"] = inv.Prompts
+		data.Data["fetched_at"] = inv.FetchedAt
+		data.Error = inv.Error
+	}
 	return data
 }
 
@@ -287,6 +197,35 @@ func (c *Client) SetEnabled(enabled bool) {
 	c.enabled = enabled
 }
 
+// SetProjectDir sets the project whose .mcp.json and local-scope servers are
+// detected; by default the working directory is used
+func (c *Client) SetProjectDir(dir string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.projectDir = dir
+}
+
+// SetInventory sets the cache QueryAll and ToolCount read tool lists from
+func (c *Client) SetInventory(inventory *InventoryCache) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inventory = inventory
+}
+
+// ToolCount returns how many tools the detected servers offer according to
+// the inventory cache; 0 until an inventory is set and has been refreshed
+func (c *Client) ToolCount() int {
+	c.mu.RLock()
+	inventory := c.inventory
+	c.mu.RUnlock()
+
+	if inventory == nil {
+		return 0
+	}
+	count, _ := inventory.ToolCount(c.GetServers())
+	return count
+}
+
 // SetTimeout sets the query timeout
 func (c *Client) SetTimeout(timeout time.Duration) {
 	c.mu.Lock()
@@ -0,0 +1,212 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/filecache"
+)
+
+// DefaultInventoryTTL is how long a server's tool lists are reused before
+// the server is started again to refresh them
+const DefaultInventoryTTL = time.Hour
+
+// maxListPages bounds pagination through tools/list and friends, in case a
+// server keeps returning a cursor
+const maxListPages = 20
+
+// Inventory is what one server offers: the names of its tools, resources and prompts
+type Inventory struct {
+	Server    string    `json:"server"`
+	Tools     []string  `json:"tools,omitempty"`
+	Resources []string  `json:"resources,omitempty"`
+	Prompts   []string  `json:"prompts,omitempty"`
+	Error     string    `json:"error,omitempty"` // Last fetch failure; the lists are from the last success
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// InventoryCache lists what MCP servers offer and keeps the lists on disk
+//
+// Like probes, fetching spawns servers, so it runs from `claude-hud mcp tools`
+// or the daemon and never while rendering. Entries younger than TTL are not
+// fetched again
+type InventoryCache struct {
+	TTL       time.Duration
+	Timeout   time.Duration // Per-server limit for connecting and listing
+	CachePath string        // Optional file sharing inventories across processes
+
+	mu      sync.Mutex
+	entries map[string]Inventory
+}
+
+// NewInventoryCache creates a cache persisting inventories at cachePath ("" keeps them in memory)
+func NewInventoryCache(cachePath string) *InventoryCache {
+	return &InventoryCache{
+		TTL:       DefaultInventoryTTL,
+		Timeout:   10 * time.Second,
+		CachePath: cachePath,
+	}
+}
+
+// Fetch connects to a server and lists its tools, resources and prompts
+// Only the lists the server advertised in its capabilities are requested
+func (c *InventoryCache) Fetch(ctx context.Context, server *MCPServer) (Inventory, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	inv := Inventory{Server: server.Name, FetchedAt: time.Now()}
+	session, err := Connect(ctx, server)
+	if err != nil {
+		return inv, err
+	}
+	defer session.Close()
+
+	if session.Capabilities.Tools {
+		if inv.Tools, err = listNames(ctx, session, "tools/list", "tools", "name"); err != nil {
+			return inv, err
+		}
+	}
+	if session.Capabilities.Resources {
+		if inv.Resources, err = listNames(ctx, session, "resources/list", "resources", "uri"); err != nil {
+			return inv, err
+		}
+	}
+	if session.Capabilities.Prompts {
+		if inv.Prompts, err = listNames(ctx, session, "prompts/list", "prompts", "name"); err != nil {
+			return inv, err
+		}
+	}
+	return inv, nil
+}
+
+// listNames pages through a list method, collecting the key field of each item
+func listNames(ctx context.Context, session *Session, method, field, key string) ([]string, error) {
+	var names []string
+	var cursor string
+	for page := 0; page < maxListPages; page++ {
+		var params interface{}
+		if cursor != "" {
+			params = map[string]string{"cursor": cursor}
+		}
+		var result map[string]json.RawMessage
+		if err := session.Call(ctx, method, params, &result); err != nil {
+			return nil, err
+		}
+
+		var items []map[string]interface{}
+		if raw, ok := result[field]; ok {
+			if err := json.Unmarshal(raw, &items); err != nil {
+				return nil, err
+			}
+		}
+		for _, item := range items {
+			if name, ok := item[key].(string); ok {
+				names = append(names, name)
+			}
+		}
+
+		cursor = ""
+		if raw, ok := result["nextCursor"]; ok {
+			_ = json.Unmarshal(raw, &cursor)
+		}
+		if cursor == "" {
+			break
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Refresh fetches the inventory of every server whose entry is missing or
+// older than TTL (all of them when force is set), drops servers that are no
+// longer configured and saves the result. A failed fetch keeps the previous
+// lists and records the error
+func (c *InventoryCache) Refresh(ctx context.Context, servers []*MCPServer, force bool) map[string]Inventory {
+	current := c.Inventories()
+
+	var stale []*MCPServer
+	for _, server := range servers {
+		inv, ok := current[server.Name]
+		if force || !ok || time.Since(inv.FetchedAt) >= c.TTL {
+			stale = append(stale, server)
+		}
+	}
+
+	fetched := make([]Inventory, len(stale))
+	sem := make(chan struct{}, maxConcurrentProbes)
+	var wg sync.WaitGroup
+	for i, server := range stale {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			inv, err := c.Fetch(ctx, server)
+			if err != nil {
+				previous := current[server.Name]
+				inv.Tools, inv.Resources, inv.Prompts = previous.Tools, previous.Resources, previous.Prompts
+				inv.Error = err.Error()
+			}
+			fetched[i] = inv
+		}()
+	}
+	wg.Wait()
+
+	entries := make(map[string]Inventory, len(servers))
+	for _, server := range servers {
+		if inv, ok := current[server.Name]; ok {
+			entries[server.Name] = inv
+		}
+	}
+	for _, inv := range fetched {
+		entries[inv.Server] = inv
+	}
+
+	c.mu.Lock()
+	c.entries = entries
+	c.writeCache(entries)
+	c.mu.Unlock()
+	return entries
+}
+
+// Inventories returns the saved inventories keyed by server name
+// Entries are re-read from CachePath, so fetches by another process show up
+func (c *InventoryCache) Inventories() map[string]Inventory {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached := c.readCache(); cached != nil {
+		c.entries = cached
+	}
+	return c.entries
+}
+
+// ToolCount returns how many tools the given servers offer, according to the
+// saved inventories, and whether any of them has been listed yet
+func (c *InventoryCache) ToolCount(servers []*MCPServer) (int, bool) {
+	entries := c.Inventories()
+	count, known := 0, false
+	for _, server := range servers {
+		if inv, ok := entries[server.Name]; ok {
+			count += len(inv.Tools)
+			known = true
+		}
+	}
+	return count, known
+}
+
+func (c *InventoryCache) readCache() map[string]Inventory {
+	var entries map[string]Inventory
+	if !filecache.Load(c.CachePath, &entries) {
+		return nil
+	}
+	return entries
+}
+
+func (c *InventoryCache) writeCache(entries map[string]Inventory) {
+	_ = filecache.Save(c.CachePath, entries)
+}
@@ -0,0 +1,108 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeInventoryServer offers tools over two pages and one prompt, but no resources
+const fakeInventoryServer = `read line
+printf '{"jsonrpc":"2.0","id":1,"result":{"serverInfo":{"name":"fake"},"capabilities":{"tools":{},"prompts":{}}}}\n'
+read line
+read line
+printf '{"jsonrpc":"2.0","id":2,"result":{"tools":[{"name":"search"},{"name":"fetch"}],"nextCursor":"page2"}}\n'
+read line
+printf '{"jsonrpc":"2.0","id":3,"result":{"tools":[{"name":"create"}]}}\n'
+read line
+printf '{"jsonrpc":"2.0","id":4,"result":{"prompts":[{"name":"review"}]}}\n'
+cat >/dev/null`
+
+func TestInventoryCache_Fetch(t *testing.T) {
+	server := &MCPServer{Name: "fake", Command: "sh", Args: []string{"-c", fakeInventoryServer}}
+
+	cache := NewInventoryCache("")
+	cache.Timeout = 5 * time.Second
+	inv, err := cache.Fetch(context.Background(), server)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if want := []string{"create", "fetch", "search"}; !reflect.DeepEqual(inv.Tools, want) {
+		t.Errorf("Tools = %v, want %v", inv.Tools, want)
+	}
+	if want := []string{"review"}; !reflect.DeepEqual(inv.Prompts, want) {
+		t.Errorf("Prompts = %v, want %v", inv.Prompts, want)
+	}
+	if len(inv.Resources) != 0 {
+		t.Errorf("Resources = %v, want none", inv.Resources)
+	}
+}
+
+func TestInventoryCache_Refresh(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "mcptools.json")
+	working := &MCPServer{Name: "fake", Command: "sh", Args: []string{"-c", fakeInventoryServer}}
+
+	cache := NewInventoryCache(cachePath)
+	cache.Timeout = 5 * time.Second
+	entries := cache.Refresh(context.Background(), []*MCPServer{working}, false)
+	if len(entries["fake"].Tools) != 3 {
+		t.Fatalf("expected 3 tools after the first refresh, got %+v", entries["fake"])
+	}
+
+	// The same name now points at a broken server: fresh entries are not refetched
+	broken := &MCPServer{Name: "fake", Command: filepath.Join(t.TempDir(), "no-such-server")}
+	entries = cache.Refresh(context.Background(), []*MCPServer{broken}, false)
+	if entries["fake"].Error != "" {
+		t.Errorf("fresh entry was refetched: %+v", entries["fake"])
+	}
+
+	// Forced refreshes refetch; a failure keeps the previous lists
+	entries = cache.Refresh(context.Background(), []*MCPServer{broken}, true)
+	if entries["fake"].Error == "" || len(entries["fake"].Tools) != 3 {
+		t.Errorf("expected the error with the previous tools, got %+v", entries["fake"])
+	}
+
+	// Another process reads the saved inventories; removed servers are dropped
+	other := NewInventoryCache(cachePath)
+	if count, known := other.ToolCount([]*MCPServer{working}); count != 3 || !known {
+		t.Errorf("ToolCount() = %d, %v, want 3, true", count, known)
+	}
+	if entries := other.Refresh(context.Background(), nil, false); len(entries) != 0 {
+		t.Errorf("expected removed servers to be dropped, got %+v", entries)
+	}
+}
+
+func TestClient_ToolCount(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".claude.json"),
+		`{"mcpServers": {"github": {"command": "gh-mcp"}, "fs": {"command": "fs-mcp"}}}`)
+	cachePath := filepath.Join(dir, "mcptools.json")
+	writeFile(t, cachePath, `{
+		"github": {"server": "github", "tools": ["create_issue", "search"], "fetched_at": "2026-01-01T00:00:00Z"},
+		"removed": {"server": "removed", "tools": ["stale"], "fetched_at": "2026-01-01T00:00:00Z"}
+	}`)
+
+	client := newScopedClient(dir)
+	ctx := context.Background()
+	if err := client.DetectServers(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := client.ToolCount(); got != 0 {
+		t.Errorf("ToolCount() without inventory = %d, want 0", got)
+	}
+
+	client.SetInventory(NewInventoryCache(cachePath))
+	if got := client.ToolCount(); got != 2 {
+		t.Errorf("ToolCount() = %d, want 2", got)
+	}
+
+	data, err := client.QueryServer(ctx, "github")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Data["status"] != "listed" || len(data.Data["tools"].([]string)) != 2 {
+		t.Errorf("unexpected query data: %+v", data.Data)
+	}
+}
@@ -0,0 +1,148 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/filecache"
+)
+
+// ProbeResult is the outcome of one server health check
+type ProbeResult struct {
+	Server    string        `json:"server"`
+	OK        bool          `json:"ok"`
+	Latency   time.Duration `json:"latency"` // From spawning or contacting the server to its initialize response
+	Error     string        `json:"error,omitempty"`
+	CheckedAt time.Time     `json:"checked_at"`
+}
+
+// maxConcurrentProbes bounds how many servers are spawned at once
+const maxConcurrentProbes = 4
+
+// Prober health-checks MCP servers by running the initialize handshake
+//
+// Probes spawn processes and can take seconds, so they run on demand
+// (`claude-hud mcp probe`) or periodically in the daemon, never while
+// rendering. Results are written to CachePath for the statusline to read
+type Prober struct {
+	Timeout   time.Duration // Per-server limit for the whole handshake
+	CachePath string        // Optional file sharing results across processes
+
+	mu      sync.Mutex
+	results map[string]ProbeResult
+}
+
+// NewProber creates a prober persisting results at cachePath ("" keeps them in memory)
+func NewProber(cachePath string) *Prober {
+	return &Prober{
+		Timeout:   10 * time.Second,
+		CachePath: cachePath,
+	}
+}
+
+// Probe checks one server: it spawns or contacts the server, completes the
+// initialize handshake, measures how long that took and shuts it down again
+func (p *Prober) Probe(ctx context.Context, server *MCPServer) ProbeResult {
+	ctx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	var err error
+	if server.Type == "sse" {
+		// The legacy SSE transport answers on a separate stream; reaching
+		// the endpoint is the best cheap signal
+		err = probeEventStream(ctx, server)
+	} else {
+		var session *Session
+		if session, err = Connect(ctx, server); err == nil {
+			session.Close()
+		}
+	}
+
+	result := ProbeResult{Server: server.Name, OK: err == nil, Latency: time.Since(start), CheckedAt: time.Now()}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// probeEventStream checks that an SSE endpoint accepts a stream
+func probeEventStream(ctx context.Context, server *MCPServer) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	for key, value := range server.Headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// ProbeAll checks servers concurrently and saves the results, replacing
+// those of servers that are no longer configured. Results are sorted by name
+func (p *Prober) ProbeAll(ctx context.Context, servers []*MCPServer) []ProbeResult {
+	results := make([]ProbeResult, len(servers))
+	sem := make(chan struct{}, maxConcurrentProbes)
+	var wg sync.WaitGroup
+	for i, server := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = p.Probe(ctx, server)
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Server < results[j].Server
+	})
+
+	byName := make(map[string]ProbeResult, len(results))
+	for _, result := range results {
+		byName[result.Server] = result
+	}
+	p.mu.Lock()
+	p.results = byName
+	p.writeCache(byName)
+	p.mu.Unlock()
+	return results
+}
+
+// Results returns the last saved results keyed by server name
+// Results are re-read from CachePath, so probes run by another process show up
+func (p *Prober) Results() map[string]ProbeResult {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if cached := p.readCache(); cached != nil {
+		p.results = cached
+	}
+	return p.results
+}
+
+func (p *Prober) readCache() map[string]ProbeResult {
+	var results map[string]ProbeResult
+	if !filecache.Load(p.CachePath, &results) {
+		return nil
+	}
+	return results
+}
+
+func (p *Prober) writeCache(results map[string]ProbeResult) {
+	_ = filecache.Save(p.CachePath, results)
+}
@@ -0,0 +1,152 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeStdioServer answers initialize after printing a log line, then waits for stdin to close
+const fakeStdioServer = `read line
+echo "starting fake server"
+printf '{"jsonrpc":"2.0","id":1,"result":{"protocolVersion":"2025-03-26","serverInfo":{"name":"fake"}}}\n'
+cat >/dev/null`
+
+func TestConnect_Stdio(t *testing.T) {
+	server := &MCPServer{Name: "fake", Command: "sh", Args: []string{"-c", fakeStdioServer}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session, err := Connect(ctx, server)
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if session.ServerName != "fake" {
+		t.Errorf("ServerName = %q, want %q", session.ServerName, "fake")
+	}
+	if err := session.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
+
+func TestConnect_StdioExitsEarly(t *testing.T) {
+	server := &MCPServer{Name: "broken", Command: "sh", Args: []string{"-c", "exit 1"}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := Connect(ctx, server); err == nil {
+		t.Error("expected error for server that exits before responding")
+	}
+}
+
+// newHTTPServer returns a streamable HTTP server answering initialize, as JSON or an event stream
+func newHTTPServer(t *testing.T, eventStream bool) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			return
+		}
+		var req rpcRequest
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if req.ID == 0 {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Header().Set("Mcp-Session-Id", "abc")
+		response := fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":{"serverInfo":{"name":"remote"}}}`, req.ID)
+		if eventStream {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprintf(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/message\"}\n\ndata: %s\n\n", response)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, response)
+	}))
+}
+
+func TestConnect_HTTP(t *testing.T) {
+	for _, eventStream := range []bool{false, true} {
+		t.Run(fmt.Sprintf("event stream %v", eventStream), func(t *testing.T) {
+			ts := newHTTPServer(t, eventStream)
+			defer ts.Close()
+
+			server := &MCPServer{Name: "remote", Type: "http", URL: ts.URL, Headers: map[string]string{"Authorization": "Bearer token"}}
+			session, err := Connect(context.Background(), server)
+			if err != nil {
+				t.Fatalf("Connect() error = %v", err)
+			}
+			defer session.Close()
+			if session.ServerName != "remote" {
+				t.Errorf("ServerName = %q, want %q", session.ServerName, "remote")
+			}
+		})
+	}
+}
+
+func TestReadEventStream_UnterminatedEvent(t *testing.T) {
+	body := strings.NewReader(`data: {"jsonrpc":"2.0","id":3,"result":{}}`)
+	resp, err := readEventStream(body, 3)
+	if err != nil {
+		t.Fatalf("readEventStream() error = %v", err)
+	}
+	if !resp.matches(3) {
+		t.Errorf("unexpected response id %s", resp.ID)
+	}
+}
+
+func TestProber_ProbeAll(t *testing.T) {
+	ts := newHTTPServer(t, false)
+	defer ts.Close()
+
+	servers := []*MCPServer{
+		{Name: "stdio", Command: "sh", Args: []string{"-c", fakeStdioServer}},
+		{Name: "remote", URL: ts.URL, Headers: map[string]string{"Authorization": "Bearer token"}},
+		{Name: "unauthorized", URL: ts.URL},
+		{Name: "missing", Command: filepath.Join(t.TempDir(), "no-such-server")},
+	}
+
+	cachePath := filepath.Join(t.TempDir(), "mcphealth.json")
+	prober := NewProber(cachePath)
+	prober.Timeout = 5 * time.Second
+	results := prober.ProbeAll(context.Background(), servers)
+
+	if len(results) != len(servers) {
+		t.Fatalf("expected %d results, got %d", len(servers), len(results))
+	}
+	want := map[string]bool{"stdio": true, "remote": true, "unauthorized": false, "missing": false}
+	for _, result := range results {
+		if result.OK != want[result.Server] {
+			t.Errorf("%s: OK = %v, want %v (error %q)", result.Server, result.OK, want[result.Server], result.Error)
+		}
+		if !result.OK && result.Error == "" {
+			t.Errorf("%s: expected an error message", result.Server)
+		}
+		if result.CheckedAt.IsZero() {
+			t.Errorf("%s: CheckedAt not set", result.Server)
+		}
+	}
+	if results[0].Server != "missing" {
+		t.Errorf("expected results sorted by name, got %s first", results[0].Server)
+	}
+
+	// Another process reads the saved results
+	cached := NewProber(cachePath).Results()
+	if len(cached) != len(servers) || !cached["stdio"].OK || cached["stdio"].Latency <= 0 {
+		t.Errorf("unexpected cached results: %+v", cached)
+	}
+}
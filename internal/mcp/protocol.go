@@ -0,0 +1,346 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/version"
+)
+
+// ProtocolVersion is the MCP protocol revision requested during initialize
+const ProtocolVersion = "2025-03-26"
+
+// maxMessageSize bounds a single JSON-RPC message read from a server
+const maxMessageSize = 4 << 20
+
+// shutdownGrace is how long a stdio server gets to exit after stdin closes
+const shutdownGrace = time.Second
+
+// rpcRequest is a JSON-RPC request, or a notification when ID is zero
+type rpcRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      int         `json:"id,omitempty"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// rpcResponse is a JSON-RPC response; messages without an ID are server
+// notifications or requests and are skipped
+type rpcResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// matches reports whether the response answers request id
+func (r *rpcResponse) matches(id int) bool {
+	return strings.TrimSpace(string(r.ID)) == fmt.Sprint(id)
+}
+
+// transport carries JSON-RPC messages to one server
+type transport interface {
+	// roundTrip sends req and waits for its response; notifications return nil
+	roundTrip(ctx context.Context, req rpcRequest) (*rpcResponse, error)
+	close() error
+}
+
+// Session is an initialized connection to an MCP server
+// A session is not safe for concurrent use
+type Session struct {
+	transport    transport
+	nextID       int
+	ServerName   string       // serverInfo.name reported by the server
+	Capabilities Capabilities // Features the server advertised during initialize
+}
+
+// Capabilities records which optional features a server offers
+type Capabilities struct {
+	Tools     bool
+	Resources bool
+	Prompts   bool
+}
+
+// Connect starts or contacts a server and performs the initialize handshake
+// Stdio servers are spawned and stopped again by Close; servers with a URL
+// are reached over streamable HTTP
+func Connect(ctx context.Context, server *MCPServer) (*Session, error) {
+	var t transport
+	var err error
+	switch {
+	case server.URL != "":
+		t = newHTTPTransport(server)
+	case server.Command != "":
+		t, err = startStdio(ctx, server)
+	default:
+		err = fmt.Errorf("server %s has neither a command nor a url", server.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Session{transport: t}
+	var initResult struct {
+		ServerInfo struct {
+			Name string `json:"name"`
+		} `json:"serverInfo"`
+		Capabilities struct {
+			Tools     json.RawMessage `json:"tools"`
+			Resources json.RawMessage `json:"resources"`
+			Prompts   json.RawMessage `json:"prompts"`
+		} `json:"capabilities"`
+	}
+	params := map[string]interface{}{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]interface{}{},
+		"clientInfo":      map[string]string{"name": "claude-hud", "version": version.Version},
+	}
+	if err := s.Call(ctx, "initialize", params, &initResult); err != nil {
+		t.close()
+		return nil, fmt.Errorf("initialize: %w", err)
+	}
+	s.ServerName = initResult.ServerInfo.Name
+	s.Capabilities = Capabilities{
+		Tools:     len(initResult.Capabilities.Tools) > 0,
+		Resources: len(initResult.Capabilities.Resources) > 0,
+		Prompts:   len(initResult.Capabilities.Prompts) > 0,
+	}
+
+	if _, err := t.roundTrip(ctx, rpcRequest{JSONRPC: "2.0", Method: "notifications/initialized"}); err != nil {
+		t.close()
+		return nil, fmt.Errorf("initialized: %w", err)
+	}
+	return s, nil
+}
+
+// Call sends a request and decodes its result into result (which may be nil)
+func (s *Session) Call(ctx context.Context, method string, params, result interface{}) error {
+	s.nextID++
+	resp, err := s.transport.roundTrip(ctx, rpcRequest{JSONRPC: "2.0", ID: s.nextID, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if result == nil || len(resp.Result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("failed to parse %s result: %w", method, err)
+	}
+	return nil
+}
+
+// Close shuts the session down, stopping a spawned server
+func (s *Session) Close() error {
+	return s.transport.close()
+}
+
+// stdioTransport speaks newline-delimited JSON-RPC over a child's stdin/stdout
+type stdioTransport struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	lines *bufio.Scanner
+}
+
+// startStdio spawns a stdio server; it is killed when ctx ends
+func startStdio(ctx context.Context, server *MCPServer) (*stdioTransport, error) {
+	cmd := exec.CommandContext(ctx, server.Command, server.Args...)
+	cmd.Env = os.Environ()
+	for key, value := range server.Env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+	// Children such as `npx` wrappers may keep the pipes open after the
+	// server exits; don't wait on them forever
+	cmd.WaitDelay = shutdownGrace
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", server.Command, err)
+	}
+
+	lines := bufio.NewScanner(stdout)
+	lines.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+	return &stdioTransport{cmd: cmd, stdin: stdin, lines: lines}, nil
+}
+
+func (t *stdioTransport) roundTrip(ctx context.Context, req rpcRequest) (*rpcResponse, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := t.stdin.Write(append(data, '\n')); err != nil {
+		return nil, fmt.Errorf("failed to write request: %w", err)
+	}
+	if req.ID == 0 {
+		return nil, nil
+	}
+
+	// Reads end when ctx kills the process and stdout closes
+	for t.lines.Scan() {
+		var resp rpcResponse
+		if json.Unmarshal(t.lines.Bytes(), &resp) != nil || !resp.matches(req.ID) {
+			continue // Logging on stdout, notifications or server requests
+		}
+		return &resp, nil
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err := t.lines.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return nil, fmt.Errorf("server exited before responding")
+}
+
+// close asks the server to exit by closing stdin, killing it after a grace period
+func (t *stdioTransport) close() error {
+	t.stdin.Close()
+	done := make(chan struct{})
+	go func() {
+		_ = t.cmd.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(shutdownGrace):
+		_ = t.cmd.Process.Kill()
+		<-done
+	}
+	return nil
+}
+
+// httpTransport speaks streamable HTTP: each message is POSTed, and the
+// response is either JSON or a server-sent event stream
+type httpTransport struct {
+	url       string
+	headers   map[string]string
+	client    *http.Client
+	sessionID string // Mcp-Session-Id assigned during initialize
+}
+
+func newHTTPTransport(server *MCPServer) *httpTransport {
+	return &httpTransport{url: server.URL, headers: server.Headers, client: &http.Client{}}
+}
+
+func (t *httpTransport) roundTrip(ctx context.Context, req rpcRequest) (*rpcResponse, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json, text/event-stream")
+	for key, value := range t.headers {
+		httpReq.Header.Set(key, value)
+	}
+	if t.sessionID != "" {
+		httpReq.Header.Set("Mcp-Session-Id", t.sessionID)
+	}
+
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	if id := resp.Header.Get("Mcp-Session-Id"); id != "" {
+		t.sessionID = id
+	}
+	if req.ID == 0 {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return readEventStream(resp.Body, req.ID)
+	}
+	var rpcResp rpcResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxMessageSize)).Decode(&rpcResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &rpcResp, nil
+}
+
+// readEventStream returns the response to id from a server-sent event stream
+func readEventStream(body io.Reader, id int) (*rpcResponse, error) {
+	lines := bufio.NewScanner(body)
+	lines.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+
+	var data strings.Builder
+	for {
+		more := lines.Scan()
+		line := lines.Text()
+		if payload, ok := strings.CutPrefix(line, "data:"); ok && more {
+			data.WriteString(strings.TrimPrefix(payload, " "))
+			continue
+		}
+		// A blank line or the end of the stream ends the event
+		if (line == "" || !more) && data.Len() > 0 {
+			var resp rpcResponse
+			if json.Unmarshal([]byte(data.String()), &resp) == nil && resp.matches(id) {
+				return &resp, nil
+			}
+			data.Reset()
+		}
+		if !more {
+			break
+		}
+	}
+	if err := lines.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read event stream: %w", err)
+	}
+	return nil, fmt.Errorf("event stream ended before responding")
+}
+
+// close ends the server-side session, if the server assigned one
+func (t *httpTransport) close() error {
+	if t.sessionID == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, t.url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Mcp-Session-Id", t.sessionID)
+	for key, value := range t.headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
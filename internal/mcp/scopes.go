@@ -0,0 +1,196 @@
+package mcp
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+)
+
+// Config scopes, in the order Claude Code gives them precedence
+const (
+	ScopeManaged = "managed" // Enterprise managed-mcp.json
+	ScopeLocal   = "local"   // ~/.claude.json, under the project's path
+	ScopeProject = "project" // .mcp.json in the project directory
+	ScopeUser    = "user"    // ~/.claude.json, top level
+	ScopePlugin  = "plugin"  // .mcp.json of an installed plugin
+)
+
+// ProjectConfigFile is the project-scoped MCP config, checked into the repository
+const ProjectConfigFile = ".mcp.json"
+
+// managedConfigPath returns where administrators deploy managed-mcp.json
+func managedConfigPath() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "/Library/Application Support/ClaudeCode/managed-mcp.json"
+	case "windows":
+		return `C:\ProgramData\ClaudeCode\managed-mcp.json`
+	default:
+		return "/etc/claude-code/managed-mcp.json"
+	}
+}
+
+// claudeConfig is the part of ~/.claude.json holding MCP servers
+type claudeConfig struct {
+	MCPServers map[string]json.RawMessage `json:"mcpServers"`
+	Projects   map[string]struct {
+		MCPServers             map[string]json.RawMessage `json:"mcpServers"`
+		DisabledMcpjsonServers []string                   `json:"disabledMcpjsonServers"`
+	} `json:"projects"`
+}
+
+// loadScopes fills c.servers from every scope, highest precedence first, so
+// a server defined in several scopes keeps the definition Claude Code uses
+// Callers must hold c.mu
+func (c *Client) loadScopes() error {
+	var errs []error
+	seen := make(map[string]bool) // Includes disabled servers, which still shadow lower scopes
+
+	add := func(scope, source string, servers map[string]json.RawMessage, skip map[string]bool) {
+		for name, data := range servers {
+			if seen[name] || skip[name] {
+				continue
+			}
+			var server MCPServer
+			if err := json.Unmarshal(data, &server); err != nil {
+				errors.Warn("mcp", "failed to parse server %s in %s: %v", name, source, err)
+				continue
+			}
+			seen[name] = true
+			server.Name = name
+			server.Scope = scope
+			server.Source = source
+			if !server.Disabled {
+				c.servers[name] = &server
+			}
+		}
+	}
+
+	// Enterprise managed servers override everything
+	if c.managedPath != "" {
+		var managed struct {
+			MCPServers map[string]json.RawMessage `json:"mcpServers"`
+		}
+		if err := readJSONFile(c.managedPath, &managed); err != nil {
+			errs = append(errs, err)
+		}
+		add(ScopeManaged, c.managedPath, managed.MCPServers, nil)
+	}
+
+	projectDir := c.projectDir
+	if projectDir == "" {
+		projectDir, _ = os.Getwd()
+	}
+
+	// Local and user servers share ~/.claude.json
+	var global claudeConfig
+	if c.configPath != "" {
+		if err := readJSONFile(c.configPath, &global); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	project := global.Projects[projectDir]
+	if projectDir != "" {
+		add(ScopeLocal, c.configPath, project.MCPServers, nil)
+
+		// Project servers the user declined when Claude Code asked are skipped
+		declined := make(map[string]bool, len(project.DisabledMcpjsonServers))
+		for _, name := range project.DisabledMcpjsonServers {
+			declined[name] = true
+		}
+		projectPath := filepath.Join(projectDir, ProjectConfigFile)
+		var projectConfig struct {
+			MCPServers map[string]json.RawMessage `json:"mcpServers"`
+		}
+		if err := readJSONFile(projectPath, &projectConfig); err != nil {
+			errs = append(errs, err)
+		}
+		add(ScopeProject, projectPath, projectConfig.MCPServers, declined)
+	}
+	add(ScopeUser, c.configPath, global.MCPServers, nil)
+
+	// Plugin servers have the lowest precedence
+	for _, installPath := range c.pluginInstallPaths() {
+		mcpPath := filepath.Join(installPath, ProjectConfigFile)
+		var pluginConfig struct {
+			MCPServers map[string]json.RawMessage `json:"mcpServers"`
+		}
+		if err := readJSONFile(mcpPath, &pluginConfig); err != nil {
+			errors.Debug("mcp", "%v", err)
+			continue
+		}
+		add(ScopePlugin, mcpPath, pluginConfig.MCPServers, nil)
+		expandPluginRoot(c.servers, mcpPath, installPath)
+	}
+
+	return stderrors.Join(errs...)
+}
+
+// readJSONFile decodes path into v; a missing file is not an error
+func readJSONFile(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// pluginInstallPaths returns the install directories of installed plugins
+func (c *Client) pluginInstallPaths() []string {
+	if c.pluginsDir == "" {
+		return nil
+	}
+
+	var installed struct {
+		Plugins map[string][]struct {
+			InstallPath string `json:"installPath"`
+		} `json:"plugins"`
+	}
+	installedPath := filepath.Join(c.pluginsDir, "installed_plugins.json")
+	if err := readJSONFile(installedPath, &installed); err != nil {
+		errors.Debug("mcp", "%v", err)
+		return nil
+	}
+
+	var paths []string
+	seen := make(map[string]bool)
+	for _, installs := range installed.Plugins {
+		for _, inst := range installs {
+			if inst.InstallPath == "" || seen[inst.InstallPath] {
+				continue
+			}
+			seen[inst.InstallPath] = true
+			paths = append(paths, inst.InstallPath)
+		}
+	}
+	sort.Strings(paths) // Stable precedence between plugins defining the same server
+	return paths
+}
+
+// expandPluginRoot substitutes ${CLAUDE_PLUGIN_ROOT} in the commands of the
+// servers read from a plugin's .mcp.json, as Claude Code does when starting them
+func expandPluginRoot(servers map[string]*MCPServer, source, installPath string) {
+	const variable = "${CLAUDE_PLUGIN_ROOT}"
+	for _, server := range servers {
+		if server.Source != source {
+			continue
+		}
+		server.Command = strings.ReplaceAll(server.Command, variable, installPath)
+		for i, arg := range server.Args {
+			server.Args[i] = strings.ReplaceAll(arg, variable, installPath)
+		}
+	}
+}
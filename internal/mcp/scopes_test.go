@@ -0,0 +1,136 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFile writes content to path, creating parent directories
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newScopedClient returns a client reading every scope from under dir
+func newScopedClient(dir string) *Client {
+	client := NewClient()
+	client.configPath = filepath.Join(dir, ".claude.json")
+	client.pluginsDir = filepath.Join(dir, "plugins")
+	client.managedPath = filepath.Join(dir, "managed-mcp.json")
+	client.SetProjectDir(filepath.Join(dir, "project"))
+	return client
+}
+
+func TestClient_DetectServers_ScopePrecedence(t *testing.T) {
+	dir := t.TempDir()
+	projectDir := filepath.Join(dir, "project")
+
+	writeFile(t, filepath.Join(dir, "managed-mcp.json"),
+		`{"mcpServers": {"shared": {"command": "managed"}}}`)
+	writeFile(t, filepath.Join(dir, ".claude.json"), `{
+		"mcpServers": {
+			"shared": {"command": "user"},
+			"local-and-user": {"command": "user"},
+			"user-only": {"command": "user"}
+		},
+		"projects": {
+			"`+projectDir+`": {
+				"mcpServers": {"local-and-user": {"command": "local"}, "project-and-local": {"command": "local"}},
+				"disabledMcpjsonServers": ["declined"]
+			},
+			"/some/other/project": {"mcpServers": {"elsewhere": {"command": "other"}}}
+		}
+	}`)
+	writeFile(t, filepath.Join(projectDir, ".mcp.json"), `{"mcpServers": {
+		"project-and-local": {"command": "project"},
+		"project-only": {"command": "project"},
+		"declined": {"command": "project"},
+		"user-only": {"command": "project"}
+	}}`)
+
+	pluginPath := filepath.Join(dir, "plugins", "cache", "p", "1.0.0")
+	writeFile(t, filepath.Join(pluginPath, ".mcp.json"), `{"mcpServers": {
+		"user-only": {"command": "plugin"},
+		"plugin-only": {"command": "${CLAUDE_PLUGIN_ROOT}/server", "args": ["--root", "${CLAUDE_PLUGIN_ROOT}"]}
+	}}`)
+	writeFile(t, filepath.Join(dir, "plugins", "installed_plugins.json"),
+		`{"plugins": {"p@test": [{"installPath": "`+pluginPath+`"}]}}`)
+
+	client := newScopedClient(dir)
+	if err := client.DetectServers(context.Background()); err != nil {
+		t.Fatalf("DetectServers() error = %v", err)
+	}
+
+	want := map[string]struct{ command, scope string }{
+		"shared":            {"managed", ScopeManaged},
+		"local-and-user":    {"local", ScopeLocal},
+		"project-and-local": {"local", ScopeLocal},
+		"project-only":      {"project", ScopeProject},
+		"user-only":         {"project", ScopeProject},
+		"plugin-only":       {pluginPath + "/server", ScopePlugin},
+	}
+	servers := make(map[string]*MCPServer)
+	for _, server := range client.GetServers() {
+		servers[server.Name] = server
+	}
+	if len(servers) != len(want) {
+		t.Errorf("expected %d servers, got %d: %v", len(want), len(servers), client.GetServerNames())
+	}
+	for name, w := range want {
+		server, ok := servers[name]
+		if !ok {
+			t.Errorf("server %s not detected", name)
+			continue
+		}
+		if server.Command != w.command || server.Scope != w.scope {
+			t.Errorf("%s: command %q scope %q, want %q %q", name, server.Command, server.Scope, w.command, w.scope)
+		}
+		if server.Source == "" {
+			t.Errorf("%s: source not set", name)
+		}
+	}
+	if got := servers["plugin-only"].Args; len(got) != 2 || got[1] != pluginPath {
+		t.Errorf("plugin root not expanded in args: %v", got)
+	}
+	if got := servers["project-only"].Source; got != filepath.Join(projectDir, ".mcp.json") {
+		t.Errorf("project-only source = %s", got)
+	}
+}
+
+func TestClient_DetectServers_DisabledShadowsLowerScopes(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "project", ".mcp.json"),
+		`{"mcpServers": {"db": {"command": "project", "disabled": true}}}`)
+	writeFile(t, filepath.Join(dir, ".claude.json"),
+		`{"mcpServers": {"db": {"command": "user"}}}`)
+
+	client := newScopedClient(dir)
+	if err := client.DetectServers(context.Background()); err != nil {
+		t.Fatalf("DetectServers() error = %v", err)
+	}
+	if client.ServerCount() != 0 {
+		t.Errorf("expected the disabled project server to hide the user one, got %v", client.GetServerNames())
+	}
+}
+
+func TestClient_DetectServers_PartialFailure(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "project", ".mcp.json"), `{not json`)
+	writeFile(t, filepath.Join(dir, ".claude.json"),
+		`{"mcpServers": {"user-server": {"command": "node"}}}`)
+
+	client := newScopedClient(dir)
+	if err := client.DetectServers(context.Background()); err == nil {
+		t.Error("expected error for invalid project .mcp.json")
+	}
+	if client.ServerCount() != 1 {
+		t.Errorf("expected the user server despite the invalid project file, got %v", client.GetServerNames())
+	}
+}
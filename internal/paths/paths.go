@@ -0,0 +1,15 @@
+// Package paths answers questions about file paths that several packages
+// ask, so they agree on the answer
+package paths
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Within reports whether path is dir or lies inside it. Both are compared
+// as given; neither is resolved against the working directory or symlinks
+func Within(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,24 @@
+package paths
+
+import "testing"
+
+func TestWithin(t *testing.T) {
+	tests := []struct {
+		path, dir string
+		want      bool
+	}{
+		{"/repo", "/repo", true},
+		{"/repo/src/main.go", "/repo", true},
+		{"/repo/", "/repo", true},
+		{"/repository", "/repo", false},
+		{"/", "/repo", false},
+		{"/other/repo", "/repo", false},
+		{"/repo/..foo", "/repo", true},
+		{"repo/src", "/repo", false},
+	}
+	for _, tt := range tests {
+		if got := Within(tt.path, tt.dir); got != tt.want {
+			t.Errorf("Within(%q, %q) = %v, want %v", tt.path, tt.dir, got, tt.want)
+		}
+	}
+}
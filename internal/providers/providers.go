@@ -0,0 +1,467 @@
+// Package providers holds the data sources shared by all sections, so that
+// each transcript, repository and system source is read once per refresh
+// no matter how many sections display it
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/beads"
+	"github.com/ll931217/claude-hud-enhanced/internal/ci"
+	"github.com/ll931217/claude-hud-enhanced/internal/git"
+	"github.com/ll931217/claude-hud-enhanced/internal/hook"
+	"github.com/ll931217/claude-hud-enhanced/internal/issues"
+	"github.com/ll931217/claude-hud-enhanced/internal/mcp"
+	"github.com/ll931217/claude-hud-enhanced/internal/quota"
+	"github.com/ll931217/claude-hud-enhanced/internal/statuspage"
+	"github.com/ll931217/claude-hud-enhanced/internal/system"
+	"github.com/ll931217/claude-hud-enhanced/internal/tasks"
+	"github.com/ll931217/claude-hud-enhanced/internal/testresults"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+	"github.com/ll931217/claude-hud-enhanced/internal/weather"
+)
+
+// maxParsers bounds how many transcripts stay parsed at once by default
+// Statusline processes only ever see one; the daemon raises it for the
+// sessions it serves with SetMaxParsers
+const maxParsers = 4
+
+// Providers is a container of lazily created, shared data sources
+// All methods are safe for concurrent use
+type Providers struct {
+	mu           sync.Mutex
+	parsers      map[string]*transcript.Parser
+	parserOrder  []string // Parsed transcripts, least recently used first
+	maxParsers   int
+	detectors    map[string]*git.Detector
+	readers      map[string]*beads.Reader
+	testResults  map[string]*testresults.Reader
+	tasks        map[string]*tasks.Reader
+	hookTrackers map[string]*hook.LatencyTracker
+	monitor      *system.Monitor
+	connectivity *system.ConnectivityChecker
+	gpu          *system.GPUReader
+	dirSizer     *system.DirSizer
+	commands     *system.CommandRunner
+	mcpClient    *mcp.Client
+	mcpProber    *mcp.Prober
+	mcpInventory *mcp.InventoryCache
+	statusPage   *statuspage.Client
+	sessionEst   *quota.SessionEstimator
+	usageClient  *quota.UsageClient
+	weather      *weather.Client
+	ci           *ci.Client
+	issues       *issues.Client
+	checkpoints  *git.CheckpointTracker
+	dangerAlerts *transcript.DangerAlerts
+	repoStats    *git.RepoStatsCache
+	stateDir     string
+	workingDir   string // Claude Code's current directory
+	projectDir   string // Directory Claude Code was started in
+}
+
+// New creates an empty provider container
+func New() *Providers {
+	return &Providers{
+		parsers:      make(map[string]*transcript.Parser),
+		maxParsers:   maxParsers,
+		detectors:    make(map[string]*git.Detector),
+		hookTrackers: make(map[string]*hook.LatencyTracker),
+		readers:      make(map[string]*beads.Reader),
+		testResults:  make(map[string]*testresults.Reader),
+		tasks:        make(map[string]*tasks.Reader),
+	}
+}
+
+// SetStateDir persists rate samples and cached probe results under dir, so
+// they carry over between statusline processes. Call before first use
+func (p *Providers) SetStateDir(dir string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stateDir = dir
+}
+
+// SetWorkspace sets the directory the session is working in, Claude Code's
+// current directory, and the one it was started in, which stays put while
+// the current directory follows the session around. Data sources are read
+// there rather than in the process's working directory
+func (p *Providers) SetWorkspace(workingDir, projectDir string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.workingDir, p.projectDir = workingDir, projectDir
+}
+
+// WorkingDir returns the directory the session is working in, or the
+// process's own in standalone mode
+func (p *Providers) WorkingDir() string {
+	p.mu.Lock()
+	dir := p.workingDir
+	p.mu.Unlock()
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	return dir
+}
+
+// ProjectDir returns the directory the session was started in, or the
+// working directory when Claude Code did not send one
+func (p *Providers) ProjectDir() string {
+	p.mu.Lock()
+	dir := p.projectDir
+	p.mu.Unlock()
+	if dir == "" {
+		return p.WorkingDir()
+	}
+	return dir
+}
+
+// statePath returns a file under the state directory, or "" when not persisting
+func (p *Providers) statePath(name string) string {
+	if p.stateDir == "" {
+		return ""
+	}
+	return filepath.Join(p.stateDir, name)
+}
+
+// SetMaxParsers bounds how many transcripts stay parsed at once; the least
+// recently used parser is dropped to make room for another
+func (p *Providers) SetMaxParsers(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.maxParsers = max(n, 1)
+	p.evictParsersLocked()
+}
+
+// Transcript returns the shared parser for a transcript path
+func (p *Providers) Transcript(path string) *transcript.Parser {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if parser, ok := p.parsers[path]; ok {
+		p.parserOrder = append(slices.DeleteFunc(p.parserOrder, func(s string) bool { return s == path }), path)
+		return parser
+	}
+
+	parser := transcript.NewParser(path)
+	p.parsers[path] = parser
+	p.parserOrder = append(p.parserOrder, path)
+	p.evictParsersLocked()
+	return parser
+}
+
+// evictParsersLocked drops the least recently used parsers over the bound
+// Caller must hold p.mu
+func (p *Providers) evictParsersLocked() {
+	for len(p.parserOrder) > p.maxParsers {
+		delete(p.parsers, p.parserOrder[0])
+		p.parserOrder = p.parserOrder[1:]
+	}
+}
+
+// Git returns the shared git detector for a repository path
+func (p *Providers) Git(repoPath string) *git.Detector {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if detector, ok := p.detectors[repoPath]; ok {
+		return detector
+	}
+	detector := git.NewDetector(repoPath)
+	p.detectors[repoPath] = detector
+	return detector
+}
+
+// Checkpoints returns the shared tracker of uncommitted change volume
+func (p *Providers) Checkpoints() *git.CheckpointTracker {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.checkpoints == nil {
+		p.checkpoints = git.NewCheckpointTracker(p.statePath("checkpoints.json"))
+	}
+	return p.checkpoints
+}
+
+// DangerAlerts returns the shared log of dangerous command notifications
+func (p *Providers) DangerAlerts() *transcript.DangerAlerts {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.dangerAlerts == nil {
+		p.dangerAlerts = transcript.NewDangerAlerts(p.statePath("dangeralerts.json"))
+	}
+	return p.dangerAlerts
+}
+
+// RepoStats returns the shared cache of tracked file counts and sizes
+func (p *Providers) RepoStats() *git.RepoStatsCache {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.repoStats == nil {
+		p.repoStats = git.NewRepoStatsCache(p.statePath(git.RepoStatsFileName))
+	}
+	return p.repoStats
+}
+
+// Beads returns the shared beads reader for a repository path
+func (p *Providers) Beads(repoPath string) *beads.Reader {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if reader, ok := p.readers[repoPath]; ok {
+		return reader
+	}
+	reader := beads.NewReader(repoPath)
+	p.readers[repoPath] = reader
+	return reader
+}
+
+// TestResults returns the shared test results reader for a directory and
+// its result file patterns
+func (p *Providers) TestResults(dir string, patterns []string) *testresults.Reader {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := dir + "\x00" + strings.Join(patterns, "\x00")
+	if reader, ok := p.testResults[key]; ok {
+		return reader
+	}
+	reader := testresults.NewReader(dir, patterns)
+	p.testResults[key] = reader
+	return reader
+}
+
+// Tasks returns the shared checklist reader for a directory and its task files
+func (p *Providers) Tasks(dir string, paths []string) *tasks.Reader {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := dir + "\x00" + strings.Join(paths, "\x00")
+	if reader, ok := p.tasks[key]; ok {
+		return reader
+	}
+	reader := tasks.NewReader(dir, paths)
+	p.tasks[key] = reader
+	return reader
+}
+
+// HookLatency returns the shared hook latency tracker for a debug log path
+func (p *Providers) HookLatency(debugLogPath string) *hook.LatencyTracker {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if tracker, ok := p.hookTrackers[debugLogPath]; ok {
+		return tracker
+	}
+	tracker := hook.NewLatencyTracker(debugLogPath)
+	p.hookTrackers[debugLogPath] = tracker
+	return tracker
+}
+
+// System returns the shared system monitor
+func (p *Providers) System() *system.Monitor {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.monitor == nil {
+		p.monitor = system.NewMonitor()
+		p.monitor.SetSampleStore(system.NewSampleStore(p.statePath("samples.json")))
+	}
+	return p.monitor
+}
+
+// Connectivity returns the shared Claude API connectivity checker
+func (p *Providers) Connectivity() *system.ConnectivityChecker {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.connectivity == nil {
+		p.connectivity = system.NewConnectivityChecker(p.statePath("connectivity.json"))
+	}
+	return p.connectivity
+}
+
+// DirSizes returns the shared incremental directory sizer
+func (p *Providers) DirSizes() *system.DirSizer {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.dirSizer == nil {
+		p.dirSizer = system.NewDirSizer(p.statePath("dirsizes.json"))
+	}
+	return p.dirSizer
+}
+
+// Commands returns the shared runner for custom section commands
+func (p *Providers) Commands() *system.CommandRunner {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.commands == nil {
+		p.commands = system.NewCommandRunner(p.statePath("commands.json"))
+	}
+	return p.commands
+}
+
+// GPU returns the shared GPU usage reader
+func (p *Providers) GPU() *system.GPUReader {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.gpu == nil {
+		p.gpu = system.NewGPUReader(p.statePath("gpu.json"))
+	}
+	return p.gpu
+}
+
+// StatusPage returns the shared Anthropic status page client
+func (p *Providers) StatusPage() *statuspage.Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.statusPage == nil {
+		p.statusPage = statuspage.NewClient(p.statePath("statuspage.json"))
+	}
+	return p.statusPage
+}
+
+// CI returns the shared CI status client
+func (p *Providers) CI() *ci.Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.ci == nil {
+		p.ci = ci.NewClient(p.statePath("ci.json"))
+	}
+	return p.ci
+}
+
+// Issues returns the shared client of remote issue trackers
+func (p *Providers) Issues() *issues.Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.issues == nil {
+		p.issues = issues.NewClient(p.statePath("issues.json"))
+	}
+	return p.issues
+}
+
+// Weather returns the shared weather client
+func (p *Providers) Weather() *weather.Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.weather == nil {
+		p.weather = weather.NewClient(p.statePath("weather.json"))
+	}
+	return p.weather
+}
+
+// MCP returns the shared MCP client
+func (p *Providers) MCP() *mcp.Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.mcpClient == nil {
+		p.mcpClient = mcp.NewClient()
+		p.mcpClient.SetInventory(p.mcpInventoryLocked())
+	}
+	return p.mcpClient
+}
+
+// MCPProber returns the shared MCP health prober
+func (p *Providers) MCPProber() *mcp.Prober {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.mcpProber == nil {
+		p.mcpProber = mcp.NewProber(p.statePath("mcphealth.json"))
+	}
+	return p.mcpProber
+}
+
+// MCPInventory returns the shared cache of MCP server tool lists
+func (p *Providers) MCPInventory() *mcp.InventoryCache {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.mcpInventoryLocked()
+}
+
+// mcpInventoryLocked returns the inventory cache; callers must hold p.mu
+func (p *Providers) mcpInventoryLocked() *mcp.InventoryCache {
+	if p.mcpInventory == nil {
+		p.mcpInventory = mcp.NewInventoryCache(p.statePath("mcptools.json"))
+	}
+	return p.mcpInventory
+}
+
+// SessionWindow returns the shared estimator of the 5-hour session window
+func (p *Providers) SessionWindow() *quota.SessionEstimator {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.sessionEst == nil {
+		projectsDir, _ := transcript.ProjectsDir()
+		p.sessionEst = quota.NewSessionEstimator(projectsDir, p.statePath("sessionwindow.json"))
+	}
+	return p.sessionEst
+}
+
+// UsageAPI returns the shared subscription usage client
+func (p *Providers) UsageAPI() *quota.UsageClient {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.usageClient == nil {
+		p.usageClient = quota.NewUsageClient(p.statePath("usage.json"))
+	}
+	return p.usageClient
+}
+
+// Close releases resources held by providers, such as beads and test
+// results file watchers, and waits for background requests to remote
+// services, so their results are saved for the next process
+func (p *Providers) Close() {
+	p.mu.Lock()
+	waiters := make([]interface{ Wait() }, 0, 4)
+	if p.usageClient != nil {
+		waiters = append(waiters, p.usageClient)
+	}
+	if p.weather != nil {
+		waiters = append(waiters, p.weather)
+	}
+	if p.ci != nil {
+		waiters = append(waiters, p.ci)
+	}
+	if p.issues != nil {
+		waiters = append(waiters, p.issues)
+	}
+	readers := make([]interface{ Stop() }, 0, len(p.readers)+len(p.testResults)+len(p.tasks))
+	for _, reader := range p.readers {
+		readers = append(readers, reader)
+	}
+	for _, reader := range p.testResults {
+		readers = append(readers, reader)
+	}
+	for _, reader := range p.tasks {
+		readers = append(readers, reader)
+	}
+	p.readers = make(map[string]*beads.Reader)
+	p.testResults = make(map[string]*testresults.Reader)
+	p.tasks = make(map[string]*tasks.Reader)
+	p.mu.Unlock()
+
+	for _, reader := range readers {
+		reader.Stop()
+	}
+	for _, client := range waiters {
+		client.Wait()
+	}
+}
@@ -0,0 +1,70 @@
+package providers
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTranscript(t *testing.T) {
+	t.Run("reuses the parser for a path", func(t *testing.T) {
+		p := New()
+		if p.Transcript("/a.jsonl") != p.Transcript("/a.jsonl") {
+			t.Error("same path should return the same parser")
+		}
+		if p.Transcript("/a.jsonl") == p.Transcript("/b.jsonl") {
+			t.Error("different paths should return different parsers")
+		}
+	})
+
+	t.Run("bounds the number of parsers", func(t *testing.T) {
+		p := New()
+		first := p.Transcript("/0.jsonl")
+		for i := 1; i <= maxParsers; i++ {
+			p.Transcript(fmt.Sprintf("/%d.jsonl", i))
+		}
+		if len(p.parsers) != maxParsers {
+			t.Errorf("len(parsers) = %d, want %d", len(p.parsers), maxParsers)
+		}
+		if p.Transcript("/0.jsonl") == first {
+			t.Error("oldest parser should have been evicted")
+		}
+	})
+
+	t.Run("evicts the least recently used parser", func(t *testing.T) {
+		p := New()
+		p.SetMaxParsers(2)
+		a := p.Transcript("/a.jsonl")
+		b := p.Transcript("/b.jsonl")
+		p.Transcript("/a.jsonl") // a is now more recent than b
+		p.Transcript("/c.jsonl")
+		if p.Transcript("/a.jsonl") != a {
+			t.Error("recently used parser should have been kept")
+		}
+		if p.Transcript("/b.jsonl") == b {
+			t.Error("least recently used parser should have been evicted")
+		}
+
+		p.SetMaxParsers(1)
+		if len(p.parsers) != 1 {
+			t.Errorf("len(parsers) = %d after lowering the bound, want 1", len(p.parsers))
+		}
+	})
+}
+
+func TestSharedSources(t *testing.T) {
+	p := New()
+	defer p.Close()
+
+	if p.Git("/repo") != p.Git("/repo") {
+		t.Error("Git() should reuse the detector for a path")
+	}
+	if p.Beads("/repo") != p.Beads("/repo") {
+		t.Error("Beads() should reuse the reader for a path")
+	}
+	if p.System() != p.System() {
+		t.Error("System() should return one monitor")
+	}
+	if p.MCP() != p.MCP() {
+		t.Error("MCP() should return one client")
+	}
+}
@@ -0,0 +1,122 @@
+package quota
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Billing modes
+const (
+	BillingUnknown      = ""
+	BillingAPI          = "api"          // Pay per token: API key, gateway or cloud provider
+	BillingSubscription = "subscription" // Claude Pro/Max quota
+)
+
+// Billing describes how Claude Code bills the session
+type Billing struct {
+	Mode   string
+	Plan   string // Subscription plan such as "pro" or "max"; empty when unknown
+	Source string // What the mode was detected from, for diagnostics
+}
+
+// BillingSources are the inputs billing is detected from
+type BillingSources struct {
+	ModelID   string              // Model ID from the statusline payload
+	ClaudeDir string              // Usually ~/.claude
+	Getenv    func(string) string // Reads the environment
+}
+
+// apiKeyEnvs select pay-per-token billing; Claude Code prefers them over a subscription login
+var apiKeyEnvs = []string{"ANTHROPIC_API_KEY", "ANTHROPIC_AUTH_TOKEN"}
+
+// cloudProviderEnvs route requests through a cloud provider's billing
+var cloudProviderEnvs = []string{"CLAUDE_CODE_USE_BEDROCK", "CLAUDE_CODE_USE_VERTEX", "CLAUDE_CODE_USE_FOUNDRY"}
+
+// DetectBilling works out how the session is billed from the model ID in
+// the statusline payload, the environment, Claude Code's settings and its
+// stored credentials, following the order Claude Code picks credentials in
+func DetectBilling(modelID string) Billing {
+	home, _ := os.UserHomeDir()
+	return DetectBillingFrom(BillingSources{
+		ModelID:   modelID,
+		ClaudeDir: filepath.Join(home, ".claude"),
+		Getenv:    os.Getenv,
+	})
+}
+
+// DetectBillingFrom detects billing from explicit sources
+func DetectBillingFrom(src BillingSources) Billing {
+	for _, name := range cloudProviderEnvs {
+		if isTruthy(src.Getenv(name)) {
+			return Billing{Mode: BillingAPI, Source: name}
+		}
+	}
+	if isCloudModelID(src.ModelID) {
+		return Billing{Mode: BillingAPI, Source: "model id"}
+	}
+	for _, name := range apiKeyEnvs {
+		if src.Getenv(name) != "" {
+			return Billing{Mode: BillingAPI, Source: name}
+		}
+	}
+	if source := settingsAPIKey(filepath.Join(src.ClaudeDir, "settings.json")); source != "" {
+		return Billing{Mode: BillingAPI, Source: source}
+	}
+	if src.Getenv(OAuthTokenEnv) != "" {
+		return Billing{Mode: BillingSubscription, Source: OAuthTokenEnv}
+	}
+	if data, err := storedCredentials(src.ClaudeDir); err == nil {
+		if creds, err := parseCredentials(data); err == nil && creds.ClaudeAIOAuth != nil && creds.ClaudeAIOAuth.AccessToken != "" {
+			return Billing{Mode: BillingSubscription, Plan: creds.ClaudeAIOAuth.SubscriptionType, Source: "credentials"}
+		}
+	}
+	return Billing{Mode: BillingUnknown}
+}
+
+// settingsAPIKey reports which setting in settings.json selects API billing, if any
+func settingsAPIKey(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var settings struct {
+		APIKeyHelper string            `json:"apiKeyHelper"`
+		Env          map[string]string `json:"env"`
+	}
+	if json.Unmarshal(data, &settings) != nil {
+		return ""
+	}
+	if settings.APIKeyHelper != "" {
+		return "apiKeyHelper"
+	}
+	for _, name := range cloudProviderEnvs {
+		if isTruthy(settings.Env[name]) {
+			return name
+		}
+	}
+	for _, name := range apiKeyEnvs {
+		if settings.Env[name] != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// isCloudModelID reports whether a model ID names a Bedrock or Vertex AI model,
+// e.g. "us.anthropic.claude-sonnet-4-5-20250929-v1:0" or "claude-sonnet-4-5@20250929"
+func isCloudModelID(modelID string) bool {
+	return strings.Contains(modelID, "anthropic.claude") ||
+		strings.HasPrefix(modelID, "arn:aws:bedrock") ||
+		strings.Contains(modelID, "@")
+}
+
+// isTruthy reports whether an environment flag is set the way Claude Code reads it
+func isTruthy(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
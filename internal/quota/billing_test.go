@@ -0,0 +1,91 @@
+package quota
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectBillingFrom(t *testing.T) {
+	const subscription = `{"claudeAiOauth":{"accessToken":"sk-ant-oat01-x","subscriptionType":"max"}}`
+
+	tests := []struct {
+		name        string
+		modelID     string
+		env         map[string]string
+		settings    string
+		credentials string
+		want        Billing
+	}{
+		{
+			name:        "subscription login",
+			modelID:     "claude-opus-4-5-20251101",
+			credentials: subscription,
+			want:        Billing{Mode: BillingSubscription, Plan: "max", Source: "credentials"},
+		},
+		{
+			name:        "API key wins over a subscription login",
+			env:         map[string]string{"ANTHROPIC_API_KEY": "sk-ant-api03-x"},
+			credentials: subscription,
+			want:        Billing{Mode: BillingAPI, Source: "ANTHROPIC_API_KEY"},
+		},
+		{
+			name:        "api key helper in settings",
+			settings:    `{"apiKeyHelper":"~/bin/get-key.sh"}`,
+			credentials: subscription,
+			want:        Billing{Mode: BillingAPI, Source: "apiKeyHelper"},
+		},
+		{
+			name:        "bedrock enabled in settings",
+			settings:    `{"env":{"CLAUDE_CODE_USE_BEDROCK":"1"}}`,
+			credentials: subscription,
+			want:        Billing{Mode: BillingAPI, Source: "CLAUDE_CODE_USE_BEDROCK"},
+		},
+		{
+			name:    "vertex enabled in the environment",
+			env:     map[string]string{"CLAUDE_CODE_USE_VERTEX": "true"},
+			want:    Billing{Mode: BillingAPI, Source: "CLAUDE_CODE_USE_VERTEX"},
+			modelID: "claude-sonnet-4-5",
+		},
+		{
+			name:    "bedrock model id",
+			modelID: "us.anthropic.claude-sonnet-4-5-20250929-v1:0",
+			want:    Billing{Mode: BillingAPI, Source: "model id"},
+		},
+		{
+			name: "oauth token in the environment",
+			env:  map[string]string{OAuthTokenEnv: "sk-ant-oat01-x"},
+			want: Billing{Mode: BillingSubscription, Source: OAuthTokenEnv},
+		},
+		{
+			name:        "nothing to go on",
+			credentials: `{}`,
+			want:        Billing{Mode: BillingUnknown},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.settings != "" {
+				if err := os.WriteFile(filepath.Join(dir, "settings.json"), []byte(tt.settings), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.credentials != "" {
+				if err := os.WriteFile(filepath.Join(dir, ".credentials.json"), []byte(tt.credentials), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got := DetectBillingFrom(BillingSources{
+				ModelID:   tt.modelID,
+				ClaudeDir: dir,
+				Getenv:    func(name string) string { return tt.env[name] },
+			})
+			if got != tt.want {
+				t.Errorf("DetectBillingFrom() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package quota
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/filecache"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+// activityLookback bounds how far back transcripts are read; a window that
+// started earlier has long reset, and chains of windows this long are rare
+const activityLookback = 24 * time.Hour
+
+// estimate is the cached outcome of a scan
+type estimate struct {
+	Window    Window    `json:"window"`
+	Found     bool      `json:"found"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// SessionEstimator estimates the current session window from the message
+// times in every project's transcripts, since all sessions share the limit
+// With a cache path the estimate is shared across processes, so statusline
+// renders do not each rescan the transcripts
+type SessionEstimator struct {
+	ProjectsDir string
+	TTL         time.Duration
+	CachePath   string // Optional file caching the last estimate
+
+	mu   sync.Mutex
+	last estimate
+}
+
+// NewSessionEstimator creates an estimator for the transcripts under projectsDir
+func NewSessionEstimator(projectsDir, cachePath string) *SessionEstimator {
+	return &SessionEstimator{
+		ProjectsDir: projectsDir,
+		TTL:         time.Minute,
+		CachePath:   cachePath,
+	}
+}
+
+// Window returns the active session window at now, rescanning the
+// transcripts once the last estimate is older than TTL
+// Returns false when no window is active
+func (e *SessionEstimator) Window(now time.Time) (Window, bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.last.CheckedAt.IsZero() {
+		e.last = e.readCache()
+	}
+	if !e.last.CheckedAt.IsZero() && now.Sub(e.last.CheckedAt) < e.TTL && !now.Before(e.last.CheckedAt) {
+		return e.current(now)
+	}
+
+	activity, err := e.activity(now.Add(-activityLookback))
+	if err != nil {
+		return Window{}, false, err
+	}
+	window, found := EstimateSessionWindow(activity, now)
+	e.last = estimate{Window: window, Found: found, CheckedAt: now}
+	e.writeCache(e.last)
+	return e.current(now)
+}
+
+// current returns the last estimate if its window is still active at now
+func (e *SessionEstimator) current(now time.Time) (Window, bool, error) {
+	if !e.last.Found || !e.last.Window.Active(now) {
+		return Window{}, false, nil
+	}
+	return e.last.Window, true, nil
+}
+
+// activity returns the times of assistant messages since the given time
+func (e *SessionEstimator) activity(since time.Time) ([]time.Time, error) {
+	if _, err := os.Stat(e.ProjectsDir); err != nil {
+		return nil, err
+	}
+	paths, err := transcript.ListTranscripts(e.ProjectsDir, since)
+	if err != nil {
+		return nil, err
+	}
+
+	var times []time.Time
+	for _, path := range paths {
+		// One unreadable transcript should not hide the window; keep what was read
+		found, _ := messageTimes(path, since)
+		times = append(times, found...)
+	}
+	return times, nil
+}
+
+// messageTimes reads the times of assistant messages in a transcript
+func messageTimes(path string, since time.Time) ([]time.Time, error) {
+	rc, err := transcript.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var times []time.Time
+	reader := bufio.NewReader(rc)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var entry struct {
+				Type      string    `json:"type"`
+				Timestamp time.Time `json:"timestamp"`
+			}
+			if json.Unmarshal(line, &entry) == nil && entry.Type == "assistant" && !entry.Timestamp.Before(since) {
+				times = append(times, entry.Timestamp)
+			}
+		}
+		if err == io.EOF {
+			return times, nil
+		}
+		if err != nil {
+			return times, err
+		}
+	}
+}
+
+func (e *SessionEstimator) readCache() estimate {
+	var result estimate
+	filecache.Load(e.CachePath, &result)
+	return result
+}
+
+func (e *SessionEstimator) writeCache(result estimate) {
+	_ = filecache.Save(e.CachePath, result)
+}
@@ -0,0 +1,272 @@
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/filecache"
+	"github.com/ll931217/claude-hud-enhanced/internal/refresh"
+)
+
+// SourceOAuth marks windows reported by the subscription usage endpoint
+const SourceOAuth = "oauth"
+
+// WeeklyWindowLength is the length of Claude's weekly usage window
+const WeeklyWindowLength = 7 * 24 * time.Hour
+
+// DefaultUsageURL is the endpoint reporting subscription quota usage
+const DefaultUsageURL = "https://api.anthropic.com/api/oauth/usage"
+
+// oauthBeta is the beta header the usage endpoint requires
+const oauthBeta = "oauth-2025-04-20"
+
+// OAuthTokenEnv overrides the credentials Claude Code stored, like in Claude Code itself
+const OAuthTokenEnv = "CLAUDE_CODE_OAUTH_TOKEN"
+
+// keychainService is the macOS Keychain item Claude Code stores credentials in
+const keychainService = "Claude Code-credentials"
+
+// ErrNoCredentials is returned when Claude Code is not logged in with a subscription
+var ErrNoCredentials = errors.New("no Claude Code OAuth credentials; log in with a Claude subscription")
+
+// Usage is the subscription quota used in the 5-hour and weekly windows
+type Usage struct {
+	FiveHour Window `json:"five_hour"`
+	Weekly   Window `json:"weekly"`
+}
+
+// cachedUsage is the outcome of the last request, shared across processes
+type cachedUsage struct {
+	Usage     Usage     `json:"usage"`
+	Found     bool      `json:"found"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// UsageClient queries the usage endpoint with Claude Code's OAuth token
+// Requests run in the background, so a slow endpoint never holds up a render.
+// Results and failures are both cached for the TTL passed to Usage, so the
+// endpoint is queried at most once per TTL across all statusline processes
+// The token is only read, never refreshed or written
+type UsageClient struct {
+	URL         string
+	Timeout     time.Duration
+	CachePath   string // Optional file caching the last result; never holds the token
+	Client      *http.Client
+	Credentials func() (string, error) // Returns the access token
+
+	mu      sync.Mutex
+	last    cachedUsage
+	fetches refresh.Group
+}
+
+// NewUsageClient creates a client reading Claude Code's stored credentials
+func NewUsageClient(cachePath string) *UsageClient {
+	return &UsageClient{
+		URL:         DefaultUsageURL,
+		Timeout:     3 * time.Second,
+		CachePath:   cachePath,
+		Client:      &http.Client{},
+		Credentials: ReadAccessToken,
+	}
+}
+
+// Usage returns the quota usage, querying the endpoint in the background once
+// the cached result is older than maxAge. It waits for the request until ctx
+// is done; until then, and after a failure, the previous usage, if any, is
+// returned, after a failure with the error until maxAge passes again
+func (c *UsageClient) Usage(ctx context.Context, maxAge time.Duration) (Usage, bool, error) {
+	c.mu.Lock()
+	if c.last.CheckedAt.IsZero() {
+		c.last = c.readCache()
+	}
+	last := c.last
+	c.mu.Unlock()
+
+	now := time.Now()
+	if last.CheckedAt.IsZero() || now.Sub(last.CheckedAt) >= maxAge || now.Before(last.CheckedAt) {
+		if c.fetches.Do(ctx, "usage", c.refresh) {
+			c.mu.Lock()
+			last = c.last
+			c.mu.Unlock()
+		}
+	}
+
+	return last.Usage, last.Found, cachedError(last.Error)
+}
+
+// refresh queries the endpoint and caches the outcome
+func (c *UsageClient) refresh() {
+	usage, err := c.fetch(context.Background())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.last.CheckedAt = time.Now()
+	c.last.Error = ""
+	if err != nil {
+		c.last.Error = err.Error()
+	} else {
+		c.last.Usage, c.last.Found = usage, true
+	}
+	c.writeCache(c.last)
+}
+
+// Wait blocks until a request still running finishes
+func (c *UsageClient) Wait() {
+	c.fetches.Wait()
+}
+
+// cachedError turns a cached error message back into an error, keeping
+// ErrNoCredentials comparable with errors.Is
+func cachedError(message string) error {
+	switch message {
+	case "":
+		return nil
+	case ErrNoCredentials.Error():
+		return ErrNoCredentials
+	}
+	return errors.New(message)
+}
+
+// usageResponse is the body returned by the usage endpoint
+type usageResponse struct {
+	FiveHour *usageWindow `json:"five_hour"`
+	SevenDay *usageWindow `json:"seven_day"`
+}
+
+type usageWindow struct {
+	Utilization float64 `json:"utilization"` // Percent of the quota used
+	ResetsAt    string  `json:"resets_at"`
+}
+
+// window converts a reported window; windows without a reset time have not started
+func (w *usageWindow) window(length time.Duration) Window {
+	if w == nil {
+		return Window{Percent: -1, Source: SourceOAuth}
+	}
+	window := Window{Percent: int(math.Round(w.Utilization)), Source: SourceOAuth}
+	if resets, err := time.Parse(time.RFC3339Nano, w.ResetsAt); err == nil {
+		window.ResetsAt = resets
+		window.Start = resets.Add(-length)
+	}
+	return window
+}
+
+// fetch queries the usage endpoint
+func (c *UsageClient) fetch(ctx context.Context) (Usage, error) {
+	token, err := c.Credentials()
+	if err != nil {
+		return Usage{}, err
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, c.URL, nil)
+	if err != nil {
+		return Usage{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("anthropic-beta", oauthBeta)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return Usage{}, fmt.Errorf("usage request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Usage{}, fmt.Errorf("usage endpoint returned %s", resp.Status)
+	}
+
+	var body usageResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return Usage{}, fmt.Errorf("invalid usage response: %w", err)
+	}
+	return Usage{
+		FiveHour: body.FiveHour.window(SessionWindowLength),
+		Weekly:   body.SevenDay.window(WeeklyWindowLength),
+	}, nil
+}
+
+// credentialsFile is the layout of Claude Code's stored credentials
+type credentialsFile struct {
+	ClaudeAIOAuth *struct {
+		AccessToken      string `json:"accessToken"`
+		ExpiresAt        int64  `json:"expiresAt"`        // Unix milliseconds
+		SubscriptionType string `json:"subscriptionType"` // e.g. "pro" or "max"
+	} `json:"claudeAiOauth"`
+}
+
+// ReadAccessToken returns Claude Code's OAuth access token, from
+// $CLAUDE_CODE_OAUTH_TOKEN, ~/.claude/.credentials.json or, on macOS, the Keychain
+func ReadAccessToken() (string, error) {
+	if token := os.Getenv(OAuthTokenEnv); token != "" {
+		return token, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	data, err := storedCredentials(filepath.Join(home, ".claude"))
+	if err != nil {
+		return "", ErrNoCredentials
+	}
+	return parseAccessToken(data, time.Now())
+}
+
+// storedCredentials reads the credentials Claude Code stored in claudeDir
+// or, on macOS, the Keychain
+func storedCredentials(claudeDir string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(claudeDir, ".credentials.json"))
+	if errors.Is(err, os.ErrNotExist) && runtime.GOOS == "darwin" {
+		data, err = exec.Command("security", "find-generic-password", "-s", keychainService, "-w").Output()
+	}
+	return data, err
+}
+
+// parseCredentials decodes stored credentials
+func parseCredentials(data []byte) (credentialsFile, error) {
+	var creds credentialsFile
+	if err := json.Unmarshal([]byte(strings.TrimSpace(string(data))), &creds); err != nil {
+		return creds, fmt.Errorf("invalid Claude Code credentials: %w", err)
+	}
+	return creds, nil
+}
+
+// parseAccessToken extracts an unexpired access token from stored credentials
+func parseAccessToken(data []byte, now time.Time) (string, error) {
+	creds, err := parseCredentials(data)
+	if err != nil {
+		return "", err
+	}
+	if creds.ClaudeAIOAuth == nil || creds.ClaudeAIOAuth.AccessToken == "" {
+		return "", ErrNoCredentials
+	}
+	if expires := creds.ClaudeAIOAuth.ExpiresAt; expires > 0 && now.UnixMilli() >= expires {
+		return "", errors.New("Claude Code OAuth token expired; it is refreshed the next time Claude Code runs")
+	}
+	return creds.ClaudeAIOAuth.AccessToken, nil
+}
+
+func (c *UsageClient) readCache() cachedUsage {
+	var result cachedUsage
+	filecache.Load(c.CachePath, &result)
+	return result
+}
+
+func (c *UsageClient) writeCache(result cachedUsage) {
+	_ = filecache.Save(c.CachePath, result)
+}
@@ -0,0 +1,98 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUsageClient_Usage(t *testing.T) {
+	var requests atomic.Int32
+	var fail atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("Authorization") != "Bearer token-1" || r.Header.Get("anthropic-beta") != oauthBeta {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if fail.Load() {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"five_hour":{"utilization":41.6,"resets_at":"2026-01-10T14:00:00+00:00"},"seven_day":{"utilization":19,"resets_at":"2026-01-14T09:00:00Z"},"seven_day_opus":null}`))
+	}))
+	defer srv.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "usage.json")
+	newClient := func() *UsageClient {
+		c := NewUsageClient(cachePath)
+		c.URL = srv.URL
+		c.Credentials = func() (string, error) { return "token-1", nil }
+		return c
+	}
+
+	ctx := context.Background()
+	usage, found, err := newClient().Usage(ctx, time.Hour)
+	if err != nil || !found {
+		t.Fatalf("Usage() = %+v, %v, %v", usage, found, err)
+	}
+	if usage.FiveHour.Percent != 42 || !usage.FiveHour.ResetsAt.Equal(time.Date(2026, 1, 10, 14, 0, 0, 0, time.UTC)) {
+		t.Errorf("FiveHour = %+v", usage.FiveHour)
+	}
+	if usage.Weekly.Percent != 19 || !usage.Weekly.Start.Equal(time.Date(2026, 1, 7, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Weekly = %+v", usage.Weekly)
+	}
+
+	// Another process reuses the cached result
+	if _, _, err := newClient().Usage(ctx, time.Hour); err != nil || requests.Load() != 1 {
+		t.Errorf("expected the cached result, got %d requests (%v)", requests.Load(), err)
+	}
+
+	// A failed refresh keeps the previous usage and is itself cached
+	fail.Store(true)
+	client := newClient()
+	usage, found, err = client.Usage(ctx, 0)
+	if err == nil || !found || usage.FiveHour.Percent != 42 {
+		t.Errorf("expected the previous usage with an error, got %+v, %v, %v", usage, found, err)
+	}
+	if _, _, err := client.Usage(ctx, time.Hour); err == nil || requests.Load() != 2 {
+		t.Errorf("expected the cached failure, got %d requests (%v)", requests.Load(), err)
+	}
+}
+
+func TestUsageClient_NoCredentials(t *testing.T) {
+	c := NewUsageClient("")
+	c.URL = "http://127.0.0.1:0"
+	c.Credentials = func() (string, error) { return "", ErrNoCredentials }
+	if _, found, err := c.Usage(context.Background(), time.Minute); !errors.Is(err, ErrNoCredentials) || found {
+		t.Errorf("Usage() = %v, %v, want ErrNoCredentials", found, err)
+	}
+}
+
+func TestParseAccessToken(t *testing.T) {
+	now := time.UnixMilli(1_700_000_000_000)
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		wantErr bool
+	}{
+		{name: "valid", data: `{"claudeAiOauth":{"accessToken":"sk-ant-oat01","expiresAt":1700000600000}}`, want: "sk-ant-oat01"},
+		{name: "expired", data: `{"claudeAiOauth":{"accessToken":"sk-ant-oat01","expiresAt":1699999999000}}`, wantErr: true},
+		{name: "API key login", data: `{}`, wantErr: true},
+		{name: "invalid", data: `not json`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAccessToken([]byte(tt.data), now)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("parseAccessToken() = %q, %v", got, err)
+			}
+		})
+	}
+}
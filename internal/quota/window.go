@@ -0,0 +1,87 @@
+// Package quota tracks Claude subscription rate-limit windows, such as the
+// 5-hour session window, and when they reset
+package quota
+
+import (
+	"sort"
+	"time"
+)
+
+// SessionWindowLength is the length of Claude's rolling session window
+const SessionWindowLength = 5 * time.Hour
+
+// Sources a window can come from
+const (
+	SourceTranscripts = "transcripts" // Estimated from local transcript activity
+	SourceZai         = "zai"         // Reported by the Z.ai quota API
+)
+
+// Window is one rate-limit window and how much of its quota is used
+type Window struct {
+	Start    time.Time `json:"start"`
+	ResetsAt time.Time `json:"resets_at"`
+	Percent  int       `json:"percent"` // Used share of the quota, or -1 when unknown
+	Source   string    `json:"source"`
+}
+
+// Estimated reports whether the window was derived from local activity
+// rather than reported by a quota API
+func (w Window) Estimated() bool {
+	return w.Source == SourceTranscripts
+}
+
+// Active reports whether the window has not reset yet at now
+func (w Window) Active(now time.Time) bool {
+	return !w.ResetsAt.IsZero() && now.Before(w.ResetsAt)
+}
+
+// Remaining returns the time until the window resets
+func (w Window) Remaining(now time.Time) time.Duration {
+	if !w.Active(now) {
+		return 0
+	}
+	return w.ResetsAt.Sub(now)
+}
+
+// ElapsedPercent returns how much of the window has passed at now
+func (w Window) ElapsedPercent(now time.Time) int {
+	length := w.ResetsAt.Sub(w.Start)
+	if length <= 0 {
+		return 0
+	}
+	elapsed := now.Sub(w.Start)
+	switch {
+	case elapsed <= 0:
+		return 0
+	case elapsed >= length:
+		return 100
+	}
+	return int(elapsed * 100 / length)
+}
+
+// EstimateSessionWindow derives the current session window from message
+// times, the way Claude's limits work: a window starts at the hour of the
+// first message after the previous window ended, or after five idle hours,
+// and lasts five hours. Returns false when no window is active at now
+func EstimateSessionWindow(activity []time.Time, now time.Time) (Window, bool) {
+	times := make([]time.Time, len(activity))
+	copy(times, activity)
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	var start, last time.Time
+	for _, t := range times {
+		if t.After(now) {
+			break
+		}
+		if start.IsZero() || t.Sub(start) >= SessionWindowLength || t.Sub(last) >= SessionWindowLength {
+			start = t.Truncate(time.Hour)
+		}
+		last = t
+	}
+
+	w := Window{Start: start, ResetsAt: start.Add(SessionWindowLength), Percent: -1, Source: SourceTranscripts}
+	if start.IsZero() || !w.Active(now) {
+		return Window{}, false
+	}
+	return w, true
+}
@@ -0,0 +1,117 @@
+package quota
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEstimateSessionWindow(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 1, 10, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name      string
+		activity  []time.Time
+		now       time.Time
+		wantStart time.Time
+		wantOK    bool
+	}{
+		{
+			name:      "window starts at the hour of the first message",
+			activity:  []time.Time{at(9, 40), at(10, 15), at(11, 0)},
+			now:       at(11, 30),
+			wantStart: at(9, 0),
+			wantOK:    true,
+		},
+		{
+			name:      "next window starts after the previous one ended",
+			activity:  []time.Time{at(8, 10), at(12, 50), at(13, 20), at(14, 0)},
+			now:       at(14, 5),
+			wantStart: at(13, 0),
+			wantOK:    true,
+		},
+		{
+			name:     "window has reset",
+			activity: []time.Time{at(6, 30)},
+			now:      at(11, 30),
+		},
+		{
+			name: "no activity",
+			now:  at(11, 30),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, ok := EstimateSessionWindow(tt.activity, tt.now)
+			if ok != tt.wantOK {
+				t.Fatalf("EstimateSessionWindow() ok = %v, want %v (%+v)", ok, tt.wantOK, w)
+			}
+			if !ok {
+				return
+			}
+			if !w.Start.Equal(tt.wantStart) || !w.ResetsAt.Equal(tt.wantStart.Add(SessionWindowLength)) {
+				t.Errorf("window = %v..%v, want start %v", w.Start, w.ResetsAt, tt.wantStart)
+			}
+			if w.Percent != -1 || !w.Estimated() {
+				t.Errorf("estimated windows have no known usage: %+v", w)
+			}
+		})
+	}
+}
+
+func TestWindow_Progress(t *testing.T) {
+	start := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	w := Window{Start: start, ResetsAt: start.Add(SessionWindowLength)}
+	now := start.Add(3*time.Hour + 48*time.Minute)
+
+	if got := w.Remaining(now); got != 72*time.Minute {
+		t.Errorf("Remaining() = %v, want 1h12m", got)
+	}
+	if got := w.ElapsedPercent(now); got != 76 {
+		t.Errorf("ElapsedPercent() = %d, want 76", got)
+	}
+	if w.Active(start.Add(SessionWindowLength)) {
+		t.Error("window should have reset at ResetsAt")
+	}
+}
+
+func TestSessionEstimator_Window(t *testing.T) {
+	dir := t.TempDir()
+	project := filepath.Join(dir, "-home-u-project")
+	if err := os.MkdirAll(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Join([]string{
+		`{"type":"user","timestamp":"2026-01-10T09:35:00Z","message":{"role":"user","content":"hi"}}`,
+		`{"type":"assistant","timestamp":"2026-01-10T09:40:00Z","message":{"role":"assistant","content":[]}}`,
+		`{"type":"assistant","timestamp":"2026-01-10T10:20:00Z","message":{"role":"assistant","content":[]}}`,
+	}, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(project, "s1.jsonl"), []byte(lines), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cachePath := filepath.Join(dir, "state", "sessionwindow.json")
+	now := time.Date(2026, 1, 10, 11, 0, 0, 0, time.UTC)
+	e := NewSessionEstimator(dir, cachePath)
+	w, ok, err := e.Window(now)
+	if err != nil || !ok {
+		t.Fatalf("Window() = %+v, %v, %v", w, ok, err)
+	}
+	if want := time.Date(2026, 1, 10, 14, 0, 0, 0, time.UTC); !w.ResetsAt.Equal(want) {
+		t.Errorf("ResetsAt = %v, want %v", w.ResetsAt, want)
+	}
+
+	// Another process reuses the cached estimate without scanning
+	other := NewSessionEstimator(filepath.Join(dir, "missing"), cachePath)
+	if w, ok, err := other.Window(now.Add(30 * time.Second)); err != nil || !ok || !w.ResetsAt.Equal(time.Date(2026, 1, 10, 14, 0, 0, 0, time.UTC)) {
+		t.Errorf("cached Window() = %+v, %v, %v", w, ok, err)
+	}
+	if _, _, err := other.Window(now.Add(2 * time.Minute)); err == nil {
+		t.Error("expected an error once the stale estimate is rescanned from a missing directory")
+	}
+}
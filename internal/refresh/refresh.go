@@ -0,0 +1,59 @@
+// Package refresh runs slow fetches, such as requests to remote services,
+// in the background. A render asks for a fetch and waits for it only as long
+// as its context allows, then shows the last cached value; the fetch carries
+// on and its result is there for the next render
+package refresh
+
+import (
+	"context"
+	"sync"
+)
+
+// Group runs at most one fetch per key at a time
+// The zero value is ready to use
+type Group struct {
+	mu      sync.Mutex
+	running map[string]chan struct{} // Closed when the fetch for a key finishes
+	wg      sync.WaitGroup
+}
+
+// Do starts fetch for key in the background unless one is already running,
+// and waits for it until ctx is done. It reports whether the fetch finished
+func (g *Group) Do(ctx context.Context, key string, fetch func()) bool {
+	g.mu.Lock()
+	done, running := g.running[key]
+	if !running {
+		if g.running == nil {
+			g.running = make(map[string]chan struct{})
+		}
+		done = make(chan struct{})
+		g.running[key] = done
+		g.wg.Add(1)
+		go g.run(key, fetch, done)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (g *Group) run(key string, fetch func(), done chan struct{}) {
+	defer g.wg.Done()
+	defer func() {
+		g.mu.Lock()
+		delete(g.running, key)
+		g.mu.Unlock()
+		close(done)
+	}()
+	fetch()
+}
+
+// Wait blocks until every fetch started so far finishes, so a process
+// rendering once can save what it fetched before it exits
+func (g *Group) Wait() {
+	g.wg.Wait()
+}
@@ -0,0 +1,36 @@
+package refresh
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup_Do(t *testing.T) {
+	var g Group
+	var fetches atomic.Int32
+	if !g.Do(context.Background(), "a", func() { fetches.Add(1) }) || fetches.Load() != 1 {
+		t.Fatalf("Do() did not wait for the fetch (%d fetches)", fetches.Load())
+	}
+
+	// A slow fetch is left running, and asking again joins it
+	release := make(chan struct{})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if g.Do(ctx, "a", func() { fetches.Add(1); <-release }) {
+		t.Error("Do() of a slow fetch finished before its context")
+	}
+	if g.Do(ctx, "a", func() { fetches.Add(1) }) {
+		t.Error("Do() while a fetch runs finished before its context")
+	}
+	close(release)
+	g.Wait()
+	if fetches.Load() != 2 {
+		t.Errorf("%d fetches, want 2: a running fetch should not start again", fetches.Load())
+	}
+
+	if !g.Do(context.Background(), "a", func() { fetches.Add(1) }) || fetches.Load() != 3 {
+		t.Errorf("Do() after the fetch finished = %d fetches, want a new one", fetches.Load())
+	}
+}
@@ -0,0 +1,38 @@
+package registry
+
+import "time"
+
+// FrameInterval is how long each frame of an animated section shows
+const FrameInterval = 500 * time.Millisecond
+
+// Animated is implemented by sections whose output moves, such as a spinner
+// for a running tool. RenderFrames renders the current output as frames that
+// show in turn, FrameInterval apart; a single frame is still output
+//
+// Render returns the frame due at the time of the call, so a single-shot
+// statusline shows a frame that depends only on the time. The refresh loop
+// keeps cycling the frames of the last render between refreshes
+type Animated interface {
+	RenderFrames() []string
+}
+
+// FrameAt returns the frame due at t; every renderer picks the same frame
+// at the same moment. Frames are rendered strings or styled text
+func FrameAt[F any](frames []F, t time.Time) F {
+	switch len(frames) {
+	case 0:
+		var none F
+		return none
+	case 1:
+		return frames[0]
+	}
+	return frames[int((t.UnixMilli()/FrameInterval.Milliseconds())%int64(len(frames)))]
+}
+
+// RenderFrames renders section's frames, its only output when it is not Animated
+func RenderFrames(section Section) []string {
+	if animated, ok := section.(Animated); ok {
+		return animated.RenderFrames()
+	}
+	return []string{section.Render()}
+}
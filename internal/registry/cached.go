@@ -0,0 +1,160 @@
+package registry
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+	"github.com/ll931217/claude-hud-enhanced/internal/styled"
+)
+
+// Cacheable is implemented by sections that declare how long their rendered
+// output stays valid. A TTL of zero disables caching.
+type Cacheable interface {
+	CacheTTL() time.Duration
+}
+
+// CachedSection wraps a Section and reuses its rendered output for ttl.
+// Once the cached output is stale it is still returned while a single
+// background render refreshes it, so slow sections never block the refresh loop.
+type CachedSection struct {
+	Section
+
+	ttl time.Duration
+
+	mu         sync.Mutex
+	output     cachedOutput
+	renderedAt time.Time
+	hasValue   bool
+	refreshing bool
+}
+
+// cachedOutput is what a CachedSection keeps of a render
+type cachedOutput struct {
+	frames   []string    // One frame unless the section is Animated
+	text     styled.Text // The section's own styled text, when it is a Styler
+	hasStyle bool
+}
+
+// NewCachedSection wraps section with a render cache of the given TTL
+func NewCachedSection(section Section, ttl time.Duration) *CachedSection {
+	return &CachedSection{
+		Section: section,
+		ttl:     ttl,
+	}
+}
+
+// WithCache wraps section in a CachedSection when ttl is positive,
+// otherwise the section is returned unchanged
+func WithCache(section Section, ttl time.Duration) Section {
+	if ttl <= 0 {
+		return section
+	}
+	if cached, ok := section.(*CachedSection); ok {
+		cached.mu.Lock()
+		cached.ttl = ttl
+		cached.mu.Unlock()
+		return cached
+	}
+	return NewCachedSection(section, ttl)
+}
+
+// Render returns the cached output, refreshing it when stale
+// The first render is synchronous; later stale renders refresh in the background
+func (c *CachedSection) Render() string {
+	return FrameAt(c.cached().frames, time.Now())
+}
+
+// RenderFrames returns the cached frames, refreshing them like Render
+func (c *CachedSection) RenderFrames() []string {
+	return c.cached().frames
+}
+
+// AppendRender implements Appender, appending the cached output
+func (c *CachedSection) AppendRender(dst []byte) []byte {
+	return append(dst, c.Render()...)
+}
+
+// RenderStyled implements Styler, returning the cached styled text of a
+// section that is a Styler and the cached output parsed otherwise
+func (c *CachedSection) RenderStyled() styled.Text {
+	output := c.cached()
+	if output.hasStyle {
+		return output.text
+	}
+	return styled.Parse(FrameAt(output.frames, time.Now()))
+}
+
+// cached returns the cached output, refreshing it when stale
+func (c *CachedSection) cached() cachedOutput {
+	c.mu.Lock()
+	if !c.hasValue {
+		c.mu.Unlock()
+		return c.refresh()
+	}
+
+	output := c.output
+	if time.Since(c.renderedAt) >= c.ttl && !c.refreshing {
+		c.refreshing = true
+		go c.refreshAsync()
+	}
+	c.mu.Unlock()
+
+	return output
+}
+
+// refresh renders the wrapped section and stores the result. A Styler's
+// output is its styled text with ANSI escapes, so it is rendered only once
+func (c *CachedSection) refresh() cachedOutput {
+	var output cachedOutput
+	_, animated := c.Section.(Animated)
+	if styler, ok := as[Styler](c.Section); ok && !animated {
+		output.text = styler.RenderStyled()
+		output.hasStyle = true
+		output.frames = []string{styled.Render(styled.ANSI{}, output.text)}
+	} else {
+		output.frames = RenderFrames(c.Section)
+	}
+
+	c.mu.Lock()
+	c.output = output
+	c.renderedAt = time.Now()
+	c.hasValue = true
+	c.mu.Unlock()
+
+	return output
+}
+
+// refreshAsync refreshes the cache in the background; a panic of the
+// section is logged and the previous output kept
+func (c *CachedSection) refreshAsync() {
+	defer func() {
+		if r := recover(); r != nil {
+			errors.LogErrorWithLevel(errors.PanicError("section."+c.Name(), r))
+		}
+		c.mu.Lock()
+		c.refreshing = false
+		c.mu.Unlock()
+	}()
+	c.refresh()
+}
+
+// Invalidate drops the cached output so the next Render is synchronous
+func (c *CachedSection) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hasValue = false
+	c.output = cachedOutput{}
+}
+
+// TTL returns the cache duration
+func (c *CachedSection) TTL() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ttl
+}
+
+// Unwrap returns the underlying section
+func (c *CachedSection) Unwrap() Section {
+	return c.Section
+}
@@ -0,0 +1,52 @@
+package registry
+
+import "time"
+
+// HealthState describes whether a section's data source is working
+type HealthState int
+
+const (
+	HealthOK          HealthState = iota // Data source read successfully (output may still be empty)
+	HealthDegraded                       // Data source exists but reading it failed
+	HealthUnavailable                    // Data source is missing (no transcript, not a git repo)
+)
+
+// String returns the lowercase state name
+func (s HealthState) String() string {
+	switch s {
+	case HealthOK:
+		return "ok"
+	case HealthDegraded:
+		return "degraded"
+	case HealthUnavailable:
+		return "unavailable"
+	default:
+		return "unknown"
+	}
+}
+
+// Health is the last health a section reported
+type Health struct {
+	State  HealthState
+	Reason string    // Short, human readable cause; empty when OK
+	Since  time.Time // When the section entered State
+}
+
+// OK reports whether the section is healthy
+func (h Health) OK() bool {
+	return h.State == HealthOK
+}
+
+// HealthReporter is implemented by sections that report why they render nothing
+type HealthReporter interface {
+	Health() Health
+}
+
+// HealthOf returns the health of a section, looking through wrappers such as
+// CachedSection. Sections that do not report health are assumed healthy
+func HealthOf(section Section) Health {
+	if reporter, ok := as[HealthReporter](section); ok {
+		return reporter.Health()
+	}
+	return Health{State: HealthOK}
+}
@@ -0,0 +1,103 @@
+package registry
+
+import (
+	"sort"
+)
+
+// Metadata describes a registered section type for introspection
+type Metadata struct {
+	Name         string
+	Description  string
+	Priority     Priority     // Default responsive priority
+	Options      []Option     // Section-specific options under sections.<name>
+	Dependencies []Dependency // Data sources the section reads
+}
+
+// Option documents a section-specific configuration option
+type Option struct {
+	Name        string
+	Type        string // bool, int, float, string, duration_ms, list
+	Default     string
+	Description string
+}
+
+// Dependency is a data source a section needs to show anything
+type Dependency struct {
+	Name string
+	// Check reports whether the data source is available for the workspace in dir
+	// A nil Check means the source is always available
+	Check func(dir string) bool
+}
+
+// Available reports whether the dependency is present for dir
+func (d Dependency) Available(dir string) bool {
+	return d.Check == nil || d.Check(dir)
+}
+
+// String returns the priority name
+func (p Priority) String() string {
+	switch p {
+	case PriorityEssential:
+		return "essential"
+	case PriorityImportant:
+		return "important"
+	case PriorityOptional:
+		return "optional"
+	default:
+		return "unset"
+	}
+}
+
+// RegisterWithMetadata registers a section type along with its metadata
+func (r *SectionRegistry) RegisterWithMetadata(name string, factory SectionFactory, meta Metadata) {
+	r.Register(name, factory)
+
+	meta.Name = name
+	r.mu.Lock()
+	r.metadata[name] = meta
+	r.mu.Unlock()
+}
+
+// Metadata returns the metadata for a section type
+// Sections registered without metadata report only their name
+func (r *SectionRegistry) Metadata(name string) (Metadata, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.factories[name]; !ok {
+		return Metadata{}, false
+	}
+	if meta, ok := r.metadata[name]; ok {
+		return meta, true
+	}
+	return Metadata{Name: name}, true
+}
+
+// All returns metadata for every registered section, sorted by name
+func (r *SectionRegistry) All() []Metadata {
+	names := r.List()
+	sort.Strings(names)
+
+	result := make([]Metadata, 0, len(names))
+	for _, name := range names {
+		if meta, ok := r.Metadata(name); ok {
+			result = append(result, meta)
+		}
+	}
+	return result
+}
+
+// RegisterWithMetadata registers a section type and its metadata with the default registry
+func RegisterWithMetadata(name string, factory SectionFactory, meta Metadata) {
+	defaultRegistry.RegisterWithMetadata(name, factory, meta)
+}
+
+// GetMetadata returns a section's metadata from the default registry
+func GetMetadata(name string) (Metadata, bool) {
+	return defaultRegistry.Metadata(name)
+}
+
+// All returns metadata for every section in the default registry
+func All() []Metadata {
+	return defaultRegistry.All()
+}
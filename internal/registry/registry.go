@@ -2,7 +2,10 @@ package registry
 
 import (
 	"fmt"
+	"strings"
 	"sync"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/render"
 )
 
 // SectionFactory is a function that creates a Section instance from configuration
@@ -12,11 +15,32 @@ type SectionFactory func(config interface{}) (Section, error)
 type SectionRegistry struct {
 	mu        sync.RWMutex
 	factories map[string]SectionFactory
+	metadata  map[string]Metadata
+	providers interface{}
+}
+
+// ProviderAware is implemented by sections that use shared data providers
+// The registry injects its providers into such sections on Create
+type ProviderAware interface {
+	SetProviders(providers interface{})
+}
+
+// Instanced is implemented by section types that can appear several times
+// in a layout, under names of the form "type:instance" (e.g. "custom:ci")
+// The registry passes the full name on Create; options are read under it
+type Instanced interface {
+	SetInstanceName(name string)
 }
 
 // global registry instance
-var defaultRegistry = &SectionRegistry{
-	factories: make(map[string]SectionFactory),
+var defaultRegistry = NewSectionRegistry()
+
+// NewSectionRegistry creates an empty registry
+func NewSectionRegistry() *SectionRegistry {
+	return &SectionRegistry{
+		factories: make(map[string]SectionFactory),
+		metadata:  make(map[string]Metadata),
+	}
 }
 
 // Register registers a new section type with the given name and factory function
@@ -33,28 +57,56 @@ func (r *SectionRegistry) Register(name string, factory SectionFactory) {
 
 // Create creates a new section instance of the specified type with the given configuration
 func (r *SectionRegistry) Create(name string, config interface{}) (Section, error) {
+	typeName, instance, _ := strings.Cut(name, ":")
 	r.mu.RLock()
-	factory, exists := r.factories[name]
+	factory, exists := r.factories[typeName]
 	r.mu.RUnlock()
 
 	if !exists {
 		return nil, fmt.Errorf("section type not registered: %s", name)
 	}
 
-	return factory(config)
+	section, err := factory(config)
+	if err != nil {
+		return nil, err
+	}
+	if instance != "" {
+		named, ok := section.(Instanced)
+		if !ok {
+			return nil, fmt.Errorf("section type %s cannot have instances: %s", typeName, name)
+		}
+		named.SetInstanceName(name)
+	}
+
+	r.mu.RLock()
+	providers := r.providers
+	r.mu.RUnlock()
+	if aware, ok := section.(ProviderAware); ok && providers != nil {
+		aware.SetProviders(providers)
+	}
+	return section, nil
 }
 
-// List returns a list of all registered section type names
-func (r *SectionRegistry) List() []string {
+// SetProviders sets the shared data providers injected into created sections
+func (r *SectionRegistry) SetProviders(providers interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers = providers
+}
+
+// Providers returns the shared data providers, or nil if none are set
+func (r *SectionRegistry) Providers() interface{} {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
+	return r.providers
+}
 
-	names := make([]string, 0, len(r.factories))
-	for name := range r.factories {
-		names = append(names, name)
-	}
+// List returns the names of all registered section types, sorted
+func (r *SectionRegistry) List() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	return names
+	return render.Keys(r.factories)
 }
 
 // Register registers a section type with the default registry
@@ -1,5 +1,7 @@
 package registry
 
+import "github.com/ll931217/claude-hud-enhanced/internal/styled"
+
 // Priority represents the display priority of a section for responsive layout
 type Priority int
 
@@ -30,3 +32,71 @@ type Section interface {
 	// MinWidth returns the minimum columns needed to display this section
 	MinWidth() int
 }
+
+// Appender is implemented by sections that can render by appending to a
+// buffer. The statusline assembles each frame in pooled buffers, so such
+// sections cost no string allocation per section per refresh
+type Appender interface {
+	// AppendRender appends what Render would return to dst
+	AppendRender(dst []byte) []byte
+}
+
+// AppendRender appends a section's output to dst, through Appender when the
+// section, or one it wraps, implements it
+func AppendRender(dst []byte, section Section) []byte {
+	if a, ok := as[Appender](section); ok {
+		return a.AppendRender(dst)
+	}
+	return append(dst, section.Render()...)
+}
+
+// Describer is implemented by sections whose output is graphical, such as a
+// progress bar, to put it in words for screen readers
+type Describer interface {
+	// Describe returns the section's state as labeled text, e.g.
+	// "context: 72 percent", or "" when Render would show nothing
+	Describe() string
+}
+
+// DescriberOf returns the Describer of a section, looking through wrappers
+// such as CachedSection
+func DescriberOf(section Section) (Describer, bool) {
+	return as[Describer](section)
+}
+
+// Styler is implemented by sections that build their output as styled text,
+// which keeps what is shown apart from how, so it can be shown on outputs
+// other than a terminal. Such a section's Render returns the text with ANSI
+// escapes, styled.Render(styled.ANSI{}, RenderStyled())
+type Styler interface {
+	// RenderStyled returns what Render would show, as styled text
+	RenderStyled() styled.Text
+}
+
+// RenderStyled returns a section's output as styled text, through Styler
+// when the section, or one it wraps, implements it and by parsing Render's
+// escapes otherwise
+func RenderStyled(section Section) styled.Text {
+	if s, ok := as[Styler](section); ok {
+		return s.RenderStyled()
+	}
+	return styled.Parse(section.Render())
+}
+
+// as returns section as a T, looking through wrappers such as CachedSection
+// until one implements T. Wrappers that change the output, as CachedSection
+// does, implement the output interfaces themselves so they are found first
+func as[T any](section Section) (T, bool) {
+	for section != nil {
+		if t, ok := section.(T); ok {
+			return t, true
+		}
+		wrapper, ok := section.(interface{ Unwrap() Section })
+		if !ok {
+			break
+		}
+		section = wrapper.Unwrap()
+	}
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,45 @@
+// Package render puts map data in a stable order for display. Go randomizes
+// map iteration, so output built by ranging over a map reorders itself from
+// one refresh to the next and the statusline flickers; whatever shows data
+// held in a map goes through these helpers instead
+package render
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"time"
+)
+
+// Keys returns m's keys in ascending order
+func Keys[M ~map[K]V, K cmp.Ordered, V any](m M) []K {
+	return slices.Sorted(maps.Keys(m))
+}
+
+// Values returns m's values ordered by compare; values that compare equal
+// keep the order of their keys
+func Values[M ~map[K]V, K cmp.Ordered, V any](m M, compare func(a, b V) int) []V {
+	keys := Keys(m)
+	values := make([]V, len(keys))
+	for i, key := range keys {
+		values[i] = m[key]
+	}
+	slices.SortStableFunc(values, compare)
+	return values
+}
+
+// Sort sorts s by compare, breaking ties by name, so elements that compare
+// equal never swap places between refreshes
+func Sort[S ~[]E, E any](s S, compare func(a, b E) int, name func(E) string) {
+	slices.SortFunc(s, func(a, b E) int {
+		if c := compare(a, b); c != 0 {
+			return c
+		}
+		return cmp.Compare(name(a), name(b))
+	})
+}
+
+// Newest compares times for sorting most recent first
+func Newest(a, b time.Time) int {
+	return b.Compare(a)
+}
@@ -0,0 +1,63 @@
+package render
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKeys(t *testing.T) {
+	m := map[string]int{"tools": 1, "agents": 2, "model": 3}
+	for i := 0; i < 20; i++ {
+		if got := strings.Join(Keys(m), ","); got != "agents,model,tools" {
+			t.Fatalf("Keys() = %s, want agents,model,tools", got)
+		}
+	}
+}
+
+func TestValues(t *testing.T) {
+	type tool struct {
+		name  string
+		count int
+	}
+	m := map[string]tool{
+		"Read": {"Read", 3},
+		"Edit": {"Edit", 5},
+		"Bash": {"Bash", 3},
+		"Grep": {"Grep", 3},
+	}
+	byCount := func(a, b tool) int { return b.count - a.count }
+	for i := 0; i < 20; i++ {
+		var names []string
+		for _, v := range Values(m, byCount) {
+			names = append(names, v.name)
+		}
+		// Equal counts in key order
+		if got := strings.Join(names, ","); got != "Edit,Bash,Grep,Read" {
+			t.Fatalf("Values() = %s, want Edit,Bash,Grep,Read", got)
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	now := time.Now()
+	type agent struct {
+		name string
+		seen time.Time
+	}
+	agents := []agent{
+		{"Review", now.Add(-time.Minute)},
+		{"Plan", now},
+		{"Arch", now.Add(-time.Minute)},
+		{"Docs", now},
+	}
+	Sort(agents, func(a, b agent) int { return Newest(a.seen, b.seen) }, func(a agent) string { return a.name })
+
+	var names []string
+	for _, a := range agents {
+		names = append(names, a.name)
+	}
+	if got := strings.Join(names, ","); got != "Docs,Plan,Arch,Review" {
+		t.Errorf("Sort() = %s, want Docs,Plan,Arch,Review", got)
+	}
+}
@@ -0,0 +1,205 @@
+// Package report renders a shareable report of one session, as Markdown or a
+// standalone HTML page, for attaching to a pull request or a standup: the
+// session's digest with charts of its token usage over time and the tools it
+// called
+package report
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"maps"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/digest"
+	"github.com/ll931217/claude-hud-enhanced/internal/export"
+	"github.com/ll931217/claude-hud-enhanced/internal/humanize"
+	"github.com/ll931217/claude-hud-enhanced/internal/store"
+)
+
+// Supported report formats
+const (
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+)
+
+// maxBuckets is the most bars the token usage chart has
+const maxBuckets = 24
+
+// barWidth is the width of the longest bar of a Markdown chart, in cells
+const barWidth = 30
+
+// intervals are the bucket sizes the token usage chart picks from, smallest first
+var intervals = []time.Duration{
+	time.Minute, 5 * time.Minute, 10 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+	time.Hour, 2 * time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour, humanize.Day,
+}
+
+//go:embed templates
+var templates embed.FS
+
+// Bar is one bar of a chart
+type Bar struct {
+	Label string
+	Value int
+	Share float64 // Value over the chart's largest value, from 0 to 1
+	Note  string  // Shown after the value, e.g. "$0.41" or "2 errors"
+}
+
+// Percent is the bar's share as a percentage, for sizing it in HTML
+func (b Bar) Percent() string {
+	return fmt.Sprintf("%.1f", b.Share*100)
+}
+
+// Report is everything a session report shows
+type Report struct {
+	digest.Digest
+	Generated time.Time
+	Interval  time.Duration // Span of each bar of the token chart
+	Tokens    []Bar         // Input and output tokens per interval, with their cost
+	Tools     []Bar         // Calls per tool, most called first, with their errors
+}
+
+// Build reads a session's report from its transcript
+func Build(ctx context.Context, path string) (*Report, error) {
+	d, err := digest.FromTranscript(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	ds, err := export.Collect(ctx, []string{path}, store.Filter{})
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Report{Digest: d, Generated: time.Now()}
+	r.Interval, r.Tokens = tokenChart(ds.Events)
+	r.Tools = toolChart(ds.Tools)
+	return r, nil
+}
+
+// Write renders the report to w in the given format
+func Write(w io.Writer, r *Report, format string) error {
+	switch format {
+	case FormatMarkdown:
+		tmpl, err := texttemplate.New("report.md.tmpl").Funcs(texttemplate.FuncMap(funcs)).ParseFS(templates, "templates/report.md.tmpl")
+		if err != nil {
+			return err
+		}
+		return tmpl.Execute(w, r)
+	case FormatHTML:
+		tmpl, err := htmltemplate.New("report.html.tmpl").Funcs(htmltemplate.FuncMap(funcs)).ParseFS(templates, "templates/report.html.tmpl")
+		if err != nil {
+			return err
+		}
+		return tmpl.Execute(w, r)
+	default:
+		return fmt.Errorf("unknown format %q (want md or html)", format)
+	}
+}
+
+// funcs are the helpers both templates use: the digest's, and bar
+var funcs = func() map[string]any {
+	funcs := maps.Clone(digest.Funcs)
+	funcs["bar"] = bar
+	return funcs
+}()
+
+// bar draws a bar of a Markdown chart, e.g. "██████▌"
+func bar(share float64) string {
+	eighths := int(share*barWidth*8 + 0.5)
+	text := strings.Repeat("█", eighths/8)
+	if partial := eighths % 8; partial > 0 {
+		text += string(rune('█' + 8 - partial)) // ▉ is one eighth less than █, and so on down to ▏
+	}
+	if text == "" && share > 0 {
+		text = "▏"
+	}
+	return text
+}
+
+// tokenChart buckets the tokens of the session's messages into at most
+// maxBuckets intervals from its first message to its last, empty ones included
+func tokenChart(events []store.Event) (time.Duration, []Bar) {
+	var first, last time.Time
+	for _, ev := range events {
+		if ev.Kind != store.KindMessage {
+			continue
+		}
+		if first.IsZero() {
+			first = ev.Timestamp
+		}
+		last = ev.Timestamp
+	}
+	if first.IsZero() {
+		return 0, nil
+	}
+
+	interval := intervals[len(intervals)-1]
+	for _, size := range intervals {
+		if last.Sub(first.Truncate(size)) < maxBuckets*size {
+			interval = size
+			break
+		}
+	}
+	start := first.Truncate(interval)
+	n := int(last.Sub(start)/interval) + 1
+
+	tokens := make([]int, n)
+	costs := make([]float64, n)
+	for _, ev := range events {
+		if ev.Kind != store.KindMessage {
+			continue
+		}
+		i := min(int(ev.Timestamp.Sub(start)/interval), n-1)
+		tokens[i] += ev.InputTokens + ev.OutputTokens
+		costs[i] += ev.Cost
+	}
+
+	layout := "15:04"
+	if last.Sub(start) >= humanize.Day {
+		layout = "Jan 2 15:04"
+	}
+	bars := make([]Bar, n)
+	for i := range bars {
+		bars[i] = Bar{
+			Label: start.Add(time.Duration(i) * interval).Local().Format(layout),
+			Value: tokens[i],
+			Note:  fmt.Sprintf("$%.2f", costs[i]),
+		}
+	}
+	return interval, scale(bars)
+}
+
+// toolChart charts the calls to each tool, as export orders them
+func toolChart(tools []export.ToolUsage) []Bar {
+	bars := make([]Bar, len(tools))
+	for i, tool := range tools {
+		bars[i] = Bar{Label: tool.ToolName, Value: tool.Calls}
+		switch {
+		case tool.Errors == 1:
+			bars[i].Note = "1 error"
+		case tool.Errors > 1:
+			bars[i].Note = fmt.Sprintf("%d errors", tool.Errors)
+		}
+	}
+	return scale(bars)
+}
+
+// scale sets each bar's share of the largest
+func scale(bars []Bar) []Bar {
+	largest := 0
+	for _, b := range bars {
+		largest = max(largest, b.Value)
+	}
+	if largest == 0 {
+		return bars
+	}
+	for i := range bars {
+		bars[i].Share = float64(bars[i].Value) / float64(largest)
+	}
+	return bars
+}
@@ -0,0 +1,150 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/export"
+	"github.com/ll931217/claude-hud-enhanced/internal/store"
+)
+
+const testTranscript = `{"type":"assistant","timestamp":"2026-03-01T10:00:00.000Z","message":{"role":"assistant","model":"claude-sonnet-4","usage":{"input_tokens":1000,"output_tokens":200},"content":[{"type":"tool_use","id":"t1","name":"Edit","input":{"file_path":"/p/<b>.go"}}]}}
+{"type":"user","timestamp":"2026-03-01T10:00:01.000Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1"}]}}
+{"type":"assistant","timestamp":"2026-03-01T10:40:00.000Z","message":{"role":"assistant","model":"claude-sonnet-4","usage":{"input_tokens":2000,"output_tokens":400},"content":[{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"go test ./..."}}]}}
+{"type":"user","timestamp":"2026-03-01T10:40:05.000Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t2","is_error":true}]}}
+`
+
+func writeTranscript(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "abc-12345678.jsonl")
+	if err := os.WriteFile(path, []byte(testTranscript), 0644); err != nil {
+		t.Fatal(err)
+	}
+	end := time.Date(2026, 3, 1, 10, 40, 5, 0, time.UTC)
+	if err := os.Chtimes(path, end, end); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestBuild(t *testing.T) {
+	r, err := Build(context.Background(), writeTranscript(t))
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if r.Interval != 5*time.Minute || len(r.Tokens) != 9 {
+		t.Fatalf("token chart = %d bars of %v, want 9 of 5m", len(r.Tokens), r.Interval)
+	}
+	if first, last := r.Tokens[0], r.Tokens[8]; first.Value != 1200 || last.Value != 2400 || last.Share != 1 || first.Share != 0.5 {
+		t.Errorf("token bars = %+v ... %+v", first, last)
+	}
+	if r.Tokens[4].Value != 0 {
+		t.Errorf("an idle interval has %d tokens, want 0", r.Tokens[4].Value)
+	}
+	if len(r.Tools) != 2 || r.Tools[0].Note == r.Tools[1].Note {
+		t.Errorf("tool chart = %+v", r.Tools)
+	}
+}
+
+func TestTokenChart_Interval(t *testing.T) {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		span time.Duration
+		want time.Duration
+	}{
+		{span: 10 * time.Minute, want: time.Minute},
+		{span: 90 * time.Minute, want: 5 * time.Minute},
+		{span: 5 * time.Hour, want: 15 * time.Minute},
+		{span: 30 * time.Hour, want: 2 * time.Hour},
+	}
+	for _, tt := range tests {
+		events := []store.Event{
+			{Kind: store.KindMessage, Timestamp: start, InputTokens: 1},
+			{Kind: store.KindMessage, Timestamp: start.Add(tt.span), InputTokens: 1},
+		}
+		interval, bars := tokenChart(events)
+		if interval != tt.want {
+			t.Errorf("interval for %v = %v, want %v", tt.span, interval, tt.want)
+		}
+		if len(bars) > maxBuckets {
+			t.Errorf("%d bars for %v, want at most %d", len(bars), tt.span, maxBuckets)
+		}
+	}
+
+	if interval, bars := tokenChart(nil); interval != 0 || bars != nil {
+		t.Errorf("tokenChart(nil) = %v, %v", interval, bars)
+	}
+}
+
+func TestToolChart(t *testing.T) {
+	bars := toolChart([]export.ToolUsage{
+		{ToolName: "Bash", Calls: 4, Errors: 2},
+		{ToolName: "Read", Calls: 2, Errors: 1},
+		{ToolName: "Edit", Calls: 1},
+	})
+	want := []Bar{
+		{Label: "Bash", Value: 4, Share: 1, Note: "2 errors"},
+		{Label: "Read", Value: 2, Share: 0.5, Note: "1 error"},
+		{Label: "Edit", Value: 1, Share: 0.25},
+	}
+	for i := range want {
+		if bars[i] != want[i] {
+			t.Errorf("bar %d = %+v, want %+v", i, bars[i], want[i])
+		}
+	}
+}
+
+func TestBar(t *testing.T) {
+	tests := []struct {
+		share float64
+		want  string
+	}{
+		{share: 0, want: ""},
+		{share: 0.001, want: "▏"},
+		{share: 0.05, want: "█▌"},
+		{share: 1, want: strings.Repeat("█", barWidth)},
+	}
+	for _, tt := range tests {
+		if got := bar(tt.share); got != tt.want {
+			t.Errorf("bar(%v) = %q, want %q", tt.share, got, tt.want)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	r, err := Build(context.Background(), writeTranscript(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Name = "auth-refactor"
+
+	var md bytes.Buffer
+	if err := Write(&md, r, FormatMarkdown); err != nil {
+		t.Fatalf("Write(md) error = %v", err)
+	}
+	for _, want := range []string{"# Session report: auth-refactor", "| Duration | 40m5s |", "## Token usage over time", "Bash", "1 error", "- `/p/<b>.go`"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("Markdown report is missing %q:\n%s", want, md.String())
+		}
+	}
+
+	var html bytes.Buffer
+	if err := Write(&html, r, FormatHTML); err != nil {
+		t.Fatalf("Write(html) error = %v", err)
+	}
+	for _, want := range []string{"<title>Session report: auth-refactor</title>", `style="height: 100.0%"`, "/p/&lt;b&gt;.go"} {
+		if !strings.Contains(html.String(), want) {
+			t.Errorf("HTML report is missing %q", want)
+		}
+	}
+
+	if err := Write(&html, r, "pdf"); err == nil {
+		t.Error("Write() of an unknown format succeeded")
+	}
+}
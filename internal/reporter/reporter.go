@@ -0,0 +1,323 @@
+// Package reporter sends anonymized usage and cost summaries to a team
+// endpoint so spend can be aggregated across developers
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+	"github.com/ll931217/claude-hud-enhanced/internal/export"
+	"github.com/ll931217/claude-hud-enhanced/internal/store"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+// APIKeyEnv is the environment variable consulted when no api_key is configured
+const APIKeyEnv = "CLAUDE_HUD_REPORTER_API_KEY"
+
+const (
+	// maxPending bounds how many session summaries wait for delivery
+	// Beyond this the oldest summaries are dropped
+	maxPending = 500
+
+	// requestTimeout bounds a single delivery attempt
+	requestTimeout = 10 * time.Second
+)
+
+// Summary is the anonymized usage of one session
+// It carries no paths, prompts, tool targets or raw session IDs
+type Summary struct {
+	Session             string             `json:"session"` // Hashed session ID
+	Day                 string             `json:"day"`     // YYYY-MM-DD (UTC) of the last activity
+	Messages            int                `json:"messages"`
+	ToolCalls           int                `json:"tool_calls"`
+	ToolErrors          int                `json:"tool_errors"`
+	InputTokens         int                `json:"input_tokens"`
+	OutputTokens        int                `json:"output_tokens"`
+	CacheCreationTokens int                `json:"cache_creation_tokens"`
+	CacheReadTokens     int                `json:"cache_read_tokens"`
+	Cost                float64            `json:"cost"`
+	CostByModel         map[string]float64 `json:"cost_by_model,omitempty"`
+	UserTurns           int                `json:"user_turns"`
+	AssistantTurns      int                `json:"assistant_turns"`
+	AvgTokensPerTurn    int                `json:"avg_tokens_per_turn"`
+	LongestTurnMs       int64              `json:"longest_turn_ms"`
+	Interrupted         int                `json:"interrupted"`   // Turns the user interrupted
+	AbortedTools        int                `json:"aborted_tools"` // Tool calls rejected or left without a result
+	UpdatedAt           time.Time          `json:"updated_at"`
+}
+
+// Batch is the payload POSTed to the endpoint
+type Batch struct {
+	Developer string    `json:"developer"`
+	SentAt    time.Time `json:"sent_at"`
+	Summaries []Summary `json:"summaries"`
+}
+
+// Reporter collects session summaries and delivers them in batches
+// Record never blocks on the network; delivery happens in Run
+type Reporter struct {
+	endpoint  string
+	apiKey    string
+	developer string
+	interval  time.Duration
+	client    *http.Client
+
+	mu      sync.Mutex
+	pending map[string]Summary
+	order   []string
+}
+
+// New creates a reporter from configuration
+// Returns nil when reporting is disabled or no endpoint is configured
+func New(cfg config.ReporterConfig) *Reporter {
+	if !cfg.Enabled || cfg.Endpoint == "" {
+		return nil
+	}
+
+	apiKey := cfg.APIKey
+	if apiKey == "" {
+		apiKey = os.Getenv(APIKeyEnv)
+	}
+
+	developer := cfg.Developer
+	if developer == "" {
+		developer = Anonymize(localIdentity())
+	}
+
+	interval := time.Duration(cfg.IntervalMs) * time.Millisecond
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	return &Reporter{
+		endpoint:  cfg.Endpoint,
+		apiKey:    apiKey,
+		developer: developer,
+		interval:  interval,
+		client:    &http.Client{Timeout: requestTimeout},
+		pending:   make(map[string]Summary),
+	}
+}
+
+// Anonymize returns a stable, non-reversible identifier for value
+func Anonymize(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:8])
+}
+
+// localIdentity returns user@host for deriving the anonymous developer ID
+func localIdentity() string {
+	name := "unknown"
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	host, _ := os.Hostname()
+	return name + "@" + host
+}
+
+// Summarize builds the anonymized summary of a session's events
+func Summarize(sessionID string, ds *export.Dataset) Summary {
+	s := Summary{
+		Session:     Anonymize(sessionID),
+		CostByModel: make(map[string]float64),
+	}
+
+	var last time.Time
+	for _, ev := range ds.Events {
+		if ev.Timestamp.After(last) {
+			last = ev.Timestamp
+		}
+		if ev.Kind == store.KindMessage {
+			s.Messages++
+		}
+	}
+	for _, row := range ds.Tools {
+		s.ToolCalls += row.Calls
+		s.ToolErrors += row.Errors
+	}
+	for _, row := range ds.Tokens {
+		s.InputTokens += row.InputTokens
+		s.OutputTokens += row.OutputTokens
+		s.CacheCreationTokens += row.CacheCreationTokens
+		s.CacheReadTokens += row.CacheReadTokens
+		s.Cost += row.Cost
+		if row.Model != "" {
+			s.CostByModel[row.Model] += row.Cost
+		}
+	}
+
+	if last.IsZero() {
+		last = time.Now()
+	}
+	s.Day = last.UTC().Format("2006-01-02")
+	s.UpdatedAt = last.UTC()
+	return s
+}
+
+// SummarizeTranscript reads a transcript and summarizes it
+func SummarizeTranscript(ctx context.Context, transcriptPath string) (Summary, error) {
+	ds, err := export.Collect(ctx, []string{transcriptPath}, store.Filter{})
+	if err != nil {
+		return Summary{}, err
+	}
+	s := Summarize(store.SessionIDFromPath(transcriptPath), ds)
+
+	parser := transcript.NewParser(transcriptPath)
+	if err := parser.Parse(ctx); err != nil {
+		return Summary{}, err
+	}
+	s.SetTurns(parser.GetTurnStats())
+	return s, nil
+}
+
+// SetTurns copies turn statistics into the summary
+func (s *Summary) SetTurns(turns transcript.TurnStats) {
+	s.UserTurns = turns.UserTurns
+	s.AssistantTurns = turns.AssistantTurns
+	s.AvgTokensPerTurn = turns.AverageTokensPerTurn()
+	s.LongestTurnMs = turns.LongestTurn.Milliseconds()
+	s.Interrupted = turns.Interrupted
+	s.AbortedTools = turns.AbortedTools
+}
+
+// Record queues a summary for the next batch
+// A newer summary for the same session replaces the queued one
+func (r *Reporter) Record(s Summary) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.pending[s.Session]; !ok {
+		r.order = append(r.order, s.Session)
+	}
+	r.pending[s.Session] = s
+
+	for len(r.order) > maxPending {
+		delete(r.pending, r.order[0])
+		r.order = r.order[1:]
+	}
+}
+
+// Pending returns the number of queued summaries
+func (r *Reporter) Pending() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.pending)
+}
+
+// take removes and returns all queued summaries
+func (r *Reporter) take() []Summary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	summaries := make([]Summary, 0, len(r.order))
+	for _, id := range r.order {
+		summaries = append(summaries, r.pending[id])
+	}
+	r.pending = make(map[string]Summary)
+	r.order = nil
+	return summaries
+}
+
+// requeue puts undelivered summaries back unless a newer one arrived meanwhile
+func (r *Reporter) requeue(summaries []Summary) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var order []string
+	for _, s := range summaries {
+		if _, ok := r.pending[s.Session]; ok {
+			continue
+		}
+		r.pending[s.Session] = s
+		order = append(order, s.Session)
+	}
+	r.order = append(order, r.order...)
+
+	for len(r.order) > maxPending {
+		delete(r.pending, r.order[0])
+		r.order = r.order[1:]
+	}
+}
+
+// Flush sends all queued summaries in one request
+// On failure the summaries are kept for the next attempt
+func (r *Reporter) Flush(ctx context.Context) error {
+	summaries := r.take()
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	if err := r.send(ctx, summaries); err != nil {
+		r.requeue(summaries)
+		return err
+	}
+
+	errors.Debug("reporter", "sent %d summaries", len(summaries))
+	return nil
+}
+
+// send POSTs a batch to the endpoint
+func (r *Reporter) send(ctx context.Context, summaries []Summary) error {
+	body, err := json.Marshal(Batch{
+		Developer: r.developer,
+		SentAt:    time.Now().UTC(),
+		Summaries: summaries,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode batch: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+r.apiKey)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send batch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Run flushes queued summaries every interval until ctx is cancelled,
+// then makes one final attempt
+func (r *Reporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			if err := r.Flush(flushCtx); err != nil {
+				errors.Warn("reporter", "final flush failed: %v", err)
+			}
+			cancel()
+			return
+		case <-ticker.C:
+			if err := r.Flush(ctx); err != nil {
+				errors.Warn("reporter", "%v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package reporter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/export"
+	"github.com/ll931217/claude-hud-enhanced/internal/store"
+)
+
+// sink records batches received by a test endpoint
+type sink struct {
+	mu      sync.Mutex
+	batches []Batch
+	auth    string
+	status  int
+}
+
+func (s *sink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.auth = r.Header.Get("Authorization")
+	if s.status != 0 {
+		w.WriteHeader(s.status)
+		return
+	}
+	var b Batch
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.batches = append(s.batches, b)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func newTestReporter(t *testing.T, endpoint string) *Reporter {
+	t.Helper()
+	r := New(config.ReporterConfig{
+		Enabled:    true,
+		Endpoint:   endpoint,
+		APIKey:     "secret",
+		IntervalMs: 60000,
+		Developer:  "alice",
+	})
+	if r == nil {
+		t.Fatal("New() returned nil for enabled config")
+	}
+	return r
+}
+
+func TestNew_Disabled(t *testing.T) {
+	if New(config.ReporterConfig{Endpoint: "http://example.invalid"}) != nil {
+		t.Error("disabled reporter should be nil")
+	}
+	if New(config.ReporterConfig{Enabled: true}) != nil {
+		t.Error("reporter without endpoint should be nil")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	ts := time.Date(2026, 1, 11, 10, 5, 0, 0, time.UTC)
+	ds := &export.Dataset{
+		Events: []store.Event{
+			{SessionID: "abc", Kind: store.KindMessage, Timestamp: ts},
+			{SessionID: "abc", Kind: store.KindToolUse, Timestamp: ts, Target: "/secret/path"},
+		},
+		Tools: []export.ToolUsage{{SessionID: "abc", ToolName: "Bash", Calls: 3, Errors: 1}},
+		Tokens: []export.TokenUsage{
+			{SessionID: "abc", Model: "claude-sonnet-4", InputTokens: 100, OutputTokens: 10, Cost: 0.5},
+			{SessionID: "abc", Model: "claude-opus-4", InputTokens: 50, Cost: 1.5},
+		},
+	}
+
+	s := Summarize("abc", ds)
+	if s.Session == "abc" || s.Session != Anonymize("abc") {
+		t.Errorf("session should be anonymized, got %q", s.Session)
+	}
+	if s.Messages != 1 || s.ToolCalls != 3 || s.ToolErrors != 1 {
+		t.Errorf("counts = %+v", s)
+	}
+	if s.InputTokens != 150 || s.Cost != 2.0 || s.CostByModel["claude-opus-4"] != 1.5 {
+		t.Errorf("usage = %+v", s)
+	}
+	if s.Day != "2026-01-11" {
+		t.Errorf("day = %q", s.Day)
+	}
+
+	data, _ := json.Marshal(s)
+	if strings.Contains(string(data), "/secret/path") {
+		t.Error("summary must not contain tool targets")
+	}
+}
+
+func TestSummarizeTranscript_Turns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "abc.jsonl")
+	lines := strings.Join([]string{
+		`{"type":"user","sessionId":"abc","timestamp":"2026-01-11T10:00:00Z","message":{"role":"user","content":"fix the build"}}`,
+		`{"type":"assistant","sessionId":"abc","timestamp":"2026-01-11T10:00:30Z","message":{"id":"msg_1","role":"assistant","model":"claude-sonnet-4","content":[{"type":"text","text":"Fixed"}],"usage":{"input_tokens":300,"output_tokens":100}}}`,
+		`{"type":"user","sessionId":"abc","timestamp":"2026-01-11T10:01:00Z","message":{"role":"user","content":"thanks"}}`,
+	}, "\n") + "\n"
+	if err := os.WriteFile(path, []byte(lines), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := SummarizeTranscript(context.Background(), path)
+	if err != nil {
+		t.Fatalf("SummarizeTranscript() error = %v", err)
+	}
+	if s.UserTurns != 2 || s.AssistantTurns != 1 || s.AvgTokensPerTurn != 400 || s.LongestTurnMs != 30000 {
+		t.Errorf("turns = %+v", s)
+	}
+}
+
+func TestReporter_Flush(t *testing.T) {
+	sk := &sink{}
+	srv := httptest.NewServer(sk)
+	defer srv.Close()
+
+	r := newTestReporter(t, srv.URL)
+	r.Record(Summary{Session: "s1", Cost: 1})
+	r.Record(Summary{Session: "s2", Cost: 2})
+	r.Record(Summary{Session: "s1", Cost: 3}) // replaces the queued s1
+
+	if err := r.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	sk.mu.Lock()
+	defer sk.mu.Unlock()
+	if sk.auth != "Bearer secret" {
+		t.Errorf("Authorization = %q", sk.auth)
+	}
+	if len(sk.batches) != 1 {
+		t.Fatalf("batches = %d, want 1", len(sk.batches))
+	}
+	b := sk.batches[0]
+	if b.Developer != "alice" || len(b.Summaries) != 2 {
+		t.Fatalf("batch = %+v", b)
+	}
+	if b.Summaries[0].Session != "s1" || b.Summaries[0].Cost != 3 {
+		t.Errorf("first summary = %+v", b.Summaries[0])
+	}
+	if r.Pending() != 0 {
+		t.Errorf("Pending() = %d after flush", r.Pending())
+	}
+
+	// Nothing queued: no request
+	if err := r.Flush(context.Background()); err != nil || len(sk.batches) != 1 {
+		t.Errorf("empty Flush() sent a batch (err=%v)", err)
+	}
+}
+
+func TestReporter_FlushFailureRequeues(t *testing.T) {
+	sk := &sink{status: http.StatusInternalServerError}
+	srv := httptest.NewServer(sk)
+	defer srv.Close()
+
+	r := newTestReporter(t, srv.URL)
+	r.Record(Summary{Session: "s1", Cost: 1})
+
+	if err := r.Flush(context.Background()); err == nil {
+		t.Fatal("Flush() should fail on 500")
+	}
+	if r.Pending() != 1 {
+		t.Errorf("Pending() = %d, want 1 after failure", r.Pending())
+	}
+}
+
+func TestReporter_RecordBounded(t *testing.T) {
+	r := newTestReporter(t, "http://127.0.0.1:0")
+	for i := 0; i < maxPending+10; i++ {
+		r.Record(Summary{Session: Anonymize(string(rune(i)))})
+	}
+	if r.Pending() != maxPending {
+		t.Errorf("Pending() = %d, want %d", r.Pending(), maxPending)
+	}
+}
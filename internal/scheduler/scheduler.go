@@ -0,0 +1,180 @@
+// Package scheduler runs the daemon's recurring background jobs, such as
+// cache refreshes and the transcript janitor, each on its own interval. A
+// random jitter spreads runs out so jobs and daemons do not fire together,
+// and a job still running when it is due again is skipped rather than run
+// twice at once
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+)
+
+// Job is a piece of recurring work
+type Job struct {
+	Name     string
+	Interval time.Duration // Time between the starts of two runs
+	Jitter   time.Duration // Each run starts up to this much late, at random
+	Run      func(ctx context.Context) error
+}
+
+// Status is how a job has been doing
+type Status struct {
+	Name     string
+	Runs     int       // Runs finished
+	Failures int       // Runs that returned an error
+	Skipped  int       // Runs skipped because the last was still going
+	Running  bool      // Whether a run is going now
+	LastRun  time.Time // When the last run started
+	LastErr  string    // Error of the last run, if it failed
+	Next     time.Time // When the next run is due
+}
+
+// entry is a scheduled job with its status
+type entry struct {
+	job    Job
+	status Status
+}
+
+// Scheduler runs jobs on their intervals
+type Scheduler struct {
+	mu      sync.Mutex
+	entries []*entry
+	running sync.WaitGroup
+}
+
+// New creates a scheduler without jobs
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Add schedules a job. Jobs must be added before Run
+func (s *Scheduler) Add(job Job) error {
+	if job.Interval <= 0 {
+		return fmt.Errorf("job %s: interval must be positive", job.Name)
+	}
+	if job.Run == nil {
+		return fmt.Errorf("job %s: nothing to run", job.Name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.entries {
+		if e.job.Name == job.Name {
+			return fmt.Errorf("job %s is already scheduled", job.Name)
+		}
+	}
+	s.entries = append(s.entries, &entry{job: job, status: Status{Name: job.Name}})
+	return nil
+}
+
+// Len returns the number of jobs scheduled
+func (s *Scheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// Run runs each job once right away, after its jitter, and then every
+// interval, until ctx is cancelled. It returns once the runs in progress
+// have seen the cancellation and finished
+func (s *Scheduler) Run(ctx context.Context) {
+	s.mu.Lock()
+	entries := append([]*entry(nil), s.entries...)
+	s.mu.Unlock()
+
+	var loops sync.WaitGroup
+	for _, e := range entries {
+		loops.Add(1)
+		errors.SafeGo("scheduler", func() {
+			defer loops.Done()
+			s.loop(ctx, e)
+		})
+	}
+	loops.Wait()
+	s.running.Wait()
+}
+
+// Statuses returns the status of every job, in the order they were added
+func (s *Scheduler) Statuses() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := make([]Status, len(s.entries))
+	for i, e := range s.entries {
+		statuses[i] = e.status
+	}
+	return statuses
+}
+
+// loop starts a job's runs until ctx is cancelled
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	due := time.Now() // When the run is due, before its jitter
+	for {
+		next := due.Add(jitter(e.job.Jitter))
+		s.mu.Lock()
+		e.status.Next = next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.start(ctx, e)
+		due = due.Add(e.job.Interval)
+		if now := time.Now(); due.Before(now) {
+			due = now // Fell behind, e.g. after the machine slept; don't catch up
+		}
+	}
+}
+
+// start runs a job in the background, unless its last run is still going
+func (s *Scheduler) start(ctx context.Context, e *entry) {
+	s.mu.Lock()
+	if e.status.Running {
+		e.status.Skipped++
+		s.mu.Unlock()
+		errors.Debug("scheduler", "job %s is still running; skipping this run", e.job.Name)
+		return
+	}
+	e.status.Running = true
+	e.status.LastRun = time.Now()
+	s.mu.Unlock()
+
+	s.running.Add(1)
+	errors.SafeGo("scheduler."+e.job.Name, func() {
+		defer s.running.Done()
+		err := fmt.Errorf("panicked") // Until Run returns
+		defer func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			e.status.Running = false
+			e.status.Runs++
+			e.status.LastErr = ""
+			if err != nil {
+				e.status.Failures++
+				e.status.LastErr = err.Error()
+			}
+		}()
+
+		if err = e.job.Run(ctx); err != nil && ctx.Err() == nil {
+			errors.Warn("scheduler", "job %s: %v", e.job.Name, err)
+		}
+	})
+}
+
+// jitter returns a random delay below limit
+func jitter(limit time.Duration) time.Duration {
+	if limit <= 0 {
+		return 0
+	}
+	return rand.N(limit)
+}
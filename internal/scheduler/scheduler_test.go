@@ -0,0 +1,115 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAdd(t *testing.T) {
+	s := New()
+	run := func(context.Context) error { return nil }
+
+	if err := s.Add(Job{Name: "refresh", Interval: time.Minute, Run: run}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := s.Add(Job{Name: "refresh", Interval: time.Minute, Run: run}); err == nil {
+		t.Error("Add() of a duplicate name succeeded")
+	}
+	if err := s.Add(Job{Name: "never", Run: run}); err == nil {
+		t.Error("Add() without an interval succeeded")
+	}
+	if err := s.Add(Job{Name: "idle", Interval: time.Minute}); err == nil {
+		t.Error("Add() without a run func succeeded")
+	}
+	if s.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", s.Len())
+	}
+}
+
+func TestRun(t *testing.T) {
+	s := New()
+	var runs atomic.Int32
+	if err := s.Add(Job{Name: "tick", Interval: 10 * time.Millisecond, Run: func(context.Context) error {
+		if runs.Add(1) == 2 {
+			return fmt.Errorf("flaky")
+		}
+		return nil
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 55*time.Millisecond)
+	defer cancel()
+	s.Run(ctx)
+
+	status := s.Statuses()[0]
+	if status.Runs < 3 || status.Runs != int(runs.Load()) {
+		t.Errorf("Runs = %d (ran %d), want at least 3", status.Runs, runs.Load())
+	}
+	if status.Failures != 1 || status.Running || status.LastRun.IsZero() {
+		t.Errorf("status = %+v, want 1 failure and not running", status)
+	}
+}
+
+func TestRun_SkipsOverlap(t *testing.T) {
+	s := New()
+	var running, overlapped atomic.Int32
+	if err := s.Add(Job{Name: "slow", Interval: 5 * time.Millisecond, Run: func(ctx context.Context) error {
+		if running.Add(1) > 1 {
+			overlapped.Add(1)
+		}
+		defer running.Add(-1)
+		select {
+		case <-ctx.Done():
+		case <-time.After(30 * time.Millisecond):
+		}
+		return nil
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	s.Run(ctx)
+
+	if overlapped.Load() > 0 {
+		t.Errorf("%d runs overlapped", overlapped.Load())
+	}
+	if status := s.Statuses()[0]; status.Skipped == 0 {
+		t.Errorf("status = %+v, want skipped runs", status)
+	}
+	if running.Load() != 0 {
+		t.Error("Run() returned with a run still going")
+	}
+}
+
+func TestRun_Panic(t *testing.T) {
+	s := New()
+	if err := s.Add(Job{Name: "broken", Interval: time.Hour, Run: func(context.Context) error {
+		panic("boom")
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	s.Run(ctx)
+
+	if status := s.Statuses()[0]; status.Failures != 1 || status.Running {
+		t.Errorf("status after a panic = %+v, want 1 failure", status)
+	}
+}
+
+func TestJitter(t *testing.T) {
+	if jitter(0) != 0 {
+		t.Error("jitter(0) != 0")
+	}
+	for range 100 {
+		if d := jitter(time.Second); d < 0 || d >= time.Second {
+			t.Fatalf("jitter(1s) = %v, want [0, 1s)", d)
+		}
+	}
+}
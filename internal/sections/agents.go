@@ -3,19 +3,18 @@ package sections
 import (
 	"context"
 	"fmt"
-	"sort"
 	"strings"
 	"time"
 
 	"github.com/ll931217/claude-hud-enhanced/internal/config"
 	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/render"
 	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
 )
 
 // AgentsSection displays agent activity (running and recently completed)
 type AgentsSection struct {
 	*BaseSection
-	parser *transcript.Parser
 }
 
 // NewAgentsSection creates a new agents section (factory function for registry)
@@ -25,37 +24,36 @@ func NewAgentsSection(cfg interface{}) (registry.Section, error) {
 		appConfig = config.DefaultConfig()
 	}
 
-	// Get transcript path from environment or use default
-	transcriptPath := getTranscriptPath()
-
 	base := NewBaseSection("agents", appConfig)
 	base.SetPriority(registry.PriorityEssential) // Show on all terminals
 	base.SetMinWidth(30)                         // Minimum width for agent names
 
 	return &AgentsSection{
 		BaseSection: base,
-		parser:      transcript.NewParser(transcriptPath),
 	}, nil
 }
 
 // Render returns the agents section output
 func (a *AgentsSection) Render() string {
 	// Get transcript path dynamically from global context
-	transcriptPath := getTranscriptPath()
+	transcriptPath := a.getTranscriptPath()
 	if transcriptPath == "" {
+		a.MarkUnavailable("no transcript")
 		return "" // Hide section if no transcript path
 	}
 
-	// Create a parser for the current transcript path
-	parser := transcript.NewParser(transcriptPath)
+	// Use the shared parser for the current transcript path
+	parser := a.Providers().Transcript(transcriptPath)
 
 	// Parse transcript for agent data
 	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 	defer cancel()
 
 	if err := parser.Parse(ctx); err != nil {
+		a.MarkDegraded(fmt.Sprintf("transcript unreadable: %v", err))
 		return "" // Hide section on parse error
 	}
+	a.MarkHealthy()
 
 	// Get agent activity
 	agents := parser.GetAgentActivity()
@@ -63,9 +61,11 @@ func (a *AgentsSection) Render() string {
 		return "" // Hide section when no agents active
 	}
 
-	// Separate running and completed agents
+	// Separate running and completed agents, most recently reported first
 	var running, completed []agentDisplay
-	for _, agent := range agents {
+	for _, agent := range render.Values(agents, func(a, b *transcript.AgentInfo) int {
+		return render.Newest(a.LastSeen, b.LastSeen)
+	}) {
 		display := agentDisplay{
 			name:      shortenAgentName(agent.AgentName),
 			status:    agent.Status,
@@ -95,11 +95,6 @@ func (a *AgentsSection) Render() string {
 	}
 
 	// Display recently completed agents (max 3) with ✓
-	sort.Slice(completed, func(i, j int) bool {
-		// Sort by completion time (most recent first)
-		return true // Simplified - in real scenario, track timestamp
-	})
-
 	for i, agent := range completed {
 		if i >= 3 {
 			break
@@ -154,5 +149,9 @@ func shortenAgentName(name string) string {
 }
 
 func init() {
-	registry.Register("agents", NewAgentsSection)
+	registry.RegisterWithMetadata("agents", NewAgentsSection, registry.Metadata{
+		Description:  "Running and recently completed subagents",
+		Priority:     registry.PriorityEssential,
+		Dependencies: []registry.Dependency{depTranscript},
+	})
 }
@@ -0,0 +1,92 @@
+package sections
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/statuspage"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+)
+
+// APIStatusSection flags active incidents on the Anthropic status page
+type APIStatusSection struct {
+	*BaseSection
+	client *statuspage.Client // Overrides the shared client when set
+}
+
+// NewAPIStatusSection creates a new API status section (factory function for registry)
+func NewAPIStatusSection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("apistatus", appConfig)
+	base.SetPriority(registry.PriorityOptional) // Only shown during incidents anyway
+	base.SetCacheTTL(time.Minute)               // The client caches the status page for longer
+
+	return &APIStatusSection{
+		BaseSection: base,
+	}, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("apistatus", NewAPIStatusSection, registry.Metadata{
+		Description: "Active incidents on the Anthropic status page",
+		Priority:    registry.PriorityOptional,
+		Options: []registry.Option{
+			{Name: "components", Type: "list", Default: "Claude API, Claude Code", Description: "Status page components to watch"},
+			{Name: "poll_interval_ms", Type: "duration_ms", Default: "300000", Description: "How often to fetch the status page"},
+			{Name: "max_length", Type: "int", Default: "40", Description: "Truncate incident titles to this many characters"},
+		},
+	})
+}
+
+// Render returns the API status section output; nothing while all is operational
+func (s *APIStatusSection) Render() string {
+	client := s.client
+	if client == nil {
+		client = s.Providers().StatusPage()
+	}
+
+	opts := s.GetConfig().SectionOptions(s.Name())
+	client.Configure(opts.Duration("poll_interval_ms", 0), opts.Strings("components", nil))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	report, err := client.Fetch(ctx)
+	if err != nil {
+		s.MarkDegraded(fmt.Sprintf("status page: %v", err))
+	} else {
+		s.MarkHealthy()
+	}
+	if !report.Active() || report.Impact == statuspage.ImpactMaintenance {
+		return ""
+	}
+
+	title := truncateTitle(report.Title, opts.Int("max_length", 40))
+	return apiStatusColor(report.Impact) + "⚠ " + title + theme.Reset
+}
+
+// apiStatusColor returns yellow for minor incidents and red for major ones
+func apiStatusColor(impact string) string {
+	switch impact {
+	case statuspage.ImpactMajor, statuspage.ImpactCritical:
+		return theme.Red
+	default:
+		return theme.Yellow
+	}
+}
+
+// truncateTitle shortens s to at most maxLen runes, ending with "…"
+func truncateTitle(s string, maxLen int) string {
+	if maxLen <= 0 || utf8.RuneCountInString(s) <= maxLen {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:maxLen-1]) + "…"
+}
@@ -0,0 +1,141 @@
+package sections
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/i18n"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+)
+
+// pendingApproval is a tool call that may be waiting for the user's approval
+type pendingApproval struct {
+	Tool      string
+	Target    string
+	Since     time.Time
+	Confirmed bool // A Notification hook announced the permission prompt
+}
+
+// ApprovalSection flags a session blocked on a permission prompt, which is
+// easy to miss while working in another window
+type ApprovalSection struct {
+	*BaseSection
+	pending func() (pendingApproval, bool, error) // Overrides the transcript and hooks when set
+	now     func() time.Time                      // Overrides the clock when set
+}
+
+// NewApprovalSection creates a new approval section (factory function for registry)
+func NewApprovalSection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("approval", appConfig)
+	base.SetPriority(registry.PriorityEssential) // A blocked session must not go unnoticed on narrow terminals
+	base.SetMinWidth(17)                         // Minimum width for "⏳ approval needed"
+
+	return &ApprovalSection{
+		BaseSection: base,
+		now:         time.Now,
+	}, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("approval", NewApprovalSection, registry.Metadata{
+		Description: "Flashing indicator while Claude waits for you to approve a tool call",
+		Priority:    registry.PriorityEssential,
+		Options: []registry.Option{
+			{Name: "wait_ms", Type: "duration_ms", Default: "10000", Description: "How long a tool call may go unanswered before it counts as waiting for approval"},
+			{Name: "ignore_tools", Type: "string", Default: "Task", Description: "Comma-separated tools that run long without asking, never flagged from the transcript alone"},
+		},
+		Dependencies: []registry.Dependency{depTranscript},
+	})
+}
+
+// Render returns the approval section output
+func (a *ApprovalSection) Render() string {
+	return registry.FrameAt(a.RenderFrames(), a.now())
+}
+
+// RenderFrames implements registry.Animated: the indicator flashes unless
+// animations are off, e.g. "⏳ approval needed: Bash go test ./... 45s"
+func (a *ApprovalSection) RenderFrames() []string {
+	pending := a.pending
+	if pending == nil {
+		pending = a.transcriptPending
+	}
+	wait, found, err := pending()
+	if err != nil {
+		a.MarkDegraded(fmt.Sprintf("transcript unreadable: %v", err))
+		return nil
+	}
+	a.MarkHealthy()
+
+	opts := a.GetConfig().SectionOptions(a.Name())
+	waited := a.now().Sub(wait.Since)
+	if !found || (!wait.Confirmed && (waited < opts.Duration("wait_ms", 10*time.Second) || ignoredTool(opts.String("ignore_tools", "Task"), wait.Tool))) {
+		return nil
+	}
+
+	text := "⏳ " + i18n.T("approval.needed")
+	if wait.Tool != "" {
+		text += ": " + shortenToolName(wait.Tool)
+		if wait.Target != "" {
+			text += " " + wait.Target
+		}
+	}
+	if waited >= time.Second {
+		text += " " + formatTurnDuration(waited)
+	}
+
+	frames := []string{theme.Bold + theme.Yellow + text + theme.Reset}
+	if a.GetConfig().Animate {
+		frames = append(frames, theme.Dim+text+theme.Reset)
+	}
+	return frames
+}
+
+// transcriptPending finds the oldest tool call with nothing written to the
+// transcript since, preferring the hooks' record of a permission prompt
+func (a *ApprovalSection) transcriptPending() (pendingApproval, bool, error) {
+	transcriptPath := a.getTranscriptPath()
+	if transcriptPath == "" {
+		return pendingApproval{}, false, nil
+	}
+
+	parser := a.Providers().Transcript(transcriptPath)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := parser.Parse(ctx); err != nil {
+		return pendingApproval{}, false, err
+	}
+	tool, found := parser.GetUnansweredTool()
+	wait := pendingApproval{Tool: tool.Name, Target: tool.Target, Since: tool.LastUsed}
+
+	// An unreadable state file only costs the hooks' precision
+	sess, _ := loadHookSession(transcriptPath)
+	switch {
+	case sess == nil:
+	case sess.Stopped:
+		return pendingApproval{}, false, nil // The turn is over; nothing waits
+	case sess.Approval != nil:
+		wait.Since, wait.Confirmed = sess.Approval.At, true
+		return wait, true, nil
+	}
+	return wait, found, nil
+}
+
+// ignoredTool reports whether tool is in the comma-separated list
+func ignoredTool(list, tool string) bool {
+	for _, name := range strings.Split(list, ",") {
+		if strings.TrimSpace(name) == tool && tool != "" {
+			return true
+		}
+	}
+	return false
+}
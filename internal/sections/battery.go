@@ -0,0 +1,98 @@
+package sections
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/humanize"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/system"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+)
+
+// BatterySection displays battery charge and charging state
+type BatterySection struct {
+	*BaseSection
+	readBattery func() (system.BatteryInfo, error)
+}
+
+// NewBatterySection creates a new battery section (factory function for registry)
+func NewBatterySection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("battery", appConfig)
+	base.SetPriority(registry.PriorityOptional) // Nice to have; hide first on narrow terminals
+	base.SetMinWidth(8)                         // Minimum width for "🔋 100%"
+	base.SetCacheTTL(30 * time.Second)          // Charge changes slowly
+
+	return &BatterySection{
+		BaseSection: base,
+		readBattery: system.GetBattery,
+	}, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("battery", NewBatterySection, registry.Metadata{
+		Description: "Battery charge, charging state and time remaining",
+		Priority:    registry.PriorityOptional,
+		Options: []registry.Option{
+			{Name: "warning_percent", Type: "int", Default: "30", Description: "Show yellow at or below this charge while unplugged"},
+			{Name: "critical_percent", Type: "int", Default: "15", Description: "Show red at or below this charge while unplugged"},
+			{Name: "hide_on_ac", Type: "bool", Default: "false", Description: "Hide the section while plugged in"},
+			{Name: "show_time", Type: "bool", Default: "true", Description: "Show estimated time to empty or full"},
+		},
+		Dependencies: []registry.Dependency{depBattery},
+	})
+}
+
+// Render returns the battery section output
+func (b *BatterySection) Render() string {
+	info, err := b.readBattery()
+	if err != nil {
+		b.MarkDegraded(fmt.Sprintf("battery status: %v", err))
+		return ""
+	}
+	if !info.Present {
+		b.MarkUnavailable("no battery")
+		return ""
+	}
+	b.MarkHealthy()
+
+	opts := b.GetConfig().SectionOptions(b.Name())
+	if info.OnAC() && opts.Bool("hide_on_ac", false) {
+		return ""
+	}
+
+	icon := "🔋"
+	if info.OnAC() {
+		icon = "⚡"
+	}
+	display := fmt.Sprintf("%d%%", info.Percent)
+	display = theme.Signal(batteryColor(info, opts.Int("warning_percent", 30), opts.Int("critical_percent", 15)), display)
+
+	if info.TimeRemaining > 0 && opts.Bool("show_time", true) {
+		display += fmt.Sprintf(" %s(%s)%s", theme.Dim, humanize.Duration(info.TimeRemaining, humanize.Short), theme.Reset)
+	}
+
+	return icon + " " + display
+}
+
+// batteryColor returns the threshold color for a battery running unplugged
+// A plugged-in battery is never colored, however low it is
+func batteryColor(info system.BatteryInfo, warning, critical int) string {
+	if info.OnAC() {
+		return ""
+	}
+	switch {
+	case info.Percent <= critical:
+		return theme.Red
+	case info.Percent <= warning:
+		return theme.Yellow
+	default:
+		return ""
+	}
+}
@@ -8,17 +8,18 @@ import (
 	"strings"
 	"time"
 
-	"github.com/ll931217/claude-hud-enhanced/internal/beads"
+	"github.com/ll931217/claude-hud-enhanced/internal/ci"
 	"github.com/ll931217/claude-hud-enhanced/internal/config"
-	"github.com/ll931217/claude-hud-enhanced/internal/git"
+	"github.com/ll931217/claude-hud-enhanced/internal/issues"
 	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/styled"
 )
 
-// BeadsSection displays beads issue tracking information
+// BeadsSection displays the current issue from beads or another issue tracker
 type BeadsSection struct {
 	*BaseSection
-	reader   *beads.Reader
-	detector *git.Detector
+	repoPath string
+	snapshot func(provider issues.Provider, interval, timeout time.Duration) (issues.Snapshot, bool, error) // Overrides the shared client when set
 }
 
 // NewBeadsSection creates a new beads section (factory function for registry)
@@ -28,72 +29,224 @@ func NewBeadsSection(cfg interface{}) (registry.Section, error) {
 		appConfig = config.DefaultConfig()
 	}
 
-	// Get current directory or use git repo root
-	repoPath := getRepoPath()
+	base := NewBaseSection("beads", appConfig)
+	base.SetCacheTTL(500 * time.Millisecond) // Issues change often; keep it fresh
 
 	return &BeadsSection{
-		BaseSection: NewBaseSection("beads", appConfig),
-		reader:      beads.NewReader(repoPath),
-		detector:    git.NewDetector(repoPath),
+		BaseSection: base,
 	}, nil
 }
 
 func init() {
-	registry.Register("beads", NewBeadsSection)
+	registry.RegisterWithMetadata("beads", NewBeadsSection, registry.Metadata{
+		Description:  "Current issue and open issue counts from beads, GitHub Issues, Linear or Jira",
+		Priority:     registry.PriorityImportant,
+		Dependencies: []registry.Dependency{depBeads},
+		Options: []registry.Option{
+			{Name: "provider", Type: "string", Default: "beads", Description: "Issue tracker: beads, github, linear or jira"},
+			{Name: "title_length", Type: "int", Default: "40", Description: "Truncate the active issue's title to this many characters"},
+			{Name: "show_epic", Type: "bool", Default: "true", Description: "Show the completion of the active issue's epic"},
+			{Name: "bar_width", Type: "int", Default: "5", Description: "Width of the epic's progress bar"},
+			{Name: "token", Type: "string", Default: "", Description: "API token (default: $GITHUB_TOKEN or $GH_TOKEN, $LINEAR_API_KEY, $JIRA_API_TOKEN)"},
+			{Name: "remote", Type: "string", Default: "origin", Description: "github: remote whose repository's issues are read"},
+			{Name: "api_url", Type: "string", Default: "", Description: "github: REST API base; linear: GraphQL endpoint"},
+			{Name: "url", Type: "string", Default: "", Description: "jira: site, e.g. https://acme.atlassian.net"},
+			{Name: "email", Type: "string", Default: "", Description: "jira: account email; set for Jira Cloud, leave empty for a Data Center access token"},
+			{Name: "interval_ms", Type: "duration_ms", Default: "120000", Description: "How often a remote tracker is asked (minimum 30000)"},
+			{Name: "timeout_ms", Type: "duration_ms", Default: "1000", Description: "How long a render may wait for a remote tracker (maximum 2000)"},
+			{Name: "issue_url", Type: "string", Default: "", Description: "beads: link for issue IDs when hyperlinks are on, with {id} for the ID"},
+		},
+	})
 }
 
+// minIssuesInterval keeps remote trackers well within their rate limits
+const minIssuesInterval = 30 * time.Second
+
+// maxIssuesTimeout bounds how long a render may wait for a remote tracker
+const maxIssuesTimeout = 2 * time.Second
+
 // Render returns the beads section output
 func (b *BeadsSection) Render() string {
+	return styled.Render(styled.ANSI{}, b.RenderStyled())
+}
+
+// RenderStyled returns the beads section output as styled text, with the
+// issue ID linked to its page
+func (b *BeadsSection) RenderStyled() styled.Text {
+	if b.repoPath == "" {
+		b.repoPath = b.getRepoPath()
+	}
+	opts := b.GetConfig().SectionOptions(b.Name())
+	if name := opts.String("provider", "beads"); name != "beads" {
+		return b.renderTracker(name, opts)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 	defer cancel()
 
 	// Load issues
-	if err := b.reader.Load(ctx); err != nil {
+	reader := b.Providers().Beads(b.repoPath)
+	provider := issues.Beads{Reader: reader}
+	snapshot, err := provider.Fetch(ctx)
+	if err != nil {
 		// Graceful degradation
-		return "[Beads: not available]"
+		if !reader.Exists() {
+			b.MarkUnavailable("no beads issues file or database")
+		} else {
+			b.MarkDegraded(fmt.Sprintf("beads unreadable: %v", err))
+		}
+		return styled.Plain("[Beads: not available]")
 	}
+	b.MarkHealthy()
+
+	return b.formatSnapshot(provider.Name(), snapshot, opts)
+}
 
-	// Get current issue
-	issue := b.reader.GetCurrentIssue()
-	if issue == nil {
-		// No active issue, show summary
-		return b.formatSummary()
+// renderTracker shows the current issue of a remote tracker, which is asked
+// through the shared client at most once per interval
+func (b *BeadsSection) renderTracker(name string, opts config.SectionOptions) styled.Text {
+	if name != "github" && name != "linear" && name != "jira" {
+		b.MarkDegraded(fmt.Sprintf("unknown issue provider %q", name))
+		return nil
+	}
+	provider, err := b.trackerProvider(name, opts)
+	if err != nil {
+		b.MarkUnavailable(err.Error())
+		return nil
 	}
 
-	// Format issue display
-	return b.formatIssue(issue)
+	interval := max(opts.Duration("interval_ms", issues.DefaultInterval), minIssuesInterval)
+	timeout := min(opts.Duration("timeout_ms", time.Second), maxIssuesTimeout)
+	readSnapshot := b.snapshot
+	if readSnapshot == nil {
+		readSnapshot = b.fetchSnapshot
+	}
+	snapshot, found, err := readSnapshot(provider, interval, timeout)
+	if err != nil {
+		// Keep showing the last snapshot while the tracker fails
+		b.MarkDegraded(fmt.Sprintf("%s: %v", provider.Name(), err))
+	} else {
+		b.MarkHealthy()
+	}
+	if !found {
+		return nil
+	}
+	return b.formatSnapshot(provider.Name(), snapshot, opts)
 }
 
-// formatIssue formats an issue for display
-func (b *BeadsSection) formatIssue(issue *beads.Issue) string {
-	var parts []string
+// trackerProvider configures the named remote tracker for the checked out branch
+func (b *BeadsSection) trackerProvider(name string, opts config.SectionOptions) (issues.Provider, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
 
-	// Status icon
-	parts = append(parts, issue.Status.Icon())
+	detector := b.Providers().Git(b.repoPath)
+	branch, _ := detector.Branch(ctx) // Without a branch, trackers fall back to the user's issues
+	token := opts.String("token", "")
 
-	// Issue ID
-	parts = append(parts, issue.ID)
+	switch name {
+	case "github":
+		if token == "" {
+			token = os.Getenv("GITHUB_TOKEN")
+		}
+		if token == "" {
+			token = os.Getenv("GH_TOKEN")
+		}
+		remote := opts.String("remote", "origin")
+		remoteURL, err := detector.RemoteURL(ctx, remote)
+		if err != nil {
+			return nil, fmt.Errorf("no %s remote", remote)
+		}
+		host, owner, repo, ok := ci.ParseRemote(remoteURL)
+		if !ok {
+			return nil, fmt.Errorf("%s is not hosted on GitHub", remote)
+		}
+		api := opts.String("api_url", "")
+		if api == "" {
+			api = ci.APIForHost(host)
+		}
+		return issues.GitHub{API: api, Token: token, Owner: owner, Repo: repo, Branch: branch}, nil
+	case "linear":
+		if token == "" {
+			token = os.Getenv("LINEAR_API_KEY")
+		}
+		return issues.Linear{API: opts.String("api_url", ""), Token: token, Branch: branch}, nil
+	case "jira":
+		if token == "" {
+			token = os.Getenv("JIRA_API_TOKEN")
+		}
+		return issues.Jira{URL: opts.String("url", ""), Email: opts.String("email", ""), Token: token, Branch: branch}, nil
+	}
+	return nil, fmt.Errorf("unknown issue provider %q", name)
+}
 
-	// Title (truncated if needed)
-	title := issue.Title
-	if len(title) > 40 {
-		title = title[:37] + "..."
+// fetchSnapshot reads the tracker through the shared client
+func (b *BeadsSection) fetchSnapshot(provider issues.Provider, interval, timeout time.Duration) (issues.Snapshot, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return b.Providers().Issues().Snapshot(ctx, provider, interval)
+}
+
+// formatSnapshot shows the current issue or, without one, the open and total
+// counts, e.g. "bd: 3/10"
+func (b *BeadsSection) formatSnapshot(label string, snapshot issues.Snapshot, opts config.SectionOptions) styled.Text {
+	if snapshot.Current == nil {
+		return styled.Plain(fmt.Sprintf("%s: %d/%d", label, snapshot.Open, snapshot.Total))
 	}
-	parts = append(parts, title)
+	return b.formatIssue(snapshot.Current, opts)
+}
+
+// formatIssue formats an issue for display
+func (b *BeadsSection) formatIssue(issue *issues.Issue, opts config.SectionOptions) styled.Text {
+	// Status icon
+	output := styled.Plain(issue.Status.Icon() + " • ")
+
+	// Issue ID, linked to the issue's page
+	url := issue.URL
+	if template := opts.String("issue_url", ""); url == "" && template != "" {
+		url = strings.ReplaceAll(template, "{id}", issue.ID)
+	}
+	output = output.Join(b.linkStyled(url, styled.Plain(issue.ID)))
+
+	// Title (truncated if needed), and the rest in plain text
+	parts := []string{truncateTitle(issue.Title, opts.Int("title_length", 40))}
 
 	// Priority
-	parts = append(parts, issue.GetPriorityLabel())
+	if issue.Priority != "" {
+		parts = append(parts, issue.Priority)
+	}
 
 	// Todo progress (if available in description)
 	if progress := b.extractTodoProgress(issue); progress != "" {
 		parts = append(parts, progress)
 	}
 
-	return strings.Join(parts, " • ")
+	// Completion of the epic the issue belongs to
+	if opts.Bool("show_epic", true) {
+		if epic := formatEpic(issue.Epic, opts.Int("bar_width", 5)); epic != "" {
+			parts = append(parts, epic)
+		}
+	}
+
+	return output.Add(" • "+strings.Join(parts, " • "), styled.None)
+}
+
+// formatEpic renders an epic with a mini bar of its closed children, e.g.
+// "Auth rework ██░░░ 2/5"; empty outside an epic or for a childless one
+func formatEpic(epic *issues.Epic, barWidth int) string {
+	if epic == nil || epic.Total == 0 {
+		return ""
+	}
+
+	bar := progressBar(epic.Closed*100/epic.Total, barWidth, "█", "░")
+	if epic.Title == "" {
+		// The epic is the issue itself, whose title is already shown
+		return fmt.Sprintf("%s %d/%d", bar, epic.Closed, epic.Total)
+	}
+	return fmt.Sprintf("%s %s %d/%d", truncateTitle(epic.Title, 20), bar, epic.Closed, epic.Total)
 }
 
 // extractTodoProgress extracts todo progress from issue description
-func (b *BeadsSection) extractTodoProgress(issue *beads.Issue) string {
+func (b *BeadsSection) extractTodoProgress(issue *issues.Issue) string {
 	// Look for todo patterns in description
 	// Format: "- [x]" for completed, "- [ ]" for open
 	desc := issue.Description
@@ -118,32 +271,21 @@ func (b *BeadsSection) extractTodoProgress(issue *beads.Issue) string {
 	return ""
 }
 
-// getRepoPath returns the git repository root path
-func getRepoPath() string {
-	// Try to get git root
+// getRepoPath returns the git repository root path of the project Claude Code
+// was started in, which the current directory may have wandered out of
+func (b *BaseSection) getRepoPath() string {
+	dir := b.Providers().ProjectDir()
 	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	cmd.Dir = dir
 	output, err := cmd.Output()
 	if err != nil {
-		// Fallback to current directory
-		if cwd, err := os.Getwd(); err == nil {
-			return cwd
-		}
-		return "."
+		// Fallback to the directory itself
+		return dir
 	}
 
 	return strings.TrimSpace(string(output))
 }
 
-// formatSummary formats a summary when no active issue
-func (b *BeadsSection) formatSummary() string {
-	// Get counts by status
-	openCount := b.reader.CountByStatus(beads.StatusOpen)
-	total := b.reader.Count()
-
-	// Format: bd: <OPEN>/<TOTAL>
-	return fmt.Sprintf("bd: %d/%d", openCount, total)
-}
-
 // getStatusSection returns the git status section
 func (b *BeadsSection) getStatusSection() *registry.Section {
 	// This would be used to combine beads and status sections
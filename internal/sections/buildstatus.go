@@ -50,14 +50,15 @@ func (b *BuildStatusSection) Render() string {
 	}
 	b.mu.RUnlock()
 
-	// Detect language
-	lang := system.DetectLanguage(".")
+	// Detect language in the session's directory
+	dir := b.workingDir()
+	lang := system.DetectLanguage(dir)
 	if lang == "" {
 		return "" // Hide section if language not detected
 	}
 
 	// Get build status based on language
-	status := b.getBuildStatus(lang)
+	status := b.getBuildStatus(dir, lang)
 
 	// Update cache
 	b.mu.Lock()
@@ -69,18 +70,18 @@ func (b *BuildStatusSection) Render() string {
 }
 
 // getBuildStatus retrieves build status for the detected language
-func (b *BuildStatusSection) getBuildStatus(lang string) string {
+func (b *BuildStatusSection) getBuildStatus(dir, lang string) string {
 	var success bool
 	var errorCount int
 	var err error
 
 	switch lang {
 	case "Go":
-		success, errorCount, err = b.getGoBuildStatus()
+		success, errorCount, err = b.getGoBuildStatus(dir)
 	case "JavaScript", "TypeScript":
-		success, errorCount, err = b.getTSBuildStatus()
+		success, errorCount, err = b.getTSBuildStatus(dir)
 	case "Python":
-		success, errorCount, err = b.getPythonBuildStatus()
+		success, errorCount, err = b.getPythonBuildStatus(dir)
 	default:
 		return "" // Unsupported language
 	}
@@ -102,11 +103,12 @@ func (b *BuildStatusSection) getBuildStatus(lang string) string {
 }
 
 // getGoBuildStatus checks Go build status
-func (b *BuildStatusSection) getGoBuildStatus() (bool, int, error) {
+func (b *BuildStatusSection) getGoBuildStatus(dir string) (bool, int, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, "go", "build", "./...")
+	cmd.Dir = dir
 	output, err := cmd.CombinedOutput()
 
 	if err == nil {
@@ -123,12 +125,13 @@ func (b *BuildStatusSection) getGoBuildStatus() (bool, int, error) {
 }
 
 // getTSBuildStatus checks TypeScript build status
-func (b *BuildStatusSection) getTSBuildStatus() (bool, int, error) {
+func (b *BuildStatusSection) getTSBuildStatus(dir string) (bool, int, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
 	// Try tsc first
 	cmd := exec.CommandContext(ctx, "npx", "tsc", "--noEmit")
+	cmd.Dir = dir
 	output, err := cmd.CombinedOutput()
 
 	if err == nil {
@@ -156,12 +159,13 @@ func (b *BuildStatusSection) getTSBuildStatus() (bool, int, error) {
 }
 
 // getPythonBuildStatus checks Python "build" status (syntax check)
-func (b *BuildStatusSection) getPythonBuildStatus() (bool, int, error) {
+func (b *BuildStatusSection) getPythonBuildStatus(dir string) (bool, int, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	// Use mypy for type checking (if available)
 	cmd := exec.CommandContext(ctx, "mypy", ".")
+	cmd.Dir = dir
 	output, err := cmd.CombinedOutput()
 
 	if err == nil {
@@ -178,5 +182,9 @@ func (b *BuildStatusSection) getPythonBuildStatus() (bool, int, error) {
 }
 
 func init() {
-	registry.Register("buildstatus", NewBuildStatusSection)
+	registry.RegisterWithMetadata("buildstatus", NewBuildStatusSection, registry.Metadata{
+		Description:  "Whether the project builds (Go, TypeScript, Python)",
+		Priority:     registry.PriorityImportant,
+		Dependencies: []registry.Dependency{depSource},
+	})
 }
@@ -0,0 +1,159 @@
+package sections
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/ci"
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+)
+
+// minCIInterval keeps unauthenticated use within GitHub's 60 requests an hour
+const minCIInterval = 15 * time.Second
+
+// maxCITimeout bounds how long a render may wait for the CI API
+const maxCITimeout = 2 * time.Second
+
+// CISection displays the CI status of the checked out commit
+type CISection struct {
+	*BaseSection
+	repoPath string
+	commit   func(remote string) (host string, commit ci.Commit, err error)                                         // Overrides git when set
+	status   func(provider ci.Provider, commit ci.Commit, interval, timeout time.Duration) (ci.Result, bool, error) // Overrides the shared client when set
+}
+
+// NewCISection creates a new CI status section (factory function for registry)
+func NewCISection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("ci", appConfig)
+	base.SetPriority(registry.PriorityImportant) // A red build matters before pushing more
+	base.SetMinWidth(4)                          // Minimum width for "CI ✓"
+	base.SetCacheTTL(5 * time.Second)            // The client caches results for longer
+
+	return &CISection{
+		BaseSection: base,
+	}, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("ci", NewCISection, registry.Metadata{
+		Description:  "CI status of HEAD from GitHub Actions or a commit status API",
+		Priority:     registry.PriorityImportant,
+		Dependencies: []registry.Dependency{depGit},
+		Options: []registry.Option{
+			{Name: "provider", Type: "string", Default: "github", Description: "github (Actions workflow runs) or status (commit statuses, also on Gitea and Forgejo)"},
+			{Name: "remote", Type: "string", Default: "origin", Description: "Remote whose repository is asked"},
+			{Name: "api_url", Type: "string", Default: "", Description: "REST API base (default: api.github.com, or https://<host>/api/v3 for other hosts)"},
+			{Name: "token", Type: "string", Default: "", Description: "API token (default: $GITHUB_TOKEN or $GH_TOKEN); needed for private repositories"},
+			{Name: "interval_ms", Type: "duration_ms", Default: "60000", Description: "How often a running build is asked about (minimum 15000); finished builds ten times less"},
+			{Name: "timeout_ms", Type: "duration_ms", Default: "1000", Description: "How long a render may wait for the API (maximum 2000)"},
+		},
+	})
+}
+
+// Render returns the CI status, e.g. "CI ✓", "CI ✗" or "CI ●" while running
+func (c *CISection) Render() string {
+	if c.repoPath == "" {
+		c.repoPath = c.getRepoPath()
+	}
+	opts := c.GetConfig().SectionOptions(c.Name())
+
+	lookup := c.commit
+	if lookup == nil {
+		lookup = c.headCommit
+	}
+	host, commit, err := lookup(opts.String("remote", "origin"))
+	if err != nil {
+		c.MarkUnavailable(err.Error())
+		return ""
+	}
+
+	api := opts.String("api_url", "")
+	if api == "" {
+		api = ci.APIForHost(host)
+	}
+	token := opts.String("token", "")
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	if token == "" {
+		token = os.Getenv("GH_TOKEN")
+	}
+
+	var provider ci.Provider
+	switch name := opts.String("provider", "github"); name {
+	case "github":
+		provider = ci.GitHubActions{API: api, Token: token}
+	case "status":
+		provider = ci.CommitStatus{API: api, Token: token}
+	default:
+		c.MarkDegraded(fmt.Sprintf("unknown CI provider %q", name))
+		return ""
+	}
+
+	interval := max(opts.Duration("interval_ms", ci.DefaultInterval), minCIInterval)
+	timeout := min(opts.Duration("timeout_ms", time.Second), maxCITimeout)
+	readStatus := c.status
+	if readStatus == nil {
+		readStatus = c.fetchStatus
+	}
+	result, found, err := readStatus(provider, commit, interval, timeout)
+	if err != nil {
+		// Keep showing the last result while the API fails
+		c.MarkDegraded(fmt.Sprintf("%s: %v", provider.Name(), err))
+	} else {
+		c.MarkHealthy()
+	}
+	if !found {
+		return ""
+	}
+
+	// The commit's page lists its checks
+	commitURL := ci.RepoURL(host, commit.Owner, commit.Repo) + "/commit/" + commit.SHA
+	switch result.State {
+	case ci.StateSuccess:
+		return c.link(commitURL, "CI "+theme.Green+"✓"+theme.Reset)
+	case ci.StateFailure:
+		return c.link(commitURL, "CI "+theme.Red+"✗"+theme.Reset)
+	case ci.StatePending:
+		return c.link(commitURL, "CI "+theme.Yellow+"●"+theme.Reset)
+	}
+	return "" // Nothing ran for this commit, e.g. it is not pushed yet
+}
+
+// headCommit finds the repository on the remote's forge and the checked out commit
+func (c *CISection) headCommit(remote string) (string, ci.Commit, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	detector := c.Providers().Git(c.repoPath)
+	sha, err := detector.Head(ctx)
+	if err != nil {
+		return "", ci.Commit{}, errors.New("no commit checked out")
+	}
+	remoteURL, err := detector.RemoteURL(ctx, remote)
+	if err != nil {
+		return "", ci.Commit{}, fmt.Errorf("no %s remote", remote)
+	}
+	host, owner, repo, ok := ci.ParseRemote(remoteURL)
+	if !ok {
+		return "", ci.Commit{}, fmt.Errorf("%s is not hosted on a forge", remote)
+	}
+	return host, ci.Commit{Owner: owner, Repo: repo, SHA: sha}, nil
+}
+
+// fetchStatus reads the commit status through the shared client
+func (c *CISection) fetchStatus(provider ci.Provider, commit ci.Commit, interval, timeout time.Duration) (ci.Result, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return c.Providers().CI().Status(ctx, provider, commit, interval)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ll931217/claude-hud-enhanced/internal/claudestats"
@@ -14,7 +15,9 @@ import (
 // ClaudeStatsSection displays Claude capability statistics
 type ClaudeStatsSection struct {
 	*BaseSection
-	collector *claudestats.Collector
+	collectorOnce sync.Once
+	collector     *claudestats.Collector
+	collect       func(ctx context.Context) *claudestats.StatsCache // Overrides the collector when set
 }
 
 // NewClaudeStatsSection creates a new claudestats section (factory function)
@@ -27,10 +30,10 @@ func NewClaudeStatsSection(cfg interface{}) (registry.Section, error) {
 	base := NewBaseSection("claudestats", appConfig)
 	base.SetPriority(registry.PriorityImportant) // Show on medium+ terminals
 	base.SetMinWidth(30)                         // Minimum width for "Core:8 | MCP:5"
+	base.SetCacheTTL(10 * time.Second)           // MCP and plugin config rarely changes
 
 	return &ClaudeStatsSection{
 		BaseSection: base,
-		collector:   claudestats.NewCollector(),
 	}, nil
 }
 
@@ -39,7 +42,17 @@ func (s *ClaudeStatsSection) Render() string {
 	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 	defer cancel()
 
-	stats := s.collector.Collect(ctx)
+	collect := s.collect
+	if collect == nil {
+		s.collectorOnce.Do(func() {
+			// Created on first render so the injected MCP client is used
+			s.collector = claudestats.NewCollectorWithClient(s.Providers().MCP())
+			s.collector.SetProjectDir(s.Providers().ProjectDir())
+		})
+		collect = s.collector.Collect
+	}
+	stats := collect(ctx)
+	breakdown := s.GetConfig().SectionOptions(s.Name()).Bool("breakdown", false)
 
 	var parts []string
 
@@ -47,9 +60,29 @@ func (s *ClaudeStatsSection) Render() string {
 	if stats.CoreCount > 0 {
 		parts = append(parts, fmt.Sprintf("Core:%d", stats.CoreCount))
 	}
-	if stats.MCPCount > 0 {
+	if stats.MCPCount > 0 && stats.MCPToolsCount > 0 {
+		parts = append(parts, fmt.Sprintf("MCP:%d (%d tools)", stats.MCPCount, stats.MCPToolsCount))
+	} else if stats.MCPCount > 0 {
 		parts = append(parts, fmt.Sprintf("MCP:%d", stats.MCPCount))
 	}
+	for _, def := range []struct {
+		label  string
+		counts claudestats.SourceCounts
+	}{
+		{"Skills", stats.Skills},
+		{"Cmds", stats.Commands},
+		{"Agents", stats.Agents},
+		{"Styles", stats.OutputStyles},
+	} {
+		if def.counts.Total() == 0 {
+			continue
+		}
+		part := fmt.Sprintf("%s:%d", def.label, def.counts.Total())
+		if breakdown {
+			part += " (" + formatSourceCounts(def.counts) + ")"
+		}
+		parts = append(parts, part)
+	}
 	if stats.PluginsCount > 0 {
 		parts = append(parts, fmt.Sprintf("Plugins:%d", stats.PluginsCount))
 	}
@@ -64,6 +97,28 @@ func (s *ClaudeStatsSection) Render() string {
 	return strings.Join(parts, " | ")
 }
 
+// formatSourceCounts formats non-zero counts per source, e.g. "u3 p1 pl2"
+func formatSourceCounts(counts claudestats.SourceCounts) string {
+	var parts []string
+	if counts.User > 0 {
+		parts = append(parts, fmt.Sprintf("u%d", counts.User))
+	}
+	if counts.Project > 0 {
+		parts = append(parts, fmt.Sprintf("p%d", counts.Project))
+	}
+	if counts.Plugin > 0 {
+		parts = append(parts, fmt.Sprintf("pl%d", counts.Plugin))
+	}
+	return strings.Join(parts, " ")
+}
+
 func init() {
-	registry.Register("claudestats", NewClaudeStatsSection)
+	registry.RegisterWithMetadata("claudestats", NewClaudeStatsSection, registry.Metadata{
+		Description: "Counts of MCP servers and tools, skills, commands, agents, output styles, plugins and hooks",
+		Priority:    registry.PriorityImportant,
+		Options: []registry.Option{
+			{Name: "breakdown", Type: "bool", Default: "false", Description: "Split skill, command, agent and style counts by user, project and plugin"},
+		},
+		Dependencies: []registry.Dependency{depClaudeHome},
+	})
 }
@@ -0,0 +1,130 @@
+package sections
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+)
+
+// ClockSection displays the local time, and optionally the time in a second timezone
+type ClockSection struct {
+	*BaseSection
+	now func() time.Time // Overrides the clock when set
+}
+
+// NewClockSection creates a new clock section (factory function for registry)
+func NewClockSection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("clock", appConfig)
+	base.SetPriority(registry.PriorityOptional) // Nice to have; hide first on narrow terminals
+	base.SetMinWidth(5)                         // Minimum width for "14:05"
+	base.SetCacheTTL(time.Second)               // Formats may include seconds
+
+	return &ClockSection{
+		BaseSection: base,
+		now:         time.Now,
+	}, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("clock", NewClockSection, registry.Metadata{
+		Description: "Current time, with an optional second timezone",
+		Priority:    registry.PriorityOptional,
+		Options: []registry.Option{
+			{Name: "format", Type: "string", Default: "%H:%M", Description: "strftime-style format, e.g. \"%a %H:%M\" or \"%I:%M %p\""},
+			{Name: "timezone", Type: "string", Default: "", Description: "Also show the time in this IANA timezone, e.g. \"America/New_York\""},
+			{Name: "timezone_label", Type: "string", Default: "", Description: "Label for the second timezone (default: its abbreviation)"},
+		},
+	})
+}
+
+// Render returns the time, e.g. "14:05" or "14:05 · EST 08:05"
+func (c *ClockSection) Render() string {
+	opts := c.GetConfig().SectionOptions(c.Name())
+	format := opts.String("format", "%H:%M")
+	now := c.now()
+	output := strftime(now, format)
+
+	name := opts.String("timezone", "")
+	if name == "" {
+		c.MarkHealthy()
+		return output
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		c.MarkDegraded(fmt.Sprintf("unknown timezone %q: %v", name, err))
+		return output
+	}
+	c.MarkHealthy()
+
+	remote := now.In(loc)
+	label := opts.String("timezone_label", "")
+	if label == "" {
+		label, _ = remote.Zone()
+	}
+	return output + " · " + label + " " + strftime(remote, format)
+}
+
+// strftime formats t with the common strftime directives
+// Unknown directives are kept as written
+func strftime(t time.Time, format string) string {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 == len(format) {
+			b.WriteByte(format[i])
+			continue
+		}
+		i++
+		switch format[i] {
+		case 'H':
+			fmt.Fprintf(&b, "%02d", t.Hour())
+		case 'I':
+			fmt.Fprintf(&b, "%02d", (t.Hour()+11)%12+1)
+		case 'l':
+			fmt.Fprintf(&b, "%d", (t.Hour()+11)%12+1)
+		case 'M':
+			fmt.Fprintf(&b, "%02d", t.Minute())
+		case 'S':
+			fmt.Fprintf(&b, "%02d", t.Second())
+		case 'p':
+			b.WriteString(t.Format("PM"))
+		case 'a':
+			b.WriteString(t.Format("Mon"))
+		case 'A':
+			b.WriteString(t.Format("Monday"))
+		case 'b':
+			b.WriteString(t.Format("Jan"))
+		case 'B':
+			b.WriteString(t.Format("January"))
+		case 'd':
+			fmt.Fprintf(&b, "%02d", t.Day())
+		case 'e':
+			fmt.Fprintf(&b, "%2d", t.Day())
+		case 'm':
+			fmt.Fprintf(&b, "%02d", int(t.Month()))
+		case 'y':
+			fmt.Fprintf(&b, "%02d", t.Year()%100)
+		case 'Y':
+			fmt.Fprintf(&b, "%d", t.Year())
+		case 'j':
+			fmt.Fprintf(&b, "%03d", t.YearDay())
+		case 'Z':
+			b.WriteString(t.Format("MST"))
+		case 'z':
+			b.WriteString(t.Format("-0700"))
+		case '%':
+			b.WriteByte('%')
+		default:
+			b.WriteByte('%')
+			b.WriteByte(format[i])
+		}
+	}
+	return b.String()
+}
@@ -3,13 +3,17 @@ package sections
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ll931217/claude-hud-enhanced/internal/config"
 	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+	"github.com/ll931217/claude-hud-enhanced/internal/humanize"
+	"github.com/ll931217/claude-hud-enhanced/internal/i18n"
 	"github.com/ll931217/claude-hud-enhanced/internal/registry"
 	"github.com/ll931217/claude-hud-enhanced/internal/statusline"
+	"github.com/ll931217/claude-hud-enhanced/internal/styled"
 	"github.com/ll931217/claude-hud-enhanced/internal/theme"
 	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
 )
@@ -17,7 +21,6 @@ import (
 // ContextBarSection displays context window progress bar with color coding
 type ContextBarSection struct {
 	*BaseSection
-	parser *transcript.Parser
 }
 
 // NewContextBarSection creates a new context bar section (factory function for registry)
@@ -27,32 +30,112 @@ func NewContextBarSection(cfg interface{}) (registry.Section, error) {
 		appConfig = config.DefaultConfig()
 	}
 
-	transcriptPath := getTranscriptPath()
 	base := NewBaseSection("contextbar", appConfig)
 	base.SetPriority(registry.PriorityEssential) // Essential - always show context
 	base.SetMinWidth(6)                          // "█ 0%" minimum
 
 	return &ContextBarSection{
 		BaseSection: base,
-		parser:      transcript.NewParser(transcriptPath),
 	}, nil
 }
 
 func init() {
-	registry.Register("contextbar", NewContextBarSection)
+	registry.RegisterWithMetadata("contextbar", NewContextBarSection, registry.Metadata{
+		Description: "Context window usage bar",
+		Priority:    registry.PriorityEssential,
+		Options: []registry.Option{
+			{Name: "warning_percent", Type: "int", Default: "70", Description: "Color the bar yellow at or above this usage"},
+			{Name: "critical_percent", Type: "int", Default: "85", Description: "Color the bar red at or above this usage"},
+			{Name: "bar_width", Type: "int", Default: "10", Description: "Bar width in characters (1-50)"},
+			{Name: "bar_filled", Type: "string", Default: "█", Description: "Glyph for the used part of the bar"},
+			{Name: "bar_empty", Type: "string", Default: "░", Description: "Glyph for the free part of the bar"},
+			{Name: "show_breakdown", Type: "bool", Default: "true", Description: "Show input and cache tokens at or above critical_percent"},
+			{Name: "pulse_percent", Type: "int", Default: "90", Description: "Pulse the bar above this usage when animate is on (0 disables)"},
+		},
+		Dependencies: []registry.Dependency{depStatusline, depTranscript},
+	})
 }
 
+// longContextMarker follows the bar once the context is past 200k tokens,
+// where long-context pricing applies
+const longContextMarker = "»200k"
+
 // Render returns the context bar section output
 func (c *ContextBarSection) Render() string {
+	return styled.Render(styled.ANSI{}, c.RenderStyled())
+}
+
+// RenderStyled implements registry.Styler, returning the frame Render shows
+// as styled text
+func (c *ContextBarSection) RenderStyled() styled.Text {
+	return registry.FrameAt(c.styledFrames(), time.Now())
+}
+
+// RenderFrames implements registry.Animated: past pulse_percent the bar
+// pulses between its color and a dimmed one
+func (c *ContextBarSection) RenderFrames() []string {
+	texts := c.styledFrames()
+	frames := make([]string, len(texts))
+	for i, text := range texts {
+		frames[i] = styled.Render(styled.ANSI{}, text)
+	}
+	return frames
+}
+
+// styledFrames renders the usage as styled text, in two frames when the bar pulses
+func (c *ContextBarSection) styledFrames() []styled.Text {
+	usage, ok := c.usage()
+	if !ok {
+		return nil
+	}
+	return c.usageFrames(usage.percentage, usage.breakdown, usage.exceeds)
+}
+
+// Describe implements registry.Describer, e.g. "context: 72 percent, warning"
+func (c *ContextBarSection) Describe() string {
+	usage, ok := c.usage()
+	if !ok {
+		return ""
+	}
+	bo := c.barOptions()
+	text := i18n.T("plain.context", i18n.T("plain.percent", strconv.Itoa(usage.percentage)))
+	switch {
+	case usage.percentage >= bo.critical:
+		text += ", " + i18n.T("plain.critical")
+	case usage.percentage >= bo.warning:
+		text += ", " + i18n.T("plain.warning")
+	}
+	if usage.exceeds {
+		text += ", " + i18n.T("plain.long_context")
+	}
+	return text
+}
+
+// contextUsage is how full the context window is
+type contextUsage struct {
+	percentage int
+	breakdown  string // Input and cache tokens, e.g. "(in: 40k, cache: 100k)"
+	exceeds    bool   // Past 200k tokens, where long-context pricing applies
+}
+
+// usage reads the context window usage, from Claude Code's input or else
+// the transcript
+func (c *ContextBarSection) usage() (contextUsage, bool) {
 	// First, try to get context window data from Claude Code's JSON input (most reliable)
 	windowSize := statusline.GetContextWindowSize()
 	inputTokens := statusline.GetContextInputTokens()
 	cacheTokens := statusline.GetContextCacheTokens()
+	exceeds := statusline.Exceeds200kTokens()
 
 	// Only use stdin data if we have actual token counts (not just zeros)
 	if windowSize > 0 && (inputTokens > 0 || cacheTokens > 0) {
 		// Calculate percentage from JSON input data
 		totalTokens := inputTokens + cacheTokens
+		exceeds = exceeds || totalTokens > transcript.STANDARD_CONTEXT_WINDOW
+		// Past 200k the session must be on a 1M-context model, whatever the reported size
+		if (exceeds || isLongContextSession()) && windowSize < transcript.LONG_CONTEXT_WINDOW {
+			windowSize = transcript.LONG_CONTEXT_WINDOW
+		}
 		percentage := (totalTokens * 100) / windowSize
 		if percentage > 100 {
 			percentage = 100
@@ -61,30 +144,20 @@ func (c *ContextBarSection) Render() string {
 			percentage = 0
 		}
 
-		bar := c.progressBar(percentage, 10) // 10-char width
-		color := theme.ContextColor(percentage)
+		c.MarkHealthy()
 
-		// Show format: "72%" without brackets as user requested
-		result := fmt.Sprintf("%s%s %d%%", color, bar, percentage)
-		if color != "" {
-			result += theme.Reset
+		var parts []string
+		if inputTokens > 0 {
+			parts = append(parts, fmt.Sprintf("in: %s", humanize.Count(inputTokens)))
 		}
-
-		// Add token breakdown at high context usage
-		if percentage >= 85 {
-			var parts []string
-			if inputTokens > 0 {
-				parts = append(parts, fmt.Sprintf("in: %s", formatTokens(inputTokens)))
-			}
-			if cacheTokens > 0 {
-				parts = append(parts, fmt.Sprintf("cache: %s", formatTokens(cacheTokens)))
-			}
-			if len(parts) > 0 {
-				result += fmt.Sprintf("%s (%s)%s", theme.Dim, strings.Join(parts, ", "), theme.Reset)
-			}
+		if cacheTokens > 0 {
+			parts = append(parts, fmt.Sprintf("cache: %s", humanize.Count(cacheTokens)))
 		}
-
-		return result
+		breakdown := ""
+		if len(parts) > 0 {
+			breakdown = fmt.Sprintf("(%s)", strings.Join(parts, ", "))
+		}
+		return contextUsage{percentage: percentage, breakdown: breakdown, exceeds: exceeds}, true
 	}
 
 	// Fallback: Try to get from transcript parser
@@ -92,12 +165,24 @@ func (c *ContextBarSection) Render() string {
 	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 	defer cancel()
 
-	_ = c.parser.Parse(ctx) // Try to parse, but don't fail if it doesn't work
+	transcriptPath := c.getTranscriptPath()
+	if transcriptPath == "" {
+		c.MarkUnavailable("no context window data or transcript")
+		return contextUsage{}, false
+	}
+
+	parser := c.Providers().Transcript(transcriptPath)
+	// Keep going on parse errors: data from earlier parses is still usable
+	if err := parser.Parse(ctx); err != nil {
+		c.MarkDegraded(fmt.Sprintf("transcript unreadable: %v", err))
+	} else {
+		c.MarkHealthy()
+	}
 
-	cw := c.parser.GetContextWindow()
+	cw := parser.GetContextWindow()
 	if cw == nil {
 		// No context window data available
-		return ""
+		return contextUsage{}, false
 	}
 	if cw.ContextWindowSize == 0 {
 		// Debug: log why context window size is 0
@@ -105,48 +190,115 @@ func (c *ContextBarSection) Render() string {
 		// Try to infer context window size from model name
 		// Note: We can't easily get model name here without duplicating logic
 		// For now, return empty
-		return ""
+		return contextUsage{}, false
+	}
+	if isLongContextSession() && cw.ContextWindowSize < transcript.LONG_CONTEXT_WINDOW {
+		cw.ContextWindowSize = transcript.LONG_CONTEXT_WINDOW
 	}
 
-	percentage := c.parser.GetContextPercentage()
-	bar := c.progressBar(percentage, 10) // 10-char width
-	color := theme.ContextColor(percentage)
+	return contextUsage{percentage: cw.Percentage(), breakdown: c.getTokenBreakdown(cw), exceeds: exceeds || cw.ExceedsStandardWindow()}, true
+}
+
+// barOptions holds the contextbar section options
+type barOptions struct {
+	warning, critical int
+	width             int
+	filled, empty     string
+	breakdown         bool
+	pulse             int
+}
 
-	// Show format: "72%" without brackets as user requested
-	// At high usage, show token breakdown
-	result := fmt.Sprintf("%s%s %d%%", color, bar, percentage)
-	if color != "" {
-		result += theme.Reset
+// barOptions reads the section options, falling back to the defaults for
+// out-of-range values
+func (c *ContextBarSection) barOptions() barOptions {
+	opts := c.GetConfig().SectionOptions(c.Name())
+	bo := barOptions{
+		warning:   opts.Int("warning_percent", theme.ContextWarningPercent),
+		critical:  opts.Int("critical_percent", theme.ContextCriticalPercent),
+		width:     opts.Int("bar_width", 10),
+		filled:    opts.String("bar_filled", "█"),
+		empty:     opts.String("bar_empty", "░"),
+		breakdown: opts.Bool("show_breakdown", true),
+		pulse:     opts.Int("pulse_percent", 90),
 	}
+	if bo.critical <= 0 || bo.critical > 100 {
+		bo.critical = theme.ContextCriticalPercent
+	}
+	if bo.warning <= 0 || bo.warning > bo.critical {
+		bo.warning = min(theme.ContextWarningPercent, bo.critical)
+	}
+	if bo.width < 1 || bo.width > 50 {
+		bo.width = 10
+	}
+	if bo.filled == "" {
+		bo.filled = "█"
+	}
+	if bo.empty == "" {
+		bo.empty = "░"
+	}
+	return bo
+}
+
+// usageFrames renders the usage, in two frames when the bar pulses
+func (c *ContextBarSection) usageFrames(percentage int, breakdown string, exceeds bool) []styled.Text {
+	bo := c.barOptions()
+	frames := []styled.Text{c.formatUsage(bo, percentage, breakdown, exceeds, false)}
+	if c.GetConfig().Animate && bo.pulse > 0 && percentage > bo.pulse {
+		frames = append(frames, c.formatUsage(bo, percentage, breakdown, exceeds, true))
+	}
+	return frames
+}
+
+// formatUsage renders the colored bar and percentage, e.g. "███░░░░░░░ 30%",
+// dimmed for the second frame of a pulse
+// At high usage the token breakdown follows, and past 200k tokens the long-context marker
+func (c *ContextBarSection) formatUsage(bo barOptions, percentage int, breakdown string, exceeds, dim bool) styled.Text {
+	bar := progressBar(percentage, bo.width, bo.filled, bo.empty)
+	level := styled.NoColor
+	switch {
+	case percentage >= bo.critical:
+		level = styled.Error
+	case percentage >= bo.warning:
+		level = styled.Warning
+	}
+
+	// Show format: "72%" without brackets as user requested; the marker
+	// of symbols mode goes before the percentage, not the bar
+	result := styled.Text(nil).Add(fmt.Sprintf("%s %s%d%%", bar, styled.Marker(level), percentage), styled.Style{Color: level, Dim: dim})
 
 	// Add token breakdown at high context usage
-	if percentage >= 85 {
-		breakdown := c.getTokenBreakdown(cw)
-		if breakdown != "" {
-			result += fmt.Sprintf("%s %s%s", theme.Dim, breakdown, theme.Reset)
-		}
+	if bo.breakdown && percentage >= bo.critical && breakdown != "" {
+		result = result.Add(" "+breakdown, styled.Dimmed)
 	}
 
+	if exceeds {
+		result = result.Add(" ", styled.None).Add(longContextMarker, styled.Caution)
+	}
 	return result
 }
 
-// progressBar creates a visual progress bar
-func (c *ContextBarSection) progressBar(percentage, width int) string {
+// isLongContextSession reports whether Claude Code runs a 1M-context beta model
+func isLongContextSession() bool {
+	return transcript.IsLongContextModel(statusline.GetModelID()) || transcript.IsLongContextModel(statusline.GetModelName())
+}
+
+// progressBar creates a visual progress bar from filled and empty glyphs
+func progressBar(percentage, width int, filled, empty string) string {
 	if width <= 0 {
 		width = 10 // Default to 10 chars
 	}
 
-	filled := percentage * width / 100
-	if filled > width {
-		filled = width
+	used := percentage * width / 100
+	if used > width {
+		used = width
 	}
 
-	empty := width - filled
-	if empty < 0 {
-		empty = 0
+	free := width - used
+	if free < 0 {
+		free = 0
 	}
 
-	return strings.Repeat("█", filled) + strings.Repeat("░", empty)
+	return strings.Repeat(filled, used) + strings.Repeat(empty, free)
 }
 
 // getTokenBreakdown returns token breakdown at high context usage
@@ -163,10 +315,10 @@ func (c *ContextBarSection) getTokenBreakdown(cw *transcript.ContextWindow) stri
 
 	var parts []string
 	if inputTokens > 0 {
-		parts = append(parts, fmt.Sprintf("in: %s", formatTokens(inputTokens)))
+		parts = append(parts, fmt.Sprintf("in: %s", humanize.Count(inputTokens)))
 	}
 	if cacheTokens > 0 {
-		parts = append(parts, fmt.Sprintf("cache: %s", formatTokens(cacheTokens)))
+		parts = append(parts, fmt.Sprintf("cache: %s", humanize.Count(cacheTokens)))
 	}
 
 	if len(parts) == 0 {
@@ -175,14 +327,3 @@ func (c *ContextBarSection) getTokenBreakdown(cw *transcript.ContextWindow) stri
 
 	return fmt.Sprintf("(%s)", strings.Join(parts, ", "))
 }
-
-// formatTokens formats a token count with suffix (k, M)
-func formatTokens(tokens int) string {
-	if tokens >= 1_000_000 {
-		return fmt.Sprintf("%.1fM", float64(tokens)/1_000_000)
-	}
-	if tokens >= 1_000 {
-		return fmt.Sprintf("%dk", tokens/1_000)
-	}
-	return fmt.Sprintf("%d", tokens)
-}
@@ -3,17 +3,26 @@ package sections
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/quota"
 	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/statusline"
 	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
 )
 
-// CostSection displays accumulated API costs for the session
+// CostSection displays accumulated API costs for the session, or the quota
+// left for sessions billed to a Claude subscription
 type CostSection struct {
 	*BaseSection
-	parser *transcript.Parser
+	billing func() quota.Billing              // Overrides billing detection when set
+	usage   func() (quota.Usage, bool, error) // Overrides the usage endpoint when set
+
+	detectOnce sync.Once
+	detected   quota.Billing
 }
 
 // NewCostSection creates a new cost section (factory function for registry)
@@ -23,37 +32,40 @@ func NewCostSection(cfg interface{}) (registry.Section, error) {
 		appConfig = config.DefaultConfig()
 	}
 
-	// Get transcript path from environment or use default
-	transcriptPath := getTranscriptPath()
-
 	base := NewBaseSection("cost", appConfig)
 	base.SetPriority(registry.PriorityImportant) // Important but not essential
 	base.SetMinWidth(10)                         // Minimum width for cost display
 
 	return &CostSection{
 		BaseSection: base,
-		parser:      transcript.NewParser(transcriptPath),
 	}, nil
 }
 
 // Render returns the cost section output
 func (c *CostSection) Render() string {
+	if billing := c.billingMode(); billing.Mode == quota.BillingSubscription {
+		return c.renderSubscription(billing)
+	}
+
 	// Get transcript path dynamically from global context
-	transcriptPath := getTranscriptPath()
+	transcriptPath := c.getTranscriptPath()
 	if transcriptPath == "" {
+		c.MarkUnavailable("no transcript")
 		return "" // Hide section if no transcript path
 	}
 
-	// Create a parser for the current transcript path
-	parser := transcript.NewParser(transcriptPath)
+	// Use the shared parser for the current transcript path
+	parser := c.Providers().Transcript(transcriptPath)
 
 	// Parse transcript for token data
 	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 	defer cancel()
 
 	if err := parser.Parse(ctx); err != nil {
+		c.MarkDegraded(fmt.Sprintf("transcript unreadable: %v", err))
 		return "" // Hide section on parse error
 	}
+	c.MarkHealthy()
 
 	// Calculate cost
 	cost := parser.CalculateCost()
@@ -68,34 +80,110 @@ func (c *CostSection) Render() string {
 		return ""
 	}
 
-	// Format cost based on magnitude
-	var costStr string
-	if cost < 0.01 {
-		costStr = fmt.Sprintf("$%.4f", cost)
-	} else if cost < 1.0 {
-		costStr = fmt.Sprintf("$%.3f", cost)
-	} else {
-		costStr = fmt.Sprintf("$%.2f", cost)
-	}
+	output := "💰 " + formatCost(cost)
 
 	// Calculate rate per hour
 	hoursElapsed := duration.Hours()
 	if hoursElapsed > 0.1 { // Only show rate after 6 minutes
-		ratePerHour := cost / hoursElapsed
-		var rateStr string
-		if ratePerHour < 0.01 {
-			rateStr = fmt.Sprintf("$%.4f/h", ratePerHour)
-		} else if ratePerHour < 1.0 {
-			rateStr = fmt.Sprintf("$%.3f/h", ratePerHour)
-		} else {
-			rateStr = fmt.Sprintf("$%.2f/h", ratePerHour)
+		output += " (" + formatCost(cost/hoursElapsed) + "/h)"
+	}
+
+	// Verbose mode expands mixed-model sessions into a per-model split
+	if c.GetConfig().SectionOptions(c.Name()).Bool("verbose", false) {
+		if split := formatModelSplit(parser.GetModelUsage()); split != "" {
+			output += " [" + split + "]"
 		}
-		return fmt.Sprintf("💰 %s (%s)", costStr, rateStr)
 	}
+	return output
+}
+
+// billingMode returns the billing mode forced in the options, or the detected one
+// Sessions that cannot be detected are priced in dollars
+func (c *CostSection) billingMode() quota.Billing {
+	switch mode := c.GetConfig().SectionOptions(c.Name()).String("mode", "auto"); mode {
+	case quota.BillingAPI, quota.BillingSubscription:
+		return quota.Billing{Mode: mode, Source: "config"}
+	}
+	if c.billing != nil {
+		return c.billing()
+	}
+	// Billing does not change during a session; detect it once
+	c.detectOnce.Do(func() {
+		c.detected = quota.DetectBilling(statusline.GetModelID())
+	})
+	return c.detected
+}
 
-	return fmt.Sprintf("💰 %s", costStr)
+// renderSubscription shows the quota left instead of a dollar figure, which
+// subscription users are not charged; without quota data it names the plan
+func (c *CostSection) renderSubscription(billing quota.Billing) string {
+	c.MarkHealthy()
+	readUsage := c.usage
+	if readUsage == nil {
+		readUsage = func() (quota.Usage, bool, error) { return subscriptionUsage(c.BaseSection) }
+	}
+	if usage, found, _ := readUsage(); found {
+		if left := formatUsageLeft(usage, true, false); left != "" {
+			return "💳 " + left
+		}
+	}
+	return "💳 " + formatPlan(billing.Plan)
+}
+
+// formatPlan names a subscription plan, e.g. "Max plan"
+func formatPlan(plan string) string {
+	if plan == "" {
+		return "subscription"
+	}
+	return strings.ToUpper(plan[:1]) + plan[1:] + " plan"
+}
+
+// formatCost formats a USD amount with precision based on its magnitude
+func formatCost(cost float64) string {
+	switch {
+	case cost < 0.01:
+		return fmt.Sprintf("$%.4f", cost)
+	case cost < 1.0:
+		return fmt.Sprintf("$%.3f", cost)
+	default:
+		return fmt.Sprintf("$%.2f", cost)
+	}
+}
+
+// formatModelSplit formats per-model costs, e.g. "opus $1.10 · haiku $0.130"
+// Single-model sessions have nothing to split and return ""
+func formatModelSplit(usage []transcript.ModelUsage) string {
+	if len(usage) < 2 {
+		return ""
+	}
+	parts := make([]string, 0, len(usage))
+	for _, u := range usage {
+		parts = append(parts, modelFamily(u.Model)+" "+formatCost(u.Cost))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// modelFamily shortens a model ID such as "claude-haiku-4-5-20251001" to "haiku"
+func modelFamily(model string) string {
+	for _, family := range []string{"opus", "sonnet", "haiku"} {
+		if strings.Contains(model, family) {
+			return family
+		}
+	}
+	if model == "" {
+		return "unknown"
+	}
+	return model
 }
 
 func init() {
-	registry.Register("cost", NewCostSection)
+	registry.RegisterWithMetadata("cost", NewCostSection, registry.Metadata{
+		Description: "Estimated API cost of the session, priced per model; quota left on Claude subscriptions",
+		Priority:    registry.PriorityImportant,
+		Options: []registry.Option{
+			{Name: "mode", Type: "string", Default: "auto", Description: "Billing mode: auto (detected), api (dollars) or subscription (quota left)"},
+			{Name: "verbose", Type: "bool", Default: "false", Description: "Split the cost by model when a session used several"},
+		},
+		Dependencies: []registry.Dependency{depTranscript},
+	})
 }
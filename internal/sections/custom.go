@@ -0,0 +1,155 @@
+package sections
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/statusline"
+)
+
+// CustomSection displays a configured template, optionally filled with a
+// shell command's output. Layouts can hold several, named "custom:<name>"
+type CustomSection struct {
+	*BaseSection
+	runCommand func(command, dir string, env []string, ttl time.Duration) (string, error) // Overrides the shared runner when set
+}
+
+// NewCustomSection creates a new custom section (factory function for registry)
+func NewCustomSection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("custom", appConfig)
+	base.SetPriority(registry.PriorityOptional) // Nice to have; hide first on narrow terminals
+	base.SetMinWidth(1)                         // Width depends entirely on the config
+
+	section := &CustomSection{
+		BaseSection: base,
+	}
+	section.applyTTL()
+	return section, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("custom", NewCustomSection, registry.Metadata{
+		Description: "Static text or a shell command's output; add more as custom:<name>",
+		Priority:    registry.PriorityOptional,
+		Options: []registry.Option{
+			{Name: "template", Type: "string", Default: "{output}", Description: "Text to show; {output}, {model}, {dir}, {workspace} and {env:NAME} are filled in"},
+			{Name: "command", Type: "string", Default: "", Description: "Shell command whose first output line fills {output}"},
+			{Name: "ttl_ms", Type: "duration_ms", Default: "30000", Description: "How long command output is reused before running it again"},
+			{Name: "timeout_ms", Type: "duration_ms", Default: "1000", Description: "How long the command may run"},
+		},
+	})
+}
+
+// SetInstanceName names this instance, e.g. "custom:ci" (registry.Instanced)
+func (c *CustomSection) SetInstanceName(name string) {
+	c.rename(name)
+	c.applyTTL()
+}
+
+// applyTTL caches the rendered output as long as the command output is reused
+func (c *CustomSection) applyTTL() {
+	c.SetCacheTTL(c.options().Duration("ttl_ms", 30*time.Second))
+}
+
+func (c *CustomSection) options() config.SectionOptions {
+	return c.GetConfig().SectionOptions(c.Name())
+}
+
+// Render returns the template with its placeholders filled in
+func (c *CustomSection) Render() string {
+	opts := c.options()
+	command := opts.String("command", "")
+	template := opts.String("template", "")
+	if command == "" && template == "" {
+		c.MarkUnavailable("set template or command")
+		return ""
+	}
+	if template == "" {
+		template = "{output}"
+	}
+
+	workspace := c.workingDir()
+
+	var output string
+	if command != "" {
+		env := []string{
+			"CLAUDE_HUD_MODEL=" + statusline.GetModelName(),
+			"CLAUDE_HUD_WORKSPACE=" + workspace,
+			"CLAUDE_HUD_TRANSCRIPT=" + c.getTranscriptPath(),
+		}
+		out, err := c.commandOutput(command, workspace, env, opts)
+		if err != nil {
+			// Keep showing the last output while the command fails
+			c.MarkDegraded(fmt.Sprintf("command failed: %v", err))
+			if out == "" {
+				return ""
+			}
+		} else {
+			c.MarkHealthy()
+		}
+		if out == "" {
+			return "" // Nothing to fill in
+		}
+		output = out
+	} else {
+		c.MarkHealthy()
+	}
+
+	return expandTemplate(template, func(key string) string {
+		switch {
+		case key == "output":
+			return output
+		case key == "model":
+			return statusline.GetModelName()
+		case key == "workspace":
+			return workspace
+		case key == "dir":
+			return filepath.Base(workspace)
+		case strings.HasPrefix(key, "env:"):
+			return os.Getenv(strings.TrimPrefix(key, "env:"))
+		}
+		return "{" + key + "}"
+	})
+}
+
+// commandOutput runs the command through the shared runner
+func (c *CustomSection) commandOutput(command, dir string, env []string, opts config.SectionOptions) (string, error) {
+	ttl := opts.Duration("ttl_ms", 30*time.Second)
+	if c.runCommand != nil {
+		return c.runCommand(command, dir, env, ttl)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Duration("timeout_ms", time.Second))
+	defer cancel()
+	return c.Providers().Commands().Output(ctx, command, dir, env, ttl)
+}
+
+// expandTemplate replaces each {key} in template with lookup(key)
+func expandTemplate(template string, lookup func(key string) string) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		b.WriteString(template[:start])
+		b.WriteString(lookup(template[start+1 : start+end]))
+		template = template[start+end+1:]
+	}
+	b.WriteString(template)
+	return b.String()
+}
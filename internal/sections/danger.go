@@ -0,0 +1,110 @@
+package sections
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+// DangerSection warns about dangerous Bash commands Claude ran in the session
+type DangerSection struct {
+	*BaseSection
+	dangerous func() ([]transcript.DangerousCommand, error) // Overrides the transcript when set
+	notify    func(title, message string)                   // Overrides desktop notifications when set
+}
+
+// NewDangerSection creates a new danger section (factory function for registry)
+func NewDangerSection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("danger", appConfig)
+	base.SetPriority(registry.PriorityEssential) // A warning must not be hidden on narrow terminals
+	base.SetMinWidth(12)                         // Minimum width for "⚠ rm -rf: rm…"
+
+	return &DangerSection{
+		BaseSection: base,
+	}, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("danger", NewDangerSection, registry.Metadata{
+		Description: "Warning for Bash commands matching a dangerous pattern, such as rm -rf or a force push",
+		Priority:    registry.PriorityEssential,
+		Options: []registry.Option{
+			{Name: "command_length", Type: "int", Default: "30", Description: "Shorten the latest dangerous command to this many characters"},
+			{Name: "notify", Type: "bool", Default: "false", Description: "Also send a desktop notification, once per command"},
+		},
+		Dependencies: []registry.Dependency{depTranscript},
+	})
+}
+
+// Render returns the danger section output, e.g. "⚠ rm -rf: rm -rf build/ +2",
+// for the latest dangerous command and how many others there were
+func (d *DangerSection) Render() string {
+	dangerous := d.dangerous
+	if dangerous == nil {
+		dangerous = d.transcriptDangerous
+	}
+	commands, err := dangerous()
+	if err != nil {
+		d.MarkDegraded(fmt.Sprintf("transcript unreadable: %v", err))
+		return ""
+	}
+	d.MarkHealthy()
+
+	if len(commands) == 0 {
+		return ""
+	}
+
+	opts := d.GetConfig().SectionOptions(d.Name())
+	if opts.Bool("notify", false) {
+		d.notifyNew(commands)
+	}
+
+	latest := commands[0]
+	command := strings.Join(strings.Fields(latest.Command), " ")
+	output := "⚠ " + latest.Rule + ": " + truncateTitle(command, opts.Int("command_length", 30))
+	if len(commands) > 1 {
+		output += fmt.Sprintf(" +%d", len(commands)-1)
+	}
+	return theme.Bold + theme.Red + output + theme.Reset
+}
+
+// notifyNew sends a desktop notification for each command not announced yet
+func (d *DangerSection) notifyNew(commands []transcript.DangerousCommand) {
+	notify := d.notify
+	if notify == nil {
+		notify = sendNotification
+	}
+	alerts := d.Providers().DangerAlerts()
+	for _, c := range commands {
+		if alerts.Notify(c.ToolUseID) {
+			notify("Dangerous command: "+c.Rule, truncateTitle(c.Command, 200))
+		}
+	}
+}
+
+// transcriptDangerous parses the current transcript for dangerous commands
+func (d *DangerSection) transcriptDangerous() ([]transcript.DangerousCommand, error) {
+	transcriptPath := d.getTranscriptPath()
+	if transcriptPath == "" {
+		return nil, nil
+	}
+
+	parser := d.Providers().Transcript(transcriptPath)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := parser.Parse(ctx); err != nil {
+		return nil, err
+	}
+	return parser.GetDangerousCommands(), nil
+}
@@ -0,0 +1,95 @@
+package sections
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/beads"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/system"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+// Data sources shared by section metadata; see registry.Dependency
+var (
+	// depStatusline is the JSON Claude Code passes on stdin in statusline mode
+	depStatusline = registry.Dependency{Name: "statusline input"}
+
+	depTranscript = registry.Dependency{Name: "transcript", Check: hasTranscripts}
+	depGit        = registry.Dependency{Name: "git", Check: hasGitRepo}
+	depBeads      = registry.Dependency{Name: "beads", Check: hasBeads}
+	depSource     = registry.Dependency{Name: "source files", Check: hasSourceFiles}
+	depClaudeHome = registry.Dependency{Name: "~/.claude", Check: hasClaudeHome}
+	depZaiKey     = registry.Dependency{Name: "GLM_API_KEY/ZAI_API_KEY", Check: hasZaiKey}
+	depBattery    = registry.Dependency{Name: "battery", Check: hasBattery}
+)
+
+// findUp reports whether rel exists in dir or any of its parents
+func findUp(dir, rel string) bool {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, rel)); err == nil {
+			return true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
+
+// hasGitRepo reports whether dir is inside a git repository or worktree
+func hasGitRepo(dir string) bool {
+	return findUp(dir, ".git")
+}
+
+// hasBeads reports whether dir is inside a project tracked with beads, in
+// either the JSONL or the SQLite format
+func hasBeads(dir string) bool {
+	return findUp(dir, filepath.Join(".beads", "issues.jsonl")) ||
+		findUp(dir, filepath.Join(".beads", beads.DefaultDatabaseName))
+}
+
+// hasSourceFiles reports whether a programming language can be detected in dir
+func hasSourceFiles(dir string) bool {
+	return system.DetectLanguage(dir) != ""
+}
+
+// hasTranscripts reports whether Claude Code has recorded sessions for dir
+func hasTranscripts(dir string) bool {
+	projectsDir, err := transcript.ProjectsDir()
+	if err != nil {
+		return false
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	matches, _ := filepath.Glob(filepath.Join(transcript.ProjectDirFor(projectsDir, abs), "*.jsonl"))
+	return len(matches) > 0
+}
+
+// hasClaudeHome reports whether the Claude Code config directory exists
+func hasClaudeHome(string) bool {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(homeDir, ".claude"))
+	return err == nil && info.IsDir()
+}
+
+// hasZaiKey reports whether a Z.ai API key is configured
+func hasZaiKey(string) bool {
+	return os.Getenv("GLM_API_KEY") != "" || os.Getenv("ZAI_API_KEY") != ""
+}
+
+// hasBattery reports whether the machine has a battery
+func hasBattery(string) bool {
+	info, err := system.GetBattery()
+	return err == nil && info.Present
+}
@@ -0,0 +1,92 @@
+package sections
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/diagnostics"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+)
+
+// DiagnosticsSection displays the editor's error and warning counts for the workspace
+type DiagnosticsSection struct {
+	*BaseSection
+}
+
+// NewDiagnosticsSection creates a new diagnostics section (factory function for registry)
+func NewDiagnosticsSection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("diagnostics", appConfig)
+	base.SetPriority(registry.PriorityImportant) // Compile errors matter while editing
+	base.SetMinWidth(4)                          // Minimum width for "✗ 2"
+	base.SetCacheTTL(time.Second)                // Editors rewrite the file on every change
+
+	return &DiagnosticsSection{
+		BaseSection: base,
+	}, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("diagnostics", NewDiagnosticsSection, registry.Metadata{
+		Description: "Error and warning counts from diagnostics your editor exports to a JSON file",
+		Priority:    registry.PriorityImportant,
+		Options: []registry.Option{
+			{Name: "path", Type: "string", Default: ".claude/diagnostics.json", Description: "Diagnostics file, relative to the workspace"},
+			{Name: "show_warnings", Type: "bool", Default: "true", Description: "Show warnings next to errors"},
+			{Name: "workspace_only", Type: "bool", Default: "true", Description: "Ignore diagnostics for files outside the workspace"},
+			{Name: "max_age_ms", Type: "duration_ms", Default: "0", Description: "Hide a file not rewritten for this long, e.g. after the editor closed (0 keeps it)"},
+		},
+	})
+}
+
+// Render returns the diagnostic counts, e.g. "✗ 2 ⚠ 5"
+func (d *DiagnosticsSection) Render() string {
+	opts := d.GetConfig().SectionOptions(d.Name())
+	workspace := d.workingDir()
+	path := opts.String("path", ".claude/diagnostics.json")
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(workspace, path)
+	}
+	filter := workspace
+	if !opts.Bool("workspace_only", true) {
+		filter = ""
+	}
+
+	counts, modTime, err := diagnostics.Read(path, filter)
+	if os.IsNotExist(err) {
+		d.MarkUnavailable("no diagnostics file at " + path)
+		return ""
+	}
+	if err != nil {
+		d.MarkDegraded(fmt.Sprintf("unreadable diagnostics: %v", err))
+		return ""
+	}
+	d.MarkHealthy()
+	if maxAge := opts.Duration("max_age_ms", 0); maxAge > 0 && time.Since(modTime) > maxAge {
+		return ""
+	}
+	return formatDiagnostics(counts, opts.Bool("show_warnings", true))
+}
+
+// formatDiagnostics renders error and warning counts, or nothing when clean
+func formatDiagnostics(counts diagnostics.Counts, showWarnings bool) string {
+	output := ""
+	if counts.Errors > 0 {
+		output = fmt.Sprintf("%s✗ %d%s", theme.Red, counts.Errors, theme.Reset)
+	}
+	if showWarnings && counts.Warnings > 0 {
+		if output != "" {
+			output += " "
+		}
+		output += fmt.Sprintf("%s⚠ %d%s", theme.Yellow, counts.Warnings, theme.Reset)
+	}
+	return output
+}
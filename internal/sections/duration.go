@@ -2,17 +2,22 @@ package sections
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/ll931217/claude-hud-enhanced/internal/config"
 	"github.com/ll931217/claude-hud-enhanced/internal/registry"
-	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+	"github.com/ll931217/claude-hud-enhanced/internal/styled"
 )
 
-// DurationSection displays session duration
+// DurationSection displays session duration, after the session's name when
+// it was given one with `claude-hud name`
 type DurationSection struct {
 	*BaseSection
-	parser *transcript.Parser
+	readName func(transcriptPath string) (string, error) // Overrides the shared state file when set
 }
 
 // NewDurationSection creates a new duration section (factory function for registry)
@@ -22,25 +27,89 @@ func NewDurationSection(cfg interface{}) (registry.Section, error) {
 		appConfig = config.DefaultConfig()
 	}
 
-	transcriptPath := getTranscriptPath()
 	base := NewBaseSection("duration", appConfig)
 	base.SetPriority(registry.PriorityImportant) // Important but not essential
 	base.SetMinWidth(2)                          // "0s" minimum
 
 	return &DurationSection{
 		BaseSection: base,
-		parser:      transcript.NewParser(transcriptPath),
 	}, nil
 }
 
 func init() {
-	registry.Register("duration", NewDurationSection)
+	registry.RegisterWithMetadata("duration", NewDurationSection, registry.Metadata{
+		Description:  "Session duration",
+		Priority:     registry.PriorityImportant,
+		Dependencies: []registry.Dependency{depTranscript},
+		Options: []registry.Option{
+			{Name: "show_name", Type: "bool", Default: "true", Description: "Show the session's name, set with `claude-hud name`"},
+		},
+	})
 }
 
-// Render returns the duration section output
+// Render returns the duration section output, e.g. "12m" or "auth refactor 12m"
 func (d *DurationSection) Render() string {
+	return styled.Render(styled.ANSI{}, d.RenderStyled())
+}
+
+// RenderStyled returns the duration section output as styled text, with the
+// session's name in bold
+func (d *DurationSection) RenderStyled() styled.Text {
 	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 	defer cancel()
-	_ = d.parser.Parse(ctx)
-	return d.parser.GetDuration()
+	transcriptPath := d.getTranscriptPath()
+	parser := d.Providers().Transcript(transcriptPath)
+	err := parser.Parse(ctx)
+	switch {
+	case transcriptPath == "":
+		d.MarkUnavailable("no transcript")
+	case err != nil:
+		d.MarkDegraded(fmt.Sprintf("transcript unreadable: %v", err))
+	default:
+		d.MarkHealthy()
+	}
+
+	duration := styled.Plain(parser.GetDuration())
+	if !d.GetConfig().SectionOptions(d.Name()).Bool("show_name", true) || transcriptPath == "" {
+		return duration
+	}
+	readName := d.readName
+	if readName == nil {
+		readName = loadSessionName
+	}
+	// An unreadable state file only costs the name; the duration still shows
+	name, err := readName(transcriptPath)
+	if err != nil || name == "" {
+		return duration
+	}
+	return styled.Text(nil).Add(name, styled.Strong).Add(" ", styled.None).Join(duration)
+}
+
+// loadSessionName reads the name of the session writing transcriptPath from
+// the shared state file
+func loadSessionName(transcriptPath string) (string, error) {
+	sess, err := loadHookSession(transcriptPath)
+	if err != nil || sess == nil {
+		return "", err
+	}
+	return sess.Name, nil
+}
+
+// loadHookSession reads the hook-recorded state of the session writing
+// transcriptPath from the shared state file; nil when hooks never saw it
+func loadHookSession(transcriptPath string) (*session.SessionState, error) {
+	store, err := session.DefaultStore()
+	if err != nil {
+		return nil, err
+	}
+	state, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+	sess := state.FindByTranscript(transcriptPath)
+	if sess == nil {
+		// Transcripts are named after their session
+		sess = state.Sessions[strings.TrimSuffix(filepath.Base(transcriptPath), ".jsonl")]
+	}
+	return sess, nil
 }
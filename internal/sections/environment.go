@@ -0,0 +1,72 @@
+package sections
+
+import (
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/system"
+)
+
+// environmentBadges are the icon and label shown for each environment
+var environmentBadges = map[system.Environment][2]string{
+	system.EnvDocker:       {"🐳", "docker"},
+	system.EnvPodman:       {"🦭", "podman"},
+	system.EnvKubernetes:   {"☸", "k8s"},
+	system.EnvLXC:          {"📦", "lxc"},
+	system.EnvWSL:          {"🐧", "wsl"},
+	system.EnvDevcontainer: {"📦", "devcontainer"},
+	system.EnvCodespaces:   {"☁", "codespace"},
+}
+
+// EnvironmentSection shows a badge when Claude Code runs in a container or
+// WSL, so a session there is not mistaken for one on the host
+type EnvironmentSection struct {
+	*BaseSection
+	detect func() system.Environment // Overrides detection when set
+}
+
+// NewEnvironmentSection creates a new environment section (factory function for registry)
+func NewEnvironmentSection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("environment", appConfig)
+	base.SetPriority(registry.PriorityOptional) // A reminder, not live data
+	base.SetMinWidth(2)                         // Minimum width for the icon alone
+
+	return &EnvironmentSection{
+		BaseSection: base,
+		detect:      system.DetectEnvironment,
+	}, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("environment", NewEnvironmentSection, registry.Metadata{
+		Description: "Badge when running inside Docker, Podman, Kubernetes, LXC, WSL, a dev container or a Codespace",
+		Priority:    registry.PriorityOptional,
+		Options: []registry.Option{
+			{Name: "icon_only", Type: "bool", Default: "false", Description: "Show only the icon, without the environment's name"},
+		},
+	})
+}
+
+// Render returns the environment badge, e.g. "🐳 docker", or nothing on a
+// plain host
+func (e *EnvironmentSection) Render() string {
+	env := e.detect()
+	if !env.Contained() {
+		e.MarkUnavailable("not in a container")
+		return ""
+	}
+	e.MarkHealthy()
+
+	badge, ok := environmentBadges[env]
+	if !ok {
+		badge = [2]string{"📦", string(env)}
+	}
+	if e.GetConfig().SectionOptions(e.Name()).Bool("icon_only", false) {
+		return badge[0]
+	}
+	return badge[0] + " " + badge[1]
+}
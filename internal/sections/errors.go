@@ -8,13 +8,11 @@ import (
 
 	"github.com/ll931217/claude-hud-enhanced/internal/config"
 	"github.com/ll931217/claude-hud-enhanced/internal/registry"
-	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
 )
 
 // ErrorsSection displays recent errors from transcript
 type ErrorsSection struct {
 	*BaseSection
-	parser *transcript.Parser
 }
 
 // NewErrorsSection creates a new errors section (factory function for registry)
@@ -24,37 +22,36 @@ func NewErrorsSection(cfg interface{}) (registry.Section, error) {
 		appConfig = config.DefaultConfig()
 	}
 
-	// Get transcript path from environment or use default
-	transcriptPath := getTranscriptPath()
-
 	base := NewBaseSection("errors", appConfig)
 	base.SetPriority(registry.PriorityImportant) // Important to see errors
 	base.SetMinWidth(15)                         // Minimum width for error display
 
 	return &ErrorsSection{
 		BaseSection: base,
-		parser:      transcript.NewParser(transcriptPath),
 	}, nil
 }
 
 // Render returns the errors section output
 func (e *ErrorsSection) Render() string {
 	// Get transcript path dynamically from global context
-	transcriptPath := getTranscriptPath()
+	transcriptPath := e.getTranscriptPath()
 	if transcriptPath == "" {
+		e.MarkUnavailable("no transcript")
 		return "" // Hide section if no transcript path
 	}
 
-	// Create a parser for the current transcript path
-	parser := transcript.NewParser(transcriptPath)
+	// Use the shared parser for the current transcript path
+	parser := e.Providers().Transcript(transcriptPath)
 
 	// Parse transcript for error data
 	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 	defer cancel()
 
 	if err := parser.Parse(ctx); err != nil {
+		e.MarkDegraded(fmt.Sprintf("transcript unreadable: %v", err))
 		return "" // Hide section on parse error
 	}
+	e.MarkHealthy()
 
 	// Get error counts (total and recent)
 	total, recent := parser.GetErrorCount(5) // Errors in last 5 minutes
@@ -87,5 +84,9 @@ func (e *ErrorsSection) Render() string {
 }
 
 func init() {
-	registry.Register("errors", NewErrorsSection)
+	registry.RegisterWithMetadata("errors", NewErrorsSection, registry.Metadata{
+		Description:  "Recent tool errors",
+		Priority:     registry.PriorityImportant,
+		Dependencies: []registry.Dependency{depTranscript},
+	})
 }
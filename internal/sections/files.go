@@ -0,0 +1,103 @@
+package sections
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+// FilesSection displays the files Claude changed in the session
+type FilesSection struct {
+	*BaseSection
+	touchedFiles func() ([]transcript.TouchedFile, error) // Overrides the transcript when set
+}
+
+// NewFilesSection creates a new files section (factory function for registry)
+func NewFilesSection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("files", appConfig)
+	base.SetPriority(registry.PriorityOptional) // Nice to have; hide first on narrow terminals
+	base.SetMinWidth(10)                        // Minimum width for "✎ 3 files"
+
+	return &FilesSection{
+		BaseSection: base,
+	}, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("files", NewFilesSection, registry.Metadata{
+		Description: "Files changed in the session, most recent first, with edit counts",
+		Priority:    registry.PriorityOptional,
+		Options: []registry.Option{
+			{Name: "max_files", Type: "int", Default: "3", Description: "File names to list after the count (0 shows the count only)"},
+		},
+		Dependencies: []registry.Dependency{depTranscript},
+	})
+}
+
+// Render returns the files section output, e.g. "✎ 5 files: login.go×3 auth.go routes.go +2"
+func (f *FilesSection) Render() string {
+	touchedFiles := f.touchedFiles
+	if touchedFiles == nil {
+		touchedFiles = f.transcriptFiles
+	}
+	files, err := touchedFiles()
+	if err != nil {
+		f.MarkDegraded(fmt.Sprintf("transcript unreadable: %v", err))
+		return ""
+	}
+	f.MarkHealthy()
+
+	if len(files) == 0 {
+		return ""
+	}
+
+	output := fmt.Sprintf("✎ %d files", len(files))
+	if len(files) == 1 {
+		output = "✎ 1 file"
+	}
+	maxFiles := f.GetConfig().SectionOptions(f.Name()).Int("max_files", 3)
+	if maxFiles <= 0 {
+		return output
+	}
+
+	names := make([]string, 0, maxFiles+1)
+	for i, file := range files {
+		if i == maxFiles {
+			names = append(names, fmt.Sprintf("+%d", len(files)-maxFiles))
+			break
+		}
+		name := filepath.Base(file.Path)
+		if file.Edits > 1 {
+			name += fmt.Sprintf("×%d", file.Edits)
+		}
+		names = append(names, name)
+	}
+	return output + ": " + strings.Join(names, " ")
+}
+
+// transcriptFiles parses the current transcript for the files it changed
+func (f *FilesSection) transcriptFiles() ([]transcript.TouchedFile, error) {
+	transcriptPath := f.getTranscriptPath()
+	if transcriptPath == "" {
+		return nil, nil
+	}
+
+	parser := f.Providers().Transcript(transcriptPath)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := parser.Parse(ctx); err != nil {
+		return nil, err
+	}
+	return parser.GetTouchedFiles(), nil
+}
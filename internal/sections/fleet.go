@@ -0,0 +1,158 @@
+package sections
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/fleet"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+// FleetSection shows the repository's worktrees side by side, with the
+// session working in each, for running several agents in worktrees of
+// their own
+type FleetSection struct {
+	*BaseSection
+	repoPath  string                                             // Resolved on first render
+	readFleet func(active time.Duration) ([]fleet.Member, error) // Overrides git and transcripts when set
+}
+
+// NewFleetSection creates a new fleet section (factory function for registry)
+func NewFleetSection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("fleet", appConfig)
+	base.SetPriority(registry.PriorityOptional) // Other worktrees matter less than this one
+	base.SetMinWidth(12)                        // Minimum width for "⑂ main ● 40%"
+	base.SetCacheTTL(5 * time.Second)           // Runs git status in every worktree
+
+	return &FleetSection{
+		BaseSection: base,
+	}, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("fleet", NewFleetSection, registry.Metadata{
+		Description:  "Each git worktree's branch, changes, session state and context use",
+		Priority:     registry.PriorityOptional,
+		Dependencies: []registry.Dependency{depGit},
+		Options: []registry.Option{
+			{Name: "active_ms", Type: "duration_ms", Default: "1800000", Description: "Sessions quiet for longer are left unmarked"},
+			{Name: "min_worktrees", Type: "int", Default: "2", Description: "Hide the section while the repository has fewer worktrees"},
+			{Name: "max_worktrees", Type: "int", Default: "4", Description: "Worktrees to show before \"+N\"; the current one is always shown"},
+		},
+	})
+}
+
+// Render returns the fleet section output, e.g.
+// "⑂ main ○ · auth± ● 72% · spike± ⏸ 41%"
+func (f *FleetSection) Render() string {
+	if f.repoPath == "" {
+		f.repoPath = f.getRepoPath()
+	}
+	opts := f.GetConfig().SectionOptions(f.Name())
+	active := opts.Duration("active_ms", fleet.DefaultActive)
+
+	readFleet := f.readFleet
+	if readFleet == nil {
+		readFleet = f.read
+	}
+	members, err := readFleet(active)
+	switch {
+	case err == context.DeadlineExceeded:
+		f.MarkDegraded("git timed out")
+		return ""
+	case err != nil:
+		f.MarkUnavailable("not a git repository")
+		return ""
+	}
+	f.MarkHealthy()
+	if len(members) == 0 || len(members) < opts.Int("min_worktrees", 2) {
+		return ""
+	}
+
+	shown := members
+	if limit := max(opts.Int("max_worktrees", 4), 1); len(members) > limit {
+		shown = slices.Clone(members[:limit])
+		isCurrent := func(m fleet.Member) bool { return m.Current }
+		if i := slices.IndexFunc(members, isCurrent); i >= limit {
+			shown[limit-1] = members[i] // The current worktree is always shown
+		}
+	}
+
+	parts := make([]string, 0, len(shown))
+	for _, m := range shown {
+		parts = append(parts, formatFleetMember(m))
+	}
+	output := "⑂ " + strings.Join(parts, " · ")
+	if hidden := len(members) - len(shown); hidden > 0 {
+		output += fmt.Sprintf(" · +%d", hidden)
+	}
+	return output
+}
+
+// read reads the worktrees with the shared git detectors and parsers
+func (f *FleetSection) read(active time.Duration) ([]fleet.Member, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	opts := fleet.Options{
+		Transcripts: f.Providers().Transcript,
+		Git:         f.Providers().Git,
+		Active:      active,
+	}
+	opts.ProjectsDir, _ = transcript.ProjectsDir()
+	if store, err := session.DefaultStore(); err == nil {
+		opts.State, _ = store.Load()
+	}
+	members, err := fleet.Read(ctx, f.repoPath, opts)
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return members, err
+}
+
+// formatFleetMember formats a worktree as its name, ± when it has changes,
+// and its session's state and context use, e.g. "auth± ● 72%". The current
+// worktree is in bold
+func formatFleetMember(m fleet.Member) string {
+	name := m.Name()
+	if m.Current {
+		name = theme.Bold + name + theme.Reset
+	}
+	switch {
+	case m.Conflicts > 0:
+		name += theme.Red + "‼" + theme.Reset
+	case m.Dirty:
+		name += "±"
+	}
+
+	sess := m.Session
+	if sess == nil || sess.State == fleet.Inactive {
+		return name
+	}
+	var mark string
+	switch sess.State {
+	case fleet.Working:
+		mark = theme.Green + "●" + theme.Reset
+	case fleet.Approval:
+		mark = theme.Bold + theme.Yellow + "⏸" + theme.Reset
+	default:
+		mark = "○"
+	}
+	output := name + " " + mark
+	if sess.ContextKnown {
+		output += fmt.Sprintf(" %d%%", sess.ContextPercent)
+	}
+	return output
+}
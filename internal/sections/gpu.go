@@ -0,0 +1,102 @@
+package sections
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/system"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+)
+
+// GPUSection displays GPU utilization and VRAM usage
+type GPUSection struct {
+	*BaseSection
+	readGPUs func(ctx context.Context) ([]system.GPUInfo, error) // Overrides the shared reader when set
+}
+
+// NewGPUSection creates a new GPU section (factory function for registry)
+func NewGPUSection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("gpu", appConfig)
+	base.SetPriority(registry.PriorityOptional) // Nice to have; hide first on narrow terminals
+	base.SetMinWidth(16)                        // Minimum width for "GPU 45% 3.2/8.0GB"
+	base.SetCacheTTL(10 * time.Second)          // Matches the GPU reader's own cache
+
+	return &GPUSection{
+		BaseSection: base,
+	}, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("gpu", NewGPUSection, registry.Metadata{
+		Description: "GPU utilization and VRAM (nvidia-smi, rocm-smi or powermetrics)",
+		Priority:    registry.PriorityOptional,
+		Options: []registry.Option{
+			{Name: "device", Type: "int", Default: "-1", Description: "Only show this GPU index (-1 shows all)"},
+			{Name: "show_name", Type: "bool", Default: "false", Description: "Show the GPU model name"},
+			{Name: "warning_percent", Type: "int", Default: "90", Description: "Show VRAM in yellow at or above this usage"},
+		},
+	})
+}
+
+// Render returns the GPU section output
+func (g *GPUSection) Render() string {
+	readGPUs := g.readGPUs
+	if readGPUs == nil {
+		readGPUs = g.Providers().GPU().GPUs
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	gpus, err := readGPUs(ctx)
+	if errors.Is(err, system.ErrNoGPU) {
+		g.MarkUnavailable(err.Error())
+		return ""
+	}
+	if err != nil {
+		g.MarkDegraded(fmt.Sprintf("GPU query: %v", err))
+		return ""
+	}
+	g.MarkHealthy()
+
+	opts := g.GetConfig().SectionOptions(g.Name())
+	if device := opts.Int("device", -1); device >= 0 {
+		if device >= len(gpus) {
+			return ""
+		}
+		gpus = gpus[device : device+1]
+	}
+
+	parts := make([]string, 0, len(gpus))
+	for _, gpu := range gpus {
+		parts = append(parts, g.formatGPU(gpu, opts))
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatGPU formats one GPU as "GPU 45% 3.2/8.0GB"
+func (g *GPUSection) formatGPU(gpu system.GPUInfo, opts config.SectionOptions) string {
+	label := "GPU"
+	if opts.Bool("show_name", false) && gpu.Name != "" {
+		label = gpu.Name
+	}
+	display := fmt.Sprintf("%s %.0f%%", label, gpu.UtilPercent)
+	if gpu.MemTotal == 0 {
+		return display
+	}
+
+	memory := fmt.Sprintf("%.1f/%.1fGB", float64(gpu.MemUsed)/(1<<30), float64(gpu.MemTotal)/(1<<30))
+	if gpu.MemPercent() >= float64(opts.Int("warning_percent", 90)) {
+		memory = theme.Signal(theme.Yellow, memory)
+	}
+	return display + " " + memory
+}
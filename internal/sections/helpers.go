@@ -3,12 +3,36 @@ package sections
 import (
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/ll931217/claude-hud-enhanced/internal/statusline"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
 )
 
+// discoveryTTL is how long a discovered transcript is reused before looking
+// again for a newer session
+const discoveryTTL = 2 * time.Second
+
+// discovered caches the transcript found for a directory, as every section
+// asks for it on every render
+var discovered struct {
+	mu   sync.Mutex
+	dir  string
+	path string
+	at   time.Time
+}
+
+// workingDir returns the directory the session is working in: Claude Code's
+// current directory, or the process's own in standalone mode. Data sources
+// take it explicitly rather than reading the process's working directory,
+// which stays wherever claude-hud was started
+func (b *BaseSection) workingDir() string {
+	return b.Providers().WorkingDir()
+}
+
 // getTranscriptPath returns the transcript path from context, environment, or default
-func getTranscriptPath() string {
+func (b *BaseSection) getTranscriptPath() string {
 	// Check global context from Claude Code first
 	if path := statusline.GetTranscriptPath(); path != "" {
 		return path
@@ -27,13 +51,36 @@ func getTranscriptPath() string {
 		"transcript.json",
 	}
 
+	dir := b.workingDir()
 	for _, loc := range locations {
-		if path, err := filepath.Abs(loc); err == nil {
+		if path, err := filepath.Abs(filepath.Join(dir, loc)); err == nil {
 			if _, err := os.Stat(path); err == nil {
 				return path
 			}
 		}
 	}
 
-	return ""
+	// Running in a terminal next to a session: follow the session Claude Code
+	// most recently wrote to in this directory
+	return discoverTranscript(dir)
+}
+
+// discoverTranscript returns the latest transcript of a session started in
+// dir or a parent of it
+func discoverTranscript(dir string) string {
+	if dir == "" {
+		return ""
+	}
+
+	discovered.mu.Lock()
+	defer discovered.mu.Unlock()
+	if discovered.dir == dir && time.Since(discovered.at) < discoveryTTL {
+		return discovered.path
+	}
+	projectsDir, err := transcript.ProjectsDir()
+	if err != nil {
+		return ""
+	}
+	discovered.dir, discovered.path, discovered.at = dir, transcript.LatestFor(projectsDir, dir), time.Now()
+	return discovered.path
 }
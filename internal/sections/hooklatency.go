@@ -0,0 +1,119 @@
+package sections
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/hook"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+)
+
+// HookLatencySection displays how much time user hooks add to each tool call
+type HookLatencySection struct {
+	*BaseSection
+	readRuns func() ([]hook.HookRun, error) // Overrides the session's debug log when set
+}
+
+// NewHookLatencySection creates a new hook latency section (factory function for registry)
+func NewHookLatencySection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("hooklatency", appConfig)
+	base.SetPriority(registry.PriorityOptional) // Nice to have; hide first on narrow terminals
+	base.SetMinWidth(16)                        // Minimum width for "Hooks +1.6s/call"
+	base.SetCacheTTL(5 * time.Second)           // The debug log is read incrementally
+
+	return &HookLatencySection{
+		BaseSection: base,
+	}, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("hooklatency", NewHookLatencySection, registry.Metadata{
+		Description: "Time user hooks add to each tool call, from Claude Code's debug log (start it with --debug)",
+		Priority:    registry.PriorityOptional,
+		Options: []registry.Option{
+			{Name: "slow_ms", Type: "duration_ms", Default: "1000", Description: "Flag hooks and per-call overhead at or above this"},
+			{Name: "show_slowest", Type: "bool", Default: "true", Description: "Name the slowest hook when it is over slow_ms"},
+		},
+		Dependencies: []registry.Dependency{depClaudeHome},
+	})
+}
+
+// Render returns the hook latency section output, e.g. "Hooks +1.6s/call ⚠lint.sh 1.5s"
+func (h *HookLatencySection) Render() string {
+	opts := h.GetConfig().SectionOptions(h.Name())
+	slow := opts.Duration("slow_ms", time.Second)
+
+	readRuns := h.readRuns
+	if readRuns == nil {
+		readRuns = h.debugLogRuns
+	}
+	runs, err := readRuns()
+	if errors.Is(err, fs.ErrNotExist) {
+		h.MarkUnavailable("no debug log for this session; start Claude Code with --debug")
+		return ""
+	}
+	if err != nil {
+		h.MarkUnavailable(err.Error())
+		return ""
+	}
+	h.MarkHealthy()
+
+	summary := hook.Summarize(runs, slow)
+	if summary.ToolCalls == 0 {
+		return ""
+	}
+
+	perCall := summary.PerToolCall()
+	output := "Hooks +" + formatHookDuration(perCall) + "/call"
+	if perCall >= slow {
+		output = theme.Signal(theme.Yellow, output)
+	}
+
+	if slowest, ok := summary.Slowest(); ok && opts.Bool("show_slowest", true) && slowest.Average() >= slow {
+		output += " " + theme.Yellow + "⚠" + hookName(slowest.Command) + " " + formatHookDuration(slowest.Average()) + theme.Reset
+	}
+	return output
+}
+
+// debugLogRuns reads the hook runs from the current session's debug log
+func (h *HookLatencySection) debugLogRuns() ([]hook.HookRun, error) {
+	transcriptPath := h.getTranscriptPath()
+	if transcriptPath == "" {
+		return nil, fmt.Errorf("no transcript to identify the session")
+	}
+	dir, err := hook.DebugLogDir()
+	if err != nil {
+		return nil, err
+	}
+	path := hook.DebugLogPath(dir, hook.SessionIDFromTranscript(transcriptPath))
+	return h.Providers().HookLatency(path).Runs()
+}
+
+// hookName shortens a hook command to its program name, e.g.
+// "~/bin/lint.sh --fix" becomes "lint.sh"
+func hookName(command string) string {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return command
+	}
+	return filepath.Base(fields[0])
+}
+
+// formatHookDuration formats a hook duration as "850ms" or "1.6s"
+func formatHookDuration(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
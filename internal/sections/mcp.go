@@ -0,0 +1,127 @@
+package sections
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/mcp"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+)
+
+// MCPSection displays configured MCP servers with their last health-check status
+type MCPSection struct {
+	*BaseSection
+	listServers  func(ctx context.Context) ([]*mcp.MCPServer, error) // Overrides the shared client when set
+	probeResults func() map[string]mcp.ProbeResult                   // Overrides the shared prober when set
+}
+
+// NewMCPSection creates a new MCP section (factory function for registry)
+func NewMCPSection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("mcp", appConfig)
+	base.SetPriority(registry.PriorityOptional) // Nice to have; hide first on narrow terminals
+	base.SetMinWidth(10)                        // Minimum width for "MCP ●●●"
+	base.SetCacheTTL(10 * time.Second)          // Probes run at most every minute
+
+	return &MCPSection{
+		BaseSection: base,
+	}, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("mcp", NewMCPSection, registry.Metadata{
+		Description: "MCP servers with health-check status (run `claude-hud mcp probe` or set mcp.probe_interval_ms)",
+		Priority:    registry.PriorityOptional,
+		Options: []registry.Option{
+			{Name: "show_names", Type: "bool", Default: "true", Description: "Show server names next to their status icons"},
+			{Name: "slow_ms", Type: "duration_ms", Default: "2000", Description: "Show servers whose handshake took longer in amber"},
+		},
+		Dependencies: []registry.Dependency{depClaudeHome},
+	})
+}
+
+// Render returns the MCP section output, e.g. "MCP ●github ●filesystem ○slack"
+func (m *MCPSection) Render() string {
+	listServers := m.listServers
+	if listServers == nil {
+		client := m.Providers().MCP()
+		client.SetProjectDir(m.Providers().ProjectDir())
+		listServers = func(ctx context.Context) ([]*mcp.MCPServer, error) {
+			err := client.DetectServers(ctx)
+			return client.GetServers(), err
+		}
+	}
+	probeResults := m.probeResults
+	if probeResults == nil {
+		probeResults = m.Providers().MCPProber().Results
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	// Servers from valid config files are listed even when another fails to parse
+	servers, err := listServers(ctx)
+	switch {
+	case err != nil:
+		m.MarkDegraded(err.Error())
+	case len(servers) == 0:
+		m.MarkUnavailable("no MCP servers configured")
+	default:
+		m.MarkHealthy()
+	}
+	if len(servers) == 0 {
+		return ""
+	}
+
+	opts := m.GetConfig().SectionOptions(m.Name())
+	showNames := opts.Bool("show_names", true)
+	slow := opts.Duration("slow_ms", 2*time.Second)
+
+	sort.Slice(servers, func(i, j int) bool {
+		return servers[i].Name < servers[j].Name
+	})
+	results := probeResults()
+
+	parts := make([]string, len(servers))
+	for i, server := range servers {
+		icon := mcpStatusIcon(results[server.Name], slow)
+		if showNames {
+			icon += server.Name
+		}
+		parts[i] = icon
+	}
+	separator := " "
+	if !showNames {
+		separator = ""
+	}
+	return "MCP " + strings.Join(parts, separator)
+}
+
+// mcpStatusIcon returns a green, amber or red dot for a probe result,
+// or a dim circle when the server has not been probed. In symbols mode the
+// dots become ✓, ⚠ and ✗, which read without telling the colors apart
+func mcpStatusIcon(result mcp.ProbeResult, slow time.Duration) string {
+	icon := func(dot, symbol string) string {
+		if theme.Symbols() {
+			return symbol
+		}
+		return dot
+	}
+	switch {
+	case result.CheckedAt.IsZero():
+		return theme.Dim + icon("○", "?") + theme.Reset
+	case !result.OK:
+		return theme.Red + icon("●", "✗") + theme.Reset
+	case slow > 0 && result.Latency >= slow:
+		return theme.Yellow + icon("●", theme.WarningMarker) + theme.Reset
+	default:
+		return theme.Green + icon("●", "✓") + theme.Reset
+	}
+}
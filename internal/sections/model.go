@@ -30,21 +30,47 @@ func NewModelSection(cfg interface{}) (registry.Section, error) {
 }
 
 func init() {
-	registry.Register("model", NewModelSection)
+	registry.RegisterWithMetadata("model", NewModelSection, registry.Metadata{
+		Description:  "Active Claude model",
+		Priority:     registry.PriorityEssential,
+		Dependencies: []registry.Dependency{depStatusline},
+	})
+}
+
+// modelAbbreviations shorten model names, e.g. "Claude Sonnet 4.5" to "SN 4.5"
+var modelAbbreviations = [...][2]string{
+	{"Claude ", ""},
+	{"Sonnet", "SN"},
+	{"Haiku", "HK"},
+	{"Opus", "OP"},
 }
 
 // Render returns the model section output
 func (m *ModelSection) Render() string {
+	return string(m.AppendRender(nil))
+}
+
+// AppendRender implements registry.Appender, shortening the model name as
+// it is appended
+func (m *ModelSection) AppendRender(dst []byte) []byte {
 	model := statusline.GetModelName()
 	if model == "" {
-		return ""
+		m.MarkUnavailable("no model in statusline input")
+		return dst
 	}
+	m.MarkHealthy()
 
-	// Shorten model name
-	model = strings.ReplaceAll(model, "Claude ", "")
-	model = strings.ReplaceAll(model, "Sonnet", "SN")
-	model = strings.ReplaceAll(model, "Haiku", "HK")
-	model = strings.ReplaceAll(model, "Opus", "OP")
-
-	return model
+next:
+	for i := 0; i < len(model); {
+		for _, abbr := range modelAbbreviations {
+			if strings.HasPrefix(model[i:], abbr[0]) {
+				dst = append(dst, abbr[1]...)
+				i += len(abbr[0])
+				continue next
+			}
+		}
+		dst = append(dst, model[i])
+		i++
+	}
+	return dst
 }
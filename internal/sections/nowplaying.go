@@ -0,0 +1,121 @@
+package sections
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/system"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+)
+
+// NowPlayingSection displays the track a media player is playing
+type NowPlayingSection struct {
+	*BaseSection
+	nowPlaying func(preferred string) (system.Track, bool, error) // Overrides the media player query when set
+}
+
+// NewNowPlayingSection creates a new now playing section (factory function for registry)
+func NewNowPlayingSection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("nowplaying", appConfig)
+	base.SetPriority(registry.PriorityOptional) // Nice to have; hide first on narrow terminals
+	base.SetMinWidth(10)                        // Minimum width for a short title
+	base.SetCacheTTL(5 * time.Second)           // Tracks change every few minutes
+
+	return &NowPlayingSection{
+		BaseSection: base,
+	}, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("nowplaying", NewNowPlayingSection, registry.Metadata{
+		Description: "Track playing in Spotify or any MPRIS player (Linux D-Bus, macOS Spotify/Music)",
+		Priority:    registry.PriorityOptional,
+		Options: []registry.Option{
+			{Name: "player", Type: "string", Default: "", Description: "Prefer this player when several are loaded, e.g. \"spotify\""},
+			{Name: "max_length", Type: "int", Default: "40", Description: "Shorten \"artist – title\" to this many characters"},
+			{Name: "show_paused", Type: "bool", Default: "false", Description: "Keep showing a paused track"},
+			{Name: "show_artist", Type: "bool", Default: "true", Description: "Show the artist before the title"},
+		},
+	})
+}
+
+// Render returns the current track, e.g. "♪ Massive Attack – Teardrop"
+func (n *NowPlayingSection) Render() string {
+	opts := n.GetConfig().SectionOptions(n.Name())
+	query := n.nowPlaying
+	if query == nil {
+		query = queryNowPlaying
+	}
+	track, ok, err := query(opts.String("player", ""))
+	if err != nil {
+		n.MarkUnavailable(fmt.Sprintf("no media player bus: %v", err))
+		return ""
+	}
+	n.MarkHealthy()
+	if !ok || (!track.Playing && !opts.Bool("show_paused", false)) {
+		return ""
+	}
+
+	artist := ""
+	if opts.Bool("show_artist", true) {
+		artist = track.Artist
+	}
+	text := formatTrack(artist, track.Title, opts.Int("max_length", 40))
+	if !track.Playing {
+		return theme.Dim + "⏸ " + text + theme.Reset
+	}
+	return "♪ " + text
+}
+
+// queryNowPlaying asks the platform's media players, bounded so a hung bus cannot stall rendering
+func queryNowPlaying(preferred string) (system.Track, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	return system.GetNowPlaying(ctx, preferred)
+}
+
+// trackDecoration matches suffixes that add length but little meaning, e.g.
+// " (Remastered 2011)", " - Live at Wembley" or " [feat. Someone]"
+var trackDecoration = regexp.MustCompile(`\s*(\([^)]*\)|\[[^\]]*\]|\s-\s.*)$`)
+
+// formatTrack joins artist and title within maxLen characters
+// Decorations are dropped from the title first, then the artist is shortened,
+// then dropped; the title is only cut when it does not fit on its own
+func formatTrack(artist, title string, maxLen int) string {
+	const sep = " – "
+	fits := func(s string) bool { return maxLen <= 0 || utf8.RuneCountInString(s) <= maxLen }
+	join := func(artist, title string) string {
+		if artist == "" {
+			return title
+		}
+		return artist + sep + title
+	}
+
+	if full := join(artist, title); fits(full) {
+		return full
+	}
+	if short := strings.TrimSpace(trackDecoration.ReplaceAllString(title, "")); short != "" {
+		title = short
+	}
+	if full := join(artist, title); fits(full) {
+		return full
+	}
+
+	// Keep at least a few characters of the artist, otherwise drop it
+	budget := maxLen - utf8.RuneCountInString(title) - utf8.RuneCountInString(sep)
+	if artist != "" && budget >= 5 {
+		return truncateTitle(artist, budget) + sep + title
+	}
+	return truncateTitle(title, maxLen)
+}
@@ -1,10 +1,38 @@
 package sections
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ll931217/claude-hud-enhanced/internal/ci"
 	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/fleet"
+	"github.com/ll931217/claude-hud-enhanced/internal/git"
+	"github.com/ll931217/claude-hud-enhanced/internal/hook"
+	"github.com/ll931217/claude-hud-enhanced/internal/mcp"
+	"github.com/ll931217/claude-hud-enhanced/internal/providers"
+	"github.com/ll931217/claude-hud-enhanced/internal/quota"
 	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+	"github.com/ll931217/claude-hud-enhanced/internal/statusline"
+	"github.com/ll931217/claude-hud-enhanced/internal/statuspage"
+	"github.com/ll931217/claude-hud-enhanced/internal/styled"
+	"github.com/ll931217/claude-hud-enhanced/internal/system"
+	"github.com/ll931217/claude-hud-enhanced/internal/tasks"
+	"github.com/ll931217/claude-hud-enhanced/internal/testresults"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+	"github.com/ll931217/claude-hud-enhanced/internal/weather"
 )
 
 // TestAgentsSectionCreation tests that the agents section can be created
@@ -48,6 +76,31 @@ func TestAgentsSectionRender(t *testing.T) {
 	_ = output
 }
 
+// TestAgentsSectionOrder tests that agents are shown most recent first
+func TestAgentsSectionOrder(t *testing.T) {
+	transcriptPath := filepath.Join(t.TempDir(), "agents.jsonl")
+	var b strings.Builder
+	for i, name := range []string{"planner", "architect", "debugger", "doc-updater"} {
+		fmt.Fprintf(&b, `{"type":"agent_run","timestamp":"2026-01-11T03:00:0%d.000Z","agent_run":{"agent_id":"z%d","agent_name":%q,"status":"completed"}}`+"\n", i, 9-i, name)
+	}
+	if err := os.WriteFile(transcriptPath, []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	statusline.SetContext(transcriptPath, "")
+	t.Cleanup(func() { statusline.SetContext("", "") })
+
+	section, err := NewAgentsSection(config.DefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to create agents section: %v", err)
+	}
+	want := "✓ Docs | ✓ Debug | ✓ Arch"
+	for i := 0; i < 10; i++ {
+		if got := section.Render(); got != want {
+			t.Fatalf("Render() = %q, want %q", got, want)
+		}
+	}
+}
+
 // TestCostSectionCreation tests that the cost section can be created
 func TestCostSectionCreation(t *testing.T) {
 	cfg := config.DefaultConfig()
@@ -333,3 +386,1965 @@ func TestOptionalSectionsRegistered(t *testing.T) {
 		})
 	}
 }
+
+// TestBatterySectionRender tests battery display, thresholds and hide_on_ac
+func TestBatterySectionRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		info    system.BatteryInfo
+		options config.SectionOptions
+		want    string
+	}{
+		{
+			name: "no battery",
+			info: system.BatteryInfo{},
+			want: "",
+		},
+		{
+			name: "discharging with time",
+			info: system.BatteryInfo{Present: true, Percent: 80, State: system.BatteryDischarging, TimeRemaining: 3*time.Hour + 5*time.Minute},
+			want: "🔋 80% " + theme.Dim + "(3h05m)" + theme.Reset,
+		},
+		{
+			name: "critical while unplugged",
+			info: system.BatteryInfo{Present: true, Percent: 10, State: system.BatteryDischarging},
+			want: "🔋 " + theme.Red + "10%" + theme.Reset,
+		},
+		{
+			name:    "custom warning threshold",
+			info:    system.BatteryInfo{Present: true, Percent: 45, State: system.BatteryDischarging},
+			options: config.SectionOptions{"warning_percent": 50},
+			want:    "🔋 " + theme.Yellow + "45%" + theme.Reset,
+		},
+		{
+			name: "charging is never colored",
+			info: system.BatteryInfo{Present: true, Percent: 10, State: system.BatteryCharging},
+			want: "⚡ 10%",
+		},
+		{
+			name:    "hidden on AC",
+			info:    system.BatteryInfo{Present: true, Percent: 100, State: system.BatteryFull},
+			options: config.SectionOptions{"hide_on_ac": true},
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"battery": tt.options}
+			section, err := NewBatterySection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			battery := section.(*BatterySection)
+			battery.readBattery = func() (system.BatteryInfo, error) { return tt.info, nil }
+
+			if got := battery.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestAPIStatusSectionRender tests incident display and coloring
+func TestAPIStatusSectionRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		options config.SectionOptions
+		want    string
+	}{
+		{
+			name: "operational",
+			body: `{"components":[{"name":"Claude API","status":"operational"}]}`,
+			want: "",
+		},
+		{
+			name: "minor incident",
+			body: `{"incidents":[{"name":"Elevated latency","impact":"minor","components":[{"name":"Claude API"}]}]}`,
+			want: theme.Yellow + "⚠ Elevated latency" + theme.Reset,
+		},
+		{
+			name:    "major incident truncated",
+			body:    `{"incidents":[{"name":"Elevated error rates","impact":"major"}]}`,
+			options: config.SectionOptions{"max_length": 10},
+			want:    theme.Red + "⚠ Elevated …" + theme.Reset,
+		},
+		{
+			name: "maintenance is not shown",
+			body: `{"incidents":[{"name":"Scheduled work","impact":"maintenance"}]}`,
+			want: "",
+		},
+		{
+			name:    "unwatched component",
+			body:    `{"incidents":[{"name":"Console down","impact":"critical","components":[{"name":"Console"}]}]}`,
+			options: config.SectionOptions{"components": []interface{}{"Claude Code"}},
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"apistatus": tt.options}
+			section, err := NewAPIStatusSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			apiStatus := section.(*APIStatusSection)
+			apiStatus.client = statuspage.NewClient("")
+			apiStatus.client.URL = server.URL
+
+			if got := apiStatus.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+			if health := apiStatus.Health(); !health.OK() {
+				t.Errorf("Health() = %+v, want OK", health)
+			}
+		})
+	}
+}
+
+// TestProcessSectionRender tests process tree display and health
+func TestProcessSectionRender(t *testing.T) {
+	cfg := config.DefaultConfig()
+	section, err := NewProcessSection(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	process := section.(*ProcessSection)
+
+	process.findProcess = func(string) (int, error) { return 0, fmt.Errorf("no claude process") }
+	if got := process.Render(); got != "" {
+		t.Errorf("Render() without process = %q, want empty", got)
+	}
+	if health := process.Health(); health.State != registry.HealthUnavailable {
+		t.Errorf("Health() = %+v, want unavailable", health)
+	}
+
+	process.findProcess = func(string) (int, error) { return os.Getppid(), nil }
+	got := process.Render()
+	if !process.Health().OK() {
+		t.Skipf("process listing unavailable: %+v", process.Health())
+	}
+	if !strings.HasPrefix(got, "claude ") || !strings.HasSuffix(got, "MB") {
+		t.Errorf("Render() = %q, want \"claude [cpu%%] <n>MB\"", got)
+	}
+}
+
+// TestFormatMemory tests memory formatting
+func TestFormatMemory(t *testing.T) {
+	tests := map[uint64]string{
+		890 << 20:  "890MB",
+		3 << 29:    "1.5GB",
+		512 << 10:  "0MB",
+		1288490189: "1.2GB",
+	}
+	for bytes, want := range tests {
+		if got := formatMemory(bytes); got != want {
+			t.Errorf("formatMemory(%d) = %q, want %q", bytes, got, want)
+		}
+	}
+}
+
+// TestGPUSectionRender tests GPU display, device selection and health
+func TestGPUSectionRender(t *testing.T) {
+	gpus := []system.GPUInfo{
+		{Name: "RTX 4090", UtilPercent: 45, MemUsed: 3 << 30, MemTotal: 24 << 30},
+		{Name: "Apple GPU", UtilPercent: 7},
+	}
+	tests := []struct {
+		name    string
+		gpus    []system.GPUInfo
+		err     error
+		options config.SectionOptions
+		want    string
+		state   registry.HealthState
+	}{
+		{
+			name:  "all GPUs",
+			gpus:  gpus,
+			want:  "GPU 45% 3.0/24.0GB GPU 7%",
+			state: registry.HealthOK,
+		},
+		{
+			name:    "single device with name",
+			gpus:    gpus,
+			options: config.SectionOptions{"device": 0, "show_name": true},
+			want:    "RTX 4090 45% 3.0/24.0GB",
+			state:   registry.HealthOK,
+		},
+		{
+			name:  "VRAM warning",
+			gpus:  []system.GPUInfo{{UtilPercent: 99, MemUsed: 23 << 30, MemTotal: 24 << 30}},
+			want:  "GPU 99% " + theme.Yellow + "23.0/24.0GB" + theme.Reset,
+			state: registry.HealthOK,
+		},
+		{
+			name:  "no GPU tool",
+			err:   system.ErrNoGPU,
+			state: registry.HealthUnavailable,
+		},
+		{
+			name:  "query failure",
+			err:   fmt.Errorf("nvidia-smi: exit status 9"),
+			state: registry.HealthDegraded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"gpu": tt.options}
+			section, err := NewGPUSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gpu := section.(*GPUSection)
+			gpu.readGPUs = func(context.Context) ([]system.GPUInfo, error) { return tt.gpus, tt.err }
+
+			if got := gpu.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+			if state := gpu.Health().State; state != tt.state {
+				t.Errorf("Health().State = %v, want %v", state, tt.state)
+			}
+		})
+	}
+}
+
+// TestMCPSectionRender tests MCP server status icons
+func TestMCPSectionRender(t *testing.T) {
+	servers := []*mcp.MCPServer{{Name: "slack"}, {Name: "github"}, {Name: "fs"}, {Name: "db"}}
+	now := time.Now()
+	results := map[string]mcp.ProbeResult{
+		"github": {Server: "github", OK: true, Latency: 300 * time.Millisecond, CheckedAt: now},
+		"fs":     {Server: "fs", OK: true, Latency: 3 * time.Second, CheckedAt: now},
+		"db":     {Server: "db", OK: false, Error: "exit status 1", CheckedAt: now},
+	}
+	green := theme.Green + "●" + theme.Reset
+	amber := theme.Yellow + "●" + theme.Reset
+	red := theme.Red + "●" + theme.Reset
+	unknown := theme.Dim + "○" + theme.Reset
+
+	tests := []struct {
+		name    string
+		servers []*mcp.MCPServer
+		err     error
+		options config.SectionOptions
+		want    string
+		state   registry.HealthState
+	}{
+		{
+			name:    "names",
+			servers: servers,
+			want:    "MCP " + red + "db " + amber + "fs " + green + "github " + unknown + "slack",
+			state:   registry.HealthOK,
+		},
+		{
+			name:    "icons only",
+			servers: servers,
+			options: config.SectionOptions{"show_names": false},
+			want:    "MCP " + red + amber + green + unknown,
+			state:   registry.HealthOK,
+		},
+		{
+			name:    "custom slow threshold",
+			servers: []*mcp.MCPServer{{Name: "github"}},
+			options: config.SectionOptions{"slow_ms": 100},
+			want:    "MCP " + amber + "github",
+			state:   registry.HealthOK,
+		},
+		{
+			name:  "no servers",
+			state: registry.HealthUnavailable,
+		},
+		{
+			name:  "detection failure",
+			err:   fmt.Errorf("MCP client is disabled"),
+			state: registry.HealthDegraded,
+		},
+		{
+			name:    "invalid config file",
+			servers: []*mcp.MCPServer{{Name: "github"}},
+			err:     fmt.Errorf("failed to parse .mcp.json"),
+			want:    "MCP " + green + "github",
+			state:   registry.HealthDegraded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"mcp": tt.options}
+			section, err := NewMCPSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m := section.(*MCPSection)
+			m.listServers = func(context.Context) ([]*mcp.MCPServer, error) {
+				return append([]*mcp.MCPServer(nil), tt.servers...), tt.err
+			}
+			m.probeResults = func() map[string]mcp.ProbeResult { return results }
+
+			if got := m.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+			if state := m.Health().State; state != tt.state {
+				t.Errorf("Health().State = %v, want %v", state, tt.state)
+			}
+		})
+	}
+}
+
+// TestHookLatencySectionRender tests per-call overhead and slow hook flagging
+func TestHookLatencySectionRender(t *testing.T) {
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	runs := func(lint time.Duration) []hook.HookRun {
+		return []hook.HookRun{
+			{Event: hook.EventPreToolUse, Command: "~/bin/lint.sh --fix", Start: start, Duration: lint},
+			{Event: hook.EventPreToolUse, Command: "claude-hud --hook", Start: start, Duration: 50 * time.Millisecond},
+			{Event: hook.EventSessionStart, Command: "setup.sh", Start: start, Duration: 5 * time.Second},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		runs    []hook.HookRun
+		err     error
+		options config.SectionOptions
+		want    string
+		state   registry.HealthState
+	}{
+		{
+			name:  "fast hooks",
+			runs:  runs(200 * time.Millisecond),
+			want:  "Hooks +200ms/call",
+			state: registry.HealthOK,
+		},
+		{
+			name:  "slow hook",
+			runs:  runs(1500 * time.Millisecond),
+			want:  theme.Yellow + "Hooks +1.5s/call" + theme.Reset + " " + theme.Yellow + "⚠lint.sh 1.5s" + theme.Reset,
+			state: registry.HealthOK,
+		},
+		{
+			name:    "slowest hidden",
+			runs:    runs(1500 * time.Millisecond),
+			options: config.SectionOptions{"show_slowest": false, "slow_ms": 2000},
+			want:    "Hooks +1.5s/call",
+			state:   registry.HealthOK,
+		},
+		{
+			name:  "no tool hooks",
+			runs:  runs(0)[2:],
+			state: registry.HealthOK,
+		},
+		{
+			name:  "no debug log",
+			err:   fs.ErrNotExist,
+			state: registry.HealthUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"hooklatency": tt.options}
+			section, err := NewHookLatencySection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			h := section.(*HookLatencySection)
+			h.readRuns = func() ([]hook.HookRun, error) { return tt.runs, tt.err }
+
+			if got := h.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+			if state := h.Health().State; state != tt.state {
+				t.Errorf("Health().State = %v, want %v", state, tt.state)
+			}
+		})
+	}
+}
+
+// TestFormatModelSplit tests the verbose per-model cost breakdown
+func TestFormatModelSplit(t *testing.T) {
+	tests := []struct {
+		name  string
+		usage []transcript.ModelUsage
+		want  string
+	}{
+		{
+			name:  "single model",
+			usage: []transcript.ModelUsage{{Model: "claude-sonnet-4-5", Cost: 1.5}},
+		},
+		{
+			name: "mixed models",
+			usage: []transcript.ModelUsage{
+				{Model: "claude-sonnet-4-5-20250929", Cost: 9},
+				{Model: "claude-haiku-4-5-20251001", Cost: 0.5},
+				{Model: "", Cost: 0.005},
+			},
+			want: "sonnet $9.00 · haiku $0.500 · unknown $0.0050",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatModelSplit(tt.usage); got != tt.want {
+				t.Errorf("formatModelSplit() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCostSectionSubscription tests that subscription sessions show quota instead of dollars
+func TestCostSectionSubscription(t *testing.T) {
+	resets := time.Now().Add(time.Hour)
+	usage := quota.Usage{
+		FiveHour: quota.Window{Start: resets.Add(-quota.SessionWindowLength), ResetsAt: resets, Percent: 42},
+		Weekly:   quota.Window{Percent: -1},
+	}
+
+	tests := []struct {
+		name    string
+		billing quota.Billing
+		options config.SectionOptions
+		found   bool
+		want    string
+	}{
+		{
+			name:    "quota left",
+			billing: quota.Billing{Mode: quota.BillingSubscription, Plan: "max"},
+			found:   true,
+			want:    "💳 5h 58% left",
+		},
+		{
+			name:    "plan without quota data",
+			billing: quota.Billing{Mode: quota.BillingSubscription, Plan: "max"},
+			want:    "💳 Max plan",
+		},
+		{
+			name:    "unknown plan",
+			billing: quota.Billing{Mode: quota.BillingSubscription},
+			want:    "💳 subscription",
+		},
+		{
+			name:    "forced in the options",
+			billing: quota.Billing{Mode: quota.BillingAPI},
+			options: config.SectionOptions{"mode": "subscription"},
+			found:   true,
+			want:    "💳 5h 58% left",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"cost": tt.options}
+			section, err := NewCostSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := section.(*CostSection)
+			c.billing = func() quota.Billing { return tt.billing }
+			c.usage = func() (quota.Usage, bool, error) { return usage, tt.found, nil }
+
+			if got := c.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Forcing dollars skips detection
+	cfg := config.DefaultConfig()
+	cfg.Sections = config.SectionsConfig{"cost": {"mode": "api"}}
+	section, _ := NewCostSection(cfg)
+	c := section.(*CostSection)
+	c.billing = func() quota.Billing { return quota.Billing{Mode: quota.BillingSubscription} }
+	if got := c.billingMode().Mode; got != quota.BillingAPI {
+		t.Errorf("billingMode() = %q, want %q", got, quota.BillingAPI)
+	}
+}
+
+// TestTurnsSectionRender tests turn counts, averages and the longest turn
+func TestTurnsSectionRender(t *testing.T) {
+	stats := transcript.TurnStats{
+		UserTurns:      12,
+		AssistantTurns: 11,
+		Tokens:         95_000,
+		LongestTurn:    4*time.Minute + 20*time.Second,
+	}
+
+	tests := []struct {
+		name    string
+		stats   transcript.TurnStats
+		err     error
+		options config.SectionOptions
+		want    string
+		state   registry.HealthState
+	}{
+		{
+			name:  "all details",
+			stats: stats,
+			want:  "Turns 12/11 avg 8k longest 4m",
+			state: registry.HealthOK,
+		},
+		{
+			name:    "counts only",
+			stats:   stats,
+			options: config.SectionOptions{"show_average": false, "show_longest": false},
+			want:    "Turns 12/11",
+			state:   registry.HealthOK,
+		},
+		{
+			name:  "short turn",
+			stats: transcript.TurnStats{UserTurns: 1, AssistantTurns: 1, Tokens: 900, LongestTurn: 45 * time.Second},
+			want:  "Turns 1/1 avg 900 longest 45s",
+			state: registry.HealthOK,
+		},
+		{
+			name:    "interrupted turns",
+			stats:   transcript.TurnStats{UserTurns: 3, AssistantTurns: 3, Interrupted: 2, AbortedTools: 3},
+			options: config.SectionOptions{"show_average": false},
+			want:    "Turns 3/3 " + theme.Yellow + "interrupted ×2" + theme.Reset,
+			state:   registry.HealthOK,
+		},
+		{
+			name:  "awaiting a response",
+			stats: transcript.TurnStats{UserTurns: 1},
+			want:  "Turns 1/0",
+			state: registry.HealthOK,
+		},
+		{
+			name:  "empty session",
+			state: registry.HealthOK,
+		},
+		{
+			name:  "unreadable transcript",
+			err:   fmt.Errorf("permission denied"),
+			state: registry.HealthDegraded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"turns": tt.options}
+			section, err := NewTurnsSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := section.(*TurnsSection)
+			s.turnStats = func() (transcript.TurnStats, error) { return tt.stats, tt.err }
+
+			if got := s.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+			if state := s.Health().State; state != tt.state {
+				t.Errorf("Health().State = %v, want %v", state, tt.state)
+			}
+		})
+	}
+}
+
+// TestFilesSectionRender tests the changed-file count and names
+func TestFilesSectionRender(t *testing.T) {
+	files := []transcript.TouchedFile{
+		{Path: "/src/login.go", Edits: 3},
+		{Path: "/src/auth.go", Edits: 1},
+		{Path: "/src/routes.go", Edits: 1},
+		{Path: "/src/db.go", Edits: 2},
+		{Path: "/README.md", Edits: 1},
+	}
+
+	tests := []struct {
+		name    string
+		files   []transcript.TouchedFile
+		err     error
+		options config.SectionOptions
+		want    string
+		state   registry.HealthState
+	}{
+		{
+			name:  "more files than shown",
+			files: files,
+			want:  "✎ 5 files: login.go×3 auth.go routes.go +2",
+			state: registry.HealthOK,
+		},
+		{
+			name:    "all files shown",
+			files:   files[:2],
+			options: config.SectionOptions{"max_files": 2},
+			want:    "✎ 2 files: login.go×3 auth.go",
+			state:   registry.HealthOK,
+		},
+		{
+			name:  "single file",
+			files: files[1:2],
+			want:  "✎ 1 file: auth.go",
+			state: registry.HealthOK,
+		},
+		{
+			name:    "count only",
+			files:   files,
+			options: config.SectionOptions{"max_files": 0},
+			want:    "✎ 5 files",
+			state:   registry.HealthOK,
+		},
+		{
+			name:  "nothing changed",
+			state: registry.HealthOK,
+		},
+		{
+			name:  "unreadable transcript",
+			err:   fmt.Errorf("permission denied"),
+			state: registry.HealthDegraded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"files": tt.options}
+			section, err := NewFilesSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := section.(*FilesSection)
+			s.touchedFiles = func() ([]transcript.TouchedFile, error) { return tt.files, tt.err }
+
+			if got := s.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+			if state := s.Health().State; state != tt.state {
+				t.Errorf("Health().State = %v, want %v", state, tt.state)
+			}
+		})
+	}
+}
+
+// TestDangerSectionRender tests the dangerous command warning and its notifications
+func TestDangerSectionRender(t *testing.T) {
+	commands := []transcript.DangerousCommand{
+		{ToolUseID: "b3", Rule: "force push", Command: "git push --force origin main"},
+		{ToolUseID: "b2", Rule: "rm -rf", Command: "rm -rf build/"},
+		{ToolUseID: "b1", Rule: "rm -rf", Command: "rm -rf\n  dist/"},
+	}
+
+	tests := []struct {
+		name     string
+		commands []transcript.DangerousCommand
+		err      error
+		options  config.SectionOptions
+		want     string
+		notified int
+		state    registry.HealthState
+	}{
+		{
+			name:     "several commands",
+			commands: commands,
+			want:     theme.Bold + theme.Red + "⚠ force push: git push --force origin main +2" + theme.Reset,
+			state:    registry.HealthOK,
+		},
+		{
+			name:     "one command on one line",
+			commands: commands[2:],
+			want:     theme.Bold + theme.Red + "⚠ rm -rf: rm -rf dist/" + theme.Reset,
+			state:    registry.HealthOK,
+		},
+		{
+			name:     "shortened command",
+			commands: commands[:1],
+			options:  config.SectionOptions{"command_length": 10},
+			want:     theme.Bold + theme.Red + "⚠ force push: git push …" + theme.Reset,
+			state:    registry.HealthOK,
+		},
+		{
+			name:     "notifications",
+			commands: commands,
+			options:  config.SectionOptions{"notify": true},
+			want:     theme.Bold + theme.Red + "⚠ force push: git push --force origin main +2" + theme.Reset,
+			notified: 3,
+			state:    registry.HealthOK,
+		},
+		{
+			name:  "nothing dangerous",
+			state: registry.HealthOK,
+		},
+		{
+			name:  "unreadable transcript",
+			err:   fmt.Errorf("permission denied"),
+			state: registry.HealthDegraded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"danger": tt.options}
+			section, err := NewDangerSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := section.(*DangerSection)
+			s.SetProviders(providers.New())
+			s.dangerous = func() ([]transcript.DangerousCommand, error) { return tt.commands, tt.err }
+			var notifications []string
+			s.notify = func(title, message string) { notifications = append(notifications, title+": "+message) }
+
+			// A second render sends nothing new
+			s.Render()
+			if got := s.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+			if len(notifications) != tt.notified {
+				t.Errorf("notifications = %q, want %d", notifications, tt.notified)
+			}
+			if state := s.Health().State; state != tt.state {
+				t.Errorf("Health().State = %v, want %v", state, tt.state)
+			}
+		})
+	}
+}
+
+// TestApprovalSectionRender tests the indicator for tool calls awaiting approval
+func TestApprovalSectionRender(t *testing.T) {
+	now := time.Date(2026, 1, 11, 3, 0, 0, 0, time.UTC)
+	bash := pendingApproval{Tool: "Bash", Target: "go test ./...", Since: now.Add(-45 * time.Second)}
+
+	tests := []struct {
+		name    string
+		pending pendingApproval
+		found   bool
+		err     error
+		options config.SectionOptions
+		animate bool
+		want    []string
+		state   registry.HealthState
+	}{
+		{
+			name:    "unanswered for long",
+			pending: bash,
+			found:   true,
+			want:    []string{theme.Bold + theme.Yellow + "⏳ approval needed: Bash go test ./... 45s" + theme.Reset},
+			state:   registry.HealthOK,
+		},
+		{
+			name:    "flashing",
+			pending: bash,
+			found:   true,
+			animate: true,
+			want: []string{
+				theme.Bold + theme.Yellow + "⏳ approval needed: Bash go test ./... 45s" + theme.Reset,
+				theme.Dim + "⏳ approval needed: Bash go test ./... 45s" + theme.Reset,
+			},
+			state: registry.HealthOK,
+		},
+		{
+			name:    "not waiting long enough",
+			pending: bash,
+			found:   true,
+			options: config.SectionOptions{"wait_ms": 60000},
+			state:   registry.HealthOK,
+		},
+		{
+			name:    "confirmed by a hook straight away",
+			pending: pendingApproval{Tool: "Bash", Since: now, Confirmed: true},
+			found:   true,
+			options: config.SectionOptions{"wait_ms": 60000},
+			want:    []string{theme.Bold + theme.Yellow + "⏳ approval needed: Bash" + theme.Reset},
+			state:   registry.HealthOK,
+		},
+		{
+			name:    "ignored tool",
+			pending: pendingApproval{Tool: "Task", Since: now.Add(-5 * time.Minute)},
+			found:   true,
+			state:   registry.HealthOK,
+		},
+		{
+			name:  "nothing pending",
+			state: registry.HealthOK,
+		},
+		{
+			name:  "unreadable transcript",
+			err:   fmt.Errorf("permission denied"),
+			state: registry.HealthDegraded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Animate = tt.animate
+			cfg.Sections = config.SectionsConfig{"approval": tt.options}
+			section, err := NewApprovalSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := section.(*ApprovalSection)
+			s.pending = func() (pendingApproval, bool, error) { return tt.pending, tt.found, tt.err }
+			s.now = func() time.Time { return now }
+
+			if got := s.RenderFrames(); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("RenderFrames() = %q, want %q", got, tt.want)
+			}
+			if state := s.Health().State; state != tt.state {
+				t.Errorf("Health().State = %v, want %v", state, tt.state)
+			}
+		})
+	}
+}
+
+// TestSessionWindowSectionRender tests the reset countdown and its bar
+func TestSessionWindowSectionRender(t *testing.T) {
+	window := func(percent int, source string) quota.Window {
+		resets := time.Now().Add(72*time.Minute + 10*time.Second)
+		return quota.Window{Start: resets.Add(-quota.SessionWindowLength), ResetsAt: resets, Percent: percent, Source: source}
+	}
+
+	tests := []struct {
+		name    string
+		window  quota.Window
+		found   bool
+		err     error
+		options config.SectionOptions
+		want    string
+		state   registry.HealthState
+	}{
+		{
+			name:   "estimated from transcripts",
+			window: window(-1, quota.SourceTranscripts),
+			found:  true,
+			want:   "5h ██████░░ resets ~1h12m",
+			state:  registry.HealthOK,
+		},
+		{
+			name:   "reported usage",
+			window: window(75, quota.SourceZai),
+			found:  true,
+			want:   "5h ██████░░ " + theme.Yellow + "75%" + theme.Reset + " resets 1h12m",
+			state:  registry.HealthOK,
+		},
+		{
+			name:    "without bar",
+			window:  window(10, quota.SourceZai),
+			found:   true,
+			options: config.SectionOptions{"show_bar": false},
+			want:    "5h 10% resets 1h12m",
+			state:   registry.HealthOK,
+		},
+		{
+			name:  "no active window",
+			state: registry.HealthOK,
+		},
+		{
+			name:  "no transcripts",
+			err:   fs.ErrNotExist,
+			state: registry.HealthUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"sessionwindow": tt.options}
+			section, err := NewSessionWindowSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := section.(*SessionWindowSection)
+			s.window = func() (quota.Window, bool, error) { return tt.window, tt.found, tt.err }
+
+			if got := s.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+			if state := s.Health().State; state != tt.state {
+				t.Errorf("Health().State = %v, want %v", state, tt.state)
+			}
+		})
+	}
+}
+
+func TestQuotaSectionRender(t *testing.T) {
+	window := func(percent int, resetsIn, length time.Duration) quota.Window {
+		resets := time.Now().Add(resetsIn + 10*time.Second)
+		return quota.Window{Start: resets.Add(-length), ResetsAt: resets, Percent: percent, Source: quota.SourceOAuth}
+	}
+	usage := quota.Usage{
+		FiveHour: window(42, 72*time.Minute, quota.SessionWindowLength),
+		Weekly:   window(93, 76*time.Hour, quota.WeeklyWindowLength),
+	}
+
+	tests := []struct {
+		name    string
+		usage   quota.Usage
+		found   bool
+		err     error
+		options config.SectionOptions
+		want    string
+		state   registry.HealthState
+	}{
+		{
+			name:  "both windows",
+			usage: usage,
+			found: true,
+			want:  "5h 58% · 7d " + theme.Red + "7%" + theme.Reset + " left",
+			state: registry.HealthOK,
+		},
+		{
+			name:    "without weekly",
+			usage:   usage,
+			found:   true,
+			options: config.SectionOptions{"show_weekly": false},
+			want:    "5h 58% left",
+			state:   registry.HealthOK,
+		},
+		{
+			name:    "with resets",
+			usage:   usage,
+			found:   true,
+			options: config.SectionOptions{"show_resets": true},
+			want: "5h 58%" + theme.Dim + " (1h12m)" + theme.Reset +
+				" · 7d " + theme.Red + "7%" + theme.Reset + theme.Dim + " (3d4h)" + theme.Reset + " left",
+			state: registry.HealthOK,
+		},
+		{
+			name:  "window reset since the request",
+			usage: quota.Usage{FiveHour: window(80, -time.Minute, quota.SessionWindowLength), Weekly: quota.Window{Percent: -1}},
+			found: true,
+			want:  "5h 100% left",
+			state: registry.HealthOK,
+		},
+		{
+			name:  "stale usage while the endpoint fails",
+			usage: usage,
+			found: true,
+			err:   errors.New("usage endpoint returned 500 Internal Server Error"),
+			want:  "5h 58% · 7d " + theme.Red + "7%" + theme.Reset + " left",
+			state: registry.HealthDegraded,
+		},
+		{
+			name:  "endpoint failing",
+			err:   errors.New("usage endpoint returned 500 Internal Server Error"),
+			state: registry.HealthDegraded,
+		},
+		{
+			name:  "usage_api disabled",
+			err:   errUsageAPIDisabled,
+			state: registry.HealthUnavailable,
+		},
+		{
+			name:  "not logged in",
+			err:   quota.ErrNoCredentials,
+			state: registry.HealthUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"quota": tt.options}
+			section, err := NewQuotaSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			q := section.(*QuotaSection)
+			q.usage = func() (quota.Usage, bool, error) { return tt.usage, tt.found, tt.err }
+
+			if got := q.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+			if state := q.Health().State; state != tt.state {
+				t.Errorf("Health().State = %v, want %v", state, tt.state)
+			}
+		})
+	}
+}
+
+// TestTimerSectionRender tests the focus timer countdown
+func TestTimerSectionRender(t *testing.T) {
+	startedAgo := func(d, ago time.Duration, label string) *session.Timer {
+		return session.NewTimer(d, label, time.Now().Add(-ago))
+	}
+
+	tests := []struct {
+		name    string
+		timer   *session.Timer
+		options config.SectionOptions
+		want    string
+	}{
+		{
+			name:  "fresh timer",
+			timer: startedAgo(25*time.Minute, 0, ""),
+			want:  "⏱ 25m",
+		},
+		{
+			name:  "with label",
+			timer: startedAgo(25*time.Minute, 7*time.Minute-time.Second, "review"),
+			want:  "⏱ review 19m",
+		},
+		{
+			name:    "label hidden",
+			timer:   startedAgo(90*time.Minute, 0, "review"),
+			options: config.SectionOptions{"show_label": false},
+			want:    "⏱ 1h30m",
+		},
+		{
+			name:  "last tenth",
+			timer: startedAgo(25*time.Minute, 24*time.Minute+30*time.Second, ""),
+			want:  "⏱ " + theme.Yellow + "30s" + theme.Reset,
+		},
+		{
+			name:  "time's up",
+			timer: startedAgo(25*time.Minute, 26*time.Minute, "review"),
+			want:  "⏱ review " + theme.Red + "time's up" + theme.Reset,
+		},
+		{
+			name:  "long expired",
+			timer: startedAgo(25*time.Minute, time.Hour, ""),
+		},
+		{
+			name: "no timer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"timer": tt.options}
+			section, err := NewTimerSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := section.(*TimerSection)
+			s.readTimer = func() (*session.Timer, error) { return tt.timer, nil }
+
+			if got := s.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+			if state := s.Health().State; state != registry.HealthOK {
+				t.Errorf("Health().State = %v, want ok", state)
+			}
+		})
+	}
+}
+
+func TestSessionsSectionRender(t *testing.T) {
+	now := time.Date(2026, 1, 11, 3, 0, 0, 0, time.UTC)
+	sess := func(ago time.Duration, stopped, approval bool) *session.SessionState {
+		s := &session.SessionState{LastEventAt: now.Add(-ago), Stopped: stopped}
+		if approval {
+			s.Approval = &session.ApprovalRequest{At: now.Add(-ago)}
+		}
+		return s
+	}
+
+	tests := []struct {
+		name     string
+		sessions map[string]*session.SessionState
+		options  config.SectionOptions
+		want     string
+	}{
+		{
+			name: "several active",
+			sessions: map[string]*session.SessionState{
+				"a": sess(time.Minute, false, false),
+				"b": sess(2*time.Minute, false, true),
+				"c": sess(5*time.Minute, true, false),
+				"d": sess(2*time.Hour, false, false), // Idle too long to count
+			},
+			want: "⧉ 3 sessions · 2 working · " + theme.Bold + theme.Yellow + "1 approval" + theme.Reset,
+		},
+		{
+			name: "all idle",
+			sessions: map[string]*session.SessionState{
+				"a": sess(time.Minute, true, false),
+				"b": sess(time.Minute, true, false),
+			},
+			want: "⧉ 2 sessions",
+		},
+		{
+			name:     "only this session",
+			sessions: map[string]*session.SessionState{"a": sess(time.Minute, false, false)},
+		},
+		{
+			name:     "only this session, shown",
+			sessions: map[string]*session.SessionState{"a": sess(time.Minute, false, false)},
+			options:  config.SectionOptions{"min_sessions": 1},
+			want:     "⧉ 1 session · 1 working",
+		},
+		{
+			name: "wider window",
+			sessions: map[string]*session.SessionState{
+				"a": sess(time.Minute, false, false),
+				"d": sess(2*time.Hour, true, false),
+			},
+			options: config.SectionOptions{"active_ms": 3 * 60 * 60 * 1000},
+			want:    "⧉ 2 sessions · 1 working",
+		},
+		{
+			name: "no sessions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"sessions": tt.options}
+			section, err := NewSessionsSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := section.(*SessionsSection)
+			s.readSessions = func() (map[string]*session.SessionState, error) { return tt.sessions, nil }
+			s.now = func() time.Time { return now }
+
+			if got := s.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+			if got, want := registry.RenderStyled(s), styled.Parse(tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("RenderStyled() = %+v, want %+v", got, want)
+			}
+			if state := s.Health().State; state != registry.HealthOK {
+				t.Errorf("Health().State = %v, want ok", state)
+			}
+		})
+	}
+}
+
+func TestFleetSectionRender(t *testing.T) {
+	member := func(branch string, current, dirty bool, sess *fleet.Session) fleet.Member {
+		return fleet.Member{Worktree: git.Worktree{Path: "/src/" + branch, Branch: branch}, Current: current, Dirty: dirty, Session: sess}
+	}
+	working := &fleet.Session{State: fleet.Working, ContextPercent: 72, ContextKnown: true}
+	approval := &fleet.Session{State: fleet.Approval, ContextPercent: 41, ContextKnown: true}
+	idle := &fleet.Session{State: fleet.Idle}
+	inactive := &fleet.Session{State: fleet.Inactive, ContextPercent: 90, ContextKnown: true}
+
+	tests := []struct {
+		name    string
+		members []fleet.Member
+		err     error
+		options config.SectionOptions
+		want    string
+		state   registry.HealthState
+	}{
+		{
+			name: "agents in worktrees",
+			members: []fleet.Member{
+				member("main", true, false, idle),
+				member("auth", false, true, working),
+				member("spike", false, true, approval),
+				member("docs", false, false, inactive),
+			},
+			want: "⑂ " + theme.Bold + "main" + theme.Reset + " ○ · auth± " + theme.Green + "●" + theme.Reset + " 72% · spike± " +
+				theme.Bold + theme.Yellow + "⏸" + theme.Reset + " 41% · docs",
+			state: registry.HealthOK,
+		},
+		{
+			name: "current worktree past the limit",
+			members: []fleet.Member{
+				member("main", false, false, nil),
+				member("auth", false, false, nil),
+				member("spike", true, false, nil),
+			},
+			options: config.SectionOptions{"max_worktrees": 2},
+			want:    "⑂ main · " + theme.Bold + "spike" + theme.Reset + " · +1",
+			state:   registry.HealthOK,
+		},
+		{
+			name:    "single worktree",
+			members: []fleet.Member{member("main", true, true, working)},
+			state:   registry.HealthOK,
+		},
+		{
+			name:  "not a repository",
+			err:   errors.New("exit status 128"),
+			state: registry.HealthUnavailable,
+		},
+		{
+			name:  "git timed out",
+			err:   context.DeadlineExceeded,
+			state: registry.HealthDegraded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"fleet": tt.options}
+			section, err := NewFleetSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f := section.(*FleetSection)
+			f.readFleet = func(active time.Duration) ([]fleet.Member, error) {
+				if active != fleet.DefaultActive {
+					t.Errorf("active = %v, want the default", active)
+				}
+				return tt.members, tt.err
+			}
+
+			if got := f.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+			if state := f.Health().State; state != tt.state {
+				t.Errorf("Health().State = %v, want %v", state, tt.state)
+			}
+		})
+	}
+}
+
+func TestEnvironmentSectionRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     system.Environment
+		options config.SectionOptions
+		want    string
+		state   registry.HealthState
+	}{
+		{name: "docker", env: system.EnvDocker, want: "🐳 docker", state: registry.HealthOK},
+		{name: "wsl", env: system.EnvWSL, want: "🐧 wsl", state: registry.HealthOK},
+		{name: "icon only", env: system.EnvKubernetes, options: config.SectionOptions{"icon_only": true}, want: "☸", state: registry.HealthOK},
+		{name: "host", env: system.EnvHost, state: registry.HealthUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"environment": tt.options}
+			section, err := NewEnvironmentSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := section.(*EnvironmentSection)
+			s.detect = func() system.Environment { return tt.env }
+
+			if got := s.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+			if state := s.Health().State; state != tt.state {
+				t.Errorf("Health().State = %v, want %v", state, tt.state)
+			}
+		})
+	}
+}
+
+// TestClockSectionRender tests clock formats and the second timezone
+func TestClockSectionRender(t *testing.T) {
+	if _, err := time.LoadLocation("America/New_York"); err != nil {
+		t.Skipf("timezone database unavailable: %v", err)
+	}
+	now := time.Date(2026, 3, 2, 14, 5, 9, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		options config.SectionOptions
+		want    string
+		state   registry.HealthState
+	}{
+		{
+			name:  "default format",
+			want:  "14:05",
+			state: registry.HealthOK,
+		},
+		{
+			name:    "custom format",
+			options: config.SectionOptions{"format": "%a %d %b %I:%M:%S %p %%"},
+			want:    "Mon 02 Mar 02:05:09 PM %",
+			state:   registry.HealthOK,
+		},
+		{
+			name:    "second timezone",
+			options: config.SectionOptions{"timezone": "America/New_York"},
+			want:    "14:05 · EST 09:05",
+			state:   registry.HealthOK,
+		},
+		{
+			name:    "labelled timezone",
+			options: config.SectionOptions{"timezone": "Asia/Tokyo", "timezone_label": "TYO"},
+			want:    "14:05 · TYO 23:05",
+			state:   registry.HealthOK,
+		},
+		{
+			name:    "unknown timezone",
+			options: config.SectionOptions{"timezone": "Mars/Olympus_Mons"},
+			want:    "14:05",
+			state:   registry.HealthDegraded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"clock": tt.options}
+			section, err := NewClockSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := section.(*ClockSection)
+			c.now = func() time.Time { return now }
+
+			if got := c.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+			if state := c.Health().State; state != tt.state {
+				t.Errorf("Health().State = %v, want %v", state, tt.state)
+			}
+		})
+	}
+}
+
+// TestCustomSectionRender tests static templates and command output
+func TestCustomSectionRender(t *testing.T) {
+	t.Setenv("CLAUDE_HUD_TEST_TEAM", "platform")
+
+	tests := []struct {
+		name    string
+		options config.SectionOptions
+		output  string
+		err     error
+		want    string
+		state   registry.HealthState
+	}{
+		{
+			name:    "static template",
+			options: config.SectionOptions{"template": "team {env:CLAUDE_HUD_TEST_TEAM} {unknown}"},
+			want:    "team platform {unknown}",
+			state:   registry.HealthOK,
+		},
+		{
+			name:    "command output",
+			options: config.SectionOptions{"command": "cat .deploy-target"},
+			output:  "staging",
+			want:    "staging",
+			state:   registry.HealthOK,
+		},
+		{
+			name:    "command in a template",
+			options: config.SectionOptions{"command": "cat .deploy-target", "template": "→ {output}"},
+			output:  "staging",
+			want:    "→ staging",
+			state:   registry.HealthOK,
+		},
+		{
+			name:    "empty command output",
+			options: config.SectionOptions{"command": "true", "template": "→ {output}"},
+			state:   registry.HealthOK,
+		},
+		{
+			name:    "failing command keeps the last output",
+			options: config.SectionOptions{"command": "cat .deploy-target"},
+			output:  "staging",
+			err:     errors.New("exit status 1"),
+			want:    "staging",
+			state:   registry.HealthDegraded,
+		},
+		{
+			name:  "not configured",
+			state: registry.HealthUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"custom": tt.options}
+			section, err := NewCustomSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := section.(*CustomSection)
+			c.runCommand = func(command, dir string, env []string, ttl time.Duration) (string, error) {
+				if ttl != 30*time.Second {
+					t.Errorf("ttl = %v, want the 30s default", ttl)
+				}
+				return tt.output, tt.err
+			}
+
+			if got := c.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+			if state := c.Health().State; state != tt.state {
+				t.Errorf("Health().State = %v, want %v", state, tt.state)
+			}
+		})
+	}
+}
+
+// TestCustomSectionInstances tests that "custom:<name>" sections read their own options
+func TestCustomSectionInstances(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Layout.Lines = []config.LineConfig{{Sections: []string{"custom:team", "custom:env"}}}
+	cfg.Sections = config.SectionsConfig{
+		"custom:team": {"template": "platform", "ttl_ms": 5000},
+		"custom:env":  {"template": "staging"},
+	}
+
+	for name, want := range map[string]string{"custom:team": "platform", "custom:env": "staging"} {
+		section, err := registry.Create(name, cfg)
+		if err != nil {
+			t.Fatalf("Create(%q) error = %v", name, err)
+		}
+		if section.Name() != name || !section.Enabled() {
+			t.Errorf("Create(%q) = %q (enabled %v)", name, section.Name(), section.Enabled())
+		}
+		if got := section.Render(); got != want {
+			t.Errorf("%s Render() = %q, want %q", name, got, want)
+		}
+	}
+
+	if section, _ := registry.Create("custom:team", cfg); section.(*CustomSection).CacheTTL() != 5*time.Second {
+		t.Errorf("CacheTTL() = %v, want ttl_ms", section.(*CustomSection).CacheTTL())
+	}
+	if _, err := registry.Create("clock:utc", cfg); err == nil {
+		t.Error("expected an error for instances of a section type without them")
+	}
+}
+
+// TestWeatherSectionRender tests weather output, units and provider selection
+func TestWeatherSectionRender(t *testing.T) {
+	t.Setenv("OPENWEATHER_API_KEY", "")
+	drizzle := weather.Report{Location: "London", TempC: 11.6, Condition: weather.ConditionRain, Description: "light drizzle"}
+
+	tests := []struct {
+		name         string
+		options      config.SectionOptions
+		report       weather.Report
+		found        bool
+		err          error
+		want         string
+		state        registry.HealthState
+		wantProvider string
+	}{
+		{
+			name:         "metric",
+			report:       drizzle,
+			found:        true,
+			want:         "🌧 12°C",
+			state:        registry.HealthOK,
+			wantProvider: "wttr",
+		},
+		{
+			name:         "imperial with condition",
+			options:      config.SectionOptions{"units": "imperial", "show_condition": true},
+			report:       drizzle,
+			found:        true,
+			want:         "🌧 53°F light drizzle",
+			state:        registry.HealthOK,
+			wantProvider: "wttr",
+		},
+		{
+			name:         "openweather",
+			options:      config.SectionOptions{"provider": "openweather", "api_key": "key", "location": "London,GB"},
+			report:       weather.Report{TempC: -0.4, Condition: weather.ConditionSnow},
+			found:        true,
+			want:         "❄ 0°C",
+			state:        registry.HealthOK,
+			wantProvider: "openweather",
+		},
+		{
+			name:    "openweather without a key",
+			options: config.SectionOptions{"provider": "openweather"},
+			state:   registry.HealthUnavailable,
+		},
+		{
+			name:         "stale report while the service fails",
+			report:       drizzle,
+			found:        true,
+			err:          errors.New("request failed: timeout"),
+			want:         "🌧 12°C",
+			state:        registry.HealthDegraded,
+			wantProvider: "wttr",
+		},
+		{
+			name:         "service failing",
+			err:          errors.New("request failed: timeout"),
+			state:        registry.HealthDegraded,
+			wantProvider: "wttr",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"weather": tt.options}
+			section, err := NewWeatherSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w := section.(*WeatherSection)
+			var gotProvider string
+			w.report = func(provider weather.Provider, location string, interval, timeout time.Duration) (weather.Report, bool, error) {
+				gotProvider = provider.Name()
+				if interval < 10*time.Minute || timeout > 2*time.Second {
+					t.Errorf("interval %v, timeout %v out of bounds", interval, timeout)
+				}
+				return tt.report, tt.found, tt.err
+			}
+
+			if got := w.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+			if state := w.Health().State; state != tt.state {
+				t.Errorf("Health().State = %v, want %v", state, tt.state)
+			}
+			if gotProvider != tt.wantProvider {
+				t.Errorf("provider = %q, want %q", gotProvider, tt.wantProvider)
+			}
+		})
+	}
+}
+
+// TestNowPlayingSectionRender tests the current track and paused players
+func TestNowPlayingSectionRender(t *testing.T) {
+	teardrop := system.Track{Player: "spotify", Title: "Teardrop", Artist: "Massive Attack", Playing: true}
+	paused := teardrop
+	paused.Playing = false
+
+	tests := []struct {
+		name    string
+		options config.SectionOptions
+		track   system.Track
+		ok      bool
+		err     error
+		want    string
+		state   registry.HealthState
+	}{
+		{
+			name:  "playing",
+			track: teardrop,
+			ok:    true,
+			want:  "♪ Massive Attack – Teardrop",
+			state: registry.HealthOK,
+		},
+		{
+			name:    "without artist",
+			options: config.SectionOptions{"show_artist": false},
+			track:   teardrop,
+			ok:      true,
+			want:    "♪ Teardrop",
+			state:   registry.HealthOK,
+		},
+		{
+			name:  "paused hidden",
+			track: paused,
+			ok:    true,
+			state: registry.HealthOK,
+		},
+		{
+			name:    "paused shown",
+			options: config.SectionOptions{"show_paused": true},
+			track:   paused,
+			ok:      true,
+			want:    theme.Dim + "⏸ Massive Attack – Teardrop" + theme.Reset,
+			state:   registry.HealthOK,
+		},
+		{
+			name:  "nothing loaded",
+			state: registry.HealthOK,
+		},
+		{
+			name:  "no session bus",
+			err:   errors.New("busctl: exit status 1"),
+			state: registry.HealthUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"nowplaying": tt.options}
+			section, err := NewNowPlayingSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			n := section.(*NowPlayingSection)
+			n.nowPlaying = func(string) (system.Track, bool, error) { return tt.track, tt.ok, tt.err }
+
+			if got := n.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+			if state := n.Health().State; state != tt.state {
+				t.Errorf("Health().State = %v, want %v", state, tt.state)
+			}
+		})
+	}
+}
+
+// TestFormatTrack tests shortening artist and title to fit
+func TestFormatTrack(t *testing.T) {
+	tests := []struct {
+		artist, title string
+		maxLen        int
+		want          string
+	}{
+		{"Massive Attack", "Teardrop", 40, "Massive Attack – Teardrop"},
+		{"The Beatles", "Here Comes the Sun (Remastered 2009)", 32, "The Beatles – Here Comes the Sun"},
+		{"Queen", "Bohemian Rhapsody - Live at Wembley '86", 30, "Queen – Bohemian Rhapsody"},
+		{"Godspeed You! Black Emperor", "Storm", 20, "Godspeed Yo… – Storm"},
+		{"Godspeed You! Black Emperor", "Moya", 10, "Moya"},
+		{"", "Sleeping Satellite And Other Very Long Titles", 20, "Sleeping Satellite …"},
+	}
+	for _, tt := range tests {
+		if got := formatTrack(tt.artist, tt.title, tt.maxLen); got != tt.want {
+			t.Errorf("formatTrack(%q, %q, %d) = %q, want %q", tt.artist, tt.title, tt.maxLen, got, tt.want)
+		}
+	}
+}
+
+// TestTestsSectionRender tests the test results section output and health
+func TestTestsSectionRender(t *testing.T) {
+	results := testresults.Summary{Path: "test-results.json", ModTime: time.Now(), Passed: 128, Failed: 2, Skipped: 3}
+	green := results
+	green.Failed = 0
+	old := green
+	old.ModTime = time.Now().Add(-2 * time.Hour)
+
+	tests := []struct {
+		name    string
+		options config.SectionOptions
+		summary testresults.Summary
+		found   bool
+		err     error
+		want    string
+		state   registry.HealthState
+	}{
+		{
+			name:    "failures",
+			summary: results,
+			found:   true,
+			want:    "tests 128✓ " + theme.Red + "2✗" + theme.Reset,
+			state:   registry.HealthOK,
+		},
+		{
+			name:    "all passing with skipped",
+			options: config.SectionOptions{"show_skipped": true},
+			summary: green,
+			found:   true,
+			want:    "tests 128✓ " + theme.Dim + "3⊘" + theme.Reset,
+			state:   registry.HealthOK,
+		},
+		{
+			name:    "too old",
+			options: config.SectionOptions{"max_age_ms": 3600000},
+			summary: old,
+			found:   true,
+			state:   registry.HealthOK,
+		},
+		{
+			name:  "no results file",
+			state: registry.HealthOK,
+		},
+		{
+			name:    "half-written file keeps the last results",
+			summary: green,
+			found:   true,
+			err:     errors.New("invalid JUnit report: unexpected EOF"),
+			want:    "tests 128✓",
+			state:   registry.HealthDegraded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"tests": tt.options}
+			section, err := NewTestsSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := section.(*TestsSection)
+			var patterns []string
+			s.latest = func(dir string, globs []string) (testresults.Summary, bool, error) {
+				patterns = globs
+				return tt.summary, tt.found, tt.err
+			}
+
+			if got := s.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+			if state := s.Health().State; state != tt.state {
+				t.Errorf("Health().State = %v, want %v", state, tt.state)
+			}
+			if len(patterns) != len(defaultTestResultGlobs) {
+				t.Errorf("patterns = %v, want the defaults", patterns)
+			}
+		})
+	}
+}
+
+// TestDiagnosticsSectionRender tests the diagnostics section output and health
+func TestDiagnosticsSectionRender(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	mixed := write("mixed.json", `[{"severity":1},{"severity":1},{"severity":2},{"severity":"hint"}]`)
+	warnings := write("warnings.json", `{"errors":0,"warnings":3}`)
+	outside := write("outside.json", `[{"severity":1,"file":"/somewhere/else/main.go"}]`)
+	broken := write("broken.json", `{"diagnostics":`)
+
+	tests := []struct {
+		name    string
+		options config.SectionOptions
+		want    string
+		state   registry.HealthState
+	}{
+		{
+			name:    "errors and warnings",
+			options: config.SectionOptions{"path": mixed},
+			want:    theme.Red + "✗ 2" + theme.Reset + " " + theme.Yellow + "⚠ 1" + theme.Reset,
+			state:   registry.HealthOK,
+		},
+		{
+			name:    "errors only",
+			options: config.SectionOptions{"path": mixed, "show_warnings": false},
+			want:    theme.Red + "✗ 2" + theme.Reset,
+			state:   registry.HealthOK,
+		},
+		{
+			name:    "warnings only",
+			options: config.SectionOptions{"path": warnings},
+			want:    theme.Yellow + "⚠ 3" + theme.Reset,
+			state:   registry.HealthOK,
+		},
+		{
+			name:    "outside the workspace",
+			options: config.SectionOptions{"path": outside},
+			state:   registry.HealthOK,
+		},
+		{
+			name:    "outside the workspace counted",
+			options: config.SectionOptions{"path": outside, "workspace_only": false},
+			want:    theme.Red + "✗ 1" + theme.Reset,
+			state:   registry.HealthOK,
+		},
+		{
+			name:    "no file",
+			options: config.SectionOptions{"path": filepath.Join(dir, "missing.json")},
+			state:   registry.HealthUnavailable,
+		},
+		{
+			name:    "invalid file",
+			options: config.SectionOptions{"path": broken},
+			state:   registry.HealthDegraded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"diagnostics": tt.options}
+			section, err := NewDiagnosticsSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := section.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+			if state := section.(*DiagnosticsSection).Health().State; state != tt.state {
+				t.Errorf("Health().State = %v, want %v", state, tt.state)
+			}
+		})
+	}
+}
+
+// TestCISectionRender tests the CI section output and health
+func TestCISectionRender(t *testing.T) {
+	commit := ci.Commit{Owner: "o", Repo: "r", SHA: "abc"}
+
+	tests := []struct {
+		name       string
+		options    config.SectionOptions
+		hyperlinks bool
+		lookupErr  error
+		result     ci.Result
+		found      bool
+		err        error
+		want       string
+		state      registry.HealthState
+	}{
+		{
+			name:   "success",
+			result: ci.Result{State: ci.StateSuccess, Total: 2},
+			found:  true,
+			want:   "CI " + theme.Green + "✓" + theme.Reset,
+			state:  registry.HealthOK,
+		},
+		{
+			name:   "failure",
+			result: ci.Result{State: ci.StateFailure, Total: 2, Failed: 1},
+			found:  true,
+			want:   "CI " + theme.Red + "✗" + theme.Reset,
+			state:  registry.HealthOK,
+		},
+		{
+			name:    "pending via commit statuses",
+			options: config.SectionOptions{"provider": "status"},
+			result:  ci.Result{State: ci.StatePending, Total: 1, Pending: 1},
+			found:   true,
+			want:    "CI " + theme.Yellow + "●" + theme.Reset,
+			state:   registry.HealthOK,
+		},
+		{
+			name:       "linked to the commit",
+			hyperlinks: true,
+			result:     ci.Result{State: ci.StateSuccess, Total: 2},
+			found:      true,
+			want:       theme.Hyperlink("https://github.com/o/r/commit/abc", "CI "+theme.Green+"✓"+theme.Reset),
+			state:      registry.HealthOK,
+		},
+		{
+			name:   "not pushed",
+			found:  true,
+			result: ci.Result{State: ci.StateUnknown},
+			state:  registry.HealthOK,
+		},
+		{
+			name:   "rate limited keeps the last result",
+			result: ci.Result{State: ci.StateSuccess, Total: 1},
+			found:  true,
+			err:    errors.New("rate limited until 14:05"),
+			want:   "CI " + theme.Green + "✓" + theme.Reset,
+			state:  registry.HealthDegraded,
+		},
+		{
+			name:      "no forge remote",
+			lookupErr: errors.New("origin is not hosted on a forge"),
+			state:     registry.HealthUnavailable,
+		},
+		{
+			name:    "unknown provider",
+			options: config.SectionOptions{"provider": "jenkins"},
+			state:   registry.HealthDegraded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"ci": tt.options}
+			cfg.Hyperlinks = tt.hyperlinks
+			section, err := NewCISection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := section.(*CISection)
+			c.commit = func(remote string) (string, ci.Commit, error) {
+				return "github.com", commit, tt.lookupErr
+			}
+			var interval time.Duration
+			c.status = func(provider ci.Provider, got ci.Commit, every, timeout time.Duration) (ci.Result, bool, error) {
+				interval = every
+				if got != commit {
+					t.Errorf("status asked for %+v, want %+v", got, commit)
+				}
+				return tt.result, tt.found, tt.err
+			}
+
+			if got := c.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+			if state := c.Health().State; state != tt.state {
+				t.Errorf("Health().State = %v, want %v", state, tt.state)
+			}
+			if interval != 0 && interval != ci.DefaultInterval {
+				t.Errorf("interval = %v, want %v", interval, ci.DefaultInterval)
+			}
+		})
+	}
+}
+
+// TestTasksSectionRender tests the tasks section output and health
+func TestTasksSectionRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		options config.SectionOptions
+		list    tasks.List
+		found   bool
+		err     error
+		want    string
+		state   registry.HealthState
+	}{
+		{
+			name:  "next item",
+			list:  tasks.List{Done: 3, Total: 8, Next: "Write the migration guide for v2"},
+			found: true,
+			want:  "tasks 3/8 • Write the migration guide for…",
+			state: registry.HealthOK,
+		},
+		{
+			name:    "next item hidden",
+			options: config.SectionOptions{"show_next": false},
+			list:    tasks.List{Done: 3, Total: 8, Next: "Write the migration guide"},
+			found:   true,
+			want:    "tasks 3/8",
+			state:   registry.HealthOK,
+		},
+		{
+			name:  "all done",
+			list:  tasks.List{Done: 8, Total: 8},
+			found: true,
+			want:  "tasks 8/8 " + theme.Green + "✓" + theme.Reset,
+			state: registry.HealthOK,
+		},
+		{
+			name:  "no checklist items",
+			found: true,
+			state: registry.HealthOK,
+		},
+		{
+			name:  "no task files",
+			state: registry.HealthUnavailable,
+		},
+		{
+			name:  "unreadable file keeps the last checklist",
+			list:  tasks.List{Done: 1, Total: 2, Next: "Ship"},
+			found: true,
+			err:   errors.New("permission denied"),
+			want:  "tasks 1/2 • Ship",
+			state: registry.HealthDegraded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"tasks": tt.options}
+			section, err := NewTasksSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := section.(*TasksSection)
+			var files []string
+			s.read = func(dir string, paths []string) (tasks.List, bool, error) {
+				files = paths
+				return tt.list, tt.found, tt.err
+			}
+
+			if got := s.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+			if state := s.Health().State; state != tt.state {
+				t.Errorf("Health().State = %v, want %v", state, tt.state)
+			}
+			if len(files) != len(defaultTaskFiles) {
+				t.Errorf("paths = %v, want the defaults", files)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package sections
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/system"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+)
+
+// ProcessSection displays CPU and memory used by the Claude Code process and its tools
+type ProcessSection struct {
+	*BaseSection
+	findProcess func(name string) (int, error)
+}
+
+// NewProcessSection creates a new process section (factory function for registry)
+func NewProcessSection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("process", appConfig)
+	base.SetPriority(registry.PriorityOptional) // Nice to have; hide first on narrow terminals
+	base.SetMinWidth(18)                        // Minimum width for "claude 3.2% 890MB"
+	base.SetCacheTTL(5 * time.Second)           // Matches the system monitor's own cache
+
+	return &ProcessSection{
+		BaseSection: base,
+		findProcess: system.FindProcess,
+	}, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("process", NewProcessSection, registry.Metadata{
+		Description: "CPU and memory of the Claude Code process and its child tools",
+		Priority:    registry.PriorityOptional,
+		Options: []registry.Option{
+			{Name: "process_name", Type: "string", Default: "claude", Description: "Ancestor process to track (or set CLAUDE_HUD_PID)"},
+			{Name: "warning_mb", Type: "int", Default: "2048", Description: "Show memory in yellow at or above this many MB"},
+		},
+	})
+}
+
+// Render returns the process section output
+func (p *ProcessSection) Render() string {
+	opts := p.GetConfig().SectionOptions(p.Name())
+	name := opts.String("process_name", "claude")
+
+	pid, err := p.findProcess(name)
+	if err != nil {
+		p.MarkUnavailable(err.Error())
+		return ""
+	}
+	usage, err := p.Providers().System().ProcessTree(pid)
+	if err != nil {
+		p.MarkDegraded(fmt.Sprintf("process tree: %v", err))
+		return ""
+	}
+	p.MarkHealthy()
+
+	display := name
+	if usage.Sampled {
+		display += fmt.Sprintf(" %.1f%%", usage.CPUPercent)
+	}
+	memory := formatMemory(usage.RSS)
+	if usage.RSS >= uint64(opts.Int("warning_mb", 2048))<<20 {
+		memory = theme.Signal(theme.Yellow, memory)
+	}
+	return display + " " + memory
+}
+
+// formatMemory formats a byte count as "890MB" or "1.2GB"
+func formatMemory(bytes uint64) string {
+	if bytes >= 1<<30 {
+		return fmt.Sprintf("%.1fGB", float64(bytes)/(1<<30))
+	}
+	return fmt.Sprintf("%dMB", bytes>>20)
+}
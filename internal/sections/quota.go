@@ -0,0 +1,130 @@
+package sections
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/humanize"
+	"github.com/ll931217/claude-hud-enhanced/internal/quota"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+)
+
+// errUsageAPIDisabled is reported while usage_api is off in the config
+var errUsageAPIDisabled = errors.New("usage_api is disabled in the config")
+
+// QuotaSection displays the Claude subscription quota left in the 5-hour and weekly windows
+type QuotaSection struct {
+	*BaseSection
+	usage func() (quota.Usage, bool, error) // Overrides the usage endpoint when set
+}
+
+// NewQuotaSection creates a new quota section (factory function for registry)
+func NewQuotaSection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("quota", appConfig)
+	base.SetPriority(registry.PriorityOptional) // Nice to have; hide first on narrow terminals
+	base.SetMinWidth(12)                        // Minimum width for "5h 58% left"
+	base.SetCacheTTL(30 * time.Second)          // The usage endpoint is cached for usage_api.cache_ttl_ms
+
+	return &QuotaSection{
+		BaseSection: base,
+	}, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("quota", NewQuotaSection, registry.Metadata{
+		Description: "Claude Pro/Max quota left in the 5-hour and weekly windows (needs usage_api.enabled)",
+		Priority:    registry.PriorityOptional,
+		Options: []registry.Option{
+			{Name: "show_weekly", Type: "bool", Default: "true", Description: "Show the weekly quota next to the 5-hour one"},
+			{Name: "show_resets", Type: "bool", Default: "false", Description: "Show when each window resets"},
+		},
+		Dependencies: []registry.Dependency{depClaudeHome},
+	})
+}
+
+// Render returns the quota left, e.g. "5h 58% · 7d 81% left"
+func (q *QuotaSection) Render() string {
+	readUsage := q.usage
+	if readUsage == nil {
+		readUsage = func() (quota.Usage, bool, error) { return subscriptionUsage(q.BaseSection) }
+	}
+	usage, found, err := readUsage()
+	switch {
+	case errors.Is(err, errUsageAPIDisabled), errors.Is(err, quota.ErrNoCredentials):
+		q.MarkUnavailable(err.Error())
+		return ""
+	case err != nil:
+		// Keep showing the last known quota while the endpoint fails
+		q.MarkDegraded(fmt.Sprintf("usage endpoint: %v", err))
+		if !found {
+			return ""
+		}
+	default:
+		q.MarkHealthy()
+	}
+	if !found {
+		return ""
+	}
+
+	opts := q.GetConfig().SectionOptions(q.Name())
+	return formatUsageLeft(usage, opts.Bool("show_weekly", true), opts.Bool("show_resets", false))
+}
+
+// formatUsageLeft formats the quota left in each window, e.g. "5h 58% · 7d 81% left"
+func formatUsageLeft(usage quota.Usage, showWeekly, showResets bool) string {
+	var parts []string
+	if part := formatQuotaLeft("5h", usage.FiveHour, showResets); part != "" {
+		parts = append(parts, part)
+	}
+	if showWeekly {
+		if part := formatQuotaLeft("7d", usage.Weekly, showResets); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, " · ") + " left"
+}
+
+// formatQuotaLeft formats the quota left in a window, e.g. "5h 58%" or "5h 58% (1h12m)"
+func formatQuotaLeft(label string, window quota.Window, showReset bool) string {
+	if window.Percent < 0 {
+		return ""
+	}
+	now := time.Now()
+	used := window.Percent
+	if !window.ResetsAt.IsZero() && !window.Active(now) {
+		used = 0 // The window reset since the endpoint was queried
+	}
+
+	left := fmt.Sprintf("%d%%", max(0, 100-used))
+	left = theme.Signal(quotaColor(used), left)
+	output := label + " " + left
+	if showReset && window.Active(now) {
+		output += theme.Dim + " (" + humanize.Duration(window.Remaining(now), humanize.Short) + ")" + theme.Reset
+	}
+	return output
+}
+
+// subscriptionUsage queries the usage endpoint when usage_api is enabled,
+// waiting a second at most; a slower request finishes in the background
+func subscriptionUsage(b *BaseSection) (quota.Usage, bool, error) {
+	cfg := b.GetConfig()
+	if !cfg.UsageAPI.Enabled {
+		return quota.Usage{}, false, errUsageAPIDisabled
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return b.Providers().UsageAPI().Usage(ctx, time.Duration(cfg.UsageAPI.CacheTTLMs)*time.Millisecond)
+}
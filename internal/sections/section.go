@@ -1,8 +1,14 @@
 package sections
 
 import (
+	"sync"
+	"time"
+
 	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/providers"
 	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/styled"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
 )
 
 // Section represents a renderable section of the statusline
@@ -29,8 +35,22 @@ type BaseSection struct {
 	config   *config.Config
 	priority registry.Priority
 	minWidth int
+	cacheTTL time.Duration
+
+	providersMu sync.RWMutex
+	providers   *providers.Providers
+
+	healthMu sync.RWMutex
+	health   registry.Health
 }
 
+// fallbackProviders is shared by sections created without injected providers
+// (e.g. by calling a factory directly), so they still share data sources
+var (
+	fallbackProviders     *providers.Providers
+	fallbackProvidersOnce sync.Once
+)
+
 // NewBaseSection creates a new base section
 // Order is set to a default value (999) since actual ordering is determined by layout
 func NewBaseSection(name string, cfg *config.Config) *BaseSection {
@@ -45,6 +65,13 @@ func NewBaseSection(name string, cfg *config.Config) *BaseSection {
 	}
 }
 
+// rename gives the section another identifier, for section types with
+// several instances; enablement follows the new name
+func (b *BaseSection) rename(name string) {
+	b.name = name
+	b.enabled = b.config.IsSectionEnabled(name)
+}
+
 // Name returns the section identifier
 func (b *BaseSection) Name() string {
 	return b.name
@@ -65,6 +92,28 @@ func (b *BaseSection) GetConfig() *config.Config {
 	return b.config
 }
 
+// link makes text a hyperlink to url when hyperlinks are enabled
+// Sections link what they show to its web page, such as an issue's
+func (b *BaseSection) link(url, text string) string {
+	if url == "" || text == "" || !b.config.Hyperlinks {
+		return text
+	}
+	return theme.Hyperlink(url, text)
+}
+
+// linkStyled makes styled text a hyperlink to url, as link does text
+func (b *BaseSection) linkStyled(url string, text styled.Text) styled.Text {
+	if url == "" || !b.config.Hyperlinks {
+		return text
+	}
+	linked := make(styled.Text, len(text))
+	for i, span := range text {
+		span.Link = url
+		linked[i] = span
+	}
+	return linked
+}
+
 // Priority returns the display priority for responsive layouts
 func (b *BaseSection) Priority() registry.Priority {
 	if b.priority == registry.PriorityUnset {
@@ -78,6 +127,12 @@ func (b *BaseSection) MinWidth() int {
 	return b.minWidth
 }
 
+// CacheTTL returns how long rendered output may be reused (0 = no caching)
+// A cache_ttl_ms entry in the config overrides the section default
+func (b *BaseSection) CacheTTL() time.Duration {
+	return b.config.GetCacheTTL(b.name, b.cacheTTL)
+}
+
 // SetPriority sets the priority for this section
 func (b *BaseSection) SetPriority(p registry.Priority) {
 	b.priority = p
@@ -87,3 +142,68 @@ func (b *BaseSection) SetPriority(p registry.Priority) {
 func (b *BaseSection) SetMinWidth(w int) {
 	b.minWidth = w
 }
+
+// SetCacheTTL sets the default render cache duration for this section
+func (b *BaseSection) SetCacheTTL(ttl time.Duration) {
+	b.cacheTTL = ttl
+}
+
+// SetProviders injects the shared data providers (see registry.ProviderAware)
+// Values of other types are ignored
+func (b *BaseSection) SetProviders(p interface{}) {
+	shared, ok := p.(*providers.Providers)
+	if !ok {
+		return
+	}
+	b.providersMu.Lock()
+	b.providers = shared
+	b.providersMu.Unlock()
+}
+
+// Providers returns the injected data providers, or the package fallback
+func (b *BaseSection) Providers() *providers.Providers {
+	b.providersMu.RLock()
+	p := b.providers
+	b.providersMu.RUnlock()
+	if p != nil {
+		return p
+	}
+
+	fallbackProvidersOnce.Do(func() {
+		fallbackProviders = providers.New()
+	})
+	return fallbackProviders
+}
+
+// Health returns the last health reported by the section (see registry.HealthReporter)
+func (b *BaseSection) Health() registry.Health {
+	b.healthMu.RLock()
+	defer b.healthMu.RUnlock()
+	return b.health
+}
+
+// MarkHealthy records that the section's data source was read successfully
+func (b *BaseSection) MarkHealthy() {
+	b.setHealth(registry.HealthOK, "")
+}
+
+// MarkDegraded records that the section's data source exists but could not be read
+func (b *BaseSection) MarkDegraded(reason string) {
+	b.setHealth(registry.HealthDegraded, reason)
+}
+
+// MarkUnavailable records that the section's data source is missing
+func (b *BaseSection) MarkUnavailable(reason string) {
+	b.setHealth(registry.HealthUnavailable, reason)
+}
+
+// setHealth updates the health, keeping Since when the state is unchanged
+func (b *BaseSection) setHealth(state registry.HealthState, reason string) {
+	b.healthMu.Lock()
+	defer b.healthMu.Unlock()
+	if b.health.State != state || b.health.Since.IsZero() {
+		b.health.Since = time.Now()
+	}
+	b.health.State = state
+	b.health.Reason = reason
+}
@@ -1,10 +1,28 @@
 package sections
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/ll931217/claude-hud-enhanced/internal/beads"
+	"github.com/ll931217/claude-hud-enhanced/internal/claudestats"
 	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+	"github.com/ll931217/claude-hud-enhanced/internal/git"
+	"github.com/ll931217/claude-hud-enhanced/internal/issues"
+	"github.com/ll931217/claude-hud-enhanced/internal/providers"
 	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/statusline"
+	"github.com/ll931217/claude-hud-enhanced/internal/styled"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
 )
 
 func TestSectionRegistry(t *testing.T) {
@@ -145,3 +163,963 @@ func (m *mockSection) Priority() registry.Priority {
 func (m *mockSection) MinWidth() int {
 	return 0
 }
+
+// countingSection counts how often it is rendered
+type countingSection struct {
+	mockSection
+	renders atomic.Int32
+	output  atomic.Value
+	panics  atomic.Bool
+}
+
+func (c *countingSection) Render() string {
+	c.renders.Add(1)
+	if c.panics.Load() {
+		panic("render failed")
+	}
+	if v, ok := c.output.Load().(string); ok {
+		return v
+	}
+	return "v1"
+}
+
+// stylingSection builds its output as styled text and counts how often
+type stylingSection struct {
+	mockSection
+	renders atomic.Int32
+}
+
+func (s *stylingSection) RenderStyled() styled.Text {
+	s.renders.Add(1)
+	return styled.Plain("CI ").Add("✓", styled.Style{Color: styled.Success, Link: "https://example.com/ci"})
+}
+
+func (s *stylingSection) Render() string {
+	return styled.Render(styled.ANSI{}, s.RenderStyled())
+}
+
+// loggedPanic reports whether a recent log entry reports op panicking with value
+func loggedPanic(op, value string) bool {
+	for _, entry := range errors.Recent(10) {
+		if strings.Contains(entry.Message, op+": panic: "+value) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCachedSection(t *testing.T) {
+	t.Run("reuses output within TTL", func(t *testing.T) {
+		inner := &countingSection{mockSection: mockSection{name: "counting"}}
+		cached := registry.NewCachedSection(inner, time.Hour)
+
+		for i := 0; i < 5; i++ {
+			if got := cached.Render(); got != "v1" {
+				t.Fatalf("Render() = %q, want v1", got)
+			}
+		}
+		if n := inner.renders.Load(); n != 1 {
+			t.Errorf("inner rendered %d times, want 1", n)
+		}
+	})
+
+	t.Run("serves stale output while refreshing", func(t *testing.T) {
+		inner := &countingSection{mockSection: mockSection{name: "counting"}}
+		cached := registry.NewCachedSection(inner, time.Millisecond)
+
+		cached.Render()
+		inner.output.Store("v2")
+		time.Sleep(5 * time.Millisecond)
+
+		// Stale: old value returned, refresh kicked off in the background
+		if got := cached.Render(); got != "v1" {
+			t.Errorf("stale Render() = %q, want v1", got)
+		}
+
+		deadline := time.Now().Add(time.Second)
+		for cached.Render() != "v2" {
+			if time.Now().After(deadline) {
+				t.Fatal("background refresh never completed")
+			}
+			time.Sleep(time.Millisecond)
+		}
+	})
+
+	t.Run("logs a panicking background refresh", func(t *testing.T) {
+		inner := &countingSection{mockSection: mockSection{name: "flaky"}}
+		cached := registry.NewCachedSection(inner, time.Millisecond)
+
+		cached.Render()
+		inner.panics.Store(true)
+		time.Sleep(5 * time.Millisecond)
+
+		if got := cached.Render(); got != "v1" {
+			t.Errorf("stale Render() = %q, want v1", got)
+		}
+		deadline := time.Now().Add(time.Second)
+		for !loggedPanic("section.flaky", "render failed") {
+			if time.Now().After(deadline) {
+				t.Fatal("background panic was not logged")
+			}
+			time.Sleep(time.Millisecond)
+		}
+		if got := cached.Render(); got != "v1" {
+			t.Errorf("Render() after panic = %q, want v1", got)
+		}
+	})
+
+	t.Run("invalidate forces synchronous render", func(t *testing.T) {
+		inner := &countingSection{mockSection: mockSection{name: "counting"}}
+		cached := registry.NewCachedSection(inner, time.Hour)
+
+		cached.Render()
+		inner.output.Store("v2")
+		cached.Invalidate()
+		if got := cached.Render(); got != "v2" {
+			t.Errorf("Render() after Invalidate = %q, want v2", got)
+		}
+	})
+
+	t.Run("keeps a Styler's styled text", func(t *testing.T) {
+		inner := &stylingSection{mockSection: mockSection{name: "styling"}}
+		cached := registry.WithCache(inner, time.Hour)
+		want := inner.RenderStyled()
+		inner.renders.Store(0)
+
+		for i := 0; i < 3; i++ {
+			if got := registry.RenderStyled(cached); !reflect.DeepEqual(got, want) {
+				t.Fatalf("RenderStyled() = %+v, want %+v", got, want)
+			}
+			if got, want := string(registry.AppendRender(nil, cached)), styled.Render(styled.ANSI{}, want); got != want {
+				t.Fatalf("AppendRender() = %q, want %q", got, want)
+			}
+		}
+		if n := inner.renders.Load(); n != 1 {
+			t.Errorf("inner rendered %d times, want 1", n)
+		}
+	})
+
+	t.Run("parses the cached output of other sections", func(t *testing.T) {
+		inner := &countingSection{mockSection: mockSection{name: "counting"}}
+		cached := registry.WithCache(inner, time.Hour)
+		registry.RenderStyled(cached)
+		if got := registry.RenderStyled(cached); !reflect.DeepEqual(got, styled.Plain("v1")) {
+			t.Errorf("RenderStyled() = %+v, want v1", got)
+		}
+		if n := inner.renders.Load(); n != 1 {
+			t.Errorf("inner rendered %d times, want 1", n)
+		}
+	})
+
+	t.Run("WithCache skips zero TTL", func(t *testing.T) {
+		inner := &mockSection{name: "plain"}
+		if got := registry.WithCache(inner, 0); got != registry.Section(inner) {
+			t.Error("WithCache(0) should return the section unchanged")
+		}
+		if _, ok := registry.WithCache(inner, time.Second).(*registry.CachedSection); !ok {
+			t.Error("WithCache(>0) should wrap the section")
+		}
+	})
+
+	t.Run("config overrides section TTL", func(t *testing.T) {
+		cfg := config.DefaultConfig()
+		cfg.CacheTTLMs = map[string]int{"status": 0, "sysinfo": 1500}
+
+		status, _ := NewStatusSection(cfg)
+		if ttl := status.(registry.Cacheable).CacheTTL(); ttl != 0 {
+			t.Errorf("status TTL = %v, want 0 (disabled by config)", ttl)
+		}
+		sysinfo, _ := NewSysInfoSection(cfg)
+		if ttl := sysinfo.(registry.Cacheable).CacheTTL(); ttl != 1500*time.Millisecond {
+			t.Errorf("sysinfo TTL = %v, want 1.5s", ttl)
+		}
+		beads, _ := NewBeadsSection(config.DefaultConfig())
+		if ttl := beads.(registry.Cacheable).CacheTTL(); ttl != 500*time.Millisecond {
+			t.Errorf("beads TTL = %v, want 500ms", ttl)
+		}
+	})
+}
+
+// animatedSection is a mock section with two frames
+type animatedSection struct {
+	mockSection
+	renders atomic.Int32
+}
+
+func (a *animatedSection) RenderFrames() []string {
+	a.renders.Add(1)
+	return []string{"a", "b"}
+}
+
+func (a *animatedSection) Render() string {
+	return registry.FrameAt(a.RenderFrames(), time.Now())
+}
+
+func TestAnimation(t *testing.T) {
+	t.Run("frames follow the clock", func(t *testing.T) {
+		frames := []string{"a", "b", "c"}
+		start := time.UnixMilli(0)
+		for i, want := range []string{"a", "b", "c", "a"} {
+			at := start.Add(time.Duration(i) * registry.FrameInterval)
+			if got := registry.FrameAt(frames, at); got != want {
+				t.Errorf("FrameAt(%v) = %q, want %q", at, got, want)
+			}
+		}
+		if got := registry.FrameAt[string](nil, start); got != "" {
+			t.Errorf("FrameAt(nil) = %q, want empty", got)
+		}
+	})
+
+	t.Run("cache keeps every frame", func(t *testing.T) {
+		inner := &animatedSection{mockSection: mockSection{name: "spinner"}}
+		cached := registry.NewCachedSection(inner, time.Hour)
+		if got := registry.RenderFrames(cached); len(got) != 2 {
+			t.Errorf("RenderFrames() = %q, want both frames", got)
+		}
+		if got := cached.Render(); got != "a" && got != "b" {
+			t.Errorf("Render() = %q, want a frame", got)
+		}
+		if n := inner.renders.Load(); n != 1 {
+			t.Errorf("inner rendered %d times, want 1", n)
+		}
+	})
+
+	t.Run("context bar pulses when nearly full", func(t *testing.T) {
+		t.Cleanup(func() { statusline.SetContextWithWindow("", "", 0, 0, 0) })
+		statusline.SetContextWithWindow("", "", 200000, 190000, 0)
+
+		cfg := config.DefaultConfig()
+		cfg.Sections = config.SectionsConfig{"contextbar": {"show_breakdown": false}}
+		section, _ := NewContextBarSection(cfg)
+		frames := section.(registry.Animated).RenderFrames()
+		want := []string{
+			theme.Red + "█████████░ 95%" + theme.Reset,
+			theme.Dim + theme.Red + "█████████░ 95%" + theme.Reset,
+		}
+		if len(frames) != 2 || frames[0] != want[0] || frames[1] != want[1] {
+			t.Errorf("RenderFrames() = %q, want %q", frames, want)
+		}
+
+		cfg.Animate = false
+		section, _ = NewContextBarSection(cfg)
+		if frames := section.(registry.Animated).RenderFrames(); len(frames) != 1 {
+			t.Errorf("RenderFrames() with animate off = %q, want one frame", frames)
+		}
+	})
+}
+
+func TestSectionMetadata(t *testing.T) {
+	t.Run("built-in sections describe themselves", func(t *testing.T) {
+		for _, meta := range registry.All() {
+			if meta.Name == "custom" {
+				continue // registered by TestSectionRegistry without metadata
+			}
+			if meta.Description == "" {
+				t.Errorf("section %q has no description", meta.Name)
+			}
+			if meta.Priority == registry.PriorityUnset {
+				t.Errorf("section %q has no default priority", meta.Name)
+			}
+		}
+
+		meta, ok := registry.GetMetadata("zaiusage")
+		if !ok || len(meta.Options) == 0 || meta.Options[0].Name != "show_reset_times" {
+			t.Errorf("zaiusage metadata = %+v", meta)
+		}
+	})
+
+	t.Run("registry without metadata reports the name", func(t *testing.T) {
+		r := registry.NewSectionRegistry()
+		r.Register("plain", func(interface{}) (registry.Section, error) { return &mockSection{name: "plain"}, nil })
+		r.RegisterWithMetadata("alpha", func(interface{}) (registry.Section, error) { return &mockSection{name: "alpha"}, nil },
+			registry.Metadata{Description: "first"})
+
+		all := r.All()
+		if len(all) != 2 || all[0].Name != "alpha" || all[1].Name != "plain" {
+			t.Fatalf("All() = %+v, want alpha then plain", all)
+		}
+		if all[0].Description != "first" {
+			t.Errorf("alpha description = %q", all[0].Description)
+		}
+		if _, ok := r.Metadata("missing"); ok {
+			t.Error("Metadata() should report unregistered sections as missing")
+		}
+	})
+
+	t.Run("dependency checks", func(t *testing.T) {
+		dir := t.TempDir()
+		nested := filepath.Join(dir, "a", "b")
+		if err := os.MkdirAll(nested, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if depGit.Available(nested) {
+			t.Error("git should be missing outside a repository")
+		}
+		if err := os.Mkdir(filepath.Join(dir, ".git"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if !depGit.Available(nested) {
+			t.Error("git should be found in a parent directory")
+		}
+		if !depStatusline.Available(dir) {
+			t.Error("dependencies without a check are always available")
+		}
+	})
+}
+
+func TestSharedProviders(t *testing.T) {
+	shared := providers.New()
+	defer shared.Close()
+
+	r := registry.NewSectionRegistry()
+	r.Register("tools", NewToolsSection)
+	r.Register("agents", NewAgentsSection)
+	r.SetProviders(shared)
+
+	cfg := config.DefaultConfig()
+	tools, err := r.Create("tools", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	agents, err := r.Create("agents", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	toolsProviders := tools.(*ToolsSection).Providers()
+	agentsProviders := agents.(*AgentsSection).Providers()
+	if toolsProviders != shared || agentsProviders != shared {
+		t.Fatal("registry should inject its providers into created sections")
+	}
+	if toolsProviders.Transcript("/tmp/session.jsonl") != agentsProviders.Transcript("/tmp/session.jsonl") {
+		t.Error("sections should share one parser per transcript")
+	}
+
+	standalone := &mockSection{name: "plain"}
+	r.Register("plain", func(interface{}) (registry.Section, error) { return standalone, nil })
+	if _, err := r.Create("plain", cfg); err != nil {
+		t.Errorf("sections without provider support should still be created: %v", err)
+	}
+}
+
+// TestTranscriptDiscovery tests finding the session of the working directory
+// without Claude Code's statusline input
+func TestTranscriptDiscovery(t *testing.T) {
+	home, project := t.TempDir(), t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CLAUDE_HUD_TRANSCRIPT_PATH", "")
+	t.Chdir(project)
+	resetDiscovery := func() { discovered.at = time.Time{} }
+	resetDiscovery()
+	t.Cleanup(resetDiscovery)
+
+	b := NewBaseSection("test", config.DefaultConfig())
+	if got := b.getTranscriptPath(); got != "" {
+		t.Fatalf("getTranscriptPath() = %q, want empty without sessions", got)
+	}
+
+	projectsDir := filepath.Join(home, ".claude", "projects")
+	path := filepath.Join(transcript.ProjectDirFor(projectsDir, project), "session.jsonl")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	resetDiscovery()
+	if got := b.getTranscriptPath(); got != path {
+		t.Errorf("getTranscriptPath() = %q, want %q", got, path)
+	}
+}
+
+func TestSectionHealth(t *testing.T) {
+	t.Setenv("CLAUDE_HUD_TRANSCRIPT_PATH", "")
+	t.Setenv("HOME", t.TempDir()) // No sessions to discover
+	t.Chdir(t.TempDir())
+
+	section, err := NewToolsSection(config.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := section.Render(); got != "" {
+		t.Fatalf("Render() = %q, want empty without a transcript", got)
+	}
+	health := registry.HealthOf(section)
+	if health.State != registry.HealthUnavailable || health.Reason != "no transcript" {
+		t.Errorf("health = %+v, want unavailable: no transcript", health)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "session.jsonl")
+	if err := os.WriteFile(path, []byte(`{"type":"user","timestamp":"2026-01-01T00:00:00Z"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CLAUDE_HUD_TRANSCRIPT_PATH", path)
+	section.Render()
+	if health := registry.HealthOf(section); !health.OK() {
+		t.Errorf("health = %+v, want ok once the transcript is readable", health)
+	}
+}
+
+func TestClaudeStatsSectionRender(t *testing.T) {
+	stats := &claudestats.StatsCache{
+		CoreCount:     30,
+		MCPCount:      3,
+		MCPToolsCount: 42,
+		Skills:        claudestats.SourceCounts{User: 2, Plugin: 3},
+		Commands:      claudestats.SourceCounts{User: 4, Project: 2},
+		Agents:        claudestats.SourceCounts{Project: 1},
+		HooksCount:    2,
+	}
+
+	tests := []struct {
+		name    string
+		stats   *claudestats.StatsCache
+		options config.SectionOptions
+		want    string
+	}{
+		{
+			name:  "totals",
+			stats: stats,
+			want:  "Core:30 | MCP:3 (42 tools) | Skills:5 | Cmds:6 | Agents:1 | Hooks:2",
+		},
+		{
+			name:    "breakdown",
+			stats:   stats,
+			options: config.SectionOptions{"breakdown": true},
+			want:    "Core:30 | MCP:3 (42 tools) | Skills:5 (u2 pl3) | Cmds:6 (u4 p2) | Agents:1 (p1) | Hooks:2",
+		},
+		{
+			name:  "tools not listed yet",
+			stats: &claudestats.StatsCache{MCPCount: 2, OutputStyles: claudestats.SourceCounts{User: 1}},
+			want:  "MCP:2 | Styles:1",
+		},
+		{
+			name:  "nothing configured",
+			stats: &claudestats.StatsCache{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"claudestats": tt.options}
+			section, err := NewClaudeStatsSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := section.(*ClaudeStatsSection)
+			s.collect = func(context.Context) *claudestats.StatsCache { return tt.stats }
+
+			if got := s.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestContextBarSection_LongContext tests the switch to the 1M window
+// and the long-context marker
+func TestContextBarSection_LongContext(t *testing.T) {
+	t.Cleanup(func() {
+		statusline.SetContextWithWindow("", "", 0, 0, 0)
+		statusline.SetLongContext("", false)
+	})
+	bar := func(bar string, percentage int) string {
+		color := theme.ContextColor(percentage)
+		if color == "" {
+			return fmt.Sprintf("%s %d%%", bar, percentage)
+		}
+		return fmt.Sprintf("%s%s %d%%%s", color, bar, percentage, theme.Reset)
+	}
+
+	tests := []struct {
+		name    string
+		modelID string
+		window  int
+		tokens  int
+		exceeds bool
+		want    string
+	}{
+		{
+			name:   "standard model",
+			window: 200000,
+			tokens: 100000,
+			want:   bar("█████░░░░░", 50),
+		},
+		{
+			name:    "1M model under 200k",
+			modelID: "claude-sonnet-4-5[1m]",
+			window:  200000,
+			tokens:  100000,
+			want:    bar("█░░░░░░░░░", 10),
+		},
+		{
+			name:    "flag switches to the larger window",
+			window:  200000,
+			tokens:  300000,
+			exceeds: true,
+			want:    bar("███░░░░░░░", 30) + " " + theme.Yellow + "»200k" + theme.Reset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statusline.SetContextWithWindow("", "", tt.window, tt.tokens, 0)
+			statusline.SetLongContext(tt.modelID, tt.exceeds)
+
+			section, err := NewContextBarSection(config.DefaultConfig())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := section.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestContextBarSection_Options tests configurable thresholds, bar and breakdown
+func TestContextBarSection_Options(t *testing.T) {
+	t.Cleanup(func() { statusline.SetContextWithWindow("", "", 0, 0, 0) })
+
+	tests := []struct {
+		name    string
+		tokens  int
+		options config.SectionOptions
+		want    string
+	}{
+		{
+			name:   "defaults",
+			tokens: 180000,
+			want:   theme.Red + "█████████░ 90%" + theme.Reset + theme.Dim + " (in: 180k)" + theme.Reset,
+		},
+		{
+			name:    "custom thresholds",
+			tokens:  120000,
+			options: config.SectionOptions{"warning_percent": 50, "critical_percent": 60},
+			want:    theme.Red + "██████░░░░ 60%" + theme.Reset + theme.Dim + " (in: 120k)" + theme.Reset,
+		},
+		{
+			name:    "custom bar without breakdown",
+			tokens:  180000,
+			options: config.SectionOptions{"bar_width": 5, "bar_filled": "#", "bar_empty": "-", "show_breakdown": false},
+			want:    theme.Red + "####- 90%" + theme.Reset,
+		},
+		{
+			name:    "invalid values fall back to defaults",
+			tokens:  150000,
+			options: config.SectionOptions{"warning_percent": 95, "critical_percent": 90, "bar_width": 0},
+			want:    theme.Yellow + "███████░░░ 75%" + theme.Reset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statusline.SetContextWithWindow("", "", 200000, tt.tokens, 0)
+
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"contextbar": tt.options}
+			section, err := NewContextBarSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := section.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestContextBarSection_Accessibility tests the warning symbol and the
+// description for screen readers
+func TestContextBarSection_Accessibility(t *testing.T) {
+	t.Cleanup(func() {
+		statusline.SetContextWithWindow("", "", 0, 0, 0)
+		theme.SetSymbols(false)
+	})
+	statusline.SetContextWithWindow("", "", 200000, 150000, 0)
+	theme.SetSymbols(true)
+
+	section, err := NewContextBarSection(config.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := section.Render(), theme.Yellow+"███████░░░ "+theme.WarningMarker+"75%"+theme.Reset; got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+	if got, want := section.(registry.Describer).Describe(), "context: 75 percent, warning"; got != want {
+		t.Errorf("Describe() = %q, want %q", got, want)
+	}
+}
+
+// TestFormatLastCommit tests the last commit shown by the status section
+func TestFormatLastCommit(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	commit := git.Commit{Subject: "fix parser for nested quotes in YAML", Author: "Ada", Time: now.Add(-2*time.Hour - 10*time.Minute)}
+
+	if got, want := formatLastCommit(commit, "", 29, now), (styled.Text{{Text: "last: fix parser for nested quotes… (2h ago)", Style: styled.Dimmed}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("formatLastCommit() = %+v, want %+v", got, want)
+	}
+	if got, want := formatLastCommit(commit, "Ada", 10, now), (styled.Text{{Text: "last: fix parse… by Ada (2h ago)", Style: styled.Dimmed}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("formatLastCommit() with author = %+v, want %+v", got, want)
+	}
+}
+
+// TestStatusSectionCheckpoint tests the reminder to commit a large uncommitted diff
+func TestStatusSectionCheckpoint(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Sections = config.SectionsConfig{"status": config.SectionOptions{
+		"checkpoint":          true,
+		"checkpoint_lines":    300,
+		"checkpoint_files":    0,
+		"checkpoint_after_ms": 0,
+		"checkpoint_notify":   true,
+	}}
+	section, err := NewStatusSection(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := section.(*StatusSection)
+	s.SetProviders(providers.New())
+	stat := git.DiffStat{Files: 14, Insertions: 312, Deletions: 88}
+	s.diffStat = func() (git.DiffStat, error) { return stat, nil }
+	var notifications []string
+	s.notify = func(title, message string) { notifications = append(notifications, title+": "+message) }
+	opts := cfg.SectionOptions("status")
+
+	if !s.checkpointDue(opts) || !s.checkpointDue(opts) {
+		t.Error("checkpointDue() = false for 400 changed lines")
+	}
+	if len(notifications) != 1 || notifications[0] != "Commit checkpoint recommended: 400 lines in 14 files uncommitted for <1m" {
+		t.Errorf("notifications = %q, want one", notifications)
+	}
+
+	stat = git.DiffStat{Files: 14, Insertions: 20}
+	if s.checkpointDue(opts) {
+		t.Error("checkpointDue() = true under the thresholds")
+	}
+
+	// A later stretch over the thresholds gets its own reminder
+	stat.Insertions = 500
+	s.checkpointDue(opts)
+	if len(notifications) != 2 {
+		t.Errorf("notifications = %q, want a second one", notifications)
+	}
+}
+
+// TestWorkspaceSectionRepoStats tests the tracked file count of the verbose workspace section
+func TestWorkspaceSectionRepoStats(t *testing.T) {
+	section, err := NewWorkspaceSection(config.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := section.(*WorkspaceSection)
+
+	w.repoStats = func() (git.RepoStats, bool, error) {
+		return git.RepoStats{Files: 1234, Bytes: 56 << 20}, true, nil
+	}
+	if got, want := w.formatRepoStats(), "1,234 files 56MB"; got != want {
+		t.Errorf("formatRepoStats() = %q, want %q", got, want)
+	}
+
+	// Nothing until the first count finished
+	w.repoStats = func() (git.RepoStats, bool, error) { return git.RepoStats{}, false, nil }
+	if got := w.formatRepoStats(); got != "" {
+		t.Errorf("formatRepoStats() before counting = %q, want empty", got)
+	}
+
+	for bytes, want := range map[int64]string{512: "512B", 820 << 10: "820KB", 3 << 29: "1.5GB"} {
+		if got := formatSize(bytes); got != want {
+			t.Errorf("formatSize(%d) = %q, want %q", bytes, got, want)
+		}
+	}
+	for n, want := range map[int]string{7: "7", 999: "999", 1000: "1,000", 1234567: "1,234,567"} {
+		if got := formatThousands(n); got != want {
+			t.Errorf("formatThousands(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+// TestWorkspaceSectionDir tests that the workspace section describes Claude
+// Code's current directory rather than the process's
+func TestWorkspaceSectionDir(t *testing.T) {
+	project := t.TempDir()
+	current := filepath.Join(project, "scripts")
+	if err := os.MkdirAll(current, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(current, "build.py"), []byte("print()\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	shared := providers.New()
+	shared.SetWorkspace(current, project)
+
+	section, err := NewWorkspaceSection(config.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	section.(*WorkspaceSection).SetProviders(shared)
+	got := section.Render()
+	if want := filepath.Base(project) + " ▸ scripts"; !strings.Contains(got, "Python") || !strings.Contains(got, want) {
+		t.Errorf("Render() = %q, want Python and %q", got, want)
+	}
+}
+
+// TestFormatWorkspaceDir tests the current directory relative to the project
+func TestFormatWorkspaceDir(t *testing.T) {
+	tests := []struct {
+		project, current, want string
+	}{
+		{"", "/src/app", "~/app"},
+		{"/src/app", "/src/app", "~/app"},
+		{"/src/app", "/src/app/web/src", "app ▸ web/src"},
+		{"/src/app/", "/src/app/web", "app ▸ web"},
+		{"/src/app", "/src/other", "~/app"}, // Outside the project
+		{"/src/app", "/src/application", "~/app"},
+	}
+	for _, tt := range tests {
+		if got := formatWorkspaceDir(tt.project, tt.current, "~/app"); got != tt.want {
+			t.Errorf("formatWorkspaceDir(%q, %q) = %q, want %q", tt.project, tt.current, got, tt.want)
+		}
+	}
+}
+
+// TestWorkspaceSectionHost tests the remote host shown for SSH sessions and
+// hosts other than the configured home
+func TestWorkspaceSectionHost(t *testing.T) {
+	tests := []struct {
+		name, host, home string
+		ssh              bool
+		want             string
+	}{
+		{name: "over ssh", host: "devbox", ssh: true, want: "@devbox"},
+		{name: "home machine", host: "laptop", home: "laptop", want: ""},
+		{name: "home with domain", host: "laptop", home: "Laptop.local", want: ""},
+		{name: "other machine", host: "devbox", home: "laptop", want: "@devbox"},
+		{name: "no home set", host: "laptop", want: ""},
+		{name: "ssh into home", host: "laptop", home: "laptop", ssh: true, want: "@laptop"},
+		{name: "unknown hostname", host: "", ssh: true, want: ""},
+	}
+	for _, tt := range tests {
+		if got := remoteHostLabel(tt.host, tt.ssh, tt.home); got != tt.want {
+			t.Errorf("%s: remoteHostLabel(%q, %v, %q) = %q, want %q", tt.name, tt.host, tt.ssh, tt.home, got, tt.want)
+		}
+	}
+
+	shared := providers.New()
+	shared.SetWorkspace(t.TempDir(), "")
+
+	cfg := config.DefaultConfig()
+	section, err := NewWorkspaceSection(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := section.(*WorkspaceSection)
+	w.SetProviders(shared)
+	w.host = func() (string, bool) { return "devbox", true }
+	if got := w.Render(); !strings.HasPrefix(got, theme.Bold+theme.Purple+"@devbox"+theme.Reset+" | ") {
+		t.Errorf("Render() = %q, want the host first", got)
+	}
+
+	cfg.Sections = config.SectionsConfig{"workspace": {"show_host": false}}
+	if got := w.Render(); strings.Contains(got, "@devbox") {
+		t.Errorf("Render() with show_host off = %q", got)
+	}
+}
+
+// TestBuiltinSectionsStyled tests that the styles of built-in sections reach
+// outputs other than a terminal
+func TestBuiltinSectionsStyled(t *testing.T) {
+	for _, name := range []string{"contextbar", "duration", "zaiusage", "beads", "workspace", "status", "sysinfo", "sessions"} {
+		section, err := registry.DefaultRegistry().Create(name, config.DefaultConfig())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := section.(registry.Styler); !ok {
+			t.Errorf("%s does not build styled text", name)
+		}
+	}
+
+	t.Cleanup(func() { statusline.SetContextWithWindow("", "", 0, 0, 0) })
+	statusline.SetContextWithWindow("", "", 200000, 190000, 0)
+	shared := providers.New()
+	shared.SetWorkspace(t.TempDir(), "")
+
+	cfg := config.DefaultConfig()
+	cfg.Animate = false // Keep the bar from pulsing
+	cfg.Layout.Responsive.Enabled = false
+	cfg.Layout.Lines = []config.LineConfig{{Sections: []string{"workspace", "contextbar"}, Separator: " | "}}
+	cfg.Sections = config.SectionsConfig{"contextbar": {"show_breakdown": false}}
+	workspace, _ := NewWorkspaceSection(cfg)
+	workspace.(*WorkspaceSection).SetProviders(shared)
+	workspace.(*WorkspaceSection).host = func() (string, bool) { return "devbox", true }
+	contextbar, _ := NewContextBarSection(cfg)
+
+	tests := []struct {
+		backend styled.Backend
+		want    []string
+	}{
+		{styled.Tmux{}, []string{"#[fg=colour177,bold]@devbox#[default] | ", "#[fg=colour203]█████████░ 95%#[default]"}},
+		{styled.JSON{}, []string{`{"text":"@devbox","color":"secondary","bold":true}`, `{"text":"█████████░ 95%","color":"error"}`}},
+	}
+	for _, tt := range tests {
+		sl, _ := statusline.New(cfg, nil)
+		var out strings.Builder
+		sl.SetOutput(&out)
+		sl.SetBackend(tt.backend)
+		sl.SetSections([]registry.Section{workspace, contextbar})
+		if err := sl.RenderStatuslineMode(); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("%s output %q lacks %q", tt.backend.Name(), out.String(), want)
+			}
+		}
+	}
+}
+
+// TestBeadsSectionEpic tests the active issue with its epic's progress bar
+func TestBeadsSectionEpic(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".beads"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := `{"id":"bd-1","title":"Auth rework","status":"open","priority":1,"issue_type":"epic"}
+{"id":"bd-1.1","title":"Login form","status":"closed","priority":2,"issue_type":"task"}
+{"id":"bd-1.2","title":"Rotate refresh tokens on every use","status":"in_progress","priority":1,"issue_type":"task"}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".beads", "issues.jsonl"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Sections = config.SectionsConfig{"beads": config.SectionOptions{"title_length": 15, "bar_width": 4}}
+	section, err := NewBeadsSection(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := section.(*BeadsSection)
+	b.repoPath = tmpDir
+	p := providers.New()
+	defer p.Close()
+	b.SetProviders(p)
+
+	if got, want := b.Render(), "◐ • bd-1.2 • Rotate refresh… • P1 • Auth rework ██░░ 1/2"; got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+// TestBeadsSectionTracker tests the current issue from a remote tracker
+func TestBeadsSectionTracker(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Sections = config.SectionsConfig{"beads": config.SectionOptions{"provider": "linear", "token": "lin_api_key"}}
+	section, err := NewBeadsSection(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := section.(*BeadsSection)
+	b.repoPath = t.TempDir()
+	b.SetProviders(providers.New())
+
+	var asked issues.Provider
+	snapshot := issues.Snapshot{Open: 3, Total: 5}
+	b.snapshot = func(provider issues.Provider, interval, timeout time.Duration) (issues.Snapshot, bool, error) {
+		asked = provider
+		return snapshot, true, nil
+	}
+	if got := b.Render(); got != "linear: 3/5" {
+		t.Errorf("Render() without a current issue = %q", got)
+	}
+	if l, ok := asked.(issues.Linear); !ok || l.Token != "lin_api_key" {
+		t.Errorf("asked %#v, want Linear with the configured token", asked)
+	}
+
+	snapshot.Current = &issues.Issue{ID: "ENG-123", Title: "Fix login", Status: beads.StatusInProgress, Priority: "High",
+		Epic: &issues.Epic{Title: "Auth rework", Closed: 1, Total: 2}}
+	if got, want := b.Render(), "◐ • ENG-123 • Fix login • High • Auth rework ██░░░ 1/2"; got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+
+	cfg.Hyperlinks = true
+	snapshot.Current.URL = "https://linear.app/acme/issue/ENG-123"
+	link := theme.Hyperlink(snapshot.Current.URL, "ENG-123")
+	if got, want := b.Render(), "◐ • "+link+" • Fix login • High • Auth rework ██░░░ 1/2"; got != want {
+		t.Errorf("Render() with hyperlinks = %q, want %q", got, want)
+	}
+	cfg.Hyperlinks = false
+
+	cfg.Sections["beads"]["provider"] = "redmine"
+	if got := b.Render(); got != "" || b.Health().State != registry.HealthDegraded {
+		t.Errorf("Render() with an unknown provider = %q, health %v", got, b.Health())
+	}
+}
+
+// TestDurationSectionName tests the session name shown before the duration
+func TestDurationSectionName(t *testing.T) {
+	transcriptPath := filepath.Join(t.TempDir(), "abc123.jsonl")
+	if err := os.WriteFile(transcriptPath, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	statusline.SetContext(transcriptPath, "")
+	t.Cleanup(func() { statusline.SetContext("", "") })
+
+	tests := []struct {
+		name    string
+		session string
+		err     error
+		options config.SectionOptions
+		want    string
+	}{
+		{name: "unnamed", want: "0s"},
+		{name: "named", session: "auth refactor", want: theme.Bold + "auth refactor" + theme.Reset + " 0s"},
+		{name: "name hidden", session: "auth refactor", options: config.SectionOptions{"show_name": false}, want: "0s"},
+		{name: "state unreadable", err: fmt.Errorf("corrupt"), want: "0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sections = config.SectionsConfig{"duration": tt.options}
+			section, err := NewDurationSection(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := section.(*DurationSection)
+			d.readName = func(path string) (string, error) {
+				if path != transcriptPath {
+					t.Errorf("readName(%q), want %q", path, transcriptPath)
+				}
+				return tt.session, tt.err
+			}
+			if got := d.Render(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestModelSectionAppendRender tests that the model section shortens model
+// names as it appends them, without allocating
+func TestModelSectionAppendRender(t *testing.T) {
+	statusline.SetContext("", "Claude Sonnet 4.5")
+	t.Cleanup(func() { statusline.SetContext("", "") })
+
+	section, err := NewModelSection(config.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := section.Render(); got != "SN 4.5" {
+		t.Errorf("Render() = %q, want %q", got, "SN 4.5")
+	}
+
+	appender := section.(registry.Appender)
+	buf := make([]byte, 0, 64)
+	if got := string(appender.AppendRender(append(buf, "» "...))); got != "» SN 4.5" {
+		t.Errorf("AppendRender() = %q, want %q", got, "» SN 4.5")
+	}
+	if allocs := testing.AllocsPerRun(100, func() { appender.AppendRender(buf[:0]) }); allocs != 0 {
+		t.Errorf("AppendRender allocated %v times, want 0", allocs)
+	}
+}
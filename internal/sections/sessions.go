@@ -0,0 +1,113 @@
+package sections
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/i18n"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+	"github.com/ll931217/claude-hud-enhanced/internal/styled"
+)
+
+// SessionsSection sums up every Claude Code session the hooks have seen
+// recently, for keeping an eye on many sessions running at once
+type SessionsSection struct {
+	*BaseSection
+	readSessions func() (map[string]*session.SessionState, error) // Overrides the shared state file when set
+	now          func() time.Time                                 // Overrides the clock when set
+}
+
+// NewSessionsSection creates a new sessions section (factory function for registry)
+func NewSessionsSection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("sessions", appConfig)
+	base.SetPriority(registry.PriorityOptional) // Other sessions matter less than this one
+	base.SetMinWidth(4)                         // Minimum width for "⧉ 5"
+
+	return &SessionsSection{
+		BaseSection: base,
+		now:         time.Now,
+	}, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("sessions", NewSessionsSection, registry.Metadata{
+		Description: "How many Claude Code sessions are active, working and waiting for approval",
+		Priority:    registry.PriorityOptional,
+		Options: []registry.Option{
+			{Name: "active_ms", Type: "duration_ms", Default: "1800000", Description: "Count sessions with a hook event within this long"},
+			{Name: "min_sessions", Type: "int", Default: "2", Description: "Hide the section while fewer sessions are active"},
+		},
+	})
+}
+
+// Render returns the sessions section output, e.g.
+// "⧉ 5 sessions · 3 working · 1 approval"
+func (s *SessionsSection) Render() string {
+	return styled.Render(styled.ANSI{}, s.RenderStyled())
+}
+
+// RenderStyled returns the sessions section output as styled text, with the
+// approval count in bold amber
+func (s *SessionsSection) RenderStyled() styled.Text {
+	readSessions := s.readSessions
+	if readSessions == nil {
+		readSessions = loadSessions
+	}
+	sessions, err := readSessions()
+	if err != nil {
+		s.MarkDegraded(fmt.Sprintf("state file unreadable: %v", err))
+		return nil
+	}
+	s.MarkHealthy()
+
+	opts := s.GetConfig().SectionOptions(s.Name())
+	window := opts.Duration("active_ms", 30*time.Minute)
+	now := s.now()
+
+	var active, working, approval int
+	for _, sess := range sessions {
+		if now.Sub(sess.LastEventAt) > window {
+			continue
+		}
+		active++
+		if !sess.Stopped {
+			working++
+		}
+		if sess.Approval != nil {
+			approval++
+		}
+	}
+	if active == 0 || active < opts.Int("min_sessions", 2) {
+		return nil
+	}
+
+	output := styled.Plain("⧉ " + i18n.N("sessions.active", active))
+	if working > 0 {
+		output = output.Add(" · "+i18n.T("sessions.working", working), styled.None)
+	}
+	if approval > 0 {
+		output = output.Add(" · ", styled.None)
+		output = output.Add(i18n.T("sessions.approval", approval), styled.Style{Color: styled.Warning, Bold: true})
+	}
+	return output
+}
+
+// loadSessions reads every session from the shared state file
+func loadSessions() (map[string]*session.SessionState, error) {
+	store, err := session.DefaultStore()
+	if err != nil {
+		return nil, err
+	}
+	state, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+	return state.Sessions, nil
+}
@@ -0,0 +1,155 @@
+package sections
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/humanize"
+	"github.com/ll931217/claude-hud-enhanced/internal/i18n"
+	"github.com/ll931217/claude-hud-enhanced/internal/quota"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+	"github.com/ll931217/claude-hud-enhanced/internal/zai"
+)
+
+// SessionWindowSection counts down to the reset of the 5-hour usage window
+type SessionWindowSection struct {
+	*BaseSection
+	zaiClient *zai.Client
+	window    func() (quota.Window, bool, error) // Overrides the configured source when set
+}
+
+// NewSessionWindowSection creates a new session window section (factory function for registry)
+func NewSessionWindowSection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("sessionwindow", appConfig)
+	base.SetPriority(registry.PriorityOptional) // Nice to have; hide first on narrow terminals
+	base.SetMinWidth(14)                        // Minimum width for "5h resets 1h12m"
+	base.SetCacheTTL(15 * time.Second)          // Minute-level countdown; sources cache for longer
+
+	return &SessionWindowSection{
+		BaseSection: base,
+		zaiClient:   zai.NewClient(),
+	}, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("sessionwindow", NewSessionWindowSection, registry.Metadata{
+		Description: "Countdown to the 5-hour usage window reset, with a progress bar",
+		Priority:    registry.PriorityOptional,
+		Options: []registry.Option{
+			{Name: "source", Type: "string", Default: "auto", Description: "Where the window comes from: auto, oauth, zai or transcripts"},
+			{Name: "show_bar", Type: "bool", Default: "true", Description: "Show a bar of used quota, or of elapsed time when usage is unknown"},
+			{Name: "bar_width", Type: "int", Default: "8", Description: "Bar width in characters (1-50)"},
+		},
+		Dependencies: []registry.Dependency{depClaudeHome},
+	})
+}
+
+// Render returns the session window output, e.g. "5h ███░░░░░ 42% resets 1h12m"
+func (s *SessionWindowSection) Render() string {
+	readWindow := s.window
+	if readWindow == nil {
+		readWindow = s.configuredWindow
+	}
+	window, ok, err := readWindow()
+	if errors.Is(err, fs.ErrNotExist) {
+		s.MarkUnavailable("no Claude Code transcripts")
+		return ""
+	}
+	if err != nil {
+		s.MarkDegraded(fmt.Sprintf("usage window: %v", err))
+		return ""
+	}
+	s.MarkHealthy()
+
+	now := time.Now()
+	if !ok || !window.Active(now) {
+		return "" // Nothing used since the last reset
+	}
+
+	opts := s.GetConfig().SectionOptions(s.Name())
+	output := "5h"
+
+	// Pace against used quota when it is reported, otherwise against the clock
+	fill := window.Percent
+	if fill < 0 {
+		fill = window.ElapsedPercent(now)
+	}
+	if opts.Bool("show_bar", true) {
+		width := opts.Int("bar_width", 8)
+		if width < 1 || width > 50 {
+			width = 8
+		}
+		output += " " + progressBar(fill, width, "█", "░")
+	}
+	if window.Percent >= 0 {
+		percent := fmt.Sprintf("%d%%", window.Percent)
+		output += " " + theme.Signal(quotaColor(window.Percent), percent)
+	}
+
+	reset := humanize.Duration(window.Remaining(now), humanize.Short)
+	if window.Estimated() {
+		reset = "~" + reset // Estimated from local activity
+	}
+	return output + " " + i18n.T("window.resets", reset)
+}
+
+// configuredWindow reads the window from the source selected in the options
+func (s *SessionWindowSection) configuredWindow() (quota.Window, bool, error) {
+	cfg := s.GetConfig()
+	source := cfg.SectionOptions(s.Name()).String("source", "auto")
+	// In auto mode a failing source falls through to the next one
+	if source == quota.SourceOAuth || (source == "auto" && cfg.UsageAPI.Enabled) {
+		usage, ok, err := subscriptionUsage(s.BaseSection)
+		if source == quota.SourceOAuth {
+			return usage.FiveHour, ok, err
+		}
+		if ok && usage.FiveHour.Active(time.Now()) {
+			return usage.FiveHour, true, nil
+		}
+	}
+	if source == quota.SourceZai || (source == "auto" && hasZaiKey("")) {
+		window, ok := s.zaiWindow()
+		if source == quota.SourceZai {
+			return window, ok, s.zaiClient.Err()
+		}
+		if ok {
+			return window, true, nil
+		}
+	}
+	return s.Providers().SessionWindow().Window(time.Now())
+}
+
+// zaiWindow returns the session window reported by the Z.ai quota API
+func (s *SessionWindowSection) zaiWindow() (quota.Window, bool) {
+	info := s.zaiClient.Fetch()
+	if info == nil || info.SessionReset.IsZero() {
+		return quota.Window{}, false
+	}
+	return quota.Window{
+		Start:    info.SessionReset.Add(-quota.SessionWindowLength),
+		ResetsAt: info.SessionReset,
+		Percent:  info.SessionPercent,
+		Source:   quota.SourceZai,
+	}, true
+}
+
+// quotaColor returns the color for a used quota percentage
+func quotaColor(percent int) string {
+	switch {
+	case percent >= 90:
+		return theme.Red
+	case percent >= 70:
+		return theme.Yellow
+	default:
+		return ""
+	}
+}
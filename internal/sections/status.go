@@ -2,17 +2,27 @@ package sections
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/ll931217/claude-hud-enhanced/internal/ci"
 	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
 	"github.com/ll931217/claude-hud-enhanced/internal/git"
+	"github.com/ll931217/claude-hud-enhanced/internal/humanize"
+	"github.com/ll931217/claude-hud-enhanced/internal/i18n"
 	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/styled"
+	"github.com/ll931217/claude-hud-enhanced/internal/system"
 )
 
 // StatusSection displays git status information
 type StatusSection struct {
 	*BaseSection
-	detector *git.Detector
+	repoPath string                       // Resolved on first render
+	diffStat func() (git.DiffStat, error) // Overrides git diff when set
+	notify   func(title, message string)  // Overrides desktop notifications when set
 }
 
 // NewStatusSection creates a new status section (factory function for registry)
@@ -22,27 +32,159 @@ func NewStatusSection(cfg interface{}) (registry.Section, error) {
 		appConfig = config.DefaultConfig()
 	}
 
-	repoPath := getRepoPath()
+	base := NewBaseSection("status", appConfig)
+	base.SetCacheTTL(2 * time.Second) // git status shells out; reuse between refreshes
 
 	return &StatusSection{
-		BaseSection: NewBaseSection("status", appConfig),
-		detector:    git.NewDetector(repoPath),
+		BaseSection: base,
 	}, nil
 }
 
 func init() {
-	registry.Register("status", NewStatusSection)
+	registry.RegisterWithMetadata("status", NewStatusSection, registry.Metadata{
+		Description:  "Git branch, dirty files, ahead/behind and stashes",
+		Priority:     registry.PriorityImportant,
+		Dependencies: []registry.Dependency{depGit},
+		Options: []registry.Option{
+			{Name: "base_branch", Type: "string", Default: "auto", Description: "Branch to count commits against, e.g. \"develop\"; auto picks origin's default, then main or master; \"\" turns it off"},
+			{Name: "show_diff", Type: "bool", Default: "true", Description: "Show uncommitted lines added and removed, e.g. \"+312 −88\""},
+			{Name: "show_last_commit", Type: "bool", Default: "false", Description: "Show the last commit's subject and age, e.g. \"last: fix parser (2h ago)\""},
+			{Name: "show_author", Type: "bool", Default: "false", Description: "Add the last commit's author"},
+			{Name: "subject_length", Type: "int", Default: "30", Description: "Shorten the last commit's subject to this many characters"},
+			{Name: "checkpoint", Type: "bool", Default: "false", Description: "Turn the section red when a lot of work has gone uncommitted for a while"},
+			{Name: "checkpoint_lines", Type: "int", Default: "300", Description: "Changed lines that call for a commit (0 ignores lines)"},
+			{Name: "checkpoint_files", Type: "int", Default: "10", Description: "Changed files that call for a commit (0 ignores files)"},
+			{Name: "checkpoint_after_ms", Type: "duration_ms", Default: "900000", Description: "How long changes may stay over a threshold before the reminder"},
+			{Name: "checkpoint_notify", Type: "bool", Default: "false", Description: "Also send a desktop notification, once per stretch of changes"},
+		},
+	})
 }
 
 // Render returns the status section output
 func (s *StatusSection) Render() string {
+	return styled.Render(styled.ANSI{}, s.RenderStyled())
+}
+
+// RenderStyled returns the status section output as styled text, in red
+// while a commit checkpoint is due
+func (s *StatusSection) RenderStyled() styled.Text {
+	if s.repoPath == "" {
+		s.repoPath = s.getRepoPath()
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 	defer cancel()
 
-	status, err := s.detector.Detect(ctx)
+	opts := s.GetConfig().SectionOptions(s.Name())
+	detector := s.Providers().Git(s.repoPath)
+	detector.SetBaseBranch(opts.String("base_branch", git.AutoBaseBranch))
+	status, err := detector.Detect(ctx)
 	if err != nil || status == nil {
-		return "[Status: not a git repo]"
+		if ctx.Err() != nil {
+			s.MarkDegraded("git timed out")
+		} else {
+			s.MarkUnavailable("not a git repository")
+		}
+		return styled.Plain("[Status: not a git repo]")
+	}
+	s.MarkHealthy()
+
+	if !opts.Bool("show_diff", true) {
+		shown := *status
+		shown.Insertions, shown.Deletions = 0, 0
+		status = &shown
+	}
+	output := s.linkStyled(s.branchURL(ctx, detector, status.Branch), status.FormatStatusStyled())
+	if opts.Bool("checkpoint", false) && s.checkpointDue(opts) {
+		// Text in a color of its own, such as the lines added, keeps it
+		var reminder styled.Text
+		for _, span := range output {
+			if span.Color == styled.NoColor {
+				span.Color = styled.Error
+			}
+			reminder = reminder.Add(span.Text, span.Style)
+		}
+		output = reminder.Add(" ⚑", styled.Critical)
+	}
+	if opts.Bool("show_last_commit", false) && !status.LastCommit.Time.IsZero() {
+		author := ""
+		if opts.Bool("show_author", false) {
+			author = status.LastCommit.Author
+		}
+		output = output.Add(" ", styled.None).Join(formatLastCommit(status.LastCommit, author, opts.Int("subject_length", 30), time.Now()))
+	}
+	return output
+}
+
+// branchURL returns the web page of the branch on the origin remote's forge,
+// or "" when hyperlinks are off or there is no such page
+func (s *StatusSection) branchURL(ctx context.Context, detector *git.Detector, branch string) string {
+	if !s.GetConfig().Hyperlinks {
+		return ""
+	}
+	remoteURL, err := detector.RemoteURL(ctx, "origin")
+	if err != nil {
+		return ""
+	}
+	host, owner, repo, ok := ci.ParseRemote(remoteURL)
+	if !ok {
+		return ""
+	}
+	if branch == "" || strings.HasPrefix(branch, "(") {
+		return ci.RepoURL(host, owner, repo) // Detached HEAD
 	}
+	return ci.RepoURL(host, owner, repo) + "/tree/" + branch
+}
+
+// checkpointDue reports whether uncommitted changes have been over the
+// checkpoint thresholds for long enough to recommend a commit
+func (s *StatusSection) checkpointDue(opts config.SectionOptions) bool {
+	readDiffStat := s.diffStat
+	if readDiffStat == nil {
+		readDiffStat = func() (git.DiffStat, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			return s.Providers().Git(s.repoPath).DiffStat(ctx)
+		}
+	}
+	stat, err := readDiffStat()
+	if err != nil {
+		return false // e.g. no commits yet, or git timed out; try again next render
+	}
+
+	lines, files := opts.Int("checkpoint_lines", 300), opts.Int("checkpoint_files", 10)
+	over := (lines > 0 && stat.Lines() >= lines) || (files > 0 && stat.Files >= files)
+	tracker := s.Providers().Checkpoints()
+	overFor := tracker.Observe(s.repoPath, over, time.Now())
+	if !over || overFor < opts.Duration("checkpoint_after_ms", 15*time.Minute) {
+		return false
+	}
+
+	if opts.Bool("checkpoint_notify", false) && tracker.Notify(s.repoPath) {
+		message := fmt.Sprintf("%d lines in %d files uncommitted for %s", stat.Lines(), stat.Files, humanize.Duration(overFor, humanize.Short))
+		notify := s.notify
+		if notify == nil {
+			notify = sendNotification
+		}
+		notify("Commit checkpoint recommended", message)
+	}
+	return true
+}
 
-	return status.FormatStatus()
+// sendNotification shows a desktop notification, bounded so it cannot stall rendering
+func sendNotification(title, message string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if err := system.Notify(ctx, title, message); err != nil {
+		errors.Debug("status", "notification failed: %v", err)
+	}
+}
+
+// formatLastCommit renders a commit as "last: fix parser (2h ago)", adding
+// the author when given, dimmed so it reads as background information
+func formatLastCommit(commit git.Commit, author string, subjectLength int, now time.Time) styled.Text {
+	text := i18n.T("commit.last", truncateTitle(commit.Subject, subjectLength))
+	if author != "" {
+		text += " " + i18n.T("commit.by", author)
+	}
+	return styled.Text(nil).Add(text+" ("+humanize.Since(commit.Time, now)+")", styled.Dimmed)
 }
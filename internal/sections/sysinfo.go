@@ -1,17 +1,21 @@
 package sections
 
 import (
-	"strings"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/ll931217/claude-hud-enhanced/internal/config"
 	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/styled"
 	"github.com/ll931217/claude-hud-enhanced/internal/system"
 )
 
-// SysInfoSection displays system resource usage (CPU, RAM, Disk)
+// SysInfoSection displays system resource usage (CPU, RAM, Disk, network)
 type SysInfoSection struct {
 	*BaseSection
-	monitor *system.Monitor
 }
 
 // NewSysInfoSection creates a new sysinfo section (factory function for registry)
@@ -23,49 +27,154 @@ func NewSysInfoSection(cfg interface{}) (registry.Section, error) {
 
 	base := NewBaseSection("sysinfo", appConfig)
 	base.SetPriority(registry.PriorityImportant) // Show on medium+ terminals (80+ cols)
+	base.SetCacheTTL(5 * time.Second)            // Matches the system monitor's own cache
 
 	return &SysInfoSection{
 		BaseSection: base,
-		monitor:     system.NewMonitor(),
 	}, nil
 }
 
 // Render returns the sysinfo section output
 func (s *SysInfoSection) Render() string {
+	return styled.Render(styled.ANSI{}, s.RenderStyled())
+}
+
+// RenderStyled returns the sysinfo section output as styled text, with
+// connectivity problems and readings over their thresholds colored
+func (s *SysInfoSection) RenderStyled() styled.Text {
+	monitor := s.Providers().System()
+
 	// Update system metrics
-	if err := s.monitor.Update(); err != nil {
-		return ""
+	if err := monitor.Update(); err != nil {
+		s.MarkDegraded(fmt.Sprintf("system metrics: %v", err))
+		return nil
+	}
+	s.MarkHealthy()
+
+	opts := s.GetConfig().SectionOptions(s.Name())
+	var output styled.Text
+	// add appends a part, its label plain and its value in the value's color
+	add := func(label, value string, color styled.Color) {
+		if len(output) > 0 {
+			output = output.Add(" · ", styled.None)
+		}
+		output = output.Add(label, styled.None).Signal(value, color)
 	}
 
-	var parts []string
+	// Connectivity flag first so it stands out; nothing is shown while online
+	if opts.Bool("check_connectivity", true) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		switch s.Providers().Connectivity().Status(ctx) {
+		case system.ConnectivityOffline:
+			output = output.Add("offline", styled.Critical)
+		case system.ConnectivityAPIUnreachable:
+			output = output.Add("API unreachable", styled.Caution)
+		}
+	}
 
 	// Add CPU usage
-	if cpu := s.monitor.FormatCPUDisplay(); cpu != "" {
-		parts = append(parts, cpu)
+	if cpu := monitor.FormatCPUDisplay(); cpu != "" {
+		if opts.Bool("show_per_core", false) {
+			if cores := monitor.FormatCPUCoresDisplay(); cores != "" {
+				cpu += " " + cores
+			}
+		}
+		add("", cpu, styled.NoColor)
+	}
+
+	// Add load averages
+	if opts.Bool("show_load", false) {
+		if load := monitor.FormatLoadDisplay(); load != "" {
+			add("", load, styled.NoColor)
+		}
+	}
+
+	// Add temperature, colored as it approaches throttling, and fan speed
+	if opts.Bool("show_temperature", false) {
+		if temp := monitor.FormatTemperatureDisplay(); temp != "" {
+			color := styled.NoColor
+			celsius := monitor.GetThermal().CPUTemp
+			switch {
+			case celsius >= opts.Float("temp_critical", 90):
+				color = styled.Error
+			case celsius >= opts.Float("temp_warning", 80):
+				color = styled.Warning
+			}
+			add("🌡 ", temp, color)
+			if fan := monitor.FormatFanDisplay(); fan != "" {
+				output = output.Add(" "+fan, styled.None)
+			}
+		}
 	}
 
 	// Add Memory usage
-	if mem := s.monitor.FormatMemoryDisplay(); mem != "" {
-		parts = append(parts, mem)
+	if mem := monitor.FormatMemoryDisplay(); mem != "" {
+		add("", mem, styled.NoColor)
 	}
 
-	// Add Disk usage
-	if disk := s.monitor.FormatDiskDisplay(); disk != "" {
-		parts = append(parts, disk)
+	// Add Disk usage of the partition the session works on
+	if disk := system.FormatDisk(monitor.Workspace(s.workingDir()).Disk); disk != "" {
+		add("", disk, styled.NoColor)
+	}
+
+	// Add project and ~/.claude directory sizes
+	if opts.Bool("show_project_size", false) {
+		if size, ok := s.dirSize(s.getRepoPath()); ok {
+			add("PROJ ", formatMemory(size), styled.NoColor)
+		}
+	}
+	if opts.Bool("show_claude_size", false) {
+		if home, err := os.UserHomeDir(); err == nil {
+			if size, ok := s.dirSize(filepath.Join(home, ".claude")); ok {
+				color := styled.NoColor
+				if size >= uint64(opts.Float("claude_size_warning_gb", 2)*(1<<30)) {
+					color = styled.Warning
+				}
+				add("~/.claude ", formatMemory(size), color)
+			}
+		}
 	}
 
 	// Add File Descriptor count
-	if fd := s.monitor.FormatFDDisplay(); fd != "" {
-		parts = append(parts, fd)
+	if fd := monitor.FormatFDDisplay(); fd != "" {
+		add("", fd, styled.NoColor)
 	}
 
-	if len(parts) == 0 {
-		return ""
+	// Add network throughput
+	if opts.Bool("show_network", false) {
+		if net := monitor.FormatNetworkDisplay(); net != "" {
+			add("", net, styled.NoColor)
+		}
 	}
 
-	return strings.Join(parts, " · ")
+	return output
 }
 
 func init() {
-	registry.Register("sysinfo", NewSysInfoSection)
+	registry.RegisterWithMetadata("sysinfo", NewSysInfoSection, registry.Metadata{
+		Description: "CPU, memory, disk and network usage, with an offline flag",
+		Priority:    registry.PriorityImportant,
+		Options: []registry.Option{
+			{Name: "show_per_core", Type: "bool", Default: "false", Description: "Show a usage bar per CPU core"},
+			{Name: "show_load", Type: "bool", Default: "false", Description: "Show 1, 5 and 15 minute load averages"},
+			{Name: "show_temperature", Type: "bool", Default: "false", Description: "Show CPU temperature and fan speed"},
+			{Name: "temp_warning", Type: "float", Default: "80", Description: "Show temperature in yellow at or above this many °C"},
+			{Name: "temp_critical", Type: "float", Default: "90", Description: "Show temperature in red at or above this many °C"},
+			{Name: "show_project_size", Type: "bool", Default: "false", Description: "Show the size of the project directory"},
+			{Name: "show_claude_size", Type: "bool", Default: "false", Description: "Show the size of ~/.claude (transcripts, caches)"},
+			{Name: "claude_size_warning_gb", Type: "float", Default: "2", Description: "Show ~/.claude in yellow at or above this size"},
+			{Name: "show_network", Type: "bool", Default: "false", Description: "Show network receive/transmit rates"},
+			{Name: "check_connectivity", Type: "bool", Default: "true", Description: "Flag when api.anthropic.com is unreachable (HEAD request, cached 30s)"},
+		},
+	})
+}
+
+// dirSize returns the size of dir; ok is false until a first scan has completed
+// Large trees are scanned over several renders; see system.DirSizer
+func (s *SysInfoSection) dirSize(dir string) (uint64, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	size, err := s.Providers().DirSizes().Size(ctx, dir)
+	return size, err == nil || size > 0
 }
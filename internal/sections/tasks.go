@@ -0,0 +1,85 @@
+package sections
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/tasks"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+)
+
+// defaultTaskFiles are where projects usually keep their checklists
+var defaultTaskFiles = []string{"TODO.md", "docs/tasks.md"}
+
+// TasksSection displays the progress of the workspace's markdown checklists
+type TasksSection struct {
+	*BaseSection
+	read func(dir string, paths []string) (tasks.List, bool, error) // Overrides the shared reader when set
+}
+
+// NewTasksSection creates a new tasks section (factory function for registry)
+func NewTasksSection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("tasks", appConfig)
+	base.SetPriority(registry.PriorityOptional) // Nice to have next to the current issue
+	base.SetMinWidth(9)                         // Minimum width for "tasks 3/8"
+	base.SetCacheTTL(time.Second)               // The reader rescans on file changes
+
+	return &TasksSection{
+		BaseSection: base,
+	}, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("tasks", NewTasksSection, registry.Metadata{
+		Description: "Completed checklist items and the next open one from markdown TODO files",
+		Priority:    registry.PriorityOptional,
+		Options: []registry.Option{
+			{Name: "paths", Type: "list", Default: "TODO.md, docs/tasks.md", Description: "Markdown files with \"- [ ]\" checklists, relative to the workspace; counts are combined"},
+			{Name: "show_next", Type: "bool", Default: "true", Description: "Show the first unchecked item"},
+			{Name: "item_length", Type: "int", Default: "30", Description: "Truncate the next item to this many characters"},
+		},
+	})
+}
+
+// Render returns the checklist progress, e.g. "tasks 3/8 • Write the migration guide"
+func (t *TasksSection) Render() string {
+	opts := t.GetConfig().SectionOptions(t.Name())
+	dir := t.workingDir()
+
+	read := t.read
+	if read == nil {
+		read = func(dir string, paths []string) (tasks.List, bool, error) {
+			return t.Providers().Tasks(dir, paths).Read()
+		}
+	}
+	list, found, err := read(dir, opts.Strings("paths", defaultTaskFiles))
+	switch {
+	case err != nil:
+		// Keep showing the last checklist while a file cannot be read
+		t.MarkDegraded(fmt.Sprintf("unreadable task file: %v", err))
+	case !found:
+		t.MarkUnavailable("no task files in the workspace")
+		return ""
+	default:
+		t.MarkHealthy()
+	}
+	if list.Total == 0 {
+		return ""
+	}
+
+	if list.Open() == 0 {
+		return fmt.Sprintf("tasks %d/%d %s✓%s", list.Done, list.Total, theme.Green, theme.Reset)
+	}
+	output := fmt.Sprintf("tasks %d/%d", list.Done, list.Total)
+	if opts.Bool("show_next", true) && list.Next != "" {
+		output += " • " + truncateTitle(list.Next, opts.Int("item_length", 30))
+	}
+	return output
+}
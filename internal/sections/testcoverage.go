@@ -52,14 +52,15 @@ func (t *TestCoverageSection) Render() string {
 	}
 	t.mu.RUnlock()
 
-	// Detect language
-	lang := system.DetectLanguage(".")
+	// Detect language in the session's directory
+	dir := t.workingDir()
+	lang := system.DetectLanguage(dir)
 	if lang == "" {
 		return "" // Hide section if language not detected
 	}
 
 	// Get coverage based on language
-	coverage := t.getCoverage(lang)
+	coverage := t.getCoverage(dir, lang)
 
 	// Update cache
 	t.mu.Lock()
@@ -71,17 +72,17 @@ func (t *TestCoverageSection) Render() string {
 }
 
 // getCoverage retrieves coverage for the detected language
-func (t *TestCoverageSection) getCoverage(lang string) string {
+func (t *TestCoverageSection) getCoverage(dir, lang string) string {
 	var coverage float64
 	var err error
 
 	switch lang {
 	case "Go":
-		coverage, err = t.getGoCoverage()
+		coverage, err = t.getGoCoverage(dir)
 	case "JavaScript", "TypeScript":
-		coverage, err = t.getJSCoverage()
+		coverage, err = t.getJSCoverage(dir)
 	case "Python":
-		coverage, err = t.getPythonCoverage()
+		coverage, err = t.getPythonCoverage(dir)
 	default:
 		return "" // Unsupported language
 	}
@@ -107,11 +108,12 @@ func (t *TestCoverageSection) getCoverage(lang string) string {
 }
 
 // getGoCoverage gets coverage for Go projects
-func (t *TestCoverageSection) getGoCoverage() (float64, error) {
+func (t *TestCoverageSection) getGoCoverage(dir string) (float64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, "go", "test", "-cover", "./...")
+	cmd.Dir = dir
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return 0, err
@@ -134,16 +136,18 @@ func (t *TestCoverageSection) getGoCoverage() (float64, error) {
 }
 
 // getJSCoverage gets coverage for JavaScript/TypeScript projects
-func (t *TestCoverageSection) getJSCoverage() (float64, error) {
+func (t *TestCoverageSection) getJSCoverage(dir string) (float64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	// Try Jest first (most common)
 	cmd := exec.CommandContext(ctx, "npm", "run", "test:coverage", "--", "--silent")
+	cmd.Dir = dir
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		// Try alternative command
 		cmd = exec.CommandContext(ctx, "npx", "jest", "--coverage", "--silent")
+		cmd.Dir = dir
 		output, err = cmd.CombinedOutput()
 		if err != nil {
 			return 0, err
@@ -170,12 +174,13 @@ func (t *TestCoverageSection) getJSCoverage() (float64, error) {
 }
 
 // getPythonCoverage gets coverage for Python projects
-func (t *TestCoverageSection) getPythonCoverage() (float64, error) {
+func (t *TestCoverageSection) getPythonCoverage(dir string) (float64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	// Try pytest with coverage
 	cmd := exec.CommandContext(ctx, "pytest", "--cov", "--cov-report=term", "-q")
+	cmd.Dir = dir
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return 0, err
@@ -201,5 +206,9 @@ func (t *TestCoverageSection) getPythonCoverage() (float64, error) {
 }
 
 func init() {
-	registry.Register("testcoverage", NewTestCoverageSection)
+	registry.RegisterWithMetadata("testcoverage", NewTestCoverageSection, registry.Metadata{
+		Description:  "Test coverage of the project",
+		Priority:     registry.PriorityImportant,
+		Dependencies: []registry.Dependency{depSource},
+	})
 }
@@ -0,0 +1,84 @@
+package sections
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/testresults"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+)
+
+// defaultTestResultGlobs are where test runners are usually told to write results
+var defaultTestResultGlobs = []string{"test-results.json", "test-results/*.json", "test-results/*.xml", "junit*.xml"}
+
+// TestsSection displays the outcome of the last test run
+type TestsSection struct {
+	*BaseSection
+	latest func(dir string, patterns []string) (testresults.Summary, bool, error) // Overrides the shared reader when set
+}
+
+// NewTestsSection creates a new test results section (factory function for registry)
+func NewTestsSection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("tests", appConfig)
+	base.SetPriority(registry.PriorityImportant) // Failing tests matter while working
+	base.SetMinWidth(10)                         // Minimum width for "tests 12✓"
+	base.SetCacheTTL(time.Second)                // The reader rescans on file changes
+
+	return &TestsSection{
+		BaseSection: base,
+	}, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("tests", NewTestsSection, registry.Metadata{
+		Description: "Passed and failed tests from the last go test -json or JUnit XML results file",
+		Priority:    registry.PriorityImportant,
+		Options: []registry.Option{
+			{Name: "paths", Type: "list", Default: "test-results.json, test-results/*.json, test-results/*.xml, junit*.xml", Description: "Result file globs, relative to the workspace; the newest match is shown"},
+			{Name: "show_skipped", Type: "bool", Default: "false", Description: "Also count skipped tests"},
+			{Name: "max_age_ms", Type: "duration_ms", Default: "0", Description: "Hide results older than this (0 keeps them)"},
+		},
+	})
+}
+
+// Render returns the last run's results, e.g. "tests 128✓ 2✗"
+func (t *TestsSection) Render() string {
+	opts := t.GetConfig().SectionOptions(t.Name())
+	dir := t.workingDir()
+
+	readLatest := t.latest
+	if readLatest == nil {
+		readLatest = func(dir string, patterns []string) (testresults.Summary, bool, error) {
+			return t.Providers().TestResults(dir, patterns).Latest()
+		}
+	}
+	summary, found, err := readLatest(dir, opts.Strings("paths", defaultTestResultGlobs))
+	if err != nil {
+		// Keep showing the last complete results while a runner writes the file
+		t.MarkDegraded(fmt.Sprintf("unreadable test results: %v", err))
+	} else {
+		t.MarkHealthy()
+	}
+	if !found || summary.Total() == 0 {
+		return ""
+	}
+	if maxAge := opts.Duration("max_age_ms", 0); maxAge > 0 && time.Since(summary.ModTime) > maxAge {
+		return ""
+	}
+
+	output := fmt.Sprintf("tests %d✓", summary.Passed)
+	if summary.Failed > 0 {
+		output += fmt.Sprintf(" %s%d✗%s", theme.Red, summary.Failed, theme.Reset)
+	}
+	if summary.Skipped > 0 && opts.Bool("show_skipped", false) {
+		output += fmt.Sprintf(" %s%d⊘%s", theme.Dim, summary.Skipped, theme.Reset)
+	}
+	return output
+}
@@ -0,0 +1,108 @@
+package sections
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/humanize"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+)
+
+// TimerSection displays the time left on the focus timer started with `claude-hud timer start`
+type TimerSection struct {
+	*BaseSection
+	readTimer func() (*session.Timer, error) // Overrides the shared state file when set
+}
+
+// NewTimerSection creates a new timer section (factory function for registry)
+func NewTimerSection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("timer", appConfig)
+	base.SetPriority(registry.PriorityImportant) // Time boxes are easy to miss when hidden
+	base.SetMinWidth(6)                          // Minimum width for "⏱ 18m"
+	base.SetCacheTTL(time.Second)                // Counts down
+
+	return &TimerSection{
+		BaseSection: base,
+	}, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("timer", NewTimerSection, registry.Metadata{
+		Description: "Focus timer set with `claude-hud timer start 25m`",
+		Priority:    registry.PriorityImportant,
+		Options: []registry.Option{
+			{Name: "show_label", Type: "bool", Default: "true", Description: "Show the label the timer was started with"},
+			{Name: "expired_ms", Type: "duration_ms", Default: "300000", Description: "How long to keep showing \"time's up\" after the timer ends"},
+		},
+	})
+}
+
+// Render returns the timer output, e.g. "⏱ review 18m" or "⏱ time's up"
+func (t *TimerSection) Render() string {
+	readTimer := t.readTimer
+	if readTimer == nil {
+		readTimer = loadTimer
+	}
+	timer, err := readTimer()
+	if err != nil {
+		t.MarkDegraded(fmt.Sprintf("state file unreadable: %v", err))
+		return ""
+	}
+	t.MarkHealthy()
+	if timer == nil {
+		return ""
+	}
+
+	opts := t.GetConfig().SectionOptions(t.Name())
+	output := "⏱ "
+	if label := timer.Label; label != "" && opts.Bool("show_label", true) {
+		output += label + " "
+	}
+
+	now := time.Now()
+	if timer.Expired(now) {
+		if now.Sub(timer.EndsAt) >= opts.Duration("expired_ms", 5*time.Minute) {
+			return ""
+		}
+		return output + theme.Red + "time's up" + theme.Reset
+	}
+
+	left := formatTimerLeft(timer.Remaining(now))
+	if timer.Remaining(now) <= timer.Duration()/10 {
+		left = theme.Signal(theme.Yellow, left) // Last tenth of the time box
+	}
+	return output + left
+}
+
+// loadTimer reads the timer from the shared state file
+func loadTimer() (*session.Timer, error) {
+	store, err := session.DefaultStore()
+	if err != nil {
+		return nil, err
+	}
+	state, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+	return state.Timer, nil
+}
+
+// formatTimerLeft formats the time left as "1h05m", "18m" or "45s"
+func formatTimerLeft(d time.Duration) string {
+	if d < time.Minute {
+		return humanize.Duration(d, humanize.Elapsed)
+	}
+	// Round up so a fresh 25m timer reads 25m rather than 24m
+	if rest := d % time.Minute; rest > 0 {
+		d += time.Minute - rest
+	}
+	return humanize.Duration(d, humanize.Short)
+}
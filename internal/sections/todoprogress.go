@@ -8,13 +8,11 @@ import (
 
 	"github.com/ll931217/claude-hud-enhanced/internal/config"
 	"github.com/ll931217/claude-hud-enhanced/internal/registry"
-	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
 )
 
 // TodoProgressSection displays todo list progress from TodoWrite
 type TodoProgressSection struct {
 	*BaseSection
-	parser *transcript.Parser
 }
 
 // NewTodoProgressSection creates a new todo progress section (factory function for registry)
@@ -24,37 +22,36 @@ func NewTodoProgressSection(cfg interface{}) (registry.Section, error) {
 		appConfig = config.DefaultConfig()
 	}
 
-	// Get transcript path from environment or use default
-	transcriptPath := getTranscriptPath()
-
 	base := NewBaseSection("todoprogress", appConfig)
 	base.SetPriority(registry.PriorityEssential) // Show current task progress
 	base.SetMinWidth(20)                         // Minimum width for progress display
 
 	return &TodoProgressSection{
 		BaseSection: base,
-		parser:      transcript.NewParser(transcriptPath),
 	}, nil
 }
 
 // Render returns the todo progress section output
 func (t *TodoProgressSection) Render() string {
 	// Get transcript path dynamically from global context
-	transcriptPath := getTranscriptPath()
+	transcriptPath := t.getTranscriptPath()
 	if transcriptPath == "" {
+		t.MarkUnavailable("no transcript")
 		return "" // Hide section if no transcript path
 	}
 
-	// Create a parser for the current transcript path
-	parser := transcript.NewParser(transcriptPath)
+	// Use the shared parser for the current transcript path
+	parser := t.Providers().Transcript(transcriptPath)
 
 	// Parse transcript for todo data
 	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 	defer cancel()
 
 	if err := parser.Parse(ctx); err != nil {
+		t.MarkDegraded(fmt.Sprintf("transcript unreadable: %v", err))
 		return "" // Hide section on parse error
 	}
+	t.MarkHealthy()
 
 	// Get todo counts
 	total, completed := parser.GetTodoCount()
@@ -92,5 +89,9 @@ func truncateTaskName(task string, maxLen int) string {
 }
 
 func init() {
-	registry.Register("todoprogress", NewTodoProgressSection)
+	registry.RegisterWithMetadata("todoprogress", NewTodoProgressSection, registry.Metadata{
+		Description:  "Progress of the current todo list",
+		Priority:     registry.PriorityEssential,
+		Dependencies: []registry.Dependency{depTranscript},
+	})
 }
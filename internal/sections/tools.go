@@ -8,13 +8,11 @@ import (
 
 	"github.com/ll931217/claude-hud-enhanced/internal/config"
 	"github.com/ll931217/claude-hud-enhanced/internal/registry"
-	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
 )
 
 // ToolsSection displays tool activity with recency tracking
 type ToolsSection struct {
 	*BaseSection
-	parser *transcript.Parser
 }
 
 // NewToolsSection creates a new tools section (factory function for registry)
@@ -24,67 +22,82 @@ func NewToolsSection(cfg interface{}) (registry.Section, error) {
 		appConfig = config.DefaultConfig()
 	}
 
-	// Get transcript path from environment or use default
-	transcriptPath := getTranscriptPath()
-
 	base := NewBaseSection("tools", appConfig)
 	base.SetPriority(registry.PriorityEssential) // Show on all terminals
 
 	return &ToolsSection{
 		BaseSection: base,
-		parser:      transcript.NewParser(transcriptPath),
 	}, nil
 }
 
+// spinnerFrames mark running tools, turning once per cycle of frames
+var spinnerFrames = []string{"◐", "◓", "◑", "◒"}
+
 // Render returns the tools section output
 func (t *ToolsSection) Render() string {
+	return registry.FrameAt(t.RenderFrames(), time.Now())
+}
+
+// RenderFrames implements registry.Animated: running tools spin unless
+// animations are off
+func (t *ToolsSection) RenderFrames() []string {
 	// Get transcript path dynamically from global context
-	transcriptPath := getTranscriptPath()
+	transcriptPath := t.getTranscriptPath()
 	if transcriptPath == "" {
-		return "" // Hide section if no transcript path
+		t.MarkUnavailable("no transcript")
+		return nil // Hide section if no transcript path
 	}
 
-	// Create a parser for the current transcript path
+	// Use the shared parser for the current transcript path
 	// (path may change between renders)
-	parser := transcript.NewParser(transcriptPath)
+	parser := t.Providers().Transcript(transcriptPath)
 
 	// Parse transcript for tool data
 	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 	defer cancel()
 
 	if err := parser.Parse(ctx); err != nil {
-		return "" // Hide section on parse error
+		t.MarkDegraded(fmt.Sprintf("transcript unreadable: %v", err))
+		return nil // Hide section on parse error
 	}
+	t.MarkHealthy()
 
 	// Get running and completed tools
 	running, completed := parser.GetToolsByStatus(2, 4)
 	if len(running) == 0 && len(completed) == 0 {
-		return "" // Hide section when no tools used yet
-	}
-
-	var parts []string
-
-	// Display running tools first (max 2) with ◐ indicator
-	for _, tool := range running {
-		name := shortenToolName(tool.Name)
-		if tool.Target != "" {
-			parts = append(parts, fmt.Sprintf("◐ %s: %s", name, tool.Target))
-		} else {
-			parts = append(parts, fmt.Sprintf("◐ %s", name))
-		}
+		return nil // Hide section when no tools used yet
 	}
 
 	// Display completed tools (max 4) with ✓ and count
+	var done []string
 	for _, tool := range completed {
 		name := shortenToolName(tool.Name)
 		if tool.Count > 1 {
-			parts = append(parts, fmt.Sprintf("✓ %s×%d", name, tool.Count))
+			done = append(done, fmt.Sprintf("✓ %s×%d", name, tool.Count))
 		} else {
-			parts = append(parts, fmt.Sprintf("✓ %s", name))
+			done = append(done, fmt.Sprintf("✓ %s", name))
 		}
 	}
 
-	return strings.Join(parts, " | ")
+	spinner := spinnerFrames
+	if len(running) == 0 || !t.GetConfig().Animate {
+		spinner = spinnerFrames[:1]
+	}
+	frames := make([]string, len(spinner))
+	for i, icon := range spinner {
+		// Display running tools first (max 2) with the spinner
+		var parts []string
+		for _, tool := range running {
+			name := shortenToolName(tool.Name)
+			if tool.Target != "" {
+				parts = append(parts, fmt.Sprintf("%s %s: %s", icon, name, tool.Target))
+			} else {
+				parts = append(parts, fmt.Sprintf("%s %s", icon, name))
+			}
+		}
+		frames[i] = strings.Join(append(parts, done...), " | ")
+	}
+	return frames
 }
 
 // mapToOfficialToolName converts internal tool names to official display names
@@ -184,5 +197,9 @@ func shortenToolName(name string) string {
 }
 
 func init() {
-	registry.Register("tools", NewToolsSection)
+	registry.RegisterWithMetadata("tools", NewToolsSection, registry.Metadata{
+		Description:  "Recently used tools and call counts",
+		Priority:     registry.PriorityEssential,
+		Dependencies: []registry.Dependency{depTranscript},
+	})
 }
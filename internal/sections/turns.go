@@ -0,0 +1,103 @@
+package sections
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/humanize"
+	"github.com/ll931217/claude-hud-enhanced/internal/i18n"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+// TurnsSection displays conversation turn counts and sizes
+type TurnsSection struct {
+	*BaseSection
+	turnStats func() (transcript.TurnStats, error) // Overrides the transcript when set
+}
+
+// NewTurnsSection creates a new turns section (factory function for registry)
+func NewTurnsSection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("turns", appConfig)
+	base.SetPriority(registry.PriorityOptional) // Nice to have; hide first on narrow terminals
+	base.SetMinWidth(10)                        // Minimum width for "Turns 12/11"
+
+	return &TurnsSection{
+		BaseSection: base,
+	}, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("turns", NewTurnsSection, registry.Metadata{
+		Description: "User and assistant turns, average tokens per turn, the longest turn and interruptions",
+		Priority:    registry.PriorityOptional,
+		Options: []registry.Option{
+			{Name: "show_average", Type: "bool", Default: "true", Description: "Show average tokens per assistant turn"},
+			{Name: "show_longest", Type: "bool", Default: "true", Description: "Show how long the longest turn took"},
+		},
+		Dependencies: []registry.Dependency{depTranscript},
+	})
+}
+
+// Render returns the turns section output, e.g. "Turns 12/11 avg 8k longest 4m interrupted ×2"
+func (t *TurnsSection) Render() string {
+	turnStats := t.turnStats
+	if turnStats == nil {
+		turnStats = t.transcriptTurns
+	}
+	stats, err := turnStats()
+	if err != nil {
+		t.MarkDegraded(fmt.Sprintf("transcript unreadable: %v", err))
+		return ""
+	}
+	t.MarkHealthy()
+
+	if stats.UserTurns == 0 && stats.AssistantTurns == 0 {
+		return ""
+	}
+
+	opts := t.GetConfig().SectionOptions(t.Name())
+	output := fmt.Sprintf("Turns %d/%d", stats.UserTurns, stats.AssistantTurns)
+	if opts.Bool("show_average", true) && stats.AssistantTurns > 0 {
+		output += " avg " + humanize.Count(stats.AverageTokensPerTurn())
+	}
+	if opts.Bool("show_longest", true) && stats.LongestTurn > 0 {
+		output += " " + i18n.T("turns.longest", formatTurnDuration(stats.LongestTurn))
+	}
+	if stats.Interrupted > 0 {
+		output += " " + theme.Yellow + fmt.Sprintf("interrupted ×%d", stats.Interrupted) + theme.Reset
+	}
+	return output
+}
+
+// transcriptTurns parses the current transcript for its turn statistics
+func (t *TurnsSection) transcriptTurns() (transcript.TurnStats, error) {
+	transcriptPath := t.getTranscriptPath()
+	if transcriptPath == "" {
+		return transcript.TurnStats{}, nil
+	}
+
+	parser := t.Providers().Transcript(transcriptPath)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := parser.Parse(ctx); err != nil {
+		return transcript.TurnStats{}, err
+	}
+	return parser.GetTurnStats(), nil
+}
+
+// formatTurnDuration formats a turn duration as "45s", "4m" or "1h05m"
+func formatTurnDuration(d time.Duration) string {
+	if d < time.Minute {
+		return humanize.Duration(d, humanize.Elapsed)
+	}
+	return humanize.Duration(d, humanize.Short)
+}
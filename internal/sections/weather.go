@@ -0,0 +1,141 @@
+package sections
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/weather"
+)
+
+// minWeatherInterval keeps the weather services' free tiers happy
+const minWeatherInterval = 10 * time.Minute
+
+// maxWeatherTimeout bounds how long a render may wait for the weather
+const maxWeatherTimeout = 2 * time.Second
+
+// WeatherSection displays the current weather from wttr.in or OpenWeather
+type WeatherSection struct {
+	*BaseSection
+	report func(provider weather.Provider, location string, interval, timeout time.Duration) (weather.Report, bool, error) // Overrides the shared client when set
+}
+
+// NewWeatherSection creates a new weather section (factory function for registry)
+func NewWeatherSection(cfg interface{}) (registry.Section, error) {
+	appConfig, ok := cfg.(*config.Config)
+	if !ok {
+		appConfig = config.DefaultConfig()
+	}
+
+	base := NewBaseSection("weather", appConfig)
+	base.SetPriority(registry.PriorityOptional) // Nice to have; hide first on narrow terminals
+	base.SetMinWidth(6)                         // Minimum width for "☀ 12°C"
+	base.SetCacheTTL(time.Minute)               // The client caches reports for longer
+
+	return &WeatherSection{
+		BaseSection: base,
+	}, nil
+}
+
+func init() {
+	registry.RegisterWithMetadata("weather", NewWeatherSection, registry.Metadata{
+		Description: "Current weather from wttr.in or OpenWeather",
+		Priority:    registry.PriorityOptional,
+		Options: []registry.Option{
+			{Name: "provider", Type: "string", Default: "wttr", Description: "wttr (no key needed) or openweather"},
+			{Name: "location", Type: "string", Default: "", Description: "City, e.g. \"London\" or \"Berlin,DE\"; wttr guesses from your IP when empty"},
+			{Name: "api_key", Type: "string", Default: "", Description: "OpenWeather API key (default: $OPENWEATHER_API_KEY)"},
+			{Name: "units", Type: "string", Default: "metric", Description: "metric (°C) or imperial (°F)"},
+			{Name: "show_condition", Type: "bool", Default: "false", Description: "Describe the weather next to the icon"},
+			{Name: "interval_ms", Type: "duration_ms", Default: "1800000", Description: "How long a report is reused (minimum 600000)"},
+			{Name: "timeout_ms", Type: "duration_ms", Default: "1000", Description: "How long a render may wait for the service (maximum 2000)"},
+		},
+	})
+}
+
+// Render returns the weather, e.g. "🌧 12°C" or "🌧 12°C light drizzle"
+func (w *WeatherSection) Render() string {
+	opts := w.GetConfig().SectionOptions(w.Name())
+
+	var provider weather.Provider
+	switch name := opts.String("provider", "wttr"); name {
+	case "wttr":
+		provider = weather.Wttr{}
+	case "openweather":
+		key := opts.String("api_key", "")
+		if key == "" {
+			key = os.Getenv("OPENWEATHER_API_KEY")
+		}
+		if key == "" {
+			w.MarkUnavailable("no OpenWeather API key; set api_key or $OPENWEATHER_API_KEY")
+			return ""
+		}
+		provider = weather.OpenWeather{APIKey: key}
+	default:
+		w.MarkDegraded(fmt.Sprintf("unknown weather provider %q", name))
+		return ""
+	}
+
+	interval := max(opts.Duration("interval_ms", weather.DefaultInterval), minWeatherInterval)
+	timeout := min(opts.Duration("timeout_ms", time.Second), maxWeatherTimeout)
+	readReport := w.report
+	if readReport == nil {
+		readReport = w.fetchReport
+	}
+	report, found, err := readReport(provider, opts.String("location", ""), interval, timeout)
+	if err != nil {
+		// Keep showing the last report while the service fails
+		w.MarkDegraded(fmt.Sprintf("%s: %v", provider.Name(), err))
+	} else {
+		w.MarkHealthy()
+	}
+	if !found {
+		return ""
+	}
+
+	output := weatherIcon(report.Condition) + " " + formatTemperature(report.TempC, opts.String("units", "metric"))
+	if opts.Bool("show_condition", false) && report.Description != "" {
+		output += " " + report.Description
+	}
+	return output
+}
+
+// fetchReport reads the weather through the shared client
+func (w *WeatherSection) fetchReport(provider weather.Provider, location string, interval, timeout time.Duration) (weather.Report, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return w.Providers().Weather().Report(ctx, provider, location, interval)
+}
+
+// formatTemperature formats a Celsius temperature in the configured units
+func formatTemperature(celsius float64, units string) string {
+	if units == "imperial" {
+		return fmt.Sprintf("%d°F", int(math.Round(celsius*9/5+32)))
+	}
+	return fmt.Sprintf("%d°C", int(math.Round(celsius)))
+}
+
+// weatherIcon returns the icon for a condition
+func weatherIcon(condition string) string {
+	switch condition {
+	case weather.ConditionClear:
+		return "☀"
+	case weather.ConditionPartlyCloudy:
+		return "⛅"
+	case weather.ConditionCloudy:
+		return "☁"
+	case weather.ConditionFog:
+		return "🌫"
+	case weather.ConditionRain:
+		return "🌧"
+	case weather.ConditionSnow:
+		return "❄"
+	case weather.ConditionThunder:
+		return "⛈"
+	}
+	return "🌡"
+}
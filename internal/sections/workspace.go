@@ -1,17 +1,27 @@
 package sections
 
 import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/git"
+	"github.com/ll931217/claude-hud-enhanced/internal/i18n"
 	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/styled"
 	"github.com/ll931217/claude-hud-enhanced/internal/system"
 )
 
 // WorkspaceSection displays workspace information
 type WorkspaceSection struct {
 	*BaseSection
-	monitor *system.Monitor
+	repoPath  string                              // Resolved on first verbose render
+	repoStats func() (git.RepoStats, bool, error) // Overrides the shared cache when set
+	host      func() (name string, ssh bool)      // Overrides the hostname and SSH detection when set
 }
 
 // NewWorkspaceSection creates a new workspace section (factory function for registry)
@@ -23,38 +33,168 @@ func NewWorkspaceSection(cfg interface{}) (registry.Section, error) {
 
 	return &WorkspaceSection{
 		BaseSection: NewBaseSection("workspace", appConfig),
-		monitor:     system.NewMonitor(),
 	}, nil
 }
 
 func init() {
-	registry.Register("workspace", NewWorkspaceSection)
+	registry.RegisterWithMetadata("workspace", NewWorkspaceSection, registry.Metadata{
+		Description:  "Project language and current directory",
+		Priority:     registry.PriorityImportant,
+		Dependencies: []registry.Dependency{depSource},
+		Options: []registry.Option{
+			{Name: "verbose", Type: "bool", Default: "false", Description: "Also show the number and total size of tracked files"},
+			{Name: "show_host", Type: "bool", Default: "true", Description: "Show @hostname in SSH sessions, or on any host other than home_host"},
+			{Name: "home_host", Type: "string", Default: "", Description: "Your own machine's hostname; any other host is shown as remote"},
+		},
+	})
 }
 
 // Render returns the workspace section output
 func (w *WorkspaceSection) Render() string {
-	// Update monitor for language detection and directory
-	if err := w.monitor.Update(); err != nil {
-		return "[Workspace: unavailable]"
+	return styled.Render(styled.ANSI{}, w.RenderStyled())
+}
+
+// RenderStyled returns the workspace section output as styled text, with a
+// remote host in bold purple
+func (w *WorkspaceSection) RenderStyled() styled.Text {
+	// Language and directory come from the session's working directory, not
+	// the process's, which stays wherever claude-hud was started
+	info := w.Providers().System().Workspace(w.workingDir())
+	if info.Dir == "" {
+		w.MarkUnavailable("no working directory")
+		return styled.Plain(i18n.T("workspace.unavailable"))
+	}
+	w.MarkHealthy()
+
+	var output styled.Text
+	add := func(part string, style styled.Style) {
+		if len(output) > 0 {
+			output = output.Add(" | ", styled.None)
+		}
+		output = output.Add(part, style)
 	}
+	opts := w.GetConfig().SectionOptions(w.Name())
 
-	var parts []string
+	// A remote host first, so remote and local sessions are not confused
+	if opts.Bool("show_host", true) {
+		host := w.host
+		if host == nil {
+			host = systemHost
+		}
+		name, ssh := host()
+		if label := remoteHostLabel(name, ssh, opts.String("home_host", "")); label != "" {
+			add(label, styled.Style{Color: styled.Secondary, Bold: true})
+		}
+	}
 
-	// Language first (with icon)
-	if lang := w.monitor.FormatLanguageDisplay(); lang != "" {
-		parts = append(parts, lang)
+	// Then the language (with icon)
+	if lang := system.FormatLanguage(info.Language); lang != "" {
+		add(lang, styled.None)
 	}
 
-	// Then directory
-	if dir := w.monitor.FormatDirDisplay(); dir != "" {
-		parts = append(parts, dir)
+	// Then directory, relative to the project once the session leaves its root
+	if dir := formatWorkspaceDir(w.Providers().ProjectDir(), info.Dir, system.FormatDir(info.Dir)); dir != "" {
+		add(dir, styled.None)
 	}
 
 	// Note: System metrics (CPU, RAM, Disk) are now in sysinfo section
 
-	if len(parts) == 0 {
-		return "[Workspace: waiting for data]"
+	if opts.Bool("verbose", false) {
+		if stats := w.formatRepoStats(); stats != "" {
+			add(stats, styled.None)
+		}
+	}
+
+	if len(output) == 0 {
+		return styled.Plain(i18n.T("workspace.waiting"))
+	}
+	return output
+}
+
+// systemHost returns this machine's hostname and whether it was reached over SSH
+func systemHost() (string, bool) {
+	return system.Hostname(), system.InSSHSession()
+}
+
+// remoteHostLabel returns "@hostname" for a host reached over SSH, or one
+// other than home when it is set, e.g. "@devbox"; "" for the home machine
+func remoteHostLabel(name string, ssh bool, home string) string {
+	if name == "" {
+		return ""
 	}
+	if ssh || (home != "" && !strings.EqualFold(name, system.ShortHostname(home))) {
+		return "@" + name
+	}
+	return ""
+}
 
-	return strings.Join(parts, " | ")
+// formatWorkspaceDir returns display, the formatted current directory, unless
+// current is below the project directory, e.g. "app ▸ web/src"
+func formatWorkspaceDir(project, current, display string) string {
+	if project == "" || current == "" {
+		return display
+	}
+	rel, err := filepath.Rel(filepath.Clean(project), filepath.Clean(current))
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return display
+	}
+	return filepath.Base(filepath.Clean(project)) + " ▸ " + filepath.ToSlash(rel)
+}
+
+// formatRepoStats returns the tracked file count and size, e.g. "1,234 files 56MB"
+// Counting a large tree finishes in the background; until then the previous
+// commit's numbers, or nothing, are shown
+func (w *WorkspaceSection) formatRepoStats() string {
+	readStats := w.repoStats
+	if readStats == nil {
+		readStats = w.readRepoStats
+	}
+	stats, ok, err := readStats()
+	if err != nil || !ok {
+		return ""
+	}
+	files := "files"
+	if stats.Files == 1 {
+		files = "file"
+	}
+	return fmt.Sprintf("%s %s %s", formatThousands(stats.Files), files, formatSize(stats.Bytes))
+}
+
+// readRepoStats asks the shared cache, waiting briefly for a count to finish
+func (w *WorkspaceSection) readRepoStats() (git.RepoStats, bool, error) {
+	if w.repoPath == "" {
+		w.repoPath = w.getRepoPath()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	return w.Providers().RepoStats().Stats(ctx, w.repoPath)
+}
+
+// formatThousands groups digits in threes, e.g. 1234567 as "1,234,567"
+func formatThousands(n int) string {
+	if n < 0 {
+		return "-" + formatThousands(-n)
+	}
+	digits := strconv.Itoa(n)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
+}
+
+// formatSize renders a byte count in the largest fitting unit, e.g. "820KB" or "1.2GB"
+func formatSize(bytes int64) string {
+	switch {
+	case bytes >= 1<<30:
+		return fmt.Sprintf("%.1fGB", float64(bytes)/(1<<30))
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%dMB", bytes>>20)
+	case bytes >= 1<<10:
+		return fmt.Sprintf("%dKB", bytes>>10)
+	}
+	return fmt.Sprintf("%dB", bytes)
 }
@@ -1,13 +1,15 @@
 package sections
 
 import (
+	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/humanize"
+	"github.com/ll931217/claude-hud-enhanced/internal/i18n"
 	"github.com/ll931217/claude-hud-enhanced/internal/registry"
-	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+	"github.com/ll931217/claude-hud-enhanced/internal/styled"
 	"github.com/ll931217/claude-hud-enhanced/internal/zai"
 )
 
@@ -36,90 +38,84 @@ func NewZaiUsageSection(cfg interface{}) (registry.Section, error) {
 
 // Render returns the Z.ai usage section output
 func (s *ZaiUsageSection) Render() string {
+	return styled.Render(styled.ANSI{}, s.RenderStyled())
+}
+
+// RenderStyled returns the Z.ai usage section output as styled text, with
+// percentages colored as they near the quota
+func (s *ZaiUsageSection) RenderStyled() styled.Text {
 	info := s.client.Fetch()
-	if info == nil || info.IsEmpty() {
-		return ""
+	if info == nil {
+		if err := s.client.Err(); errors.Is(err, zai.ErrNoAPIKey) {
+			s.MarkUnavailable(err.Error())
+		} else if err != nil {
+			s.MarkDegraded(fmt.Sprintf("usage API: %v", err))
+		}
+		return nil
+	}
+	s.MarkHealthy()
+	if info.IsEmpty() {
+		return nil
 	}
 
-	var parts []string
-	showResetTimes := s.GetConfig().ShowZaiResetTimes()
+	var output styled.Text
+	showResetTimes := s.GetConfig().SectionOptions(s.Name()).Bool("show_reset_times", false)
+	add := func(icon string, percent int, reset time.Time) {
+		if len(output) > 0 {
+			output = output.Add(" | ", styled.None)
+		}
+		output = output.Add(icon+" ", styled.None).Signal(fmt.Sprintf("%d%%", percent), s.getUsageColor(percent))
+		if showResetTimes && !reset.IsZero() {
+			output = output.Add(" ", styled.None).Add(fmt.Sprintf("(reset: %s)", formatResetTime(reset)), styled.Dimmed)
+		}
+	}
 
 	// Session usage (5-hour rolling window)
 	if info.SessionPercent > 0 {
-		sessionDisplay := fmt.Sprintf("%d%%", info.SessionPercent)
-		color := s.getUsageColor(info.SessionPercent)
-		if color != "" {
-			sessionDisplay = fmt.Sprintf("%s%s%s", color, sessionDisplay, theme.Reset)
-		}
-		if showResetTimes && !info.SessionReset.IsZero() {
-			sessionDisplay += fmt.Sprintf(" %s(reset: %s)%s", theme.Dim, formatResetTime(info.SessionReset), theme.Reset)
-		}
-		parts = append(parts, "🔋 "+sessionDisplay)
+		add("🔋", info.SessionPercent, info.SessionReset)
 	}
 
 	// Weekly usage
 	if info.WeeklyPercent > 0 {
-		weeklyDisplay := fmt.Sprintf("%d%%", info.WeeklyPercent)
-		color := s.getUsageColor(info.WeeklyPercent)
-		if color != "" {
-			weeklyDisplay = fmt.Sprintf("%s%s%s", color, weeklyDisplay, theme.Reset)
-		}
-		if showResetTimes && !info.WeeklyReset.IsZero() {
-			weeklyDisplay += fmt.Sprintf(" %s(reset: %s)%s", theme.Dim, formatResetTime(info.WeeklyReset), theme.Reset)
-		}
-		parts = append(parts, "📊 "+weeklyDisplay)
+		add("📊", info.WeeklyPercent, info.WeeklyReset)
 	}
 
-	// Search usage (monthly)
+	// Search usage (monthly), which has no reset time
 	if info.SearchPercent > 0 {
-		searchDisplay := fmt.Sprintf("%d%%", info.SearchPercent)
-		color := s.getUsageColor(info.SearchPercent)
-		if color != "" {
-			searchDisplay = fmt.Sprintf("%s%s%s", color, searchDisplay, theme.Reset)
-		}
-		parts = append(parts, "🔍 "+searchDisplay)
-	}
-
-	if len(parts) == 0 {
-		return ""
+		add("🔍", info.SearchPercent, time.Time{})
 	}
 
-	return strings.Join(parts, " | ")
+	return output
 }
 
 // getUsageColor returns the color based on usage percentage
-func (s *ZaiUsageSection) getUsageColor(percent int) string {
+func (s *ZaiUsageSection) getUsageColor(percent int) styled.Color {
 	switch {
 	case percent >= 90:
-		return theme.Red // Red for critical
+		return styled.Error // Red for critical
 	case percent >= 70:
-		return theme.Yellow // Yellow for warning
+		return styled.Warning // Yellow for warning
 	default:
-		return "" // Default terminal color (green implied by low usage)
+		return styled.NoColor // Default terminal color (green implied by low usage)
 	}
 }
 
 // formatResetTime formats a reset time for display
 func formatResetTime(t time.Time) string {
-	now := time.Now()
-	duration := t.Sub(now)
-
+	duration := time.Until(t)
 	if duration < 0 {
-		return "soon"
-	}
-
-	// Format as relative time
-	if duration < time.Minute {
-		return "<1m"
-	} else if duration < time.Hour {
-		return fmt.Sprintf("%dm", int(duration.Minutes()))
-	} else if duration < 24*time.Hour {
-		return fmt.Sprintf("%dh %dm", int(duration.Hours()), int(duration.Minutes())%60)
-	} else {
-		return fmt.Sprintf("%dd %dh", int(duration.Hours())/24, int(duration.Hours())%24)
+		return i18n.T("time.soon")
 	}
+	return humanize.Duration(duration, humanize.Format{Smallest: time.Minute})
 }
 
 func init() {
-	registry.Register("zaiusage", NewZaiUsageSection)
+	registry.RegisterWithMetadata("zaiusage", NewZaiUsageSection, registry.Metadata{
+		Description: "Z.ai coding plan quota usage",
+		Priority:    registry.PriorityImportant,
+		Options: []registry.Option{
+			{Name: "show_reset_times", Type: "bool", Default: "false", Description: "Show when quotas reset"},
+		},
+		Dependencies: []registry.Dependency{depZaiKey},
+	})
 }
@@ -0,0 +1,27 @@
+package session
+
+import "time"
+
+// postWindow is the span webhook rate limits count posts over
+const postWindow = time.Hour
+
+// AllowPost reports whether the webhook named name may post at now without
+// going over perHour posts in the last hour, and records the post if so.
+// Hooks of concurrent sessions share the limit through the state file
+func (s *State) AllowPost(name string, perHour int, now time.Time) bool {
+	if s.Posts == nil {
+		s.Posts = make(map[string][]time.Time)
+	}
+	var recent []time.Time
+	for _, at := range s.Posts[name] {
+		if now.Sub(at) < postWindow {
+			recent = append(recent, at)
+		}
+	}
+	if len(recent) >= perHour {
+		s.Posts[name] = recent
+		return false
+	}
+	s.Posts[name] = append(recent, now)
+	return true
+}
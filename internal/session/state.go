@@ -0,0 +1,312 @@
+// Package session persists cross-process session state shared between
+// hook invocations, the statusline and the daemon
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	// StateFileName is the name of the shared state file inside the state directory
+	StateFileName = "state.json"
+
+	// MaxRecentEvents caps the number of hook events kept per session
+	MaxRecentEvents = 50
+
+	// staleSessionAge is how long a session may be idle before it is pruned
+	staleSessionAge = 7 * 24 * time.Hour
+)
+
+// HookEvent is a compact record of a single hook invocation
+type HookEvent struct {
+	Name      string    `json:"name"`                  // PreToolUse, PostToolUse, Stop, ...
+	ToolName  string    `json:"tool_name,omitempty"`   // Tool name for tool events
+	ToolUseID string    `json:"tool_use_id,omitempty"` // Tool use ID when provided by Claude Code
+	Target    string    `json:"target,omitempty"`      // Extracted target (file path, command, pattern)
+	IsError   bool      `json:"is_error,omitempty"`    // Whether the tool reported an error
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ActiveTool is a tool call that has started (PreToolUse) but not finished yet
+type ActiveTool struct {
+	Name      string    `json:"name"`
+	Target    string    `json:"target,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// ApprovalRequest is a permission prompt Claude Code is showing, announced
+// by a Notification hook
+type ApprovalRequest struct {
+	Message string    `json:"message"` // e.g. "Claude needs your permission to use Bash"
+	At      time.Time `json:"at"`
+}
+
+// RemoteStatus is what the statusline of a session on another machine, such
+// as a container or dev box, last reported to the daemon; its transcript
+// cannot be read from here
+type RemoteStatus struct {
+	Host          string    `json:"host,omitempty"`
+	Model         string    `json:"model,omitempty"`
+	ContextTokens int       `json:"context_tokens,omitempty"`
+	ContextSize   int       `json:"context_size,omitempty"`
+	ReportedAt    time.Time `json:"reported_at"`
+}
+
+// SessionState is the hook-derived state for a single Claude Code session
+type SessionState struct {
+	SessionID      string                `json:"session_id"`
+	TranscriptPath string                `json:"transcript_path,omitempty"`
+	Cwd            string                `json:"cwd,omitempty"`
+	LastEvent      string                `json:"last_event,omitempty"`
+	LastEventAt    time.Time             `json:"last_event_at"`
+	ActiveTools    map[string]ActiveTool `json:"active_tools,omitempty"`
+	RecentEvents   []HookEvent           `json:"recent_events,omitempty"`
+	ToolCalls      int                   `json:"tool_calls"`
+	Stopped        bool                  `json:"stopped"`
+	Name           string                `json:"name,omitempty"`     // Set with `claude-hud name`
+	Approval       *ApprovalRequest      `json:"approval,omitempty"` // Pending permission prompt; cleared by the next hook event
+	Remote         *RemoteStatus         `json:"remote,omitempty"`   // Reported by a statusline on another machine
+}
+
+// State is the content of the shared state file
+type State struct {
+	Sessions  map[string]*SessionState `json:"sessions"`
+	Timer     *Timer                   `json:"timer,omitempty"` // Focus timer; nil when none is set
+	Posts     map[string][]time.Time   `json:"posts,omitempty"` // Webhook posts in the last hour, by webhook name
+	UpdatedAt time.Time                `json:"updated_at"`
+}
+
+// NewState creates an empty state
+func NewState() *State {
+	return &State{
+		Sessions: make(map[string]*SessionState),
+	}
+}
+
+// Session returns the state for a session, creating it if necessary
+func (s *State) Session(id string) *SessionState {
+	if s.Sessions == nil {
+		s.Sessions = make(map[string]*SessionState)
+	}
+	sess, ok := s.Sessions[id]
+	if !ok {
+		sess = &SessionState{
+			SessionID:   id,
+			ActiveTools: make(map[string]ActiveTool),
+		}
+		s.Sessions[id] = sess
+	}
+	if sess.ActiveTools == nil {
+		sess.ActiveTools = make(map[string]ActiveTool)
+	}
+	return sess
+}
+
+// Latest returns the most recently updated session, or nil if there are none
+func (s *State) Latest() *SessionState {
+	var latest *SessionState
+	for _, sess := range s.Sessions {
+		if latest == nil || sess.LastEventAt.After(latest.LastEventAt) {
+			latest = sess
+		}
+	}
+	return latest
+}
+
+// FindByTranscript returns the session whose transcript matches path, or nil
+func (s *State) FindByTranscript(path string) *SessionState {
+	if path == "" {
+		return nil
+	}
+	for _, sess := range s.Sessions {
+		if sess.TranscriptPath == path {
+			return sess
+		}
+	}
+	return nil
+}
+
+// FindByName returns the most recently active session with the given name,
+// ignoring case, or nil
+func (s *State) FindByName(name string) *SessionState {
+	var found *SessionState
+	for _, sess := range s.Sessions {
+		if sess.Name == "" || !strings.EqualFold(sess.Name, name) {
+			continue
+		}
+		if found == nil || sess.LastEventAt.After(found.LastEventAt) {
+			found = sess
+		}
+	}
+	return found
+}
+
+// prune removes sessions that have been idle for longer than staleSessionAge
+// Named sessions are kept so reports can still show their names, but their
+// event history is dropped
+func (s *State) prune(now time.Time) {
+	for id, sess := range s.Sessions {
+		if sess.LastEventAt.IsZero() || now.Sub(sess.LastEventAt) <= staleSessionAge {
+			continue
+		}
+		if sess.Name == "" {
+			delete(s.Sessions, id)
+			continue
+		}
+		sess.ActiveTools = nil
+		sess.RecentEvents = nil
+	}
+}
+
+// AddEvent appends an event to the session's recent history, keeping at most MaxRecentEvents
+func (ss *SessionState) AddEvent(ev HookEvent) {
+	ss.RecentEvents = append(ss.RecentEvents, ev)
+	if len(ss.RecentEvents) > MaxRecentEvents {
+		ss.RecentEvents = ss.RecentEvents[len(ss.RecentEvents)-MaxRecentEvents:]
+	}
+	ss.LastEvent = ev.Name
+	ss.LastEventAt = ev.Timestamp
+}
+
+// Store reads and writes the shared state file with cross-process locking
+type Store struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewStore creates a store backed by the given state file path
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// DefaultStore returns a store at the default state file location
+func DefaultStore() (*Store, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(filepath.Join(dir, StateFileName)), nil
+}
+
+// StateDir returns the claude-hud state directory
+// Honors $XDG_STATE_HOME, defaulting to ~/.local/state/claude-hud
+func StateDir() (string, error) {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "claude-hud"), nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".local", "state", "claude-hud"), nil
+}
+
+// Path returns the state file path
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load reads the current state without taking the write lock
+// A missing file yields an empty state
+func (s *Store) Load() (*State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read()
+}
+
+// Update applies fn to the state under an exclusive lock and writes the result atomically
+func (s *Store) Update(fn func(*State) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	// Serialize writers across processes (hooks can fire concurrently)
+	lock, err := os.OpenFile(s.path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open lock file: %w", err)
+	}
+	defer lock.Close()
+	if err := syscall.Flock(int(lock.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("failed to lock state file: %w", err)
+	}
+	defer syscall.Flock(int(lock.Fd()), syscall.LOCK_UN)
+
+	state, err := s.read()
+	if err != nil {
+		// A corrupt state file should never block hooks - start over
+		state = NewState()
+	}
+
+	if err := fn(state); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	state.prune(now)
+	state.UpdatedAt = now
+
+	return s.write(state)
+}
+
+// read loads the state file, returning an empty state when it does not exist
+func (s *Store) read() (*State, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return NewState(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	state := NewState()
+	if len(data) == 0 {
+		return state, nil
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file: %w", err)
+	}
+	if state.Sessions == nil {
+		state.Sessions = make(map[string]*SessionState)
+	}
+	return state, nil
+}
+
+// write atomically replaces the state file
+func (s *Store) write(state *State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".state-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temp state file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to close state file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+	return nil
+}
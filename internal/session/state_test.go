@@ -0,0 +1,222 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStore_LoadMissingFile(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "state.json"))
+
+	state, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(state.Sessions) != 0 {
+		t.Errorf("expected empty state, got %d sessions", len(state.Sessions))
+	}
+}
+
+func TestStore_UpdateRoundTrip(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "nested", "state.json"))
+	now := time.Now()
+
+	err := store.Update(func(s *State) error {
+		sess := s.Session("abc")
+		sess.TranscriptPath = "/tmp/t.jsonl"
+		sess.AddEvent(HookEvent{Name: "PreToolUse", ToolName: "Read", Timestamp: now})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	state, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	sess, ok := state.Sessions["abc"]
+	if !ok {
+		t.Fatal("session abc not persisted")
+	}
+	if sess.LastEvent != "PreToolUse" {
+		t.Errorf("LastEvent = %q, want PreToolUse", sess.LastEvent)
+	}
+	if got := state.FindByTranscript("/tmp/t.jsonl"); got == nil || got.SessionID != "abc" {
+		t.Errorf("FindByTranscript() = %v, want session abc", got)
+	}
+	if state.UpdatedAt.IsZero() {
+		t.Error("UpdatedAt should be set")
+	}
+}
+
+func TestStore_UpdateRecoversFromCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	store := NewStore(path)
+
+	if _, err := store.Load(); err == nil {
+		t.Error("Load() should report corrupt state file")
+	}
+	if err := store.Update(func(s *State) error {
+		s.Session("x")
+		return nil
+	}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	state, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() after update error = %v", err)
+	}
+	if _, ok := state.Sessions["x"]; !ok {
+		t.Error("expected session x after recovery")
+	}
+}
+
+func TestStore_ConcurrentUpdates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Separate stores simulate separate hook processes
+			store := NewStore(path)
+			_ = store.Update(func(s *State) error {
+				s.Session(fmt.Sprintf("s%d", i)).LastEventAt = time.Now()
+				return nil
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	state, err := NewStore(path).Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(state.Sessions) != 10 {
+		t.Errorf("expected 10 sessions, got %d", len(state.Sessions))
+	}
+}
+
+func TestSessionState_AddEventCapsHistory(t *testing.T) {
+	sess := NewState().Session("s")
+	for i := 0; i < MaxRecentEvents+10; i++ {
+		sess.AddEvent(HookEvent{Name: fmt.Sprintf("e%d", i), Timestamp: time.Now()})
+	}
+	if len(sess.RecentEvents) != MaxRecentEvents {
+		t.Errorf("len(RecentEvents) = %d, want %d", len(sess.RecentEvents), MaxRecentEvents)
+	}
+	if sess.RecentEvents[0].Name != "e10" {
+		t.Errorf("oldest event = %q, want e10", sess.RecentEvents[0].Name)
+	}
+}
+
+func TestState_PruneAndLatest(t *testing.T) {
+	now := time.Now()
+	state := NewState()
+	state.Session("old").LastEventAt = now.Add(-8 * 24 * time.Hour)
+	state.Session("recent").LastEventAt = now.Add(-time.Minute)
+	state.Session("newest").LastEventAt = now
+
+	state.prune(now)
+
+	if _, ok := state.Sessions["old"]; ok {
+		t.Error("stale session should be pruned")
+	}
+	if latest := state.Latest(); latest == nil || latest.SessionID != "newest" {
+		t.Errorf("Latest() = %v, want newest", latest)
+	}
+}
+
+func TestState_NamedSessions(t *testing.T) {
+	now := time.Now()
+	state := NewState()
+	old := state.Session("old")
+	old.Name = "Auth refactor"
+	old.LastEventAt = now.Add(-30 * 24 * time.Hour)
+	old.AddEvent(HookEvent{Name: "Stop", Timestamp: old.LastEventAt})
+	newer := state.Session("newer")
+	newer.Name = "auth refactor"
+	newer.LastEventAt = now
+	state.Session("unnamed").LastEventAt = now.Add(-30 * 24 * time.Hour)
+
+	state.prune(now)
+
+	if _, ok := state.Sessions["unnamed"]; ok {
+		t.Error("stale unnamed session should be pruned")
+	}
+	kept, ok := state.Sessions["old"]
+	if !ok {
+		t.Fatal("stale named session should be kept")
+	}
+	if len(kept.RecentEvents) != 0 {
+		t.Errorf("stale named session kept %d events, want 0", len(kept.RecentEvents))
+	}
+	if found := state.FindByName("AUTH REFACTOR"); found == nil || found.SessionID != "newer" {
+		t.Errorf("FindByName() = %v, want the newer session", found)
+	}
+	if found := state.FindByName("other"); found != nil {
+		t.Errorf("FindByName(other) = %v, want nil", found)
+	}
+}
+
+func TestStore_TimerRoundTrip(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "state.json"))
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	if err := store.Update(func(s *State) error {
+		s.Timer = NewTimer(25*time.Minute, "review", now)
+		return nil
+	}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	state, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	timer := state.Timer
+	if timer == nil || timer.Label != "review" || timer.Duration() != 25*time.Minute {
+		t.Fatalf("Timer = %+v, want a 25m review timer", timer)
+	}
+	if got := timer.Remaining(now.Add(10 * time.Minute)); got != 15*time.Minute {
+		t.Errorf("Remaining() = %v, want 15m", got)
+	}
+	if timer.Expired(now.Add(24 * time.Minute)) {
+		t.Error("timer expired early")
+	}
+	if !timer.Expired(now.Add(25*time.Minute)) || timer.Remaining(now.Add(time.Hour)) != 0 {
+		t.Error("timer should be up after 25m")
+	}
+}
+
+func TestState_AllowPost(t *testing.T) {
+	state := NewState()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	for i := range 3 {
+		if !state.AllowPost("team", 3, now.Add(time.Duration(i)*time.Minute)) {
+			t.Fatalf("post %d refused, want 3 allowed", i+1)
+		}
+	}
+	if state.AllowPost("team", 3, now.Add(10*time.Minute)) {
+		t.Error("fourth post within the hour allowed")
+	}
+	if !state.AllowPost("other", 3, now.Add(10*time.Minute)) {
+		t.Error("another webhook's posts counted against this one")
+	}
+	if !state.AllowPost("team", 3, now.Add(time.Hour)) {
+		t.Error("post refused once the first left the hour")
+	}
+	if got := len(state.Posts["team"]); got != 3 {
+		t.Errorf("%d posts kept, want the 3 of the last hour", got)
+	}
+}
@@ -0,0 +1,30 @@
+package session
+
+import "time"
+
+// Timer is a focus timer started with `claude-hud timer start`
+type Timer struct {
+	Label     string    `json:"label,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	EndsAt    time.Time `json:"ends_at"`
+}
+
+// NewTimer creates a timer running for d from now
+func NewTimer(d time.Duration, label string, now time.Time) *Timer {
+	return &Timer{Label: label, StartedAt: now, EndsAt: now.Add(d)}
+}
+
+// Duration returns how long the timer was set for
+func (t *Timer) Duration() time.Duration {
+	return t.EndsAt.Sub(t.StartedAt)
+}
+
+// Remaining returns the time left at now, or 0 once the timer is up
+func (t *Timer) Remaining(now time.Time) time.Duration {
+	return max(0, t.EndsAt.Sub(now))
+}
+
+// Expired reports whether the timer is up at now
+func (t *Timer) Expired(now time.Time) bool {
+	return !now.Before(t.EndsAt)
+}
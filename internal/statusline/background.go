@@ -0,0 +1,141 @@
+package statusline
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+)
+
+// sectionWorker collects a single section's output in the background and
+// keeps the last successfully rendered result
+type sectionWorker struct {
+	section registry.Section
+	cancel  context.CancelFunc
+	budget  *errorBudget
+
+	mu        sync.RWMutex
+	frames    []string
+	ready     bool
+	updatedAt time.Time
+}
+
+// latest returns the frame of the most recent completed render due now, so
+// animated sections keep moving between renders
+func (w *sectionWorker) latest() (string, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return registry.FrameAt(w.frames, time.Now()), w.ready
+}
+
+// store records a completed render
+func (w *sectionWorker) store(frames []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.frames = frames
+	w.ready = true
+	w.updatedAt = time.Now()
+}
+
+// collect renders the section once, keeping the previous result if it
+// panics, and reports whether the section is still within its error budget
+func (w *sectionWorker) collect(debug bool) (ok bool) {
+	done := w.budget.watch(w.section.Name())
+	defer func() {
+		done()
+		if r := recover(); r != nil {
+			errors.RecordPanic("section."+w.section.Name(), r)
+			if debug {
+				log.Printf("Panic collecting section %s: %v", w.section.Name(), r)
+			}
+			ok = !w.budget.fail(w.section.Name(), fmt.Sprintf("a panic: %v", r), time.Now())
+		}
+	}()
+	w.store(registry.RenderFrames(w.section))
+	return !w.budget.isDisabled(w.section.Name())
+}
+
+// run collects the section every interval until ctx is cancelled
+// A slow render only delays this section's next update; the render loop
+// keeps showing the last completed result in the meantime
+func (w *sectionWorker) run(ctx context.Context, interval time.Duration, debug bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	if !w.collect(debug) {
+		return
+	}
+	for {
+		select {
+		case <-ticker.C:
+			if !w.collect(debug) {
+				return // Turned off for failing too often
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// startWorkers launches a background collector for every current section
+func (s *Statusline) startWorkers(ctx context.Context) {
+	s.mu.RLock()
+	interval := s.refreshInterval
+	s.mu.RUnlock()
+
+	s.workersMu.Lock()
+	s.workersCtx = ctx
+	s.workersInterval = interval
+	s.workersMu.Unlock()
+
+	for _, section := range s.GetSections() {
+		s.ensureWorker(section)
+	}
+}
+
+// ensureWorker starts a collector for section if background refresh is
+// active and none exists yet; returns nil when rendering is synchronous
+func (s *Statusline) ensureWorker(section registry.Section) *sectionWorker {
+	s.workersMu.Lock()
+	defer s.workersMu.Unlock()
+
+	if s.workersCtx == nil || s.workersCtx.Err() != nil {
+		return nil
+	}
+	if w, ok := s.workers[section]; ok {
+		return w
+	}
+
+	ctx, cancel := context.WithCancel(s.workersCtx)
+	w := &sectionWorker{section: section, cancel: cancel, budget: s.budget}
+	s.workers[section] = w
+	go w.run(ctx, s.workersInterval, s.config.Debug)
+	return w
+}
+
+// stopWorker stops the collector for section, if any
+func (s *Statusline) stopWorker(section registry.Section) {
+	s.workersMu.Lock()
+	defer s.workersMu.Unlock()
+
+	if w, ok := s.workers[section]; ok {
+		w.cancel()
+		delete(s.workers, section)
+	}
+}
+
+// stopWorkers stops all background collectors
+func (s *Statusline) stopWorkers() {
+	s.workersMu.Lock()
+	defer s.workersMu.Unlock()
+
+	for section, w := range s.workers {
+		w.cancel()
+		delete(s.workers, section)
+	}
+	s.workersCtx = nil
+}
@@ -0,0 +1,143 @@
+package statusline
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/crash"
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+)
+
+// errorBudget turns off sections that keep failing. A section that panics
+// or times out more often than the budget allows within its window is
+// skipped for the rest of the run, instead of being rendered and recovered
+// from on every refresh. Failures and sections turned off are recorded in
+// the crash record, and later runs carry them over with restore
+type errorBudget struct {
+	failures int
+	window   time.Duration
+	timeout  time.Duration
+	disable  time.Duration
+
+	mu       sync.Mutex
+	recent   map[string][]time.Time // Failures within the window, by section
+	disabled map[string]string      // Why each section was turned off
+}
+
+// newErrorBudget creates a budget with the configured limits
+func newErrorBudget(cfg config.ErrorBudgetConfig) *errorBudget {
+	return &errorBudget{
+		failures: cfg.Failures,
+		window:   time.Duration(cfg.WindowMs) * time.Millisecond,
+		timeout:  time.Duration(cfg.TimeoutMs) * time.Millisecond,
+		disable:  time.Duration(cfg.DisableMs) * time.Millisecond,
+		recent:   make(map[string][]time.Time),
+		disabled: make(map[string]string),
+	}
+}
+
+// fail records a failure of the section called name, described by what,
+// e.g. "a panic: boom", and reports whether the section is now turned off
+func (b *errorBudget) fail(name, what string, now time.Time) bool {
+	if b.failures <= 0 {
+		return false
+	}
+	b.mu.Lock()
+	if _, ok := b.disabled[name]; ok {
+		b.mu.Unlock()
+		return true
+	}
+
+	times := append(b.recent[name], now)
+	for len(times) > 0 && now.Sub(times[0]) > b.window {
+		times = times[1:]
+	}
+	if len(times) <= b.failures {
+		b.recent[name] = times
+		b.mu.Unlock()
+		errors.RecordFailure("section."+name, now)
+		return false
+	}
+
+	reason := fmt.Sprintf("%d panics or timeouts in %s, the last %s", len(times), b.window, what)
+	b.disabled[name] = reason
+	delete(b.recent, name)
+	b.mu.Unlock()
+
+	// Observers may write the crash record; not while holding the lock
+	errors.Warn("statusline", "section %s disabled: %s", name, reason)
+	errors.RecordDisabled("section."+name, reason)
+	return true
+}
+
+// watch counts a render of the section called name starting now as a
+// failure once it runs past the timeout, whether or not it ever returns.
+// Call the returned function when the render is done
+func (b *errorBudget) watch(name string) (done func()) {
+	if b.failures <= 0 {
+		return func() {}
+	}
+	timer := time.AfterFunc(b.timeout, func() {
+		b.fail(name, fmt.Sprintf("a render taking over %s", b.timeout), time.Now())
+	})
+	return func() { timer.Stop() }
+}
+
+// restore carries over the failures within the window and the sections
+// turned off within the disable period from an earlier run's record.
+// Claude Code starts a process for every statusline refresh, so without
+// them a single-shot render could never use up the budget
+func (b *errorBudget) restore(record *crash.Record, now time.Time) {
+	if b.failures <= 0 || record == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for op, d := range record.Disabled {
+		name, ok := strings.CutPrefix(op, "section.")
+		if ok && now.Sub(d.At) < b.disable {
+			b.disabled[name] = d.Reason
+		}
+	}
+	for op, times := range record.Failures {
+		name, ok := strings.CutPrefix(op, "section.")
+		if !ok {
+			continue
+		}
+		var recent []time.Time
+		for _, t := range times {
+			if now.Sub(t) <= b.window {
+				recent = append(recent, t)
+			}
+		}
+		if len(recent) > 0 {
+			b.recent[name] = recent
+		}
+	}
+}
+
+// isDisabled reports whether the section called name is turned off
+func (b *errorBudget) isDisabled(name string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, ok := b.disabled[name]
+	return ok
+}
+
+// RestoreErrorBudget carries the error budget over from an earlier run's
+// crash record, turning off the sections it turned off
+func (s *Statusline) RestoreErrorBudget(record *crash.Record) {
+	s.budget.restore(record, time.Now())
+}
+
+// DisabledSections returns the sections turned off for failing too often,
+// with why, by name
+func (s *Statusline) DisabledSections() map[string]string {
+	s.budget.mu.Lock()
+	defer s.budget.mu.Unlock()
+	return maps.Clone(s.budget.disabled)
+}
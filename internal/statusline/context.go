@@ -8,11 +8,12 @@ import (
 type ClaudeCodeContext struct {
 	mu                 sync.RWMutex
 	TranscriptPath     string
-	WorkspaceDir       string
 	ModelName          string
 	ContextWindowSize  int
 	ContextInputTokens int
 	ContextCacheTokens int
+	ModelID            string
+	Exceeds200kTokens  bool // Claude Code's exceeds_200k_tokens flag
 	Available          bool // true if JSON was successfully parsed
 }
 
@@ -20,16 +21,15 @@ type ClaudeCodeContext struct {
 var globalContext = &ClaudeCodeContext{}
 
 // SetContext updates the global context from parsed JSON
-func SetContext(transcriptPath, workspaceDir, modelName string) {
-	SetContextWithWindow(transcriptPath, workspaceDir, modelName, 0, 0, 0)
+func SetContext(transcriptPath, modelName string) {
+	SetContextWithWindow(transcriptPath, modelName, 0, 0, 0)
 }
 
 // SetContextWithWindow updates the global context including context window data
-func SetContextWithWindow(transcriptPath, workspaceDir, modelName string, contextWindowSize, contextInputTokens, contextCacheTokens int) {
+func SetContextWithWindow(transcriptPath, modelName string, contextWindowSize, contextInputTokens, contextCacheTokens int) {
 	globalContext.mu.Lock()
 	defer globalContext.mu.Unlock()
 	globalContext.TranscriptPath = transcriptPath
-	globalContext.WorkspaceDir = workspaceDir
 	globalContext.ModelName = modelName
 	globalContext.ContextWindowSize = contextWindowSize
 	globalContext.ContextInputTokens = contextInputTokens
@@ -37,18 +37,34 @@ func SetContextWithWindow(transcriptPath, workspaceDir, modelName string, contex
 	globalContext.Available = true
 }
 
-// GetTranscriptPath returns the transcript path from context
-func GetTranscriptPath() string {
+// SetLongContext records the model ID and whether the context has grown past
+// 200k tokens, for 1M-context beta awareness
+func SetLongContext(modelID string, exceeds200kTokens bool) {
+	globalContext.mu.Lock()
+	defer globalContext.mu.Unlock()
+	globalContext.ModelID = modelID
+	globalContext.Exceeds200kTokens = exceeds200kTokens
+}
+
+// GetModelID returns the model ID from context, e.g. "claude-sonnet-4-5[1m]"
+func GetModelID() string {
 	globalContext.mu.RLock()
 	defer globalContext.mu.RUnlock()
-	return globalContext.TranscriptPath
+	return globalContext.ModelID
 }
 
-// GetWorkspaceDir returns the workspace directory from context
-func GetWorkspaceDir() string {
+// Exceeds200kTokens reports Claude Code's exceeds_200k_tokens flag
+func Exceeds200kTokens() bool {
 	globalContext.mu.RLock()
 	defer globalContext.mu.RUnlock()
-	return globalContext.WorkspaceDir
+	return globalContext.Exceeds200kTokens
+}
+
+// GetTranscriptPath returns the transcript path from context
+func GetTranscriptPath() string {
+	globalContext.mu.RLock()
+	defer globalContext.mu.RUnlock()
+	return globalContext.TranscriptPath
 }
 
 // GetModelName returns the model name from context
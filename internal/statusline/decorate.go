@@ -0,0 +1,130 @@
+package statusline
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+)
+
+// defaultSeparator separates sections when neither the line nor the layout sets one
+const defaultSeparator = " | "
+
+// reverseVideo swaps foreground and background colors, filling a pill
+const (
+	reverseVideo    = "\033[7m"
+	reverseVideoOff = "\033[27m"
+)
+
+// sectionStyle is how a section is joined to the one before it and decorated
+// Sections inherit the style of their line, which inherits the layout's;
+// sections.<name>.separator, decoration and padding override it per section
+type sectionStyle struct {
+	separator  string // Placed before the section unless it starts the line
+	decoration string
+	padding    int
+}
+
+// lineStyle returns the style of sections on a line; lineCfg may be nil for
+// sections outside layout.lines
+func lineStyle(cfg *config.Config, lineCfg *config.LineConfig) sectionStyle {
+	style := sectionStyle{
+		separator:  cfg.Layout.Separator,
+		decoration: cfg.Layout.Decoration,
+		padding:    cfg.Layout.Padding,
+	}
+	if lineCfg != nil {
+		if lineCfg.Separator != "" {
+			style.separator = lineCfg.Separator
+		}
+		if lineCfg.Decoration != "" {
+			style.decoration = lineCfg.Decoration
+		}
+		if lineCfg.Padding != nil {
+			style.padding = *lineCfg.Padding
+		}
+	}
+	if style.separator == "" {
+		style.separator = defaultSeparator
+	}
+	return style
+}
+
+// forSection applies a section's own style options
+func (s sectionStyle) forSection(cfg *config.Config, name string) sectionStyle {
+	opts := cfg.Sections.Get(name)
+	s.separator = opts.String("separator", s.separator)
+	s.decoration = opts.String("decoration", s.decoration)
+	s.padding = opts.Int("padding", s.padding)
+	return s
+}
+
+// resetSequence is theme.Reset as bytes, for finding it in rendered sections
+var resetSequence = []byte(theme.Reset)
+
+// decorate pads content and wraps it in the style's decoration
+func (s sectionStyle) decorate(content string) string {
+	return string(s.appendDecorated(nil, []byte(content)))
+}
+
+// appendDecorated appends content to dst, padded and wrapped in the style's decoration
+func (s sectionStyle) appendDecorated(dst, content []byte) []byte {
+	if s.decoration == "pill" {
+		caps := config.Decorations["pill"]
+		dst = append(dst, caps[0]...)
+		dst = append(dst, reverseVideo...)
+		dst = s.appendPadded(dst, content, true)
+		dst = append(dst, reverseVideoOff...)
+		return append(dst, caps[1]...)
+	}
+	opening, closing := decorationParts(s.decoration)
+	dst = append(dst, opening...)
+	dst = s.appendPadded(dst, content, false)
+	return append(dst, closing...)
+}
+
+// appendPadded appends content between the style's padding; in reverse video,
+// color resets are followed by turning reverse video back on
+func (s sectionStyle) appendPadded(dst, content []byte, reverse bool) []byte {
+	dst = appendSpaces(dst, s.padding)
+	for reverse {
+		// Sections reset colors, which would end the reverse video early
+		i := bytes.Index(content, resetSequence)
+		if i < 0 {
+			break
+		}
+		i += len(resetSequence)
+		dst = append(dst, content[:i]...)
+		dst = append(dst, reverseVideo...)
+		content = content[i:]
+	}
+	dst = append(dst, content...)
+	return appendSpaces(dst, s.padding)
+}
+
+func appendSpaces(dst []byte, n int) []byte {
+	for i := 0; i < n; i++ {
+		dst = append(dst, ' ')
+	}
+	return dst
+}
+
+// decorationParts returns the opening and closing strings of a decoration
+func decorationParts(decoration string) (string, string) {
+	if parts, ok := config.Decorations[decoration]; ok {
+		return parts[0], parts[1]
+	}
+	if opening, closing, ok := strings.Cut(decoration, " "); ok {
+		return opening, closing
+	}
+	return "", ""
+}
+
+// appendSection appends a rendered section in style to the line in dst
+func appendSection(dst []byte, style sectionStyle, content []byte) []byte {
+	if len(dst) > 0 {
+		dst = append(dst, style.separator...)
+	}
+	return style.appendDecorated(dst, content)
+}
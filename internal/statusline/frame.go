@@ -0,0 +1,122 @@
+package statusline
+
+import (
+	"io"
+	"sync"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/styled"
+)
+
+// maxPooledBuffer is the largest buffer returned to the pool; an unusually
+// long render should not pin its memory for good
+const maxPooledBuffer = 64 * 1024
+
+// frame holds the buffers one render assembles its lines in. Frames are
+// pooled, so once their buffers have grown, a render with warm section
+// caches allocates nothing: sections append to the frame's buffers, lines
+// are kept in its arena, and everything is written out in a single write
+type frame struct {
+	section []byte // Section being rendered
+	line    []byte // Line being assembled
+	arena   []byte // Texts of the frame's lines
+	lines   []line
+	out     []byte // Lines as written to the output
+
+	// Scratch space for the responsive layout
+	sections []registry.Section
+	grouped  []registry.Section
+	groups   []lineGroup
+}
+
+var framePool = sync.Pool{
+	New: func() interface{} {
+		return &frame{
+			section: make([]byte, 0, 256),
+			line:    make([]byte, 0, 512),
+			arena:   make([]byte, 0, 2048),
+			out:     make([]byte, 0, 2048),
+		}
+	},
+}
+
+// getFrame returns a frame with empty buffers
+func getFrame() *frame {
+	return framePool.Get().(*frame)
+}
+
+// release returns the frame to the pool; nothing it holds may be used after
+func (f *frame) release() {
+	if cap(f.arena) > maxPooledBuffer || cap(f.out) > maxPooledBuffer {
+		return
+	}
+	f.section = f.section[:0]
+	f.line = f.line[:0]
+	f.arena = f.arena[:0]
+	clear(f.lines)
+	f.lines = f.lines[:0]
+	f.out = f.out[:0]
+	clear(f.sections)
+	f.sections = f.sections[:0]
+	clear(f.grouped)
+	f.grouped = f.grouped[:0]
+	clear(f.groups)
+	f.groups = f.groups[:0]
+	framePool.Put(f)
+}
+
+// addLine adds a copy of text as a line of the frame
+func (f *frame) addLine(text []byte, priority registry.Priority) {
+	start := len(f.arena)
+	f.arena = append(f.arena, text...)
+	f.lines = append(f.lines, line{text: f.arena[start:len(f.arena):len(f.arena)], priority: priority})
+}
+
+// renderSection renders section into f.section, or its placeholder when it
+// renders nothing
+func (f *frame) renderSection(cfg *config.Config, section registry.Section) []byte {
+	f.section = registry.AppendRender(f.section[:0], section)
+	if len(f.section) == 0 {
+		f.section = append(f.section, placeholder(cfg, section)...)
+	}
+	return f.section
+}
+
+// strings returns the frame's lines as strings
+func (f *frame) strings() []string {
+	lines := make([]string, len(f.lines))
+	for i, l := range f.lines {
+		lines[i] = string(l.text)
+	}
+	return lines
+}
+
+// writeTo writes prefix and the frame's lines, one per line, in a single write
+func (f *frame) writeTo(w io.Writer, prefix string) error {
+	f.out = append(f.out[:0], prefix...)
+	for i, l := range f.lines {
+		if i > 0 {
+			f.out = append(f.out, '\n')
+		}
+		f.out = append(f.out, l.text...)
+	}
+	_, err := w.Write(f.out)
+	return err
+}
+
+// writeStyled writes the frame's lines shown by backend b instead of as the
+// ANSI escapes sections render. Built-in sections render styled text with
+// the ANSI backend, whose escapes Parse reads back as the same styles;
+// those of custom and third-party sections are read as well as they can be
+func (f *frame) writeStyled(w io.Writer, b styled.Backend) error {
+	f.out = f.out[:0]
+	for i, l := range f.lines {
+		if i > 0 {
+			f.out = append(f.out, '\n')
+		}
+		f.out = b.Append(f.out, styled.Parse(string(l.text)))
+	}
+	_, err := w.Write(f.out)
+	return err
+}
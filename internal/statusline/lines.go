@@ -0,0 +1,143 @@
+package statusline
+
+import (
+	"unicode/utf8"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/terminal"
+)
+
+// defaultMergeWidth is the line width assumed for merging lines when the
+// terminal width is unknown, as when Claude Code runs the statusline
+const defaultMergeWidth = 160
+
+// line is a rendered statusline line with the priority of its most important
+// section, which decides the order lines give way in under max_lines
+// Its text lives in the arena of the frame it was rendered in
+type line struct {
+	text     []byte
+	priority registry.Priority
+}
+
+// morePriority returns the more important of two priorities, ignoring unset ones
+func morePriority(a, b registry.Priority) registry.Priority {
+	switch {
+	case a == registry.PriorityUnset:
+		return b
+	case b == registry.PriorityUnset || a < b:
+		return a
+	}
+	return b
+}
+
+// carryOver wraps the line being assembled before the section appended at
+// mark: the line up to mark is added to the frame with priority, and the
+// section starts a continuation line of its own
+func (f *frame) carryOver(mark int, priority registry.Priority, style sectionStyle, content []byte) {
+	f.addLine(f.line[:mark], priority)
+	f.line = style.appendDecorated(f.line[:0], content)
+}
+
+// fitLines reduces the frame's lines to at most maxLines (0 or less keeps them all)
+// Claude Code cuts off statuslines taller than it allows, hiding whatever
+// happens to come last; instead, the least important line gives way first,
+// the lowest of equally important ones. It is merged onto the line above it,
+// or below it for the first line, joined by separator, when the two fit
+// within width columns together, and dropped otherwise
+func (f *frame) fitLines(maxLines, width int, separator string) {
+	for maxLines > 0 && len(f.lines) > maxLines {
+		// Least important line, the last one among equals
+		i := 0
+		for j := range f.lines {
+			if f.lines[j].priority >= f.lines[i].priority {
+				i = j
+			}
+		}
+
+		neighbours := [2]int{i - 1, i + 1}
+		if i == 0 {
+			neighbours[0] = i + 1
+		}
+		for _, n := range neighbours {
+			if n >= len(f.lines) {
+				continue
+			}
+			first, second := f.lines[n], f.lines[i]
+			if n > i {
+				first, second = second, first
+			}
+			if width > 0 && visibleWidth(first.text)+visibleWidth(separator)+visibleWidth(second.text) > width {
+				continue
+			}
+			start := len(f.arena)
+			f.arena = append(f.arena, first.text...)
+			f.arena = append(f.arena, separator...)
+			f.arena = append(f.arena, second.text...)
+			f.lines[n] = line{text: f.arena[start:len(f.arena):len(f.arena)], priority: morePriority(first.priority, second.priority)}
+			break
+		}
+		f.lines = append(f.lines[:i], f.lines[i+1:]...)
+	}
+}
+
+// mergeWidth returns how wide merged lines may get: the terminal width, or
+// the large breakpoint when the width is unknown
+func mergeWidth(cfg *config.Config) int {
+	if width := terminal.AvailableWidth(); width > 0 {
+		return width
+	}
+	if large := cfg.Layout.Responsive.Large; large > 0 {
+		return large
+	}
+	return defaultMergeWidth
+}
+
+// visibleWidth returns the number of columns s takes up on screen, not
+// counting ANSI escape sequences
+func visibleWidth[T string | []byte](s T) int {
+	width := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\033':
+			i += escapeLength(s[i:]) - 1
+		case !utf8.RuneStart(s[i]):
+			// Continues the rune already counted
+		default:
+			width++
+		}
+	}
+	return width
+}
+
+// escapeLength returns the length of the ANSI escape sequence s starts with:
+// a CSI sequence such as a color code, or an OSC sequence such as a hyperlink
+func escapeLength[T string | []byte](s T) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+	case ']':
+		// Terminated by BEL or ESC \
+		for end := 2; end < len(s); end++ {
+			switch s[end] {
+			case '\a':
+				return end + 1
+			case '\033':
+				if end+1 < len(s) {
+					return end + 2
+				}
+				return end + 1
+			}
+		}
+	default:
+		return 2
+	}
+	return len(s)
+}
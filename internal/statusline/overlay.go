@@ -0,0 +1,59 @@
+package statusline
+
+import (
+	"strings"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+)
+
+// DefaultDebugOverlay is how many entries --debug-overlay shows when the
+// config does not say
+const DefaultDebugOverlay = 3
+
+// overlaySeparator separates entries on the debug overlay line
+const overlaySeparator = " · "
+
+// addOverlay adds the debug overlay, if any, to the frame's lines
+// The overlay is not subject to max_lines: it is there to be seen
+func (s *Statusline) addOverlay(f *frame) {
+	if s.config.DebugOverlay <= 0 {
+		return
+	}
+	if overlay := debugOverlay(errors.Recent(s.config.DebugOverlay), mergeWidth(s.config)); overlay != "" {
+		f.addLine([]byte(overlay), registry.PriorityUnset)
+	}
+}
+
+// debugOverlay formats logged warnings and errors as one dimmed line of at
+// most width columns, e.g. "⚠ git: status timed out · ✗ beads: database locked"
+// Claude Code discards stderr, so in statusline mode this is the only place
+// they show up
+func debugOverlay(entries []errors.Entry, width int) string {
+	if len(entries) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(entries))
+	for _, e := range entries {
+		icon := "⚠"
+		if e.Level >= errors.LevelError {
+			icon = "✗"
+		}
+		text := strings.Join(strings.Fields(e.Message), " ")
+		if e.Op != "" {
+			text = e.Op + ": " + text
+		}
+		parts = append(parts, icon+" "+text)
+	}
+	return theme.Dim + truncateColumns(strings.Join(parts, overlaySeparator), width) + theme.Reset
+}
+
+// truncateColumns shortens plain text to width columns, ending it with "…"
+func truncateColumns(text string, width int) string {
+	runes := []rune(text)
+	if width <= 0 || len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
+}
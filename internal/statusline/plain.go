@@ -0,0 +1,137 @@
+package statusline
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+	"github.com/ll931217/claude-hud-enhanced/internal/i18n"
+	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/terminal"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+)
+
+// plainSeparator separates sections on a line of plain output; screen
+// readers pause at it
+const plainSeparator = "; "
+
+// percentPattern finds percentages to spell out, e.g. "72%"
+var percentPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)%`)
+
+// RenderPlain renders for screen readers: each section as text labeled with
+// its name, e.g. "context: 72 percent, warning", with no colors, bars or
+// spinners. Sections are laid out on the layout's lines, or one per line
+// without a layout, and fitted into max_lines like the statusline
+func (s *Statusline) RenderPlain() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	f := getFrame()
+	defer f.release()
+
+	var groups [][]registry.Section
+	var wraps []bool // Whether each group's line wraps
+	if len(s.config.Layout.Lines) > 0 {
+		for _, lineConfig := range s.config.Layout.Lines {
+			var group []registry.Section
+			for _, name := range lineConfig.Sections {
+				if section, ok := s.byName[name]; ok {
+					group = append(group, section)
+				}
+			}
+			groups = append(groups, group)
+			wraps = append(wraps, lineConfig.Wrap)
+		}
+	} else {
+		for _, section := range s.sections {
+			groups = append(groups, []registry.Section{section})
+			wraps = append(wraps, false)
+		}
+	}
+
+	width := terminal.AvailableWidth()
+	for g, group := range groups {
+		var parts []string
+		var priority registry.Priority
+		lineWidth := 0
+		for _, section := range group {
+			if !section.Enabled() {
+				continue
+			}
+			if text := s.plainSection(f, section); text != "" {
+				textWidth := utf8.RuneCountInString(text)
+				if wraps[g] && len(parts) > 0 && width > 0 && lineWidth+len(plainSeparator)+textWidth > width {
+					// Start a continuation line with this section
+					f.addLine([]byte(strings.Join(parts, plainSeparator)), priority)
+					parts, priority, lineWidth = parts[:0], registry.PriorityUnset, 0
+				}
+				if len(parts) > 0 {
+					lineWidth += len(plainSeparator)
+				}
+				parts = append(parts, text)
+				lineWidth += textWidth
+				priority = morePriority(priority, section.Priority())
+			}
+		}
+		if len(parts) > 0 {
+			f.addLine([]byte(strings.Join(parts, plainSeparator)), priority)
+		}
+	}
+
+	f.fitLines(s.config.MaxLines, mergeWidth(s.config), plainSeparator)
+	s.write(f, "")
+	return nil
+}
+
+// plainSection returns a section's output as labeled plain text: its own
+// description when it has one, or else its rendered output stripped down
+func (s *Statusline) plainSection(f *frame, section registry.Section) (text string) {
+	if describer, ok := registry.DescriberOf(section); ok {
+		defer func() {
+			if r := recover(); r != nil {
+				errors.LogErrorWithLevel(errors.PanicError("section."+section.Name(), r))
+				text = ""
+			}
+		}()
+		return describer.Describe()
+	}
+
+	f.section = s.appendRender(f.section[:0], section)
+	if text = plainText(string(f.section)); text == "" {
+		return ""
+	}
+	return section.Name() + ": " + text
+}
+
+// plainText reduces rendered output to words a screen reader reads well:
+// escape sequences, bar and spinner glyphs go, percentages are spelled out
+// and the warning and critical markers become words
+func plainText(rendered string) string {
+	var b strings.Builder
+	for i := 0; i < len(rendered); {
+		if rendered[i] == '\033' {
+			i += escapeLength(rendered[i:])
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(rendered[i:])
+		i += size
+		switch {
+		case r == 0xfe0f: // Emoji presentation selector
+		case r >= 0x2580 && r <= 0x259f: // Block elements: bars and sparklines
+			b.WriteByte(' ')
+		case r >= 0x2800 && r <= 0x28ff: // Braille patterns: spinners
+			b.WriteByte(' ')
+		case string(r) == theme.CriticalMarker:
+			b.WriteString(" " + i18n.T("plain.critical") + " ")
+		case string(r) == theme.WarningMarker:
+			b.WriteString(" " + i18n.T("plain.warning") + " ")
+		default:
+			b.WriteRune(r)
+		}
+	}
+	text := percentPattern.ReplaceAllStringFunc(b.String(), func(match string) string {
+		return i18n.T("plain.percent", strings.TrimSuffix(match, "%"))
+	})
+	return strings.Join(strings.Fields(text), " ")
+}
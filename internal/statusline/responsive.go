@@ -1,11 +1,14 @@
 package statusline
 
 import (
-	"strings"
+	"cmp"
+	"unicode/utf8"
 
 	"github.com/ll931217/claude-hud-enhanced/internal/config"
 	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/render"
 	"github.com/ll931217/claude-hud-enhanced/internal/terminal"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
 )
 
 // BreakpointLevel represents terminal size category
@@ -20,48 +23,61 @@ const (
 // ResponsiveRenderer handles adaptive layout based on terminal size
 type ResponsiveRenderer struct {
 	config   *config.Config
-	sections map[string]registry.Section
+	sections []registry.Section // In display order
+
+	// appendRender renders a section with the statusline's panic recovery
+	// and error budget; without a statusline, sections render directly
+	appendRender func(dst []byte, section registry.Section) []byte
 }
 
 // NewResponsiveRenderer creates a new responsive renderer
 func NewResponsiveRenderer(cfg *config.Config, sections map[string]registry.Section) *ResponsiveRenderer {
 	return &ResponsiveRenderer{
-		config:   cfg,
-		sections: sections,
+		config: cfg,
+		sections: render.Values(sections, func(a, b registry.Section) int {
+			return cmp.Compare(a.Order(), b.Order())
+		}),
 	}
 }
 
 // RenderLayout renders sections according to available space
 func (r *ResponsiveRenderer) RenderLayout() []string {
+	f := getFrame()
+	defer f.release()
+	r.render(f)
+	return f.strings()
+}
+
+// render renders sections according to available space into the frame's lines
+func (r *ResponsiveRenderer) render(f *frame) {
 	termWidth := terminal.AvailableWidth()
 
 	// If terminal width is 0 (non-TTY/statusline mode), assume large terminal
 	// Claude Code will handle the actual layout
 	if termWidth == 0 {
-		sections := r.getAllSections()
-		return r.layoutSections(sections, 0)
+		r.layoutSections(f, r.getAllSections(f), 0)
+		f.fitLines(r.config.MaxLines, mergeWidth(r.config), lineStyle(r.config, nil).separator)
+		return
 	}
 
 	// Determine breakpoint level
 	breakpoint := r.getBreakpoint(termWidth)
 
 	// Filter sections by priority based on breakpoint
-	filteredSections := r.filterSectionsByPriority(breakpoint)
-
-	// Layout sections into lines
-	lines := r.layoutSections(filteredSections, termWidth)
+	filteredSections := r.filterSectionsByPriority(f, breakpoint)
 
-	return lines
+	// Layout sections into lines, then fit them into max_lines
+	r.layoutSections(f, filteredSections, termWidth)
+	f.fitLines(r.config.MaxLines, termWidth, lineStyle(r.config, nil).separator)
 }
 
-func (r *ResponsiveRenderer) getAllSections() []registry.Section {
-	var result []registry.Section
+func (r *ResponsiveRenderer) getAllSections(f *frame) []registry.Section {
 	for _, section := range r.sections {
 		if section.Enabled() {
-			result = append(result, section)
+			f.sections = append(f.sections, section)
 		}
 	}
-	return result
+	return f.sections
 }
 
 func (r *ResponsiveRenderer) getBreakpoint(width int) BreakpointLevel {
@@ -80,8 +96,8 @@ func (r *ResponsiveRenderer) getBreakpoint(width int) BreakpointLevel {
 	return BreakpointLarge // 120+ cols
 }
 
-func (r *ResponsiveRenderer) filterSectionsByPriority(level BreakpointLevel) []registry.Section {
-	var result []registry.Section
+func (r *ResponsiveRenderer) filterSectionsByPriority(f *frame, level BreakpointLevel) []registry.Section {
+	result := f.sections
 
 	for _, section := range r.sections {
 		if !section.Enabled() {
@@ -106,90 +122,144 @@ func (r *ResponsiveRenderer) filterSectionsByPriority(level BreakpointLevel) []r
 		}
 	}
 
+	f.sections = result
 	return result
 }
 
-func (r *ResponsiveRenderer) layoutSections(sections []registry.Section, maxWidth int) []string {
+func (r *ResponsiveRenderer) layoutSections(f *frame, sections []registry.Section, maxWidth int) {
 	// Group sections by their configured line
-	lineGroups := r.groupSectionsByLine(sections)
-
-	var lines []string
-
-	for _, group := range lineGroups {
-		line := r.buildLine(group, maxWidth)
-		if line != "" {
-			lines = append(lines, line)
-		}
+	for _, group := range r.groupSectionsByLine(f, sections) {
+		r.buildLine(f, group, maxWidth)
 	}
+}
 
-	return lines
+// lineGroup is the sections shown on one line and the line's configuration,
+// nil when there is no layout
+type lineGroup struct {
+	config   *config.LineConfig
+	sections []registry.Section
 }
 
-func (r *ResponsiveRenderer) groupSectionsByLine(sections []registry.Section) [][]registry.Section {
+func (r *ResponsiveRenderer) groupSectionsByLine(f *frame, sections []registry.Section) []lineGroup {
 	if len(r.config.Layout.Lines) == 0 {
 		// No layout configured, put all sections on one line
-		return [][]registry.Section{sections}
-	}
-
-	// Create a map of section name to section
-	sectionMap := make(map[string]registry.Section)
-	for _, section := range sections {
-		sectionMap[section.Name()] = section
+		f.groups = append(f.groups, lineGroup{sections: sections})
+		return f.groups
 	}
 
 	// Group sections by their configured line
-	var lineGroups [][]registry.Section
-
-	for _, lineConfig := range r.config.Layout.Lines {
-		var group []registry.Section
+	for i := range r.config.Layout.Lines {
+		lineConfig := &r.config.Layout.Lines[i]
+		start := len(f.grouped)
 		for _, sectionName := range lineConfig.Sections {
-			if section, ok := sectionMap[sectionName]; ok {
-				group = append(group, section)
+			if section := findSection(sections, sectionName); section != nil {
+				f.grouped = append(f.grouped, section)
 			}
 		}
-		if len(group) > 0 {
-			lineGroups = append(lineGroups, group)
+		if len(f.grouped) > start {
+			f.groups = append(f.groups, lineGroup{config: lineConfig, sections: f.grouped[start:len(f.grouped):len(f.grouped)]})
 		}
 	}
 
-	return lineGroups
+	return f.groups
 }
 
-func (r *ResponsiveRenderer) buildLine(sections []registry.Section, maxWidth int) string {
-	var parts []string
-	currentWidth := 0
-
+// findSection returns the section named name, or nil
+func findSection(sections []registry.Section, name string) registry.Section {
 	for _, section := range sections {
-		content := section.Render()
-		if content == "" {
+		if section.Name() == name {
+			return section
+		}
+	}
+	return nil
+}
+
+// renderSection renders section into f.section, or its placeholder when it
+// renders nothing
+func (r *ResponsiveRenderer) renderSection(f *frame, section registry.Section) []byte {
+	if r.appendRender == nil {
+		return f.renderSection(r.config, section)
+	}
+	f.section = r.appendRender(f.section[:0], section)
+	return f.section
+}
+
+// buildLine renders a group's sections into a line of the frame, as many
+// as fit within maxWidth. A line set to wrap carries the sections that do
+// not fit over onto continuation lines instead, which max_lines can merge
+// back or drop like any other line
+func (r *ResponsiveRenderer) buildLine(f *frame, group lineGroup, maxWidth int) {
+	var priority registry.Priority
+	style := lineStyle(r.config, group.config)
+	wrap := group.config != nil && group.config.Wrap
+
+	f.line = f.line[:0]
+	for _, section := range group.sections {
+		content := r.renderSection(f, section)
+		if len(content) == 0 {
 			continue
 		}
 
-		contentWidth := len(content) + len(" | ") // Include separator
+		mark := len(f.line)
+		sectionStyle := style.forSection(r.config, section.Name())
+		f.line = appendSection(f.line, sectionStyle, content)
 
 		// Check if we have space (maxWidth of 0 means no limit)
-		if maxWidth > 0 && currentWidth+contentWidth > maxWidth {
-			// Try to fit by truncating or skipping
-			if currentWidth == 0 {
+		if maxWidth > 0 && visibleWidth(f.line) > maxWidth {
+			if wrap && mark > 0 {
+				f.carryOver(mark, priority, sectionStyle, content)
+				priority, mark = registry.PriorityUnset, 0
+			}
+			if visibleWidth(f.line) > maxWidth {
+				// Try to fit by truncating or skipping
+				if mark > 0 {
+					f.line = f.line[:mark]
+					break // Skip this item and the rest
+				}
 				// First item, force fit with truncation
-				parts = append(parts, truncate(content, maxWidth))
+				f.line = truncate(f.line, maxWidth)
+				if !wrap {
+					priority = section.Priority()
+					break
+				}
 			}
-			break // Skip this item
 		}
 
-		parts = append(parts, content)
-		currentWidth += contentWidth
+		priority = morePriority(priority, section.Priority())
 	}
 
-	return strings.Join(parts, " | ")
+	if len(f.line) > 0 {
+		f.addLine(f.line, priority)
+	}
 }
 
-func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
+// truncate shortens b to maxLen columns, ending it with "...". Escape
+// sequences are kept whole, and colors left open are reset
+func truncate(b []byte, maxLen int) []byte {
+	if visibleWidth(b) <= maxLen {
+		return b
 	}
 	if maxLen <= 3 {
-		return "..."
+		return append(b[:0], "..."...)
+	}
+	end, width, styled := 0, 0, false
+	for ; end < len(b); end++ {
+		switch {
+		case b[end] == '\033':
+			end += escapeLength(b[end:]) - 1
+			styled = true
+			continue
+		case !utf8.RuneStart(b[end]):
+			continue
+		}
+		if width == maxLen-3 {
+			break
+		}
+		width++
+	}
+	b = append(b[:end], "..."...)
+	if styled {
+		b = append(b, theme.Reset...)
 	}
-	return s[:maxLen-3] + "..."
+	return b
 }
@@ -3,14 +3,20 @@ package statusline
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"os"
-	"strings"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+	"github.com/ll931217/claude-hud-enhanced/internal/i18n"
 	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/styled"
+	"github.com/ll931217/claude-hud-enhanced/internal/terminal"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
 )
 
 // Statusline manages the rendering of the statusline display
@@ -24,14 +30,33 @@ type Statusline struct {
 	// sections holds the active sections to render
 	sections []registry.Section
 
+	// byName indexes sections by name for layouts
+	byName map[string]registry.Section
+
 	// mu protects concurrent access to sections
 	mu sync.RWMutex
 
 	// done is used to signal shutdown
 	done chan struct{}
 
+	// out receives the rendered lines
+	out io.Writer
+
+	// backend shows the lines on outputs other than a terminal; nil for
+	// the ANSI escapes sections render
+	backend styled.Backend
+
 	// refreshInterval is how often to refresh the display
 	refreshInterval time.Duration
+
+	// budget turns off sections that keep panicking or timing out
+	budget *errorBudget
+
+	// workers collect section output in the background while Run is active
+	workers         map[registry.Section]*sectionWorker
+	workersCtx      context.Context
+	workersInterval time.Duration
+	workersMu       sync.Mutex
 }
 
 // New creates a new Statusline instance
@@ -54,7 +79,10 @@ func New(cfg *config.Config, reg *registry.SectionRegistry) (*Statusline, error)
 		registry:        reg,
 		sections:        make([]registry.Section, 0),
 		done:            make(chan struct{}),
+		out:             os.Stdout,
 		refreshInterval: interval,
+		budget:          newErrorBudget(cfg.ErrorBudget),
+		workers:         make(map[registry.Section]*sectionWorker),
 	}, nil
 }
 
@@ -63,7 +91,7 @@ func (s *Statusline) AddSection(section registry.Section) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.sections = append(s.sections, section)
+	s.sections = append(s.sections, s.withCache(section))
 	s.sortSections()
 }
 
@@ -76,9 +104,12 @@ func (s *Statusline) RemoveSection(name string) {
 	for _, section := range s.sections {
 		if section.Name() != name {
 			newSections = append(newSections, section)
+		} else {
+			s.stopWorker(section)
 		}
 	}
 	s.sections = newSections
+	s.indexSections()
 }
 
 // SetSections replaces all sections with the provided list
@@ -86,11 +117,27 @@ func (s *Statusline) SetSections(sections []registry.Section) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	for _, section := range s.sections {
+		s.stopWorker(section)
+	}
+
 	s.sections = make([]registry.Section, len(sections))
-	copy(s.sections, sections)
+	for i, section := range sections {
+		s.sections[i] = s.withCache(section)
+	}
 	s.sortSections()
 }
 
+// withCache wraps sections that declare a cache TTL so their output is
+// reused between refreshes instead of being recomputed every tick
+func (s *Statusline) withCache(section registry.Section) registry.Section {
+	cacheable, ok := section.(registry.Cacheable)
+	if !ok {
+		return section
+	}
+	return registry.WithCache(section, cacheable.CacheTTL())
+}
+
 // sortSections sorts sections by their order
 func (s *Statusline) sortSections() {
 	// Simple bubble sort for small lists
@@ -102,6 +149,15 @@ func (s *Statusline) sortSections() {
 			}
 		}
 	}
+	s.indexSections()
+}
+
+// indexSections rebuilds the index of sections by name, so renders need not
+func (s *Statusline) indexSections() {
+	s.byName = make(map[string]registry.Section, len(s.sections))
+	for _, section := range s.sections {
+		s.byName[section.Name()] = section
+	}
 }
 
 // Render renders all enabled sections and outputs to stdout
@@ -109,7 +165,8 @@ func (s *Statusline) Render() error {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	var lines []string
+	f := getFrame()
+	defer f.release()
 
 	// Render each section
 	for _, section := range s.sections {
@@ -119,67 +176,126 @@ func (s *Statusline) Render() error {
 		}
 
 		// Render the section with error handling
-		content := s.renderSection(section)
+		f.section = s.appendRender(f.section[:0], section)
 
 		// Skip empty sections
-		if content == "" {
+		if len(f.section) == 0 {
 			continue
 		}
 
-		lines = append(lines, content)
+		f.addLine(f.section, section.Priority())
 	}
 
-	// Output to stdout (for Claude Code statusline API)
-	s.output(lines)
+	// Output to stdout (for Claude Code statusline API), after clearing
+	// the current line
+	s.write(f, "\r\033[K")
 
 	return nil
 }
 
 // renderSection renders a single section with error handling
 func (s *Statusline) renderSection(section registry.Section) string {
-	// Recover from panics during rendering
+	f := getFrame()
+	defer f.release()
+	return string(s.appendRender(f.section, section))
+}
+
+// appendRender appends a section's output to dst with error handling
+// While Run is active this appends the latest result collected in the
+// background, so a slow section shows stale output instead of stalling
+func (s *Statusline) appendRender(dst []byte, section registry.Section) (out []byte) {
+	if s.budget.isDisabled(section.Name()) {
+		return dst
+	}
+	if w := s.ensureWorker(section); w != nil {
+		if content, ok := w.latest(); ok {
+			return append(dst, content...)
+		}
+		// No result collected yet: render this first frame synchronously
+	}
+
+	// Recover from panics during rendering, dropping partial output
+	start := len(dst)
+	done := s.budget.watch(section.Name())
 	defer func() {
+		done()
 		if r := recover(); r != nil {
-			if s.config.Debug {
-				log.Printf("Panic rendering section %s: %v", section.Name(), r)
-			}
+			errors.LogErrorWithLevel(errors.PanicError("section."+section.Name(), r))
+			s.budget.fail(section.Name(), fmt.Sprintf("a panic: %v", r), time.Now())
+			out = dst[:start]
 		}
 	}()
 
 	// Render the section
-	content := section.Render()
+	dst = registry.AppendRender(dst, section)
 
 	// Handle render errors or empty results
-	if content == "" {
-		return ""
+	if len(dst) == start {
+		dst = append(dst, placeholder(s.config, section)...)
 	}
-
-	return content
+	return dst
 }
 
-// output writes the rendered lines to stdout
-func (s *Statusline) output(lines []string) {
-	// Clear previous output using ANSI escape code
-	// Move cursor to beginning of line and clear
-	fmt.Print("\r\033[K")
+// placeholder returns a dimmed marker such as "[status unavailable]" for a
+// section that rendered nothing because its data source failed, so it does
+// not silently vanish. Disabled unless layout.show_placeholders is set
+func placeholder(cfg *config.Config, section registry.Section) string {
+	if cfg == nil || !cfg.Layout.ShowPlaceholders {
+		return ""
+	}
+	health := registry.HealthOf(section)
+	if health.OK() {
+		return ""
+	}
+	return fmt.Sprintf("%s[%s %s]%s", theme.Dim, section.Name(), i18n.T("health."+health.State.String()), theme.Reset)
+}
 
-	// Output each section on its own line
-	for i, line := range lines {
-		if i > 0 {
-			fmt.Println()
-		}
-		fmt.Print(line)
+// write writes prefix and the frame's lines. With a backend other than
+// ANSI, the lines are converted and prefix, which controls the terminal, is
+// left out
+func (s *Statusline) write(f *frame, prefix string) {
+	if s.backend != nil {
+		f.writeStyled(s.out, s.backend)
+	} else {
+		f.writeTo(s.out, prefix)
 	}
 
 	// Ensure the output is displayed immediately
-	os.Stdout.Sync()
+	if file, ok := s.out.(*os.File); ok {
+		file.Sync()
+	}
+}
+
+// SetOutput sets where rendered lines are written; stdout by default
+func (s *Statusline) SetOutput(w io.Writer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.out = w
+}
+
+// SetBackend sets how rendered lines are shown, e.g. as tmux formats or
+// JSON; the ANSI backend, the default, writes them unchanged
+func (s *Statusline) SetBackend(b styled.Backend) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := b.(styled.ANSI); ok {
+		b = nil
+	}
+	s.backend = b
 }
 
 // Run starts the refresh loop
+// Section data is collected by per-section background workers; each tick
+// only assembles the latest completed results
 func (s *Statusline) Run(ctx context.Context) error {
 	ticker := time.NewTicker(s.refreshInterval)
 	defer ticker.Stop()
 
+	workersCtx, cancelWorkers := context.WithCancel(ctx)
+	defer cancelWorkers()
+	s.startWorkers(workersCtx)
+	defer s.stopWorkers()
+
 	// Initial render
 	if err := s.Render(); err != nil {
 		if s.config.Debug {
@@ -246,33 +362,32 @@ func (s *Statusline) RenderStatuslineMode() error {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	// Build section map for responsive renderer
-	sectionMap := make(map[string]registry.Section)
-	for _, section := range s.sections {
-		if section.Enabled() {
-			sectionMap[section.Name()] = section
-		}
-	}
+	f := getFrame()
+	defer f.release()
 
 	// Use responsive renderer if enabled
+	// Every mode but compact mode fits its lines into max_lines
 	if s.config.Layout.Responsive.Enabled {
-		renderer := NewResponsiveRenderer(s.config, sectionMap)
-		lines := renderer.RenderLayout()
-		s.output(lines)
+		renderer := ResponsiveRenderer{config: s.config, sections: s.sections, appendRender: s.appendRender}
+		renderer.render(f)
+		s.addOverlay(f)
+		s.write(f, "\r\033[K")
 		return nil
 	}
 
 	// Check if new layout is configured (non-responsive)
 	if len(s.config.Layout.Lines) > 0 {
-		return s.renderWithLayout(sectionMap)
+		s.renderWithLayout(f)
+		return nil
 	}
 
 	// Fallback to old modes
 	if s.config.CompactMode {
-		return s.renderCompactMode()
+		s.renderCompactMode(f)
+		return nil
 	}
 
-	var lines []string
+	style := lineStyle(s.config, nil)
 
 	// Render each section
 	for _, section := range s.sections {
@@ -282,97 +397,90 @@ func (s *Statusline) RenderStatuslineMode() error {
 		}
 
 		// Render the section with error handling
-		content := s.renderSection(section)
+		f.section = s.appendRender(f.section[:0], section)
 
 		// Skip empty sections
-		if content == "" {
+		if len(f.section) == 0 {
 			continue
 		}
 
-		lines = append(lines, content)
+		f.line = style.forSection(s.config, section.Name()).appendDecorated(f.line[:0], f.section)
+		f.addLine(f.line, section.Priority())
 	}
+	f.fitLines(s.config.MaxLines, mergeWidth(s.config), style.separator)
+	s.addOverlay(f)
 
 	// Output each line on its own line (no ANSI codes for Claude Code)
-	for i, line := range lines {
-		if i > 0 {
-			fmt.Println()
-		}
-		fmt.Print(line)
-	}
-
+	s.write(f, "")
 	return nil
 }
 
 // renderWithLayout renders sections according to configured layout
-func (s *Statusline) renderWithLayout(sectionMap map[string]registry.Section) error {
-	var outputLines []string
+func (s *Statusline) renderWithLayout(f *frame) {
+	// Lines set to wrap break before the section that takes them past the
+	// terminal's width; with the width unknown, nothing wraps
+	width := terminal.AvailableWidth()
 
 	// Render each line according to layout config
-	for _, lineConfig := range s.config.Layout.Lines {
-		var lineParts []string
+	for i := range s.config.Layout.Lines {
+		lineConfig := &s.config.Layout.Lines[i]
+		style := lineStyle(s.config, lineConfig)
+		f.line = f.line[:0]
+		var priority registry.Priority
 		for _, sectionName := range lineConfig.Sections {
-			if section, ok := sectionMap[sectionName]; ok {
-				content := s.renderSection(section)
-				if content != "" {
-					lineParts = append(lineParts, content)
+			section, ok := s.byName[sectionName]
+			if !ok || !section.Enabled() {
+				continue
+			}
+			f.section = s.appendRender(f.section[:0], section)
+			if len(f.section) > 0 {
+				mark := len(f.line)
+				sectionStyle := style.forSection(s.config, sectionName)
+				f.line = appendSection(f.line, sectionStyle, f.section)
+				if lineConfig.Wrap && mark > 0 && width > 0 && visibleWidth(f.line) > width {
+					f.carryOver(mark, priority, sectionStyle, f.section)
+					priority = registry.PriorityUnset
 				}
+				priority = morePriority(priority, section.Priority())
 			}
 		}
 
-		if len(lineParts) > 0 {
-			separator := lineConfig.Separator
-			if separator == "" {
-				separator = " | "
-			}
-			outputLines = append(outputLines, strings.Join(lineParts, separator))
+		if len(f.line) > 0 {
+			f.addLine(f.line, priority)
 		}
 	}
 
-	s.output(outputLines)
-	return nil
+	f.fitLines(s.config.MaxLines, mergeWidth(s.config), lineStyle(s.config, nil).separator)
+	s.addOverlay(f)
+	s.write(f, "\r\033[K")
 }
 
 // renderCompactMode renders sections in compact 2-line mode
-func (s *Statusline) renderCompactMode() error {
-	var line1, line2 []string
-
+func (s *Statusline) renderCompactMode(f *frame) {
 	// Line 1: Session + Beads + Git (project state)
-	for _, section := range s.sections {
-		if !section.Enabled() {
-			continue
-		}
-		switch section.Name() {
-		case "session", "beads", "status":
-			content := s.renderSection(section)
-			if content != "" {
-				line1 = append(line1, content)
-			}
-		}
-	}
-
 	// Line 2: Workspace (environment)
-	for _, section := range s.sections {
-		if !section.Enabled() {
-			continue
-		}
-		if section.Name() == "workspace" {
-			content := s.renderSection(section)
-			if content != "" {
-				line2 = append(line2, content)
+	compactLines := [][]string{{"session", "beads", "status"}, {"workspace"}}
+
+	for _, names := range compactLines {
+		f.line = f.line[:0]
+		for _, section := range s.sections {
+			if !section.Enabled() || !slices.Contains(names, section.Name()) {
+				continue
+			}
+			f.section = s.appendRender(f.section[:0], section)
+			if len(f.section) > 0 {
+				// Output with consistent separator
+				if len(f.line) > 0 {
+					f.line = append(f.line, " | "...)
+				}
+				f.line = append(f.line, f.section...)
 			}
 		}
-	}
-
-	// Output with consistent separator
-	if len(line1) > 0 {
-		fmt.Print(strings.Join(line1, " | "))
-	}
-	if len(line2) > 0 {
-		if len(line1) > 0 {
-			fmt.Println()
+		if len(f.line) > 0 {
+			f.addLine(f.line, registry.PriorityUnset)
 		}
-		fmt.Print(strings.Join(line2, " | "))
 	}
 
-	return nil
+	s.addOverlay(f)
+	s.write(f, "")
 }
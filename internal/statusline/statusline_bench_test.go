@@ -2,17 +2,20 @@ package statusline
 
 import (
 	"context"
+	"io"
 	"testing"
 	"time"
 
 	"github.com/ll931217/claude-hud-enhanced/internal/config"
 	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
 )
 
 func BenchmarkStatusline_Render(b *testing.B) {
 	cfg := config.DefaultConfig()
 	reg := registry.DefaultRegistry()
 	sl, _ := New(cfg, reg)
+	sl.SetOutput(io.Discard)
 
 	ctx := context.Background()
 
@@ -27,6 +30,7 @@ func BenchmarkStatusline_RenderWithSections(b *testing.B) {
 	cfg := config.DefaultConfig()
 	reg := registry.DefaultRegistry()
 	sl, _ := New(cfg, reg)
+	sl.SetOutput(io.Discard)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -38,6 +42,7 @@ func BenchmarkStatusline_RenderSingle(b *testing.B) {
 	cfg := config.DefaultConfig()
 	reg := registry.DefaultRegistry()
 	sl, _ := New(cfg, reg)
+	sl.SetOutput(io.Discard)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -50,6 +55,7 @@ func BenchmarkStatusline_Refresh(b *testing.B) {
 	cfg.RefreshIntervalMs = 100
 	reg := registry.DefaultRegistry()
 	sl, _ := New(cfg, reg)
+	sl.SetOutput(io.Discard)
 
 	ctx := context.Background()
 
@@ -67,6 +73,7 @@ func BenchmarkStatusline_CreateAndRender(b *testing.B) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		sl, _ := New(cfg, reg)
+		sl.SetOutput(io.Discard)
 		_ = sl.Render()
 	}
 }
@@ -104,6 +111,7 @@ func BenchmarkStatusline_Render_WithAllocations(b *testing.B) {
 	cfg := config.DefaultConfig()
 	reg := registry.DefaultRegistry()
 	sl, _ := New(cfg, reg)
+	sl.SetOutput(io.Discard)
 
 	b.ReportAllocs()
 	b.ResetTimer()
@@ -117,6 +125,7 @@ func BenchmarkStatusline_Render_Latency(b *testing.B) {
 	cfg := config.DefaultConfig()
 	reg := registry.DefaultRegistry()
 	sl, _ := New(cfg, reg)
+	sl.SetOutput(io.Discard)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -130,3 +139,49 @@ func BenchmarkStatusline_Render_Latency(b *testing.B) {
 		}
 	}
 }
+
+// warmStatusline returns a statusline with the default layout whose sections
+// have their output ready, as with warm caches, writing to io.Discard
+func warmStatusline(tb testing.TB, cfg *config.Config) *Statusline {
+	sl, err := New(cfg, nil)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	sl.SetOutput(io.Discard)
+	var sections []registry.Section
+	for i, name := range cfg.GetEnabledSections() {
+		sections = append(sections, &MockSection{name: name, enabled: true, order: i, content: theme.Green + name + " 42%" + theme.Reset})
+	}
+	sl.SetSections(sections)
+	return sl
+}
+
+// BenchmarkStatusline_RenderStatuslineMode measures a statusline-mode render
+// with warm caches, in each layout mode. Frames are pooled, so such renders
+// should not allocate, and must stay well under a millisecond
+func BenchmarkStatusline_RenderStatuslineMode(b *testing.B) {
+	modes := map[string]func(*config.Config){
+		"responsive": func(cfg *config.Config) {},
+		"layout":     func(cfg *config.Config) { cfg.Layout.Responsive.Enabled = false },
+		"sections": func(cfg *config.Config) {
+			cfg.Layout.Responsive.Enabled = false
+			cfg.Layout.Lines = nil
+		},
+	}
+	for _, name := range []string{"responsive", "layout", "sections"} {
+		b.Run(name, func(b *testing.B) {
+			cfg := config.DefaultConfig()
+			modes[name](cfg)
+			sl := warmStatusline(b, cfg)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = sl.RenderStatuslineMode()
+			}
+			if perRender := b.Elapsed() / time.Duration(b.N); perRender > time.Millisecond {
+				b.Errorf("render took %v, want under 1ms", perRender)
+			}
+		})
+	}
+}
@@ -2,11 +2,18 @@ package statusline
 
 import (
 	"context"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/crash"
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
 	"github.com/ll931217/claude-hud-enhanced/internal/registry"
+	"github.com/ll931217/claude-hud-enhanced/internal/styled"
+	"github.com/ll931217/claude-hud-enhanced/internal/terminal"
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
 )
 
 // MockSection is a test implementation of registry.Section
@@ -337,3 +344,576 @@ func TestRenderWithNoSections(t *testing.T) {
 		t.Errorf("Render() with no sections should not return error, got: %v", err)
 	}
 }
+
+// flakySection renders "good" once, then panics on every later render
+type flakySection struct {
+	MockSection
+	mu      sync.Mutex
+	renders int
+}
+
+func (f *flakySection) Render() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.renders++
+	if f.renders > 1 {
+		panic("collector failure")
+	}
+	return "good"
+}
+
+func TestBackgroundRefreshKeepsLastKnownGood(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.RefreshIntervalMs = 10
+	cfg.ErrorBudget.Failures = 0 // Keep collecting past the error budget
+	statusline, _ := New(cfg, nil)
+
+	section := &flakySection{MockSection: MockSection{name: "flaky", enabled: true}}
+	statusline.AddSection(section)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	statusline.startWorkers(ctx)
+	defer statusline.stopWorkers()
+
+	// Let several collections panic after the first success
+	time.Sleep(50 * time.Millisecond)
+
+	if got := statusline.renderSection(section); got != "good" {
+		t.Errorf("renderSection() = %q, want last known good %q", got, "good")
+	}
+}
+
+func TestErrorBudget(t *testing.T) {
+	b := newErrorBudget(config.ErrorBudgetConfig{Failures: 2, WindowMs: 60 * 1000, TimeoutMs: 2000})
+	start := time.Now()
+	failures := []struct {
+		after    time.Duration
+		disabled bool
+	}{
+		{0, false},
+		{10 * time.Second, false},
+		{2 * time.Minute, false}, // The first two fell out of the window
+		{2*time.Minute + 10*time.Second, false},
+		{2*time.Minute + 20*time.Second, true},
+	}
+	for _, f := range failures {
+		if got := b.fail("git", "a panic: boom", start.Add(f.after)); got != f.disabled {
+			t.Errorf("fail() after %s = %v, want %v", f.after, got, f.disabled)
+		}
+	}
+	if !b.isDisabled("git") || b.isDisabled("cost") {
+		t.Error("want git turned off and cost left alone")
+	}
+
+	// Renders count once they run past the timeout, even when they hang
+	timed := newErrorBudget(config.ErrorBudgetConfig{Failures: 1, WindowMs: 60 * 1000, TimeoutMs: 10})
+	timed.watch("cost")() // Done in time
+	time.Sleep(30 * time.Millisecond)
+	timed.mu.Lock()
+	quick := len(timed.recent["cost"])
+	timed.mu.Unlock()
+	if quick != 0 {
+		t.Errorf("watch() counted %d failures for a render done in time", quick)
+	}
+	for range 2 {
+		defer timed.watch("cost")() // Never done before the deadline
+	}
+	deadline := time.Now().Add(time.Second)
+	for !timed.isDisabled("cost") {
+		if time.Now().After(deadline) {
+			t.Fatal("hanging renders never turned cost off")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	unlimited := newErrorBudget(config.ErrorBudgetConfig{Failures: 0, WindowMs: 60 * 1000, TimeoutMs: 2000})
+	for range 10 {
+		if unlimited.fail("git", "a panic: boom", start) {
+			t.Fatal("fail() turned a section off with failures set to 0")
+		}
+	}
+}
+
+func TestErrorBudgetRestore(t *testing.T) {
+	b := newErrorBudget(config.ErrorBudgetConfig{Failures: 1, WindowMs: 60 * 1000, TimeoutMs: 2000, DisableMs: 60 * 60 * 1000})
+	now := time.Now()
+	b.restore(&crash.Record{
+		Disabled: map[string]*crash.Disabled{
+			"section.git":  {At: now.Add(-10 * time.Minute), Reason: "2 panics or timeouts in 1m0s, the last a panic: boom"},
+			"section.cost": {At: now.Add(-2 * time.Hour), Reason: "back on by now"},
+			"daemon.jobs":  {At: now, Reason: "not a section"},
+		},
+		Failures: map[string][]time.Time{
+			"section.model": {now.Add(-2 * time.Minute), now.Add(-10 * time.Second)},
+		},
+	}, now)
+
+	if !b.isDisabled("git") || b.isDisabled("cost") || b.isDisabled("daemon.jobs") {
+		t.Errorf("disabled = %v, want git alone", b.disabled)
+	}
+	if got := len(b.recent["model"]); got != 1 {
+		t.Errorf("restored %d failures of model, want the one within the window", got)
+	}
+	if !b.fail("model", "a panic: boom", now) {
+		t.Error("fail() did not count the failure carried over")
+	}
+}
+
+func TestRestoredErrorBudgetSkipsSection(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Layout.Responsive.Enabled = true
+	statusline, _ := New(cfg, nil)
+	statusline.AddSection(&MockSection{name: "model", enabled: true, content: "model output"})
+	statusline.RestoreErrorBudget(&crash.Record{Disabled: map[string]*crash.Disabled{
+		"section.model": {At: time.Now(), Reason: "3 panics or timeouts in 1m0s, the last a panic: boom"},
+	}})
+
+	var out strings.Builder
+	statusline.SetOutput(&out)
+	if err := statusline.RenderStatuslineMode(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "model output") {
+		t.Errorf("output %q shows a section an earlier run turned off", out.String())
+	}
+}
+
+func TestErrorBudgetDisablesSection(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.RefreshIntervalMs = 10
+	cfg.ErrorBudget.Failures = 2
+	statusline, _ := New(cfg, nil)
+
+	section := &flakySection{MockSection: MockSection{name: "flaky", enabled: true}}
+	statusline.AddSection(section)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	statusline.startWorkers(ctx)
+	defer statusline.stopWorkers()
+
+	deadline := time.Now().Add(time.Second)
+	for statusline.DisabledSections()["flaky"] == "" {
+		if time.Now().After(deadline) {
+			t.Fatal("section never turned off")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if reason := statusline.DisabledSections()["flaky"]; !strings.Contains(reason, "3 panics or timeouts") {
+		t.Errorf("reason = %q, want the failures counted", reason)
+	}
+
+	section.mu.Lock()
+	renders := section.renders
+	section.mu.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	if got := statusline.renderSection(section); got != "" {
+		t.Errorf("renderSection() = %q for a section turned off, want nothing", got)
+	}
+	section.mu.Lock()
+	defer section.mu.Unlock()
+	if section.renders != renders {
+		t.Errorf("section rendered %d more times after being turned off", section.renders-renders)
+	}
+}
+
+// slowSection blocks in Render until released
+type slowSection struct {
+	MockSection
+	release chan struct{}
+}
+
+func (s *slowSection) Render() string {
+	<-s.release
+	return "slow"
+}
+
+func TestBackgroundRefreshDoesNotBlockOnSlowSection(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.RefreshIntervalMs = 10
+	statusline, _ := New(cfg, nil)
+
+	slow := &slowSection{MockSection: MockSection{name: "slow", enabled: true}, release: make(chan struct{})}
+	statusline.AddSection(slow)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	statusline.startWorkers(ctx)
+	defer statusline.stopWorkers()
+
+	// Seed a result, then block the next collection indefinitely
+	slow.release <- struct{}{}
+	deadline := time.Now().Add(time.Second)
+	for {
+		w := statusline.ensureWorker(slow)
+		if content, ok := w.latest(); ok && content == "slow" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("worker never produced a result")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	done := make(chan string, 1)
+	go func() { done <- statusline.renderSection(slow) }()
+
+	select {
+	case got := <-done:
+		if got != "slow" {
+			t.Errorf("renderSection() = %q, want stale %q", got, "slow")
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("renderSection blocked on a slow section")
+	}
+	close(slow.release)
+}
+
+// unhealthySection renders nothing and reports an unavailable data source
+type unhealthySection struct {
+	MockSection
+}
+
+func (u *unhealthySection) Health() registry.Health {
+	return registry.Health{State: registry.HealthUnavailable, Reason: "not a git repository"}
+}
+
+func TestPlaceholder(t *testing.T) {
+	unhealthy := &unhealthySection{MockSection{name: "status", enabled: true}}
+	healthy := &MockSection{name: "tools", enabled: true}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		if got := placeholder(config.DefaultConfig(), unhealthy); got != "" {
+			t.Errorf("placeholder() = %q, want empty", got)
+		}
+	})
+
+	cfg := config.DefaultConfig()
+	cfg.Layout.ShowPlaceholders = true
+
+	t.Run("marks unhealthy sections", func(t *testing.T) {
+		got := placeholder(cfg, registry.WithCache(unhealthy, time.Second))
+		if !strings.Contains(got, "[status unavailable]") {
+			t.Errorf("placeholder() = %q, want [status unavailable]", got)
+		}
+	})
+
+	t.Run("healthy sections stay hidden", func(t *testing.T) {
+		if got := placeholder(cfg, healthy); got != "" {
+			t.Errorf("placeholder() = %q, want empty", got)
+		}
+	})
+}
+
+func TestFitLines(t *testing.T) {
+	essential := func(text string) line { return line{text: []byte(text), priority: registry.PriorityEssential} }
+	important := func(text string) line { return line{text: []byte(text), priority: registry.PriorityImportant} }
+	optional := func(text string) line { return line{text: []byte(text), priority: registry.PriorityOptional} }
+
+	tests := []struct {
+		name     string
+		lines    []line
+		maxLines int
+		width    int
+		want     []string
+	}{
+		{
+			name:     "within limit",
+			lines:    []line{essential("model"), optional("sysinfo")},
+			maxLines: 2,
+			width:    80,
+			want:     []string{"model", "sysinfo"},
+		},
+		{
+			name:     "no limit",
+			lines:    []line{essential("a"), optional("b"), optional("c")},
+			maxLines: 0,
+			want:     []string{"a", "b", "c"},
+		},
+		{
+			name:     "least important line merged onto the line above",
+			lines:    []line{essential("model"), optional("tools"), important("git")},
+			maxLines: 2,
+			width:    80,
+			want:     []string{"model | tools", "git"},
+		},
+		{
+			name:     "last of equally important lines goes first",
+			lines:    []line{essential("model"), optional("tools"), optional("sysinfo")},
+			maxLines: 2,
+			width:    80,
+			want:     []string{"model", "tools | sysinfo"},
+		},
+		{
+			name:     "first line merged onto the line below",
+			lines:    []line{optional("tools"), essential("model")},
+			maxLines: 1,
+			width:    80,
+			want:     []string{"tools | model"},
+		},
+		{
+			name:     "dropped when too wide to merge",
+			lines:    []line{essential(strings.Repeat("m", 30)), optional(strings.Repeat("t", 30)), important(strings.Repeat("g", 30))},
+			maxLines: 2,
+			width:    40,
+			want:     []string{strings.Repeat("m", 30), strings.Repeat("g", 30)},
+		},
+		{
+			name:     "merged onto the line below when the line above is full",
+			lines:    []line{essential(strings.Repeat("m", 35)), optional("tools"), important("git")},
+			maxLines: 2,
+			width:    40,
+			want:     []string{strings.Repeat("m", 35), "tools | git"},
+		},
+		{
+			name:     "color codes do not count towards the width",
+			lines:    []line{essential("\033[1mmodel\033[0m"), optional("\033[38;5;40mtools\033[0m")},
+			maxLines: 1,
+			width:    13,
+			want:     []string{"\033[1mmodel\033[0m | \033[38;5;40mtools\033[0m"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &frame{lines: tt.lines}
+			f.fitLines(tt.maxLines, tt.width, " | ")
+			got := f.strings()
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("fitLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayoutWrap(t *testing.T) {
+	terminal.SetWidth(16)
+	t.Cleanup(func() { terminal.SetWidth(0) })
+
+	tests := []struct {
+		name       string
+		responsive bool
+		wrap       bool
+		maxLines   int
+		want       string
+	}{
+		{name: "wrapped at section boundaries", responsive: true, wrap: true, want: "model | tools\n" + theme.Bold + "git" + theme.Reset + " | sysinfo\nwide section ..."},
+		{name: "wrapped without responsive layout", wrap: true, want: "model | tools\n" + theme.Bold + "git" + theme.Reset + " | sysinfo\nwide section that does not fit"},
+		{name: "truncated without wrap", responsive: true, want: "model | tools"},
+		{name: "continuation lines fitted into max_lines", responsive: true, wrap: true, maxLines: 2, want: "model | tools\n" + theme.Bold + "git" + theme.Reset + " | sysinfo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.MaxLines = tt.maxLines
+			cfg.Layout.Responsive = config.ResponsiveConfig{Enabled: tt.responsive}
+			cfg.Layout.Lines = []config.LineConfig{
+				{Sections: []string{"model", "tools", "git", "sysinfo", "wide"}, Wrap: tt.wrap},
+			}
+			sl, _ := New(cfg, nil)
+			var out strings.Builder
+			sl.SetOutput(&out)
+			sl.AddSection(&MockSection{name: "model", enabled: true, order: 1, content: "model"})
+			sl.AddSection(&MockSection{name: "tools", enabled: true, order: 2, content: "tools"})
+			sl.AddSection(&MockSection{name: "git", enabled: true, order: 3, content: theme.Bold + "git" + theme.Reset})
+			sl.AddSection(&MockSection{name: "sysinfo", enabled: true, order: 4, content: "sysinfo"})
+			sl.AddSection(&MockSection{name: "wide", enabled: true, order: 5, content: "wide section that does not fit"})
+
+			if err := sl.RenderStatuslineMode(); err != nil {
+				t.Fatalf("RenderStatuslineMode() error = %v", err)
+			}
+			if got := strings.TrimPrefix(out.String(), "\r\033[K"); got != tt.want {
+				t.Errorf("RenderStatuslineMode() wrote %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetBackend(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Layout.Lines = []config.LineConfig{
+		{Sections: []string{"model", "context"}},
+		{Sections: []string{"status"}},
+	}
+	tests := []struct {
+		backend styled.Backend
+		want    string
+	}{
+		{styled.ANSI{}, "\r\033[K" + theme.Bold + "Opus" + theme.Reset + " | " + theme.Red + "95%" + theme.Reset + "\nmain #4"},
+		{styled.Tmux{}, "#[bold]Opus#[default] | #[fg=colour203]95%#[default]\nmain ##4"},
+		{styled.JSON{}, `[{"text":"Opus","bold":true},{"text":" | "},{"text":"95%","color":"error"}]` + "\n" + `[{"text":"main #4"}]`},
+	}
+	for _, tt := range tests {
+		sl, _ := New(cfg, nil)
+		var out strings.Builder
+		sl.SetOutput(&out)
+		sl.SetBackend(tt.backend)
+		sl.AddSection(&MockSection{name: "model", enabled: true, order: 1, content: theme.Bold + "Opus" + theme.Reset})
+		sl.AddSection(&MockSection{name: "context", enabled: true, order: 2, content: theme.Red + "95%" + theme.Reset})
+		sl.AddSection(&MockSection{name: "status", enabled: true, order: 3, content: "main #4"})
+
+		if err := sl.RenderStatuslineMode(); err != nil {
+			t.Fatalf("RenderStatuslineMode() error = %v", err)
+		}
+		if got := out.String(); got != tt.want {
+			t.Errorf("%s: RenderStatuslineMode() wrote %q, want %q", tt.backend.Name(), got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in     string
+		maxLen int
+		want   string
+	}{
+		{"model", 10, "model"},
+		{"context window", 10, "context..."},
+		{theme.Bold + "context window" + theme.Reset, 10, theme.Bold + "context..." + theme.Reset},
+		{"█████░░░░░ 50%", 8, "█████..."},
+		{"model", 2, "..."},
+	}
+	for _, tt := range tests {
+		if got := string(truncate([]byte(tt.in), tt.maxLen)); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.in, tt.maxLen, got, tt.want)
+		}
+	}
+}
+
+func TestVisibleWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"", 0},
+		{"model", 5},
+		{"█████░░░░░ 50%", 14},
+		{"\033[38;5;203mred\033[0m", 3},
+		{"\033]8;;https://example.com\033\\link\033]8;;\033\\", 4},
+	}
+	for _, tt := range tests {
+		if got := visibleWidth(tt.s); got != tt.want {
+			t.Errorf("visibleWidth(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestSectionStyle(t *testing.T) {
+	padding := 0
+	cfg := config.DefaultConfig()
+	cfg.Layout.Separator = " · "
+	cfg.Layout.Decoration = "brackets"
+	cfg.Layout.Padding = 1
+	cfg.Layout.Lines = []config.LineConfig{
+		{Sections: []string{"model", "git"}},
+		{Sections: []string{"tools", "cost"}, Separator: " ", Decoration: "⟨ ⟩", Padding: &padding},
+	}
+	cfg.Sections = config.SectionsConfig{
+		"git":  {"decoration": "none", "padding": 0, "separator": " — "},
+		"cost": {"decoration": "pill"},
+	}
+
+	build := func(lineCfg *config.LineConfig, names ...string) string {
+		style := lineStyle(cfg, lineCfg)
+		var text []byte
+		for _, name := range names {
+			text = appendSection(text, style.forSection(cfg, name), []byte(name))
+		}
+		return string(text)
+	}
+
+	if got, want := build(&cfg.Layout.Lines[0], "model", "git"), "[ model ] — git"; got != want {
+		t.Errorf("first line = %q, want %q", got, want)
+	}
+	if got, want := build(&cfg.Layout.Lines[1], "tools"), "⟨tools⟩"; got != want {
+		t.Errorf("second line = %q, want %q", got, want)
+	}
+	if got, want := build(nil, "a", "b"), "[ a ] · [ b ]"; got != want {
+		t.Errorf("without a line = %q, want %q", got, want)
+	}
+
+	pill := lineStyle(cfg, &cfg.Layout.Lines[1]).forSection(cfg, "cost").decorate("\033[1m$1\033[0m")
+	want := "\033[7m\033[1m$1\033[0m\033[7m\033[27m"
+	if pill != want {
+		t.Errorf("pill = %q, want %q", pill, want)
+	}
+
+	if got := lineStyle(config.DefaultConfig(), nil).separator; got != " | " {
+		t.Errorf("default separator = %q, want \" | \"", got)
+	}
+}
+
+func TestDebugOverlay(t *testing.T) {
+	if got := debugOverlay(nil, 80); got != "" {
+		t.Errorf("debugOverlay(nil) = %q, want empty", got)
+	}
+
+	entries := []errors.Entry{
+		{Level: errors.LevelWarn, Op: "git", Message: "status timed\nout"},
+		{Level: errors.LevelError, Op: "beads", Message: "database locked"},
+	}
+	got := debugOverlay(entries, 80)
+	want := theme.Dim + "⚠ git: status timed out · ✗ beads: database locked" + theme.Reset
+	if got != want {
+		t.Errorf("debugOverlay() = %q, want %q", got, want)
+	}
+
+	if got := debugOverlay(entries, 20); visibleWidth(got) != 20 || !strings.HasSuffix(got, "…"+theme.Reset) {
+		t.Errorf("debugOverlay() at width 20 = %q", got)
+	}
+}
+
+// describedSection is a section that describes itself for screen readers
+type describedSection struct {
+	MockSection
+	description string
+}
+
+func (d *describedSection) Describe() string {
+	return d.description
+}
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		rendered string
+		want     string
+	}{
+		{rendered: theme.Yellow + "███░░ 72%" + theme.Reset, want: "72 percent"},
+		{rendered: "CPU 4.5% · RAM 12%", want: "CPU 4.5 percent · RAM 12 percent"},
+		{rendered: theme.Red + theme.CriticalMarker + "95%" + theme.Reset, want: "critical 95 percent"},
+		{rendered: theme.Hyperlink("https://example.com", "CI ✓"), want: "CI ✓"},
+		{rendered: "⠋ Bash go test", want: "Bash go test"},
+		{rendered: "⚠️ slow", want: "warning slow"},
+	}
+	for _, tt := range tests {
+		if got := plainText(tt.rendered); got != tt.want {
+			t.Errorf("plainText(%q) = %q, want %q", tt.rendered, got, tt.want)
+		}
+	}
+}
+
+func TestRenderPlain(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.MaxLines = 0
+	cfg.Layout.Lines = []config.LineConfig{
+		{Sections: []string{"model", "contextbar", "empty"}},
+		{Sections: []string{"status"}},
+	}
+	sl, _ := New(cfg, nil)
+	var out strings.Builder
+	sl.SetOutput(&out)
+
+	sl.AddSection(&MockSection{name: "model", enabled: true, order: 1, content: theme.Bold + "Opus" + theme.Reset})
+	sl.AddSection(&describedSection{MockSection{name: "contextbar", enabled: true, order: 2, content: "███░ 72%"}, "context: 72 percent, warning"})
+	sl.AddSection(&MockSection{name: "empty", enabled: true, order: 3})
+	sl.AddSection(&MockSection{name: "status", enabled: true, order: 4, content: "main " + theme.Red + "‼ 1 conflict" + theme.Reset})
+
+	if err := sl.RenderPlain(); err != nil {
+		t.Fatalf("RenderPlain() error = %v", err)
+	}
+	want := "model: Opus; context: 72 percent, warning\nstatus: main critical 1 conflict"
+	if got := out.String(); got != want {
+		t.Errorf("RenderPlain() wrote %q, want %q", got, want)
+	}
+}
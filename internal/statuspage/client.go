@@ -0,0 +1,220 @@
+// Package statuspage polls the Anthropic status page for incidents affecting
+// the Claude API, so outages can be told apart from local problems
+package statuspage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/filecache"
+)
+
+const (
+	// DefaultURL is Anthropic's Statuspage summary endpoint
+	DefaultURL = "https://status.anthropic.com/api/v2/summary.json"
+
+	// DefaultInterval is how long a fetched summary is reused
+	// The status page changes rarely; polling it often only adds load
+	DefaultInterval = 5 * time.Minute
+
+	timeout = 3 * time.Second
+)
+
+// DefaultComponents match the status page components relevant to Claude Code
+var DefaultComponents = []string{"Claude API", "Claude Code"}
+
+// cachedReport is the on-disk cache shared between statusline processes
+type cachedReport struct {
+	Report    Report    `json:"report"`
+	CheckedAt time.Time `json:"checked_at"` // Last attempt, successful or not
+}
+
+// Client fetches and caches the status page summary
+type Client struct {
+	URL        string
+	Interval   time.Duration
+	Components []string // Component name substrings to report on
+	CachePath  string   // Optional file caching the last report
+
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	cached  cachedReport
+	lastErr error
+}
+
+// NewClient creates a status page client with the default URL and interval
+func NewClient(cachePath string) *Client {
+	return &Client{
+		URL:        DefaultURL,
+		Interval:   DefaultInterval,
+		Components: DefaultComponents,
+		CachePath:  cachePath,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Fetch returns the current report, fetching the summary at most once per Interval
+// Failed attempts also wait out the interval, so an unreachable status page
+// does not slow down every render. On failure the last known report is kept
+func (c *Client) Fetch(ctx context.Context) (Report, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cached.CheckedAt.IsZero() {
+		c.cached = c.readCache()
+	}
+	if !c.cached.CheckedAt.IsZero() && time.Since(c.cached.CheckedAt) < c.Interval {
+		return c.cached.Report, c.lastErr
+	}
+
+	now := time.Now()
+	c.cached.CheckedAt = now
+	summary, err := c.fetchSummary(ctx)
+	if err != nil {
+		c.lastErr = err
+		c.writeCache(c.cached)
+		return c.cached.Report, err
+	}
+	c.lastErr = nil
+
+	report := Evaluate(summary, c.Components)
+	report.FetchedAt = now
+	c.cached.Report = report
+	c.writeCache(c.cached)
+	return report, nil
+}
+
+// Configure sets the poll interval and component filters
+// Zero or empty values keep the current settings
+func (c *Client) Configure(interval time.Duration, components []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if interval > 0 {
+		c.Interval = interval
+	}
+	if len(components) > 0 {
+		c.Components = components
+	}
+}
+
+// Err returns the error from the last fetch attempt, if any
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastErr
+}
+
+// fetchSummary downloads and decodes summary.json
+func (c *Client) fetchSummary(ctx context.Context) (*Summary, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status page returned status %d", resp.StatusCode)
+	}
+
+	var summary Summary
+	if err := json.NewDecoder(resp.Body).Decode(&summary); err != nil {
+		return nil, fmt.Errorf("failed to parse status page: %w", err)
+	}
+	return &summary, nil
+}
+
+// Evaluate picks the most severe incident affecting the given components
+// Without a matching incident, a degraded matching component is reported instead
+func Evaluate(summary *Summary, components []string) Report {
+	var report Report
+	for _, incident := range summary.Incidents {
+		if !incident.Affects(components) {
+			continue
+		}
+		if severity(incident.Impact) > severity(report.Impact) {
+			report = Report{Title: incident.Name, Impact: incident.Impact, Link: incident.Shortlink}
+		}
+	}
+	if report.Active() {
+		return report
+	}
+
+	for _, component := range summary.Components {
+		if !matchesAny(component.Name, components) {
+			continue
+		}
+		impact := componentImpact(component.Status)
+		if severity(impact) > severity(report.Impact) {
+			report = Report{Title: component.Name + " " + strings.ReplaceAll(component.Status, "_", " "), Impact: impact}
+		}
+	}
+	return report
+}
+
+// severity orders impact levels; unknown values rank as minor
+func severity(impact string) int {
+	switch impact {
+	case "", ImpactNone:
+		return 0
+	case ImpactMaintenance:
+		return 1
+	case ImpactMinor:
+		return 2
+	case ImpactMajor:
+		return 3
+	case ImpactCritical:
+		return 4
+	}
+	return 2
+}
+
+// componentImpact maps a component status to an incident impact level
+func componentImpact(status string) string {
+	switch status {
+	case ComponentDegradedPerformance:
+		return ImpactMinor
+	case ComponentPartialOutage:
+		return ImpactMajor
+	case ComponentMajorOutage:
+		return ImpactCritical
+	case ComponentUnderMaintenance:
+		return ImpactMaintenance
+	}
+	return ImpactNone
+}
+
+// matchesAny reports whether name contains any filter, ignoring case
+// An empty filter list matches everything
+func matchesAny(name string, filters []string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	lower := strings.ToLower(name)
+	for _, f := range filters {
+		if strings.Contains(lower, strings.ToLower(f)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) readCache() cachedReport {
+	var result cachedReport
+	filecache.Load(c.CachePath, &result)
+	return result
+}
+
+func (c *Client) writeCache(result cachedReport) {
+	_ = filecache.Save(c.CachePath, result)
+}
@@ -0,0 +1,175 @@
+package statuspage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const summaryJSON = `{
+  "status": {"indicator": "minor", "description": "Minor Service Outage"},
+  "components": [
+    {"name": "claude.ai", "status": "operational"},
+    {"name": "Claude API (api.anthropic.com)", "status": "degraded_performance"},
+    {"name": "Claude Code", "status": "operational"}
+  ],
+  "incidents": [
+    {
+      "name": "Elevated errors on claude.ai",
+      "status": "investigating",
+      "impact": "major",
+      "shortlink": "https://stspg.io/a",
+      "components": [{"name": "claude.ai", "status": "major_outage"}]
+    },
+    {
+      "name": "Elevated API latency",
+      "status": "identified",
+      "impact": "minor",
+      "shortlink": "https://stspg.io/b",
+      "components": [{"name": "Claude API (api.anthropic.com)", "status": "degraded_performance"}]
+    }
+  ]
+}`
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name       string
+		summary    Summary
+		components []string
+		wantTitle  string
+		wantImpact string
+	}{
+		{
+			name:       "all operational",
+			summary:    Summary{Components: []Component{{Name: "Claude API", Status: ComponentOperational}}},
+			components: DefaultComponents,
+		},
+		{
+			name: "incident on watched component",
+			summary: Summary{Incidents: []Incident{
+				{Name: "API errors", Impact: ImpactMajor, Components: []Component{{Name: "Claude API"}}},
+			}},
+			components: DefaultComponents,
+			wantTitle:  "API errors",
+			wantImpact: ImpactMajor,
+		},
+		{
+			name: "incident on other component is ignored",
+			summary: Summary{Incidents: []Incident{
+				{Name: "Console down", Impact: ImpactCritical, Components: []Component{{Name: "Console"}}},
+			}},
+			components: DefaultComponents,
+		},
+		{
+			name: "incident without components applies",
+			summary: Summary{Incidents: []Incident{
+				{Name: "Widespread outage", Impact: ImpactCritical},
+			}},
+			components: DefaultComponents,
+			wantTitle:  "Widespread outage",
+			wantImpact: ImpactCritical,
+		},
+		{
+			name: "most severe incident wins",
+			summary: Summary{Incidents: []Incident{
+				{Name: "Slow", Impact: ImpactMinor},
+				{Name: "Down", Impact: ImpactCritical},
+				{Name: "Errors", Impact: ImpactMajor},
+			}},
+			wantTitle:  "Down",
+			wantImpact: ImpactCritical,
+		},
+		{
+			name:       "degraded component without incident",
+			summary:    Summary{Components: []Component{{Name: "Claude Code", Status: ComponentPartialOutage}}},
+			components: DefaultComponents,
+			wantTitle:  "Claude Code partial outage",
+			wantImpact: ImpactMajor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Evaluate(&tt.summary, tt.components)
+			if report.Title != tt.wantTitle || report.Impact != tt.wantImpact {
+				t.Errorf("Evaluate() = %q/%q, want %q/%q", report.Title, report.Impact, tt.wantTitle, tt.wantImpact)
+			}
+		})
+	}
+}
+
+func TestClient_Fetch(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(summaryJSON))
+	}))
+	defer server.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "statuspage.json")
+	client := NewClient(cachePath)
+	client.URL = server.URL
+
+	report, err := client.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if report.Title != "Elevated API latency" || report.Impact != ImpactMinor || report.Link != "https://stspg.io/b" {
+		t.Errorf("Fetch() = %+v, want the API latency incident", report)
+	}
+
+	// Within the interval the cached report is reused
+	if _, err := client.Fetch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+
+	// A new client, as in the next statusline process, reads the cache file
+	other := NewClient(cachePath)
+	other.URL = server.URL
+	if report, _ := other.Fetch(context.Background()); report.Title != "Elevated API latency" {
+		t.Errorf("cached Fetch() title = %q", report.Title)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("requests after cache read = %d, want 1", got)
+	}
+}
+
+func TestClient_FetchError(t *testing.T) {
+	var fail atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(summaryJSON))
+	}))
+	defer server.Close()
+
+	client := NewClient("")
+	client.URL = server.URL
+	if _, err := client.Fetch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Once the interval passes, a failed fetch keeps the last report
+	fail.Store(true)
+	client.Configure(time.Nanosecond, nil)
+	time.Sleep(time.Millisecond)
+	report, err := client.Fetch(context.Background())
+	if err == nil {
+		t.Fatal("Fetch() expected error for 503")
+	}
+	if client.Err() == nil {
+		t.Error("Err() = nil after failed fetch")
+	}
+	if report.Title != "Elevated API latency" {
+		t.Errorf("Fetch() after failure = %q, want last known report", report.Title)
+	}
+}
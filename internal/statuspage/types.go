@@ -0,0 +1,74 @@
+package statuspage
+
+import "time"
+
+// Impact levels used by Statuspage incidents, from least to most severe
+const (
+	ImpactNone        = "none"
+	ImpactMaintenance = "maintenance"
+	ImpactMinor       = "minor"
+	ImpactMajor       = "major"
+	ImpactCritical    = "critical"
+)
+
+// Component statuses reported by Statuspage
+const (
+	ComponentOperational         = "operational"
+	ComponentDegradedPerformance = "degraded_performance"
+	ComponentPartialOutage       = "partial_outage"
+	ComponentMajorOutage         = "major_outage"
+	ComponentUnderMaintenance    = "under_maintenance"
+)
+
+// Summary is the subset of a Statuspage /api/v2/summary.json response we use
+type Summary struct {
+	Status struct {
+		Indicator   string `json:"indicator"`
+		Description string `json:"description"`
+	} `json:"status"`
+	Components []Component `json:"components"`
+	Incidents  []Incident  `json:"incidents"`
+}
+
+// Component is a service listed on the status page
+type Component struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// Incident is an unresolved incident on the status page
+type Incident struct {
+	Name       string      `json:"name"`
+	Status     string      `json:"status"` // investigating, identified, monitoring
+	Impact     string      `json:"impact"`
+	Shortlink  string      `json:"shortlink"`
+	Components []Component `json:"components"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+// Affects reports whether the incident involves a component matching one of
+// the name filters. Incidents without components are assumed to affect everything
+func (i Incident) Affects(filters []string) bool {
+	if len(i.Components) == 0 {
+		return true
+	}
+	for _, c := range i.Components {
+		if matchesAny(c.Name, filters) {
+			return true
+		}
+	}
+	return false
+}
+
+// Report is what the section shows: the most severe active problem, if any
+type Report struct {
+	Title     string    // Incident name or "<component> degraded"
+	Impact    string    // One of the Impact* constants
+	Link      string    // Incident shortlink, when known
+	FetchedAt time.Time // When the summary was fetched
+}
+
+// Active reports whether there is a problem to show
+func (r Report) Active() bool {
+	return r.Impact != "" && r.Impact != ImpactNone
+}
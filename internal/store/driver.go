@@ -0,0 +1,5 @@
+package store
+
+// The SQLite driver is built into every binary: modernc.org/sqlite is
+// cgo-free, which keeps cross-compiled release builds working
+import _ "modernc.org/sqlite"
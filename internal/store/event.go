@@ -0,0 +1,107 @@
+package store
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+// Event kinds stored in the events table
+const (
+	KindMessage    = "message"     // Assistant message carrying token usage
+	KindToolUse    = "tool_use"    // Tool invocation
+	KindToolResult = "tool_result" // Tool result (success or error)
+)
+
+// Event is a normalized transcript event
+type Event struct {
+	SessionID           string    `json:"session_id"`
+	Timestamp           time.Time `json:"timestamp"`
+	Kind                string    `json:"kind"`
+	ToolName            string    `json:"tool_name,omitempty"`
+	ToolUseID           string    `json:"tool_use_id,omitempty"`
+	Target              string    `json:"target,omitempty"`
+	Model               string    `json:"model,omitempty"`
+	InputTokens         int       `json:"input_tokens,omitempty"`
+	OutputTokens        int       `json:"output_tokens,omitempty"`
+	CacheCreationTokens int       `json:"cache_creation_tokens,omitempty"`
+	CacheReadTokens     int       `json:"cache_read_tokens,omitempty"`
+	Cost                float64   `json:"cost,omitempty"`
+	IsError             bool      `json:"is_error,omitempty"`
+}
+
+// SessionIDFromPath derives the session ID from a transcript path
+// Claude Code names transcripts <session-id>.jsonl; archives add .gz
+func SessionIDFromPath(transcriptPath string) string {
+	name := strings.TrimSuffix(filepath.Base(transcriptPath), transcript.ArchiveExt)
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// DecodeLine normalizes one transcript line into zero or more events
+// Lines that are not messages (summaries, metadata) yield no events
+func DecodeLine(sessionID string, line []byte) []Event {
+	var ccLine transcript.ClaudeCodeTranscriptLine
+	if err := json.Unmarshal(line, &ccLine); err != nil || ccLine.Message == nil {
+		return nil
+	}
+
+	var ts time.Time
+	if ccLine.Timestamp != "" {
+		if t, err := time.Parse(time.RFC3339Nano, ccLine.Timestamp); err == nil {
+			ts = t
+		}
+	}
+
+	var events []Event
+	msg := ccLine.Message
+
+	if usage := msg.Usage; usage != nil && msg.Role == "assistant" {
+		events = append(events, Event{
+			SessionID:           sessionID,
+			Timestamp:           ts,
+			Kind:                KindMessage,
+			Model:               msg.Model,
+			InputTokens:         usage.InputTokens,
+			OutputTokens:        usage.OutputTokens,
+			CacheCreationTokens: usage.CacheCreationInputTokens,
+			CacheReadTokens:     usage.CacheReadInputTokens,
+			Cost:                transcript.EstimateCost(msg.Model, usage.InputTokens, usage.OutputTokens),
+		})
+	}
+
+	for _, block := range msg.Content {
+		switch block.Type {
+		case "tool_use":
+			if block.Name == "" {
+				continue
+			}
+			var input map[string]interface{}
+			_ = json.Unmarshal(block.Input, &input)
+			events = append(events, Event{
+				SessionID: sessionID,
+				Timestamp: ts,
+				Kind:      KindToolUse,
+				ToolName:  block.Name,
+				ToolUseID: block.ID,
+				Target:    transcript.ToolTarget(block.Name, input),
+				Model:     msg.Model,
+			})
+		case "tool_result":
+			if block.ToolUseID == "" {
+				continue
+			}
+			events = append(events, Event{
+				SessionID: sessionID,
+				Timestamp: ts,
+				Kind:      KindToolResult,
+				ToolUseID: block.ToolUseID,
+				IsError:   block.IsError,
+			})
+		}
+	}
+
+	return events
+}
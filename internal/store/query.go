@@ -0,0 +1,222 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Filter restricts queries to a session and/or time range
+// Zero values mean "no restriction"
+type Filter struct {
+	SessionID string
+	Since     time.Time
+	Until     time.Time
+}
+
+// Match reports whether an event passes the filter
+func (f Filter) Match(ev Event) bool {
+	if f.SessionID != "" && ev.SessionID != f.SessionID {
+		return false
+	}
+	if !f.Since.IsZero() && ev.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !ev.Timestamp.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// where builds a WHERE clause (including the keyword) and its arguments
+func (f Filter) where(extra ...string) (string, []interface{}) {
+	conds := append([]string(nil), extra...)
+	var args []interface{}
+	if f.SessionID != "" {
+		conds = append(conds, "session_id = ?")
+		args = append(args, f.SessionID)
+	}
+	if !f.Since.IsZero() {
+		conds = append(conds, "ts >= ?")
+		args = append(args, f.Since.UnixMilli())
+	}
+	if !f.Until.IsZero() {
+		conds = append(conds, "ts < ?")
+		args = append(args, f.Until.UnixMilli())
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
+
+// ToolCount is the number of calls and errors for a tool
+type ToolCount struct {
+	Name   string
+	Calls  int
+	Errors int
+}
+
+// TokenBucket is token usage and cost aggregated over one hour
+type TokenBucket struct {
+	Hour                time.Time
+	InputTokens         int
+	OutputTokens        int
+	CacheCreationTokens int
+	CacheReadTokens     int
+	Cost                float64
+}
+
+// SessionSummary describes one ingested session
+type SessionSummary struct {
+	ID             string
+	TranscriptPath string
+	Project        string
+	FirstEventAt   time.Time
+	LastEventAt    time.Time
+	Messages       int
+	ToolCalls      int
+	Cost           float64
+}
+
+// ToolCounts returns per-tool call and error counts, most used first
+func (s *Store) ToolCounts(ctx context.Context, f Filter) ([]ToolCount, error) {
+	where, args := f.where("kind = '" + KindToolUse + "'")
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT u.tool_name, COUNT(*),
+			SUM(CASE WHEN EXISTS (
+				SELECT 1 FROM events r
+				WHERE r.kind = '`+KindToolResult+`' AND r.is_error = 1
+					AND r.session_id = u.session_id AND r.tool_use_id = u.tool_use_id
+			) THEN 1 ELSE 0 END)
+		FROM events u `+where+`
+		GROUP BY u.tool_name
+		ORDER BY COUNT(*) DESC, u.tool_name`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tool counts: %w", err)
+	}
+	defer rows.Close()
+
+	var result []ToolCount
+	for rows.Next() {
+		var tc ToolCount
+		if err := rows.Scan(&tc.Name, &tc.Calls, &tc.Errors); err != nil {
+			return nil, fmt.Errorf("failed to scan tool count: %w", err)
+		}
+		result = append(result, tc)
+	}
+	return result, rows.Err()
+}
+
+// HourlyTokens returns token usage and cost grouped by hour (UTC), oldest first
+func (s *Store) HourlyTokens(ctx context.Context, f Filter) ([]TokenBucket, error) {
+	where, args := f.where("kind = '" + KindMessage + "'")
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT (ts / 3600000) * 3600000 AS hour,
+			SUM(input_tokens), SUM(output_tokens),
+			SUM(cache_creation_tokens), SUM(cache_read_tokens), SUM(cost)
+		FROM events `+where+`
+		GROUP BY hour
+		ORDER BY hour`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query hourly tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var result []TokenBucket
+	for rows.Next() {
+		var b TokenBucket
+		var hour int64
+		if err := rows.Scan(&hour, &b.InputTokens, &b.OutputTokens,
+			&b.CacheCreationTokens, &b.CacheReadTokens, &b.Cost); err != nil {
+			return nil, fmt.Errorf("failed to scan token bucket: %w", err)
+		}
+		b.Hour = time.UnixMilli(hour).UTC()
+		result = append(result, b)
+	}
+	return result, rows.Err()
+}
+
+// TotalCost returns the estimated cost of all matching usage
+func (s *Store) TotalCost(ctx context.Context, f Filter) (float64, error) {
+	where, args := f.where("kind = '" + KindMessage + "'")
+	var cost sql.NullFloat64
+	if err := s.db.QueryRowContext(ctx, `SELECT SUM(cost) FROM events `+where, args...).Scan(&cost); err != nil {
+		return 0, fmt.Errorf("failed to query cost: %w", err)
+	}
+	return cost.Float64, nil
+}
+
+// Sessions returns summaries of sessions with events in the filter range,
+// most recently active first
+func (s *Store) Sessions(ctx context.Context, f Filter) ([]SessionSummary, error) {
+	where, args := f.where()
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT s.id, s.transcript_path, COALESCE(s.project, ''),
+			COALESCE(s.first_event_at, 0), COALESCE(s.last_event_at, 0),
+			e.messages, e.tool_calls, e.cost
+		FROM sessions s
+		JOIN (
+			SELECT session_id,
+				SUM(CASE WHEN kind = '`+KindMessage+`' THEN 1 ELSE 0 END) AS messages,
+				SUM(CASE WHEN kind = '`+KindToolUse+`' THEN 1 ELSE 0 END) AS tool_calls,
+				SUM(cost) AS cost
+			FROM events `+where+`
+			GROUP BY session_id
+		) e ON e.session_id = s.id
+		ORDER BY s.last_event_at DESC`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var result []SessionSummary
+	for rows.Next() {
+		var ss SessionSummary
+		var first, last int64
+		if err := rows.Scan(&ss.ID, &ss.TranscriptPath, &ss.Project, &first, &last,
+			&ss.Messages, &ss.ToolCalls, &ss.Cost); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		if first > 0 {
+			ss.FirstEventAt = time.UnixMilli(first)
+		}
+		if last > 0 {
+			ss.LastEventAt = time.UnixMilli(last)
+		}
+		result = append(result, ss)
+	}
+	return result, rows.Err()
+}
+
+// Events returns normalized events matching the filter in timestamp order
+func (s *Store) Events(ctx context.Context, f Filter) ([]Event, error) {
+	where, args := f.where()
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT session_id, ts, kind, COALESCE(tool_name, ''), COALESCE(tool_use_id, ''),
+			COALESCE(target, ''), COALESCE(model, ''),
+			input_tokens, output_tokens, cache_creation_tokens, cache_read_tokens, cost, is_error
+		FROM events `+where+`
+		ORDER BY ts, id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	var result []Event
+	for rows.Next() {
+		var ev Event
+		var ts int64
+		if err := rows.Scan(&ev.SessionID, &ts, &ev.Kind, &ev.ToolName, &ev.ToolUseID, &ev.Target, &ev.Model,
+			&ev.InputTokens, &ev.OutputTokens, &ev.CacheCreationTokens, &ev.CacheReadTokens, &ev.Cost, &ev.IsError); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		if ts > 0 {
+			ev.Timestamp = time.UnixMilli(ts)
+		}
+		result = append(result, ev)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,304 @@
+// Package store provides an optional SQLite index of transcript events
+// It ingests transcripts incrementally and answers aggregate queries without
+// rescanning JSONL files
+package store
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/session"
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+// driverName is the database/sql driver registered by modernc.org/sqlite
+const driverName = "sqlite"
+
+// DatabaseFileName is the store's file name inside the state directory
+const DatabaseFileName = "sessions.db"
+
+// maxLineSize bounds a single transcript line during ingestion
+const maxLineSize = 1024 * 1024
+
+const schema = `
+CREATE TABLE IF NOT EXISTS sessions (
+	id              TEXT PRIMARY KEY,
+	transcript_path TEXT NOT NULL,
+	project         TEXT,
+	first_event_at  INTEGER,
+	last_event_at   INTEGER
+);
+
+CREATE TABLE IF NOT EXISTS ingest_offsets (
+	transcript_path TEXT PRIMARY KEY,
+	byte_offset     INTEGER NOT NULL,
+	mtime           INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS events (
+	id                    INTEGER PRIMARY KEY AUTOINCREMENT,
+	session_id            TEXT NOT NULL,
+	ts                    INTEGER NOT NULL,
+	kind                  TEXT NOT NULL,
+	tool_name             TEXT,
+	tool_use_id           TEXT,
+	target                TEXT,
+	model                 TEXT,
+	input_tokens          INTEGER NOT NULL DEFAULT 0,
+	output_tokens         INTEGER NOT NULL DEFAULT 0,
+	cache_creation_tokens INTEGER NOT NULL DEFAULT 0,
+	cache_read_tokens     INTEGER NOT NULL DEFAULT 0,
+	cost                  REAL NOT NULL DEFAULT 0,
+	is_error              INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_events_session ON events(session_id, ts);
+CREATE INDEX IF NOT EXISTS idx_events_ts ON events(ts);
+CREATE INDEX IF NOT EXISTS idx_events_kind ON events(kind, tool_name);
+`
+
+// Store is a SQLite-backed event store
+type Store struct {
+	db *sql.DB
+}
+
+// DefaultPath returns the default database location in the state directory
+func DefaultPath() (string, error) {
+	dir, err := session.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, DatabaseFileName), nil
+}
+
+// Open opens (creating if necessary) the store at path
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	db, err := sql.Open(driverName, path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store: %w", err)
+	}
+	// SQLite allows a single writer; serialize through one connection
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize store schema: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close closes the underlying database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Ingest reads events appended to transcriptPath since the last ingest
+// Only complete lines are consumed, so a line being written is picked up next time.
+// A transcript that shrank (rewritten or rotated) is re-ingested from scratch.
+// Archived .jsonl.gz transcripts are read decompressed, carrying on from where
+// the transcript they were compressed from was ingested up to.
+// Returns the number of new events stored.
+func (s *Store) Ingest(ctx context.Context, transcriptPath string) (int, error) {
+	info, err := os.Stat(transcriptPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat transcript: %w", err)
+	}
+
+	sessionID := SessionIDFromPath(transcriptPath)
+	archive := transcript.IsArchive(transcriptPath)
+
+	offset, mtime, found, err := s.ingestOffset(ctx, transcriptPath)
+	if err != nil {
+		return 0, err
+	}
+	if archive && !found {
+		// Offsets of an archive count decompressed bytes, so the one of the
+		// transcript it was compressed from still applies
+		offset, _, _, err = s.ingestOffset(ctx, strings.TrimSuffix(transcriptPath, transcript.ArchiveExt))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// Archives do not grow, so one is done with until it is rewritten
+	unchanged := mtime == info.ModTime().UnixNano() && (archive || offset == info.Size())
+	if found && unchanged {
+		return 0, nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rewritten := info.Size() < offset
+	if archive {
+		rewritten = found
+	}
+	if rewritten {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM events WHERE session_id = ?`, sessionID); err != nil {
+			return 0, fmt.Errorf("failed to reset session events: %w", err)
+		}
+		offset = 0
+	}
+
+	file, err := transcript.Open(transcriptPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open transcript: %w", err)
+	}
+	defer file.Close()
+
+	if err := skip(file, offset); err != nil {
+		return 0, fmt.Errorf("failed to seek transcript: %w", err)
+	}
+
+	insert, err := tx.PrepareContext(ctx, `INSERT INTO events
+		(session_id, ts, kind, tool_name, tool_use_id, target, model,
+		 input_tokens, output_tokens, cache_creation_tokens, cache_read_tokens, cost, is_error)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	defer insert.Close()
+
+	reader := bufio.NewReaderSize(file, 64*1024)
+	count := 0
+	var first, last time.Time
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		line, n, err := readLine(reader)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read transcript: %w", err)
+		}
+		offset += n
+
+		for _, ev := range DecodeLine(sessionID, line) {
+			if _, err := insert.ExecContext(ctx,
+				ev.SessionID, millis(ev.Timestamp), ev.Kind, ev.ToolName, ev.ToolUseID, ev.Target, ev.Model,
+				ev.InputTokens, ev.OutputTokens, ev.CacheCreationTokens, ev.CacheReadTokens, ev.Cost, ev.IsError,
+			); err != nil {
+				return 0, fmt.Errorf("failed to insert event: %w", err)
+			}
+			count++
+			if !ev.Timestamp.IsZero() {
+				if first.IsZero() || ev.Timestamp.Before(first) {
+					first = ev.Timestamp
+				}
+				if ev.Timestamp.After(last) {
+					last = ev.Timestamp
+				}
+			}
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO sessions (id, transcript_path, project, first_event_at, last_event_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			transcript_path = excluded.transcript_path,
+			first_event_at = COALESCE(MIN(sessions.first_event_at, excluded.first_event_at), excluded.first_event_at, sessions.first_event_at),
+			last_event_at = COALESCE(MAX(sessions.last_event_at, excluded.last_event_at), excluded.last_event_at, sessions.last_event_at)`,
+		sessionID, transcriptPath, filepath.Base(filepath.Dir(transcriptPath)), nullableMillis(first), nullableMillis(last),
+	); err != nil {
+		return 0, fmt.Errorf("failed to update session: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO ingest_offsets (transcript_path, byte_offset, mtime) VALUES (?, ?, ?)
+		ON CONFLICT(transcript_path) DO UPDATE SET byte_offset = excluded.byte_offset, mtime = excluded.mtime`,
+		transcriptPath, offset, info.ModTime().UnixNano(),
+	); err != nil {
+		return 0, fmt.Errorf("failed to update ingest offset: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit ingest: %w", err)
+	}
+	return count, nil
+}
+
+// ingestOffset returns how far transcriptPath was ingested, and its
+// modification time then
+func (s *Store) ingestOffset(ctx context.Context, transcriptPath string) (offset, mtime int64, found bool, err error) {
+	err = s.db.QueryRowContext(ctx,
+		`SELECT byte_offset, mtime FROM ingest_offsets WHERE transcript_path = ?`, transcriptPath,
+	).Scan(&offset, &mtime)
+	switch {
+	case err == sql.ErrNoRows:
+		return 0, 0, false, nil
+	case err != nil:
+		return 0, 0, false, fmt.Errorf("failed to read ingest offset: %w", err)
+	}
+	return offset, mtime, true, nil
+}
+
+// skip moves r offset bytes on, seeking files and reading through archives
+func skip(r io.Reader, offset int64) error {
+	if seeker, ok := r.(io.Seeker); ok {
+		_, err := seeker.Seek(offset, io.SeekStart)
+		return err
+	}
+	_, err := io.CopyN(io.Discard, r, offset)
+	return err
+}
+
+// readLine returns the next complete newline-terminated line and the number
+// of bytes it occupies, or io.EOF when only a partial line or nothing remains.
+// Lines longer than maxLineSize are consumed but returned as nil
+func readLine(r *bufio.Reader) ([]byte, int64, error) {
+	var line []byte
+	var consumed int64
+	oversized := false
+	for {
+		chunk, err := r.ReadSlice('\n')
+		consumed += int64(len(chunk))
+		if !oversized {
+			line = append(line, chunk...)
+			if len(line) > maxLineSize {
+				oversized = true
+				line = nil
+			}
+		}
+		if err == nil {
+			return line, consumed, nil
+		}
+		if err != bufio.ErrBufferFull {
+			// Partial trailing line: leave it for the next ingest
+			return nil, 0, io.EOF
+		}
+	}
+}
+
+// nullableMillis converts a time to Unix milliseconds, or NULL when zero
+func nullableMillis(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UnixMilli()
+}
+
+// millis converts a time to Unix milliseconds, using 0 for the zero time
+func millis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
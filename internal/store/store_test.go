@@ -0,0 +1,213 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/transcript"
+)
+
+const testTranscript = `{"type":"assistant","timestamp":"2026-01-11T10:05:00.000Z","message":{"role":"assistant","model":"claude-sonnet-4","usage":{"input_tokens":1000,"output_tokens":200,"cache_creation_input_tokens":50,"cache_read_input_tokens":300},"content":[{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"/p/main.go"}}]}}
+{"type":"user","timestamp":"2026-01-11T10:05:01.000Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1"}]}}
+{"type":"assistant","timestamp":"2026-01-11T11:10:00.000Z","message":{"role":"assistant","model":"claude-sonnet-4","usage":{"input_tokens":2000,"output_tokens":400},"content":[{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"go test ./..."}}]}}
+{"type":"user","timestamp":"2026-01-11T11:10:05.000Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t2","is_error":true}]}}
+`
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "sessions.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func writeTranscript(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, "project", "abc-123.jsonl")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestStore_IngestAndQuery(t *testing.T) {
+	ctx := context.Background()
+	s := openTestStore(t)
+	path := writeTranscript(t, t.TempDir(), testTranscript)
+
+	n, err := s.Ingest(ctx, path)
+	if err != nil {
+		t.Fatalf("Ingest() error = %v", err)
+	}
+	// 2 messages + 2 tool uses + 2 tool results
+	if n != 6 {
+		t.Errorf("Ingest() = %d events, want 6", n)
+	}
+
+	// Re-ingesting an unchanged file is a no-op
+	if n, _ := s.Ingest(ctx, path); n != 0 {
+		t.Errorf("second Ingest() = %d, want 0", n)
+	}
+
+	counts, err := s.ToolCounts(ctx, Filter{})
+	if err != nil {
+		t.Fatalf("ToolCounts() error = %v", err)
+	}
+	byName := make(map[string]ToolCount)
+	for _, c := range counts {
+		byName[c.Name] = c
+	}
+	if byName["Read"].Calls != 1 || byName["Read"].Errors != 0 {
+		t.Errorf("Read = %+v", byName["Read"])
+	}
+	if byName["Bash"].Calls != 1 || byName["Bash"].Errors != 1 {
+		t.Errorf("Bash = %+v", byName["Bash"])
+	}
+
+	buckets, err := s.HourlyTokens(ctx, Filter{SessionID: "abc-123"})
+	if err != nil {
+		t.Fatalf("HourlyTokens() error = %v", err)
+	}
+	if len(buckets) != 2 {
+		t.Fatalf("HourlyTokens() = %d buckets, want 2", len(buckets))
+	}
+	if buckets[0].InputTokens != 1000 || buckets[0].CacheReadTokens != 300 {
+		t.Errorf("first bucket = %+v", buckets[0])
+	}
+	if want := time.Date(2026, 1, 11, 10, 0, 0, 0, time.UTC); !buckets[0].Hour.Equal(want) {
+		t.Errorf("first bucket hour = %v, want %v", buckets[0].Hour, want)
+	}
+
+	cost, err := s.TotalCost(ctx, Filter{})
+	if err != nil {
+		t.Fatalf("TotalCost() error = %v", err)
+	}
+	// Sonnet: 3000 input @ $3/M + 600 output @ $15/M
+	if want := 0.009 + 0.009; cost < want-1e-9 || cost > want+1e-9 {
+		t.Errorf("TotalCost() = %v, want %v", cost, want)
+	}
+
+	since := time.Date(2026, 1, 11, 11, 0, 0, 0, time.UTC)
+	if cost, _ := s.TotalCost(ctx, Filter{Since: since}); cost < 0.0119 || cost > 0.0121 {
+		t.Errorf("TotalCost(since 11:00) = %v, want 0.012", cost)
+	}
+
+	sessions, err := s.Sessions(ctx, Filter{})
+	if err != nil {
+		t.Fatalf("Sessions() error = %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("Sessions() = %d, want 1", len(sessions))
+	}
+	if ss := sessions[0]; ss.ID != "abc-123" || ss.Project != "project" || ss.ToolCalls != 2 || ss.Messages != 2 {
+		t.Errorf("session summary = %+v", ss)
+	}
+}
+
+func TestStore_IngestIncremental(t *testing.T) {
+	ctx := context.Background()
+	s := openTestStore(t)
+
+	lines := []byte(testTranscript)
+	split := len(`{"type":"assistant","timestamp":"2026-01-11T10:05:00.000Z","message":{"role":"assistant","model":"claude-sonnet-4","usage":{"input_tokens":1000,"output_tokens":200,"cache_creation_input_tokens":50,"cache_read_input_tokens":300},"content":[{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"/p/main.go"}}]}}`) + 1
+
+	// First write ends with a partial line which must not be consumed yet
+	path := writeTranscript(t, t.TempDir(), string(lines[:split+20]))
+	n, err := s.Ingest(ctx, path)
+	if err != nil {
+		t.Fatalf("Ingest() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("first Ingest() = %d, want 2", n)
+	}
+
+	if err := os.WriteFile(path, lines, 0644); err != nil {
+		t.Fatal(err)
+	}
+	n, err = s.Ingest(ctx, path)
+	if err != nil {
+		t.Fatalf("Ingest() error = %v", err)
+	}
+	if n != 4 {
+		t.Errorf("second Ingest() = %d, want 4", n)
+	}
+
+	// A shorter file means the transcript was rewritten: start over
+	if err := os.WriteFile(path, lines[:split], 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Ingest(ctx, path); err != nil {
+		t.Fatalf("Ingest() error = %v", err)
+	}
+	events, err := s.Events(ctx, Filter{SessionID: "abc-123"})
+	if err != nil {
+		t.Fatalf("Events() error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Errorf("events after rewrite = %d, want 2", len(events))
+	}
+}
+
+func TestStore_IngestArchive(t *testing.T) {
+	ctx := context.Background()
+	s := openTestStore(t)
+
+	lines := []byte(testTranscript)
+	split := len(`{"type":"assistant","timestamp":"2026-01-11T10:05:00.000Z","message":{"role":"assistant","model":"claude-sonnet-4","usage":{"input_tokens":1000,"output_tokens":200,"cache_creation_input_tokens":50,"cache_read_input_tokens":300},"content":[{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"/p/main.go"}}]}}`) + 1
+
+	// The first line was ingested before the transcript grew and was archived
+	path := writeTranscript(t, t.TempDir(), string(lines[:split]))
+	if n, err := s.Ingest(ctx, path); err != nil || n != 2 {
+		t.Fatalf("Ingest() = %d, %v, want 2 events", n, err)
+	}
+	if err := os.WriteFile(path, lines, 0644); err != nil {
+		t.Fatal(err)
+	}
+	archived, err := transcript.Archive(path)
+	if err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+
+	n, err := s.Ingest(ctx, archived)
+	if err != nil {
+		t.Fatalf("Ingest() of the archive error = %v", err)
+	}
+	if n != 4 {
+		t.Errorf("Ingest() of the archive = %d events, want the 4 after the first line", n)
+	}
+	if n, _ := s.Ingest(ctx, archived); n != 0 {
+		t.Errorf("second Ingest() of the archive = %d, want 0", n)
+	}
+	events, err := s.Events(ctx, Filter{SessionID: "abc-123"})
+	if err != nil {
+		t.Fatalf("Events() error = %v", err)
+	}
+	if len(events) != 6 {
+		t.Errorf("events = %d, want 6 decompressed events", len(events))
+	}
+}
+
+func TestDecodeLine(t *testing.T) {
+	events := DecodeLine("s", []byte(`{"type":"assistant","message":{"role":"assistant","model":"claude-opus-4","usage":{"input_tokens":10,"output_tokens":5},"content":[{"type":"tool_use","id":"x","name":"Grep","input":{"pattern":"TODO"}}]}}`))
+	if len(events) != 2 {
+		t.Fatalf("DecodeLine() = %d events, want 2", len(events))
+	}
+	if events[1].Kind != KindToolUse || events[1].Target != "TODO" {
+		t.Errorf("tool event = %+v", events[1])
+	}
+
+	if got := DecodeLine("s", []byte(`{"type":"summary","summary":"x"}`)); len(got) != 0 {
+		t.Errorf("summary line should yield no events, got %d", len(got))
+	}
+	if got := DecodeLine("s", []byte(`not json`)); len(got) != 0 {
+		t.Errorf("invalid line should yield no events, got %d", len(got))
+	}
+}
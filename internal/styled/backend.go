@@ -0,0 +1,168 @@
+package styled
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+)
+
+// Backend shows styled text on one kind of output
+type Backend interface {
+	// Name returns the name the backend is selected by, e.g. "tmux"
+	Name() string
+	// Append appends t, shown for this output, to dst
+	Append(dst []byte, t Text) []byte
+}
+
+// Backends, by the name they are selected by
+var backends = map[string]Backend{
+	"ansi":  ANSI{},
+	"tmux":  Tmux{},
+	"plain": PlainText{},
+	"json":  JSON{},
+}
+
+// Lookup returns the backend called name; "" is ANSI, the default
+func Lookup(name string) (Backend, error) {
+	if name == "" {
+		return ANSI{}, nil
+	}
+	if b, ok := backends[strings.ToLower(name)]; ok {
+		return b, nil
+	}
+	return nil, fmt.Errorf("unknown output %q (have: %s)", name, strings.Join(Names(), ", "))
+}
+
+// Names returns the names of the backends, sorted
+func Names() []string {
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render returns t shown by the backend
+func Render(b Backend, t Text) string {
+	return string(b.Append(nil, t))
+}
+
+// ANSI shows styled text with ANSI escapes in the theme's colors, for
+// terminals
+type ANSI struct{}
+
+// Name returns "ansi"
+func (ANSI) Name() string { return "ansi" }
+
+// Append appends t with ANSI escapes to dst
+func (ANSI) Append(dst []byte, t Text) []byte {
+	for _, span := range t {
+		if span.Link != "" {
+			dst = append(dst, "\033]8;;"+span.Link+"\033\\"...)
+		}
+		styled := false
+		if span.Bold {
+			dst, styled = append(dst, theme.Bold...), true
+		}
+		if span.Dim && theme.Dim != "" {
+			dst, styled = append(dst, theme.Dim...), true
+		}
+		if code := ansiColor(span.Color); code != "" {
+			dst, styled = append(dst, code...), true
+		}
+		dst = append(dst, span.Text...)
+		if styled {
+			dst = append(dst, theme.Reset...)
+		}
+		if span.Link != "" {
+			dst = append(dst, "\033]8;;\033\\"...)
+		}
+	}
+	return dst
+}
+
+// ansiColor returns the theme's escape for c
+func ansiColor(c Color) string {
+	switch c {
+	case Success:
+		return theme.Green
+	case Warning:
+		return theme.Yellow
+	case Error:
+		return theme.Red
+	case Secondary:
+		return theme.Purple
+	}
+	return ""
+}
+
+// Tmux shows styled text with tmux's #[...] style formats, for a status-left
+// or status-right running claude-hud with #(...)
+type Tmux struct{}
+
+// tmuxColors are the tmux colors matching the theme's default palette
+var tmuxColors = [...]string{Success: "colour40", Warning: "colour215", Error: "colour203", Secondary: "colour177"}
+
+// Name returns "tmux"
+func (Tmux) Name() string { return "tmux" }
+
+// Append appends t with tmux style formats to dst. Links are left out
+func (Tmux) Append(dst []byte, t Text) []byte {
+	for _, span := range t {
+		var attrs []string
+		if int(span.Color) < len(tmuxColors) && tmuxColors[span.Color] != "" {
+			attrs = append(attrs, "fg="+tmuxColors[span.Color])
+		}
+		if span.Bold {
+			attrs = append(attrs, "bold")
+		}
+		if span.Dim {
+			attrs = append(attrs, "dim")
+		}
+		if len(attrs) > 0 {
+			dst = append(dst, "#["+strings.Join(attrs, ",")+"]"...)
+		}
+		dst = append(dst, strings.ReplaceAll(span.Text, "#", "##")...)
+		if len(attrs) > 0 {
+			dst = append(dst, "#[default]"...)
+		}
+	}
+	return dst
+}
+
+// PlainText shows the text alone, for outputs without styling
+type PlainText struct{}
+
+// Name returns "plain"
+func (PlainText) Name() string { return "plain" }
+
+// Append appends t's text to dst
+func (PlainText) Append(dst []byte, t Text) []byte {
+	for _, span := range t {
+		dst = append(dst, span.Text...)
+	}
+	return dst
+}
+
+// JSON shows styled text as a JSON array of spans, for scripts and other
+// renderers, e.g. [{"text":"72%","color":"warning","bold":true}]
+type JSON struct{}
+
+// Name returns "json"
+func (JSON) Name() string { return "json" }
+
+// Append appends t as a JSON array to dst
+func (JSON) Append(dst []byte, t Text) []byte {
+	if t == nil {
+		t = Text{} // [] rather than null
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return append(dst, "[]"...) // Spans always encode
+	}
+	return append(dst, data...)
+}
@@ -0,0 +1,81 @@
+package styled
+
+import (
+	"strings"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+)
+
+// Parse turns text with embedded ANSI escapes into styled text, for sections
+// that still build their output from the theme's escapes. The theme's
+// colors, bold, dim and reset, and OSC 8 links, become styles; other escapes
+// are dropped
+func Parse(s string) Text {
+	var t Text
+	var style Style
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '\033')
+		if i < 0 {
+			return t.Add(s, style)
+		}
+		t = t.Add(s[:i], style)
+		n := escapeLength(s[i:])
+		style = apply(style, s[i:i+n])
+		s = s[i+n:]
+	}
+	return t
+}
+
+// apply returns style changed by the escape seq
+func apply(style Style, seq string) Style {
+	if url, ok := strings.CutPrefix(seq, "\033]8;;"); ok {
+		style.Link = strings.TrimSuffix(strings.TrimSuffix(url, "\033\\"), "\a")
+		return style
+	}
+	switch seq {
+	case theme.Reset, "\033[m":
+		return Style{Link: style.Link} // Links end with an escape of their own
+	case theme.Bold:
+		style.Bold = true
+	case theme.Dim:
+		style.Dim = true
+	case theme.Green:
+		style.Color = Success
+	case theme.Yellow:
+		style.Color = Warning
+	case theme.Red:
+		style.Color = Error
+	case theme.Purple:
+		style.Color = Secondary
+	}
+	return style
+}
+
+// escapeLength returns the length of the escape sequence s starts with: a
+// CSI sequence up to its final byte, an OSC sequence up to its terminator,
+// or the escape and the byte after it
+func escapeLength(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+	case ']':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\033' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+	default:
+		return 2
+	}
+	return len(s)
+}
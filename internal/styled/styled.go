@@ -0,0 +1,150 @@
+// Package styled is text with semantic styles kept apart from how they are
+// shown. A section builds a Text of spans, each marked as a warning, an
+// error, dimmed and so on, and a Backend turns it into ANSI escapes for a
+// terminal, tmux formats, plain text or JSON. Output that still embeds ANSI
+// escapes is turned into a Text by Parse
+package styled
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+)
+
+// Color is the meaning of a span's color; backends pick the actual color,
+// the terminal's from the theme in effect
+type Color uint8
+
+// Colors, as the theme names them
+const (
+	NoColor   Color = iota
+	Success         // Green: done, healthy
+	Warning         // Amber: getting close to a limit
+	Error           // Red: over a limit, failed
+	Secondary       // Purple: highlights
+)
+
+// colorNames are the names of colors in JSON output
+var colorNames = [...]string{NoColor: "", Success: "success", Warning: "warning", Error: "error", Secondary: "secondary"}
+
+// String returns the color's name, e.g. "warning", or "" for NoColor
+func (c Color) String() string {
+	if int(c) < len(colorNames) {
+		return colorNames[c]
+	}
+	return ""
+}
+
+// MarshalText encodes the color as its name
+func (c Color) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText decodes a color name
+func (c *Color) UnmarshalText(text []byte) error {
+	for i, name := range colorNames {
+		if name == string(text) {
+			*c = Color(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown color %q", text)
+}
+
+// Style is how a span is shown
+type Style struct {
+	Color Color  `json:"color,omitempty"`
+	Bold  bool   `json:"bold,omitempty"`
+	Dim   bool   `json:"dim,omitempty"`
+	Link  string `json:"link,omitempty"` // URL the span links to
+}
+
+// Common styles
+var (
+	None     = Style{}
+	Dimmed   = Style{Dim: true}
+	Strong   = Style{Bold: true}
+	Good     = Style{Color: Success}
+	Caution  = Style{Color: Warning}
+	Critical = Style{Color: Error}
+)
+
+// Span is a run of text in one style
+type Span struct {
+	Text string `json:"text"`
+	Style
+}
+
+// Text is styled text: spans in order
+type Text []Span
+
+// Plain returns s as a Text without styling
+func Plain(s string) Text {
+	return Text(nil).Add(s, None)
+}
+
+// Add appends s in style to t, joining it to the last span when that has
+// the same style. Empty strings are left out
+func (t Text) Add(s string, style Style) Text {
+	if s == "" {
+		return t
+	}
+	if n := len(t); n > 0 && t[n-1].Style == style {
+		t[n-1].Text += s
+		return t
+	}
+	return append(t, Span{Text: s, Style: style})
+}
+
+// Signal appends s in color c to t, for text that shows a state, e.g. a
+// percentage over a threshold. In symbols mode warning and error text
+// starts with the color's marker, so the state reads without the color
+func (t Text) Signal(s string, c Color) Text {
+	return t.Add(Marker(c)+s, Style{Color: c})
+}
+
+// Marker returns the theme's symbol for warning or error text in symbols
+// mode, and "" otherwise
+func Marker(c Color) string {
+	if !theme.Symbols() {
+		return ""
+	}
+	switch c {
+	case Error:
+		return theme.CriticalMarker
+	case Warning:
+		return theme.WarningMarker
+	}
+	return ""
+}
+
+// Join appends the spans of other to t
+func (t Text) Join(other Text) Text {
+	for _, span := range other {
+		t = t.Add(span.Text, span.Style)
+	}
+	return t
+}
+
+// String returns t's text without styling
+func (t Text) String() string {
+	n := 0
+	for _, span := range t {
+		n += len(span.Text)
+	}
+	b := make([]byte, 0, n)
+	for _, span := range t {
+		b = append(b, span.Text...)
+	}
+	return string(b)
+}
+
+// Width returns the number of columns t takes up on screen
+func (t Text) Width() int {
+	width := 0
+	for _, span := range t {
+		width += utf8.RuneCountInString(span.Text)
+	}
+	return width
+}
@@ -0,0 +1,102 @@
+package styled
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/theme"
+)
+
+func TestAdd(t *testing.T) {
+	got := Plain("⧉ 3").Add(" · ", None).Add("", Caution).Add("1 approval", Caution)
+	want := Text{{Text: "⧉ 3 · "}, {Text: "1 approval", Style: Caution}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Add() = %+v, want %+v", got, want)
+	}
+	if got.String() != "⧉ 3 · 1 approval" || got.Width() != 16 {
+		t.Errorf("String() = %q, Width() = %d, want the text and its 16 columns", got.String(), got.Width())
+	}
+}
+
+func TestSignal(t *testing.T) {
+	t.Cleanup(func() { theme.SetSymbols(false) })
+	if got, want := Plain("ctx ").Signal("95%", Error), (Text{{Text: "ctx "}, {Text: "95%", Style: Critical}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Signal() = %+v, want %+v", got, want)
+	}
+	theme.SetSymbols(true)
+	want := Text{{Text: theme.CriticalMarker + "95%", Style: Critical}, {Text: "ok"}}
+	if got := Text(nil).Signal("95%", Error).Signal("ok", NoColor); !reflect.DeepEqual(got, want) {
+		t.Errorf("Signal() in symbols mode = %+v, want %+v", got, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want Text
+	}{
+		{"plain", "main ± 3", Text{{Text: "main ± 3"}}},
+		{"color", "ctx " + theme.Red + "95%" + theme.Reset, Text{{Text: "ctx "}, {Text: "95%", Style: Critical}}},
+		{"bold color", theme.Bold + theme.Yellow + "1 approval" + theme.Reset + " · 2",
+			Text{{Text: "1 approval", Style: Style{Color: Warning, Bold: true}}, {Text: " · 2"}}},
+		{"dim", theme.Dim + "[git unavailable]" + theme.Reset, Text{{Text: "[git unavailable]", Style: Dimmed}}},
+		{"link", theme.Hyperlink("https://example.com/pr/1", "#1"), Text{{Text: "#1", Style: Style{Link: "https://example.com/pr/1"}}}},
+		{"unknown escape", "a\033[38;2;1;2;3mb\033[Kc", Text{{Text: "abc"}}},
+		{"cut off escape", "a\033[38", Text{{Text: "a"}}},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Parse(%q) = %+v, want %+v", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseHighContrast(t *testing.T) {
+	t.Cleanup(func() { theme.SetHighContrast(false) })
+	theme.SetHighContrast(true)
+	want := Text{{Text: "95%", Style: Critical}}
+	if got := Parse(theme.Red + "95%" + theme.Reset); !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() with high contrast colors = %+v, want %+v", got, want)
+	}
+}
+
+func TestBackends(t *testing.T) {
+	text := Plain("#3 ").Add("72%", Style{Color: Warning, Bold: true}).Add(" ok", Good)
+	tests := []struct {
+		backend Backend
+		want    string
+	}{
+		{ANSI{}, "#3 " + theme.Bold + theme.Yellow + "72%" + theme.Reset + theme.Green + " ok" + theme.Reset},
+		{Tmux{}, "##3 #[fg=colour215,bold]72%#[default]#[fg=colour40] ok#[default]"},
+		{PlainText{}, "#3 72% ok"},
+		{JSON{}, `[{"text":"#3 "},{"text":"72%","color":"warning","bold":true},{"text":" ok","color":"success"}]`},
+	}
+	for _, tt := range tests {
+		if got := Render(tt.backend, text); got != tt.want {
+			t.Errorf("%s: Render() = %q, want %q", tt.backend.Name(), got, tt.want)
+		}
+	}
+	if got := Render(JSON{}, nil); got != "[]" {
+		t.Errorf("json: Render(nil) = %q, want an empty array", got)
+	}
+}
+
+func TestANSIRoundTrip(t *testing.T) {
+	text := Plain("PR ").Add("#12", Style{Link: "https://example.com/12", Bold: true}).Add(" idle", Dimmed)
+	if got := Parse(Render(ANSI{}, text)); !reflect.DeepEqual(got, text) {
+		t.Errorf("Parse(Render(ANSI)) = %+v, want %+v", got, text)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for name, want := range map[string]string{"": "ansi", "tmux": "tmux", "JSON": "json"} {
+		b, err := Lookup(name)
+		if err != nil || b.Name() != want {
+			t.Errorf("Lookup(%q) = %v, %v, want the %s backend", name, b, err, want)
+		}
+	}
+	if _, err := Lookup("html"); err == nil {
+		t.Error("Lookup(\"html\") succeeded, want an error naming the outputs")
+	}
+}
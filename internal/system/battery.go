@@ -0,0 +1,190 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BatteryState is the charging state of the battery
+type BatteryState string
+
+const (
+	BatteryUnknown     BatteryState = "unknown"
+	BatteryCharging    BatteryState = "charging"
+	BatteryDischarging BatteryState = "discharging"
+	BatteryFull        BatteryState = "full"
+	BatteryNotCharging BatteryState = "not charging" // On AC but holding charge
+)
+
+// BatteryInfo contains battery and power status
+type BatteryInfo struct {
+	Present       bool          // False on desktops and VMs
+	Percent       int           // Charge level 0-100
+	State         BatteryState  // Charging state
+	TimeRemaining time.Duration // Time to empty (or full when charging); 0 if unknown
+}
+
+// OnAC reports whether the machine is running on external power
+func (b BatteryInfo) OnAC() bool {
+	return b.State != BatteryDischarging && b.State != BatteryUnknown
+}
+
+// sysfsPowerSupply is where Linux exposes batteries and AC adapters
+const sysfsPowerSupply = "/sys/class/power_supply"
+
+// GetBattery reads the battery status for the current platform
+// Returns a BatteryInfo with Present false when there is no battery
+func GetBattery() (BatteryInfo, error) {
+	switch runtime.GOOS {
+	case "linux":
+		return readSysfsBattery(sysfsPowerSupply)
+	case "darwin":
+		output, err := exec.Command("pmset", "-g", "batt").Output()
+		if err != nil {
+			return BatteryInfo{}, fmt.Errorf("pmset: %w", err)
+		}
+		return parsePmset(string(output)), nil
+	case "windows":
+		return getWindowsBattery()
+	}
+	return BatteryInfo{}, nil
+}
+
+// readSysfsBattery combines every battery under root (laptops may have two)
+// Charge is weighted by energy when available, otherwise averaged
+func readSysfsBattery(root string) (BatteryInfo, error) {
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return BatteryInfo{}, nil
+	}
+	if err != nil {
+		return BatteryInfo{}, err
+	}
+
+	var info BatteryInfo
+	var energyNow, energyFull, powerNow float64
+	var capacitySum, count int
+	for _, entry := range entries {
+		dir := filepath.Join(root, entry.Name())
+		if readSysfsString(dir, "type") != "Battery" || readSysfsString(dir, "present") == "0" {
+			continue
+		}
+
+		count++
+		info.Present = true
+		if capacity, err := strconv.Atoi(readSysfsString(dir, "capacity")); err == nil {
+			capacitySum += capacity
+		}
+		// A charging or discharging battery decides the overall state
+		state := parseSysfsStatus(readSysfsString(dir, "status"))
+		if info.State == "" || info.State == BatteryUnknown || state == BatteryCharging || state == BatteryDischarging {
+			info.State = state
+		}
+
+		// Batteries report either energy_* (µWh, power_now in µW) or charge_* (µAh, current_now in µA)
+		now, full, rate := readSysfsFloat(dir, "energy_now"), readSysfsFloat(dir, "energy_full"), readSysfsFloat(dir, "power_now")
+		if full == 0 {
+			now, full, rate = readSysfsFloat(dir, "charge_now"), readSysfsFloat(dir, "charge_full"), readSysfsFloat(dir, "current_now")
+		}
+		energyNow += now
+		energyFull += full
+		powerNow += rate
+	}
+
+	if count == 0 {
+		return BatteryInfo{}, nil
+	}
+
+	if energyFull > 0 {
+		info.Percent = int(energyNow/energyFull*100 + 0.5)
+	} else {
+		info.Percent = capacitySum / count
+	}
+	if info.Percent > 100 {
+		info.Percent = 100
+	}
+
+	if powerNow > 0 {
+		var hours float64
+		switch info.State {
+		case BatteryDischarging:
+			hours = energyNow / powerNow
+		case BatteryCharging:
+			hours = (energyFull - energyNow) / powerNow
+		}
+		info.TimeRemaining = time.Duration(hours * float64(time.Hour)).Round(time.Minute)
+	}
+
+	return info, nil
+}
+
+// parseSysfsStatus maps the sysfs status attribute to a BatteryState
+func parseSysfsStatus(status string) BatteryState {
+	switch status {
+	case "Charging":
+		return BatteryCharging
+	case "Discharging":
+		return BatteryDischarging
+	case "Full":
+		return BatteryFull
+	case "Not charging":
+		return BatteryNotCharging
+	}
+	return BatteryUnknown
+}
+
+func readSysfsString(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func readSysfsFloat(dir, name string) float64 {
+	v, _ := strconv.ParseFloat(readSysfsString(dir, name), 64)
+	return v
+}
+
+// pmsetBattery matches a battery line of `pmset -g batt`, e.g.
+//
+//	-InternalBattery-0 (id=4653155)	85%; discharging; 3:45 remaining present: true
+var pmsetBattery = regexp.MustCompile(`(\d+)%;\s*([^;]+);\s*(?:(\d+):(\d+) remaining)?`)
+
+// parsePmset parses the output of `pmset -g batt`
+func parsePmset(output string) BatteryInfo {
+	match := pmsetBattery.FindStringSubmatch(output)
+	if match == nil {
+		return BatteryInfo{}
+	}
+
+	info := BatteryInfo{Present: true}
+	info.Percent, _ = strconv.Atoi(match[1])
+
+	switch strings.TrimSpace(match[2]) {
+	case "charging":
+		info.State = BatteryCharging
+	case "discharging":
+		info.State = BatteryDischarging
+	case "charged", "finishing charge":
+		info.State = BatteryFull
+	case "AC attached":
+		info.State = BatteryNotCharging
+	default:
+		info.State = BatteryUnknown
+	}
+
+	if match[3] != "" {
+		hours, _ := strconv.Atoi(match[3])
+		minutes, _ := strconv.Atoi(match[4])
+		info.TimeRemaining = time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute
+	}
+	return info
+}
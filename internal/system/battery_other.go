@@ -0,0 +1,8 @@
+//go:build !windows
+
+package system
+
+// getWindowsBattery is only implemented on Windows
+func getWindowsBattery() (BatteryInfo, error) {
+	return BatteryInfo{}, nil
+}
@@ -0,0 +1,104 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeSupply creates a fake /sys/class/power_supply entry
+func writeSupply(t *testing.T, root, name string, attrs map[string]string) {
+	t.Helper()
+	dir := filepath.Join(root, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for attr, value := range attrs {
+		if err := os.WriteFile(filepath.Join(dir, attr), []byte(value+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadSysfsBattery(t *testing.T) {
+	t.Run("no power supplies", func(t *testing.T) {
+		info, err := readSysfsBattery(filepath.Join(t.TempDir(), "missing"))
+		if err != nil || info.Present {
+			t.Errorf("readSysfsBattery() = %+v, %v; want no battery", info, err)
+		}
+	})
+
+	t.Run("AC adapter only", func(t *testing.T) {
+		root := t.TempDir()
+		writeSupply(t, root, "AC", map[string]string{"type": "Mains", "online": "1"})
+		if info, _ := readSysfsBattery(root); info.Present {
+			t.Errorf("readSysfsBattery() = %+v, want no battery", info)
+		}
+	})
+
+	t.Run("discharging with energy readings", func(t *testing.T) {
+		root := t.TempDir()
+		writeSupply(t, root, "BAT0", map[string]string{
+			"type": "Battery", "present": "1", "status": "Discharging", "capacity": "41",
+			"energy_now": "20000000", "energy_full": "50000000", "power_now": "10000000",
+		})
+		info, err := readSysfsBattery(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !info.Present || info.Percent != 40 || info.State != BatteryDischarging || info.OnAC() {
+			t.Errorf("info = %+v, want 40%% discharging", info)
+		}
+		if info.TimeRemaining != 2*time.Hour {
+			t.Errorf("TimeRemaining = %v, want 2h", info.TimeRemaining)
+		}
+	})
+
+	t.Run("two batteries, one charging", func(t *testing.T) {
+		root := t.TempDir()
+		writeSupply(t, root, "BAT0", map[string]string{"type": "Battery", "status": "Full", "capacity": "100"})
+		writeSupply(t, root, "BAT1", map[string]string{"type": "Battery", "status": "Charging", "capacity": "50"})
+		info, _ := readSysfsBattery(root)
+		if info.Percent != 75 || info.State != BatteryCharging || !info.OnAC() {
+			t.Errorf("info = %+v, want 75%% charging", info)
+		}
+	})
+}
+
+func TestParsePmset(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   BatteryInfo
+	}{
+		{
+			name:   "discharging",
+			output: "Now drawing from 'Battery Power'\n -InternalBattery-0 (id=4653155)\t85%; discharging; 3:45 remaining present: true\n",
+			want:   BatteryInfo{Present: true, Percent: 85, State: BatteryDischarging, TimeRemaining: 3*time.Hour + 45*time.Minute},
+		},
+		{
+			name:   "no estimate yet",
+			output: " -InternalBattery-0 (id=4653155)\t62%; charging; (no estimate) present: true\n",
+			want:   BatteryInfo{Present: true, Percent: 62, State: BatteryCharging},
+		},
+		{
+			name:   "charged",
+			output: " -InternalBattery-0 (id=4653155)\t100%; charged; 0:00 remaining present: true\n",
+			want:   BatteryInfo{Present: true, Percent: 100, State: BatteryFull},
+		},
+		{
+			name:   "desktop",
+			output: "Now drawing from 'AC Power'\n",
+			want:   BatteryInfo{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parsePmset(tt.output); got != tt.want {
+				t.Errorf("parsePmset() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+//go:build windows
+
+package system
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// systemPowerStatus mirrors SYSTEM_POWER_STATUS from winbase.h
+type systemPowerStatus struct {
+	ACLineStatus        byte
+	BatteryFlag         byte
+	BatteryLifePercent  byte
+	SystemStatusFlag    byte
+	BatteryLifeTime     uint32
+	BatteryFullLifeTime uint32
+}
+
+const (
+	batteryFlagCharging  = 8
+	batteryFlagNoBattery = 128
+	batteryUnknownValue  = 255
+	batteryUnknownTime   = 0xFFFFFFFF
+)
+
+var procGetSystemPowerStatus = syscall.NewLazyDLL("kernel32.dll").NewProc("GetSystemPowerStatus")
+
+// getWindowsBattery reads the battery status via GetSystemPowerStatus
+func getWindowsBattery() (BatteryInfo, error) {
+	var status systemPowerStatus
+	if ret, _, err := procGetSystemPowerStatus.Call(uintptr(unsafe.Pointer(&status))); ret == 0 {
+		return BatteryInfo{}, err
+	}
+	if status.BatteryFlag == batteryUnknownValue || status.BatteryFlag&batteryFlagNoBattery != 0 {
+		return BatteryInfo{}, nil
+	}
+
+	info := BatteryInfo{Present: true, Percent: int(status.BatteryLifePercent), State: BatteryUnknown}
+	if status.BatteryLifePercent == batteryUnknownValue {
+		info.Percent = 0
+	}
+
+	switch {
+	case status.BatteryFlag&batteryFlagCharging != 0:
+		info.State = BatteryCharging
+	case status.ACLineStatus == 1 && info.Percent >= 100:
+		info.State = BatteryFull
+	case status.ACLineStatus == 1:
+		info.State = BatteryNotCharging
+	case status.ACLineStatus == 0:
+		info.State = BatteryDischarging
+	}
+
+	if status.BatteryLifeTime != batteryUnknownTime && info.State == BatteryDischarging {
+		info.TimeRemaining = time.Duration(status.BatteryLifeTime) * time.Second
+	}
+	return info, nil
+}
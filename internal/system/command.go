@@ -0,0 +1,158 @@
+package system
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/filecache"
+	"github.com/ll931217/claude-hud-enhanced/internal/refresh"
+)
+
+// maxCommandOutput bounds how much of a command's output is kept
+const maxCommandOutput = 4096
+
+// commandResult is the cached outcome of one command
+type commandResult struct {
+	Output string    `json:"output"`
+	Error  string    `json:"error,omitempty"`
+	RanAt  time.Time `json:"ran_at"`
+}
+
+// CommandRunner runs shell commands for the custom section and caches their
+// output for a TTL. With a cache path the output is shared across processes,
+// so statusline renders do not each run the command
+type CommandRunner struct {
+	Timeout   time.Duration // Used when the context passed to Output has no deadline
+	CachePath string        // Optional file caching the last results
+
+	mu      sync.Mutex
+	results map[string]commandResult // Keyed by directory and command
+	loaded  bool
+	runs    refresh.Group // One run of a command at a time
+}
+
+// NewCommandRunner creates a runner persisted at cachePath ("" keeps results in memory)
+func NewCommandRunner(cachePath string) *CommandRunner {
+	return &CommandRunner{
+		Timeout:   time.Second,
+		CachePath: cachePath,
+		results:   make(map[string]commandResult),
+	}
+}
+
+// Output returns the first line of the command's output when run with sh in
+// dir, running it again once the cached result is older than ttl. A failed
+// run returns the previous output, if any, with the error until ttl passes
+func (r *CommandRunner) Output(ctx context.Context, command, dir string, env []string, ttl time.Duration) (string, error) {
+	key := dir + "\x00" + command
+	r.mu.Lock()
+	r.load()
+	last, ok := r.results[key]
+	r.mu.Unlock()
+
+	now := time.Now()
+	if !ok || now.Sub(last.RanAt) >= ttl || now.Before(last.RanAt) {
+		// Callers asking for the command meanwhile share this run. Runs end
+		// at their timeout, so waiting for one to finish is bounded too
+		r.runs.Do(context.Background(), key, func() { r.record(ctx, key, command, dir, env) })
+		r.mu.Lock()
+		last = r.results[key]
+		r.mu.Unlock()
+	}
+
+	if last.Error != "" {
+		return last.Output, errors.New(last.Error)
+	}
+	return last.Output, nil
+}
+
+// record runs the command and caches the outcome
+func (r *CommandRunner) record(ctx context.Context, key, command, dir string, env []string) {
+	output, err := r.run(ctx, command, dir, env)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	last := r.results[key]
+	last.RanAt = now
+	last.Error = ""
+	if err != nil {
+		last.Error = err.Error()
+	} else {
+		last.Output = output
+	}
+	r.results[key] = last
+	r.prune(now)
+	r.save()
+}
+
+// run executes the command and returns the first non-empty line of its output
+func (r *CommandRunner) run(ctx context.Context, command, dir string, env []string) (string, error) {
+	runCtx := ctx
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(runCtx, "sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	cmd.WaitDelay = 100 * time.Millisecond // Children of sh may keep the output open
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if runCtx.Err() == context.DeadlineExceeded {
+		return "", errors.New("timed out")
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, firstLine(msg))
+		}
+		return "", err
+	}
+	if len(out) > maxCommandOutput {
+		out = out[:maxCommandOutput]
+	}
+	return firstLine(string(out)), nil
+}
+
+// firstLine returns the first non-empty line of s, trimmed
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// prune forgets commands that have not run for a day, e.g. after a config change
+func (r *CommandRunner) prune(now time.Time) {
+	filecache.Prune(r.results, now, 24*time.Hour, func(r commandResult) time.Time { return r.RanAt })
+}
+
+// load reads the persisted results once; a missing or corrupt file starts empty
+func (r *CommandRunner) load() {
+	if r.loaded {
+		return
+	}
+	r.loaded = true
+	var results map[string]commandResult
+	if filecache.Load(r.CachePath, &results) {
+		maps.Copy(r.results, results)
+	}
+}
+
+// save atomically replaces the results file
+func (r *CommandRunner) save() {
+	_ = filecache.Save(r.CachePath, r.results)
+}
@@ -0,0 +1,104 @@
+package system
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCommandRunner_Output(t *testing.T) {
+	dir := t.TempDir()
+	cachePath := filepath.Join(t.TempDir(), "commands.json")
+	counter := filepath.Join(dir, "runs")
+	command := `echo x >> runs; printf '\n  %s ran %s times  \nsecond line\n' "$GREETING" "$(wc -l < runs | tr -d ' ')"`
+	env := []string{"GREETING=hello"}
+	ctx := context.Background()
+
+	out, err := NewCommandRunner(cachePath).Output(ctx, command, dir, env, time.Hour)
+	if err != nil || out != "hello ran 1 times" {
+		t.Fatalf("Output() = %q, %v; want first line", out, err)
+	}
+
+	// Another process reuses the cached output within the TTL
+	if out, _ := NewCommandRunner(cachePath).Output(ctx, command, dir, env, time.Hour); out != "hello ran 1 times" {
+		t.Errorf("Output() within TTL = %q, want cached output", out)
+	}
+	if data, _ := os.ReadFile(counter); strings.Count(string(data), "x") != 1 {
+		t.Errorf("command ran %d times, want 1", strings.Count(string(data), "x"))
+	}
+
+	// After the TTL it runs again
+	if out, _ := NewCommandRunner(cachePath).Output(ctx, command, dir, env, 0); out != "hello ran 2 times" {
+		t.Errorf("Output() after TTL = %q, want a fresh run", out)
+	}
+}
+
+func TestCommandRunner_Failures(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	runner := NewCommandRunner("")
+
+	if _, err := runner.Output(ctx, "echo broken >&2; exit 3", dir, nil, time.Hour); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("Output() error = %v, want stderr in the error", err)
+	}
+
+	runner.Timeout = 50 * time.Millisecond
+	if _, err := runner.Output(ctx, "sleep 2", dir, nil, time.Hour); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Output() error = %v, want a timeout", err)
+	}
+
+	// A failing run keeps the last good output
+	marker := filepath.Join(dir, "fail")
+	command := "test -e fail && exit 1; echo ok"
+	if out, err := runner.Output(ctx, command, dir, nil, 0); err != nil || out != "ok" {
+		t.Fatalf("Output() = %q, %v", out, err)
+	}
+	if err := os.WriteFile(marker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := runner.Output(ctx, command, dir, nil, 0); err == nil || out != "ok" {
+		t.Errorf("Output() = %q, %v; want stale output with an error", out, err)
+	}
+}
+
+func TestCommandRunner_Concurrent(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	runner := NewCommandRunner("")
+	slow := "sleep 0.3; echo x >> runs; echo slow"
+
+	// Callers asking for a command that is running share its run
+	var wg sync.WaitGroup
+	outputs := make([]string, 3)
+	for i := range outputs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			outputs[i], _ = runner.Output(ctx, slow, dir, nil, time.Hour)
+		}()
+	}
+
+	// and another command runs meanwhile
+	time.Sleep(50 * time.Millisecond)
+	start := time.Now()
+	if out, err := runner.Output(ctx, "echo fast", dir, nil, time.Hour); err != nil || out != "fast" {
+		t.Errorf("Output() = %q, %v", out, err)
+	}
+	if waited := time.Since(start); waited > 200*time.Millisecond {
+		t.Errorf("Output() of another command waited %v for the slow one", waited)
+	}
+
+	wg.Wait()
+	for _, out := range outputs {
+		if out != "slow" {
+			t.Errorf("Output() = %q, want slow", out)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "runs")); strings.Count(string(data), "x") != 1 {
+		t.Errorf("slow command ran %d times, want 1", strings.Count(string(data), "x"))
+	}
+}
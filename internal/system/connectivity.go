@@ -0,0 +1,114 @@
+package system
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/filecache"
+)
+
+// Connectivity describes whether the Claude API can be reached
+type Connectivity string
+
+const (
+	ConnectivityUnknown        Connectivity = ""
+	ConnectivityOnline         Connectivity = "online"          // API answered
+	ConnectivityAPIUnreachable Connectivity = "api-unreachable" // DNS works but the API did not answer
+	ConnectivityOffline        Connectivity = "offline"         // Name resolution failed
+)
+
+// DefaultConnectivityURL is the endpoint probed with HEAD requests
+const DefaultConnectivityURL = "https://api.anthropic.com"
+
+// connectivityResult is the cached outcome of a check
+type connectivityResult struct {
+	Status    Connectivity `json:"status"`
+	CheckedAt time.Time    `json:"checked_at"`
+}
+
+// ConnectivityChecker probes the API with a HEAD request and caches the result
+// With a cache path the result is shared across processes, so statusline
+// renders do not each make a request
+type ConnectivityChecker struct {
+	URL       string
+	Timeout   time.Duration
+	TTL       time.Duration
+	CachePath string // Optional file caching the last result
+	Client    *http.Client
+	Resolver  *net.Resolver
+
+	mu   sync.Mutex
+	last connectivityResult
+}
+
+// NewConnectivityChecker creates a checker for the Claude API
+func NewConnectivityChecker(cachePath string) *ConnectivityChecker {
+	return &ConnectivityChecker{
+		URL:       DefaultConnectivityURL,
+		Timeout:   time.Second,
+		TTL:       30 * time.Second,
+		CachePath: cachePath,
+		Client:    &http.Client{},
+		Resolver:  net.DefaultResolver,
+	}
+}
+
+// Status returns the cached connectivity, probing again once it is older than TTL
+func (c *ConnectivityChecker) Status(ctx context.Context) Connectivity {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if c.last.Status == ConnectivityUnknown {
+		c.last = c.readCache()
+	}
+	if c.last.Status != ConnectivityUnknown && now.Sub(c.last.CheckedAt) < c.TTL {
+		return c.last.Status
+	}
+
+	c.last = connectivityResult{Status: c.probe(ctx), CheckedAt: now}
+	c.writeCache(c.last)
+	return c.last.Status
+}
+
+// probe sends a HEAD request; any HTTP response, even an error status, means reachable
+func (c *ConnectivityChecker) probe(ctx context.Context) Connectivity {
+	reqCtx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodHead, c.URL, nil)
+	if err != nil {
+		return ConnectivityUnknown
+	}
+	resp, err := c.Client.Do(req)
+	if err == nil {
+		resp.Body.Close()
+		return ConnectivityOnline
+	}
+
+	// Tell "no network" from "network up, API down" by resolving the host
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return ConnectivityAPIUnreachable
+	}
+	lookupCtx, cancelLookup := context.WithTimeout(ctx, c.Timeout)
+	defer cancelLookup()
+	if _, err := c.Resolver.LookupHost(lookupCtx, u.Hostname()); err != nil {
+		return ConnectivityOffline
+	}
+	return ConnectivityAPIUnreachable
+}
+
+func (c *ConnectivityChecker) readCache() connectivityResult {
+	var result connectivityResult
+	filecache.Load(c.CachePath, &result)
+	return result
+}
+
+func (c *ConnectivityChecker) writeCache(result connectivityResult) {
+	_ = filecache.Save(c.CachePath, result)
+}
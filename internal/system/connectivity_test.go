@@ -0,0 +1,79 @@
+package system
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestConnectivityChecker(t *testing.T) {
+	t.Run("any response means online", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			if r.Method != http.MethodHead {
+				t.Errorf("method = %s, want HEAD", r.Method)
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		c := NewConnectivityChecker("")
+		c.URL = server.URL
+		if got := c.Status(context.Background()); got != ConnectivityOnline {
+			t.Errorf("Status() = %q, want online", got)
+		}
+		c.Status(context.Background())
+		if requests.Load() != 1 {
+			t.Errorf("requests = %d, want 1 (second call cached)", requests.Load())
+		}
+	})
+
+	t.Run("API down but network up", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr := listener.Addr().String()
+		listener.Close()
+
+		c := NewConnectivityChecker("")
+		c.URL = "http://" + addr
+		if got := c.Status(context.Background()); got != ConnectivityAPIUnreachable {
+			t.Errorf("Status() = %q, want api-unreachable", got)
+		}
+	})
+
+	t.Run("name resolution failure means offline", func(t *testing.T) {
+		c := NewConnectivityChecker("")
+		c.URL = "http://claude-hud.invalid"
+		if got := c.Status(context.Background()); got != ConnectivityOffline {
+			t.Errorf("Status() = %q, want offline", got)
+		}
+	})
+
+	t.Run("result is shared through the cache file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "connectivity.json")
+		first := NewConnectivityChecker(path)
+		first.URL = "http://claude-hud.invalid"
+		first.Status(context.Background())
+
+		// A second process within the TTL reuses the result without probing
+		second := NewConnectivityChecker(path)
+		second.URL = "http://127.0.0.1:1"
+		if got := second.Status(context.Background()); got != ConnectivityOffline {
+			t.Errorf("Status() = %q, want cached offline", got)
+		}
+
+		second.TTL = time.Nanosecond
+		second.last = connectivityResult{}
+		if got := second.Status(context.Background()); got != ConnectivityAPIUnreachable {
+			t.Errorf("Status() = %q, want a fresh probe once stale", got)
+		}
+	})
+}
@@ -0,0 +1,213 @@
+package system
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cpuSampleKey identifies CPU time counters in the SampleStore
+const cpuSampleKey = "cpu"
+
+// cpuSampleMinAge keeps the baseline sample until it is at least this old;
+// renders a few milliseconds apart would otherwise measure mostly noise
+const cpuSampleMinAge = time.Second
+
+// cpuSampleMaxAge discards samples too old to describe current usage
+const cpuSampleMaxAge = 5 * time.Minute
+
+// cpuTimes holds cumulative busy and total jiffies for one CPU line of /proc/stat
+type cpuTimes struct {
+	busy  uint64
+	total uint64
+}
+
+// cpuUsage computes usage from the change in CPU time since the previous sample
+// Until a usable sample exists, the since-boot average is reported instead
+func (m *Monitor) cpuUsage(now time.Time) CPUInfo {
+	info := CPUInfo{CoreCount: runtime.NumCPU()}
+	if load, err := getLoadAverage(); err == nil {
+		info.Load1, info.Load5, info.Load15 = load[0], load[1], load[2]
+	}
+
+	times, err := getCPUTimes()
+	if err != nil || len(times) == 0 {
+		if runtime.GOOS == "darwin" {
+			info.CoreCount = darwinCoreCount(info.CoreCount)
+		}
+		return info
+	}
+	if len(times) > 1 {
+		info.CoreCount = len(times) - 1
+	}
+
+	current := Sample{At: now, Values: flattenCPUTimes(times)}
+	prev, ok := m.samples.Advance(cpuSampleKey, current, cpuSampleMinAge)
+	elapsed := now.Sub(prev.At)
+	if !ok || elapsed <= 0 || elapsed > cpuSampleMaxAge || len(prev.Values) != len(current.Values) {
+		info.UsagePercent = cpuPercent(cpuTimes{}, times[0])
+		return info
+	}
+
+	prevTimes := unflattenCPUTimes(prev.Values)
+	info.UsagePercent = cpuPercent(prevTimes[0], times[0])
+	info.PerCore = make([]float64, 0, len(times)-1)
+	for i := 1; i < len(times); i++ {
+		info.PerCore = append(info.PerCore, cpuPercent(prevTimes[i], times[i]))
+	}
+	info.Sampled = true
+	return info
+}
+
+// cpuPercent returns the busy share of CPU time between two samples
+func cpuPercent(prev, current cpuTimes) float64 {
+	if current.total <= prev.total || current.busy < prev.busy {
+		return 0
+	}
+	percent := float64(current.busy-prev.busy) / float64(current.total-prev.total) * 100
+	if percent > 100 {
+		return 100
+	}
+	return percent
+}
+
+// flattenCPUTimes stores CPU times as busy/total pairs for the SampleStore
+func flattenCPUTimes(times []cpuTimes) []uint64 {
+	values := make([]uint64, 0, len(times)*2)
+	for _, t := range times {
+		values = append(values, t.busy, t.total)
+	}
+	return values
+}
+
+// unflattenCPUTimes reverses flattenCPUTimes
+func unflattenCPUTimes(values []uint64) []cpuTimes {
+	times := make([]cpuTimes, len(values)/2)
+	for i := range times {
+		times[i] = cpuTimes{busy: values[2*i], total: values[2*i+1]}
+	}
+	return times
+}
+
+// getCPUTimes returns the aggregate CPU times followed by one entry per core
+func getCPUTimes() ([]cpuTimes, error) {
+	if runtime.GOOS != "linux" {
+		return nil, fmt.Errorf("CPU times not supported on %s", runtime.GOOS)
+	}
+	file, err := os.Open("/proc/stat")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return parseProcStat(file)
+}
+
+// parseProcStat reads the "cpu" and "cpuN" lines of /proc/stat
+//
+//	cpu  user nice system idle iowait irq softirq steal guest guest_nice
+//
+// Idle and iowait count as idle; guest time is already included in user
+func parseProcStat(r io.Reader) ([]cpuTimes, error) {
+	var times []cpuTimes
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 || !strings.HasPrefix(fields[0], "cpu") {
+			continue
+		}
+		if len(times) == 0 && fields[0] != "cpu" {
+			return nil, fmt.Errorf("invalid /proc/stat format")
+		}
+		var t cpuTimes
+		for i, field := range fields[1:] {
+			if i >= 8 { // guest and guest_nice are part of user and nice
+				break
+			}
+			v, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid /proc/stat value %q", field)
+			}
+			t.total += v
+			if i != 3 && i != 4 { // idle, iowait
+				t.busy += v
+			}
+		}
+		times = append(times, t)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(times) == 0 {
+		return nil, fmt.Errorf("invalid /proc/stat format")
+	}
+	return times, nil
+}
+
+// getLoadAverage returns the 1, 5 and 15 minute load averages
+func getLoadAverage() ([3]float64, error) {
+	switch runtime.GOOS {
+	case "linux":
+		data, err := os.ReadFile("/proc/loadavg")
+		if err != nil {
+			return [3]float64{}, err
+		}
+		return parseLoadAvg(string(data))
+	case "darwin":
+		output, err := exec.Command("sysctl", "-n", "vm.loadavg").Output()
+		if err != nil {
+			return [3]float64{}, err
+		}
+		return parseLoadAvg(strings.Trim(strings.TrimSpace(string(output)), "{}"))
+	}
+	return [3]float64{}, fmt.Errorf("load average not supported on %s", runtime.GOOS)
+}
+
+// parseLoadAvg parses the first three fields of /proc/loadavg or `sysctl vm.loadavg`
+func parseLoadAvg(s string) ([3]float64, error) {
+	var load [3]float64
+	fields := strings.Fields(s)
+	if len(fields) < 3 {
+		return load, fmt.Errorf("invalid load average %q", s)
+	}
+	for i := range load {
+		v, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return load, fmt.Errorf("invalid load average %q", s)
+		}
+		load[i] = v
+	}
+	return load, nil
+}
+
+// darwinCoreCount reads the logical core count via sysctl
+func darwinCoreCount(def int) int {
+	output, err := exec.Command("sysctl", "-n", "machdep.cpu.thread_count").Output()
+	if err != nil {
+		return def
+	}
+	if cores, err := strconv.Atoi(strings.TrimSpace(string(output))); err == nil && cores > 0 {
+		return cores
+	}
+	return def
+}
+
+// usageBars are block characters from idle to fully busy
+var usageBars = []rune("▁▂▃▄▅▆▇█")
+
+// usageBar returns the block character for a usage percentage
+func usageBar(percent float64) rune {
+	i := int(percent / 100 * float64(len(usageBars)))
+	if i >= len(usageBars) {
+		i = len(usageBars) - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	return usageBars[i]
+}
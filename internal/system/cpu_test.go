@@ -0,0 +1,114 @@
+package system
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseProcStat(t *testing.T) {
+	input := `cpu  100 0 50 800 50 0 0 0 20 0
+cpu0 60 0 20 400 20 0 0 0 10 0
+cpu1 40 0 30 400 30 0 0 0 10 0
+intr 12345
+ctxt 67890
+`
+	times, err := parseProcStat(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(times) != 3 {
+		t.Fatalf("parseProcStat() returned %d entries, want 3", len(times))
+	}
+	// Guest time is excluded since it is already counted in user
+	if times[0].busy != 150 || times[0].total != 1000 {
+		t.Errorf("aggregate = %+v, want busy 150 total 1000", times[0])
+	}
+	if times[2].busy != 70 || times[2].total != 500 {
+		t.Errorf("cpu1 = %+v, want busy 70 total 500", times[2])
+	}
+
+	if _, err := parseProcStat(strings.NewReader("intr 1\n")); err == nil {
+		t.Error("parseProcStat() should reject input without cpu lines")
+	}
+}
+
+func TestCPUPercent(t *testing.T) {
+	tests := []struct {
+		name    string
+		prev    cpuTimes
+		current cpuTimes
+		want    float64
+	}{
+		{"half busy", cpuTimes{busy: 100, total: 1000}, cpuTimes{busy: 150, total: 1100}, 50},
+		{"idle", cpuTimes{busy: 100, total: 1000}, cpuTimes{busy: 100, total: 1100}, 0},
+		{"since boot", cpuTimes{}, cpuTimes{busy: 250, total: 1000}, 25},
+		{"no elapsed time", cpuTimes{busy: 100, total: 1000}, cpuTimes{busy: 100, total: 1000}, 0},
+		{"counters reset", cpuTimes{busy: 500, total: 1000}, cpuTimes{busy: 10, total: 50}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cpuPercent(tt.prev, tt.current); got != tt.want {
+				t.Errorf("cpuPercent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLoadAvg(t *testing.T) {
+	load, err := parseLoadAvg("0.52 0.58 0.59 1/467 12345\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if load != [3]float64{0.52, 0.58, 0.59} {
+		t.Errorf("parseLoadAvg() = %v", load)
+	}
+
+	// sysctl vm.loadavg output with braces trimmed
+	if load, err := parseLoadAvg(" 1.23 1.45 1.67 "); err != nil || load[2] != 1.67 {
+		t.Errorf("parseLoadAvg(sysctl) = %v, %v", load, err)
+	}
+
+	if _, err := parseLoadAvg("1.0"); err == nil {
+		t.Error("parseLoadAvg() should reject short input")
+	}
+}
+
+func TestSampleStoreAdvance(t *testing.T) {
+	store := NewSampleStore("")
+	start := time.Now()
+
+	store.Advance("cpu", Sample{At: start, Values: []uint64{1}}, time.Second)
+
+	// A sample taken too soon keeps the older baseline
+	prev, ok := store.Advance("cpu", Sample{At: start.Add(100 * time.Millisecond), Values: []uint64{2}}, time.Second)
+	if !ok || prev.Values[0] != 1 {
+		t.Errorf("Advance() = %+v, want the first sample", prev)
+	}
+	prev, _ = store.Advance("cpu", Sample{At: start.Add(2 * time.Second), Values: []uint64{3}}, time.Second)
+	if prev.Values[0] != 1 {
+		t.Errorf("Advance() = %+v, want the baseline kept until it was old enough", prev)
+	}
+	prev, _ = store.Advance("cpu", Sample{At: start.Add(4 * time.Second), Values: []uint64{4}}, time.Second)
+	if prev.Values[0] != 3 {
+		t.Errorf("Advance() = %+v, want the replaced baseline", prev)
+	}
+}
+
+func TestMonitor_FormatCPUCoresDisplay(t *testing.T) {
+	m := NewMonitor()
+	m.cpu = CPUInfo{UsagePercent: 50, PerCore: []float64{0, 40, 100}, Sampled: true, Load1: 1.2, Load5: 0.85, Load15: 0.6}
+
+	if got := m.FormatCPUCoresDisplay(); got != "▁▄█" {
+		t.Errorf("FormatCPUCoresDisplay() = %q, want %q", got, "▁▄█")
+	}
+	if got := m.FormatLoadDisplay(); got != "LOAD 1.20 0.85 0.60" {
+		t.Errorf("FormatLoadDisplay() = %q", got)
+	}
+
+	// A measured 0% is still shown
+	m.cpu = CPUInfo{Sampled: true}
+	if got := m.FormatCPUDisplay(); got != "CPU 0%" {
+		t.Errorf("FormatCPUDisplay() = %q, want %q", got, "CPU 0%")
+	}
+}
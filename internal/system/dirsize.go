@@ -0,0 +1,181 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/filecache"
+)
+
+// ErrScanIncomplete is returned when a directory scan ran out of time
+// Progress is kept, so the next scan continues where this one stopped
+var ErrScanIncomplete = errors.New("directory scan incomplete")
+
+// dirNode caches one directory: its own files' sizes and its subdirectories
+type dirNode struct {
+	ModTime   time.Time `json:"mtime"`
+	Files     uint64    `json:"files"` // Apparent size of regular files directly inside
+	Subdirs   []string  `json:"subdirs,omitempty"`
+	ScannedAt time.Time `json:"scanned_at"`
+}
+
+// dirTree is the cached state of one root directory
+type dirTree struct {
+	Total     uint64              `json:"total"`
+	Complete  bool                `json:"complete"`
+	ScannedAt time.Time           `json:"scanned_at"`
+	Nodes     map[string]*dirNode `json:"nodes"` // Keyed by path relative to the root
+}
+
+// DirSizer computes directory sizes like `du --apparent-size`, incrementally
+//
+// A directory is only listed again when its modification time changed or its
+// cached listing is older than Refresh; unchanged directories reuse their
+// cached file total. Growth of existing files is therefore picked up at the
+// latest after Refresh. Scans stop after Budget and resume on the next call
+type DirSizer struct {
+	TTL       time.Duration // How long a complete total is reused without rescanning
+	Refresh   time.Duration // Re-list directories at least this often
+	Budget    time.Duration // Maximum time spent scanning per call
+	CachePath string        // Optional file caching the scan state
+
+	mu     sync.Mutex
+	trees  map[string]*dirTree
+	loaded bool
+}
+
+// NewDirSizer creates a directory sizer persisted at cachePath ("" keeps state in memory)
+func NewDirSizer(cachePath string) *DirSizer {
+	return &DirSizer{
+		TTL:       5 * time.Minute,
+		Refresh:   time.Hour,
+		Budget:    500 * time.Millisecond,
+		CachePath: cachePath,
+		trees:     make(map[string]*dirTree),
+	}
+}
+
+// Size returns the total apparent size of files under root
+// When the scan runs out of time, the last complete total (or 0) is returned
+// with ErrScanIncomplete
+func (d *DirSizer) Size(ctx context.Context, root string) (uint64, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	root = filepath.Clean(root)
+	d.load()
+	tree, ok := d.trees[root]
+	if !ok {
+		tree = &dirTree{Nodes: make(map[string]*dirNode)}
+		d.trees[root] = tree
+	}
+	if tree.Complete && time.Since(tree.ScannedAt) < d.TTL {
+		return tree.Total, nil
+	}
+	if _, err := os.Stat(root); err != nil {
+		delete(d.trees, root)
+		return 0, err
+	}
+
+	scanCtx, cancel := context.WithTimeout(ctx, d.Budget)
+	defer cancel()
+
+	now := time.Now()
+	seen := make(map[string]bool, len(tree.Nodes))
+	total, err := d.scan(scanCtx, root, ".", tree, seen, now)
+
+	// Forget directories that no longer exist, unless the scan stopped early
+	if err == nil {
+		for rel := range tree.Nodes {
+			if !seen[rel] {
+				delete(tree.Nodes, rel)
+			}
+		}
+		tree.Total = total
+		tree.Complete = true
+		tree.ScannedAt = now
+	}
+	d.save()
+
+	if err != nil {
+		return tree.Total, ErrScanIncomplete
+	}
+	return total, nil
+}
+
+// scan returns the size of dir (relative to root), reusing cached listings
+func (d *DirSizer) scan(ctx context.Context, root, rel string, tree *dirTree, seen map[string]bool, now time.Time) (uint64, error) {
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+	seen[rel] = true
+	path := filepath.Join(root, rel)
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		return 0, nil // Removed during the scan
+	}
+	node := tree.Nodes[rel]
+	if node == nil || !node.ModTime.Equal(info.ModTime()) || now.Sub(node.ScannedAt) >= d.Refresh {
+		if node, err = listDir(path, info.ModTime(), now); err != nil {
+			return 0, nil // Unreadable directories count as empty
+		}
+		tree.Nodes[rel] = node
+	}
+
+	total := node.Files
+	for _, sub := range node.Subdirs {
+		size, err := d.scan(ctx, root, filepath.Join(rel, sub), tree, seen, now)
+		if err != nil {
+			return 0, err
+		}
+		total += size
+	}
+	return total, nil
+}
+
+// listDir reads one directory's direct file sizes and subdirectory names
+// Symlinks are not followed
+func listDir(path string, modTime, now time.Time) (*dirNode, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	node := &dirNode{ModTime: modTime, ScannedAt: now}
+	for _, entry := range entries {
+		switch {
+		case entry.IsDir():
+			node.Subdirs = append(node.Subdirs, entry.Name())
+		case entry.Type().IsRegular():
+			if info, err := entry.Info(); err == nil {
+				node.Files += uint64(info.Size())
+			}
+		}
+	}
+	return node, nil
+}
+
+// load reads the persisted scan state once; a missing or corrupt file starts empty
+func (d *DirSizer) load() {
+	if d.loaded || d.CachePath == "" {
+		return
+	}
+	d.loaded = true
+	var trees map[string]*dirTree
+	if filecache.Load(d.CachePath, &trees) {
+		for root, tree := range trees {
+			if tree != nil && tree.Nodes != nil {
+				d.trees[root] = tree
+			}
+		}
+	}
+}
+
+// save atomically replaces the scan state file
+func (d *DirSizer) save() {
+	_ = filecache.Save(d.CachePath, d.trees)
+}
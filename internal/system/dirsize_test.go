@@ -0,0 +1,94 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeTree creates files of the given sizes under root
+func writeTree(t *testing.T, root string, files map[string]int) {
+	t.Helper()
+	for name, size := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDirSizer_Size(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]int{
+		"a.txt":         100,
+		"sub/b.txt":     200,
+		"sub/deep/c.go": 300,
+	})
+
+	sizer := NewDirSizer("")
+	size, err := sizer.Size(context.Background(), root)
+	if err != nil || size != 600 {
+		t.Fatalf("Size() = %d, %v; want 600", size, err)
+	}
+
+	// Within the TTL the cached total is returned
+	writeTree(t, root, map[string]int{"new.txt": 1000})
+	if size, _ := sizer.Size(context.Background(), root); size != 600 {
+		t.Errorf("Size() within TTL = %d, want cached 600", size)
+	}
+
+	// After the TTL, only directories whose mtime changed are listed again
+	sizer.TTL = 0
+	os.RemoveAll(filepath.Join(root, "sub", "deep"))
+	if size, _ := sizer.Size(context.Background(), root); size != 1300 {
+		t.Errorf("Size() after changes = %d, want 1300", size)
+	}
+	if _, ok := sizer.trees[filepath.Clean(root)].Nodes[filepath.Join("sub", "deep")]; ok {
+		t.Error("removed directory should be dropped from the cache")
+	}
+}
+
+func TestDirSizer_Persists(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]int{"x/y/z.bin": 4096})
+	cachePath := filepath.Join(t.TempDir(), "dirsizes.json")
+
+	if size, err := NewDirSizer(cachePath).Size(context.Background(), root); err != nil || size != 4096 {
+		t.Fatalf("Size() = %d, %v", size, err)
+	}
+
+	// A new sizer, as in the next statusline process, reuses the saved total
+	writeTree(t, root, map[string]int{"more.bin": 10})
+	if size, _ := NewDirSizer(cachePath).Size(context.Background(), root); size != 4096 {
+		t.Errorf("Size() from cache = %d, want 4096", size)
+	}
+}
+
+func TestDirSizer_Incomplete(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]int{"a/1": 1, "b/2": 2})
+
+	sizer := NewDirSizer("")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := sizer.Size(ctx, root); !errors.Is(err, ErrScanIncomplete) {
+		t.Errorf("Size() with expired budget error = %v, want ErrScanIncomplete", err)
+	}
+
+	// The next call completes the scan
+	sizer.Budget = time.Minute
+	if size, err := sizer.Size(context.Background(), root); err != nil || size != 3 {
+		t.Errorf("Size() = %d, %v; want 3", size, err)
+	}
+
+	if _, err := sizer.Size(context.Background(), filepath.Join(root, "missing")); err == nil {
+		t.Error("Size() should fail for a missing directory")
+	}
+}
@@ -0,0 +1,106 @@
+package system
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Environment is the kind of container or virtual machine the process runs in
+type Environment string
+
+const (
+	EnvHost         Environment = ""             // Not in a container
+	EnvDocker       Environment = "docker"       // Docker, or another runtime using containerd
+	EnvPodman       Environment = "podman"       // Podman
+	EnvKubernetes   Environment = "kubernetes"   // A Kubernetes pod
+	EnvLXC          Environment = "lxc"          // An LXC or systemd-nspawn container
+	EnvWSL          Environment = "wsl"          // Windows Subsystem for Linux
+	EnvDevcontainer Environment = "devcontainer" // A VS Code dev container
+	EnvCodespaces   Environment = "codespaces"   // A GitHub Codespace
+)
+
+// Contained reports whether files may be shared with another system through
+// bind mounts or a translation layer, where inotify often misses changes
+// made on the other side
+func (e Environment) Contained() bool {
+	return e != EnvHost
+}
+
+// envProbe reads what environment detection looks at; tests replace it
+type envProbe struct {
+	getenv   func(key string) string
+	readFile func(path string) ([]byte, error)
+	exists   func(path string) bool
+}
+
+var (
+	environment     Environment
+	environmentOnce sync.Once
+)
+
+// DetectEnvironment returns the container or virtual machine the process
+// runs in, from environment markers, runtime files and cgroups. Detected once
+func DetectEnvironment() Environment {
+	environmentOnce.Do(func() {
+		environment = detectEnvironment(envProbe{
+			getenv:   os.Getenv,
+			readFile: os.ReadFile,
+			exists: func(path string) bool {
+				_, err := os.Stat(path)
+				return err == nil
+			},
+		})
+	})
+	return environment
+}
+
+// detectEnvironment checks the most specific markers first: a dev container
+// or Codespace also looks like a Docker container
+func detectEnvironment(p envProbe) Environment {
+	switch {
+	case p.getenv("CODESPACES") == "true":
+		return EnvCodespaces
+	case p.getenv("REMOTE_CONTAINERS") == "true", p.getenv("DEVCONTAINER") == "true":
+		return EnvDevcontainer
+	case p.getenv("KUBERNETES_SERVICE_HOST") != "":
+		return EnvKubernetes
+	case p.exists("/run/.containerenv"):
+		return EnvPodman
+	case p.exists("/.dockerenv"):
+		return EnvDocker
+	}
+
+	// Set by podman, LXC and systemd-nspawn for the container's init
+	switch value := p.getenv("container"); value {
+	case "":
+	case "podman":
+		return EnvPodman
+	case "docker", "oci":
+		return EnvDocker
+	default:
+		return EnvLXC
+	}
+
+	if cgroup, err := p.readFile("/proc/1/cgroup"); err == nil {
+		switch {
+		case bytes.Contains(cgroup, []byte("kubepods")):
+			return EnvKubernetes
+		case bytes.Contains(cgroup, []byte("libpod")):
+			return EnvPodman
+		case bytes.Contains(cgroup, []byte("docker")), bytes.Contains(cgroup, []byte("containerd")):
+			return EnvDocker
+		case bytes.Contains(cgroup, []byte("/lxc")):
+			return EnvLXC
+		}
+	}
+
+	if p.getenv("WSL_DISTRO_NAME") != "" {
+		return EnvWSL
+	}
+	if version, err := p.readFile("/proc/version"); err == nil && strings.Contains(strings.ToLower(string(version)), "microsoft") {
+		return EnvWSL
+	}
+	return EnvHost
+}
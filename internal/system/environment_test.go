@@ -0,0 +1,51 @@
+package system
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDetectEnvironment(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   map[string]string
+		files map[string]string
+		want  Environment
+	}{
+		{name: "plain host", files: map[string]string{"/proc/1/cgroup": "0::/init.scope\n", "/proc/version": "Linux version 6.8.0-generic"}, want: EnvHost},
+		{name: "docker", files: map[string]string{"/.dockerenv": ""}, want: EnvDocker},
+		{name: "podman", files: map[string]string{"/run/.containerenv": "", "/.dockerenv": ""}, want: EnvPodman},
+		{name: "podman marker", env: map[string]string{"container": "podman"}, want: EnvPodman},
+		{name: "systemd-nspawn", env: map[string]string{"container": "systemd-nspawn"}, want: EnvLXC},
+		{name: "docker cgroup", files: map[string]string{"/proc/1/cgroup": "12:memory:/docker/3f2a9c\n"}, want: EnvDocker},
+		{name: "kubernetes", env: map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1"}, files: map[string]string{"/.dockerenv": ""}, want: EnvKubernetes},
+		{name: "kubernetes cgroup", files: map[string]string{"/proc/1/cgroup": "0::/kubepods/besteffort/pod1\n"}, want: EnvKubernetes},
+		{name: "devcontainer", env: map[string]string{"REMOTE_CONTAINERS": "true"}, files: map[string]string{"/.dockerenv": ""}, want: EnvDevcontainer},
+		{name: "codespaces", env: map[string]string{"CODESPACES": "true", "REMOTE_CONTAINERS": "true"}, want: EnvCodespaces},
+		{name: "wsl", env: map[string]string{"WSL_DISTRO_NAME": "Ubuntu"}, want: EnvWSL},
+		{name: "wsl kernel", files: map[string]string{"/proc/version": "Linux version 5.15.153.1-microsoft-standard-WSL2"}, want: EnvWSL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detectEnvironment(envProbe{
+				getenv: func(key string) string { return tt.env[key] },
+				readFile: func(path string) ([]byte, error) {
+					if content, ok := tt.files[path]; ok {
+						return []byte(content), nil
+					}
+					return nil, os.ErrNotExist
+				},
+				exists: func(path string) bool {
+					_, ok := tt.files[path]
+					return ok
+				},
+			})
+			if got != tt.want {
+				t.Errorf("detectEnvironment() = %q, want %q", got, tt.want)
+			}
+			if got.Contained() != (tt.want != EnvHost) {
+				t.Errorf("Contained() = %v for %q", got.Contained(), got)
+			}
+		})
+	}
+}
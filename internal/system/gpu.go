@@ -0,0 +1,207 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/filecache"
+)
+
+// GPUInfo contains usage of one GPU
+type GPUInfo struct {
+	Name        string  `json:"name"`
+	UtilPercent float64 `json:"util_percent"`
+	MemUsed     uint64  `json:"mem_used"`  // Bytes; 0 with unified memory
+	MemTotal    uint64  `json:"mem_total"` // Bytes; 0 with unified memory
+}
+
+// MemPercent returns VRAM usage as a percentage, or 0 when unknown
+func (g GPUInfo) MemPercent() float64 {
+	if g.MemTotal == 0 {
+		return 0
+	}
+	return float64(g.MemUsed) / float64(g.MemTotal) * 100
+}
+
+// ErrNoGPU is returned when no supported GPU tool is installed
+var ErrNoGPU = errors.New("no usable GPU tool (nvidia-smi, rocm-smi, or powermetrics as root)")
+
+// gpuResult is the cached outcome of a query
+type gpuResult struct {
+	GPUs      []GPUInfo `json:"gpus"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// GPUReader queries GPU tools and caches the result
+// The tools take tens of milliseconds or more, so with a cache path the
+// result is shared across statusline processes
+type GPUReader struct {
+	TTL       time.Duration
+	Timeout   time.Duration
+	CachePath string // Optional file caching the last result
+
+	mu   sync.Mutex
+	last gpuResult
+}
+
+// NewGPUReader creates a GPU reader with a 10 second cache
+func NewGPUReader(cachePath string) *GPUReader {
+	return &GPUReader{
+		TTL:       10 * time.Second,
+		Timeout:   2 * time.Second,
+		CachePath: cachePath,
+	}
+}
+
+// GPUs returns the cached GPU usage, querying again once it is older than TTL
+func (r *GPUReader) GPUs(ctx context.Context) ([]GPUInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if r.last.CheckedAt.IsZero() {
+		r.last = r.readCache()
+	}
+	if r.last.CheckedAt.IsZero() || now.Sub(r.last.CheckedAt) >= r.TTL {
+		queryCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+		gpus, err := queryGPUs(queryCtx)
+		cancel()
+		r.last = gpuResult{GPUs: gpus, CheckedAt: now}
+		if err != nil {
+			r.last.Error = err.Error()
+		}
+		r.writeCache(r.last)
+	}
+
+	switch r.last.Error {
+	case "":
+		return r.last.GPUs, nil
+	case ErrNoGPU.Error():
+		return nil, ErrNoGPU
+	default:
+		return nil, errors.New(r.last.Error)
+	}
+}
+
+// queryGPUs tries each supported GPU tool in turn
+func queryGPUs(ctx context.Context) ([]GPUInfo, error) {
+	if path, err := exec.LookPath("nvidia-smi"); err == nil {
+		output, err := exec.CommandContext(ctx, path,
+			"--query-gpu=name,utilization.gpu,memory.used,memory.total",
+			"--format=csv,noheader,nounits").Output()
+		if err != nil {
+			return nil, fmt.Errorf("nvidia-smi: %w", err)
+		}
+		return parseNvidiaSmi(string(output))
+	}
+	if path, err := exec.LookPath("rocm-smi"); err == nil {
+		output, err := exec.CommandContext(ctx, path, "--showuse", "--showmeminfo", "vram", "--showproductname", "--json").Output()
+		if err != nil {
+			return nil, fmt.Errorf("rocm-smi: %w", err)
+		}
+		return parseRocmSmi(output)
+	}
+	// powermetrics reads GPU residency on macOS but only works as root
+	if runtime.GOOS == "darwin" && os.Geteuid() == 0 {
+		output, err := exec.CommandContext(ctx, "powermetrics", "--samplers", "gpu_power", "-n", "1", "-i", "500").Output()
+		if err != nil {
+			return nil, fmt.Errorf("powermetrics: %w", err)
+		}
+		return parsePowermetricsGPU(string(output))
+	}
+	return nil, ErrNoGPU
+}
+
+// parseNvidiaSmi parses CSV rows of name, utilization %, used MiB, total MiB
+func parseNvidiaSmi(output string) ([]GPUInfo, error) {
+	var gpus []GPUInfo
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) != 4 {
+			continue
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		// Unsupported metrics read "[N/A]" and parse as 0
+		util, _ := strconv.ParseFloat(fields[1], 64)
+		used, _ := strconv.ParseUint(fields[2], 10, 64)
+		total, _ := strconv.ParseUint(fields[3], 10, 64)
+		gpus = append(gpus, GPUInfo{Name: fields[0], UtilPercent: util, MemUsed: used << 20, MemTotal: total << 20})
+	}
+	if len(gpus) == 0 {
+		return nil, fmt.Errorf("no GPUs in nvidia-smi output")
+	}
+	return gpus, nil
+}
+
+// parseRocmSmi parses `rocm-smi --json`, which maps card names to string fields
+//
+//	{"card0": {"GPU use (%)": "12", "VRAM Total Memory (B)": "17163091968", "VRAM Total Used Memory (B)": "1048576"}}
+func parseRocmSmi(output []byte) ([]GPUInfo, error) {
+	var cards map[string]map[string]string
+	if err := json.Unmarshal(output, &cards); err != nil {
+		return nil, fmt.Errorf("failed to parse rocm-smi output: %w", err)
+	}
+	names := make([]string, 0, len(cards))
+	for name := range cards {
+		if strings.HasPrefix(name, "card") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	gpus := make([]GPUInfo, 0, len(names))
+	for _, name := range names {
+		card := cards[name]
+		gpu := GPUInfo{Name: name}
+		if product := card["Card series"]; product != "" {
+			gpu.Name = product
+		}
+		gpu.UtilPercent, _ = strconv.ParseFloat(card["GPU use (%)"], 64)
+		gpu.MemUsed, _ = strconv.ParseUint(card["VRAM Total Used Memory (B)"], 10, 64)
+		gpu.MemTotal, _ = strconv.ParseUint(card["VRAM Total Memory (B)"], 10, 64)
+		gpus = append(gpus, gpu)
+	}
+	if len(gpus) == 0 {
+		return nil, fmt.Errorf("no GPUs in rocm-smi output")
+	}
+	return gpus, nil
+}
+
+// powermetricsGPU matches the GPU residency line of powermetrics, e.g.
+//
+//	GPU HW active residency:  23.45% (389 MHz: .12% ...)
+var powermetricsGPU = regexp.MustCompile(`GPU (?:HW )?active residency:\s*([\d.]+)%`)
+
+// parsePowermetricsGPU parses the gpu_power sampler; Apple GPUs share system memory
+func parsePowermetricsGPU(output string) ([]GPUInfo, error) {
+	match := powermetricsGPU.FindStringSubmatch(output)
+	if match == nil {
+		return nil, fmt.Errorf("no GPU residency in powermetrics output")
+	}
+	util, _ := strconv.ParseFloat(match[1], 64)
+	return []GPUInfo{{Name: "Apple GPU", UtilPercent: util}}, nil
+}
+
+func (r *GPUReader) readCache() gpuResult {
+	var result gpuResult
+	filecache.Load(r.CachePath, &result)
+	return result
+}
+
+func (r *GPUReader) writeCache(result gpuResult) {
+	_ = filecache.Save(r.CachePath, result)
+}
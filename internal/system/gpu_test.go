@@ -0,0 +1,82 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseNvidiaSmi(t *testing.T) {
+	output := `NVIDIA GeForce RTX 4090, 45, 3277, 24564
+Tesla T4, [N/A], 100, 15360
+`
+	gpus, err := parseNvidiaSmi(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gpus) != 2 {
+		t.Fatalf("parseNvidiaSmi() returned %d GPUs, want 2", len(gpus))
+	}
+	if gpus[0].Name != "NVIDIA GeForce RTX 4090" || gpus[0].UtilPercent != 45 || gpus[0].MemUsed != 3277<<20 || gpus[0].MemTotal != 24564<<20 {
+		t.Errorf("gpus[0] = %+v", gpus[0])
+	}
+	if gpus[1].UtilPercent != 0 {
+		t.Errorf("gpus[1].UtilPercent = %v, want 0 for [N/A]", gpus[1].UtilPercent)
+	}
+
+	if _, err := parseNvidiaSmi("No devices were found\n"); err == nil {
+		t.Error("parseNvidiaSmi() should fail without GPUs")
+	}
+}
+
+func TestParseRocmSmi(t *testing.T) {
+	output := []byte(`{
+  "card1": {"GPU use (%)": "80", "VRAM Total Memory (B)": "8589934592", "VRAM Total Used Memory (B)": "4294967296"},
+  "card0": {"GPU use (%)": "12", "VRAM Total Memory (B)": "17179869184", "VRAM Total Used Memory (B)": "1073741824", "Card series": "Radeon RX 7900 XTX"},
+  "system": {"Driver version": "6.7.0"}
+}`)
+	gpus, err := parseRocmSmi(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gpus) != 2 {
+		t.Fatalf("parseRocmSmi() returned %d GPUs, want 2", len(gpus))
+	}
+	if gpus[0].Name != "Radeon RX 7900 XTX" || gpus[0].UtilPercent != 12 {
+		t.Errorf("gpus[0] = %+v, want card0 first", gpus[0])
+	}
+	if gpus[1].MemPercent() != 50 {
+		t.Errorf("gpus[1].MemPercent() = %v, want 50", gpus[1].MemPercent())
+	}
+}
+
+func TestParsePowermetricsGPU(t *testing.T) {
+	gpus, err := parsePowermetricsGPU("**** GPU usage ****\n\nGPU HW active frequency: 389 MHz\nGPU HW active residency:  23.45% (389 MHz: 23%)\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gpus[0].UtilPercent != 23.45 || gpus[0].MemTotal != 0 {
+		t.Errorf("parsePowermetricsGPU() = %+v", gpus[0])
+	}
+}
+
+func TestGPUReaderCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gpu.json")
+	reader := NewGPUReader(path)
+	reader.writeCache(gpuResult{GPUs: []GPUInfo{{Name: "cached", UtilPercent: 10}}, CheckedAt: time.Now()})
+
+	// A fresh cache entry is used without running any GPU tool
+	gpus, err := reader.GPUs(context.Background())
+	if err != nil || len(gpus) != 1 || gpus[0].Name != "cached" {
+		t.Errorf("GPUs() = %+v, %v; want the cached GPU", gpus, err)
+	}
+
+	// A cached "no GPU" result keeps its sentinel error
+	other := NewGPUReader(path)
+	other.writeCache(gpuResult{Error: ErrNoGPU.Error(), CheckedAt: time.Now()})
+	if _, err := other.GPUs(context.Background()); !errors.Is(err, ErrNoGPU) {
+		t.Errorf("GPUs() error = %v, want ErrNoGPU", err)
+	}
+}
@@ -0,0 +1,36 @@
+package system
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+var (
+	hostname     string
+	hostnameOnce sync.Once
+)
+
+// Hostname returns the machine's hostname without its domain, e.g. "devbox"
+// for devbox.example.com, or "" when unknown. Looked up once
+func Hostname() string {
+	hostnameOnce.Do(func() {
+		name, err := os.Hostname()
+		if err == nil {
+			hostname = ShortHostname(name)
+		}
+	})
+	return hostname
+}
+
+// ShortHostname strips the domain from a hostname
+func ShortHostname(name string) string {
+	name, _, _ = strings.Cut(name, ".")
+	return name
+}
+
+// InSSHSession reports whether the process runs in an SSH login, from the
+// variables sshd sets for it
+func InSSHSession() bool {
+	return os.Getenv("SSH_CONNECTION") != "" || os.Getenv("SSH_CLIENT") != "" || os.Getenv("SSH_TTY") != ""
+}
@@ -33,14 +33,21 @@ type Monitor struct {
 	memory         MemoryInfo
 	disk           DiskInfo
 	fd             FDInfo
-	currentDir     string
-	language       string
+	network        NetworkInfo
+	thermal        ThermalInfo
+	samples        *SampleStore
+	workspaces     map[string]workspaceEntry // Keyed by directory; see Workspace
 }
 
 // CPUInfo contains CPU usage information
 type CPUInfo struct {
-	UsagePercent float64
+	UsagePercent float64   // Busy time since the previous sample, or since boot until one exists
+	PerCore      []float64 // Per-core usage, set once Sampled
 	CoreCount    int
+	Sampled      bool    // False while usage is the since-boot average
+	Load1        float64 // Load averages over 1, 5 and 15 minutes
+	Load5        float64
+	Load15       float64
 }
 
 // MemoryInfo contains memory usage information
@@ -69,6 +76,7 @@ type FDInfo struct {
 func NewMonitor() *Monitor {
 	return &Monitor{
 		updateInterval: 5 * time.Second,
+		samples:        NewSampleStore(""),
 	}
 }
 
@@ -84,9 +92,7 @@ func (m *Monitor) Update() error {
 		}
 
 		// Update CPU
-		if cpu, err := getCPUUsage(); err == nil {
-			m.cpu = cpu
-		}
+		m.cpu = m.cpuUsage(time.Now())
 
 		// Update Memory
 		if mem, err := getMemoryUsage(); err == nil {
@@ -94,7 +100,7 @@ func (m *Monitor) Update() error {
 		}
 
 		// Update Disk
-		if disk, err := getDiskUsage(); err == nil {
+		if disk, err := getDiskUsage(processDir()); err == nil {
 			m.disk = disk
 		}
 
@@ -103,19 +109,57 @@ func (m *Monitor) Update() error {
 			m.fd = fd
 		}
 
-		// Update current directory
-		if cwd, err := os.Getwd(); err == nil {
-			m.currentDir = cwd
+		// Update temperature and fan speed
+		if thermal, err := getThermal(); err == nil {
+			m.thermal = thermal
 		}
 
-		// Update language detection
-		m.language = DetectLanguage(m.currentDir)
+		// Update network throughput
+		if rx, tx, err := getNetCounters(); err == nil {
+			m.network = m.networkRates(rx, tx, time.Now())
+		}
 
 		m.lastUpdate = time.Now()
 		return nil
 	})
 }
 
+// SetSampleStore sets where counter samples are kept between updates
+// Use a persisted store so rates work when each render is a new process
+func (m *Monitor) SetSampleStore(store *SampleStore) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.samples = store
+}
+
+// networkRates turns cumulative byte counters into per-second rates
+func (m *Monitor) networkRates(rx, tx uint64, now time.Time) NetworkInfo {
+	current := Sample{At: now, Values: []uint64{rx, tx}}
+	prev, ok := m.samples.Swap(netSampleKey, current)
+	if !ok {
+		return NetworkInfo{}
+	}
+	rates, ok := current.Rates(prev, netSampleMaxAge)
+	if !ok {
+		return NetworkInfo{}
+	}
+	return NetworkInfo{RxBytesPerSec: rates[0], TxBytesPerSec: rates[1], Sampled: true}
+}
+
+// GetNetwork returns the current network throughput
+func (m *Monitor) GetNetwork() NetworkInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.network
+}
+
+// GetThermal returns the current CPU temperature and fan speed
+func (m *Monitor) GetThermal() ThermalInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.thermal
+}
+
 // GetCPU returns the current CPU usage
 func (m *Monitor) GetCPU() CPUInfo {
 	m.mu.RLock()
@@ -144,18 +188,15 @@ func (m *Monitor) GetFD() FDInfo {
 	return m.fd
 }
 
-// GetCurrentDir returns the current working directory
+// GetCurrentDir returns the process's working directory
+// Sections should use the workspace directory Claude Code reported instead
 func (m *Monitor) GetCurrentDir() string {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-	return m.currentDir
+	return processDir()
 }
 
-// GetLanguage returns the detected programming language
+// GetLanguage returns the language detected in the process's working directory
 func (m *Monitor) GetLanguage() string {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-	return m.language
+	return m.Workspace(processDir()).Language
 }
 
 // GetThresholdLevel returns the color threshold level for a percentage
@@ -170,12 +211,32 @@ func GetThresholdLevel(percent float64) ThresholdLevel {
 
 // FormatCPUDisplay formats CPU usage for display
 func (m *Monitor) FormatCPUDisplay() string {
-	if m.cpu.UsagePercent == 0 {
+	if m.cpu.UsagePercent == 0 && !m.cpu.Sampled {
 		return ""
 	}
 	return fmt.Sprintf("CPU %.0f%%", m.cpu.UsagePercent)
 }
 
+// FormatCPUCoresDisplay formats per-core usage as one bar per core, e.g. "▂▇▁▃"
+func (m *Monitor) FormatCPUCoresDisplay() string {
+	if len(m.cpu.PerCore) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, percent := range m.cpu.PerCore {
+		b.WriteRune(usageBar(percent))
+	}
+	return b.String()
+}
+
+// FormatLoadDisplay formats load averages for display
+func (m *Monitor) FormatLoadDisplay() string {
+	if m.cpu.Load1 == 0 && m.cpu.Load5 == 0 && m.cpu.Load15 == 0 {
+		return ""
+	}
+	return fmt.Sprintf("LOAD %.2f %.2f %.2f", m.cpu.Load1, m.cpu.Load5, m.cpu.Load15)
+}
+
 // FormatMemoryDisplay formats memory usage for display
 func (m *Monitor) FormatMemoryDisplay() string {
 	if m.memory.Total == 0 {
@@ -187,11 +248,16 @@ func (m *Monitor) FormatMemoryDisplay() string {
 
 // FormatDiskDisplay formats disk usage for display
 func (m *Monitor) FormatDiskDisplay() string {
-	if m.disk.Total == 0 {
+	return FormatDisk(m.disk)
+}
+
+// FormatDisk formats a partition's usage for display
+func FormatDisk(disk DiskInfo) string {
+	if disk.Total == 0 {
 		return ""
 	}
 
-	return fmt.Sprintf("DISK %.0f%%", m.disk.Percent)
+	return fmt.Sprintf("DISK %.0f%%", disk.Percent)
 }
 
 // FormatFDDisplay formats file descriptor count for display
@@ -203,19 +269,49 @@ func (m *Monitor) FormatFDDisplay() string {
 	return fmt.Sprintf("FD %d", m.fd.Count)
 }
 
-// FormatDirDisplay formats the current directory for display
+// FormatNetworkDisplay formats network throughput for display
+func (m *Monitor) FormatNetworkDisplay() string {
+	if !m.network.Sampled {
+		return ""
+	}
+	return fmt.Sprintf("NET ↓%s ↑%s", formatRate(m.network.RxBytesPerSec), formatRate(m.network.TxBytesPerSec))
+}
+
+// FormatTemperatureDisplay formats the CPU temperature for display
+func (m *Monitor) FormatTemperatureDisplay() string {
+	if m.thermal.CPUTemp == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.0f°C", m.thermal.CPUTemp)
+}
+
+// FormatFanDisplay formats the fan speed for display
+func (m *Monitor) FormatFanDisplay() string {
+	if m.thermal.FanRPM == 0 {
+		return ""
+	}
+	return fmt.Sprintf("FAN %drpm", m.thermal.FanRPM)
+}
+
+// FormatDirDisplay formats the process's working directory for display
 func (m *Monitor) FormatDirDisplay() string {
-	if m.currentDir == "" {
+	return FormatDir(m.GetCurrentDir())
+}
+
+// FormatDir formats a directory for display, relative to home as ~ and
+// shortened to its last component when long
+func FormatDir(path string) string {
+	if path == "" {
 		return ""
 	}
 
 	// Get the base directory name
-	dir := filepath.Base(m.currentDir)
+	dir := filepath.Base(path)
 
 	// If we're in home directory, show ~
 	if homeDir, err := os.UserHomeDir(); err == nil {
-		if strings.HasPrefix(m.currentDir, homeDir) {
-			rel := strings.TrimPrefix(m.currentDir, homeDir)
+		if strings.HasPrefix(path, homeDir) {
+			rel := strings.TrimPrefix(path, homeDir)
 			if rel == "" {
 				dir = "~"
 			} else {
@@ -248,84 +344,19 @@ func (m *Monitor) FormatDirDisplay() string {
 	return dir
 }
 
-// FormatLanguageDisplay formats the language name with icon
+// FormatLanguageDisplay formats the language of the process's working directory with icon
 func (m *Monitor) FormatLanguageDisplay() string {
-	if m.language == "" {
-		return ""
-	}
-
-	icon := getLanguageIcon(m.language)
-	return fmt.Sprintf("%s %s", icon, m.language)
-}
-
-// getCPUUsage retrieves CPU usage on Linux/macOS
-func getCPUUsage() (CPUInfo, error) {
-	if runtime.GOOS == "linux" {
-		return getLinuxCPUUsage()
-	} else if runtime.GOOS == "darwin" {
-		return getDarwinCPUUsage()
-	}
-
-	// Fallback: use 0
-	return CPUInfo{CoreCount: runtime.NumCPU()}, nil
-}
-
-// getLinuxCPUUsage reads CPU usage from /proc/stat
-func getLinuxCPUUsage() (CPUInfo, error) {
-	file, err := os.Open("/proc/stat")
-	if err != nil {
-		return CPUInfo{}, err
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	if !scanner.Scan() {
-		return CPUInfo{}, scanner.Err()
-	}
-
-	line := scanner.Text()
-	fields := strings.Fields(line)
-
-	if len(fields) < 8 || fields[0] != "cpu" {
-		return CPUInfo{}, fmt.Errorf("invalid /proc/stat format")
-	}
-
-	// Parse CPU time fields
-	// user, nice, system, idle, iowait, irq, softirq, steal
-	user, _ := strconv.ParseFloat(fields[1], 64)
-	nice, _ := strconv.ParseFloat(fields[2], 64)
-	system, _ := strconv.ParseFloat(fields[3], 64)
-	idle, _ := strconv.ParseFloat(fields[4], 64)
-
-	total := user + nice + system + idle
-	usage := total - idle
-
-	var percent float64
-	if total > 0 {
-		percent = (usage / total) * 100
-	}
-
-	return CPUInfo{
-		UsagePercent: percent,
-		CoreCount:    runtime.NumCPU(),
-	}, nil
+	return FormatLanguage(m.GetLanguage())
 }
 
-// getDarwinCPUUsage reads CPU usage on macOS via sysctl
-func getDarwinCPUUsage() (CPUInfo, error) {
-	cmd := exec.Command("sysctl", "-n", "machdep.cpu.thread_count")
-	output, err := cmd.Output()
-	if err != nil {
-		return CPUInfo{}, err
+// FormatLanguage formats a language name with its icon
+func FormatLanguage(language string) string {
+	if language == "" {
+		return ""
 	}
 
-	cores, _ := strconv.Atoi(strings.TrimSpace(string(output)))
-
-	// For simplicity on macOS, return 0 usage (would require more complex sysctl calls)
-	return CPUInfo{
-		UsagePercent: 0,
-		CoreCount:    cores,
-	}, nil
+	icon := getLanguageIcon(language)
+	return fmt.Sprintf("%s %s", icon, language)
 }
 
 // getMemoryUsage retrieves memory usage
@@ -412,25 +443,24 @@ func getDarwinMemoryUsage() (MemoryInfo, error) {
 	}, nil
 }
 
-// getDiskUsage retrieves disk usage for current partition
-func getDiskUsage() (DiskInfo, error) {
-	cwd, err := os.Getwd()
-	if err != nil {
-		return DiskInfo{}, err
+// getDiskUsage retrieves disk usage for the partition holding dir
+func getDiskUsage(dir string) (DiskInfo, error) {
+	if dir == "" {
+		return DiskInfo{}, fmt.Errorf("no directory to measure")
 	}
 
 	var total, available uint64
 
 	// Use df command for cross-platform compatibility
-	cmd := exec.Command("df", "-k", cwd)
+	cmd := exec.Command("df", "-k", dir)
 	output, err := cmd.Output()
 	if err != nil {
-		return DiskInfo{Path: cwd}, nil
+		return DiskInfo{Path: dir}, nil
 	}
 
 	lines := strings.Split(string(output), "\n")
 	if len(lines) < 2 {
-		return DiskInfo{Path: cwd}, nil
+		return DiskInfo{Path: dir}, nil
 	}
 
 	// Parse df output
@@ -463,7 +493,7 @@ func getDiskUsage() (DiskInfo, error) {
 		Used:      used,
 		Available: available,
 		Percent:   percent,
-		Path:      cwd,
+		Path:      dir,
 	}, nil
 }
 
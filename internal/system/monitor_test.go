@@ -1,6 +1,8 @@
 package system
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -194,3 +196,38 @@ func TestMonitor_ForceUpdate(t *testing.T) {
 		t.Errorf("ForceUpdate() error = %v", err)
 	}
 }
+
+func TestMonitor_Workspace(t *testing.T) {
+	goDir, pyDir := t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(goDir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(pyDir, "app.py"), []byte("print()\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each directory is measured on its own, whatever the process's working directory
+	m := NewMonitor()
+	if got := m.Workspace(goDir); got.Dir != goDir || got.Language != "Go" {
+		t.Errorf("Workspace(goDir) = %+v, want Dir %s and Language Go", got, goDir)
+	}
+	if got := m.Workspace(pyDir).Language; got != "Python" {
+		t.Errorf("Workspace(pyDir).Language = %q, want Python", got)
+	}
+
+	// Cached until the update interval passes
+	if err := os.Remove(filepath.Join(pyDir, "app.py")); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Workspace(pyDir).Language; got != "Python" {
+		t.Errorf("cached Workspace(pyDir).Language = %q, want Python", got)
+	}
+	m.SetUpdateInterval(0)
+	if got := m.Workspace(pyDir).Language; got != "" {
+		t.Errorf("Workspace(pyDir).Language after removal = %q, want empty", got)
+	}
+
+	if got := m.Workspace(""); got != (WorkspaceInfo{}) {
+		t.Errorf("Workspace(\"\") = %+v, want zero", got)
+	}
+}
@@ -0,0 +1,122 @@
+package system
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NetworkInfo contains network throughput across all non-loopback interfaces
+type NetworkInfo struct {
+	RxBytesPerSec float64
+	TxBytesPerSec float64
+	Sampled       bool // False until two samples are available
+}
+
+// netSampleKey identifies network counters in the SampleStore
+const netSampleKey = "net"
+
+// netSampleMaxAge discards samples too old to give a meaningful current rate
+const netSampleMaxAge = 5 * time.Minute
+
+// getNetCounters returns total received and transmitted bytes
+func getNetCounters() (rx, tx uint64, err error) {
+	switch runtime.GOOS {
+	case "linux":
+		file, err := os.Open("/proc/net/dev")
+		if err != nil {
+			return 0, 0, err
+		}
+		defer file.Close()
+		return parseProcNetDev(file)
+	case "darwin":
+		output, err := exec.Command("netstat", "-ib").Output()
+		if err != nil {
+			return 0, 0, fmt.Errorf("netstat: %w", err)
+		}
+		return parseNetstat(string(output))
+	}
+	return 0, 0, fmt.Errorf("network counters not supported on %s", runtime.GOOS)
+}
+
+// parseProcNetDev sums byte counters from /proc/net/dev, skipping loopback
+//
+//	face |bytes    packets errs drop fifo frame compressed multicast|bytes ...
+//	eth0: 1234567  ...
+func parseProcNetDev(r io.Reader) (rx, tx uint64, err error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		name, counters, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || strings.TrimSpace(name) == "lo" {
+			continue
+		}
+		fields := strings.Fields(counters)
+		if len(fields) < 9 {
+			continue
+		}
+		r, _ := strconv.ParseUint(fields[0], 10, 64)
+		t, _ := strconv.ParseUint(fields[8], 10, 64)
+		rx += r
+		tx += t
+	}
+	return rx, tx, scanner.Err()
+}
+
+// parseNetstat sums byte counters from `netstat -ib`, using each interface's
+// link-level row (its Network column starts with "<Link#") and skipping loopback
+func parseNetstat(output string) (rx, tx uint64, err error) {
+	lines := strings.Split(output, "\n")
+	if len(lines) == 0 {
+		return 0, 0, fmt.Errorf("empty netstat output")
+	}
+	header := strings.Fields(lines[0])
+	ibytes, obytes := -1, -1
+	for i, col := range header {
+		switch col {
+		case "Ibytes":
+			ibytes = i
+		case "Obytes":
+			obytes = i
+		}
+	}
+	if ibytes < 0 || obytes < 0 {
+		return 0, 0, fmt.Errorf("unexpected netstat header")
+	}
+
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || !strings.HasPrefix(fields[2], "<Link#") || strings.HasPrefix(fields[0], "lo") {
+			continue
+		}
+		// Link rows have no Address column when the interface has no MAC
+		offset := len(header) - len(fields)
+		if offset < 0 || ibytes-offset >= len(fields) || obytes-offset >= len(fields) {
+			continue
+		}
+		r, _ := strconv.ParseUint(fields[ibytes-offset], 10, 64)
+		t, _ := strconv.ParseUint(fields[obytes-offset], 10, 64)
+		rx += r
+		tx += t
+	}
+	return rx, tx, nil
+}
+
+// formatRate formats bytes per second as "1.2MB/s"
+func formatRate(bytesPerSec float64) string {
+	switch {
+	case bytesPerSec >= 1<<30:
+		return fmt.Sprintf("%.1fGB/s", bytesPerSec/(1<<30))
+	case bytesPerSec >= 1<<20:
+		return fmt.Sprintf("%.1fMB/s", bytesPerSec/(1<<20))
+	case bytesPerSec >= 1<<10:
+		return fmt.Sprintf("%.0fKB/s", bytesPerSec/(1<<10))
+	default:
+		return fmt.Sprintf("%.0fB/s", bytesPerSec)
+	}
+}
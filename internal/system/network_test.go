@@ -0,0 +1,98 @@
+package system
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseProcNetDev(t *testing.T) {
+	input := `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo: 5000      50    0    0    0     0          0         0     5000      50    0    0    0     0       0          0
+  eth0: 1000      10    0    0    0     0          0         0     200       2    0    0    0     0       0          0
+ wlan0: 3000      30    0    0    0     0          0         0     800       8    0    0    0     0       0          0
+`
+	rx, tx, err := parseProcNetDev(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rx != 4000 || tx != 1000 {
+		t.Errorf("parseProcNetDev() = %d, %d; want 4000, 1000 (loopback excluded)", rx, tx)
+	}
+}
+
+func TestParseNetstat(t *testing.T) {
+	output := `Name       Mtu   Network       Address            Ipkts Ierrs     Ibytes    Opkts Oerrs     Obytes  Coll
+lo0        16384 <Link#1>                         9000     0    9000000     9000     0    9000000     0
+lo0        16384 127           localhost          9000     -    9000000     9000     -    9000000     -
+en0        1500  <Link#6>    aa:bb:cc:dd:ee:ff    1000     0    4000000      500     0     100000     0
+en0        1500  192.168.1     192.168.1.10       1000     -    4000000      500     -     100000     -
+utun0      1380  <Link#9>                           10     0       1000       10     0       2000     0
+`
+	rx, tx, err := parseNetstat(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rx != 4001000 || tx != 102000 {
+		t.Errorf("parseNetstat() = %d, %d; want 4001000, 102000", rx, tx)
+	}
+
+	if _, _, err := parseNetstat("garbage\n"); err == nil {
+		t.Error("parseNetstat() should reject output without byte columns")
+	}
+}
+
+func TestSampleRates(t *testing.T) {
+	now := time.Now()
+	prev := Sample{At: now.Add(-2 * time.Second), Values: []uint64{1000, 500}}
+	current := Sample{At: now, Values: []uint64{3000, 400}}
+
+	rates, ok := current.Rates(prev, time.Minute)
+	if !ok {
+		t.Fatal("Rates() should accept a recent sample")
+	}
+	if rates[0] != 1000 || rates[1] != 0 {
+		t.Errorf("Rates() = %v, want [1000 0] (counter reset reports 0)", rates)
+	}
+
+	if _, ok := current.Rates(Sample{}, time.Minute); ok {
+		t.Error("Rates() should reject an empty sample")
+	}
+	if _, ok := current.Rates(prev, time.Second); ok {
+		t.Error("Rates() should reject a sample older than maxAge")
+	}
+}
+
+func TestSampleStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "samples.json")
+	first := Sample{At: time.Now().Add(-time.Second).Round(0), Values: []uint64{1, 2}}
+
+	if _, ok := NewSampleStore(path).Swap("net", first); ok {
+		t.Error("first Swap() should have no previous sample")
+	}
+
+	// A new store, as in the next statusline process, sees the saved sample
+	prev, ok := NewSampleStore(path).Swap("net", Sample{At: time.Now(), Values: []uint64{3, 4}})
+	if !ok || !prev.At.Equal(first.At) || prev.Values[1] != 2 {
+		t.Errorf("Swap() = %+v, %v; want the persisted sample", prev, ok)
+	}
+}
+
+func TestMonitorNetworkRates(t *testing.T) {
+	m := NewMonitor()
+	now := time.Now()
+	if info := m.networkRates(1000, 1000, now); info.Sampled {
+		t.Error("first sample should not produce a rate")
+	}
+	info := m.networkRates(3048, 2024, now.Add(time.Second))
+	if !info.Sampled || info.RxBytesPerSec != 2048 || info.TxBytesPerSec != 1024 {
+		t.Errorf("networkRates() = %+v, want 2048/1024 B/s", info)
+	}
+
+	m.network = info
+	if got := m.FormatNetworkDisplay(); got != "NET ↓2KB/s ↑1KB/s" {
+		t.Errorf("FormatNetworkDisplay() = %q", got)
+	}
+}
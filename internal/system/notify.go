@@ -0,0 +1,28 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+)
+
+// Notify shows a desktop notification with notify-send on Linux and
+// osascript on macOS. Other platforms are silently skipped
+func Notify(ctx context.Context, title, message string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "linux":
+		cmd = exec.CommandContext(ctx, "notify-send", "--app-name=claude-hud", title, message)
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", strconv.Quote(message), strconv.Quote(title))
+		cmd = exec.CommandContext(ctx, "osascript", "-e", script)
+	default:
+		return nil
+	}
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", cmd.Args[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// mprisPrefix is the bus name prefix of MPRIS media players
+const mprisPrefix = "org.mpris.MediaPlayer2."
+
+// Track is what a media player is playing
+type Track struct {
+	Player  string // e.g. "spotify"
+	Title   string
+	Artist  string
+	Album   string
+	Playing bool // False while paused
+}
+
+// GetNowPlaying returns the track of a playing media player, or of a paused
+// one when none is playing. Players whose name contains preferred win ties
+// Reads MPRIS over the D-Bus session bus (via busctl) on Linux, and Spotify
+// or Music (via osascript) on macOS. Returns false when nothing is loaded
+func GetNowPlaying(ctx context.Context, preferred string) (Track, bool, error) {
+	switch runtime.GOOS {
+	case "linux":
+		return mprisNowPlaying(ctx, preferred)
+	case "darwin":
+		output, err := exec.CommandContext(ctx, "osascript", "-e", nowPlayingScript).Output()
+		if err != nil {
+			return Track{}, false, fmt.Errorf("osascript: %w", err)
+		}
+		track, ok := parseOsascriptTrack(string(output))
+		return track, ok, nil
+	}
+	return Track{}, false, nil
+}
+
+// mprisNowPlaying asks every MPRIS player on the session bus for its track
+func mprisNowPlaying(ctx context.Context, preferred string) (Track, bool, error) {
+	output, err := exec.CommandContext(ctx, "busctl", "--user", "--json=short", "call",
+		"org.freedesktop.DBus", "/org/freedesktop/DBus", "org.freedesktop.DBus", "ListNames").Output()
+	if err != nil {
+		return Track{}, false, fmt.Errorf("busctl: %w", err)
+	}
+	players, err := parseMPRISPlayers(output)
+	if err != nil {
+		return Track{}, false, err
+	}
+
+	var tracks []Track
+	for _, name := range players {
+		props, err := exec.CommandContext(ctx, "busctl", "--user", "--json=short", "get-property",
+			name, "/org/mpris/MediaPlayer2", "org.mpris.MediaPlayer2.Player", "PlaybackStatus", "Metadata").Output()
+		if err != nil {
+			continue // Players may exit between the calls
+		}
+		if track, ok := parseMPRISTrack(strings.TrimPrefix(name, mprisPrefix), props); ok {
+			tracks = append(tracks, track)
+		}
+	}
+	track, ok := pickTrack(tracks, preferred)
+	return track, ok, nil
+}
+
+// parseMPRISPlayers extracts MPRIS bus names from busctl's ListNames reply,
+// e.g. {"type":"as","data":[["org.freedesktop.DBus","org.mpris.MediaPlayer2.spotify"]]}
+func parseMPRISPlayers(output []byte) ([]string, error) {
+	var reply struct {
+		Data [][]string `json:"data"`
+	}
+	if err := json.Unmarshal(output, &reply); err != nil {
+		return nil, fmt.Errorf("invalid busctl reply: %w", err)
+	}
+	var players []string
+	for _, names := range reply.Data {
+		for _, name := range names {
+			if strings.HasPrefix(name, mprisPrefix) {
+				players = append(players, name)
+			}
+		}
+	}
+	return players, nil
+}
+
+// parseMPRISTrack parses the PlaybackStatus and Metadata properties, which
+// busctl prints as one JSON value per line
+// Stopped players and players without a title report false
+func parseMPRISTrack(player string, output []byte) (Track, bool) {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) != 2 {
+		return Track{}, false
+	}
+	var status struct {
+		Data string `json:"data"`
+	}
+	var metadata struct {
+		Data map[string]struct {
+			Data json.RawMessage `json:"data"`
+		} `json:"data"`
+	}
+	if json.Unmarshal([]byte(lines[0]), &status) != nil || json.Unmarshal([]byte(lines[1]), &metadata) != nil {
+		return Track{}, false
+	}
+	if status.Data != "Playing" && status.Data != "Paused" {
+		return Track{}, false
+	}
+
+	track := Track{Player: player, Playing: status.Data == "Playing"}
+	_ = json.Unmarshal(metadata.Data["xesam:title"].Data, &track.Title)
+	_ = json.Unmarshal(metadata.Data["xesam:album"].Data, &track.Album)
+	var artists []string
+	_ = json.Unmarshal(metadata.Data["xesam:artist"].Data, &artists)
+	track.Artist = strings.Join(artists, ", ")
+	if track.Title == "" {
+		return Track{}, false
+	}
+	return track, true
+}
+
+// pickTrack prefers playing tracks, then the preferred player, then the first found
+func pickTrack(tracks []Track, preferred string) (Track, bool) {
+	best := -1
+	score := func(t Track) int {
+		s := 0
+		if t.Playing {
+			s += 2
+		}
+		if preferred != "" && strings.Contains(strings.ToLower(t.Player), strings.ToLower(preferred)) {
+			s++
+		}
+		return s
+	}
+	for i, track := range tracks {
+		if best < 0 || score(track) > score(tracks[best]) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return Track{}, false
+	}
+	return tracks[best], true
+}
+
+// nowPlayingScript prints "state<TAB>player<TAB>title<TAB>artist<TAB>album"
+// for Spotify or Music, without launching either
+const nowPlayingScript = `set output to ""
+if application "Spotify" is running then
+	tell application "Spotify"
+		if player state is not stopped then set output to (player state as text) & tab & "spotify" & tab & name of current track & tab & artist of current track & tab & album of current track
+	end tell
+end if
+if output is "" and application "Music" is running then
+	tell application "Music"
+		if player state is not stopped then set output to (player state as text) & tab & "music" & tab & name of current track & tab & artist of current track & tab & album of current track
+	end tell
+end if
+return output`
+
+// parseOsascriptTrack parses the output of nowPlayingScript
+func parseOsascriptTrack(output string) (Track, bool) {
+	fields := strings.Split(strings.TrimRight(output, "\r\n"), "\t")
+	if len(fields) != 5 || fields[2] == "" {
+		return Track{}, false
+	}
+	return Track{
+		Player:  fields[1],
+		Title:   fields[2],
+		Artist:  fields[3],
+		Album:   fields[4],
+		Playing: fields[0] == "playing",
+	}, true
+}
@@ -0,0 +1,88 @@
+package system
+
+import "testing"
+
+func TestParseMPRISPlayers(t *testing.T) {
+	output := []byte(`{"type":"as","data":[["org.freedesktop.DBus",":1.7","org.mpris.MediaPlayer2.spotify","org.mpris.MediaPlayer2.firefox.instance_1_42"]]}`)
+	players, err := parseMPRISPlayers(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(players) != 2 || players[0] != "org.mpris.MediaPlayer2.spotify" || players[1] != "org.mpris.MediaPlayer2.firefox.instance_1_42" {
+		t.Errorf("parseMPRISPlayers() = %v", players)
+	}
+	if _, err := parseMPRISPlayers([]byte("not json")); err == nil {
+		t.Error("expected an error for an invalid reply")
+	}
+}
+
+func TestParseMPRISTrack(t *testing.T) {
+	metadata := `{"type":"a{sv}","data":{"mpris:length":{"type":"t","data":233000000},"xesam:title":{"type":"s","data":"Teardrop"},"xesam:artist":{"type":"as","data":["Massive Attack","Elizabeth Fraser"]},"xesam:album":{"type":"s","data":"Mezzanine"}}}`
+
+	tests := []struct {
+		name   string
+		output string
+		want   Track
+		ok     bool
+	}{
+		{
+			name:   "playing",
+			output: `{"type":"s","data":"Playing"}` + "\n" + metadata + "\n",
+			want:   Track{Player: "spotify", Title: "Teardrop", Artist: "Massive Attack, Elizabeth Fraser", Album: "Mezzanine", Playing: true},
+			ok:     true,
+		},
+		{
+			name:   "paused",
+			output: `{"type":"s","data":"Paused"}` + "\n" + metadata,
+			want:   Track{Player: "spotify", Title: "Teardrop", Artist: "Massive Attack, Elizabeth Fraser", Album: "Mezzanine"},
+			ok:     true,
+		},
+		{
+			name:   "stopped",
+			output: `{"type":"s","data":"Stopped"}` + "\n" + metadata,
+		},
+		{
+			name:   "no title",
+			output: `{"type":"s","data":"Playing"}` + "\n" + `{"type":"a{sv}","data":{}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseMPRISTrack("spotify", []byte(tt.output))
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("parseMPRISTrack() = %+v, %v; want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestPickTrack(t *testing.T) {
+	paused := Track{Player: "spotify", Title: "A"}
+	playing := Track{Player: "firefox.instance_1_42", Title: "B", Playing: true}
+	otherPaused := Track{Player: "vlc", Title: "C"}
+
+	if got, _ := pickTrack([]Track{paused, playing}, "spotify"); got != playing {
+		t.Errorf("pickTrack() = %+v, want the playing track", got)
+	}
+	if got, _ := pickTrack([]Track{otherPaused, paused}, "spotify"); got != paused {
+		t.Errorf("pickTrack() = %+v, want the preferred player", got)
+	}
+	if got, _ := pickTrack([]Track{otherPaused, paused}, ""); got != otherPaused {
+		t.Errorf("pickTrack() = %+v, want the first track", got)
+	}
+	if _, ok := pickTrack(nil, ""); ok {
+		t.Error("pickTrack(nil) should report nothing playing")
+	}
+}
+
+func TestParseOsascriptTrack(t *testing.T) {
+	got, ok := parseOsascriptTrack("playing\tspotify\tTeardrop\tMassive Attack\tMezzanine\n")
+	want := Track{Player: "spotify", Title: "Teardrop", Artist: "Massive Attack", Album: "Mezzanine", Playing: true}
+	if !ok || got != want {
+		t.Errorf("parseOsascriptTrack() = %+v, %v; want %+v", got, ok, want)
+	}
+	if _, ok := parseOsascriptTrack("\n"); ok {
+		t.Error("empty output should report nothing playing")
+	}
+}
@@ -0,0 +1,248 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProcessInfo is a snapshot of one process
+type ProcessInfo struct {
+	PID        int
+	PPID       int
+	Name       string
+	CPUTime    time.Duration // User and system time, including reaped children (Linux)
+	CPUPercent float64       // Usage reported directly by the OS (macOS ps)
+	RSS        uint64        // Resident memory in bytes
+}
+
+// ProcessTreeUsage is the combined resource usage of a process and its descendants
+type ProcessTreeUsage struct {
+	PID        int     // Root of the tree
+	Processes  int     // Number of processes in the tree
+	CPUPercent float64 // Percent of one core, as in top
+	RSS        uint64  // Combined resident memory in bytes
+	Sampled    bool    // False until CPU usage could be measured
+}
+
+// ProcessPIDEnv overrides detection of the process to track
+const ProcessPIDEnv = "CLAUDE_HUD_PID"
+
+// procSampleKey identifies process tree CPU time in the SampleStore
+const procSampleKey = "proctree"
+
+// maxAncestorDepth bounds the walk up from our parent when detecting by name
+const maxAncestorDepth = 10
+
+// ProcessTree returns the combined usage of rootPID and its descendants,
+// excluding this process. CPU usage is measured between calls
+func (m *Monitor) ProcessTree(rootPID int) (ProcessTreeUsage, error) {
+	procs, err := listProcesses()
+	if err != nil {
+		return ProcessTreeUsage{}, err
+	}
+	tree := descendants(procs, rootPID, os.Getpid())
+	if len(tree) == 0 {
+		return ProcessTreeUsage{}, fmt.Errorf("process %d not found", rootPID)
+	}
+
+	usage := ProcessTreeUsage{PID: rootPID, Processes: len(tree)}
+	var cpuTime time.Duration
+	reported := false
+	for _, p := range tree {
+		usage.RSS += p.RSS
+		usage.CPUPercent += p.CPUPercent
+		cpuTime += p.CPUTime
+		reported = reported || p.CPUPercent > 0
+	}
+	if reported {
+		usage.Sampled = true
+		return usage, nil
+	}
+
+	// Rates need a baseline from the same tree; the root PID is stored with the sample
+	current := Sample{At: time.Now(), Values: []uint64{uint64(rootPID), uint64(cpuTime)}}
+	prev, ok := m.samples.Advance(procSampleKey, current, cpuSampleMinAge)
+	if !ok || len(prev.Values) != 2 || prev.Values[0] != uint64(rootPID) {
+		return usage, nil
+	}
+	if rates, ok := current.Rates(prev, cpuSampleMaxAge); ok {
+		usage.CPUPercent = rates[1] / float64(time.Second) * 100
+		usage.Sampled = true
+	}
+	return usage, nil
+}
+
+// FindProcess returns the PID to track: $CLAUDE_HUD_PID when set, otherwise
+// the nearest ancestor of this process whose name matches name
+func FindProcess(name string) (int, error) {
+	if env := os.Getenv(ProcessPIDEnv); env != "" {
+		pid, err := strconv.Atoi(env)
+		if err != nil || pid <= 0 {
+			return 0, fmt.Errorf("invalid %s %q", ProcessPIDEnv, env)
+		}
+		return pid, nil
+	}
+
+	procs, err := listProcesses()
+	if err != nil {
+		return 0, err
+	}
+	if pid := findAncestor(procs, os.Getppid(), name); pid != 0 {
+		return pid, nil
+	}
+	return 0, fmt.Errorf("no %s process above pid %d", name, os.Getpid())
+}
+
+// findAncestor walks up from pid and returns the first process named name
+func findAncestor(procs []ProcessInfo, pid int, name string) int {
+	byPID := make(map[int]ProcessInfo, len(procs))
+	for _, p := range procs {
+		byPID[p.PID] = p
+	}
+	for depth := 0; depth < maxAncestorDepth && pid > 1; depth++ {
+		p, ok := byPID[pid]
+		if !ok {
+			return 0
+		}
+		if p.Name == name {
+			return p.PID
+		}
+		pid = p.PPID
+	}
+	return 0
+}
+
+// descendants returns root and all processes below it, skipping the subtree of exclude
+func descendants(procs []ProcessInfo, root, exclude int) []ProcessInfo {
+	children := make(map[int][]ProcessInfo)
+	var tree []ProcessInfo
+	for _, p := range procs {
+		children[p.PPID] = append(children[p.PPID], p)
+		if p.PID == root {
+			tree = append(tree, p)
+		}
+	}
+	if len(tree) == 0 {
+		return nil
+	}
+	for i := 0; i < len(tree); i++ {
+		for _, child := range children[tree[i].PID] {
+			if child.PID != exclude && child.PID != root {
+				tree = append(tree, child)
+			}
+		}
+	}
+	return tree
+}
+
+// listProcesses returns a snapshot of all processes
+func listProcesses() ([]ProcessInfo, error) {
+	switch runtime.GOOS {
+	case "linux":
+		return listLinuxProcesses()
+	case "darwin":
+		output, err := exec.Command("ps", "-axo", "pid=,ppid=,rss=,%cpu=,comm=").Output()
+		if err != nil {
+			return nil, fmt.Errorf("ps: %w", err)
+		}
+		return parsePs(string(output)), nil
+	}
+	return nil, fmt.Errorf("process listing not supported on %s", runtime.GOOS)
+}
+
+// listLinuxProcesses reads /proc/<pid>/stat for every process
+// Processes that exit while being read are skipped
+func listLinuxProcesses() ([]ProcessInfo, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+	pageSize := uint64(os.Getpagesize())
+	procs := make([]ProcessInfo, 0, len(entries))
+	for _, entry := range entries {
+		if _, err := strconv.Atoi(entry.Name()); err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join("/proc", entry.Name(), "stat"))
+		if err != nil {
+			continue
+		}
+		if p, err := parseProcPIDStat(string(data), pageSize); err == nil {
+			procs = append(procs, p)
+		}
+	}
+	return procs, nil
+}
+
+// clockTicks is USER_HZ, the unit of /proc CPU times; 100 on all mainstream kernels
+const clockTicks = 100
+
+// parseProcPIDStat parses /proc/<pid>/stat
+//
+//	pid (comm) state ppid ... utime stime cutime cstime ... rss ...
+//
+// comm may contain spaces and parentheses, so fields are split after the last ')'
+func parseProcPIDStat(data string, pageSize uint64) (ProcessInfo, error) {
+	open := strings.IndexByte(data, '(')
+	end := strings.LastIndexByte(data, ')')
+	if open < 0 || end < open {
+		return ProcessInfo{}, fmt.Errorf("invalid stat line")
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(data[:open]))
+	if err != nil {
+		return ProcessInfo{}, fmt.Errorf("invalid pid: %w", err)
+	}
+	fields := strings.Fields(data[end+1:])
+	if len(fields) < 22 {
+		return ProcessInfo{}, fmt.Errorf("short stat line")
+	}
+
+	ppid, _ := strconv.Atoi(fields[1])
+	var ticks uint64
+	for _, f := range fields[11:15] { // utime stime cutime cstime
+		v, _ := strconv.ParseUint(f, 10, 64)
+		ticks += v
+	}
+	rssPages, _ := strconv.ParseUint(fields[21], 10, 64)
+
+	return ProcessInfo{
+		PID:     pid,
+		PPID:    ppid,
+		Name:    data[open+1 : end],
+		CPUTime: time.Duration(ticks) * time.Second / clockTicks,
+		RSS:     rssPages * pageSize,
+	}, nil
+}
+
+// parsePs parses `ps -axo pid=,ppid=,rss=,%cpu=,comm=`; rss is in KB
+// comm is a full path on macOS and may contain spaces
+func parsePs(output string) []ProcessInfo {
+	var procs []ProcessInfo
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		ppid, err2 := strconv.Atoi(fields[1])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		rss, _ := strconv.ParseUint(fields[2], 10, 64)
+		cpu, _ := strconv.ParseFloat(fields[3], 64)
+		procs = append(procs, ProcessInfo{
+			PID:        pid,
+			PPID:       ppid,
+			Name:       filepath.Base(strings.Join(fields[4:], " ")),
+			CPUPercent: cpu,
+			RSS:        rss * 1024,
+		})
+	}
+	return procs
+}
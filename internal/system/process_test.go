@@ -0,0 +1,91 @@
+package system
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParseProcPIDStat(t *testing.T) {
+	// comm with spaces and parentheses must not shift the fields
+	line := "4242 (claude (main)) S 100 4242 4242 0 -1 4194304 1000 0 0 0 250 50 30 20 20 0 12 0 5000 123456789 2048 18446744073709551615\n"
+	p, err := parseProcPIDStat(line, 4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.PID != 4242 || p.PPID != 100 || p.Name != "claude (main)" {
+		t.Errorf("parseProcPIDStat() = %+v", p)
+	}
+	if p.CPUTime != 3500*time.Millisecond {
+		t.Errorf("CPUTime = %v, want 3.5s (utime+stime+cutime+cstime)", p.CPUTime)
+	}
+	if p.RSS != 2048*4096 {
+		t.Errorf("RSS = %d, want %d", p.RSS, 2048*4096)
+	}
+
+	if _, err := parseProcPIDStat("garbage", 4096); err == nil {
+		t.Error("parseProcPIDStat() should reject malformed input")
+	}
+}
+
+func TestParsePs(t *testing.T) {
+	output := `    1     0   1024   0.0 /sbin/launchd
+  500     1 512000   3.2 claude
+  501   500  20480  12.5 /bin/zsh
+  502   501   4096   0.1 /Applications/Some App.app/Contents/MacOS/Some App
+`
+	procs := parsePs(output)
+	if len(procs) != 4 {
+		t.Fatalf("parsePs() returned %d processes, want 4", len(procs))
+	}
+	if procs[1].Name != "claude" || procs[1].RSS != 512000*1024 || procs[1].CPUPercent != 3.2 {
+		t.Errorf("parsePs()[1] = %+v", procs[1])
+	}
+	if procs[3].Name != "Some App" {
+		t.Errorf("parsePs()[3].Name = %q, want %q", procs[3].Name, "Some App")
+	}
+}
+
+func TestFindAncestorAndDescendants(t *testing.T) {
+	procs := []ProcessInfo{
+		{PID: 1, PPID: 0, Name: "init"},
+		{PID: 100, PPID: 1, Name: "zsh"},
+		{PID: 200, PPID: 100, Name: "claude", RSS: 800},
+		{PID: 300, PPID: 200, Name: "sh", RSS: 10},
+		{PID: 301, PPID: 300, Name: "claude-hud", RSS: 5},
+		{PID: 400, PPID: 200, Name: "node", RSS: 90},
+		{PID: 401, PPID: 400, Name: "rg", RSS: 20},
+		{PID: 500, PPID: 1, Name: "other", RSS: 1000},
+	}
+
+	if got := findAncestor(procs, 300, "claude"); got != 200 {
+		t.Errorf("findAncestor() = %d, want 200", got)
+	}
+	if got := findAncestor(procs, 300, "missing"); got != 0 {
+		t.Errorf("findAncestor(missing) = %d, want 0", got)
+	}
+
+	tree := descendants(procs, 200, 301)
+	var rss uint64
+	for _, p := range tree {
+		rss += p.RSS
+	}
+	if len(tree) != 4 || rss != 920 {
+		t.Errorf("descendants() = %d processes, %d RSS; want 4, 920", len(tree), rss)
+	}
+}
+
+func TestMonitor_ProcessTree(t *testing.T) {
+	m := NewMonitor()
+	usage, err := m.ProcessTree(os.Getppid())
+	if err != nil {
+		t.Skipf("process listing unavailable: %v", err)
+	}
+	if usage.Processes < 1 || usage.RSS == 0 {
+		t.Errorf("ProcessTree() = %+v, want at least the parent process", usage)
+	}
+
+	if _, err := m.ProcessTree(-1); err == nil {
+		t.Error("ProcessTree() should fail for a missing process")
+	}
+}
@@ -0,0 +1,94 @@
+package system
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/filecache"
+)
+
+// Sample is a snapshot of cumulative counters, such as bytes received
+type Sample struct {
+	At     time.Time `json:"at"`
+	Values []uint64  `json:"values"`
+}
+
+// Rates returns the per-second change of each counter since prev
+// ok is false when prev is unusable: empty, too old, from the future or of another shape
+// Counters that went backwards (e.g. after a reboot) report 0
+func (s Sample) Rates(prev Sample, maxAge time.Duration) ([]float64, bool) {
+	elapsed := s.At.Sub(prev.At)
+	if prev.At.IsZero() || elapsed <= 0 || elapsed > maxAge || len(prev.Values) != len(s.Values) {
+		return nil, false
+	}
+	rates := make([]float64, len(s.Values))
+	for i, v := range s.Values {
+		if v >= prev.Values[i] {
+			rates[i] = float64(v-prev.Values[i]) / elapsed.Seconds()
+		}
+	}
+	return rates, true
+}
+
+// SampleStore keeps the previous sample of each counter set so rates can be
+// computed between updates. With a path, samples are also saved to disk, since
+// in statusline mode every render is a new process
+type SampleStore struct {
+	mu      sync.Mutex
+	path    string
+	samples map[string]Sample
+	loaded  bool
+}
+
+// NewSampleStore creates a sample store persisted at path ("" keeps samples in memory only)
+func NewSampleStore(path string) *SampleStore {
+	return &SampleStore{path: path, samples: make(map[string]Sample)}
+}
+
+// Swap records current under key and returns the sample it replaced
+func (s *SampleStore) Swap(key string, current Sample) (Sample, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.load()
+	prev, ok := s.samples[key]
+	s.samples[key] = current
+	s.save()
+	return prev, ok
+}
+
+// Advance returns the previous sample under key, replacing it with current
+// only once it is at least minAge old. Rates then always span at least minAge
+func (s *SampleStore) Advance(key string, current Sample, minAge time.Duration) (Sample, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.load()
+	prev, ok := s.samples[key]
+	if !ok || current.At.Sub(prev.At) >= minAge || current.At.Before(prev.At) {
+		s.samples[key] = current
+		s.save()
+	}
+	return prev, ok
+}
+
+// load reads persisted samples once; a missing or corrupt file starts empty
+func (s *SampleStore) load() {
+	if s.loaded || s.path == "" {
+		return
+	}
+	s.loaded = true
+	var samples map[string]Sample
+	if filecache.Load(s.path, &samples) {
+		for key, sample := range samples {
+			if _, ok := s.samples[key]; !ok {
+				s.samples[key] = sample
+			}
+		}
+	}
+}
+
+// save atomically replaces the samples file; failures only cost one rate sample
+func (s *SampleStore) save() {
+	_ = filecache.Save(s.path, s.samples)
+}
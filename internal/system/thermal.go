@@ -0,0 +1,149 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// ThermalInfo contains CPU temperature and fan speed
+type ThermalInfo struct {
+	CPUTemp float64 // Degrees Celsius; 0 if unknown
+	FanRPM  int     // Fastest fan; 0 if unknown or fanless
+}
+
+const (
+	sysfsHwmon   = "/sys/class/hwmon"
+	sysfsThermal = "/sys/class/thermal"
+)
+
+// cpuHwmonChips are hwmon drivers reporting CPU temperature, most specific first
+var cpuHwmonChips = []string{"coretemp", "k10temp", "zenpower", "cpu_thermal", "soc_thermal", "acpitz"}
+
+// cpuTempLabels are the package-level sensor labels preferred over per-core ones
+var cpuTempLabels = []string{"Package id 0", "Tctl", "Tdie"}
+
+// getThermal reads CPU temperature and fan speed for the current platform
+func getThermal() (ThermalInfo, error) {
+	switch runtime.GOOS {
+	case "linux":
+		info := readHwmon(sysfsHwmon)
+		if info.CPUTemp == 0 {
+			info.CPUTemp = readThermalZones(sysfsThermal)
+		}
+		return info, nil
+	case "darwin":
+		// powermetrics reads the SMC sensors but only works as root
+		if os.Geteuid() != 0 {
+			return ThermalInfo{}, fmt.Errorf("powermetrics requires root")
+		}
+		output, err := exec.Command("powermetrics", "--samplers", "smc", "-n", "1", "-i", "1").Output()
+		if err != nil {
+			return ThermalInfo{}, fmt.Errorf("powermetrics: %w", err)
+		}
+		return parsePowermetrics(string(output)), nil
+	}
+	return ThermalInfo{}, fmt.Errorf("temperature not supported on %s", runtime.GOOS)
+}
+
+// readHwmon finds the CPU temperature and fastest fan among hwmon devices under root
+// Values are in millidegrees Celsius and RPM
+func readHwmon(root string) ThermalInfo {
+	var info ThermalInfo
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return info
+	}
+
+	chips := make(map[string]string) // driver name -> device dir
+	for _, entry := range entries {
+		dir := filepath.Join(root, entry.Name())
+		if name := readSysfsString(dir, "name"); name != "" {
+			if _, seen := chips[name]; !seen {
+				chips[name] = dir
+			}
+		}
+		fans, _ := filepath.Glob(filepath.Join(dir, "fan*_input"))
+		for _, fan := range fans {
+			if rpm, err := strconv.Atoi(readSysfsString(dir, filepath.Base(fan))); err == nil && rpm > info.FanRPM {
+				info.FanRPM = rpm
+			}
+		}
+	}
+
+	for _, chip := range cpuHwmonChips {
+		if dir, ok := chips[chip]; ok {
+			if temp := hwmonCPUTemp(dir); temp > 0 {
+				info.CPUTemp = temp
+				break
+			}
+		}
+	}
+	return info
+}
+
+// hwmonCPUTemp returns the package temperature of a hwmon device,
+// or the hottest sensor when none is labelled as the package
+func hwmonCPUTemp(dir string) float64 {
+	inputs, _ := filepath.Glob(filepath.Join(dir, "temp*_input"))
+	var hottest float64
+	for _, input := range inputs {
+		temp := readSysfsFloat(dir, filepath.Base(input)) / 1000
+		label := readSysfsString(dir, strings.TrimSuffix(filepath.Base(input), "_input")+"_label")
+		for _, want := range cpuTempLabels {
+			if label == want && temp > 0 {
+				return temp
+			}
+		}
+		if temp > hottest {
+			hottest = temp
+		}
+	}
+	return hottest
+}
+
+// readThermalZones falls back to ACPI thermal zones, preferring the CPU package zone
+func readThermalZones(root string) float64 {
+	zones, _ := filepath.Glob(filepath.Join(root, "thermal_zone*"))
+	var first float64
+	for _, zone := range zones {
+		temp := readSysfsFloat(zone, "temp") / 1000
+		if temp <= 0 {
+			continue
+		}
+		switch readSysfsString(zone, "type") {
+		case "x86_pkg_temp", "cpu-thermal", "cpu_thermal":
+			return temp
+		}
+		if first == 0 {
+			first = temp
+		}
+	}
+	return first
+}
+
+var (
+	powermetricsTemp = regexp.MustCompile(`CPU die temperature:\s*([\d.]+)\s*C`)
+	powermetricsFan  = regexp.MustCompile(`Fan:\s*([\d.]+)\s*rpm`)
+)
+
+// parsePowermetrics parses the SMC sampler output of powermetrics
+//
+//	Fan: 1840.45 rpm
+//	CPU die temperature: 52.31 C
+func parsePowermetrics(output string) ThermalInfo {
+	var info ThermalInfo
+	if match := powermetricsTemp.FindStringSubmatch(output); match != nil {
+		info.CPUTemp, _ = strconv.ParseFloat(match[1], 64)
+	}
+	if match := powermetricsFan.FindStringSubmatch(output); match != nil {
+		rpm, _ := strconv.ParseFloat(match[1], 64)
+		info.FanRPM = int(rpm + 0.5)
+	}
+	return info
+}
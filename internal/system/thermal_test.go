@@ -0,0 +1,100 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeSysfs creates sysfs-style files under dir
+func writeSysfs(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadHwmon(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, filepath.Join(root, "hwmon0"), map[string]string{
+		"name":        "acpitz",
+		"temp1_input": "40000",
+	})
+	writeSysfs(t, filepath.Join(root, "hwmon1"), map[string]string{
+		"name":        "coretemp",
+		"temp1_input": "71000",
+		"temp1_label": "Package id 0",
+		"temp2_input": "75000",
+		"temp2_label": "Core 0",
+	})
+	writeSysfs(t, filepath.Join(root, "hwmon2"), map[string]string{
+		"name":       "thinkpad",
+		"fan1_input": "2400",
+		"fan2_input": "0",
+	})
+
+	info := readHwmon(root)
+	if info.CPUTemp != 71 {
+		t.Errorf("CPUTemp = %v, want 71 (coretemp package sensor)", info.CPUTemp)
+	}
+	if info.FanRPM != 2400 {
+		t.Errorf("FanRPM = %d, want 2400", info.FanRPM)
+	}
+
+	// Without a package label the hottest sensor is used
+	root = t.TempDir()
+	writeSysfs(t, filepath.Join(root, "hwmon0"), map[string]string{
+		"name":        "k10temp",
+		"temp1_input": "55500",
+		"temp3_input": "61250",
+	})
+	if info := readHwmon(root); info.CPUTemp != 61.25 {
+		t.Errorf("CPUTemp = %v, want 61.25", info.CPUTemp)
+	}
+
+	if info := readHwmon(filepath.Join(root, "missing")); info != (ThermalInfo{}) {
+		t.Errorf("readHwmon(missing) = %+v, want zero", info)
+	}
+}
+
+func TestReadThermalZones(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, filepath.Join(root, "thermal_zone0"), map[string]string{"type": "acpitz", "temp": "45000"})
+	writeSysfs(t, filepath.Join(root, "thermal_zone1"), map[string]string{"type": "x86_pkg_temp", "temp": "68000"})
+
+	if got := readThermalZones(root); got != 68 {
+		t.Errorf("readThermalZones() = %v, want 68 (package zone)", got)
+	}
+}
+
+func TestParsePowermetrics(t *testing.T) {
+	output := `**** SMC sensors ****
+
+Fan: 1840.45 rpm
+CPU die temperature: 52.31 C
+GPU die temperature: 48.00 C
+`
+	info := parsePowermetrics(output)
+	if info.CPUTemp != 52.31 || info.FanRPM != 1840 {
+		t.Errorf("parsePowermetrics() = %+v", info)
+	}
+}
+
+func TestMonitor_FormatThermalDisplay(t *testing.T) {
+	m := NewMonitor()
+	if m.FormatTemperatureDisplay() != "" || m.FormatFanDisplay() != "" {
+		t.Error("thermal display should be empty without data")
+	}
+	m.thermal = ThermalInfo{CPUTemp: 72.4, FanRPM: 2400}
+	if got := m.FormatTemperatureDisplay(); got != "72°C" {
+		t.Errorf("FormatTemperatureDisplay() = %q", got)
+	}
+	if got := m.FormatFanDisplay(); got != "FAN 2400rpm" {
+		t.Errorf("FormatFanDisplay() = %q", got)
+	}
+}
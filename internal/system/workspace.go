@@ -0,0 +1,74 @@
+package system
+
+import (
+	"os"
+	"time"
+)
+
+// workspaceEntries caps how many directories a monitor remembers; the
+// oldest entry is forgotten first
+const workspaceEntries = 32
+
+// WorkspaceInfo is what the monitor knows about a working directory
+type WorkspaceInfo struct {
+	Dir      string
+	Language string   // Detected from source files; empty if none
+	Disk     DiskInfo // Partition holding Dir
+}
+
+// workspaceEntry is a cached WorkspaceInfo with the time it was measured
+type workspaceEntry struct {
+	info WorkspaceInfo
+	at   time.Time
+}
+
+// Workspace returns the language and disk usage of dir, measured at most once
+// per update interval. Each directory is measured on its own, so one monitor
+// serves sessions in different directories without changing the process's
+// working directory
+func (m *Monitor) Workspace(dir string) WorkspaceInfo {
+	if dir == "" {
+		return WorkspaceInfo{}
+	}
+
+	now := time.Now()
+	m.mu.RLock()
+	entry, ok := m.workspaces[dir]
+	interval := m.updateInterval
+	m.mu.RUnlock()
+	if ok && now.Sub(entry.at) < interval && !now.Before(entry.at) {
+		return entry.info
+	}
+
+	info := WorkspaceInfo{Dir: dir, Language: DetectLanguage(dir)}
+	if disk, err := getDiskUsage(dir); err == nil {
+		info.Disk = disk
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.workspaces == nil {
+		m.workspaces = make(map[string]workspaceEntry)
+	}
+	if _, ok := m.workspaces[dir]; !ok && len(m.workspaces) >= workspaceEntries {
+		oldest := ""
+		for d, e := range m.workspaces {
+			if oldest == "" || e.at.Before(m.workspaces[oldest].at) {
+				oldest = d
+			}
+		}
+		delete(m.workspaces, oldest)
+	}
+	m.workspaces[dir] = workspaceEntry{info: info, at: now}
+	return info
+}
+
+// processDir returns the process's working directory, for callers with no
+// workspace directory of their own
+func processDir() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	return dir
+}
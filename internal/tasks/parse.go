@@ -0,0 +1,65 @@
+// Package tasks reads markdown checklists such as TODO.md, a zero-dependency
+// alternative to an issue tracker
+package tasks
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// checkboxPattern matches a checklist item: a bullet ("-", "*", "+" or "1.")
+// followed by "[ ]" or "[x]", at any indentation
+var checkboxPattern = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[([ xX])\]\s+(.*)$`)
+
+// List summarizes the checklist items of one or more files
+type List struct {
+	Done  int
+	Total int
+	Next  string // First unchecked item, "" when all are done
+}
+
+// Open returns the number of unchecked items
+func (l List) Open() int {
+	return l.Total - l.Done
+}
+
+// add merges another file's items, keeping the earlier file's next item
+func (l *List) add(other List) {
+	l.Done += other.Done
+	l.Total += other.Total
+	if l.Next == "" {
+		l.Next = other.Next
+	}
+}
+
+// Parse counts the checklist items of a markdown document
+// Items inside fenced code blocks are examples, not tasks, and are skipped
+func Parse(data []byte) List {
+	var list List
+	inFence := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		m := checkboxPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		list.Total++
+		if m[1] != " " {
+			list.Done++
+		} else if list.Next == "" {
+			list.Next = strings.TrimSpace(m[2])
+		}
+	}
+	return list
+}
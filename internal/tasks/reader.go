@@ -0,0 +1,174 @@
+package tasks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+	"github.com/ll931217/claude-hud-enhanced/internal/watcher"
+)
+
+// scanInterval is how often the files are checked again without a watcher
+// event, e.g. for a file created in a directory that did not exist yet
+const scanInterval = 2 * time.Second
+
+// fileStamp identifies a version of a file
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// Reader reads the checklists of a set of files and caches their summary
+// until one of them changes
+type Reader struct {
+	mu             sync.Mutex
+	paths          []string
+	list           List
+	found          bool
+	stamps         map[string]fileStamp
+	lastScan       time.Time
+	watcher        *watcher.Watcher
+	watcherStarted bool
+	forceReload    bool // Set when the watcher sees a file change
+	watcherCancel  context.CancelFunc
+	watcherDone    chan struct{}
+}
+
+// NewReader creates a reader for paths relative to dir, e.g. "TODO.md";
+// absolute paths are used as they are
+func NewReader(dir string, paths []string) *Reader {
+	resolved := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		resolved = append(resolved, path)
+	}
+	return &Reader{
+		paths:       resolved,
+		watcher:     watcher.NewWatcher(),
+		watcherDone: make(chan struct{}),
+	}
+}
+
+// Read returns the combined checklist of the files, with the next item taken
+// from the first file that has one. Returns false when none of the files exist
+func (r *Reader) Read() (List, bool, error) {
+	r.startWatcherOnce()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.forceReload && time.Since(r.lastScan) < scanInterval {
+		return r.list, r.found, nil
+	}
+	r.forceReload = false
+	r.lastScan = time.Now()
+
+	stamps := make(map[string]fileStamp, len(r.paths))
+	for _, path := range r.paths {
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			stamps[path] = fileStamp{info.ModTime(), info.Size()}
+		}
+	}
+	if sameStamps(stamps, r.stamps) {
+		return r.list, r.found, nil
+	}
+
+	var list List
+	for _, path := range r.paths {
+		if _, ok := stamps[path]; !ok {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			// Try again on the next call; keep the last complete summary
+			r.forceReload = true
+			return r.list, r.found, err
+		}
+		list.add(Parse(data))
+	}
+	r.list, r.found, r.stamps = list, len(stamps) > 0, stamps
+	return r.list, r.found, nil
+}
+
+// sameStamps reports whether two scans saw the same files in the same versions
+func sameStamps(a, b map[string]fileStamp) bool {
+	if a == nil || b == nil || len(a) != len(b) {
+		return a == nil && b == nil
+	}
+	for path, stamp := range a {
+		if other, ok := b[path]; !ok || other != stamp {
+			return false
+		}
+	}
+	return true
+}
+
+// startWatcherOnce starts watching the files on first call (idempotent)
+func (r *Reader) startWatcherOnce() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.watcherStarted {
+		return
+	}
+	r.watcherStarted = true
+
+	// Editors save in several steps; one reload per burst is enough
+	r.watcher.SetDebounce(100 * time.Millisecond)
+	for _, path := range r.paths {
+		// A glob without wildcards also reports the file being created
+		if err := r.watcher.AddWatchGlob(path); err != nil {
+			errors.Debug("tasks.reader", "not watching %s: %v", path, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.watcherCancel = cancel
+
+	go func() {
+		defer close(r.watcherDone)
+
+		if err := r.watcher.Start(ctx); err != nil {
+			errors.Warn("tasks.reader", "watcher error: %v", err)
+			return
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-r.watcher.Events():
+				r.mu.Lock()
+				r.forceReload = true
+				r.mu.Unlock()
+				errors.Debug("tasks.reader", "%s changed, forcing reload", event.Path)
+			case err := <-r.watcher.Errors():
+				errors.Warn("tasks.reader", "watcher error: %v", err)
+			}
+		}
+	}()
+}
+
+// Stop stops the file watcher
+// Safe to call when the watcher was never started
+func (r *Reader) Stop() {
+	r.mu.Lock()
+	cancel := r.watcherCancel
+	r.watcherCancel = nil
+	r.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	r.watcher.Stop()
+
+	// Wait for the watcher goroutine (if any) without holding the lock it needs
+	if cancel != nil {
+		<-r.watcherDone
+	}
+}
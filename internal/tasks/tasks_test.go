@@ -0,0 +1,71 @@
+package tasks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	doc := "# Release\n\n" +
+		"- [x] Bump the version\n" +
+		"* [X] Tag the release\n" +
+		"  - [ ] Write the migration guide\n" +
+		"1. [ ] Announce it\n" +
+		"- [] not a checkbox\n" +
+		"- plain bullet\n" +
+		"```markdown\n- [ ] example in a code block\n```\n" +
+		"+ [ ] Close the milestone\n"
+
+	list := Parse([]byte(doc))
+	if list.Done != 2 || list.Total != 5 || list.Open() != 3 {
+		t.Errorf("Parse() = %d/%d, want 2/5", list.Done, list.Total)
+	}
+	if list.Next != "Write the migration guide" {
+		t.Errorf("Next = %q, want the first unchecked item", list.Next)
+	}
+
+	if list := Parse([]byte("- [x] done\n")); list.Next != "" || list.Open() != 0 {
+		t.Errorf("Parse() of a finished list = %+v", list)
+	}
+}
+
+func TestReader(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "docs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	reader := NewReader(dir, []string{"TODO.md", "docs/tasks.md"})
+	defer reader.Stop()
+
+	if _, found, err := reader.Read(); found || err != nil {
+		t.Fatalf("Read() without files = %v, %v", found, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "docs", "tasks.md"), []byte("- [x] one\n- [ ] two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "TODO.md"), []byte("- [ ] first\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The new files are seen by the watcher or, at the latest, the next rescan
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		list, found, err := reader.Read()
+		if err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		if found && list.Total == 3 {
+			if list.Done != 1 || list.Next != "first" {
+				t.Errorf("Read() = %+v, want 1/3 with TODO.md's item next", list)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Read() = %+v, %v; new files not picked up", list, found)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -2,10 +2,16 @@ package terminal
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"syscall"
 	"unsafe"
 )
 
+// width is the width set with SetWidth, or 0 to use the terminal's
+var width atomic.Int64
+
 // Size represents terminal dimensions
 type Size struct {
 	Columns int
@@ -34,14 +40,42 @@ func GetSize() Size {
 	}
 }
 
-// AvailableWidth returns available columns (with safety margin)
+// SetWidth makes AvailableWidth report columns, whatever the terminal's size,
+// so output can be laid out for a width other than the current one; 0 goes
+// back to measuring the terminal
+func SetWidth(columns int) {
+	width.Store(int64(max(columns, 0)))
+}
+
+// AvailableWidth returns available columns (with safety margin), or the
+// width set with SetWidth
 func AvailableWidth() int {
-	size := GetSize()
+	if w := width.Load(); w > 0 {
+		return int(w)
+	}
+	return available(GetSize().Columns)
+}
+
+// EnvWidth returns the available columns of a terminal as wide as $COLUMNS
+// says, with the margin AvailableWidth leaves, or 0 when it is unset or
+// not a number. Shells set it, and wrappers can pass it on to processes
+// whose output is piped, which cannot measure the terminal
+func EnvWidth() int {
+	columns, err := strconv.Atoi(strings.TrimSpace(os.Getenv("COLUMNS")))
+	if err != nil {
+		return 0
+	}
+	return available(columns)
+}
+
+// available returns the columns left on a terminal columns wide after the
+// safety margin, or 0 when it is too narrow to tell
+func available(columns int) int {
 	// Leave 2 columns margin on each side
-	if size.Columns <= 4 {
+	if columns <= 4 {
 		return 0
 	}
-	return size.Columns - 4
+	return columns - 4
 }
 
 // AvailableRows returns available rows (with safety margin)
@@ -0,0 +1,30 @@
+package terminal
+
+import "testing"
+
+func TestEnvWidth(t *testing.T) {
+	tests := []struct {
+		columns string
+		want    int
+	}{
+		{"120", 116},
+		{" 80\n", 76},
+		{"4", 0},
+		{"", 0},
+		{"wide", 0},
+	}
+	for _, tt := range tests {
+		t.Setenv("COLUMNS", tt.columns)
+		if got := EnvWidth(); got != tt.want {
+			t.Errorf("EnvWidth() with COLUMNS=%q = %d, want %d", tt.columns, got, tt.want)
+		}
+	}
+}
+
+func TestSetWidth(t *testing.T) {
+	t.Cleanup(func() { SetWidth(0) })
+	SetWidth(72)
+	if got := AvailableWidth(); got != 72 {
+		t.Errorf("AvailableWidth() = %d, want the width set", got)
+	}
+}
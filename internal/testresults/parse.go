@@ -0,0 +1,156 @@
+// Package testresults reads the outcome of the last test run from the files
+// test runners leave behind: go test -json output and JUnit XML reports
+package testresults
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrNoResults is returned for files that contain no test results
+var ErrNoResults = errors.New("no test results")
+
+// Summary counts the outcomes of a test run
+type Summary struct {
+	Path    string    // File the results were read from
+	ModTime time.Time // When the file was last written
+	Passed  int
+	Failed  int
+	Skipped int
+}
+
+// Total returns the number of tests that finished
+func (s Summary) Total() int {
+	return s.Passed + s.Failed + s.Skipped
+}
+
+// Parse counts the results in go test -json output or a JUnit XML report,
+// telling them apart by their first character
+func Parse(data []byte) (Summary, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '<' {
+		return parseJUnit(trimmed)
+	}
+	return parseGoTest(trimmed)
+}
+
+// goTestEvent is one line of go test -json output
+type goTestEvent struct {
+	Action  string
+	Package string
+	Test    string
+}
+
+// parseGoTest counts top-level tests by their last pass, fail or skip event
+// Subtests are not counted, since their parent fails with them. A package
+// that failed without a failing test, e.g. one that did not build, counts
+// as one failure so a broken build never reads as all green
+func parseGoTest(data []byte) (Summary, error) {
+	type result struct{ pkg, test string }
+	outcomes := make(map[result]string)
+	failedPackages := make(map[string]bool)
+	events := 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 || line[0] != '{' {
+			continue // Build output mixed into the file
+		}
+		var event goTestEvent
+		if json.Unmarshal(line, &event) != nil || event.Action == "" {
+			continue
+		}
+		events++
+		switch event.Action {
+		case "pass", "fail", "skip":
+		default:
+			continue
+		}
+		if event.Test == "" {
+			if event.Action == "fail" {
+				failedPackages[event.Package] = true
+			}
+			continue
+		}
+		if !strings.Contains(event.Test, "/") {
+			outcomes[result{event.Package, event.Test}] = event.Action
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Summary{}, fmt.Errorf("reading go test output: %w", err)
+	}
+	if events == 0 {
+		return Summary{}, ErrNoResults
+	}
+
+	var summary Summary
+	for key, action := range outcomes {
+		switch action {
+		case "pass":
+			summary.Passed++
+		case "fail":
+			summary.Failed++
+			delete(failedPackages, key.pkg)
+		case "skip":
+			summary.Skipped++
+		}
+	}
+	summary.Failed += len(failedPackages)
+	return summary, nil
+}
+
+// parseJUnit counts the testcase elements of a JUnit report, whether they
+// sit in a testsuite, testsuites or nested suites
+// Cases with a failure or error element fail; cases with skipped are skipped
+func parseJUnit(data []byte) (Summary, error) {
+	var summary Summary
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	inCase, failed, skipped, cases := false, false, false, 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Summary{}, fmt.Errorf("invalid JUnit report: %w", err)
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "testcase":
+				inCase, failed, skipped = true, false, false
+			case "failure", "error":
+				failed = failed || inCase
+			case "skipped":
+				skipped = skipped || inCase
+			}
+		case xml.EndElement:
+			if element.Name.Local != "testcase" {
+				continue
+			}
+			inCase = false
+			cases++
+			switch {
+			case failed:
+				summary.Failed++
+			case skipped:
+				summary.Skipped++
+			default:
+				summary.Passed++
+			}
+		}
+	}
+	if cases == 0 {
+		return Summary{}, ErrNoResults
+	}
+	return summary, nil
+}
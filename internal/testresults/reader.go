@@ -0,0 +1,177 @@
+package testresults
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+	"github.com/ll931217/claude-hud-enhanced/internal/watcher"
+)
+
+// scanInterval is how often the patterns are globbed again without a watcher
+// event, e.g. for results directories created after the watcher started
+const scanInterval = 2 * time.Second
+
+// Reader finds the most recently written results file matching a set of glob
+// patterns and caches its summary until the file changes
+type Reader struct {
+	mu             sync.Mutex
+	dir            string
+	patterns       []string
+	summary        Summary
+	size           int64
+	lastScan       time.Time
+	watcher        *watcher.Watcher
+	watcherStarted bool
+	forceReload    bool // Set when the watcher sees a results file change
+	watcherCancel  context.CancelFunc
+	watcherDone    chan struct{}
+}
+
+// NewReader creates a reader for patterns relative to dir, e.g.
+// "test-results/*.xml"; absolute patterns are used as they are
+func NewReader(dir string, patterns []string) *Reader {
+	resolved := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(dir, pattern)
+		}
+		resolved = append(resolved, pattern)
+	}
+	return &Reader{
+		dir:         dir,
+		patterns:    resolved,
+		watcher:     watcher.NewWatcher(),
+		watcherDone: make(chan struct{}),
+	}
+}
+
+// Latest returns the summary of the newest results file
+// Returns false when no file matches. When the newest file cannot be parsed,
+// e.g. while a runner is still writing it, the previous summary is returned
+// along with the error
+func (r *Reader) Latest() (Summary, bool, error) {
+	r.startWatcherOnce()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	found := !r.summary.ModTime.IsZero()
+	if !r.forceReload && time.Since(r.lastScan) < scanInterval {
+		return r.summary, found, nil
+	}
+	r.forceReload = false
+	r.lastScan = time.Now()
+
+	path, info := r.newest()
+	if info == nil {
+		r.summary, r.size = Summary{}, 0
+		return Summary{}, false, nil
+	}
+	if path == r.summary.Path && info.ModTime().Equal(r.summary.ModTime) && info.Size() == r.size {
+		return r.summary, true, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		var summary Summary
+		if summary, err = Parse(data); err == nil {
+			summary.Path, summary.ModTime = path, info.ModTime()
+			r.summary, r.size = summary, info.Size()
+			return summary, true, nil
+		}
+	}
+	// Parse the file again on the next call, it may be complete by then
+	r.forceReload = true
+	return r.summary, found, err
+}
+
+// newest returns the most recently modified regular file matching a pattern
+func (r *Reader) newest() (string, os.FileInfo) {
+	var newestPath string
+	var newestInfo os.FileInfo
+	for _, pattern := range r.patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			continue
+		}
+		for _, path := range matches {
+			info, err := os.Stat(path)
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			if newestInfo == nil || info.ModTime().After(newestInfo.ModTime()) {
+				newestPath, newestInfo = path, info
+			}
+		}
+	}
+	return newestPath, newestInfo
+}
+
+// startWatcherOnce starts watching the patterns on first call (idempotent)
+// Patterns with wildcards in a directory name are only picked up by rescans
+func (r *Reader) startWatcherOnce() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.watcherStarted {
+		return
+	}
+	r.watcherStarted = true
+
+	// Runners write reports in several steps; one reload per burst is enough
+	r.watcher.SetDebounce(100 * time.Millisecond)
+	for _, pattern := range r.patterns {
+		if err := r.watcher.AddWatchGlob(pattern); err != nil {
+			errors.Debug("testresults.reader", "not watching %s: %v", pattern, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.watcherCancel = cancel
+
+	go func() {
+		defer close(r.watcherDone)
+
+		if err := r.watcher.Start(ctx); err != nil {
+			errors.Warn("testresults.reader", "watcher error: %v", err)
+			return
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-r.watcher.Events():
+				r.mu.Lock()
+				r.forceReload = true
+				r.mu.Unlock()
+				errors.Debug("testresults.reader", "%s changed, forcing reload", event.Path)
+			case err := <-r.watcher.Errors():
+				errors.Warn("testresults.reader", "watcher error: %v", err)
+			}
+		}
+	}()
+}
+
+// Stop stops the file watcher
+// Safe to call when the watcher was never started
+func (r *Reader) Stop() {
+	r.mu.Lock()
+	cancel := r.watcherCancel
+	r.watcherCancel = nil
+	r.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	r.watcher.Stop()
+
+	// Wait for the watcher goroutine (if any) without holding the lock it needs
+	if cancel != nil {
+		<-r.watcherDone
+	}
+}
@@ -0,0 +1,133 @@
+package testresults
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const goTestJSON = `# example.com/pkg/broken
+broken/x.go:3:1: syntax error
+{"Action":"start","Package":"example.com/pkg"}
+{"Action":"run","Package":"example.com/pkg","Test":"TestA"}
+{"Action":"pass","Package":"example.com/pkg","Test":"TestA","Elapsed":0}
+{"Action":"run","Package":"example.com/pkg","Test":"TestB"}
+{"Action":"run","Package":"example.com/pkg","Test":"TestB/case_1"}
+{"Action":"fail","Package":"example.com/pkg","Test":"TestB/case_1","Elapsed":0}
+{"Action":"fail","Package":"example.com/pkg","Test":"TestB","Elapsed":0}
+{"Action":"skip","Package":"example.com/pkg","Test":"TestC","Elapsed":0}
+{"Action":"fail","Package":"example.com/pkg","Elapsed":0.1}
+{"Action":"pass","Package":"example.com/other","Test":"TestA","Elapsed":0}
+{"Action":"pass","Package":"example.com/other","Elapsed":0.1}
+{"Action":"fail","Package":"example.com/pkg/broken","Elapsed":0}
+`
+
+const junitXML = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="api" tests="3">
+    <testcase name="creates" classname="api"/>
+    <testcase name="rejects" classname="api"><failure message="expected 400">trace</failure></testcase>
+    <testcase name="later" classname="api"><skipped/></testcase>
+  </testsuite>
+  <testsuite name="ui">
+    <testsuite name="nested">
+      <testcase name="renders"></testcase>
+      <testcase name="crashes"><error message="boom"/></testcase>
+    </testsuite>
+  </testsuite>
+</testsuites>`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Summary
+		wantErr bool
+	}{
+		{
+			name: "go test -json",
+			data: goTestJSON,
+			// TestB counts once despite its subtest; the broken package adds a failure
+			want: Summary{Passed: 2, Failed: 2, Skipped: 1},
+		},
+		{
+			name: "junit",
+			data: junitXML,
+			want: Summary{Passed: 2, Failed: 2, Skipped: 1},
+		},
+		{
+			name:    "truncated junit",
+			data:    `<testsuite><testcase name="a">`,
+			wantErr: true,
+		},
+		{
+			name:    "not test output",
+			data:    "hello\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.data))
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("Parse() = %+v, %v; want %+v, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := Parse([]byte("{\"Action\":\"start\"}\n")); err != nil {
+		t.Errorf("a run without finished tests should parse, got %v", err)
+	}
+	if _, err := Parse([]byte("")); !errors.Is(err, ErrNoResults) {
+		t.Errorf("Parse(empty) error = %v, want ErrNoResults", err)
+	}
+}
+
+func TestReader_Latest(t *testing.T) {
+	dir := t.TempDir()
+	reader := NewReader(dir, []string{"results/*.xml", "go-test.json"})
+	defer reader.Stop()
+
+	if _, found, err := reader.Latest(); found || err != nil {
+		t.Fatalf("Latest() without results = %v, %v", found, err)
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, "results"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	older := time.Now().Add(-time.Minute)
+	writeResults(t, filepath.Join(dir, "go-test.json"), goTestJSON, older)
+	writeResults(t, filepath.Join(dir, "results", "junit.xml"), junitXML, time.Now())
+
+	// Rescans wait for the scan interval or a watcher event
+	reader.mu.Lock()
+	reader.forceReload = true
+	reader.mu.Unlock()
+
+	summary, found, err := reader.Latest()
+	if err != nil || !found || summary.Path != filepath.Join(dir, "results", "junit.xml") || summary.Failed != 2 {
+		t.Fatalf("Latest() = %+v, %v, %v; want the newest file", summary, found, err)
+	}
+
+	// A half-written report keeps the previous summary
+	writeResults(t, filepath.Join(dir, "results", "junit.xml"), "<testsuite><testcase>", time.Now().Add(time.Second))
+	reader.mu.Lock()
+	reader.forceReload = true
+	reader.mu.Unlock()
+	if stale, found, err := reader.Latest(); err == nil || !found || stale.Failed != 2 {
+		t.Errorf("Latest() while writing = %+v, %v, %v; want the stale summary with an error", stale, found, err)
+	}
+}
+
+func writeResults(t *testing.T, path, data string, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
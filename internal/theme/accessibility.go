@@ -0,0 +1,68 @@
+package theme
+
+import "sync/atomic"
+
+// symbols is whether warning and critical colors are paired with a symbol
+var symbols atomic.Bool
+
+// Critical and warning markers, paired with red and yellow text in symbols
+// mode so the state reads without telling the colors apart
+const (
+	CriticalMarker = "‼"
+	WarningMarker  = "⚠"
+)
+
+// SetHighContrast switches to bright basic colors, which every terminal
+// palette keeps readable against its background, and stops dimming text.
+// Call it before rendering starts
+func SetHighContrast(on bool) {
+	if on {
+		Dim = ""
+		Green = "\033[1;92m"
+		Yellow = "\033[1;93m"
+		Red = "\033[1;91m"
+		Purple = "\033[1;95m"
+		return
+	}
+	Dim = "\033[2m"
+	Green = "\033[38;5;40m"
+	Yellow = "\033[38;5;215m"
+	Red = "\033[38;5;203m"
+	Purple = "\033[38;5;177m"
+}
+
+// SetSymbols pairs warning and critical colors with a symbol, so no state is
+// shown by color alone
+func SetSymbols(on bool) {
+	symbols.Store(on)
+}
+
+// Symbols reports whether states are marked with symbols as well as colors
+func Symbols() bool {
+	return symbols.Load()
+}
+
+// Marker returns the symbol for a warning or critical color in symbols
+// mode, and "" otherwise
+func Marker(color string) string {
+	if !symbols.Load() {
+		return ""
+	}
+	switch color {
+	case Red:
+		return CriticalMarker
+	case Yellow:
+		return WarningMarker
+	}
+	return ""
+}
+
+// Signal colors text that shows a state, e.g. a percentage over a threshold,
+// adding the color's marker in symbols mode. Text with no color is returned
+// as is
+func Signal(color, text string) string {
+	if color == "" {
+		return text
+	}
+	return color + Marker(color) + text + Reset
+}
@@ -0,0 +1,46 @@
+package theme
+
+import "testing"
+
+func TestSignal(t *testing.T) {
+	t.Cleanup(func() { SetSymbols(false) })
+
+	if got, want := Signal(Red, "95%"), Red+"95%"+Reset; got != want {
+		t.Errorf("Signal() = %q, want %q", got, want)
+	}
+	if got := Signal("", "40%"); got != "40%" {
+		t.Errorf("Signal() without a color = %q, want the text alone", got)
+	}
+
+	SetSymbols(true)
+	if got, want := Signal(Red, "95%"), Red+CriticalMarker+"95%"+Reset; got != want {
+		t.Errorf("Signal() with symbols = %q, want %q", got, want)
+	}
+	if got, want := Signal(Yellow, "72%"), Yellow+WarningMarker+"72%"+Reset; got != want {
+		t.Errorf("Signal() with symbols = %q, want %q", got, want)
+	}
+	if got := Marker(Green); got != "" {
+		t.Errorf("Marker(Green) = %q, want none", got)
+	}
+}
+
+func TestSetHighContrast(t *testing.T) {
+	defaultRed := Red
+	t.Cleanup(func() { SetHighContrast(false) })
+
+	SetHighContrast(true)
+	if Dim != "" {
+		t.Errorf("Dim = %q in high contrast, want no dimming", Dim)
+	}
+	if Red == defaultRed {
+		t.Error("Red unchanged in high contrast")
+	}
+	if ContextColor(90) != Red {
+		t.Error("ContextColor() does not follow the high contrast palette")
+	}
+
+	SetHighContrast(false)
+	if Red != defaultRed {
+		t.Errorf("Red = %q after turning high contrast off, want %q", Red, defaultRed)
+	}
+}
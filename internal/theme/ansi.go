@@ -4,23 +4,43 @@ package theme
 const (
 	Reset = "\033[0m"
 	Bold  = "\033[1m"
-	Dim   = "\033[2m"
 )
 
-// ANSI color codes (256-color mode)
-const (
+// ANSI color codes (256-color mode), and dimmed text. Variables so that
+// SetHighContrast can swap them; only set them through it
+var (
+	Dim    = "\033[2m"
 	Green  = "\033[38;5;40m"
 	Yellow = "\033[38;5;215m"
 	Red    = "\033[38;5;203m"
+	Purple = "\033[38;5;177m"
+)
+
+// Default context usage thresholds, in percent
+const (
+	ContextWarningPercent  = 70
+	ContextCriticalPercent = 85
 )
 
 // ContextColor returns the ANSI color code for a given context percentage
 func ContextColor(percentage int) string {
-	if percentage >= 85 {
+	return ContextColorWithThresholds(percentage, ContextWarningPercent, ContextCriticalPercent)
+}
+
+// ContextColorWithThresholds returns the ANSI color code for a context
+// percentage: red at or above critical, yellow at or above warning
+func ContextColorWithThresholds(percentage, warning, critical int) string {
+	if percentage >= critical {
 		return Red
 	}
-	if percentage >= 70 {
+	if percentage >= warning {
 		return Yellow
 	}
 	return "" // No color for low usage (user request)
 }
+
+// Hyperlink makes text a link to url with an OSC 8 escape sequence
+// Terminals without OSC 8 support show the text alone
+func Hyperlink(url, text string) string {
+	return "\033]8;;" + url + "\033\\" + text + "\033]8;;\033\\"
+}
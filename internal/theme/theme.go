@@ -1,5 +1,13 @@
 package theme
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/render"
+)
+
 // Theme defines color constants for the statusline
 type Theme struct {
 	// Background colors
@@ -34,6 +42,103 @@ func CatppuccinMocha() *Theme {
 	}
 }
 
+// Nord returns the Nord theme colors
+// Reference: https://www.nordtheme.com/
+func Nord() *Theme {
+	return &Theme{
+		Background: "#2E3440",
+
+		Primary:   "#88c0d0", // Frost
+		Secondary: "#b48ead", // Aurora purple
+		Muted:     "#4c566a", // Polar Night
+
+		Success: "#a3be8c", // Aurora green
+		Warning: "#ebcb8b", // Aurora yellow
+		Error:   "#bf616a", // Aurora red
+		Info:    "#81a1c1", // Frost
+	}
+}
+
+// Dracula returns the Dracula theme colors
+// Reference: https://draculatheme.com/
+func Dracula() *Theme {
+	return &Theme{
+		Background: "#282A36",
+
+		Primary:   "#8be9fd", // Cyan
+		Secondary: "#bd93f9", // Purple
+		Muted:     "#6272a4", // Comment
+
+		Success: "#50fa7b", // Green
+		Warning: "#f1fa8c", // Yellow
+		Error:   "#ff5555", // Red
+		Info:    "#ff79c6", // Pink
+	}
+}
+
+// GruvboxDark returns the Gruvbox dark theme colors
+// Reference: https://github.com/morhetz/gruvbox
+func GruvboxDark() *Theme {
+	return &Theme{
+		Background: "#282828",
+
+		Primary:   "#83a598", // Blue
+		Secondary: "#d3869b", // Purple
+		Muted:     "#928374", // Gray
+
+		Success: "#b8bb26", // Green
+		Warning: "#fabd2f", // Yellow
+		Error:   "#fb4934", // Red
+		Info:    "#8ec07c", // Aqua
+	}
+}
+
+// themes are the built-in themes by name
+var themes = map[string]func() *Theme{
+	"catppuccin-mocha": CatppuccinMocha,
+	"dracula":          Dracula,
+	"gruvbox-dark":     GruvboxDark,
+	"nord":             Nord,
+}
+
+// Named returns the built-in theme with the given name
+func Named(name string) (*Theme, error) {
+	newTheme, ok := themes[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown theme %q (have: %s)", name, strings.Join(Names(), ", "))
+	}
+	return newTheme(), nil
+}
+
+// Names returns the names of the built-in themes, sorted
+func Names() []string {
+	return render.Keys(themes)
+}
+
+// Use colors warnings, errors, successes and highlights with the theme's
+// colors in 24-bit color, instead of the 256-color defaults. Call it before
+// rendering starts; SetHighContrast undoes it
+func Use(t *Theme) {
+	Green = Foreground(t.Success)
+	Yellow = Foreground(t.Warning)
+	Red = Foreground(t.Error)
+	Purple = Foreground(t.Secondary)
+}
+
+// Foreground returns the escape sequence that sets the text color to a hex
+// color such as "#88c0d0", or "" when hex is not one
+func Foreground(hex string) string {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) != 6 {
+		return ""
+	}
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("\033[38;2;%d;%d;%dm", rgb>>16, rgb>>8&0xff, rgb&0xff)
+}
+
 // Default returns the default theme (Catppuccin Mocha)
 func Default() *Theme {
 	return CatppuccinMocha()
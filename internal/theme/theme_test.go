@@ -105,3 +105,45 @@ func TestANSIColors(t *testing.T) {
 		t.Errorf("ANSIColors()[error] = %d, want 203", colors["error"])
 	}
 }
+
+func TestNamed(t *testing.T) {
+	for _, name := range Names() {
+		theme, err := Named(name)
+		if err != nil || theme == nil {
+			t.Errorf("Named(%q) = %v, %v", name, theme, err)
+		}
+	}
+	if theme, err := Named("Nord"); err != nil || theme.Background != "#2E3440" {
+		t.Errorf("Named(Nord) = %v, %v, want the nord theme", theme, err)
+	}
+	if _, err := Named("solarized"); err == nil {
+		t.Error("Named() of an unknown theme succeeded")
+	}
+}
+
+func TestForeground(t *testing.T) {
+	tests := map[string]string{
+		"#88c0d0": "\033[38;2;136;192;208m",
+		"BF616A":  "\033[38;2;191;97;106m",
+		"#fff":    "",
+		"#zzzzzz": "",
+	}
+	for hex, want := range tests {
+		if got := Foreground(hex); got != want {
+			t.Errorf("Foreground(%q) = %q, want %q", hex, got, want)
+		}
+	}
+}
+
+func TestUse(t *testing.T) {
+	t.Cleanup(func() { SetHighContrast(false) })
+
+	Use(Nord())
+	if Red != "\033[38;2;191;97;106m" || Green != Foreground(Nord().Success) {
+		t.Errorf("Use(Nord()) left Red = %q, Green = %q", Red, Green)
+	}
+	SetHighContrast(false)
+	if Red != "\033[38;5;203m" {
+		t.Errorf("SetHighContrast(false) left Red = %q", Red)
+	}
+}
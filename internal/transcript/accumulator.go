@@ -0,0 +1,88 @@
+package transcript
+
+import "os"
+
+// sessionTotals are a session's aggregates from transcript content that is
+// gone: when Claude Code rotates or compacts the transcript, the file is
+// replaced or truncated and a fresh parse would otherwise start the session's
+// token, cost, turn, tool and touched file counts over from zero, and forget
+// its dangerous commands. What survived a truncation is in the totals already,
+// so a parse counts only what follows it (see Parser.countedSize)
+type sessionTotals struct {
+	inputTokens  int
+	outputTokens int
+	modelUsage   map[string]*ModelUsage
+	turns        TurnStats
+	toolStats    map[string]*ToolStats
+	touchedFiles map[string]*TouchedFile
+	dangerous    map[string]DangerousCommand
+	errorsTotal  int
+	rewrites     int // Times the transcript was found rotated or truncated
+}
+
+// rotated reports whether the transcript was replaced by another file since
+// it was last parsed, so content already counted is no longer in it
+func rotated(last, current os.FileInfo) bool {
+	return last != nil && !os.SameFile(last, current)
+}
+
+// truncated reports whether the transcript, the same file, shrank from
+// lastSize since it was last parsed, so the end of what was counted is gone
+func truncated(last os.FileInfo, lastSize int64, current os.FileInfo) bool {
+	return last != nil && os.SameFile(last, current) && current.Size() < lastSize
+}
+
+// carryTotalsLocked moves the aggregates parsed so far, which already include
+// anything carried before, into the session accumulator. Callers hold p.mu
+func (p *Parser) carryTotalsLocked() {
+	p.carried = sessionTotals{
+		inputTokens:  p.totalInputTokens,
+		outputTokens: p.totalOutputTokens,
+		modelUsage:   copyModelUsage(p.modelUsage),
+		turns:        p.turns,
+		toolStats:    copyToolStats(p.toolStats),
+		touchedFiles: copyTouchedFiles(p.touchedFiles),
+		dangerous:    copyDangerous(p.dangerous),
+		errorsTotal:  p.errorsTotal,
+		rewrites:     p.carried.rewrites + 1,
+	}
+}
+
+// seedTotalsLocked starts the aggregates of a fresh parse from the session
+// accumulator. Callers hold p.mu
+func (p *Parser) seedTotalsLocked() {
+	p.totalInputTokens = p.carried.inputTokens
+	p.totalOutputTokens = p.carried.outputTokens
+	p.modelUsage = copyModelUsage(p.carried.modelUsage)
+	p.turns = p.carried.turns
+	p.toolStats = copyToolStats(p.carried.toolStats)
+	p.touchedFiles = copyTouchedFiles(p.carried.touchedFiles)
+	p.dangerous = copyDangerous(p.carried.dangerous)
+	p.errorsTotal = p.carried.errorsTotal
+}
+
+// Rewrites returns how many times the transcript was found rotated or
+// truncated; the session's totals include what those versions held
+func (p *Parser) Rewrites() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.carried.rewrites
+}
+
+func copyModelUsage(usage map[string]*ModelUsage) map[string]*ModelUsage {
+	copied := make(map[string]*ModelUsage, len(usage))
+	for model, u := range usage {
+		c := *u
+		copied[model] = &c
+	}
+	return copied
+}
+
+func copyToolStats(stats map[string]*ToolStats) map[string]*ToolStats {
+	copied := make(map[string]*ToolStats, len(stats))
+	for name, s := range stats {
+		c := *s
+		copied[name] = &c
+	}
+	return copied
+}
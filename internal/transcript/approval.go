@@ -0,0 +1,35 @@
+package transcript
+
+// onlyToolUse reports whether a message holds nothing but tool calls
+func onlyToolUse(content []ContentBlock) bool {
+	for i := range content {
+		if content[i].Type != "tool_use" {
+			return false
+		}
+	}
+	return len(content) > 0
+}
+
+// GetUnansweredTool returns the oldest tool call that is still running with
+// nothing written to the transcript since: no result, message or prompt.
+// Claude Code writes nothing while it waits for you to approve a tool call,
+// so one that stays unanswered for long is likely a permission prompt
+func (p *Parser) GetUnansweredTool() (ToolInfo, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var oldest *ToolInfo
+	for _, tool := range p.toolActivity {
+		if tool.Status != "running" || tool.LastUsed.IsZero() || tool.LastUsed.Before(p.lastActivity) {
+			continue
+		}
+		if oldest == nil || tool.LastUsed.Before(oldest.LastUsed) ||
+			(tool.LastUsed.Equal(oldest.LastUsed) && tool.ToolUseID < oldest.ToolUseID) {
+			oldest = tool
+		}
+	}
+	if oldest == nil {
+		return ToolInfo{}, false
+	}
+	return *oldest, true
+}
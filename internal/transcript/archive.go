@@ -0,0 +1,134 @@
+package transcript
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ArchiveExt is appended to transcripts compressed by Archive
+const ArchiveExt = ".gz"
+
+// IsArchive reports whether path is a gzip-compressed transcript
+func IsArchive(path string) bool {
+	return strings.HasSuffix(path, ArchiveExt)
+}
+
+// Open opens a transcript for reading, decompressing .jsonl.gz archives
+func Open(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if !IsArchive(path) {
+		return file, nil
+	}
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to decompress %s: %w", path, err)
+	}
+	return &gzipFile{Reader: gz, file: file}, nil
+}
+
+// gzipFile closes both the decompressor and the underlying file
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (g *gzipFile) Close() error {
+	err := g.Reader.Close()
+	if cerr := g.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// File describes a transcript file on disk
+type File struct {
+	Path     string
+	Size     int64
+	ModTime  time.Time
+	Archived bool // Gzip-compressed (.jsonl.gz)
+}
+
+// ListFiles returns transcripts and archived transcripts under dir, largest first
+func ListFiles(dir string) ([]File, error) {
+	var files []File
+	for _, pattern := range []string{"*.jsonl", "*.jsonl" + ArchiveExt} {
+		matches, err := filepath.Glob(filepath.Join(dir, "*", pattern))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list transcripts: %w", err)
+		}
+		for _, path := range matches {
+			info, err := os.Stat(path)
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			files = append(files, File{
+				Path:     path,
+				Size:     info.Size(),
+				ModTime:  info.ModTime(),
+				Archived: IsArchive(path),
+			})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].Size != files[j].Size {
+			return files[i].Size > files[j].Size
+		}
+		return files[i].Path < files[j].Path
+	})
+	return files, nil
+}
+
+// Archive gzips a transcript to path.gz, keeping its modification time, and
+// removes the original. The original is only removed once the archive is complete
+func Archive(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	src, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	archivePath := path + ArchiveExt
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".archive-*.gz")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+	_ = tmp.Chmod(info.Mode().Perm())
+
+	gz := gzip.NewWriter(tmp)
+	gz.Name = filepath.Base(path)
+	gz.ModTime = info.ModTime()
+	_, err = io.Copy(gz, src)
+	if cerr := gz.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to compress %s: %w", path, err)
+	}
+
+	if err := os.Rename(tmp.Name(), archivePath); err != nil {
+		return "", err
+	}
+	_ = os.Chtimes(archivePath, info.ModTime(), info.ModTime())
+	if err := os.Remove(path); err != nil {
+		return archivePath, err
+	}
+	return archivePath, nil
+}
@@ -0,0 +1,114 @@
+package transcript
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestArchive(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "session.jsonl")
+	content := `{"type":"user","message":{"role":"user","content":"hello"}}` + "\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	archived, err := Archive(path)
+	if err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+	if archived != path+".gz" {
+		t.Errorf("Archive() = %q, want %q", archived, path+".gz")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("original transcript should be removed")
+	}
+
+	info, err := os.Stat(archived)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(modTime) {
+		t.Errorf("archive mtime = %v, want %v", info.ModTime(), modTime)
+	}
+
+	file, err := os.Open(archived)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(gz)
+	if err != nil || string(data) != content {
+		t.Errorf("archived content = %q, %v", data, err)
+	}
+}
+
+func TestListFiles(t *testing.T) {
+	dir := t.TempDir()
+	project := filepath.Join(dir, "-home-user-project")
+	if err := os.MkdirAll(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, size := range map[string]int{"small.jsonl": 10, "big.jsonl": 1000, "old.jsonl.gz": 100, "notes.txt": 5000} {
+		if err := os.WriteFile(filepath.Join(project, name), make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := ListFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("ListFiles() returned %d files, want 3", len(files))
+	}
+	if filepath.Base(files[0].Path) != "big.jsonl" || filepath.Base(files[2].Path) != "small.jsonl" {
+		t.Errorf("ListFiles() order = %v, want largest first", files)
+	}
+	if !files[1].Archived || files[0].Archived {
+		t.Error("only .jsonl.gz files should be marked archived")
+	}
+}
+
+func TestParser_ParseArchive(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "session.jsonl")
+	content := `{"type":"assistant","timestamp":"2026-01-11T10:05:00.000Z","message":{"role":"assistant","model":"claude-sonnet-4","usage":{"input_tokens":1000,"output_tokens":200},"content":[{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"/p/main.go"}}]}}` + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	archived, err := Archive(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parser := NewParser(archived)
+	if err := parser.Parse(context.Background()); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if lines := parser.GetState().LinesParsed; lines != 1 {
+		t.Errorf("LinesParsed = %d, want 1", lines)
+	}
+
+	// A file named .gz that is not gzip fails clearly
+	bogus := filepath.Join(dir, "bogus.jsonl.gz")
+	if err := os.WriteFile(bogus, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(bogus); err == nil {
+		t.Error("Open() should fail for a corrupt archive")
+	}
+}
@@ -0,0 +1,167 @@
+package transcript
+
+import (
+	"encoding/json"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/filecache"
+	"github.com/ll931217/claude-hud-enhanced/internal/render"
+)
+
+// DangerousCommand is a Bash command that matched a dangerous command pattern
+type DangerousCommand struct {
+	ToolUseID string
+	Rule      string // Name of the pattern that matched, e.g. "rm -rf"
+	Command   string
+	Time      time.Time
+}
+
+// defaultDangerPatterns catch commands that destroy work or data, or run
+// code fetched from the network
+var defaultDangerPatterns = map[string]*regexp.Regexp{
+	"rm -rf":         regexp.MustCompile(`\brm\s+(?:-\S+\s+)*-[a-zA-Z]*(?:[rR][a-zA-Z]*f|f[a-zA-Z]*[rR])|\brm\s+.*--recursive.*--force|\brm\s+.*--force.*--recursive`),
+	"force push":     regexp.MustCompile(`\bgit\s+push\b.*\s(?:--force|-f)(?:\s|$)`),
+	"hard reset":     regexp.MustCompile(`\bgit\s+reset\b.*\s--hard\b`),
+	"pipe to shell":  regexp.MustCompile(`\b(?:curl|wget)\b[^|]*\|\s*(?:sudo\s+)?(?:ba|z|da)?sh\b`),
+	"DROP TABLE":     regexp.MustCompile(`(?i)\bdrop\s+(?:table|database|schema)\b`),
+	"disk overwrite": regexp.MustCompile(`\bmkfs(?:\.\w+)?\s|\bdd\b.*\bof=/dev/`),
+}
+
+var (
+	dangerPatternsMu sync.RWMutex
+	dangerPatterns   = defaultDangerPatterns
+)
+
+// SetDangerPatterns adds patterns to, or replaces patterns of, the default
+// ones, by name; a nil pattern turns a default off
+func SetDangerPatterns(patterns map[string]*regexp.Regexp) {
+	merged := make(map[string]*regexp.Regexp, len(defaultDangerPatterns)+len(patterns))
+	for name, re := range defaultDangerPatterns {
+		merged[name] = re
+	}
+	for name, re := range patterns {
+		if re == nil {
+			delete(merged, name)
+			continue
+		}
+		merged[name] = re
+	}
+
+	dangerPatternsMu.Lock()
+	defer dangerPatternsMu.Unlock()
+	dangerPatterns = merged
+}
+
+// ResetDangerPatterns restores the default patterns
+func ResetDangerPatterns() {
+	dangerPatternsMu.Lock()
+	defer dangerPatternsMu.Unlock()
+	dangerPatterns = defaultDangerPatterns
+}
+
+// MatchDangerous returns the name of the first pattern, by name, that
+// command matches
+func MatchDangerous(command string) (string, bool) {
+	dangerPatternsMu.RLock()
+	defer dangerPatternsMu.RUnlock()
+
+	for _, name := range render.Keys(dangerPatterns) {
+		if dangerPatterns[name].MatchString(command) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// checkDanger records a Bash tool call whose command matches a dangerous
+// command pattern
+func (p *Parser) checkDanger(toolUseID, toolName string, input json.RawMessage, at time.Time) {
+	if toolName != "Bash" || toolUseID == "" {
+		return
+	}
+	command, _ := decodeToolInput(input)["command"].(string)
+	rule, ok := MatchDangerous(command)
+	if !ok {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dangerous[toolUseID] = DangerousCommand{ToolUseID: toolUseID, Rule: rule, Command: command, Time: at}
+}
+
+// GetDangerousCommands returns the session's dangerous commands, most recent
+// first
+func (p *Parser) GetDangerousCommands() []DangerousCommand {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return render.Values(p.dangerous, func(a, b DangerousCommand) int {
+		return render.Newest(a.Time, b.Time)
+	})
+}
+
+func copyDangerous(commands map[string]DangerousCommand) map[string]DangerousCommand {
+	copied := make(map[string]DangerousCommand, len(commands))
+	for id, c := range commands {
+		copied[id] = c
+	}
+	return copied
+}
+
+// maxDangerAlerts bounds how many notified commands are remembered
+const maxDangerAlerts = 500
+
+// DangerAlerts remembers which dangerous commands a notification was sent
+// for, so each is announced once. With a cache path, this carries over
+// between statusline processes
+type DangerAlerts struct {
+	CachePath string
+
+	mu       sync.Mutex
+	notified []string // Tool use IDs, oldest first
+	loaded   bool
+}
+
+// NewDangerAlerts creates an alert log persisted at cachePath ("" keeps it in memory)
+func NewDangerAlerts(cachePath string) *DangerAlerts {
+	return &DangerAlerts{CachePath: cachePath}
+}
+
+// Notify reports whether a notification for the command with toolUseID is
+// still due, and records it as sent
+func (d *DangerAlerts) Notify(toolUseID string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.load()
+	for _, id := range d.notified {
+		if id == toolUseID {
+			return false
+		}
+	}
+	d.notified = append(d.notified, toolUseID)
+	if len(d.notified) > maxDangerAlerts {
+		d.notified = d.notified[len(d.notified)-maxDangerAlerts:]
+	}
+	d.save()
+	return true
+}
+
+// load reads the persisted IDs once; a missing or corrupt file starts empty
+func (d *DangerAlerts) load() {
+	if d.loaded {
+		return
+	}
+	d.loaded = true
+	var notified []string
+	if filecache.Load(d.CachePath, &notified) {
+		d.notified = append(notified, d.notified...)
+	}
+}
+
+func (d *DangerAlerts) save() {
+	_ = filecache.Save(d.CachePath, d.notified)
+}
@@ -0,0 +1,176 @@
+package transcript
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Transcript fields do not always keep their shape: a message's content is
+// a list of blocks or a plain string, a block's content likewise, and a
+// tool call's toolUseResult an object or an error message. Lines are decoded
+// leniently, so a field of an unexpected type is left empty, and its path
+// noted, rather than costing the whole line. Only lines that are not JSON
+// objects at all fail to decode
+
+// decodeLenient decodes data into v, leaving fields of an unexpected type
+// empty. It returns the path of the first such field, if any
+func decodeLenient(data []byte, v any) (skipped string, err error) {
+	err = json.Unmarshal(data, v)
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return typeErr.Field, nil
+	}
+	return "", err
+}
+
+// isNull reports whether a raw field is missing or null
+func isNull(raw json.RawMessage) bool {
+	return len(raw) == 0 || string(raw) == "null"
+}
+
+// skip records a field left empty, unless field is ""
+func skip(skipped []string, field string) []string {
+	if field == "" {
+		return skipped
+	}
+	return append(skipped, field)
+}
+
+// skipNested records the fields left empty in the blocks of a content
+// field; a block that was not an object at all is noted as content itself
+func skipNested(skipped []string, blocks []ContentBlock) []string {
+	for i := range blocks {
+		for _, field := range blocks[i].skipped {
+			if field == "" {
+				skipped = append(skipped, "content")
+			} else {
+				skipped = append(skipped, "content."+field)
+			}
+		}
+	}
+	return skipped
+}
+
+// UnmarshalJSON decodes a transcript line leniently
+func (l *ClaudeCodeTranscriptLine) UnmarshalJSON(data []byte) error {
+	type plain ClaudeCodeTranscriptLine
+	var raw struct {
+		plain
+		Message json.RawMessage `json:"message,omitempty"`
+	}
+	skipped, err := decodeLenient(data, &raw)
+	if err != nil {
+		return err
+	}
+	*l = ClaudeCodeTranscriptLine(raw.plain)
+	l.skipped = skip(nil, skipped)
+
+	switch message := raw.Message; {
+	case isNull(message):
+	case message[0] == '{':
+		l.Message = &ClaudeCodeMessage{}
+		if err := json.Unmarshal(message, l.Message); err != nil {
+			return err
+		}
+		for _, field := range l.Message.skipped {
+			l.skipped = append(l.skipped, "message."+field)
+		}
+	default:
+		l.skipped = append(l.skipped, "message")
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes a message whose content is a list of blocks, a
+// single block or a plain string, which becomes a text block
+func (m *ClaudeCodeMessage) UnmarshalJSON(data []byte) error {
+	type plain ClaudeCodeMessage
+	var raw struct {
+		plain
+		Content json.RawMessage `json:"content"`
+	}
+	skipped, err := decodeLenient(data, &raw)
+	if err != nil {
+		return err
+	}
+	*m = ClaudeCodeMessage(raw.plain)
+	m.skipped = skip(nil, skipped)
+
+	switch content := raw.Content; {
+	case isNull(content):
+	case content[0] == '"':
+		var text string
+		if err := json.Unmarshal(content, &text); err != nil {
+			return err
+		}
+		m.Content = []ContentBlock{{Type: "text", Text: text}}
+	case content[0] == '[':
+		if err := json.Unmarshal(content, &m.Content); err != nil {
+			return err
+		}
+	case content[0] == '{':
+		m.Content = make([]ContentBlock, 1)
+		if err := json.Unmarshal(content, &m.Content[0]); err != nil {
+			return err
+		}
+	default:
+		m.skipped = append(m.skipped, "content")
+	}
+	m.skipped = skipNested(m.skipped, m.Content)
+	return nil
+}
+
+// UnmarshalJSON decodes a content block whose content is either an array of
+// nested blocks or a plain string, which is kept in ContentStr. A block
+// given as a plain string is a text block
+func (b *ContentBlock) UnmarshalJSON(data []byte) error {
+	switch {
+	case len(data) > 0 && data[0] == '"':
+		*b = ContentBlock{Type: "text"}
+		return json.Unmarshal(data, &b.Text)
+	case len(data) > 0 && data[0] != '{' && !isNull(data):
+		*b = ContentBlock{skipped: []string{""}}
+		return nil
+	}
+
+	type plain ContentBlock
+	var raw struct {
+		plain
+		Content json.RawMessage `json:"content,omitempty"`
+	}
+	skipped, err := decodeLenient(data, &raw)
+	if err != nil {
+		return err
+	}
+	*b = ContentBlock(raw.plain)
+	b.skipped = skip(nil, skipped)
+
+	switch content := raw.Content; {
+	case isNull(content):
+	case content[0] == '"':
+		if err := json.Unmarshal(content, &b.ContentStr); err != nil {
+			return err
+		}
+	case content[0] == '[':
+		if err := json.Unmarshal(content, &b.Content); err != nil {
+			return err
+		}
+	default:
+		b.skipped = append(b.skipped, "content")
+	}
+	b.skipped = skipNested(b.skipped, b.Content)
+	return nil
+}
+
+// UnmarshalJSON decodes a tool call's extra result info, which failed calls
+// give as a plain error message instead; that, or any other value but an
+// object, leaves it empty
+func (r *ToolResultExtra) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] != '{' {
+		*r = ToolResultExtra{}
+		return nil
+	}
+	type plain ToolResultExtra
+	_, err := decodeLenient(data, (*plain)(r))
+	return err
+}
@@ -0,0 +1,121 @@
+package transcript
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// ProjectsDir returns the directory where Claude Code stores per-project transcripts
+func ProjectsDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".claude", "projects"), nil
+}
+
+// ListTranscripts returns transcript files under dir, including .jsonl.gz
+// archives, modified at or after since, oldest first. A zero since returns
+// every transcript
+func ListTranscripts(dir string, since time.Time) ([]string, error) {
+	return listTranscripts(filepath.Join(dir, "*"), since)
+}
+
+// ListProjectTranscripts returns the transcripts of one project directory,
+// as returned by ProjectDirFor, including .jsonl.gz archives, oldest first
+func ListProjectTranscripts(projectDir string) ([]string, error) {
+	return listTranscripts(projectDir, time.Time{})
+}
+
+// listTranscripts returns the transcripts in the directories matching dirGlob
+func listTranscripts(dirGlob string, since time.Time) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dirGlob, "*.jsonl"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transcripts: %w", err)
+	}
+	archives, _ := filepath.Glob(filepath.Join(dirGlob, "*.jsonl"+ArchiveExt))
+	matches = append(matches, archives...)
+
+	type entry struct {
+		path    string
+		modTime time.Time
+	}
+	entries := make([]entry, 0, len(matches))
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if !since.IsZero() && info.ModTime().Before(since) {
+			continue
+		}
+		entries = append(entries, entry{path: path, modTime: info.ModTime()})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].modTime.Before(entries[j].modTime)
+	})
+
+	paths := make([]string, len(entries))
+	for i, e := range entries {
+		paths[i] = e.path
+	}
+	return paths, nil
+}
+
+// ProjectDirFor returns the transcript directory Claude Code uses for a
+// working directory; it encodes the path by replacing every character other
+// than letters and digits with '-'
+func ProjectDirFor(projectsDir, cwd string) string {
+	encoded := make([]byte, len(cwd))
+	for i := 0; i < len(cwd); i++ {
+		c := cwd[i]
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			encoded[i] = c
+		} else {
+			encoded[i] = '-'
+		}
+	}
+	return filepath.Join(projectsDir, string(encoded))
+}
+
+// LatestFor returns the most recently modified transcript of a session
+// started in cwd or, failing that, in the nearest parent directory with
+// sessions, as Claude Code is often started at the project root
+// Returns "" when no session ran there
+func LatestFor(projectsDir, cwd string) string {
+	for dir := filepath.Clean(cwd); ; dir = filepath.Dir(dir) {
+		if path := latestIn(ProjectDirFor(projectsDir, dir)); path != "" {
+			return path
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return ""
+		}
+	}
+}
+
+// LatestAt returns the most recently modified transcript of a session
+// started in cwd itself, without looking in parent directories, or ""
+func LatestAt(projectsDir, cwd string) string {
+	return latestIn(ProjectDirFor(projectsDir, filepath.Clean(cwd)))
+}
+
+// latestIn returns the most recently modified transcript in dir, or ""
+func latestIn(dir string) string {
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	var latest string
+	var latestTime time.Time
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if latest == "" || info.ModTime().After(latestTime) {
+			latest, latestTime = path, info.ModTime()
+		}
+	}
+	return latest
+}
@@ -0,0 +1,105 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestProjectDirFor(t *testing.T) {
+	got := ProjectDirFor("/home/u/.claude/projects", "/home/u/my_app.v2")
+	want := filepath.Join("/home/u/.claude/projects", "-home-u-my-app-v2")
+	if got != want {
+		t.Errorf("ProjectDirFor() = %q, want %q", got, want)
+	}
+}
+
+func TestListTranscripts(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "proj-a", "old.jsonl")
+	recent := filepath.Join(dir, "proj-b", "recent.jsonl")
+	for _, path := range []string{old, recent, filepath.Join(dir, "proj-a", "notes.txt")} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	past := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(old, past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := ListTranscripts(dir, time.Time{})
+	if err != nil {
+		t.Fatalf("ListTranscripts() error = %v", err)
+	}
+	if len(all) != 2 || all[0] != old || all[1] != recent {
+		t.Errorf("ListTranscripts() = %v, want [old recent]", all)
+	}
+
+	since, err := ListTranscripts(dir, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ListTranscripts(since) error = %v", err)
+	}
+	if len(since) != 1 || since[0] != recent {
+		t.Errorf("ListTranscripts(since) = %v, want [recent]", since)
+	}
+
+	// Archived transcripts are listed too
+	archived, err := Archive(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err = ListTranscripts(dir, time.Time{})
+	if err != nil {
+		t.Fatalf("ListTranscripts() error = %v", err)
+	}
+	if len(all) != 2 || all[0] != archived {
+		t.Errorf("ListTranscripts() = %v, want [%s recent]", all, archived)
+	}
+}
+
+func TestLatestFor(t *testing.T) {
+	projects := t.TempDir()
+	write := func(cwd, name string, age time.Duration) string {
+		path := filepath.Join(ProjectDirFor(projects, cwd), name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		modTime := time.Now().Add(-age)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	write("/work/app", "old.jsonl", time.Hour)
+	current := write("/work/app", "current.jsonl", time.Minute)
+	other := write("/work/other", "other.jsonl", 0)
+
+	tests := []struct {
+		cwd  string
+		want string
+	}{
+		{"/work/app", current},
+		{"/work/app/internal/api", current}, // Sessions of the project root
+		{"/work/other/", other},
+		{"/work/none", ""},
+	}
+	for _, tt := range tests {
+		if got := LatestFor(projects, tt.cwd); got != tt.want {
+			t.Errorf("LatestFor(%q) = %q, want %q", tt.cwd, got, tt.want)
+		}
+	}
+	if got := LatestAt(projects, "/work/app/"); got != current {
+		t.Errorf("LatestAt(/work/app/) = %q, want %q", got, current)
+	}
+	if got := LatestAt(projects, "/work/app/internal/api"); got != "" {
+		t.Errorf("LatestAt() = %q, want none for a directory without sessions of its own", got)
+	}
+}
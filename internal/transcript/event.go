@@ -2,6 +2,7 @@ package transcript
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 )
 
@@ -69,7 +70,7 @@ type ToolInfo struct {
 	ToolUseID string          `json:"tool_use_id,omitempty"`
 	LastUsed  time.Time       `json:"-"` // Track last use time for recency sorting
 	Target    string          `json:"-"` // Extracted target (file path, pattern, command)
-	Status    string          `json:"-"` // running, completed, error
+	Status    string          `json:"-"` // running, completed, error, interrupted
 }
 
 // ToolUsage represents aggregated tool usage statistics
@@ -78,7 +79,7 @@ type ToolUsage struct {
 	Count    int
 	LastUsed time.Time
 	Target   string // Extracted target (file path, pattern, command)
-	Status   string // running, completed, error
+	Status   string // running, completed, error, interrupted
 }
 
 // ToolResult contains the result of a tool execution
@@ -95,6 +96,8 @@ type AgentInfo struct {
 	Type      string `json:"type,omitempty"`
 	Input     string `json:"input,omitempty"`
 	Status    string `json:"status,omitempty"`
+
+	LastSeen time.Time `json:"-"` // When the agent was last reported, for ordering
 }
 
 // AgentMessageInfo contains messages from agents
@@ -133,6 +136,39 @@ type ContextWindow struct {
 	ContextWindowSize int       `json:"context_window_size"`
 }
 
+// Percentage returns context usage as a percentage of the window
+// Includes auto-compact buffer in calculation for accuracy
+func (cw *ContextWindow) Percentage() int {
+	if cw.ContextWindowSize == 0 {
+		return 0
+	}
+
+	totalTokens := cw.CurrentUsage.TotalInput()
+
+	// Include auto-compact buffer in calculation
+	percentage := (totalTokens + AUTOCOMPACT_BUFFER) * 100 / cw.ContextWindowSize
+	if percentage > 100 {
+		return 100
+	}
+	if percentage < 0 {
+		return 0
+	}
+	return percentage
+}
+
+// ExceedsStandardWindow reports whether the context has grown past the
+// standard 200k window, where long-context pricing applies
+func (cw *ContextWindow) ExceedsStandardWindow() bool {
+	return cw.CurrentUsage.TotalInput() > STANDARD_CONTEXT_WINDOW
+}
+
+// IsLongContextModel reports whether a model ID or display name names a
+// 1M-context beta model, e.g. "claude-sonnet-4-5[1m]" or "Sonnet 4.5 (1M context)"
+func IsLongContextModel(model string) bool {
+	model = strings.ToLower(model)
+	return strings.Contains(model, "[1m]") || strings.Contains(model, "1m context")
+}
+
 // UsageInfo contains token usage breakdown
 type UsageInfo struct {
 	InputTokens              int `json:"input_tokens"`
@@ -153,15 +189,22 @@ type ClaudeCodeTranscriptLine struct {
 	ToolUseResult *ToolResultExtra   `json:"toolUseResult,omitempty"`
 	UUID          string             `json:"uuid,omitempty"`
 	Timestamp     string             `json:"timestamp,omitempty"`
+	IsMeta        bool               `json:"isMeta,omitempty"`      // Injected context such as command output, not typed by the user
+	IsSidechain   bool               `json:"isSidechain,omitempty"` // Subagent conversation
+
+	skipped []string // Fields left empty because of an unexpected type, by path
 }
 
 // ClaudeCodeMessage represents the full message structure from Claude Code
 type ClaudeCodeMessage struct {
+	ID         string         `json:"id,omitempty"` // Shared by the lines of one API response
 	Role       string         `json:"role"`
 	Model      string         `json:"model,omitempty"`
 	Content    []ContentBlock `json:"content"`
 	Usage      *UsageInfo     `json:"usage,omitempty"`
 	StopReason string         `json:"stop_reason,omitempty"`
+
+	skipped []string
 }
 
 // ContentBlock represents a content block within a message
@@ -172,9 +215,20 @@ type ContentBlock struct {
 	Input      json.RawMessage `json:"input,omitempty"`       // for tool_use
 	ToolUseID  string          `json:"tool_use_id,omitempty"` // for tool_result
 	Content    []ContentBlock  `json:"content,omitempty"`     // nested content array
-	ContentStr string          `json:"-"`                     // Raw content string (extracted separately)
+	ContentStr string          `json:"-"`                     // Content given as a plain string, as tool results often are
 	Text       string          `json:"text,omitempty"`        // for text blocks
 	IsError    bool            `json:"is_error,omitempty"`    // for tool_result error status
+
+	skipped []string
+}
+
+// PlainText returns the text of a block, including text nested in its content
+func (b *ContentBlock) PlainText() string {
+	text := b.Text + b.ContentStr
+	for i := range b.Content {
+		text += b.Content[i].PlainText()
+	}
+	return text
 }
 
 // ToolResultExtra contains extended tool result info
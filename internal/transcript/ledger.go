@@ -0,0 +1,96 @@
+package transcript
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/render"
+)
+
+// TouchedFile is a file changed in the session, with how often it was changed
+type TouchedFile struct {
+	Path  string
+	Edits int       // Successful Write, Edit, MultiEdit and NotebookEdit calls
+	Last  time.Time // When it was last changed
+}
+
+// editTools are the tools that change files, with the input field holding
+// the file's path
+var editTools = map[string]string{
+	"Write":        "file_path",
+	"Edit":         "file_path",
+	"MultiEdit":    "file_path",
+	"NotebookEdit": "notebook_path",
+}
+
+// pendingEdit is an edit tool call waiting for its result
+type pendingEdit struct {
+	path string
+	at   time.Time
+}
+
+// trackEdit notes an edit tool call; the file counts as touched once the
+// call succeeds
+func (p *Parser) trackEdit(toolUseID, toolName string, input json.RawMessage, at time.Time) {
+	field, ok := editTools[toolName]
+	if !ok || toolUseID == "" {
+		return
+	}
+	path, _ := decodeToolInput(input)[field].(string)
+	if path == "" {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pendingEdits[toolUseID] = pendingEdit{path: path, at: at}
+}
+
+// resolveEdit records the outcome of an edit tool call, if toolUseID is one
+func (p *Parser) resolveEdit(toolUseID string, succeeded bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	edit, ok := p.pendingEdits[toolUseID]
+	if !ok {
+		return
+	}
+	delete(p.pendingEdits, toolUseID)
+	if !succeeded {
+		return
+	}
+	file, ok := p.touchedFiles[edit.path]
+	if !ok {
+		file = &TouchedFile{Path: edit.path}
+		p.touchedFiles[edit.path] = file
+	}
+	file.Edits++
+	if edit.at.After(file.Last) {
+		file.Last = edit.at
+	}
+}
+
+// GetTouchedFiles returns the files changed in the session, most recently
+// changed first
+func (p *Parser) GetTouchedFiles() []TouchedFile {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	files := render.Values(p.touchedFiles, func(a, b *TouchedFile) int {
+		return render.Newest(a.Last, b.Last)
+	})
+	result := make([]TouchedFile, len(files))
+	for i, file := range files {
+		result[i] = *file
+	}
+	return result
+}
+
+func copyTouchedFiles(files map[string]*TouchedFile) map[string]*TouchedFile {
+	copied := make(map[string]*TouchedFile, len(files))
+	for path, f := range files {
+		c := *f
+		copied[path] = &c
+	}
+	return copied
+}
@@ -0,0 +1,155 @@
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+)
+
+// How lines longer than the line limit are handled
+const (
+	OversizedSkip  = "skip"  // Skip the line and read on, e.g. past a huge base64 tool result
+	OversizedAbort = "abort" // Stop reading with an error
+)
+
+// LineLimit is how long a transcript line may be, and what becomes of
+// longer ones
+type LineLimit struct {
+	MaxBytes  int
+	Oversized string // OversizedSkip or OversizedAbort
+}
+
+// defaultLineLimit keeps lines up to MAX_SCAN_TOKEN_SIZE and skips longer ones
+var defaultLineLimit = LineLimit{MaxBytes: MAX_SCAN_TOKEN_SIZE, Oversized: OversizedSkip}
+
+// lineLimit is the limit transcripts are read with
+var lineLimit atomic.Pointer[LineLimit]
+
+// SetLineLimit sets how long transcript lines may be and what becomes of
+// longer ones, for readers created from now on. A MaxBytes of 0 keeps the
+// default size
+func SetLineLimit(limit LineLimit) {
+	if limit.MaxBytes <= 0 {
+		limit.MaxBytes = defaultLineLimit.MaxBytes
+	}
+	if limit.Oversized != OversizedAbort {
+		limit.Oversized = OversizedSkip
+	}
+	lineLimit.Store(&limit)
+}
+
+// currentLineLimit returns the limit set with SetLineLimit, or the default
+func currentLineLimit() LineLimit {
+	if limit := lineLimit.Load(); limit != nil {
+		return *limit
+	}
+	return defaultLineLimit
+}
+
+// LineReader reads a transcript line by line. Claude Code appends to the
+// transcript while it is read, so the last line may be only partly written;
+// LineReader tells a line ended by a newline from such a trailing one.
+// Lines over the line limit are skipped without being held in memory, or
+// stop the reader, as SetLineLimit chose
+type LineReader struct {
+	reader  *bufio.Reader
+	limit   LineLimit
+	line    []byte // A line longer than the reader's buffer, gathered
+	num     int    // Number of the line returned last, from 1
+	skipped int    // Lines skipped for being over the limit
+	offset  int64  // Bytes read, up to the end of the line returned last
+}
+
+// NewLineReader reads lines from r with the current line limit
+func NewLineReader(r io.Reader) *LineReader {
+	return newLineReader(r, currentLineLimit())
+}
+
+// newLineReader reads lines from r with the given line limit
+func newLineReader(r io.Reader, limit LineLimit) *LineReader {
+	return &LineReader{reader: bufio.NewReaderSize(r, 64*1024), limit: limit}
+}
+
+// Next returns the next line, without its line ending, and whether a
+// newline ended it; only the last line of the input can be unterminated.
+// The line is valid until the next call. At the end of the input it
+// returns io.EOF
+func (l *LineReader) Next() (line []byte, terminated bool, err error) {
+	for {
+		line, terminated, err = l.read()
+		if err != errLineTooLong {
+			return line, terminated, err
+		}
+		if l.limit.Oversized == OversizedAbort {
+			return nil, false, fmt.Errorf("line %d is longer than %d bytes", l.num, l.limit.MaxBytes)
+		}
+		l.skipped++
+		if l.skipped <= 10 {
+			errors.Warn("transcript", "skipped line %d, which is longer than %d bytes", l.num, l.limit.MaxBytes)
+		}
+	}
+}
+
+// Line returns the number of the line Next returned last, counting from 1
+func (l *LineReader) Line() int {
+	return l.num
+}
+
+// Offset returns how many bytes of the input were read, up to and including
+// the end of the line Next returned last
+func (l *LineReader) Offset() int64 {
+	return l.offset
+}
+
+// Skipped returns the number of lines skipped for being over the limit
+func (l *LineReader) Skipped() int {
+	return l.skipped
+}
+
+// errLineTooLong is returned by read for a line over the limit, once the
+// rest of it has been read past
+var errLineTooLong = fmt.Errorf("line too long")
+
+// read returns the next line, or errLineTooLong for one over the limit
+func (l *LineReader) read() (line []byte, terminated bool, err error) {
+	l.line = l.line[:0]
+	tooLong := false
+	for {
+		chunk, err := l.reader.ReadSlice('\n')
+		l.offset += int64(len(chunk))
+		if err == bufio.ErrBufferFull {
+			if !tooLong {
+				l.line = append(l.line, chunk...)
+				tooLong = len(l.line) > l.limit.MaxBytes
+			}
+			continue
+		}
+		if err != nil && err != io.EOF {
+			return nil, false, err
+		}
+		if err == io.EOF && len(l.line)+len(chunk) == 0 && !tooLong {
+			return nil, false, io.EOF
+		}
+
+		l.num++
+		if tooLong {
+			l.line = l.line[:0]
+			return nil, false, errLineTooLong
+		}
+		line = chunk
+		if len(l.line) > 0 {
+			l.line = append(l.line, chunk...)
+			line = l.line
+		}
+		terminated = err == nil
+		line = bytes.TrimSuffix(bytes.TrimSuffix(line, []byte("\n")), []byte("\r"))
+		if len(line) > l.limit.MaxBytes {
+			return nil, false, errLineTooLong
+		}
+		return line, terminated, nil
+	}
+}
@@ -0,0 +1,75 @@
+package transcript
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLineReader(t *testing.T) {
+	long := strings.Repeat("x", 100*1024) // Longer than the reader's buffer
+	input := "one\r\n\n" + long + "\ntwo"
+
+	lines := newLineReader(strings.NewReader(input), defaultLineLimit)
+	want := []struct {
+		line       string
+		terminated bool
+	}{{"one", true}, {"", true}, {long, true}, {"two", false}}
+	for i, w := range want {
+		line, terminated, err := lines.Next()
+		if err != nil {
+			t.Fatalf("next() #%d error = %v", i+1, err)
+		}
+		if string(line) != w.line || terminated != w.terminated {
+			t.Errorf("next() #%d = %.10q, %v, want %.10q, %v", i+1, line, terminated, w.line, w.terminated)
+		}
+	}
+	if _, _, err := lines.Next(); err != io.EOF {
+		t.Errorf("next() at the end error = %v, want io.EOF", err)
+	}
+	if lines.Line() != 4 {
+		t.Errorf("Line() = %d, want 4", lines.Line())
+	}
+}
+
+func TestLineReader_Oversized(t *testing.T) {
+	input := "short\n" + strings.Repeat("x", 200*1024) + "\n" + strings.Repeat("y", 1000) + "\nlast"
+
+	lines := newLineReader(strings.NewReader(input), LineLimit{MaxBytes: 500, Oversized: OversizedSkip})
+	var got []string
+	for {
+		line, _, err := lines.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		got = append(got, fmt.Sprintf("%d:%s", lines.Line(), line))
+	}
+	if strings.Join(got, " ") != "1:short 4:last" || lines.Skipped() != 2 {
+		t.Errorf("read %v, skipping %d, want lines 1 and 4, skipping 2", got, lines.Skipped())
+	}
+
+	lines = newLineReader(strings.NewReader(input), LineLimit{MaxBytes: 500, Oversized: OversizedAbort})
+	if _, _, err := lines.Next(); err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if _, _, err := lines.Next(); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Next() of a line over the limit error = %v, want one naming line 2", err)
+	}
+}
+
+func TestSetLineLimit(t *testing.T) {
+	t.Cleanup(func() { SetLineLimit(defaultLineLimit) })
+
+	SetLineLimit(LineLimit{Oversized: "explode"})
+	if got := currentLineLimit(); got != defaultLineLimit {
+		t.Errorf("limit = %+v, want the default for unset values", got)
+	}
+	SetLineLimit(LineLimit{MaxBytes: 4096, Oversized: OversizedAbort})
+	if got := NewLineReader(strings.NewReader("")).limit; got.MaxBytes != 4096 || got.Oversized != OversizedAbort {
+		t.Errorf("reader limit = %+v, want 4096 bytes and abort", got)
+	}
+}
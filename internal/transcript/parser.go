@@ -1,52 +1,90 @@
 package transcript
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/ll931217/claude-hud-enhanced/internal/errors"
+	"github.com/ll931217/claude-hud-enhanced/internal/humanize"
+	"github.com/ll931217/claude-hud-enhanced/internal/render"
 )
 
 // Constants for context window calculations
 const (
-	AUTOCOMPACT_BUFFER  = 128000      // Tokens reserved for auto-compact
-	MAX_SCAN_TOKEN_SIZE = 1024 * 1024 // 1MB max line size for transcript parsing
+	AUTOCOMPACT_BUFFER      = 128000      // Tokens reserved for auto-compact
+	MAX_SCAN_TOKEN_SIZE     = 1024 * 1024 // Default max line size for transcript parsing, 1MB; see SetLineLimit
+	STANDARD_CONTEXT_WINDOW = 200000      // Context window of most models
+	LONG_CONTEXT_WINDOW     = 1000000     // Context window of 1M-context beta models
 )
 
 // Parser handles parsing Claude Code transcript JSONL files
 type Parser struct {
 	mu                sync.RWMutex
+	parseMu           sync.Mutex // Serializes Parse when sections share a parser
 	state             *ParserState
 	transcriptPath    string
 	lastModified      time.Time
 	lastFileSize      int64
+	lastFile          os.FileInfo   // Identifies the file parsed last, to notice rotation
+	carried           sessionTotals // Totals from rotated or truncated content
+	countedSize       int64         // Leading bytes of the file already in carried, after it was truncated
 	latestEvents      map[EventType]*Event
 	toolActivity      map[string]*ToolInfo
 	agentActivity     map[string]*AgentInfo
 	contextWindow     *ContextWindow
 	sessionStart      time.Time
-	sessionEnd        time.Time
+	lastActivity      time.Time // Latest line other than tool calls, e.g. a tool result or message
 	totalInputTokens  int
 	totalOutputTokens int
+	modelUsage        map[string]*ModelUsage // Token usage per model, for mixed-model sessions
+	turns             TurnStats
+	turn              currentTurn
 	todos             map[string]*TodoInfo
 	errors            []*ErrorInfo
+	errorsTotal       int
+	toolOrder         map[string][]string // Tool keys per name, oldest first
+	toolStats         map[string]*ToolStats
+	touchedFiles      map[string]*TouchedFile     // Files changed in the session, by path
+	pendingEdits      map[string]pendingEdit      // Edit tool calls awaiting results, by tool use ID
+	dangerous         map[string]DangerousCommand // Bash commands matching a dangerous pattern, by tool use ID
+	evictedTools      int
+	maxToolsPerName   int
 }
 
 // ParserState tracks the current state of the parser
 type ParserState struct {
 	LinesParsed       int
 	ErrorsEncountered int
+	FieldErrors       map[string]int // Fields left empty because of an unexpected type, by path, e.g. message.content.is_error
+	PartialLine       bool           // The last line was still being written, and is left for the next parse
+	OversizedLines    int            // Lines skipped for being longer than the line limit
 	LastParseTime     time.Time
 }
 
+// countFieldErrors counts the fields a line was decoded without
+func (s *ParserState) countFieldErrors(fields []string) {
+	if len(fields) == 0 {
+		return
+	}
+	if s.FieldErrors == nil {
+		s.FieldErrors = make(map[string]int)
+	}
+	for _, field := range fields {
+		if s.FieldErrors[field] == 0 {
+			errors.Debug("transcript.parser", "field %s has an unexpected type; decoding lines without it", field)
+		}
+		s.FieldErrors[field]++
+	}
+}
+
 // NewParser creates a new transcript parser
 func NewParser(transcriptPath string) *Parser {
 	return &Parser{
@@ -55,6 +93,12 @@ func NewParser(transcriptPath string) *Parser {
 		toolActivity:   make(map[string]*ToolInfo),
 		agentActivity:  make(map[string]*AgentInfo),
 		todos:          make(map[string]*TodoInfo),
+		toolOrder:      make(map[string][]string),
+		toolStats:      make(map[string]*ToolStats),
+		touchedFiles:   make(map[string]*TouchedFile),
+		pendingEdits:   make(map[string]pendingEdit),
+		dangerous:      make(map[string]DangerousCommand),
+		modelUsage:     make(map[string]*ModelUsage),
 		state:          &ParserState{},
 	}
 }
@@ -62,6 +106,9 @@ func NewParser(transcriptPath string) *Parser {
 // Parse reads and parses the transcript file
 // Uses streaming to avoid loading the entire file into memory
 func (p *Parser) Parse(ctx context.Context) error {
+	p.parseMu.Lock()
+	defer p.parseMu.Unlock()
+
 	return errors.SafeCall(func() error {
 		// Check if file exists
 		if _, err := os.Stat(p.transcriptPath); os.IsNotExist(err) {
@@ -74,11 +121,25 @@ func (p *Parser) Parse(ctx context.Context) error {
 			return fmt.Errorf("failed to stat transcript: %w", err)
 		}
 
-		// Check if file has changed since last parse
+		// Check if file has changed since last parse. A rotated file can
+		// have an older modification time and the same size, so the file's
+		// identity is compared too
 		p.mu.Lock()
-		modified := info.ModTime().After(p.lastModified) || info.Size() != p.lastFileSize
-		p.lastModified = info.ModTime()
-		p.lastFileSize = info.Size()
+		replaced := rotated(p.lastFile, info)
+		shrunk := truncated(p.lastFile, p.lastFileSize, info)
+		modified := replaced || info.ModTime().After(p.lastModified) || info.Size() != p.lastFileSize
+		if (replaced || shrunk) && p.state.LinesParsed > 0 {
+			errors.Debug("transcript.parser", "%s was rotated or truncated; keeping the session's totals", p.transcriptPath)
+			p.carryTotalsLocked()
+			// A new file holds nothing counted; a truncated one, what is left
+			p.countedSize = 0
+			if shrunk {
+				p.countedSize = info.Size()
+			}
+		}
+		// The file's identity is recorded now so its totals are carried once;
+		// its size and time once it parsed, so a failed parse is retried
+		p.lastFile = info
 		p.mu.Unlock()
 
 		if !modified && p.state.LinesParsed > 0 {
@@ -86,8 +147,8 @@ func (p *Parser) Parse(ctx context.Context) error {
 			return nil
 		}
 
-		// Open the file
-		file, err := os.Open(p.transcriptPath)
+		// Open the file, decompressing archived transcripts
+		file, err := Open(p.transcriptPath)
 		if err != nil {
 			return fmt.Errorf("failed to open transcript: %w", err)
 		}
@@ -96,47 +157,75 @@ func (p *Parser) Parse(ctx context.Context) error {
 		// Reset state for fresh parse
 		p.resetState()
 
-		// Parse line by line
-		scanner := bufio.NewScanner(file)
-		// Increase buffer size for long transcript lines (Claude Code format can have very long lines)
-		buf := make([]byte, 0, MAX_SCAN_TOKEN_SIZE)
-		scanner.Buffer(buf, MAX_SCAN_TOKEN_SIZE)
-		lineNum := 0
-
-		for scanner.Scan() {
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			default:
-			}
+		if err := p.parseLines(ctx, file); err != nil {
+			return err
+		}
 
-			lineNum++
-			line := scanner.Bytes()
+		p.mu.Lock()
+		p.lastModified = info.ModTime()
+		p.lastFileSize = info.Size()
+		p.mu.Unlock()
+		p.state.LastParseTime = time.Now()
 
-			if len(line) == 0 {
-				continue
-			}
+		stats := p.MemoryStats()
+		errors.Debug("transcript.parser", "parsed %d lines: %d tool entries (%d evicted), %d errors retained of %d",
+			p.state.LinesParsed, stats.ToolEntries, stats.EvictedTools, stats.Errors, stats.ErrorsTotal)
+		return nil
+	})
+}
 
-			// Parse the line
-			if err := p.parseLine(line); err != nil {
-				// Log error but continue parsing
-				p.state.ErrorsEncountered++
-				if p.state.ErrorsEncountered <= 10 {
-					// Only log first 10 errors to avoid spam
-					errors.Warn("transcript.parser", "line %d: %v", lineNum, err)
-				}
-			}
+// parseLines parses a transcript's lines. A last line that is not yet
+// complete JSON, because Claude Code is still writing it, is left for the
+// next parse instead of counting as an error
+func (p *Parser) parseLines(ctx context.Context, r io.Reader) error {
+	lines := NewLineReader(r)
+	defer func() { p.state.OversizedLines = lines.Skipped() }()
+	p.mu.RLock()
+	counted := p.countedSize
+	p.mu.RUnlock()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
 
-			p.state.LinesParsed++
+		line, terminated, err := lines.Next()
+		if counted > 0 && (err == io.EOF || lines.Offset() > counted) {
+			// The lines up to here survived a truncation and are in the
+			// carried totals already; count only what follows
+			p.mu.Lock()
+			p.seedTotalsLocked()
+			p.mu.Unlock()
+			counted = 0
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read transcript: %w", err)
+		}
+		if len(line) == 0 {
+			continue
+		}
+		if !terminated && !json.Valid(line) {
+			p.state.PartialLine = true
+			errors.Debug("transcript.parser", "line %d is still being written; leaving it for the next parse", lines.Line())
+			return nil
 		}
 
-		if err := scanner.Err(); err != nil {
-			return fmt.Errorf("scanner error: %w", err)
+		// Parse the line
+		if err := p.parseLine(line); err != nil {
+			// Log error but continue parsing
+			p.state.ErrorsEncountered++
+			if p.state.ErrorsEncountered <= 10 {
+				// Only log first 10 errors to avoid spam
+				errors.Warn("transcript.parser", "line %d: %v", lines.Line(), err)
+			}
 		}
 
-		p.state.LastParseTime = time.Now()
-		return nil
-	})
+		p.state.LinesParsed++
+	}
 }
 
 // parseLine parses a single JSONL line
@@ -153,6 +242,9 @@ func (p *Parser) parseLine(line []byte) error {
 	// Try to parse as Claude Code format first
 	var ccLine ClaudeCodeTranscriptLine
 	ccParseErr := json.Unmarshal(line, &ccLine)
+	if ccParseErr == nil {
+		p.state.countFieldErrors(ccLine.skipped)
+	}
 
 	// Handle Claude Code format with content blocks
 	if ccParseErr == nil && ccLine.Message != nil && len(ccLine.Message.Content) > 0 {
@@ -181,7 +273,15 @@ func (p *Parser) parseLine(line []byte) error {
 					}
 
 					// Use the content block ID as the tracking key
-					p.toolActivity[block.ID] = toolInfo
+					p.recordTool(block.ID, toolInfo)
+					p.trackEdit(block.ID, block.Name, block.Input, toolInfo.LastUsed)
+					p.checkDanger(block.ID, block.Name, block.Input, toolInfo.LastUsed)
+					if block.Name == "TodoWrite" && !ccLine.IsSidechain {
+						p.trackTodoWrite(block.Input)
+					}
+					if !ccLine.IsSidechain {
+						p.trackToolCall(block.ID)
+					}
 
 					// Also set event.ToolUse for compatibility
 					event.ToolUse = toolInfo
@@ -190,10 +290,18 @@ func (p *Parser) parseLine(line []byte) error {
 			case "tool_result":
 				// Update tool status when result comes in
 				if block.ToolUseID != "" {
+					aborted := isAbortedResult(&block)
+					if !ccLine.IsSidechain {
+						p.trackToolResult(block.ToolUseID, aborted)
+					}
+					p.resolveEdit(block.ToolUseID, !block.IsError)
 					if existingTool, ok := p.toolActivity[block.ToolUseID]; ok {
-						// Set status based on is_error field
-						if block.IsError {
+						// Set status based on is_error field; calls the user stopped didn't fail
+						if aborted {
+							existingTool.Status = "interrupted"
+						} else if block.IsError {
 							existingTool.Status = "error"
+							p.countToolError(existingTool.Name)
 							// Track error
 							p.trackError(ccLine.Timestamp, existingTool.Name, "Tool execution failed", "error")
 						} else {
@@ -203,15 +311,17 @@ func (p *Parser) parseLine(line []byte) error {
 						// Tool not found - might be a tool_result without a matching tool_use
 						// Create an entry with completed status
 						status := "completed"
-						if block.IsError {
+						if aborted {
+							status = "interrupted"
+						} else if block.IsError {
 							status = "error"
 							p.trackError(ccLine.Timestamp, "Unknown", "Tool execution failed", "error")
 						}
-						p.toolActivity[block.ToolUseID] = &ToolInfo{
+						p.recordTool(block.ToolUseID, &ToolInfo{
 							Name:      "Unknown",
 							Status:    status,
 							ToolUseID: block.ToolUseID,
-						}
+						})
 					}
 
 					// Set event.ToolResult for compatibility
@@ -237,6 +347,10 @@ func (p *Parser) parseLine(line []byte) error {
 				// Update existing context window's usage
 				p.contextWindow.CurrentUsage = *ccLine.Message.Usage
 			}
+			// Only 1M-context models can go past the standard window
+			if p.contextWindow.ExceedsStandardWindow() && p.contextWindow.ContextWindowSize < LONG_CONTEXT_WINDOW {
+				p.contextWindow.ContextWindowSize = LONG_CONTEXT_WINDOW
+			}
 		}
 
 		// Track session start time
@@ -246,13 +360,29 @@ func (p *Parser) parseLine(line []byte) error {
 			}
 		}
 
+		if !onlyToolUse(ccLine.Message.Content) {
+			if t, err := time.Parse(time.RFC3339Nano, ccLine.Timestamp); err == nil && t.After(p.lastActivity) {
+				p.lastActivity = t
+			}
+		}
+
+		if !ccLine.IsSidechain {
+			if isInterruptNotice(&ccLine) {
+				p.trackInterrupt()
+			}
+			p.trackTurn(ccLine.Message.Role, ccLine.Message.ID, isPrompt(&ccLine), ccLine.Timestamp, ccLine.Message.Usage)
+		}
+
 		// Track token usage from message usage
 		if ccLine.Message.Usage != nil {
 			p.totalInputTokens += ccLine.Message.Usage.InputTokens
 			p.totalOutputTokens += ccLine.Message.Usage.OutputTokens
+			p.recordModelUsage(ccLine.Message.Model, ccLine.Message.Usage.InputTokens, ccLine.Message.Usage.OutputTokens)
 		}
 
 		// Update latest event
+		// Raw points into the reused scanner buffer, so it must not be retained
+		event.Raw = nil
 		p.mu.Lock()
 		p.latestEvents[eventType] = &event
 		p.mu.Unlock()
@@ -268,10 +398,22 @@ func (p *Parser) parseLine(line []byte) error {
 			Timestamp     string         `json:"timestamp,omitempty"`
 			Message       MessageInfo    `json:"message"`
 			ContextWindow *ContextWindow `json:"context_window,omitempty"`
+			IsMeta        bool           `json:"isMeta,omitempty"`
+			IsSidechain   bool           `json:"isSidechain,omitempty"`
 		}
 		if err := json.Unmarshal(line, &msg); err != nil {
 			return err
 		}
+		if !msg.IsSidechain {
+			role := "assistant"
+			prompt := false
+			if eventType == EventTypeUserMessage {
+				role = "user"
+				prompt = !msg.IsMeta && !hasToolResult(line)
+			}
+			usage := &UsageInfo{InputTokens: msg.Message.InputTokens, OutputTokens: msg.Message.OutputTokens}
+			p.trackTurn(role, msg.Message.ID, prompt, msg.Timestamp, usage)
+		}
 		event.Timestamp = msg.Timestamp
 		event.Message = &msg.Message
 		event.ContextWindow = msg.ContextWindow
@@ -295,6 +437,7 @@ func (p *Parser) parseLine(line []byte) error {
 		if msg.Message.OutputTokens > 0 {
 			p.totalOutputTokens += msg.Message.OutputTokens
 		}
+		p.recordModelUsage(msg.Message.Model, msg.Message.InputTokens, msg.Message.OutputTokens)
 
 	case EventTypeToolUse:
 		var tool struct {
@@ -333,7 +476,9 @@ func (p *Parser) parseLine(line []byte) error {
 			if key == "" {
 				key = tool.ToolName + "_" + tool.Timestamp
 			}
-			p.toolActivity[key] = toolInfo
+			p.recordTool(key, toolInfo)
+			p.trackEdit(key, tool.ToolName, tool.ToolUse, toolInfo.LastUsed)
+			p.checkDanger(key, tool.ToolName, tool.ToolUse, toolInfo.LastUsed)
 
 			// Also set event.ToolUse for compatibility
 			event.ToolUse = toolInfo
@@ -360,6 +505,8 @@ func (p *Parser) parseLine(line []byte) error {
 				key = result.ToolName + "_" + result.Timestamp
 			}
 
+			p.resolveEdit(key, true)
+
 			// Check if this tool exists and update its status
 			if existingTool, ok := p.toolActivity[key]; ok {
 				existingTool.Status = "completed"
@@ -375,7 +522,7 @@ func (p *Parser) parseLine(line []byte) error {
 						toolInfo.LastUsed = t
 					}
 				}
-				p.toolActivity[key] = toolInfo
+				p.recordTool(key, toolInfo)
 			}
 
 			// Set event.ToolResult for compatibility
@@ -398,6 +545,7 @@ func (p *Parser) parseLine(line []byte) error {
 
 		// Track agent activity
 		if agent.AgentRun.AgentID != "" {
+			agent.AgentRun.LastSeen, _ = time.Parse(time.RFC3339Nano, agent.Timestamp)
 			p.agentActivity[agent.AgentRun.AgentID] = &agent.AgentRun
 		}
 
@@ -450,6 +598,7 @@ func (p *Parser) parseLine(line []byte) error {
 	}
 
 	// Update latest event for this type
+	event.Raw = nil
 	p.mu.Lock()
 	p.latestEvents[eventType] = &event
 
@@ -474,6 +623,12 @@ func (p *Parser) resetState() {
 	p.agentActivity = make(map[string]*AgentInfo)
 	p.todos = make(map[string]*TodoInfo)
 	p.errors = make([]*ErrorInfo, 0)
+	p.toolOrder = make(map[string][]string)
+	p.pendingEdits = make(map[string]pendingEdit)
+	p.evictedTools = 0
+	p.turn = currentTurn{}
+	// Totals start from what rotated or truncated content held
+	p.seedTotalsLocked()
 	// Keep session start if we already found it
 }
 
@@ -558,21 +713,10 @@ func (p *Parser) HasContextWindow() bool {
 // Includes auto-compact buffer in calculation for accuracy
 func (p *Parser) GetContextPercentage() int {
 	cw := p.GetContextWindow()
-	if cw == nil || cw.ContextWindowSize == 0 {
-		return 0
-	}
-
-	totalTokens := cw.CurrentUsage.TotalInput()
-
-	// Include auto-compact buffer in calculation
-	percentage := (totalTokens + AUTOCOMPACT_BUFFER) * 100 / cw.ContextWindowSize
-	if percentage > 100 {
-		return 100
-	}
-	if percentage < 0 {
+	if cw == nil {
 		return 0
 	}
-	return percentage
+	return cw.Percentage()
 }
 
 // ActiveToolCount returns the number of active tools
@@ -591,36 +735,8 @@ func (p *Parser) ActiveAgentCount() int {
 func (p *Parser) ParseFromReader(ctx context.Context, r io.Reader) error {
 	return errors.SafeCall(func() error {
 		p.resetState()
-
-		scanner := bufio.NewScanner(r)
-		// Increase buffer size for long transcript lines
-		buf := make([]byte, 0, MAX_SCAN_TOKEN_SIZE)
-		scanner.Buffer(buf, MAX_SCAN_TOKEN_SIZE)
-		lineNum := 0
-
-		for scanner.Scan() {
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			default:
-			}
-
-			lineNum++
-			line := scanner.Bytes()
-
-			if len(line) == 0 {
-				continue
-			}
-
-			if err := p.parseLine(line); err != nil {
-				p.state.ErrorsEncountered++
-			}
-
-			p.state.LinesParsed++
-		}
-
-		if err := scanner.Err(); err != nil {
-			return fmt.Errorf("scanner error: %w", err)
+		if err := p.parseLines(ctx, r); err != nil {
+			return err
 		}
 
 		p.state.LastParseTime = time.Now()
@@ -640,6 +756,43 @@ func (p *Parser) GetTodos() map[string]*TodoInfo {
 	return result
 }
 
+// GetTodoList returns the tracked todos in list order
+func (p *Parser) GetTodoList() []TodoInfo {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	todos := render.Values(p.todos, func(a, b *TodoInfo) int {
+		x, errX := strconv.Atoi(a.ID)
+		y, errY := strconv.Atoi(b.ID)
+		if errX == nil && errY == nil {
+			return x - y
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	result := make([]TodoInfo, len(todos))
+	for i, todo := range todos {
+		result[i] = *todo
+	}
+	return result
+}
+
+// trackTodoWrite replaces the todos with the list a TodoWrite call sets,
+// numbered from 1 in list order
+func (p *Parser) trackTodoWrite(input json.RawMessage) {
+	var todos todoInput
+	if json.Unmarshal(input, &todos) != nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.todos = make(map[string]*TodoInfo, len(todos.Todos))
+	for i, todo := range todos.Todos {
+		id := strconv.Itoa(i + 1)
+		p.todos[id] = &TodoInfo{ID: id, Status: todo.Status, Content: todo.Content}
+	}
+}
+
 // GetTodoCount returns the total and completed todo counts
 func (p *Parser) GetTodoCount() (total, completed int) {
 	p.mu.RLock()
@@ -668,12 +821,275 @@ func (p *Parser) GetCurrentTodo() *TodoInfo {
 }
 
 // CalculateCost estimates the token cost based on model pricing
+// Each message is priced with the model that produced it, so sessions that
+// mix models (e.g. Haiku for background tasks) are not priced as one model
 func (p *Parser) CalculateCost() float64 {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
-	// Pricing per million tokens (USD)
-	// These are approximate prices for Claude models
+	cost := 0.0
+	for _, usage := range p.modelUsage {
+		cost += usage.Cost
+	}
+	return cost
+}
+
+// ModelUsage is the token usage and estimated cost of one model in a session
+type ModelUsage struct {
+	Model        string // Empty when messages did not name a model; priced as Opus
+	Messages     int
+	InputTokens  int
+	OutputTokens int
+	Cost         float64
+}
+
+// recordModelUsage adds a message's tokens to its model's usage
+func (p *Parser) recordModelUsage(model string, inputTokens, outputTokens int) {
+	if inputTokens == 0 && outputTokens == 0 {
+		return // Synthetic messages such as interruption notices carry no usage
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	usage, ok := p.modelUsage[model]
+	if !ok {
+		usage = &ModelUsage{Model: model}
+		p.modelUsage[model] = usage
+	}
+	usage.Messages++
+	usage.InputTokens += inputTokens
+	usage.OutputTokens += outputTokens
+	usage.Cost = EstimateCost(model, usage.InputTokens, usage.OutputTokens)
+}
+
+// TurnStats summarizes the conversation turn by turn
+// A turn starts with a prompt typed by the user and lasts until the next
+// one, covering every assistant message and tool call in between
+type TurnStats struct {
+	UserTurns         int           // Prompts typed by the user
+	AssistantTurns    int           // Turns Claude responded to
+	AssistantMessages int           // API responses across all turns
+	Tokens            int           // Input and output tokens of assistant messages
+	LongestTurn       time.Duration // From a prompt to the last assistant message answering it
+	LongestTurnTokens int           // Tokens used by the longest turn
+	Interrupted       int           // Turns the user interrupted or whose tool calls were aborted
+	AbortedTools      int           // Tool calls the user rejected or that never got a result
+}
+
+// AverageTokensPerTurn returns the mean tokens used per assistant turn
+func (s TurnStats) AverageTokensPerTurn() int {
+	if s.AssistantTurns == 0 {
+		return 0
+	}
+	return s.Tokens / s.AssistantTurns
+}
+
+// currentTurn tracks the turn in progress
+type currentTurn struct {
+	start     time.Time
+	tokens    int
+	answered  bool
+	longest   bool   // The turn is the longest so far
+	messageID string // Last assistant message; its repeated lines are counted once
+
+	interrupted  bool
+	pendingTools []string // Tool calls awaiting their result
+}
+
+// Claude Code records an interruption as a user message starting with
+// interruptNotice, and a rejected or interrupted tool call as an error
+// result starting with rejectedToolNotice or interruptNotice
+const (
+	interruptNotice    = "[Request interrupted by user"
+	rejectedToolNotice = "The user doesn't want to proceed with this tool use"
+)
+
+// isInterruptNotice reports whether a user line is Claude Code's notice
+// that the user interrupted the response
+func isInterruptNotice(line *ClaudeCodeTranscriptLine) bool {
+	if line.Message.Role != "user" {
+		return false
+	}
+	for i := range line.Message.Content {
+		block := &line.Message.Content[i]
+		if block.Type == "text" && strings.HasPrefix(block.Text, interruptNotice) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAbortedResult reports whether a tool result records a call the user
+// rejected or interrupted, rather than one that ran and failed
+func isAbortedResult(block *ContentBlock) bool {
+	if !block.IsError {
+		return false
+	}
+	text := block.PlainText()
+	return strings.HasPrefix(text, rejectedToolNotice) || strings.HasPrefix(text, interruptNotice)
+}
+
+// isPrompt reports whether a user line carries a prompt, rather than tool
+// results or context injected by Claude Code
+func isPrompt(line *ClaudeCodeTranscriptLine) bool {
+	if line.Message.Role != "user" || line.IsMeta || isInterruptNotice(line) {
+		return false
+	}
+	for _, block := range line.Message.Content {
+		if block.Type == "tool_result" {
+			return false
+		}
+	}
+	return true
+}
+
+// hasToolResult reports whether a raw user line carries tool results; it
+// handles lines whose content shape ClaudeCodeTranscriptLine can't decode,
+// such as tool results with a plain string as their content
+func hasToolResult(line []byte) bool {
+	var raw struct {
+		Message struct {
+			Content json.RawMessage `json:"content"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return false
+	}
+	var blocks []struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(raw.Message.Content, &blocks); err != nil {
+		return false // Plain-text prompts have a string as their content
+	}
+	for _, block := range blocks {
+		if block.Type == "tool_result" {
+			return true
+		}
+	}
+	return false
+}
+
+// trackTurn folds a main-conversation message into the turn statistics
+// Claude Code writes each content block of a response as its own line with
+// the same message ID and usage, so repeated IDs only extend the turn
+func (p *Parser) trackTurn(role, messageID string, prompt bool, timestamp string, usage *UsageInfo) {
+	var ts time.Time
+	if timestamp != "" {
+		ts, _ = time.Parse(time.RFC3339Nano, timestamp)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch {
+	case prompt:
+		// Tool calls still waiting for a result were abandoned by the interruption
+		for _, id := range p.turn.pendingTools {
+			if tool, ok := p.toolActivity[id]; ok && tool.Status == "running" {
+				tool.Status = "interrupted"
+			}
+			p.turns.AbortedTools++
+			p.markInterruptedLocked()
+		}
+		p.turns.UserTurns++
+		p.turn = currentTurn{start: ts}
+	case role == "assistant":
+		if !p.turn.answered {
+			p.turn.answered = true
+			p.turns.AssistantTurns++
+		}
+		if messageID == "" || messageID != p.turn.messageID {
+			p.turn.messageID = messageID
+			p.turns.AssistantMessages++
+			if usage != nil {
+				tokens := usage.TotalInput() + usage.OutputTokens
+				p.turn.tokens += tokens
+				p.turns.Tokens += tokens
+			}
+		}
+		if !p.turn.start.IsZero() && !ts.IsZero() {
+			if d := ts.Sub(p.turn.start); d > p.turns.LongestTurn {
+				p.turns.LongestTurn = d
+				p.turn.longest = true
+			}
+		}
+		if p.turn.longest {
+			p.turns.LongestTurnTokens = p.turn.tokens
+		}
+	}
+}
+
+// trackToolCall records a main-conversation tool call awaiting its result
+func (p *Parser) trackToolCall(toolUseID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.turn.pendingTools = append(p.turn.pendingTools, toolUseID)
+}
+
+// trackToolResult records the result of a tool call; aborted results count
+// against the turn as an interruption
+func (p *Parser) trackToolResult(toolUseID string, aborted bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pending := p.turn.pendingTools
+	for i, id := range pending {
+		if id == toolUseID {
+			p.turn.pendingTools = append(pending[:i:i], pending[i+1:]...)
+			break
+		}
+	}
+	if aborted {
+		p.turns.AbortedTools++
+		p.markInterruptedLocked()
+	}
+}
+
+// trackInterrupt records that the user interrupted the current turn
+func (p *Parser) trackInterrupt() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.markInterruptedLocked()
+}
+
+// markInterruptedLocked counts the current turn as interrupted, once
+// Callers must hold p.mu
+func (p *Parser) markInterruptedLocked() {
+	if !p.turn.interrupted {
+		p.turn.interrupted = true
+		p.turns.Interrupted++
+	}
+}
+
+// GetTurnStats returns counts of user and assistant turns and their sizes
+func (p *Parser) GetTurnStats() TurnStats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.turns
+}
+
+// GetModelUsage returns token usage and cost per model, most expensive first
+func (p *Parser) GetModelUsage() []ModelUsage {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	result := make([]ModelUsage, 0, len(p.modelUsage))
+	for _, usage := range p.modelUsage {
+		result = append(result, *usage)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Cost != result[j].Cost {
+			return result[i].Cost > result[j].Cost
+		}
+		return result[i].Model < result[j].Model
+	})
+	return result
+}
+
+// ModelPricing returns the input and output price per million tokens (USD)
+// for a model name. These are approximate prices for Claude models; unknown
+// models are priced as Opus so costs are never underestimated
+func ModelPricing(model string) (inputPrice, outputPrice float64) {
 	const (
 		opusInputPrice    = 15.0
 		opusOutputPrice   = 75.0
@@ -683,24 +1099,20 @@ func (p *Parser) CalculateCost() float64 {
 		haikuOutputPrice  = 1.25
 	)
 
-	// Get model from latest assistant message
-	inputPrice, outputPrice := opusInputPrice, opusOutputPrice // default to Opus
-	if event := p.latestEvents[EventTypeAssistantMessage]; event != nil && event.Message != nil {
-		model := event.Message.Model
-		switch {
-		case strings.Contains(model, "opus"):
-			inputPrice, outputPrice = opusInputPrice, opusOutputPrice
-		case strings.Contains(model, "sonnet"):
-			inputPrice, outputPrice = sonnetInputPrice, sonnetOutputPrice
-		case strings.Contains(model, "haiku"):
-			inputPrice, outputPrice = haikuInputPrice, haikuOutputPrice
-		}
+	switch {
+	case strings.Contains(model, "sonnet"):
+		return sonnetInputPrice, sonnetOutputPrice
+	case strings.Contains(model, "haiku"):
+		return haikuInputPrice, haikuOutputPrice
+	default:
+		return opusInputPrice, opusOutputPrice
 	}
+}
 
-	inputCost := (float64(p.totalInputTokens) / 1_000_000) * inputPrice
-	outputCost := (float64(p.totalOutputTokens) / 1_000_000) * outputPrice
-
-	return inputCost + outputCost
+// EstimateCost returns the approximate USD cost of token usage for a model
+func EstimateCost(model string, inputTokens, outputTokens int) float64 {
+	inputPrice, outputPrice := ModelPricing(model)
+	return (float64(inputTokens)/1_000_000)*inputPrice + (float64(outputTokens)/1_000_000)*outputPrice
 }
 
 // GetDuration returns the formatted session duration
@@ -712,29 +1124,7 @@ func (p *Parser) GetDuration() string {
 		return "0s"
 	}
 
-	duration := time.Since(p.sessionStart)
-
-	// Format duration in human-readable format
-	switch {
-	case duration < time.Minute:
-		return fmt.Sprintf("%ds", int(duration.Seconds()))
-	case duration < time.Hour:
-		return fmt.Sprintf("%dm", int(duration.Minutes()))
-	case duration < 24*time.Hour:
-		hours := int(duration.Hours())
-		mins := int(duration.Minutes()) % 60
-		if mins > 0 {
-			return fmt.Sprintf("%dh%dm", hours, mins)
-		}
-		return fmt.Sprintf("%dh", hours)
-	default:
-		days := int(duration.Hours() / 24)
-		hours := int(duration.Hours()) % 24
-		if hours > 0 {
-			return fmt.Sprintf("%dd%dh", days, hours)
-		}
-		return fmt.Sprintf("%dd", days)
-	}
+	return humanize.Duration(time.Since(p.sessionStart), humanize.Elapsed)
 }
 
 // GetToolsByRecency returns tools aggregated by name, sorted by most recently used
@@ -745,7 +1135,8 @@ func (p *Parser) GetToolsByRecency(maxTools int) []ToolUsage {
 	// Aggregate tools by name
 	toolMap := make(map[string]*ToolUsage)
 
-	for _, tool := range p.toolActivity {
+	for _, key := range render.Keys(p.toolActivity) {
+		tool := p.toolActivity[key]
 		if tool.Name == "" {
 			continue
 		}
@@ -764,15 +1155,9 @@ func (p *Parser) GetToolsByRecency(maxTools int) []ToolUsage {
 		}
 	}
 
-	// Convert to slice and sort by recency
-	result := make([]ToolUsage, 0, len(toolMap))
-	for _, usage := range toolMap {
-		result = append(result, *usage)
-	}
-
 	// Sort by last used time (most recent first)
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].LastUsed.After(result[j].LastUsed)
+	result := render.Values(toolMap, func(a, b *ToolUsage) int {
+		return render.Newest(a.LastUsed, b.LastUsed)
 	})
 
 	// Limit to maxTools
@@ -780,6 +1165,15 @@ func (p *Parser) GetToolsByRecency(maxTools int) []ToolUsage {
 		result = result[:maxTools]
 	}
 
+	return derefUsage(result)
+}
+
+// derefUsage copies tool usage out of an aggregation
+func derefUsage(usage []*ToolUsage) []ToolUsage {
+	result := make([]ToolUsage, len(usage))
+	for i, u := range usage {
+		result[i] = *u
+	}
 	return result
 }
 
@@ -790,6 +1184,27 @@ func (p *Parser) GetTranscriptPath() string {
 	return p.transcriptPath
 }
 
+// GetRecentToolCalls returns up to limit tool calls, most recent first;
+// limit <= 0 returns all the parser still holds
+func (p *Parser) GetRecentToolCalls(limit int) []ToolInfo {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	calls := render.Values(p.toolActivity, func(a, b *ToolInfo) int {
+		return render.Newest(a.LastUsed, b.LastUsed)
+	})
+	var result []ToolInfo
+	for _, call := range calls {
+		if limit > 0 && len(result) == limit {
+			break
+		}
+		if call.Name != "" {
+			result = append(result, *call)
+		}
+	}
+	return result
+}
+
 // GetToolsByStatus returns tools separated by running and completed status
 func (p *Parser) GetToolsByStatus(maxRunning, maxCompleted int) (running, completed []ToolUsage) {
 	p.mu.RLock()
@@ -798,7 +1213,9 @@ func (p *Parser) GetToolsByStatus(maxRunning, maxCompleted int) (running, comple
 	runningMap := make(map[string]*ToolUsage)
 	completedMap := make(map[string]*ToolUsage)
 
-	for _, tool := range p.toolActivity {
+	// In key order, so the target shown among equally recent calls is stable
+	for _, key := range render.Keys(p.toolActivity) {
+		tool := p.toolActivity[key]
 		if tool.Name == "" {
 			continue
 		}
@@ -813,6 +1230,7 @@ func (p *Parser) GetToolsByStatus(maxRunning, maxCompleted int) (running, comple
 			existing.Count++
 			if tool.LastUsed.After(existing.LastUsed) {
 				existing.LastUsed = tool.LastUsed
+				existing.Target = tool.Target
 			}
 		} else {
 			targetMap[tool.Name] = &ToolUsage{
@@ -825,34 +1243,26 @@ func (p *Parser) GetToolsByStatus(maxRunning, maxCompleted int) (running, comple
 		}
 	}
 
-	// Convert running to slice and sort by recency
-	runningResult := make([]ToolUsage, 0, len(runningMap))
-	for _, usage := range runningMap {
-		runningResult = append(runningResult, *usage)
-	}
-	sort.Slice(runningResult, func(i, j int) bool {
-		return runningResult[i].LastUsed.After(runningResult[j].LastUsed)
+	// Running tools by recency
+	runningResult := render.Values(runningMap, func(a, b *ToolUsage) int {
+		return render.Newest(a.LastUsed, b.LastUsed)
 	})
 	if maxRunning > 0 && len(runningResult) > maxRunning {
 		runningResult = runningResult[:maxRunning]
 	}
 
-	// Convert completed to slice and sort by frequency (count)
-	completedResult := make([]ToolUsage, 0, len(completedMap))
-	for _, usage := range completedMap {
-		completedResult = append(completedResult, *usage)
-	}
-	sort.Slice(completedResult, func(i, j int) bool {
-		if completedResult[i].Count != completedResult[j].Count {
-			return completedResult[i].Count > completedResult[j].Count
+	// Completed tools by frequency (count), then recency
+	completedResult := render.Values(completedMap, func(a, b *ToolUsage) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
 		}
-		return completedResult[i].LastUsed.After(completedResult[j].LastUsed)
+		return render.Newest(a.LastUsed, b.LastUsed)
 	})
 	if maxCompleted > 0 && len(completedResult) > maxCompleted {
 		completedResult = completedResult[:maxCompleted]
 	}
 
-	return runningResult, completedResult
+	return derefUsage(runningResult), derefUsage(completedResult)
 }
 
 // extractToolTarget extracts the target (file path, pattern, command) from tool input
@@ -860,78 +1270,20 @@ func extractToolTarget(toolName string, input json.RawMessage) string {
 	if len(input) == 0 {
 		return ""
 	}
+	return ToolTarget(toolName, decodeToolInput(input))
+}
 
+// decodeToolInput decodes a tool call's input, which is either the input
+// itself or, in the legacy format, nested as {"name":"ToolName","input":{...}}
+func decodeToolInput(input json.RawMessage) map[string]interface{} {
 	var inputData map[string]interface{}
 	if err := json.Unmarshal(input, &inputData); err != nil {
-		return ""
+		return nil
 	}
-
-	// The tool_use JSON has nested structure: {"name":"ToolName","input":{...}}
-	// Extract the actual input data
-	var actualInput map[string]interface{}
 	if inputField, ok := inputData["input"].(map[string]interface{}); ok {
-		actualInput = inputField
-	} else {
-		// Fallback: try to use the data directly (for compatibility)
-		actualInput = inputData
-	}
-
-	switch toolName {
-	case "Read", "Write", "Edit":
-		// Extract file_path
-		if path, ok := actualInput["file_path"].(string); ok {
-			return truncateTarget(path, 20)
-		}
-		if path, ok := actualInput["path"].(string); ok {
-			return truncateTarget(path, 20)
-		}
-	case "Glob":
-		if pattern, ok := actualInput["pattern"].(string); ok {
-			return truncateTarget(pattern, 20)
-		}
-	case "Grep":
-		if pattern, ok := actualInput["pattern"].(string); ok {
-			return truncateTarget(pattern, 20)
-		}
-	case "Bash":
-		if cmd, ok := actualInput["command"].(string); ok {
-			// Truncate command to 30 characters
-			return truncateTarget(cmd, 30)
-		}
+		return inputField
 	}
-
-	return ""
-}
-
-// truncateTarget truncates a target string to max characters, with smart path handling
-func truncateTarget(target string, maxLen int) string {
-	if len(target) <= maxLen {
-		return target
-	}
-
-	// Convert backslashes to forward slashes (for Windows paths)
-	target = strings.ReplaceAll(target, "\\", "/")
-
-	// If there are no path separators, just truncate
-	if !strings.Contains(target, "/") {
-		if len(target) > maxLen {
-			return target[:maxLen-3] + "..."
-		}
-		return target
-	}
-
-	// Extract just the filename if it fits
-	parts := strings.Split(target, "/")
-	filename := parts[len(parts)-1]
-	if len(filename) <= maxLen {
-		return filename
-	}
-
-	// Truncate filename
-	if len(filename) > maxLen {
-		return filename[:maxLen-3] + "..."
-	}
-	return filename
+	return inputData
 }
 
 // trackError adds an error to the error list
@@ -955,6 +1307,12 @@ func (p *Parser) trackError(timestamp, toolName, message, severity string) {
 		Message:   message,
 		Severity:  severity,
 	})
+	p.errorsTotal++
+
+	// Keep only the most recent errors; errorsTotal still counts all of them
+	if len(p.errors) > MAX_TRACKED_ERRORS {
+		p.errors = append([]*ErrorInfo(nil), p.errors[len(p.errors)-MAX_TRACKED_ERRORS:]...)
+	}
 }
 
 // GetRecentErrors returns the most recent errors (up to limit)
@@ -982,7 +1340,7 @@ func (p *Parser) GetErrorCount(lastMinutes int) (total, recent int) {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
-	total = len(p.errors)
+	total = p.errorsTotal
 	if lastMinutes <= 0 {
 		return total, 0
 	}
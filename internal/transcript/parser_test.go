@@ -2,9 +2,13 @@ package transcript
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestParseEventType(t *testing.T) {
@@ -240,6 +244,37 @@ func TestParser_ContextWindow(t *testing.T) {
 	}
 }
 
+func TestParser_ContextWindow_LongContext(t *testing.T) {
+	ctx := context.Background()
+	p := NewParser("test.jsonl")
+
+	input := `{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"hi"}],"usage":{"input_tokens":10,"cache_read_input_tokens":250000,"output_tokens":5}}}` + "\n"
+	if err := p.ParseFromReader(ctx, strings.NewReader(input)); err != nil {
+		t.Fatalf("ParseFromReader() error = %v", err)
+	}
+
+	cw := p.GetContextWindow()
+	if cw == nil || cw.ContextWindowSize != LONG_CONTEXT_WINDOW || !cw.ExceedsStandardWindow() {
+		t.Fatalf("expected the 1M window past 200k tokens, got %+v", cw)
+	}
+	if pct := p.GetContextPercentage(); pct != 37 {
+		t.Errorf("GetContextPercentage() = %d, want 37", pct)
+	}
+}
+
+func TestIsLongContextModel(t *testing.T) {
+	for model, want := range map[string]bool{
+		"claude-sonnet-4-5-20250929[1m]": true,
+		"Sonnet 4.5 (1M context)":        true,
+		"claude-sonnet-4-5-20250929":     false,
+		"Opus 4.1":                       false,
+	} {
+		if got := IsLongContextModel(model); got != want {
+			t.Errorf("IsLongContextModel(%q) = %v, want %v", model, got, want)
+		}
+	}
+}
+
 func TestParser_SessionTracking(t *testing.T) {
 	ctx := context.Background()
 	p := NewParser("test.jsonl")
@@ -375,6 +410,304 @@ func TestParser_CalculateCost_ModelDetection(t *testing.T) {
 	}
 }
 
+func TestParser_ModelUsage_MixedModels(t *testing.T) {
+	ctx := context.Background()
+	p := NewParser("test.jsonl")
+
+	input := strings.Join([]string{
+		`{"type":"assistant","timestamp":"2026-01-07T12:00:00Z","message":{"role":"assistant","model":"claude-sonnet-4-5-20250929","content":[{"type":"text","text":"hi"}],"usage":{"input_tokens":1000000,"output_tokens":100000}}}`,
+		`{"type":"assistant","timestamp":"2026-01-07T12:01:00Z","message":{"role":"assistant","model":"claude-haiku-4-5-20251001","content":[{"type":"text","text":"bg"}],"usage":{"input_tokens":2000000,"output_tokens":0}}}`,
+		`{"type":"assistant","timestamp":"2026-01-07T12:02:00Z","message":{"role":"assistant","model":"claude-sonnet-4-5-20250929","content":[{"type":"text","text":"again"}],"usage":{"input_tokens":1000000,"output_tokens":100000}}}`,
+		`{"type":"assistant","timestamp":"2026-01-07T12:03:00Z","message":{"role":"assistant","model":"<synthetic>","content":[{"type":"text","text":"No response requested."}],"usage":{"input_tokens":0,"output_tokens":0}}}`,
+	}, "\n") + "\n"
+
+	if err := p.ParseFromReader(ctx, strings.NewReader(input)); err != nil {
+		t.Fatalf("ParseFromReader() error = %v", err)
+	}
+
+	usage := p.GetModelUsage()
+	if len(usage) != 2 {
+		t.Fatalf("expected 2 models, got %+v", usage)
+	}
+	// Sonnet: 2M input * $3/M + 200K output * $15/M = $9; Haiku: 2M input * $0.25/M = $0.50
+	if usage[0].Model != "claude-sonnet-4-5-20250929" || usage[0].Messages != 2 || usage[0].Cost < 8.99 || usage[0].Cost > 9.01 {
+		t.Errorf("unexpected sonnet usage: %+v", usage[0])
+	}
+	if usage[1].Model != "claude-haiku-4-5-20251001" || usage[1].InputTokens != 2000000 || usage[1].Cost < 0.49 || usage[1].Cost > 0.51 {
+		t.Errorf("unexpected haiku usage: %+v", usage[1])
+	}
+	if cost := p.CalculateCost(); cost < 9.49 || cost > 9.51 {
+		t.Errorf("CalculateCost() = %v, want 9.50", cost)
+	}
+
+	// Parsing again starts over instead of adding to the previous totals
+	if err := p.ParseFromReader(ctx, strings.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+	if cost := p.CalculateCost(); cost < 9.49 || cost > 9.51 {
+		t.Errorf("CalculateCost() after reparse = %v, want 9.50", cost)
+	}
+}
+
+func TestParser_TurnStats(t *testing.T) {
+	ctx := context.Background()
+	p := NewParser("test.jsonl")
+
+	input := strings.Join([]string{
+		// Turn 1: one response split over two lines, a tool call and a final answer, 90s
+		`{"type":"user","timestamp":"2026-01-07T12:00:00Z","message":{"role":"user","content":"fix the build"}}`,
+		`{"type":"assistant","timestamp":"2026-01-07T12:00:10Z","message":{"id":"msg_1","role":"assistant","content":[{"type":"text","text":"Looking"}],"usage":{"input_tokens":100,"output_tokens":50}}}`,
+		`{"type":"assistant","timestamp":"2026-01-07T12:00:11Z","message":{"id":"msg_1","role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"make"}}],"usage":{"input_tokens":100,"output_tokens":50}}}`,
+		`{"type":"user","timestamp":"2026-01-07T12:00:40Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}`,
+		`{"type":"assistant","timestamp":"2026-01-07T12:01:30Z","message":{"id":"msg_2","role":"assistant","content":[{"type":"text","text":"Fixed"}],"usage":{"input_tokens":200,"cache_read_input_tokens":50,"output_tokens":50}}}`,
+		// Injected command output is not a prompt
+		`{"type":"user","isMeta":true,"timestamp":"2026-01-07T12:02:00Z","message":{"role":"user","content":"<local-command-stdout>done</local-command-stdout>"}}`,
+		// Subagent messages belong to the Task call, not to the conversation
+		`{"type":"assistant","isSidechain":true,"timestamp":"2026-01-07T12:02:30Z","message":{"id":"msg_s","role":"assistant","content":[{"type":"text","text":"sub"}],"usage":{"input_tokens":999,"output_tokens":999}}}`,
+		// Turn 2: 5s
+		`{"type":"user","timestamp":"2026-01-07T12:05:00Z","message":{"role":"user","content":[{"type":"text","text":"thanks"}]}}`,
+		`{"type":"assistant","timestamp":"2026-01-07T12:05:05Z","message":{"id":"msg_3","role":"assistant","content":[{"type":"text","text":"np"}],"usage":{"input_tokens":40,"output_tokens":10}}}`,
+		// Turn 3: interrupted before any response
+		`{"type":"user","timestamp":"2026-01-07T12:06:00Z","message":{"role":"user","content":"and the tests?"}}`,
+	}, "\n") + "\n"
+
+	if err := p.ParseFromReader(ctx, strings.NewReader(input)); err != nil {
+		t.Fatalf("ParseFromReader() error = %v", err)
+	}
+
+	stats := p.GetTurnStats()
+	want := TurnStats{
+		UserTurns:         3,
+		AssistantTurns:    2,
+		AssistantMessages: 3,
+		Tokens:            150 + 300 + 50,
+		LongestTurn:       90 * time.Second,
+		LongestTurnTokens: 450,
+	}
+	if stats != want {
+		t.Errorf("GetTurnStats() = %+v, want %+v", stats, want)
+	}
+	if avg := stats.AverageTokensPerTurn(); avg != 250 {
+		t.Errorf("AverageTokensPerTurn() = %d, want 250", avg)
+	}
+}
+
+func TestParser_Interruptions(t *testing.T) {
+	ctx := context.Background()
+	p := NewParser("test.jsonl")
+
+	input := strings.Join([]string{
+		// Turn 1: the user rejects an edit, then Claude Code notes the interruption
+		`{"type":"user","timestamp":"2026-01-07T12:00:00Z","message":{"role":"user","content":"refactor it"}}`,
+		`{"type":"assistant","timestamp":"2026-01-07T12:00:05Z","message":{"id":"msg_1","role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Edit","input":{"file_path":"/a.go"}}],"stop_reason":"tool_use"}}`,
+		`{"type":"user","timestamp":"2026-01-07T12:00:09Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","is_error":true,"content":"The user doesn't want to proceed with this tool use. The tool use was rejected."}]}}`,
+		`{"type":"user","timestamp":"2026-01-07T12:00:09Z","message":{"role":"user","content":[{"type":"text","text":"[Request interrupted by user for tool use]"}]}}`,
+		// Turn 2: a failing command is an error, not an interruption
+		`{"type":"user","timestamp":"2026-01-07T12:01:00Z","message":{"role":"user","content":"run the tests"}}`,
+		`{"type":"assistant","timestamp":"2026-01-07T12:01:05Z","message":{"id":"msg_2","role":"assistant","content":[{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"go test"}}]}}`,
+		`{"type":"user","timestamp":"2026-01-07T12:01:20Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t2","is_error":true,"content":[{"type":"text","text":"exit status 1"}]}]}}`,
+		// Turn 3: two calls are abandoned when the user moves on without results
+		`{"type":"user","timestamp":"2026-01-07T12:02:00Z","message":{"role":"user","content":"check the docs"}}`,
+		`{"type":"assistant","timestamp":"2026-01-07T12:02:05Z","message":{"id":"msg_3","role":"assistant","content":[{"type":"tool_use","id":"t3","name":"Read","input":{"file_path":"/README.md"}}]}}`,
+		`{"type":"assistant","timestamp":"2026-01-07T12:02:05Z","message":{"id":"msg_3","role":"assistant","content":[{"type":"tool_use","id":"t4","name":"Grep","input":{"pattern":"TODO"}}]}}`,
+		// Turn 4: still running
+		`{"type":"user","timestamp":"2026-01-07T12:03:00Z","message":{"role":"user","content":"never mind"}}`,
+		`{"type":"assistant","timestamp":"2026-01-07T12:03:05Z","message":{"id":"msg_4","role":"assistant","content":[{"type":"tool_use","id":"t5","name":"Bash","input":{"command":"ls"}}]}}`,
+	}, "\n") + "\n"
+
+	if err := p.ParseFromReader(ctx, strings.NewReader(input)); err != nil {
+		t.Fatalf("ParseFromReader() error = %v", err)
+	}
+
+	stats := p.GetTurnStats()
+	if stats.UserTurns != 4 {
+		t.Errorf("UserTurns = %d, want 4; interruption notices are not prompts", stats.UserTurns)
+	}
+	if stats.Interrupted != 2 || stats.AbortedTools != 3 {
+		t.Errorf("Interrupted = %d, AbortedTools = %d, want 2 and 3", stats.Interrupted, stats.AbortedTools)
+	}
+
+	tools := p.GetToolActivity()
+	for id, want := range map[string]string{"t1": "interrupted", "t2": "error", "t3": "interrupted", "t4": "interrupted", "t5": "running"} {
+		if got := tools[id].Status; got != want {
+			t.Errorf("tool %s status = %q, want %q", id, got, want)
+		}
+	}
+	if total, _ := p.GetErrorCount(5); total != 1 {
+		t.Errorf("GetErrorCount() total = %d, want 1; rejected calls are not errors", total)
+	}
+}
+
+func TestContentBlock_StringContent(t *testing.T) {
+	var block ContentBlock
+	if err := json.Unmarshal([]byte(`{"type":"tool_result","tool_use_id":"t1","content":"ok"}`), &block); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if block.ContentStr != "ok" || block.ToolUseID != "t1" || block.PlainText() != "ok" {
+		t.Errorf("unexpected block: %+v", block)
+	}
+
+	if err := json.Unmarshal([]byte(`{"type":"tool_result","content":[{"type":"text","text":"a"},{"type":"text","text":"b"}]}`), &block); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if block.ContentStr != "" || len(block.Content) != 2 || block.PlainText() != "ab" {
+		t.Errorf("unexpected block: %+v", block)
+	}
+}
+
+func TestClaudeCodeTranscriptLine_Lenient(t *testing.T) {
+	tests := []struct {
+		name        string
+		line        string
+		wantText    string
+		wantBlocks  int
+		wantSkipped string
+	}{
+		{name: "string content", line: `{"type":"user","message":{"role":"user","content":"fix it"}}`, wantText: "fix it", wantBlocks: 1},
+		{name: "single block", line: `{"type":"user","message":{"role":"user","content":{"type":"text","text":"fix it"}}}`, wantText: "fix it", wantBlocks: 1},
+		{name: "string block", line: `{"type":"user","message":{"role":"user","content":["fix it"]}}`, wantText: "fix it", wantBlocks: 1},
+		{name: "string tool result", line: `{"type":"user","toolUseResult":"Error: denied","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"denied","is_error":true}]}}`, wantText: "denied", wantBlocks: 1},
+		{name: "bad block field", line: `{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","is_error":"yes"}]}}`, wantBlocks: 1, wantSkipped: "message.content.is_error"},
+		{name: "bad usage", line: `{"type":"assistant","message":{"role":"assistant","usage":{"input_tokens":"12"},"content":[{"type":"text","text":"hi"}]}}`, wantText: "hi", wantBlocks: 1, wantSkipped: "message.usage.input_tokens"},
+		{name: "bad block", line: `{"type":"user","message":{"role":"user","content":[42]}}`, wantBlocks: 1, wantSkipped: "message.content"},
+		{name: "bad message", line: `{"type":"user","timestamp":"2026-01-11T03:00:00Z","message":"hi"}`, wantSkipped: "message"},
+		{name: "bad timestamp", line: `{"type":"user","timestamp":12,"message":{"role":"user","content":"hi"}}`, wantText: "hi", wantBlocks: 1, wantSkipped: "timestamp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var line ClaudeCodeTranscriptLine
+			if err := json.Unmarshal([]byte(tt.line), &line); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			var text string
+			var blocks int
+			if line.Message != nil {
+				blocks = len(line.Message.Content)
+				for i := range line.Message.Content {
+					text += line.Message.Content[i].PlainText()
+				}
+			}
+			if text != tt.wantText || blocks != tt.wantBlocks {
+				t.Errorf("content = %d blocks %q, want %d blocks %q", blocks, text, tt.wantBlocks, tt.wantText)
+			}
+			if got := strings.Join(line.skipped, ","); got != tt.wantSkipped {
+				t.Errorf("skipped = %q, want %q", got, tt.wantSkipped)
+			}
+		})
+	}
+
+	var line ClaudeCodeTranscriptLine
+	if err := json.Unmarshal([]byte(`{"type":"user","message":{"content":[`), &line); err == nil {
+		t.Error("Unmarshal() of a truncated line succeeded")
+	}
+}
+
+func TestParser_FieldErrors(t *testing.T) {
+	content := `{"type":"user","timestamp":"2026-01-11T03:00:00Z","message":{"role":"user","content":"fix the build"}}
+{"type":"assistant","timestamp":"2026-01-11T03:00:01Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"make"}},{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"make test"}}]}}
+{"type":"user","timestamp":"2026-01-11T03:00:02Z","toolUseResult":"Error: exit status 2","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"exit status 2","is_error":true}]}}
+{"type":"user","timestamp":"2026-01-11T03:00:03Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t2","content":"ok","is_error":"false"}]}}
+`
+	p := NewParser("")
+	if err := p.ParseFromReader(context.Background(), strings.NewReader(content)); err != nil {
+		t.Fatal(err)
+	}
+
+	state := p.GetState()
+	if state.ErrorsEncountered != 0 || state.LinesParsed != 4 {
+		t.Errorf("state = %+v, want 4 lines without errors", state)
+	}
+	if len(state.FieldErrors) != 1 || state.FieldErrors["message.content.is_error"] != 1 {
+		t.Errorf("FieldErrors = %v, want message.content.is_error once", state.FieldErrors)
+	}
+	tools := p.GetToolActivity()
+	if tools["t1"].Status != "error" || tools["t2"].Status != "completed" {
+		t.Errorf("tool statuses = %s, %s, want error, completed", tools["t1"].Status, tools["t2"].Status)
+	}
+	if turns := p.GetTurnStats(); turns.UserTurns != 1 {
+		t.Errorf("UserTurns = %d, want the string prompt counted", turns.UserTurns)
+	}
+	if !p.GetSessionStart().Equal(time.Date(2026, 1, 11, 3, 0, 0, 0, time.UTC)) {
+		t.Errorf("GetSessionStart() = %v", p.GetSessionStart())
+	}
+}
+
+func TestParser_PartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	complete := `{"type":"assistant","timestamp":"2026-01-11T03:00:01Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"make"}}]}}` + "\n"
+	result := `{"type":"user","timestamp":"2026-01-11T03:00:02Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}` + "\n"
+	half := len(result) / 2
+	if err := os.WriteFile(path, []byte(complete+result[:half]), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewParser(path)
+	if err := p.Parse(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if state := p.GetState(); state.ErrorsEncountered != 0 || state.LinesParsed != 1 || !state.PartialLine {
+		t.Errorf("state mid-write = %+v, want 1 line, no errors and a partial line", state)
+	}
+	if tool := p.GetToolActivity()["t1"]; tool == nil || tool.Status != "running" {
+		t.Errorf("tool mid-write = %+v, want it running", tool)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString(result[half:]); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	if err := p.Parse(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if state := p.GetState(); state.ErrorsEncountered != 0 || state.LinesParsed != 2 || state.PartialLine {
+		t.Errorf("state once written = %+v, want 2 lines and no partial line", state)
+	}
+	if tool := p.GetToolActivity()["t1"]; tool == nil || tool.Status != "completed" {
+		t.Errorf("tool once written = %+v, want it completed", tool)
+	}
+
+	// A broken line that a newline ends is an error, as is a last line of
+	// complete JSON that fails to parse
+	p = NewParser("")
+	if err := p.ParseFromReader(context.Background(), strings.NewReader("{broken\n"+complete+"[1]")); err != nil {
+		t.Fatal(err)
+	}
+	if state := p.GetState(); state.ErrorsEncountered != 2 || state.PartialLine {
+		t.Errorf("state = %+v, want 2 errors", state)
+	}
+}
+
+func TestParser_OversizedLine(t *testing.T) {
+	t.Cleanup(func() { SetLineLimit(defaultLineLimit) })
+	SetLineLimit(LineLimit{MaxBytes: 64 * 1024})
+
+	huge := `{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t0","content":"` + strings.Repeat("QUJD", 64*1024) + `"}]}}` + "\n"
+	content := `{"type":"assistant","message":{"role":"assistant","usage":{"input_tokens":100,"output_tokens":10},"content":[{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"/p/a.go"}}]}}` + "\n" +
+		huge +
+		`{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}` + "\n"
+
+	p := NewParser("")
+	if err := p.ParseFromReader(context.Background(), strings.NewReader(content)); err != nil {
+		t.Fatalf("ParseFromReader() error = %v", err)
+	}
+	if state := p.GetState(); state.OversizedLines != 1 || state.LinesParsed != 2 || state.ErrorsEncountered != 0 {
+		t.Errorf("state = %+v, want the huge line skipped and the others parsed", state)
+	}
+	if tool := p.GetToolActivity()["t1"]; tool == nil || tool.Status != "completed" {
+		t.Errorf("tool after the huge line = %+v, want it completed", tool)
+	}
+
+	SetLineLimit(LineLimit{MaxBytes: 64 * 1024, Oversized: OversizedAbort})
+	if err := p.ParseFromReader(context.Background(), strings.NewReader(content)); err == nil {
+		t.Error("ParseFromReader() with oversized lines aborting succeeded")
+	}
+}
+
 func TestParser_GetDuration(t *testing.T) {
 	ctx := context.Background()
 	p := NewParser("test.jsonl")
@@ -395,3 +728,348 @@ func TestParser_GetDuration(t *testing.T) {
 		t.Error("GetDuration() returned '0s', expected some duration")
 	}
 }
+
+func TestParser_ToolRetention(t *testing.T) {
+	ctx := context.Background()
+	p := NewParser("test.jsonl")
+	p.SetMaxToolsPerName(10)
+
+	var b strings.Builder
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(&b, `{"type":"assistant","timestamp":"2026-01-11T03:%02d:%02d.000Z","message":{"content":[{"type":"tool_use","id":"read_%d","name":"Read","input":{"file_path":"/f%d.go"}}]}}`+"\n", i/60, i%60, i, i)
+		fmt.Fprintf(&b, `{"type":"user","timestamp":"2026-01-11T03:%02d:%02d.000Z","message":{"content":[{"type":"tool_result","tool_use_id":"read_%d","is_error":%v}]}}`+"\n", i/60, i%60, i, i%10 == 0)
+	}
+	b.WriteString(`{"type":"assistant","message":{"content":[{"type":"tool_use","id":"bash_1","name":"Bash","input":{"command":"ls"}}]}}` + "\n")
+
+	if err := p.ParseFromReader(ctx, strings.NewReader(b.String())); err != nil {
+		t.Fatalf("ParseFromReader() error = %v", err)
+	}
+
+	stats := p.MemoryStats()
+	if stats.ToolEntries != 11 {
+		t.Errorf("ToolEntries = %d, want 11 (10 Read + 1 Bash)", stats.ToolEntries)
+	}
+	if stats.EvictedTools != 90 {
+		t.Errorf("EvictedTools = %d, want 90", stats.EvictedTools)
+	}
+
+	tools := p.GetToolActivity()
+	if _, ok := tools["read_99"]; !ok {
+		t.Error("most recent Read entry should be retained")
+	}
+	if _, ok := tools["read_0"]; ok {
+		t.Error("oldest Read entry should be evicted")
+	}
+
+	toolStats := p.GetToolStats()
+	if got := toolStats["Read"]; got.Calls != 100 || got.Errors != 10 {
+		t.Errorf("Read stats = %+v, want 100 calls / 10 errors", got)
+	}
+
+	total, _ := p.GetErrorCount(0)
+	if total != 10 {
+		t.Errorf("GetErrorCount() total = %d, want 10", total)
+	}
+}
+
+func TestParser_ToolOrder(t *testing.T) {
+	p := NewParser("test.jsonl")
+	var b strings.Builder
+	// Tools completed at the same time, in an order unrelated to their
+	// names, and a later Read
+	for i, name := range []string{"Read", "Bash", "Grep", "Edit", "Read"} {
+		second := i / 4
+		fmt.Fprintf(&b, `{"type":"assistant","timestamp":"2026-01-11T03:00:0%d.000Z","message":{"content":[{"type":"tool_use","id":"t%d","name":%q,"input":{"file_path":"/f%d.go"}}]}}`+"\n", second, i, name, i)
+		fmt.Fprintf(&b, `{"type":"user","timestamp":"2026-01-11T03:00:0%d.000Z","message":{"content":[{"type":"tool_result","tool_use_id":"t%d"}]}}`+"\n", second, i)
+	}
+	if err := p.ParseFromReader(context.Background(), strings.NewReader(b.String())); err != nil {
+		t.Fatalf("ParseFromReader() error = %v", err)
+	}
+
+	for i := 0; i < 20; i++ {
+		_, completed := p.GetToolsByStatus(0, 0)
+		var names []string
+		for _, tool := range completed {
+			names = append(names, tool.Name)
+		}
+		if got := strings.Join(names, ","); got != "Read,Bash,Edit,Grep" {
+			t.Fatalf("completed tools = %s, want Read,Bash,Edit,Grep", got)
+		}
+		if completed[0].Target != "/f4.go" {
+			t.Errorf("Read target = %q, want the latest call's /f4.go", completed[0].Target)
+		}
+	}
+}
+
+func TestParser_ErrorRetention(t *testing.T) {
+	p := NewParser("test.jsonl")
+	for i := 0; i < MAX_TRACKED_ERRORS+50; i++ {
+		p.trackError("", "Bash", fmt.Sprintf("error %d", i), "error")
+	}
+
+	total, _ := p.GetErrorCount(0)
+	if total != MAX_TRACKED_ERRORS+50 {
+		t.Errorf("total = %d, want %d", total, MAX_TRACKED_ERRORS+50)
+	}
+	recent := p.GetRecentErrors(0)
+	if len(recent) != MAX_TRACKED_ERRORS {
+		t.Errorf("retained %d errors, want %d", len(recent), MAX_TRACKED_ERRORS)
+	}
+	if last := recent[len(recent)-1].Message; last != fmt.Sprintf("error %d", MAX_TRACKED_ERRORS+49) {
+		t.Errorf("newest error = %q", last)
+	}
+}
+
+func TestParser_RotatedTranscript(t *testing.T) {
+	message := func(id string, tokens int) string {
+		return fmt.Sprintf(`{"type":"assistant","timestamp":"2026-01-07T12:00:00Z","message":{"id":%q,"role":"assistant","model":"claude-sonnet-4-5-20250929","content":[{"type":"text","text":"hi"}],"usage":{"input_tokens":%d,"output_tokens":10}}}`, id, tokens) + "\n"
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "session.jsonl")
+	write := func(name, content string, modTime time.Time) {
+		t.Helper()
+		target := filepath.Join(dir, name)
+		if err := os.WriteFile(target, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(target, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	parse := func(p *Parser) {
+		t.Helper()
+		if err := p.Parse(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	start := time.Now().Add(-time.Hour)
+	write("session.jsonl", message("a", 100)+message("b", 200), start)
+	p := NewParser(path)
+	parse(p)
+	if input, _ := p.GetTotalTokens(); input != 300 {
+		t.Fatalf("input tokens = %d, want 300", input)
+	}
+
+	// Replaced by a file of the same size and an older modification time
+	write("rotated.jsonl", message("c", 400)+message("d", 500), start.Add(-time.Minute))
+	if err := os.Rename(filepath.Join(dir, "rotated.jsonl"), path); err != nil {
+		t.Fatal(err)
+	}
+	parse(p)
+	if input, _ := p.GetTotalTokens(); input != 1200 {
+		t.Errorf("input tokens after rotation = %d, want 1200", input)
+	}
+
+	// Truncated in place to its first message, then appended to: the
+	// surviving message is not counted twice, and the one cut off is kept
+	if err := os.Truncate(path, int64(len(message("c", 400)))); err != nil {
+		t.Fatal(err)
+	}
+	parse(p)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(message("e", 1000)); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	parse(p)
+	if input, output := p.GetTotalTokens(); input != 2200 || output != 50 {
+		t.Errorf("tokens after truncation = %d in, %d out, want 2200 in, 50 out", input, output)
+	}
+	if got := p.GetTurnStats().AssistantMessages; got != 5 {
+		t.Errorf("AssistantMessages = %d, want 5", got)
+	}
+	if got := p.Rewrites(); got != 2 {
+		t.Errorf("Rewrites() = %d, want 2", got)
+	}
+
+	// Growing as usual carries nothing more
+	f, err = os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(message("f", 1)); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	parse(p)
+	if input, _ := p.GetTotalTokens(); input != 2201 {
+		t.Errorf("input tokens after append = %d, want 2201", input)
+	}
+}
+
+func TestParser_TruncatedTranscript(t *testing.T) {
+	message := func(id string, tokens int) string {
+		return fmt.Sprintf(`{"type":"assistant","timestamp":"2026-01-07T12:00:00Z","message":{"id":%q,"role":"assistant","model":"claude-sonnet-4-5-20250929","content":[{"type":"text","text":"hi"}],"usage":{"input_tokens":%d,"output_tokens":10}}}`, id, tokens) + "\n"
+	}
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	if err := os.WriteFile(path, []byte(message("a", 100)+message("b", 200)+message("c", 400)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p := NewParser(path)
+	parse := func() {
+		t.Helper()
+		if err := p.Parse(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	parse()
+
+	// Cut back in place to its first message: the messages cut off stay
+	// counted, and the one left is not counted again
+	if err := os.Truncate(path, int64(len(message("a", 100)))); err != nil {
+		t.Fatal(err)
+	}
+	parse()
+	if input, output := p.GetTotalTokens(); input != 700 || output != 30 {
+		t.Errorf("tokens after truncation = %d in, %d out, want 700 in, 30 out", input, output)
+	}
+	if got := p.GetTurnStats().AssistantMessages; got != 3 {
+		t.Errorf("AssistantMessages = %d, want 3", got)
+	}
+	if got := p.Rewrites(); got != 1 {
+		t.Errorf("Rewrites() = %d, want 1", got)
+	}
+
+	// What is written after it counts as usual
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(message("d", 50)); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	parse()
+	if input, _ := p.GetTotalTokens(); input != 750 {
+		t.Errorf("input tokens after append = %d, want 750", input)
+	}
+	if got := p.GetTurnStats().AssistantMessages; got != 4 {
+		t.Errorf("AssistantMessages after append = %d, want 4", got)
+	}
+}
+
+func TestParser_TouchedFiles(t *testing.T) {
+	edit := func(id, tool, field, path, ts string, failed bool) string {
+		return fmt.Sprintf(`{"type":"assistant","timestamp":%q,"message":{"role":"assistant","content":[{"type":"tool_use","id":%q,"name":%q,"input":{%q:%q}}]}}`, ts, id, tool, field, path) + "\n" +
+			fmt.Sprintf(`{"type":"user","timestamp":%q,"message":{"role":"user","content":[{"type":"tool_result","tool_use_id":%q,"is_error":%v}]}}`, ts, id, failed) + "\n"
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "session.jsonl")
+	content := edit("e1", "Edit", "file_path", "/src/login.go", "2026-01-11T03:00:00Z", false) +
+		edit("e2", "Write", "file_path", "/src/routes.go", "2026-01-11T03:01:00Z", false) +
+		edit("e3", "MultiEdit", "file_path", "/src/login.go", "2026-01-11T03:02:00Z", false) +
+		edit("e4", "Edit", "file_path", "/src/missing.go", "2026-01-11T03:03:00Z", true) +
+		edit("r1", "Read", "file_path", "/src/read.go", "2026-01-11T03:04:00Z", false) +
+		edit("n1", "NotebookEdit", "notebook_path", "/nb/analysis.ipynb", "2026-01-11T03:05:00Z", false) +
+		// Still running: not counted until it succeeds
+		fmt.Sprintf(`{"type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"e5","name":"Edit","input":{"file_path":"/src/pending.go"}}]}}`) + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewParser(path)
+	if err := p.Parse(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range p.GetTouchedFiles() {
+		got = append(got, fmt.Sprintf("%s×%d", f.Path, f.Edits))
+	}
+	want := "/nb/analysis.ipynb×1 /src/login.go×2 /src/routes.go×1"
+	if strings.Join(got, " ") != want {
+		t.Errorf("GetTouchedFiles() = %v, want %s", got, want)
+	}
+
+	// Files touched before the transcript was rotated are kept
+	rotated := filepath.Join(dir, "rotated.jsonl")
+	if err := os.WriteFile(rotated, []byte(edit("e6", "Edit", "file_path", "/src/new.go", "2026-01-11T04:00:00Z", false)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(rotated, path); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Parse(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if files := p.GetTouchedFiles(); len(files) != 4 || files[0].Path != "/src/new.go" {
+		t.Errorf("GetTouchedFiles() after rotation = %+v, want 4 files, /src/new.go first", files)
+	}
+}
+
+func TestParser_UnansweredTool(t *testing.T) {
+	toolUse := func(id, name, ts string) string {
+		return fmt.Sprintf(`{"type":"assistant","timestamp":%q,"message":{"role":"assistant","content":[{"type":"tool_use","id":%q,"name":%q,"input":{"command":"go test ./..."}}]}}`, ts, id, name) + "\n"
+	}
+	result := func(id, ts string) string {
+		return fmt.Sprintf(`{"type":"user","timestamp":%q,"message":{"role":"user","content":[{"type":"tool_result","tool_use_id":%q}]}}`, ts, id) + "\n"
+	}
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "waiting on the last call",
+			content: toolUse("t1", "Read", "2026-01-11T03:00:00Z") + result("t1", "2026-01-11T03:00:01Z") + toolUse("t2", "Bash", "2026-01-11T03:00:02Z"),
+			want:    "t2",
+		},
+		{
+			name:    "parallel calls, oldest first",
+			content: toolUse("t1", "Bash", "2026-01-11T03:00:00Z") + toolUse("t2", "Edit", "2026-01-11T03:00:01Z"),
+			want:    "t1",
+		},
+		{
+			name:    "answered",
+			content: toolUse("t1", "Bash", "2026-01-11T03:00:00Z") + result("t1", "2026-01-11T03:00:05Z"),
+		},
+		{
+			name: "conversation moved on",
+			content: toolUse("t1", "Bash", "2026-01-11T03:00:00Z") +
+				`{"type":"user","timestamp":"2026-01-11T03:01:00Z","message":{"role":"user","content":"never mind"}}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParser("")
+			if err := p.ParseFromReader(context.Background(), strings.NewReader(tt.content)); err != nil {
+				t.Fatal(err)
+			}
+			tool, ok := p.GetUnansweredTool()
+			if tool.ToolUseID != tt.want || ok != (tt.want != "") {
+				t.Errorf("GetUnansweredTool() = %q, %v, want %q", tool.ToolUseID, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestParser_TodoWrite(t *testing.T) {
+	todoWrite := func(id, todos string) string {
+		return `{"type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"` + id + `","name":"TodoWrite","input":{"todos":` + todos + `}}]}}` + "\n"
+	}
+	content := todoWrite("t1", `[{"content":"Fix login","status":"in_progress"},{"content":"Add tests","status":"pending"},{"content":"Update docs","status":"pending"}]`) +
+		todoWrite("t2", `[{"content":"Fix login","status":"completed"},{"content":"Add tests","status":"in_progress"}]`)
+
+	p := NewParser("")
+	if err := p.ParseFromReader(context.Background(), strings.NewReader(content)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The latest call replaces the whole list
+	var got []string
+	for _, todo := range p.GetTodoList() {
+		got = append(got, todo.Content+":"+todo.Status)
+	}
+	if want := "Fix login:completed Add tests:in_progress"; strings.Join(got, " ") != want {
+		t.Errorf("GetTodoList() = %v, want %s", got, want)
+	}
+	if total, completed := p.GetTodoCount(); total != 2 || completed != 1 {
+		t.Errorf("GetTodoCount() = %d, %d, want 2, 1", total, completed)
+	}
+	if current := p.GetCurrentTodo(); current == nil || current.Content != "Add tests" {
+		t.Errorf("GetCurrentTodo() = %+v, want Add tests", current)
+	}
+}
@@ -0,0 +1,119 @@
+package transcript
+
+// Retention limits keep memory bounded for multi-day transcripts with tens
+// of thousands of tool calls. Individual entries beyond these limits are
+// dropped, while aggregate counters keep counting.
+const (
+	DEFAULT_MAX_TOOLS_PER_NAME = 50  // Tool entries kept per tool name
+	MAX_TRACKED_ERRORS         = 200 // Error entries kept for GetRecentErrors
+)
+
+// ToolStats holds aggregate counters for a tool that survive entry eviction
+type ToolStats struct {
+	Name   string
+	Calls  int
+	Errors int
+}
+
+// MemoryStats describes how much state the parser is retaining
+type MemoryStats struct {
+	ToolEntries     int // Tool entries currently retained
+	ToolNames       int // Distinct tool names seen
+	EvictedTools    int // Tool entries dropped by retention
+	AgentEntries    int
+	Todos           int
+	Errors          int // Error entries retained
+	ErrorsTotal     int // Errors seen, including dropped entries
+	LatestEvents    int
+	MaxToolsPerName int
+}
+
+// SetMaxToolsPerName sets how many tool entries are kept per tool name
+// Values <= 0 restore the default
+func (p *Parser) SetMaxToolsPerName(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if n <= 0 {
+		n = DEFAULT_MAX_TOOLS_PER_NAME
+	}
+	p.maxToolsPerName = n
+}
+
+// recordTool stores a new tool entry under key, counting it and evicting the
+// oldest entry of the same name once the per-name limit is exceeded
+func (p *Parser) recordTool(key string, info *ToolInfo) {
+	if _, exists := p.toolActivity[key]; exists {
+		p.toolActivity[key] = info
+		return
+	}
+	p.toolActivity[key] = info
+
+	stats, ok := p.toolStats[info.Name]
+	if !ok {
+		stats = &ToolStats{Name: info.Name}
+		p.toolStats[info.Name] = stats
+	}
+	stats.Calls++
+	if info.Status == "error" {
+		stats.Errors++
+	}
+
+	keys := append(p.toolOrder[info.Name], key)
+	limit := p.maxToolsPerName
+	if limit <= 0 {
+		limit = DEFAULT_MAX_TOOLS_PER_NAME
+	}
+	for len(keys) > limit {
+		oldest := keys[0]
+		keys = keys[1:]
+		// The key may have been reused by a different tool since
+		if existing, ok := p.toolActivity[oldest]; ok && existing.Name == info.Name {
+			delete(p.toolActivity, oldest)
+			p.evictedTools++
+		}
+	}
+	p.toolOrder[info.Name] = keys
+}
+
+// countToolError records an error for a tool that is already tracked
+func (p *Parser) countToolError(name string) {
+	if stats, ok := p.toolStats[name]; ok {
+		stats.Errors++
+	}
+}
+
+// GetToolStats returns aggregate per-tool counters, including evicted entries
+func (p *Parser) GetToolStats() map[string]ToolStats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	result := make(map[string]ToolStats, len(p.toolStats))
+	for name, stats := range p.toolStats {
+		result[name] = *stats
+	}
+	return result
+}
+
+// MemoryStats returns a snapshot of retained parser state for debugging
+func (p *Parser) MemoryStats() MemoryStats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	limit := p.maxToolsPerName
+	if limit <= 0 {
+		limit = DEFAULT_MAX_TOOLS_PER_NAME
+	}
+
+	return MemoryStats{
+		ToolEntries:     len(p.toolActivity),
+		ToolNames:       len(p.toolStats),
+		EvictedTools:    p.evictedTools,
+		AgentEntries:    len(p.agentActivity),
+		Todos:           len(p.todos),
+		Errors:          len(p.errors),
+		ErrorsTotal:     p.errorsTotal,
+		LatestEvents:    len(p.latestEvents),
+		MaxToolsPerName: limit,
+	}
+}
@@ -0,0 +1,181 @@
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/render"
+)
+
+// snippetContext is how many characters a search snippet keeps on each side
+// of the match
+const snippetContext = 40
+
+// SearchMatch is a place in a transcript where a pattern matched
+type SearchMatch struct {
+	Time      time.Time
+	Role      string // user or assistant
+	Tool      string // Tool name, for matches in a tool's input
+	Field     string // Top-level input field, for matches in a tool's input
+	Snippet   string // The text around the match, on one line
+	Sidechain bool   // Part of a subagent's conversation
+}
+
+// searchLine holds the fields of a transcript line a search needs
+type searchLine struct {
+	Timestamp   string `json:"timestamp"`
+	IsSidechain bool   `json:"isSidechain"`
+	Message     *struct {
+		Role    string          `json:"role"`
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+// Search streams a transcript and calls fn with every message text and tool
+// input re matches, at most once per text block or input field, until fn
+// returns false. Tool results are not searched. Lines longer than maxLine
+// bytes are skipped without being held in memory
+func Search(r io.Reader, re *regexp.Regexp, maxLine int, fn func(SearchMatch) bool) error {
+	// Transcripts store text JSON-escaped; a pattern that matches the same
+	// way escaped or not can rule out lines before they are decoded
+	prefilter := !strings.ContainsAny(re.String(), `\"`) && isASCII(re.String())
+
+	reader := bufio.NewReaderSize(r, 64*1024)
+	var line []byte
+	skipping := false
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			if !skipping {
+				line = append(line, chunk...)
+				skipping = len(line) > maxLine
+			}
+			continue
+		}
+		if !skipping {
+			line = append(line, chunk...)
+			skipping = len(line) > maxLine
+		}
+		if !skipping && (!prefilter || re.Match(line)) {
+			if !searchLineMatches(line, re, fn) {
+				return nil
+			}
+		}
+		line, skipping = line[:0], false
+
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// searchLineMatches reports the matches in one line; it returns false when
+// fn asks to stop
+func searchLineMatches(raw []byte, re *regexp.Regexp, fn func(SearchMatch) bool) bool {
+	var line searchLine
+	if err := json.Unmarshal(raw, &line); err != nil || line.Message == nil || len(line.Message.Content) == 0 {
+		return true
+	}
+	match := SearchMatch{Role: line.Message.Role, Sidechain: line.IsSidechain}
+	match.Time, _ = time.Parse(time.RFC3339Nano, line.Timestamp)
+
+	var blocks []ContentBlock
+	if line.Message.Content[0] == '"' {
+		var text string
+		if err := json.Unmarshal(line.Message.Content, &text); err != nil {
+			return true
+		}
+		blocks = []ContentBlock{{Type: "text", Text: text}}
+	} else if err := json.Unmarshal(line.Message.Content, &blocks); err != nil {
+		return true
+	}
+
+	for i := range blocks {
+		block := &blocks[i]
+		switch block.Type {
+		case "text":
+			if snippet, ok := searchSnippet(block.Text, re); ok {
+				match.Snippet = snippet
+				if !fn(match) {
+					return false
+				}
+			}
+		case "tool_use":
+			var input map[string]interface{}
+			if json.Unmarshal(block.Input, &input) != nil {
+				continue
+			}
+			for _, field := range render.Keys(input) {
+				if snippet, ok := searchValue(input[field], re); ok {
+					m := match
+					m.Tool, m.Field, m.Snippet = block.Name, field, snippet
+					if !fn(m) {
+						return false
+					}
+				}
+			}
+		}
+	}
+	return true
+}
+
+// searchValue returns a snippet of the first string in v, however nested,
+// that re matches
+func searchValue(v interface{}, re *regexp.Regexp) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return searchSnippet(v, re)
+	case []interface{}:
+		for _, item := range v {
+			if snippet, ok := searchValue(item, re); ok {
+				return snippet, true
+			}
+		}
+	case map[string]interface{}:
+		for _, key := range render.Keys(v) {
+			if snippet, ok := searchValue(v[key], re); ok {
+				return snippet, true
+			}
+		}
+	}
+	return "", false
+}
+
+// searchSnippet returns the text around re's first match in text, on one line
+func searchSnippet(text string, re *regexp.Regexp) (string, bool) {
+	loc := re.FindStringIndex(text)
+	if loc == nil {
+		return "", false
+	}
+	before := text[:loc[0]]
+	after := text[loc[1]:]
+
+	prefix := ""
+	if n := utf8.RuneCountInString(before); n > snippetContext {
+		runes := []rune(before)
+		before, prefix = string(runes[n-snippetContext:]), "…"
+	}
+	suffix := ""
+	if n := utf8.RuneCountInString(after); n > snippetContext {
+		after, suffix = string([]rune(after)[:snippetContext]), "…"
+	}
+	snippet := prefix + before + text[loc[0]:loc[1]] + after + suffix
+	return strings.Join(strings.Fields(snippet), " "), true
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package transcript
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	long := strings.Repeat("x", 2000)
+	input := `{"type":"user","timestamp":"2026-01-11T03:00:00.000Z","message":{"role":"user","content":"rename \"Login\" to SignIn in\nthe handler"}}
+{"type":"assistant","timestamp":"2026-01-11T03:00:02.000Z","message":{"role":"assistant","content":[{"type":"text","text":"Renaming login."},{"type":"tool_use","id":"t1","name":"Edit","input":{"file_path":"/src/auth/login.go","old_string":"func Login(","new_string":"func SignIn("}}]}}
+{"type":"user","timestamp":"2026-01-11T03:00:03.000Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"login.go updated"}]}}
+{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"login ` + long + `"}]}}
+not json login
+{"type":"assistant","isSidechain":true,"message":{"role":"assistant","content":[{"type":"tool_use","id":"t2","name":"mcp__db__query","input":{"args":{"sql":["SELECT 1","SELECT * FROM login_events"]}}}]}}`
+
+	search := func(pattern string, max int) []SearchMatch {
+		var matches []SearchMatch
+		err := Search(strings.NewReader(input), regexp.MustCompile(pattern), 1000, func(m SearchMatch) bool {
+			matches = append(matches, m)
+			return max == 0 || len(matches) < max
+		})
+		if err != nil {
+			t.Fatalf("Search(%s) error = %v", pattern, err)
+		}
+		return matches
+	}
+
+	matches := search(`(?i)login`, 0)
+	want := []SearchMatch{
+		{Role: "user", Snippet: `rename "Login" to SignIn in the handler`},
+		{Role: "assistant", Snippet: "Renaming login."},
+		{Role: "assistant", Tool: "Edit", Field: "file_path", Snippet: "/src/auth/login.go"},
+		{Role: "assistant", Tool: "Edit", Field: "old_string", Snippet: "func Login("},
+		{Role: "assistant", Tool: "mcp__db__query", Field: "args", Snippet: "SELECT * FROM login_events", Sidechain: true},
+	}
+	if len(matches) != len(want) {
+		t.Fatalf("got %d matches, want %d: %+v", len(matches), len(want), matches)
+	}
+	for i, w := range want {
+		got := matches[i]
+		got.Time = want[i].Time
+		if got != w {
+			t.Errorf("match %d = %+v, want %+v", i, got, w)
+		}
+	}
+	if matches[0].Time.IsZero() {
+		t.Error("expected the match's timestamp")
+	}
+
+	// Patterns with JSON-escaped characters are matched against the decoded text
+	if got := search(`"Login"`, 0); len(got) != 1 {
+		t.Errorf(`search for "Login" found %d matches, want 1`, len(got))
+	}
+	if got := search(`in\sthe`, 0); len(got) != 1 {
+		t.Errorf(`search across a newline found %d matches, want 1`, len(got))
+	}
+	if got := search(`(?i)login`, 2); len(got) != 2 {
+		t.Errorf("search stopped after %d matches, want 2", len(got))
+	}
+}
+
+func TestSearchSnippet(t *testing.T) {
+	text := strings.Repeat("a", 100) + " needle " + strings.Repeat("b", 100)
+	snippet, ok := searchSnippet(text, regexp.MustCompile("needle"))
+	if !ok {
+		t.Fatal("expected a match")
+	}
+	if want := "…" + strings.Repeat("a", 39) + " needle " + strings.Repeat("b", 39) + "…"; snippet != want {
+		t.Errorf("searchSnippet() = %q, want %q", snippet, want)
+	}
+}
@@ -0,0 +1,191 @@
+package transcript
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// TargetFormat is how a tool's target is shortened for display
+type TargetFormat string
+
+const (
+	TargetText   TargetFormat = "text"   // Cut to length
+	TargetPath   TargetFormat = "path"   // Trailing path components, as many as fit
+	TargetDomain TargetFormat = "domain" // Host of a URL
+	TargetAuto   TargetFormat = "auto"   // Domain for URLs, path for paths, text otherwise
+)
+
+// ParseTargetFormat returns the format with the given name
+func ParseTargetFormat(name string) (TargetFormat, error) {
+	switch format := TargetFormat(strings.ToLower(name)); format {
+	case TargetText, TargetPath, TargetDomain, TargetAuto:
+		return format, nil
+	case "":
+		return TargetAuto, nil
+	}
+	return "", fmt.Errorf("unknown target format %q (want text, path, domain or auto)", name)
+}
+
+// TargetRule says which input field holds a tool's target and how to show it
+type TargetRule struct {
+	Fields []string     // Input fields to look in; the first non-empty string wins
+	Format TargetFormat // How the value is shortened
+	MaxLen int          // Longest target shown, in characters
+}
+
+// mcpTools matches MCP tools, which are named mcp__<server>__<tool>
+const mcpTools = "mcp__*"
+
+// defaultTargetRules hold the targets of Claude Code's tools and, through
+// the mcp__* pattern, of MCP tools without a rule of their own
+var defaultTargetRules = map[string]TargetRule{
+	"Read":         {Fields: []string{"file_path", "path"}, Format: TargetPath, MaxLen: 20},
+	"Write":        {Fields: []string{"file_path", "path"}, Format: TargetPath, MaxLen: 20},
+	"Edit":         {Fields: []string{"file_path", "path"}, Format: TargetPath, MaxLen: 20},
+	"MultiEdit":    {Fields: []string{"file_path"}, Format: TargetPath, MaxLen: 20},
+	"NotebookEdit": {Fields: []string{"notebook_path"}, Format: TargetPath, MaxLen: 20},
+	"LS":           {Fields: []string{"path"}, Format: TargetPath, MaxLen: 20},
+	"Glob":         {Fields: []string{"pattern"}, Format: TargetText, MaxLen: 20},
+	"Grep":         {Fields: []string{"pattern"}, Format: TargetText, MaxLen: 20},
+	"Bash":         {Fields: []string{"command"}, Format: TargetText, MaxLen: 30},
+	"WebFetch":     {Fields: []string{"url"}, Format: TargetDomain, MaxLen: 30},
+	"WebSearch":    {Fields: []string{"query"}, Format: TargetText, MaxLen: 30},
+	"Task":         {Fields: []string{"subagent_type", "description"}, Format: TargetText, MaxLen: 20},
+	"Skill":        {Fields: []string{"skill"}, Format: TargetText, MaxLen: 20},
+	mcpTools:       {Fields: []string{"file_path", "path", "url", "query", "pattern", "command", "name"}, Format: TargetAuto, MaxLen: 20},
+}
+
+var (
+	targetRulesMu sync.RWMutex
+	targetRules   = defaultTargetRules
+)
+
+// SetTargetRules adds rules to, or replaces rules of, the default ones. Keys
+// are tool names; a trailing * matches tool names by prefix, e.g.
+// mcp__github__*. Exact names take precedence, then the longest prefix
+func SetTargetRules(rules map[string]TargetRule) {
+	merged := make(map[string]TargetRule, len(defaultTargetRules)+len(rules))
+	for name, rule := range defaultTargetRules {
+		merged[name] = rule
+	}
+	for name, rule := range rules {
+		merged[name] = rule
+	}
+
+	targetRulesMu.Lock()
+	defer targetRulesMu.Unlock()
+	targetRules = merged
+}
+
+// ResetTargetRules restores the default rules
+func ResetTargetRules() {
+	targetRulesMu.Lock()
+	defer targetRulesMu.Unlock()
+	targetRules = defaultTargetRules
+}
+
+// targetRule returns the rule for a tool
+func targetRule(toolName string) (TargetRule, bool) {
+	targetRulesMu.RLock()
+	defer targetRulesMu.RUnlock()
+
+	if rule, ok := targetRules[toolName]; ok {
+		return rule, true
+	}
+	matched := -1
+	var found TargetRule
+	for pattern, rule := range targetRules {
+		prefix, ok := strings.CutSuffix(pattern, "*")
+		if ok && strings.HasPrefix(toolName, prefix) && len(prefix) > matched {
+			matched, found = len(prefix), rule
+		}
+	}
+	return found, matched >= 0
+}
+
+// ToolTarget returns a short, display-friendly target for a tool call
+// (file path, search pattern, command, domain...) from its decoded input
+func ToolTarget(toolName string, actualInput map[string]interface{}) string {
+	rule, ok := targetRule(toolName)
+	if !ok {
+		return ""
+	}
+	for _, field := range rule.Fields {
+		if value, ok := actualInput[field].(string); ok && value != "" {
+			return formatTarget(value, rule.Format, rule.MaxLen)
+		}
+	}
+	return ""
+}
+
+// formatTarget shortens a target value according to its format
+func formatTarget(value string, format TargetFormat, maxLen int) string {
+	if maxLen <= 0 {
+		maxLen = 20
+	}
+	if format == TargetAuto {
+		switch {
+		case strings.Contains(value, "://"):
+			format = TargetDomain
+		case strings.ContainsAny(value, "/\\") && !strings.ContainsAny(value, " \n"):
+			format = TargetPath
+		default:
+			format = TargetText
+		}
+	}
+
+	switch format {
+	case TargetDomain:
+		if u, err := url.Parse(value); err == nil && u.Host != "" {
+			return truncateText(strings.TrimPrefix(u.Hostname(), "www."), maxLen)
+		}
+	case TargetPath:
+		return shortenPath(value, maxLen)
+	}
+	return truncateText(strings.Join(strings.Fields(value), " "), maxLen)
+}
+
+// truncateText cuts s to maxLen characters, ending it with "..."
+func truncateText(s string, maxLen int) string {
+	if utf8.RuneCountInString(s) <= maxLen {
+		return s
+	}
+	if maxLen <= 3 {
+		return "..."
+	}
+	runes := []rune(s)
+	return string(runes[:maxLen-3]) + "..."
+}
+
+// shortenPath shortens a path to maxLen characters, keeping the file name and
+// as many of its parent directories as fit, e.g. …/sections/tools.go
+func shortenPath(path string, maxLen int) string {
+	// Convert backslashes to forward slashes (for Windows paths)
+	path = strings.ReplaceAll(path, "\\", "/")
+	if utf8.RuneCountInString(path) <= maxLen {
+		return path
+	}
+
+	parts := strings.Split(strings.TrimRight(path, "/"), "/")
+	shortened := parts[len(parts)-1]
+	if utf8.RuneCountInString(shortened) > maxLen {
+		return truncateText(shortened, maxLen)
+	}
+	for i := len(parts) - 2; i >= 0; i-- {
+		if parts[i] == "" {
+			continue
+		}
+		candidate := parts[i] + "/" + shortened
+		if utf8.RuneCountInString("…/"+candidate) > maxLen {
+			break
+		}
+		shortened = candidate
+	}
+	if utf8.RuneCountInString("…/"+shortened) <= maxLen {
+		return "…/" + shortened
+	}
+	return shortened
+}
@@ -0,0 +1,72 @@
+package transcript
+
+import "testing"
+
+func TestToolTarget(t *testing.T) {
+	tests := []struct {
+		tool  string
+		input map[string]interface{}
+		want  string
+	}{
+		{"Read", map[string]interface{}{"file_path": "main.go"}, "main.go"},
+		{"Read", map[string]interface{}{"file_path": "/home/me/proj/internal/sections/tools.go"}, "…/sections/tools.go"},
+		{"Edit", map[string]interface{}{"file_path": "/home/me/proj/a_very_long_file_name_indeed.go"}, "a_very_long_file_..."},
+		{"MultiEdit", map[string]interface{}{"file_path": "/home/me/proj/cmd/main.go"}, "…/proj/cmd/main.go"},
+		{"NotebookEdit", map[string]interface{}{"notebook_path": "/data/notebooks/analysis.ipynb"}, "…/analysis.ipynb"},
+		{"Bash", map[string]interface{}{"command": "go test\n  ./..."}, "go test ./..."},
+		{"WebFetch", map[string]interface{}{"url": "https://www.example.com/docs/page?q=1"}, "example.com"},
+		{"Task", map[string]interface{}{"subagent_type": "code-reviewer", "description": "Review the diff"}, "code-reviewer"},
+		{"Task", map[string]interface{}{"description": "Review the diff"}, "Review the diff"},
+		{"mcp__github__get_issue", map[string]interface{}{"url": "https://github.com/o/r/issues/1"}, "github.com"},
+		{"mcp__fs__read", map[string]interface{}{"path": "/srv/app/config/settings.yaml"}, "…/settings.yaml"},
+		{"mcp__db__query", map[string]interface{}{"limit": 5.0}, ""},
+		{"TodoWrite", map[string]interface{}{"todos": []interface{}{}}, ""},
+	}
+	for _, tt := range tests {
+		if got := ToolTarget(tt.tool, tt.input); got != tt.want {
+			t.Errorf("ToolTarget(%s, %v) = %q, want %q", tt.tool, tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestSetTargetRules(t *testing.T) {
+	t.Cleanup(ResetTargetRules)
+	SetTargetRules(map[string]TargetRule{
+		"Deploy":         {Fields: []string{"environment"}, Format: TargetText},
+		"mcp__github__*": {Fields: []string{"repo"}, Format: TargetText, MaxLen: 10},
+		"Bash":           {Fields: []string{"description", "command"}, Format: TargetText, MaxLen: 30},
+	})
+
+	tests := []struct {
+		tool  string
+		input map[string]interface{}
+		want  string
+	}{
+		{"Deploy", map[string]interface{}{"environment": "staging"}, "staging"},
+		{"mcp__github__get_issue", map[string]interface{}{"repo": "acme/widgets", "url": "https://github.com"}, "acme/wi..."},
+		{"mcp__fs__read", map[string]interface{}{"path": "notes.md"}, "notes.md"},
+		{"Bash", map[string]interface{}{"description": "Run tests", "command": "go test ./..."}, "Run tests"},
+		{"Read", map[string]interface{}{"file_path": "main.go"}, "main.go"},
+	}
+	for _, tt := range tests {
+		if got := ToolTarget(tt.tool, tt.input); got != tt.want {
+			t.Errorf("ToolTarget(%s) = %q, want %q", tt.tool, got, tt.want)
+		}
+	}
+
+	ResetTargetRules()
+	if got := ToolTarget("Deploy", map[string]interface{}{"environment": "staging"}); got != "" {
+		t.Errorf("ToolTarget(Deploy) after reset = %q, want none", got)
+	}
+}
+
+func TestParseTargetFormat(t *testing.T) {
+	for name, want := range map[string]TargetFormat{"": TargetAuto, "path": TargetPath, "Domain": TargetDomain, "text": TargetText} {
+		if got, err := ParseTargetFormat(name); err != nil || got != want {
+			t.Errorf("ParseTargetFormat(%q) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseTargetFormat("url"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
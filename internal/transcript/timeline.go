@@ -0,0 +1,243 @@
+package transcript
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxTimelineText is the longest message text a timeline entry keeps
+const maxTimelineText = 120
+
+// TimelineKind is what happened at a point of a session's timeline
+type TimelineKind string
+
+const (
+	TimelinePrompt     TimelineKind = "prompt"     // The user's prompt
+	TimelineReply      TimelineKind = "reply"      // Text Claude replied with
+	TimelineTool       TimelineKind = "tool"       // A tool call, with its outcome
+	TimelineTodos      TimelineKind = "todos"      // The todo list was updated
+	TimelineInterrupt  TimelineKind = "interrupt"  // The user interrupted Claude
+	TimelineCompaction TimelineKind = "compaction" // The conversation was compacted
+)
+
+// TimelineEntry is one event of a session's timeline
+type TimelineEntry struct {
+	Time      time.Time
+	Kind      TimelineKind
+	Text      string        // Message text on one line, the todo summary, or the compaction trigger
+	Tool      string        // Tool name, for tool calls
+	ToolUseID string        // Tool call ID, for tool calls
+	Target    string        // Tool target, for tool calls
+	Status    string        // Tool call outcome: running, completed, error or interrupted
+	Duration  time.Duration // From the tool call to its result
+	Sidechain bool          // Part of a subagent's conversation
+}
+
+// timelineLine holds the fields of a transcript line the timeline needs;
+// message content may be a plain string or content blocks
+type timelineLine struct {
+	Type             string `json:"type"`
+	Subtype          string `json:"subtype"`
+	Timestamp        string `json:"timestamp"`
+	IsMeta           bool   `json:"isMeta"`
+	IsSidechain      bool   `json:"isSidechain"`
+	IsCompactSummary bool   `json:"isCompactSummary"`
+	CompactMetadata  *struct {
+		Trigger   string `json:"trigger"`
+		PreTokens int    `json:"preTokens"`
+	} `json:"compactMetadata"`
+	Message *struct {
+		Role    string          `json:"role"`
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+// todoInput is the input of a TodoWrite call
+type todoInput struct {
+	Todos []struct {
+		Content    string `json:"content"`
+		Status     string `json:"status"`
+		ActiveForm string `json:"activeForm"`
+	} `json:"todos"`
+}
+
+// ReadTimeline reads a Claude Code transcript into a chronological timeline
+// of prompts, replies, tool calls with their durations, todo updates,
+// interruptions and compactions. Lines it cannot decode are skipped
+func ReadTimeline(r io.Reader) ([]TimelineEntry, error) {
+	var entries []TimelineEntry
+	calls := make(map[string]int) // Index of running tool calls in entries, by tool use ID
+	stream := NewTimelineStream()
+
+	lines := NewLineReader(r)
+	for {
+		line, _, err := lines.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return entries, err
+		}
+		for _, entry := range stream.Line(line) {
+			// A finished call takes the place of its running entry
+			if i, ok := calls[entry.ToolUseID]; ok && entry.Status != "running" {
+				entries[i] = entry
+				delete(calls, entry.ToolUseID)
+				continue
+			}
+			if entry.Kind == TimelineTool && entry.ToolUseID != "" {
+				calls[entry.ToolUseID] = len(entries)
+			}
+			entries = append(entries, entry)
+		}
+	}
+}
+
+// TimelineStream turns transcript lines into timeline entries one line at a
+// time, for following a transcript as it is written
+type TimelineStream struct {
+	calls map[string]TimelineEntry // Running tool calls, by tool use ID
+}
+
+// NewTimelineStream creates a stream with no tool calls running
+func NewTimelineStream() *TimelineStream {
+	return &TimelineStream{calls: make(map[string]TimelineEntry)}
+}
+
+// Line returns the timeline entries a transcript line adds. A tool call is
+// returned twice: running when it is made, and with its outcome and
+// duration when its result arrives. Lines it cannot decode add nothing
+func (s *TimelineStream) Line(raw []byte) []TimelineEntry {
+	var line timelineLine
+	if err := json.Unmarshal(raw, &line); err != nil {
+		return nil
+	}
+	ts, _ := time.Parse(time.RFC3339Nano, line.Timestamp)
+
+	if line.Type == "system" && line.Subtype == "compact_boundary" {
+		entry := TimelineEntry{Time: ts, Kind: TimelineCompaction, Text: "compacted"}
+		if meta := line.CompactMetadata; meta != nil {
+			entry.Text = fmt.Sprintf("%s, %s tokens before", meta.Trigger, formatTokens(meta.PreTokens))
+		}
+		return []TimelineEntry{entry}
+	}
+	if line.Message == nil || len(line.Message.Content) == 0 || line.IsCompactSummary {
+		return nil
+	}
+
+	var blocks []ContentBlock
+	if line.Message.Content[0] == '"' {
+		var text string
+		if err := json.Unmarshal(line.Message.Content, &text); err != nil {
+			return nil
+		}
+		blocks = []ContentBlock{{Type: "text", Text: text}}
+	} else if err := json.Unmarshal(line.Message.Content, &blocks); err != nil {
+		return nil
+	}
+
+	var entries []TimelineEntry
+	for i := range blocks {
+		block := &blocks[i]
+		switch block.Type {
+		case "text":
+			text := oneLine(block.Text)
+			switch {
+			case text == "":
+			case line.Message.Role == "assistant":
+				entries = append(entries, TimelineEntry{Time: ts, Kind: TimelineReply, Text: text, Sidechain: line.IsSidechain})
+			case strings.HasPrefix(block.Text, interruptNotice):
+				entries = append(entries, TimelineEntry{Time: ts, Kind: TimelineInterrupt, Sidechain: line.IsSidechain})
+			case !line.IsMeta:
+				entries = append(entries, TimelineEntry{Time: ts, Kind: TimelinePrompt, Text: text, Sidechain: line.IsSidechain})
+			}
+
+		case "tool_use":
+			if block.Name == "" {
+				continue
+			}
+			if block.Name == "TodoWrite" {
+				var todos todoInput
+				if json.Unmarshal(block.Input, &todos) == nil {
+					entries = append(entries, TimelineEntry{Time: ts, Kind: TimelineTodos, Text: todos.summary(), Sidechain: line.IsSidechain})
+				}
+				continue
+			}
+			var input map[string]interface{}
+			_ = json.Unmarshal(block.Input, &input)
+			call := TimelineEntry{
+				Time:      ts,
+				Kind:      TimelineTool,
+				Tool:      block.Name,
+				ToolUseID: block.ID,
+				Target:    ToolTarget(block.Name, input),
+				Status:    "running",
+				Sidechain: line.IsSidechain,
+			}
+			if block.ID != "" {
+				s.calls[block.ID] = call
+			}
+			entries = append(entries, call)
+
+		case "tool_result":
+			call, ok := s.calls[block.ToolUseID]
+			if !ok {
+				continue
+			}
+			delete(s.calls, block.ToolUseID)
+			switch {
+			case isAbortedResult(block):
+				call.Status = "interrupted"
+			case block.IsError:
+				call.Status = "error"
+			default:
+				call.Status = "completed"
+			}
+			if !ts.IsZero() && !call.Time.IsZero() && ts.After(call.Time) {
+				call.Duration = ts.Sub(call.Time)
+			}
+			entries = append(entries, call)
+		}
+	}
+	return entries
+}
+
+// summary describes a todo list, e.g. "2/5 done, now: Fixing the tests"
+func (t todoInput) summary() string {
+	done := 0
+	var active string
+	for _, todo := range t.Todos {
+		switch todo.Status {
+		case "completed":
+			done++
+		case "in_progress":
+			if active == "" {
+				active = todo.ActiveForm
+				if active == "" {
+					active = todo.Content
+				}
+			}
+		}
+	}
+	summary := fmt.Sprintf("%d/%d done", done, len(t.Todos))
+	if active != "" {
+		summary += ", now: " + oneLine(active)
+	}
+	return summary
+}
+
+// oneLine collapses whitespace, so text fits on one line, and shortens it
+func oneLine(text string) string {
+	return truncateText(strings.Join(strings.Fields(text), " "), maxTimelineText)
+}
+
+// formatTokens formats a token count, e.g. 152k
+func formatTokens(n int) string {
+	if n >= 1000 {
+		return fmt.Sprintf("%dk", n/1000)
+	}
+	return fmt.Sprintf("%d", n)
+}
@@ -0,0 +1,74 @@
+package transcript
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadTimeline(t *testing.T) {
+	input := `{"type":"user","timestamp":"2026-01-11T03:00:00.000Z","message":{"role":"user","content":"fix the\nlogin page"}}
+{"type":"assistant","timestamp":"2026-01-11T03:00:02.000Z","message":{"role":"assistant","content":[{"type":"text","text":"Looking."},{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"/home/me/proj/internal/auth/login.go"}}]}}
+{"type":"user","timestamp":"2026-01-11T03:00:02.120Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"package auth"}]}}
+not json
+{"type":"assistant","timestamp":"2026-01-11T03:00:03.000Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"t2","name":"TodoWrite","input":{"todos":[{"content":"Fix tests","status":"in_progress","activeForm":"Fixing tests"},{"content":"Read","status":"completed"}]}}]}}
+{"type":"assistant","timestamp":"2026-01-11T03:00:04.000Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"t3","name":"Bash","input":{"command":"go test ./..."}}]}}
+{"type":"user","timestamp":"2026-01-11T03:00:08.200Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t3","is_error":true,"content":"FAIL"}]}}
+{"type":"system","subtype":"compact_boundary","timestamp":"2026-01-11T03:10:00.000Z","compactMetadata":{"trigger":"auto","preTokens":152340}}
+{"type":"user","timestamp":"2026-01-11T03:10:00.000Z","isCompactSummary":true,"message":{"role":"user","content":"This session is being continued"}}
+{"type":"user","timestamp":"2026-01-11T03:10:01.000Z","isMeta":true,"message":{"role":"user","content":"<command-output>ok</command-output>"}}
+{"type":"user","timestamp":"2026-01-11T03:11:00.000Z","message":{"role":"user","content":[{"type":"text","text":"[Request interrupted by user]"}]}}
+{"type":"assistant","timestamp":"2026-01-11T03:12:00.000Z","isSidechain":true,"message":{"role":"assistant","content":[{"type":"tool_use","id":"t4","name":"Grep","input":{"pattern":"TODO"}}]}}
+`
+	entries, err := ReadTimeline(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadTimeline() error = %v", err)
+	}
+
+	want := []TimelineEntry{
+		{Kind: TimelinePrompt, Text: "fix the login page"},
+		{Kind: TimelineReply, Text: "Looking."},
+		{Kind: TimelineTool, Tool: "Read", ToolUseID: "t1", Target: "…/auth/login.go", Status: "completed", Duration: 120 * time.Millisecond},
+		{Kind: TimelineTodos, Text: "1/2 done, now: Fixing tests"},
+		{Kind: TimelineTool, Tool: "Bash", ToolUseID: "t3", Target: "go test ./...", Status: "error", Duration: 4200 * time.Millisecond},
+		{Kind: TimelineCompaction, Text: "auto, 152k tokens before"},
+		{Kind: TimelineInterrupt},
+		{Kind: TimelineTool, Tool: "Grep", ToolUseID: "t4", Target: "TODO", Status: "running", Sidechain: true},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i, w := range want {
+		got := entries[i]
+		if got.Time.IsZero() {
+			t.Errorf("entry %d has no time", i)
+		}
+		got.Time = time.Time{}
+		if got != w {
+			t.Errorf("entry %d = %+v, want %+v", i, got, w)
+		}
+	}
+}
+
+func TestTimelineStream(t *testing.T) {
+	stream := NewTimelineStream()
+
+	entries := stream.Line([]byte(`{"type":"assistant","timestamp":"2026-01-11T03:00:04.000Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"t3","name":"Bash","input":{"command":"go test ./..."}}]}}`))
+	if len(entries) != 1 || entries[0].Status != "running" || entries[0].ToolUseID != "t3" {
+		t.Fatalf("Line(tool_use) = %+v, want the running call", entries)
+	}
+
+	if entries := stream.Line([]byte("not json")); entries != nil {
+		t.Errorf("Line(not json) = %+v, want nothing", entries)
+	}
+
+	entries = stream.Line([]byte(`{"type":"user","timestamp":"2026-01-11T03:00:08.200Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t3","content":"ok"}]}}`))
+	if len(entries) != 1 || entries[0].Status != "completed" || entries[0].Duration != 4200*time.Millisecond {
+		t.Fatalf("Line(tool_result) = %+v, want the completed call", entries)
+	}
+
+	// A result is only matched once
+	if entries := stream.Line([]byte(`{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t3","content":"ok"}]}}`)); len(entries) != 0 {
+		t.Errorf("Line(repeated tool_result) = %+v, want nothing", entries)
+	}
+}
@@ -2,9 +2,14 @@ package watcher
 
 import (
 	"context"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
@@ -34,69 +39,412 @@ const (
 	ModePolling
 )
 
+// String returns the mode name shown by the doctor command
+func (m WatcherMode) String() string {
+	if m == ModePolling {
+		return "polling"
+	}
+	return "fsnotify"
+}
+
+// Health describes the watcher's state for diagnostics
+type Health struct {
+	Mode        WatcherMode
+	Running     bool      // Started and not yet stopped or cancelled
+	Files       int       // Files currently known to exist
+	Fallbacks   int       // Times fsnotify was abandoned for polling
+	LastError   error     // Most recent fsnotify error, nil if none
+	LastErrorAt time.Time // When LastError occurred
+	PollOnly    bool      // Polling by choice rather than after an fsnotify failure
+}
+
+// preferPolling makes new watchers poll without trying fsnotify
+var preferPolling atomic.Bool
+
+// SetPreferPolling makes watchers created afterwards poll from the start and
+// never switch to fsnotify, for file systems where fsnotify misses changes,
+// such as bind mounts into a container
+func SetPreferPolling(poll bool) {
+	preferPolling.Store(poll)
+}
+
+// dirWatch watches the files of a directory whose names match a pattern
+type dirWatch struct {
+	dir       string
+	pattern   string // Glob matched against file names; "" matches every file
+	recursive bool   // Include files in subdirectories
+}
+
 // Watcher watches files for changes with fsnotify and polling fallback
+//
+// Files can be watched individually with AddWatch, or by directory and name
+// pattern with AddWatchDir and AddWatchGlob. Files appearing in or vanishing
+// from a watched directory are reported as EventCreated and EventDeleted
+//
+// Every goroutine runs under one owner context, cancelled by Stop or by the
+// context passed to Start. Mode changes happen under mu, and goroutines are
+// only added while the watcher is running, so Stop can wait for all of them
 type Watcher struct {
 	mu               sync.RWMutex
 	mode             WatcherMode
 	fsnotifyWatcher  *fsnotify.Watcher
-	pollingTicker    *time.Ticker
+	pollCancel       context.CancelFunc // Stops the polling loop; nil when not polling
 	watchPaths       map[string]bool
+	dirWatches       []dirWatch
 	eventChan        chan Event
 	errorChan        chan error
-	stopChan         chan struct{}
 	wg               sync.WaitGroup
 	recoveryInterval time.Duration
 	pollingInterval  time.Duration
-	lastModTimes     map[string]time.Time
-	ctx              context.Context
+	lastModTimes     map[string]time.Time // Every file currently known to exist
+	debounce         time.Duration
+	pending          map[string]*pendingEvent
+	debounceWake     chan struct{}
+	ctx              context.Context // Owner context of every goroutine
+	cancel           context.CancelFunc
+	stopParent       func() bool // Detaches the Start context from ctx
+	started          bool
 	stopped          bool
+	fallbacks        int
+	pollOnly         bool // Never use fsnotify; see SetPreferPolling
+	lastErr          error
+	lastErrAt        time.Time
 }
 
 // NewWatcher creates a new file watcher
 func NewWatcher() *Watcher {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &Watcher{
+		ctx:              ctx,
+		cancel:           cancel,
 		watchPaths:       make(map[string]bool),
 		eventChan:        make(chan Event, 100),
 		errorChan:        make(chan error, 10),
-		stopChan:         make(chan struct{}),
 		recoveryInterval: 30 * time.Second,
 		pollingInterval:  300 * time.Millisecond,
 		lastModTimes:     make(map[string]time.Time),
+		pending:          make(map[string]*pendingEvent),
+		debounceWake:     make(chan struct{}, 1),
+		pollOnly:         preferPolling.Load(),
 	}
 }
 
+// pendingEvent is a coalesced event waiting for its debounce window to end
+type pendingEvent struct {
+	event Event
+	due   time.Time
+}
+
 // AddWatch adds a path to be watched
 func (w *Watcher) AddWatch(path string) error {
-	w.mu.Lock()
-	defer w.mu.Unlock()
-
 	// Check if file exists
-	if _, err := os.Stat(path); os.IsNotExist(err) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
 		return nil // Don't error, just don't watch non-existent files
 	}
 
+	w.mu.Lock()
 	// Add to watch list
 	w.watchPaths[path] = true
 
 	// Initialize last mod time
-	if info, err := os.Stat(path); err == nil {
+	if err == nil {
 		w.lastModTimes[path] = info.ModTime()
 	}
 
-	// If fsnotify watcher is active, add the watch
-	if w.mode == ModeFsnotify && w.fsnotifyWatcher != nil {
-		// Watch the parent directory for file changes
+	// If fsnotify watcher is active, watch the parent directory for file changes
+	fsw := w.fsnotifyWatcher
+	var addErr error
+	if w.mode == ModeFsnotify && fsw != nil {
 		dir := filepath.Dir(path)
-		if err := w.fsnotifyWatcher.Add(dir); err != nil {
-			errors.Warn("watcher", "failed to watch directory %s: %v", dir, err)
-			// Fall back to polling
-			w.fallbackToPolling()
+		if addErr = fsw.Add(dir); addErr != nil {
+			errors.Warn("watcher", "failed to watch directory %s: %v", dir, addErr)
 		}
 	}
+	w.mu.Unlock()
 
+	if addErr != nil {
+		w.fallbackToPolling(fsw, addErr)
+	}
 	return nil
 }
 
+// AddWatchDir watches every file in dir, and in its subdirectories when
+// recursive is set. Like AddWatch, a directory that does not exist is ignored
+func (w *Watcher) AddWatchDir(dir string, recursive bool) error {
+	return w.addDirWatch(dirWatch{dir: filepath.Clean(dir), recursive: recursive})
+}
+
+// AddWatchGlob watches the files matching pattern, e.g.
+// ~/.claude/projects/<project>/*.jsonl, including files created later
+// Wildcards are only supported in the last path element
+func (w *Watcher) AddWatchGlob(pattern string) error {
+	dir, name := filepath.Split(pattern)
+	if strings.ContainsAny(dir, "*?[") {
+		return fmt.Errorf("wildcards are only supported in the file name: %s", pattern)
+	}
+	if _, err := filepath.Match(name, ""); err != nil {
+		return fmt.Errorf("invalid watch pattern %s: %w", pattern, err)
+	}
+	return w.addDirWatch(dirWatch{dir: filepath.Clean(dir), pattern: name})
+}
+
+// addDirWatch registers a directory watch; files already present are
+// recorded without events
+func (w *Watcher) addDirWatch(dw dirWatch) error {
+	if info, err := os.Stat(dw.dir); err != nil || !info.IsDir() {
+		return nil // Don't error, just don't watch non-existent directories
+	}
+
+	w.mu.Lock()
+	w.dirWatches = append(w.dirWatches, dw)
+	for path, modTime := range dw.files() {
+		if _, known := w.lastModTimes[path]; !known {
+			w.lastModTimes[path] = modTime
+		}
+	}
+
+	fsw := w.fsnotifyWatcher
+	var addErr error
+	if w.mode == ModeFsnotify && fsw != nil {
+		for _, dir := range dw.dirs() {
+			if addErr = fsw.Add(dir); addErr != nil {
+				errors.Warn("watcher", "failed to watch directory %s: %v", dir, addErr)
+				break
+			}
+		}
+	}
+	w.mu.Unlock()
+
+	if addErr != nil {
+		w.fallbackToPolling(fsw, addErr)
+	}
+	return nil
+}
+
+// matches reports whether a directory watch covers path
+func (dw dirWatch) matches(path string) bool {
+	if parent := filepath.Dir(path); parent != dw.dir && !dw.matchesDir(parent) {
+		return false
+	}
+	if dw.pattern == "" {
+		return true
+	}
+	ok, _ := filepath.Match(dw.pattern, filepath.Base(path))
+	return ok
+}
+
+// matchesDir reports whether dir lies inside a recursive watch
+func (dw dirWatch) matchesDir(dir string) bool {
+	return dw.recursive && strings.HasPrefix(dir, dw.dir+string(filepath.Separator))
+}
+
+// dirs returns the directories to watch: dir and, when recursive, its subdirectories
+func (dw dirWatch) dirs() []string {
+	if !dw.recursive {
+		return []string{dw.dir}
+	}
+	var dirs []string
+	_ = filepath.WalkDir(dw.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err == nil && entry.IsDir() {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	return dirs
+}
+
+// files returns the modification times of the regular files the watch covers
+// Symlinked directories are not followed
+func (dw dirWatch) files() map[string]time.Time {
+	files := make(map[string]time.Time)
+	add := func(path string, entry fs.DirEntry) {
+		if !entry.Type().IsRegular() || !dw.matches(path) {
+			return
+		}
+		if info, err := entry.Info(); err == nil {
+			files[path] = info.ModTime()
+		}
+	}
+
+	if !dw.recursive {
+		entries, _ := os.ReadDir(dw.dir)
+		for _, entry := range entries {
+			add(filepath.Join(dw.dir, entry.Name()), entry)
+		}
+		return files
+	}
+	_ = filepath.WalkDir(dw.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err == nil {
+			add(path, entry)
+		}
+		return nil
+	})
+	return files
+}
+
+// watched reports whether path is covered by AddWatch or a directory watch
+// Callers must hold w.mu
+func (w *Watcher) watched(path string) bool {
+	if w.watchPaths[path] {
+		return true
+	}
+	for _, dw := range w.dirWatches {
+		if dw.matches(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// update compares a file's current state with the last one seen and returns
+// the resulting event, if any. Callers must hold w.mu
+func (w *Watcher) update(path string, modTime time.Time, exists bool) (Event, bool) {
+	lastMod, known := w.lastModTimes[path]
+	switch {
+	case !exists && known:
+		delete(w.lastModTimes, path)
+		return Event{Path: path, EventType: EventDeleted}, true
+	case exists && !known:
+		w.lastModTimes[path] = modTime
+		return Event{Path: path, EventType: EventCreated}, true
+	case exists && modTime.After(lastMod):
+		w.lastModTimes[path] = modTime
+		return Event{Path: path, EventType: EventModified}, true
+	}
+	return Event{}, false
+}
+
+// watchDirs returns every directory fsnotify has to watch. Callers must hold w.mu
+func (w *Watcher) watchDirs() []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	add := func(dir string) {
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	for path := range w.watchPaths {
+		add(filepath.Dir(path))
+	}
+	for _, dw := range w.dirWatches {
+		for _, dir := range dw.dirs() {
+			add(dir)
+		}
+	}
+	return dirs
+}
+
+// send delivers events, or queues them for coalescing when debouncing is enabled
+func (w *Watcher) send(events []Event) {
+	if len(events) == 0 {
+		return
+	}
+	w.mu.Lock()
+	if w.debounce <= 0 {
+		w.mu.Unlock()
+		w.deliver(events)
+		return
+	}
+	now := time.Now()
+	for _, event := range events {
+		w.coalesce(event, now.Add(w.debounce))
+	}
+	w.mu.Unlock()
+
+	select {
+	case w.debounceWake <- struct{}{}:
+	default: // A wake-up is already pending
+	}
+}
+
+// coalesce merges an event into the pending event for its path
+// The window starts at the first event, so a file appended to continuously
+// still produces one event per window. Callers must hold w.mu
+func (w *Watcher) coalesce(event Event, due time.Time) {
+	p, ok := w.pending[event.Path]
+	if !ok {
+		w.pending[event.Path] = &pendingEvent{event: event, due: due}
+		return
+	}
+	switch {
+	case p.event.EventType == EventCreated && event.EventType == EventDeleted:
+		delete(w.pending, event.Path) // Appeared and vanished within the window
+	case p.event.EventType == EventCreated:
+		// Still a new file, however often it was written
+	case p.event.EventType == EventDeleted && event.EventType != EventDeleted:
+		p.event.EventType = EventModified // Replaced
+	default:
+		p.event.EventType = event.EventType
+	}
+}
+
+// debounceLoop delivers coalesced events once their window has ended
+func (w *Watcher) debounceLoop() {
+	defer w.wg.Done()
+
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-w.debounceWake:
+		case <-timer.C:
+		}
+
+		due, next := w.takeDue(time.Now())
+		w.deliver(due)
+		if !next.IsZero() {
+			timer.Reset(time.Until(next))
+		}
+	}
+}
+
+// takeDue removes the pending events whose window has ended, oldest first,
+// and returns when the next one is due (zero when none are left)
+func (w *Watcher) takeDue(now time.Time) ([]Event, time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var due []*pendingEvent
+	var next time.Time
+	for path, p := range w.pending {
+		if !p.due.After(now) {
+			due = append(due, p)
+			delete(w.pending, path)
+		} else if next.IsZero() || p.due.Before(next) {
+			next = p.due
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].due.Equal(due[j].due) {
+			return due[i].due.Before(due[j].due)
+		}
+		return due[i].event.Path < due[j].event.Path
+	})
+
+	events := make([]Event, len(due))
+	for i, p := range due {
+		events[i] = p.event
+	}
+	return events, next
+}
+
+// deliver sends events, dropping them once the watcher has been stopped
+func (w *Watcher) deliver(events []Event) {
+	for _, event := range events {
+		select {
+		case w.eventChan <- event:
+		case <-w.ctx.Done():
+			return
+		}
+	}
+}
+
 // Events returns the event channel
 func (w *Watcher) Events() <-chan Event {
 	return w.eventChan
@@ -108,31 +456,41 @@ func (w *Watcher) Errors() <-chan error {
 }
 
 // Start begins watching files
+// The watcher stops when ctx is cancelled; Stop must still be called to
+// wait for its goroutines and close the channels
 func (w *Watcher) Start(ctx context.Context) error {
 	return errors.SafeCall(func() error {
 		w.mu.Lock()
-		w.ctx = ctx
+		if w.started || w.stopped {
+			w.mu.Unlock()
+			return nil
+		}
+		w.started = true
+		w.stopParent = context.AfterFunc(ctx, w.cancel)
+		w.wg.Add(2)
 		w.mu.Unlock()
 
 		// Try to start fsnotify watcher
-		if err := w.startFsnotifyWatcher(); err != nil {
-			errors.Warn("watcher", "fsnotify not available, using polling: %v", err)
+		if w.pollOnly {
 			w.startPolling()
+		} else if err := w.startFsnotifyWatcher(); err != nil {
+			errors.Warn("watcher", "fsnotify not available, using polling: %v", err)
+			w.fallbackToPolling(nil, err)
 		}
 
-		// Start recovery goroutine
-		w.wg.Add(1)
-		go w.recoveryLoop(ctx)
-
-		// Start event processing
-		w.wg.Add(1)
-		go w.processEvents(ctx)
-
+		go w.recoveryLoop()
+		go w.debounceLoop()
 		return nil
 	})
 }
 
-// startFsnotifyWatcher attempts to start the fsnotify watcher
+// running reports whether goroutines may still be added. Callers must hold w.mu
+func (w *Watcher) running() bool {
+	return w.started && !w.stopped && w.ctx.Err() == nil
+}
+
+// startFsnotifyWatcher switches to fsnotify, stopping polling once every
+// directory is watched
 func (w *Watcher) startFsnotifyWatcher() error {
 	fsw, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -140,47 +498,39 @@ func (w *Watcher) startFsnotifyWatcher() error {
 	}
 
 	w.mu.Lock()
-	w.fsnotifyWatcher = fsw
-	w.mode = ModeFsnotify
+	defer w.mu.Unlock()
 
-	// Add watches for all paths
-	for path := range w.watchPaths {
-		dir := filepath.Dir(path)
+	if !w.running() {
+		fsw.Close()
+		return context.Canceled
+	}
+	for _, dir := range w.watchDirs() {
 		if err := fsw.Add(dir); err != nil {
-			w.mu.Unlock()
 			fsw.Close()
 			return err
 		}
 	}
-	w.mu.Unlock()
 
-	// Start fsnotify event loop
-	w.wg.Add(1)
-	go w.fsnotifyEventLoop()
+	w.fsnotifyWatcher = fsw
+	w.mode = ModeFsnotify
+	if w.pollCancel != nil {
+		w.pollCancel()
+		w.pollCancel = nil
+	}
 
+	w.wg.Add(1)
+	go w.fsnotifyEventLoop(fsw)
 	return nil
 }
 
-// fsnotifyEventLoop processes fsnotify events
-func (w *Watcher) fsnotifyEventLoop() {
+// fsnotifyEventLoop processes events from one fsnotify watcher until it is
+// closed or fails
+func (w *Watcher) fsnotifyEventLoop(fsw *fsnotify.Watcher) {
 	defer w.wg.Done()
 
-	w.mu.RLock()
-	ctx := w.ctx
-	fsw := w.fsnotifyWatcher
-	w.mu.RUnlock()
-
-	defer func() {
-		if fsw != nil {
-			fsw.Close()
-		}
-	}()
-
 	for {
 		select {
-		case <-ctx.Done():
-			return
-		case <-w.stopChan:
+		case <-w.ctx.Done():
 			return
 		case event, ok := <-fsw.Events:
 			if !ok {
@@ -191,143 +541,202 @@ func (w *Watcher) fsnotifyEventLoop() {
 			if !ok {
 				return
 			}
-			w.errorChan <- err
-			// Fall back to polling on error
-			w.fallbackToPolling()
+			select {
+			case w.errorChan <- err:
+			default: // Nobody is reading errors; Health still reports it
+			}
+			w.fallbackToPolling(fsw, err)
+			return
 		}
 	}
 }
 
 // handleFsnotifyEvent handles a single fsnotify event
+// The file is stat'ed rather than trusting the operation, so renames and
+// atomic replaces are reported by what is on disk afterwards
 func (w *Watcher) handleFsnotifyEvent(event fsnotify.Event) {
 	w.mu.Lock()
-	defer w.mu.Unlock()
-
-	// Check if this is a path we're watching
-	for path := range w.watchPaths {
-		if event.Name == path {
-			// Check if file was actually modified
-			if info, err := os.Stat(path); err == nil {
-				lastMod := w.lastModTimes[path]
-				if info.ModTime().After(lastMod) {
-					w.lastModTimes[path] = info.ModTime()
-					w.eventChan <- Event{Path: path, EventType: EventModified}
+	var events []Event
+	var addErr error
+	fsw := w.fsnotifyWatcher
+	info, err := os.Stat(event.Name)
+
+	// New subdirectories of a recursive watch are watched too; files created
+	// in them before the watch was added are reported now
+	if err == nil && info.IsDir() && event.Has(fsnotify.Create) {
+		for _, dw := range w.dirWatches {
+			if !dw.matchesDir(event.Name) {
+				continue
+			}
+			sub := dirWatch{dir: event.Name, pattern: dw.pattern, recursive: true}
+			if fsw != nil && addErr == nil {
+				for _, dir := range sub.dirs() {
+					if addErr = fsw.Add(dir); addErr != nil {
+						break
+					}
+				}
+			}
+			for path, modTime := range sub.files() {
+				if e, ok := w.update(path, modTime, true); ok {
+					events = append(events, e)
 				}
 			}
-			break
 		}
+	} else if w.watched(event.Name) && (err != nil || info.Mode().IsRegular()) {
+		var modTime time.Time
+		if err == nil {
+			modTime = info.ModTime()
+		}
+		if e, ok := w.update(event.Name, modTime, err == nil); ok {
+			events = append(events, e)
+		}
+	}
+	w.mu.Unlock()
+
+	w.send(events)
+	if addErr != nil {
+		errors.Warn("watcher", "failed to watch new directory %s: %v", event.Name, addErr)
+		w.fallbackToPolling(fsw, addErr)
 	}
 }
 
-// fallbackToPolling switches to polling mode
-func (w *Watcher) fallbackToPolling() {
+// fallbackToPolling switches to polling mode after fsw failed with err
+// It is a no-op when fsw is no longer the active watcher, so a late error
+// from a replaced watcher cannot undo a recovery
+func (w *Watcher) fallbackToPolling(fsw *fsnotify.Watcher, err error) {
 	w.mu.Lock()
-	defer w.mu.Unlock()
-
-	if w.mode == ModePolling {
-		return // Already in polling mode
+	if err != nil {
+		w.lastErr = err
+		w.lastErrAt = time.Now()
+	}
+	if !w.running() || fsw != w.fsnotifyWatcher || w.mode == ModePolling {
+		w.mu.Unlock()
+		return
 	}
 
-	// Close fsnotify watcher if open
+	// The event loop returns once its channels are closed
 	if w.fsnotifyWatcher != nil {
 		w.fsnotifyWatcher.Close()
 		w.fsnotifyWatcher = nil
 	}
+	w.fallbacks++
+	pollCtx, interval := w.pollLocked()
+	w.mu.Unlock()
 
-	w.mode = ModePolling
-	w.startPolling()
+	go w.pollingLoop(pollCtx, interval)
 	errors.Warn("watcher", "fell back to polling mode")
 }
 
-// startPolling starts the polling ticker
+// startPolling polls from the start, for watchers that never use fsnotify
 func (w *Watcher) startPolling() {
 	w.mu.Lock()
-	defer w.mu.Unlock()
-
-	if w.pollingTicker != nil {
-		return // Already polling
+	if !w.running() {
+		w.mu.Unlock()
+		return
 	}
+	pollCtx, interval := w.pollLocked()
+	w.mu.Unlock()
 
-	w.pollingTicker = time.NewTicker(w.pollingInterval)
+	go w.pollingLoop(pollCtx, interval)
+	errors.Debug("watcher", "polling instead of fsnotify")
+}
 
+// pollLocked switches to polling mode and accounts for the polling loop the
+// caller must start. Callers must hold w.mu
+func (w *Watcher) pollLocked() (context.Context, time.Duration) {
+	w.mode = ModePolling
+	pollCtx, cancel := context.WithCancel(w.ctx)
+	w.pollCancel = cancel
 	w.wg.Add(1)
-	go func() {
-		defer w.wg.Done()
-		w.pollingLoop(context.Background())
-	}()
+	return pollCtx, w.pollingInterval
 }
 
-// pollingLoop checks for file changes periodically
-func (w *Watcher) pollingLoop(ctx context.Context) {
+// pollingLoop checks for file changes periodically until ctx is cancelled
+func (w *Watcher) pollingLoop(ctx context.Context, interval time.Duration) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-w.stopChan:
-			return
-		case <-w.pollingTicker.C:
+		case <-ticker.C:
 			w.checkForChanges()
 		}
 	}
 }
 
-// checkForChanges checks all watched files for modifications
+// checkForChanges checks all watched files for modifications, and watched
+// directories for files that appeared or disappeared
 func (w *Watcher) checkForChanges() {
 	w.mu.Lock()
-	defer w.mu.Unlock()
-
+	current := make(map[string]time.Time)
 	for path := range w.watchPaths {
 		if info, err := os.Stat(path); err == nil {
-			lastMod := w.lastModTimes[path]
-			if info.ModTime().After(lastMod) {
-				w.lastModTimes[path] = info.ModTime()
-				w.eventChan <- Event{Path: path, EventType: EventModified}
+			current[path] = info.ModTime()
+		}
+	}
+	for _, dw := range w.dirWatches {
+		for path, modTime := range dw.files() {
+			current[path] = modTime
+		}
+	}
+
+	var events []Event
+	for path, modTime := range current {
+		if e, ok := w.update(path, modTime, true); ok {
+			events = append(events, e)
+		}
+	}
+	for path := range w.lastModTimes {
+		if _, ok := current[path]; !ok {
+			if e, ok := w.update(path, time.Time{}, false); ok {
+				events = append(events, e)
 			}
 		}
 	}
+	w.mu.Unlock()
+
+	w.send(events)
 }
 
 // recoveryLoop periodically attempts to recover fsnotify
-func (w *Watcher) recoveryLoop(ctx context.Context) {
+func (w *Watcher) recoveryLoop() {
 	defer w.wg.Done()
 
-	recoveryTicker := time.NewTicker(w.recoveryInterval)
+	w.mu.RLock()
+	interval := w.recoveryInterval
+	w.mu.RUnlock()
+
+	recoveryTicker := time.NewTicker(interval)
 	defer recoveryTicker.Stop()
 
 	for {
 		select {
-		case <-ctx.Done():
+		case <-w.ctx.Done():
 			return
 		case <-recoveryTicker.C:
-			if w.mode == ModePolling {
+			if w.GetMode() == ModePolling {
 				w.tryRecoverFsnotify()
 			}
 		}
 	}
 }
 
-// tryRecoverFsnotify attempts to recover fsnotify mode
+// tryRecoverFsnotify attempts to recover fsnotify mode, unless polling by choice
 func (w *Watcher) tryRecoverFsnotify() {
+	if w.pollOnly {
+		return
+	}
 	if err := w.startFsnotifyWatcher(); err == nil {
 		errors.Info("watcher", "recovered fsnotify mode")
 	}
 }
 
-// processEvents ensures events are processed
-func (w *Watcher) processEvents(ctx context.Context) {
-	defer w.wg.Done()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-w.stopChan:
-			return
-		}
-	}
-}
-
-// Stop stops the watcher
+// Stop stops the watcher, waits for its goroutines and closes the channels
+// Safe to call more than once and before Start
 func (w *Watcher) Stop() {
 	w.mu.Lock()
 	if w.stopped {
@@ -335,26 +744,24 @@ func (w *Watcher) Stop() {
 		return
 	}
 	w.stopped = true
-	w.mu.Unlock()
-
-	close(w.stopChan)
-
-	// Stop polling ticker
-	w.mu.Lock()
-	if w.pollingTicker != nil {
-		w.pollingTicker.Stop()
-		w.pollingTicker = nil
+	w.cancel()
+	if w.stopParent != nil {
+		w.stopParent()
 	}
-	// Note: fsnotifyWatcher is closed by fsnotifyEventLoop goroutine
-	// We just nil out the reference here after waiting for goroutines
 	w.mu.Unlock()
 
-	// Wait for all goroutines to finish
+	// No goroutine can be added once stopped is set
 	w.wg.Wait()
 
-	// Now safe to nil out the fsnotify watcher
 	w.mu.Lock()
-	w.fsnotifyWatcher = nil
+	if w.fsnotifyWatcher != nil {
+		w.fsnotifyWatcher.Close()
+		w.fsnotifyWatcher = nil
+	}
+	if w.pollCancel != nil {
+		w.pollCancel()
+		w.pollCancel = nil
+	}
 	w.mu.Unlock()
 
 	// Close channels
@@ -362,6 +769,21 @@ func (w *Watcher) Stop() {
 	close(w.errorChan)
 }
 
+// Health returns the current mode and the last fsnotify error
+func (w *Watcher) Health() Health {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return Health{
+		Mode:        w.mode,
+		Running:     w.running(),
+		Files:       len(w.lastModTimes),
+		Fallbacks:   w.fallbacks,
+		LastError:   w.lastErr,
+		LastErrorAt: w.lastErrAt,
+		PollOnly:    w.pollOnly,
+	}
+}
+
 // GetMode returns the current watcher mode
 func (w *Watcher) GetMode() WatcherMode {
 	w.mu.RLock()
@@ -376,6 +798,15 @@ func (w *Watcher) SetPollingInterval(interval time.Duration) {
 	w.pollingInterval = interval
 }
 
+// SetDebounce coalesces events so consumers get at most one event per path
+// per window; zero (the default) delivers every event immediately
+// Events still pending when the watcher stops are dropped
+func (w *Watcher) SetDebounce(window time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.debounce = window
+}
+
 // SetRecoveryInterval sets the recovery interval (for testing)
 func (w *Watcher) SetRecoveryInterval(interval time.Duration) {
 	w.mu.Lock()
@@ -2,10 +2,13 @@ package watcher
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/fsnotify/fsnotify"
 )
 
 func TestNewWatcher(t *testing.T) {
@@ -181,3 +184,386 @@ func TestWatcher_MultipleFiles(t *testing.T) {
 
 	w.Stop()
 }
+
+// waitForEvent returns the next event for path, skipping others
+func waitForEvent(t *testing.T, w *Watcher, path string, timeout time.Duration) (Event, bool) {
+	t.Helper()
+	deadline := time.After(timeout)
+	for {
+		select {
+		case event := <-w.Events():
+			if event.Path == path {
+				return event, true
+			}
+		case <-deadline:
+			return Event{}, false
+		}
+	}
+}
+
+func TestWatcher_AddWatchGlob_CreatedAndDeleted(t *testing.T) {
+	tmpDir := t.TempDir()
+	existing := filepath.Join(tmpDir, "old.jsonl")
+	if err := os.WriteFile(existing, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewWatcher()
+	w.SetPollingInterval(20 * time.Millisecond)
+	if err := w.AddWatchGlob(filepath.Join(tmpDir, "*.jsonl")); err != nil {
+		t.Fatalf("AddWatchGlob() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := w.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() {
+		cancel()
+		w.Stop()
+	}()
+
+	// Non-matching files are ignored
+	if err := os.WriteFile(filepath.Join(tmpDir, "notes.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	created := filepath.Join(tmpDir, "new.jsonl")
+	if err := os.WriteFile(created, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	event, ok := waitForEvent(t, w, created, time.Second)
+	if !ok {
+		t.Fatal("did not receive event for new transcript")
+	}
+	if event.EventType != EventCreated {
+		t.Errorf("expected EventCreated, got %v", event.EventType)
+	}
+
+	if err := os.Remove(existing); err != nil {
+		t.Fatal(err)
+	}
+	event, ok = waitForEvent(t, w, existing, time.Second)
+	if !ok {
+		t.Fatal("did not receive event for removed transcript")
+	}
+	if event.EventType != EventDeleted {
+		t.Errorf("expected EventDeleted, got %v", event.EventType)
+	}
+}
+
+func TestWatcher_AddWatchDir_Recursive(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	w := NewWatcher()
+	w.SetPollingInterval(20 * time.Millisecond)
+	if err := w.AddWatchDir(tmpDir, true); err != nil {
+		t.Fatalf("AddWatchDir() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := w.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() {
+		cancel()
+		w.Stop()
+	}()
+
+	// A file in a directory created after the watch started
+	subDir := filepath.Join(tmpDir, "project")
+	if err := os.Mkdir(subDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	nested := filepath.Join(subDir, "session.jsonl")
+	if err := os.WriteFile(nested, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	event, ok := waitForEvent(t, w, nested, time.Second)
+	if !ok {
+		t.Fatal("did not receive event for file in new subdirectory")
+	}
+	if event.EventType != EventCreated {
+		t.Errorf("expected EventCreated, got %v", event.EventType)
+	}
+}
+
+func TestWatcher_PollingMode_CreatedAndDeleted(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "a.jsonl")
+
+	w := NewWatcher()
+	if err := w.AddWatchGlob(filepath.Join(tmpDir, "*.jsonl")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Drive the polling check directly
+	if err := os.WriteFile(testFile, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w.checkForChanges()
+	if event := <-w.Events(); event.Path != testFile || event.EventType != EventCreated {
+		t.Errorf("expected EventCreated for %s, got %+v", testFile, event)
+	}
+
+	if err := os.Remove(testFile); err != nil {
+		t.Fatal(err)
+	}
+	w.checkForChanges()
+	if event := <-w.Events(); event.Path != testFile || event.EventType != EventDeleted {
+		t.Errorf("expected EventDeleted for %s, got %+v", testFile, event)
+	}
+	w.Stop()
+}
+
+func TestWatcher_AddWatchGlob_Invalid(t *testing.T) {
+	w := NewWatcher()
+	defer w.Stop()
+
+	if err := w.AddWatchGlob(filepath.Join(t.TempDir(), "*", "*.jsonl")); err == nil {
+		t.Error("expected error for wildcard in directory")
+	}
+	if err := w.AddWatchGlob(filepath.Join(t.TempDir(), "[.jsonl")); err == nil {
+		t.Error("expected error for malformed pattern")
+	}
+}
+
+func TestDirWatch_Matches(t *testing.T) {
+	dir := filepath.Join("/tmp", "projects")
+	tests := []struct {
+		name string
+		dw   dirWatch
+		path string
+		want bool
+	}{
+		{"direct child", dirWatch{dir: dir}, filepath.Join(dir, "a.txt"), true},
+		{"nested not recursive", dirWatch{dir: dir}, filepath.Join(dir, "sub", "a.txt"), false},
+		{"nested recursive", dirWatch{dir: dir, recursive: true}, filepath.Join(dir, "sub", "a.txt"), true},
+		{"pattern match", dirWatch{dir: dir, pattern: "*.jsonl"}, filepath.Join(dir, "s.jsonl"), true},
+		{"pattern mismatch", dirWatch{dir: dir, pattern: "*.jsonl"}, filepath.Join(dir, "s.json"), false},
+		{"sibling prefix", dirWatch{dir: dir, recursive: true}, filepath.Join("/tmp", "projects2", "a.txt"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.dw.matches(tt.path); got != tt.want {
+				t.Errorf("matches(%s) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatcher_Coalesce(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []EventType
+		want   EventType
+		none   bool
+	}{
+		{"repeated writes", []EventType{EventModified, EventModified, EventModified}, EventModified, false},
+		{"created then written", []EventType{EventCreated, EventModified}, EventCreated, false},
+		{"created then deleted", []EventType{EventCreated, EventModified, EventDeleted}, 0, true},
+		{"replaced", []EventType{EventDeleted, EventCreated}, EventModified, false},
+		{"written then deleted", []EventType{EventModified, EventDeleted}, EventDeleted, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWatcher()
+			due := time.Now()
+			for _, eventType := range tt.events {
+				w.coalesce(Event{Path: "a", EventType: eventType}, due)
+			}
+			events, next := w.takeDue(due)
+			if !next.IsZero() {
+				t.Errorf("expected no remaining events, next due %v", next)
+			}
+			if tt.none {
+				if len(events) != 0 {
+					t.Errorf("expected no events, got %+v", events)
+				}
+				return
+			}
+			if len(events) != 1 || events[0].EventType != tt.want {
+				t.Errorf("expected one %v event, got %+v", tt.want, events)
+			}
+		})
+	}
+}
+
+func TestWatcher_Debounce(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "session.jsonl")
+	if err := os.WriteFile(testFile, []byte("{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewWatcher()
+	w.SetDebounce(200 * time.Millisecond)
+	w.AddWatch(testFile)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := w.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() {
+		cancel()
+		w.Stop()
+	}()
+
+	// A burst of appends within one window
+	for i := 0; i < 5; i++ {
+		w.send([]Event{{Path: testFile, EventType: EventModified}})
+	}
+
+	select {
+	case event := <-w.Events():
+		if event.Path != testFile || event.EventType != EventModified {
+			t.Errorf("unexpected event %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("did not receive debounced event")
+	}
+
+	select {
+	case event := <-w.Events():
+		t.Errorf("expected one event per window, got another: %+v", event)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
+func TestWatcher_FallbackToPolling(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.txt")
+	if err := os.WriteFile(testFile, []byte("test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewWatcher()
+	w.SetPollingInterval(20 * time.Millisecond)
+	w.AddWatch(testFile)
+	if err := w.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer w.Stop()
+
+	fsw := w.fsnotifyWatcherForTest()
+	w.fallbackToPolling(fsw, errors.New("inotify watch limit reached"))
+
+	health := w.Health()
+	if health.Mode != ModePolling {
+		t.Errorf("expected ModePolling, got %v", health.Mode)
+	}
+	if !health.Running {
+		t.Error("expected watcher to be running")
+	}
+	if health.Fallbacks != 1 {
+		t.Errorf("expected 1 fallback, got %d", health.Fallbacks)
+	}
+	if health.LastError == nil || health.LastErrorAt.IsZero() {
+		t.Errorf("expected last error to be recorded, got %+v", health)
+	}
+
+	// A stale watcher failing again does not count as another fallback
+	w.fallbackToPolling(fsw, errors.New("late error"))
+	if got := w.Health().Fallbacks; got != 1 {
+		t.Errorf("expected 1 fallback after stale error, got %d", got)
+	}
+
+	// Polling picks up the change
+	time.Sleep(50 * time.Millisecond)
+	if err := os.WriteFile(testFile, []byte("modified"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := waitForEvent(t, w, testFile, time.Second); !ok {
+		t.Error("did not receive event after falling back to polling")
+	}
+}
+
+func TestWatcher_PreferPolling(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.txt")
+	if err := os.WriteFile(testFile, []byte("test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	SetPreferPolling(true)
+	w := NewWatcher()
+	SetPreferPolling(false)
+	w.SetPollingInterval(20 * time.Millisecond)
+	w.AddWatch(testFile)
+	if err := w.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer w.Stop()
+
+	health := w.Health()
+	if health.Mode != ModePolling || !health.PollOnly || health.Fallbacks != 0 {
+		t.Errorf("Health() = %+v, want polling by choice without a fallback", health)
+	}
+	w.tryRecoverFsnotify()
+	if mode := w.GetMode(); mode != ModePolling {
+		t.Errorf("GetMode() = %v after recovery, want polling to stay", mode)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if err := os.WriteFile(testFile, []byte("modified"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := waitForEvent(t, w, testFile, time.Second); !ok {
+		t.Error("did not receive event while polling")
+	}
+
+	if NewWatcher().Health().PollOnly {
+		t.Error("watchers created after SetPreferPolling(false) should use fsnotify")
+	}
+}
+
+func TestWatcher_ContextCancelStopsGoroutines(t *testing.T) {
+	w := NewWatcher()
+	w.SetPollingInterval(10 * time.Millisecond)
+	w.AddWatchDir(t.TempDir(), false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := w.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	w.fallbackToPolling(w.fsnotifyWatcherForTest(), errors.New("forced"))
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.Stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop() did not return after the context was cancelled")
+	}
+
+	if w.Health().Running {
+		t.Error("expected watcher not to be running after Stop")
+	}
+	// Falling back after Stop must not start a new polling loop
+	w.fallbackToPolling(nil, errors.New("after stop"))
+}
+
+func TestWatcher_HealthBeforeStart(t *testing.T) {
+	w := NewWatcher()
+	defer w.Stop()
+
+	health := w.Health()
+	if health.Running {
+		t.Error("expected watcher not to be running before Start")
+	}
+	if health.Mode.String() != "fsnotify" || ModePolling.String() != "polling" {
+		t.Errorf("unexpected mode names %q, %q", health.Mode, ModePolling)
+	}
+}
+
+// fsnotifyWatcherForTest returns the active fsnotify watcher
+func (w *Watcher) fsnotifyWatcherForTest() *fsnotify.Watcher {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.fsnotifyWatcher
+}
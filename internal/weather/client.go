@@ -0,0 +1,116 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/filecache"
+	"github.com/ll931217/claude-hud-enhanced/internal/refresh"
+)
+
+// DefaultInterval is how long a report is reused; weather changes slowly
+const DefaultInterval = 30 * time.Minute
+
+// cachedReport is the outcome of the last fetch for one provider and location
+type cachedReport struct {
+	Report    Report    `json:"report"`
+	Found     bool      `json:"found"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"` // Last attempt, successful or not
+}
+
+// Client fetches and caches weather reports
+// Fetches run in the background, so a slow service never holds up a render,
+// and failed attempts are cached too, so an unreachable service is asked at
+// most once per interval. With a cache path reports are shared across processes
+type Client struct {
+	CachePath  string        // Optional file caching the last reports
+	Timeout    time.Duration // Bounds each fetch
+	HTTPClient *http.Client
+
+	mu      sync.Mutex
+	reports map[string]cachedReport // Keyed by provider and location
+	loaded  bool
+	fetches refresh.Group
+}
+
+// NewClient creates a weather client persisted at cachePath ("" keeps reports in memory)
+func NewClient(cachePath string) *Client {
+	return &Client{
+		CachePath:  cachePath,
+		Timeout:    10 * time.Second,
+		HTTPClient: &http.Client{},
+		reports:    make(map[string]cachedReport),
+	}
+}
+
+// Report returns the weather at location, fetching it in the background once
+// the cached report is older than interval. It waits for the fetch until ctx
+// is done; until then, and after a failure, the previous report, if any, is
+// returned, after a failure with the error
+func (c *Client) Report(ctx context.Context, provider Provider, location string, interval time.Duration) (Report, bool, error) {
+	key := provider.Name() + "\x00" + location
+	c.mu.Lock()
+	c.load()
+	last, ok := c.reports[key]
+	c.mu.Unlock()
+
+	now := time.Now()
+	if !ok || now.Sub(last.CheckedAt) >= interval || now.Before(last.CheckedAt) {
+		if c.fetches.Do(ctx, key, func() { c.fetch(provider, location, key) }) {
+			c.mu.Lock()
+			last = c.reports[key]
+			c.mu.Unlock()
+		}
+	}
+
+	if last.Error != "" {
+		return last.Report, last.Found, errors.New(last.Error)
+	}
+	return last.Report, last.Found, nil
+}
+
+// fetch asks provider for the weather at location and caches the outcome
+func (c *Client) fetch(provider Provider, location, key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+	report, err := provider.Fetch(ctx, c.HTTPClient, location)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	last := c.reports[key]
+	last.CheckedAt = time.Now()
+	last.Error = ""
+	if err != nil {
+		last.Error = err.Error()
+	} else {
+		last.Report, last.Found = report, true
+	}
+	c.reports[key] = last
+	c.save()
+}
+
+// Wait blocks until fetches still running finish
+func (c *Client) Wait() {
+	c.fetches.Wait()
+}
+
+// load reads the persisted reports once; a missing or corrupt file starts empty
+func (c *Client) load() {
+	if c.loaded {
+		return
+	}
+	c.loaded = true
+	var reports map[string]cachedReport
+	if filecache.Load(c.CachePath, &reports) {
+		maps.Copy(c.reports, reports)
+	}
+}
+
+func (c *Client) save() {
+	_ = filecache.Save(c.CachePath, c.reports)
+}
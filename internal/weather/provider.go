@@ -0,0 +1,209 @@
+// Package weather fetches current conditions from wttr.in or OpenWeather for
+// the weather section, caching them so the statusline rarely waits on the network
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Conditions, coarse enough to pick an icon
+const (
+	ConditionUnknown      = ""
+	ConditionClear        = "clear"
+	ConditionPartlyCloudy = "partly cloudy"
+	ConditionCloudy       = "cloudy"
+	ConditionFog          = "fog"
+	ConditionRain         = "rain"
+	ConditionSnow         = "snow"
+	ConditionThunder      = "thunder"
+)
+
+// Report is the current weather at a location
+type Report struct {
+	Location    string  `json:"location,omitempty"`
+	TempC       float64 `json:"temp_c"`
+	Condition   string  `json:"condition"`             // One of the Condition constants
+	Description string  `json:"description,omitempty"` // Provider's wording, e.g. "light drizzle"
+}
+
+// Provider fetches the current weather
+type Provider interface {
+	// Name identifies the provider in cache keys and errors
+	Name() string
+	// Fetch returns the weather at location; "" means the provider's guess
+	Fetch(ctx context.Context, client *http.Client, location string) (Report, error)
+}
+
+// Wttr is the keyless wttr.in service
+type Wttr struct {
+	URL string // Defaults to https://wttr.in
+}
+
+// Name returns "wttr"
+func (w Wttr) Name() string { return "wttr" }
+
+// Fetch queries wttr.in's JSON format; an empty location uses the caller's IP
+func (w Wttr) Fetch(ctx context.Context, client *http.Client, location string) (Report, error) {
+	base := w.URL
+	if base == "" {
+		base = "https://wttr.in"
+	}
+	var body struct {
+		CurrentCondition []struct {
+			TempC       string `json:"temp_C"`
+			WeatherCode string `json:"weatherCode"`
+			WeatherDesc []struct {
+				Value string `json:"value"`
+			} `json:"weatherDesc"`
+		} `json:"current_condition"`
+		NearestArea []struct {
+			AreaName []struct {
+				Value string `json:"value"`
+			} `json:"areaName"`
+		} `json:"nearest_area"`
+	}
+	if err := getJSON(ctx, client, base+"/"+url.PathEscape(location)+"?format=j1", &body); err != nil {
+		return Report{}, err
+	}
+	if len(body.CurrentCondition) == 0 {
+		return Report{}, errors.New("wttr.in returned no current conditions")
+	}
+
+	current := body.CurrentCondition[0]
+	temp, err := strconv.ParseFloat(current.TempC, 64)
+	if err != nil {
+		return Report{}, fmt.Errorf("wttr.in returned an invalid temperature %q", current.TempC)
+	}
+	code, _ := strconv.Atoi(current.WeatherCode)
+	report := Report{TempC: temp, Condition: wttrCondition(code)}
+	if len(current.WeatherDesc) > 0 {
+		report.Description = current.WeatherDesc[0].Value
+	}
+	if len(body.NearestArea) > 0 && len(body.NearestArea[0].AreaName) > 0 {
+		report.Location = body.NearestArea[0].AreaName[0].Value
+	}
+	return report, nil
+}
+
+// wttrCondition maps a WWO weather code, as used by wttr.in, to a condition
+func wttrCondition(code int) string {
+	switch code {
+	case 113:
+		return ConditionClear
+	case 116:
+		return ConditionPartlyCloudy
+	case 119, 122:
+		return ConditionCloudy
+	case 143, 248, 260:
+		return ConditionFog
+	case 200, 386, 389, 392, 395:
+		return ConditionThunder
+	case 179, 182, 185, 227, 230, 317, 320, 323, 326, 329, 332, 335, 338, 350, 362, 365, 368, 371, 374, 377:
+		return ConditionSnow
+	case 0:
+		return ConditionUnknown
+	}
+	return ConditionRain
+}
+
+// OpenWeather is the OpenWeather current weather API, which needs an API key
+type OpenWeather struct {
+	APIKey string
+	URL    string // Defaults to https://api.openweathermap.org/data/2.5/weather
+}
+
+// Name returns "openweather"
+func (o OpenWeather) Name() string { return "openweather" }
+
+// Fetch queries the current weather for a city name, e.g. "London,GB"
+func (o OpenWeather) Fetch(ctx context.Context, client *http.Client, location string) (Report, error) {
+	if o.APIKey == "" {
+		return Report{}, errors.New("openweather needs an API key")
+	}
+	if location == "" {
+		return Report{}, errors.New("openweather needs a location")
+	}
+	base := o.URL
+	if base == "" {
+		base = "https://api.openweathermap.org/data/2.5/weather"
+	}
+	query := url.Values{"q": {location}, "appid": {o.APIKey}, "units": {"metric"}}
+
+	var body struct {
+		Name string `json:"name"`
+		Main struct {
+			Temp *float64 `json:"temp"`
+		} `json:"main"`
+		Weather []struct {
+			ID          int    `json:"id"`
+			Description string `json:"description"`
+		} `json:"weather"`
+	}
+	if err := getJSON(ctx, client, base+"?"+query.Encode(), &body); err != nil {
+		return Report{}, err
+	}
+	if body.Main.Temp == nil {
+		return Report{}, errors.New("openweather returned no temperature")
+	}
+
+	report := Report{Location: body.Name, TempC: *body.Main.Temp}
+	if len(body.Weather) > 0 {
+		report.Condition = openWeatherCondition(body.Weather[0].ID)
+		report.Description = body.Weather[0].Description
+	}
+	return report, nil
+}
+
+// openWeatherCondition maps an OpenWeather condition ID to a condition
+func openWeatherCondition(id int) string {
+	switch {
+	case id >= 200 && id < 300:
+		return ConditionThunder
+	case id >= 300 && id < 600:
+		return ConditionRain
+	case id >= 600 && id < 700:
+		return ConditionSnow
+	case id >= 700 && id < 800:
+		return ConditionFog
+	case id == 800:
+		return ConditionClear
+	case id == 801 || id == 802:
+		return ConditionPartlyCloudy
+	case id > 802 && id < 900:
+		return ConditionCloudy
+	}
+	return ConditionUnknown
+}
+
+// getJSON decodes a JSON response from rawURL
+func getJSON(ctx context.Context, client *http.Client, rawURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		// Errors carry the URL, which may hold the API key
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("weather service returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse weather response: %w", err)
+	}
+	return nil
+}
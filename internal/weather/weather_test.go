@@ -0,0 +1,127 @@
+package weather
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const wttrJSON = `{
+  "current_condition": [{"temp_C": "12", "weatherCode": "266", "weatherDesc": [{"value": "Light drizzle"}]}],
+  "nearest_area": [{"areaName": [{"value": "London"}]}]
+}`
+
+const openWeatherJSON = `{
+  "name": "Berlin",
+  "main": {"temp": -2.6},
+  "weather": [{"id": 601, "main": "Snow", "description": "snow"}]
+}`
+
+func TestProviders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/London" && r.URL.Query().Get("format") == "j1":
+			w.Write([]byte(wttrJSON))
+		case r.URL.Path == "/owm" && r.URL.Query().Get("appid") == "key-1" && r.URL.Query().Get("q") == "Berlin,DE":
+			w.Write([]byte(openWeatherJSON))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	report, err := Wttr{URL: srv.URL}.Fetch(ctx, srv.Client(), "London")
+	want := Report{Location: "London", TempC: 12, Condition: ConditionRain, Description: "Light drizzle"}
+	if err != nil || report != want {
+		t.Errorf("Wttr.Fetch() = %+v, %v; want %+v", report, err, want)
+	}
+
+	owm := OpenWeather{APIKey: "key-1", URL: srv.URL + "/owm"}
+	report, err = owm.Fetch(ctx, srv.Client(), "Berlin,DE")
+	want = Report{Location: "Berlin", TempC: -2.6, Condition: ConditionSnow, Description: "snow"}
+	if err != nil || report != want {
+		t.Errorf("OpenWeather.Fetch() = %+v, %v; want %+v", report, err, want)
+	}
+
+	// A rejected key is reported without leaking it
+	owm.APIKey = "secret-key"
+	if _, err := owm.Fetch(ctx, srv.Client(), "Berlin,DE"); err == nil || strings.Contains(err.Error(), "secret-key") {
+		t.Errorf("OpenWeather.Fetch() error = %v, want an error without the key", err)
+	}
+	if _, err := (OpenWeather{}).Fetch(ctx, srv.Client(), "Berlin"); err == nil {
+		t.Error("expected an error without an API key")
+	}
+}
+
+func TestClient_Report(t *testing.T) {
+	var requests atomic.Int32
+	var fail atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if fail.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(wttrJSON))
+	}))
+	defer srv.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "weather.json")
+	provider := Wttr{URL: srv.URL}
+	ctx := context.Background()
+
+	report, found, err := NewClient(cachePath).Report(ctx, provider, "London", time.Hour)
+	if err != nil || !found || report.TempC != 12 {
+		t.Fatalf("Report() = %+v, %v, %v", report, found, err)
+	}
+
+	// Another process reuses the cached report
+	if _, _, err := NewClient(cachePath).Report(ctx, provider, "London", time.Hour); err != nil || requests.Load() != 1 {
+		t.Errorf("expected the cached report, got %d requests (%v)", requests.Load(), err)
+	}
+
+	// A failed refresh keeps the previous report and is itself cached
+	fail.Store(true)
+	client := NewClient(cachePath)
+	report, found, err = client.Report(ctx, provider, "London", 0)
+	if err == nil || !found || report.TempC != 12 {
+		t.Errorf("Report() after failure = %+v, %v, %v; want stale report with error", report, found, err)
+	}
+	if _, _, err := client.Report(ctx, provider, "London", time.Hour); err == nil || requests.Load() != 2 {
+		t.Errorf("expected the cached failure, got %d requests (%v)", requests.Load(), err)
+	}
+
+	// Locations are cached separately
+	if _, found, _ := client.Report(ctx, provider, "Paris", time.Hour); found {
+		t.Error("expected no report for an uncached location while the service fails")
+	}
+}
+
+func TestClient_ReportInBackground(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(wttrJSON))
+	}))
+	defer srv.Close()
+
+	client := NewClient(filepath.Join(t.TempDir(), "weather.json"))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, found, err := client.Report(ctx, Wttr{URL: srv.URL}, "London", time.Hour); found || err != nil {
+		t.Errorf("Report() while the service is slow = %v, %v; want nothing yet", found, err)
+	}
+
+	// The fetch finishes in the background and is there for the next render
+	close(release)
+	client.Wait()
+	if report, found, err := client.Report(ctx, Wttr{URL: srv.URL}, "London", time.Hour); !found || err != nil || report.TempC != 12 {
+		t.Errorf("Report() after the fetch = %+v, %v, %v", report, found, err)
+	}
+}
@@ -0,0 +1,176 @@
+// Package webhook posts session digests to Slack and Discord incoming
+// webhooks, so a team can see what its sessions did, overnight ones included
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/digest"
+)
+
+// Supported webhook kinds
+const (
+	KindSlack   = "slack"
+	KindDiscord = "discord"
+)
+
+// requestTimeout bounds a single post
+const requestTimeout = 5 * time.Second
+
+// maxLength is the longest message each kind accepts, in characters
+var maxLength = map[string]int{
+	KindSlack:   40000,
+	KindDiscord: 2000,
+}
+
+// defaultTemplates are the messages posted without a configured template.
+// Slack and Discord mark up bold and code differently
+var defaultTemplates = map[string]string{
+	KindSlack: "*{{.Title}}* finished{{if .Dir}} in `{{.Dir}}`{{end}}: " +
+		"{{duration .Duration}} · {{cost .Cost}} · {{count .InputTokens}} in / {{count .OutputTokens}} out\n" +
+		"{{if .Files}}Files ({{len .Files}}): {{list .RelativeFiles}}\n{{end}}" +
+		"{{if .TodosTotal}}Todos {{len .TodosDone}}/{{.TodosTotal}}{{if .TodosDone}}: {{list .TodosDone}}{{end}}\n{{end}}",
+	KindDiscord: "**{{.Title}}** finished{{if .Dir}} in `{{.Dir}}`{{end}}: " +
+		"{{duration .Duration}} · {{cost .Cost}} · {{count .InputTokens}} in / {{count .OutputTokens}} out\n" +
+		"{{if .Files}}Files ({{len .Files}}): {{list .RelativeFiles}}\n{{end}}" +
+		"{{if .TodosTotal}}Todos {{len .TodosDone}}/{{.TodosTotal}}{{if .TodosDone}}: {{list .TodosDone}}{{end}}\n{{end}}",
+}
+
+// Webhook is a configured incoming webhook
+type Webhook struct {
+	name    string
+	kind    string
+	url     string
+	perHour int
+	tmpl    *template.Template
+	client  *http.Client
+}
+
+// New creates a webhook from configuration. It fails when the webhook has
+// no URL, its kind cannot be told or its template does not parse
+func New(cfg config.WebhookConfig) (*Webhook, error) {
+	endpoint := cfg.URL
+	if cfg.URLEnv != "" {
+		endpoint = os.Getenv(cfg.URLEnv)
+	}
+	if endpoint == "" {
+		return nil, fmt.Errorf("webhook %q has no url", cfg.Name)
+	}
+
+	kind := cfg.Kind
+	if kind == "" {
+		if kind = guessKind(endpoint); kind == "" {
+			return nil, fmt.Errorf("webhook %q: cannot tell slack from discord by its url; set kind", cfg.Name)
+		}
+	}
+	if _, ok := maxLength[kind]; !ok {
+		return nil, fmt.Errorf("webhook %q: unknown kind %q (want slack or discord)", cfg.Name, kind)
+	}
+
+	name := cfg.Name
+	if name == "" {
+		name = kind
+	}
+
+	text := cfg.Template
+	if text == "" {
+		text = defaultTemplates[kind]
+	}
+	tmpl, err := template.New(name).Funcs(digest.Funcs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("webhook %q: %w", name, err)
+	}
+
+	return &Webhook{
+		name:    name,
+		kind:    kind,
+		url:     endpoint,
+		perHour: max(cfg.MaxPerHour, 1),
+		tmpl:    tmpl,
+		client:  &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+// Name returns the webhook's name
+func (w *Webhook) Name() string {
+	return w.name
+}
+
+// PerHour returns how many posts the webhook allows per hour
+func (w *Webhook) PerHour() int {
+	return w.perHour
+}
+
+// Message renders the message posted for d, cut to the length the webhook
+// accepts
+func (w *Webhook) Message(d digest.Digest) (string, error) {
+	var b strings.Builder
+	if err := w.tmpl.Execute(&b, d); err != nil {
+		return "", fmt.Errorf("webhook %q: %w", w.name, err)
+	}
+	message := strings.TrimSpace(b.String())
+	if runes := []rune(message); len(runes) > maxLength[w.kind] {
+		message = string(runes[:maxLength[w.kind]-1]) + "…"
+	}
+	return message, nil
+}
+
+// Post posts d's message to the webhook
+func (w *Webhook) Post(ctx context.Context, d digest.Digest) error {
+	message, err := w.Message(d)
+	if err != nil {
+		return err
+	}
+
+	// Slack reads the message from text, Discord from content
+	field := "text"
+	if w.kind == KindDiscord {
+		field = "content"
+	}
+	body, err := json.Marshal(map[string]string{field: message})
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		// The URL is the webhook's secret; keep it out of the error
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("webhook %q: %w", w.name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %q returned %s", w.name, resp.Status)
+	}
+	return nil
+}
+
+// guessKind tells a webhook's kind from its URL, or returns "" when it cannot
+func guessKind(endpoint string) string {
+	switch {
+	case strings.Contains(endpoint, "hooks.slack.com/"):
+		return KindSlack
+	case strings.Contains(endpoint, "discord.com/api/webhooks/"), strings.Contains(endpoint, "discordapp.com/api/webhooks/"):
+		return KindDiscord
+	}
+	return ""
+}
@@ -0,0 +1,141 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ll931217/claude-hud-enhanced/internal/config"
+	"github.com/ll931217/claude-hud-enhanced/internal/digest"
+)
+
+var testDigest = digest.Digest{
+	Name:         "auth-refactor",
+	Dir:          "/p",
+	Start:        time.Date(2026, 3, 1, 1, 0, 0, 0, time.UTC),
+	End:          time.Date(2026, 3, 1, 2, 12, 0, 0, time.UTC),
+	Cost:         2.41,
+	InputTokens:  1_200_000,
+	OutputTokens: 48_000,
+	Files:        []string{"/p/auth/token.go"},
+	TodosDone:    []string{"Add refresh tokens"},
+	TodosTotal:   2,
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.WebhookConfig
+		wantKind string
+		wantErr  bool
+	}{
+		{name: "slack url", cfg: config.WebhookConfig{URL: "https://hooks.slack.com/services/T/B/x"}, wantKind: KindSlack},
+		{name: "discord url", cfg: config.WebhookConfig{URL: "https://discord.com/api/webhooks/1/x"}, wantKind: KindDiscord},
+		{name: "explicit kind", cfg: config.WebhookConfig{URL: "https://proxy.example.com/hook", Kind: KindDiscord}, wantKind: KindDiscord},
+		{name: "unknown url", cfg: config.WebhookConfig{URL: "https://proxy.example.com/hook"}, wantErr: true},
+		{name: "unknown kind", cfg: config.WebhookConfig{URL: "https://hooks.slack.com/x", Kind: "teams"}, wantErr: true},
+		{name: "no url", cfg: config.WebhookConfig{Kind: KindSlack}, wantErr: true},
+		{name: "bad template", cfg: config.WebhookConfig{URL: "https://hooks.slack.com/x", Template: "{{.Title"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := New(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (w.kind != tt.wantKind || w.Name() != tt.wantKind) {
+				t.Errorf("New() = %s named %s, want %s", w.kind, w.Name(), tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestNew_URLEnv(t *testing.T) {
+	t.Setenv("TEST_WEBHOOK_URL", "https://hooks.slack.com/services/T/B/x")
+	w, err := New(config.WebhookConfig{Name: "team", URLEnv: "TEST_WEBHOOK_URL"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if w.url != "https://hooks.slack.com/services/T/B/x" || w.Name() != "team" {
+		t.Errorf("New() = %s at %s", w.Name(), w.url)
+	}
+}
+
+func TestMessage(t *testing.T) {
+	w, err := New(config.WebhookConfig{URL: "https://hooks.slack.com/x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "*auth-refactor* finished in `/p`: 1h12m · $2.41 · 1.2M in / 48k out\n" +
+		"Files (1): auth/token.go\n" +
+		"Todos 1/2: Add refresh tokens"
+	if got, _ := w.Message(testDigest); got != want {
+		t.Errorf("Message() =\n%s\nwant\n%s", got, want)
+	}
+
+	w, err = New(config.WebhookConfig{URL: "https://discord.com/api/webhooks/1/x", Template: "{{.Title}}: {{cost .Cost}} {{.Files}}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := testDigest
+	d.Files = []string{strings.Repeat("x", 3000)}
+	got, err := w.Message(d)
+	if err != nil {
+		t.Fatalf("Message() error = %v", err)
+	}
+	if !strings.HasPrefix(got, "auth-refactor: $2.41 [xxx") || len([]rune(got)) != 2000 || !strings.HasSuffix(got, "…") {
+		t.Errorf("Message() = %d runes starting %q, want it cut to 2000", len([]rune(got)), got[:30])
+	}
+}
+
+func TestPost(t *testing.T) {
+	for _, kind := range []string{KindSlack, KindDiscord} {
+		t.Run(kind, func(t *testing.T) {
+			var body map[string]string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("decode: %v", err)
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			w, err := New(config.WebhookConfig{URL: server.URL, Kind: kind})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Post(context.Background(), testDigest); err != nil {
+				t.Fatalf("Post() error = %v", err)
+			}
+
+			field := map[string]string{KindSlack: "text", KindDiscord: "content"}[kind]
+			if !strings.Contains(body[field], "auth-refactor") {
+				t.Errorf("posted %v, want the message in %q", body, field)
+			}
+		})
+	}
+}
+
+func TestPost_Failure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	w, err := New(config.WebhookConfig{Name: "team", URL: server.URL + "/secret", Kind: KindSlack})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Post(context.Background(), testDigest); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Post() error = %v, want the status", err)
+	}
+
+	server.Close()
+	if err := w.Post(context.Background(), testDigest); err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("Post() error = %v, want one without the URL", err)
+	}
+}
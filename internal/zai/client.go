@@ -2,6 +2,7 @@ package zai
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -15,10 +16,16 @@ const (
 	cacheTTL = 60 * time.Second
 )
 
+// ErrNoAPIKey is reported when neither GLM_API_KEY nor ZAI_API_KEY is set
+var ErrNoAPIKey = errors.New("no API key (set GLM_API_KEY or ZAI_API_KEY)")
+
 // Client handles fetching usage data from the Z.ai API
 type Client struct {
 	httpClient *http.Client
 	cache      *UsageCache
+
+	errMu   sync.RWMutex
+	lastErr error // Why the last Fetch returned nil, for health reporting
 }
 
 // UsageCache provides thread-safe caching of usage data
@@ -85,11 +92,13 @@ func (c *Client) Fetch() *UsageInfo {
 	// Get API key
 	apiKey := getAPIKey()
 	if apiKey == "" {
+		c.setErr(ErrNoAPIKey)
 		return nil
 	}
 
 	// Fetch from API
 	data, err := c.fetchFromAPI(apiKey)
+	c.setErr(err)
 	if err != nil {
 		return nil
 	}
@@ -99,6 +108,19 @@ func (c *Client) Fetch() *UsageInfo {
 	return data
 }
 
+// Err returns why the last Fetch returned nil, or nil if it succeeded
+func (c *Client) Err() error {
+	c.errMu.RLock()
+	defer c.errMu.RUnlock()
+	return c.lastErr
+}
+
+func (c *Client) setErr(err error) {
+	c.errMu.Lock()
+	c.lastErr = err
+	c.errMu.Unlock()
+}
+
 // fetchFromAPI makes the actual HTTP request to the Z.ai API
 func (c *Client) fetchFromAPI(apiKey string) (*UsageInfo, error) {
 	req, err := http.NewRequest("GET", apiURL, nil)